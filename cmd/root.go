@@ -6,29 +6,35 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/denysvitali/gh-actions-mcp/config"
 	"github.com/denysvitali/gh-actions-mcp/github"
 	appmcp "github.com/denysvitali/gh-actions-mcp/mcp"
 
 	mcptypes "github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var (
-	version   = "dev"
 	cfgFile   string
 	repoOwner string
 	repoName  string
 	token     string
 	logLevel  string
+	remote    string
+
+	validateOnStart bool
+	containerFlag   bool
 )
 
+// containerModeEnv enables container mode like --container, for images that set it in
+// their environment.
+var containerModeEnv = []string{"GITHUB_CONTAINER_MODE", "GH_CONTAINER_MODE"}
+
 // Logs command flags
 var (
 	logsSearch    string
@@ -42,12 +48,20 @@ var (
 	logsJobID     int64
 	logsOwner     string
 	logsRepo      string
+	logsCancel    bool
+	logsRerun     bool
+	logsRawZip    string
 )
 
 var toolArgsJSON string
 
+var inferJSON bool
+
 var log = logrus.New()
 
+// startupValidationTimeout bounds the API call made by --validate-on-start.
+const startupValidationTimeout = 30 * time.Second
+
 func init() {
 	log.SetFormatter(&logrus.TextFormatter{
 		DisableTimestamp: false,
@@ -59,6 +73,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&repoName, "repo-name", "r", "", "repository name")
 	rootCmd.PersistentFlags().StringVarP(&token, "token", "t", "", "GitHub token (or use GITHUB_TOKEN env var, or macOS keychain)")
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "info", "log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().BoolVar(&containerFlag, "container", false, "container mode: read configuration from the environment only and never run git (or set GH_CONTAINER_MODE=true)")
+	rootCmd.PersistentFlags().StringVar(&remote, "remote", "", "git remote to infer the repository from (default: upstream, then origin)")
+
+	rootCmd.Flags().BoolVar(&validateOnStart, "validate-on-start", false, "check the token and repository with one API call before serving, and exit if they are unusable")
 
 	// Infer repo from git origin
 	rootCmd.AddCommand(inferCmd)
@@ -84,11 +102,13 @@ Token sources (in order of precedence):
 1. --token flag
 2. GITHUB_TOKEN environment variable
 3. Config file token field
-4. macOS Keychain (if authenticated via 'gh auth login')
+4. token_command in the config file
+5. macOS Keychain (if authenticated via 'gh auth login')
+6. gh's hosts.yml
 
 Other configuration:
 - Config file (--config or default locations)
-- Command line flags (--repo-owner, --repo-name)
+- Command line flags (--repo-owner, --repo-name, --remote)
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := configureLogLevel(); err != nil {
@@ -101,57 +121,127 @@ Other configuration:
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		if validateOnStart {
+			cfg.ValidateOnStart = true
+		}
+
 		// Create MCP server
 		mcpServer := appmcp.NewMCPServer(cfg, log)
 
+		if cfg.ValidateOnStart {
+			ctx, cancel := context.WithTimeout(context.Background(), startupValidationTimeout)
+			err := mcpServer.ValidateAccess(ctx)
+			cancel()
+			if err != nil {
+				return err
+			}
+			log.Infof("Startup validation passed")
+		}
+
 		// Run stdio transport using the library's built-in handler
-		return server.ServeStdio(mcpServer.GetServer())
+		return mcpServer.ServeStdio()
 	},
 }
 
 var inferCmd = &cobra.Command{
 	Use:   "infer-repo",
-	Short: "Infer repository from git remote origin",
-	Long:  "Get the repository owner and name from the git remote origin URL",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get git remote URL
-		cmdExec := exec.Command("git", "remote", "get-url", "origin")
-		output, err := cmdExec.Output()
-		if err != nil {
-			return fmt.Errorf("failed to get git remote: %v (are you in a git repo with an 'origin' remote?)", err)
-		}
+	Short: "Infer repository from git remotes",
+	Long: `Get the repository owner and name from the git remotes of the current checkout,
+along with the remote used, its URL, the current branch, and the default branch.
 
-		remoteURL := strings.TrimRight(string(output), "\n\r")
+The remote is chosen with --remote, or else the first of upstream and origin that exists.
+Use --json for machine-readable output.`,
+	RunE: runInferRepo,
+}
 
-		owner, repo, err := github.InferRepoFromOrigin(remoteURL)
-		if err != nil {
-			return fmt.Errorf("failed to parse repo from URL: %w", err)
-		}
+func init() {
+	inferCmd.Flags().BoolVar(&inferJSON, "json", false, "Print the result as JSON")
+}
 
-		fmt.Printf("Owner: %s\n", owner)
-		fmt.Printf("Repo:  %s\n", repo)
-		fmt.Printf("\nYou can use these with:\n")
-		fmt.Printf("  --repo-owner %s --repo-name %s\n", owner, repo)
-		fmt.Printf("Or set in config:\n")
-		fmt.Printf("  repo_owner: %s\n", owner)
-		fmt.Printf("  repo_name: %s\n", repo)
+func runInferRepo(cmd *cobra.Command, args []string) error {
+	if err := configureLogLevel(); err != nil {
+		return err
+	}
+	config.SetLogger(log)
+	github.SetDetectorLogger(log)
 
-		return nil
-	},
-}
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if remote != "" {
+		cfg.Remote = remote
+	}
 
-func loadConfig() (*config.Config, error) {
-	return loadConfigWithOptions(true)
+	info, err := github.NewRepoDetectorWithOptions(github.RepoDetectorOptions{
+		Remote:       cfg.Remote,
+		Preference:   cfg.RemotePreference,
+		SearchUpward: true,
+		HostPolicy:   github.HostPolicy{Hosts: []string{cfg.GitHubHost()}},
+	}).Detect()
+	if err != nil {
+		return fmt.Errorf("failed to infer repository: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if inferJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+
+	fmt.Fprintf(out, "Owner:  %s\n", info.Owner)
+	fmt.Fprintf(out, "Repo:   %s\n", info.Repo)
+	fmt.Fprintf(out, "Remote: %s (%s)\n", info.Remote, info.RawURL)
+	if info.Branch != "" {
+		fmt.Fprintf(out, "Branch: %s\n", info.Branch)
+	}
+	if info.DefaultBranch != "" {
+		fmt.Fprintf(out, "Default branch: %s\n", info.DefaultBranch)
+	}
+	if info.Fork != "" {
+		fmt.Fprintf(out, "Fork:   %s is a fork of %s\n", info.Fork, info.Upstream)
+	}
+	fmt.Fprintf(out, "\nYou can use these with:\n")
+	fmt.Fprintf(out, "  --repo-owner %s --repo-name %s\n", info.Owner, info.Repo)
+	fmt.Fprintf(out, "Or set in config:\n")
+	fmt.Fprintf(out, "  repo_owner: %s\n", info.Owner)
+	fmt.Fprintf(out, "  repo_name: %s\n", info.Repo)
+
+	return nil
 }
 
-func loadConfigAllowMissingRepo() (*config.Config, error) {
-	return loadConfigWithOptions(false)
+// containerMode reports whether --container or GH_CONTAINER_MODE is set. In container
+// mode configuration comes from the environment and flags only, git is never run, and
+// the effective configuration is logged at startup.
+func containerMode() bool {
+	if containerFlag {
+		return true
+	}
+	for _, env := range containerModeEnv {
+		if on, err := strconv.ParseBool(os.Getenv(env)); err == nil && on {
+			return true
+		}
+	}
+	return false
 }
 
-func loadConfigWithOptions(requireRepo bool) (*config.Config, error) {
+// loadConfig loads the configuration and applies the CLI flags. A default repository is
+// optional, since every tool accepts owner/repo arguments, unless require_repo is set.
+func loadConfig() (*config.Config, error) {
 	config.SetLogger(log)
 
-	cfg, err := config.Load(cfgFile)
+	var cfg *config.Config
+	var err error
+	if containerMode() {
+		github.DisableGitCommands()
+		if cfgFile != "" {
+			return nil, fmt.Errorf("--config cannot be used in container mode; configure with environment variables")
+		}
+		cfg, err = config.LoadFromEnv()
+	} else {
+		cfg, err = config.Load(cfgFile)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -164,35 +254,37 @@ func loadConfigWithOptions(requireRepo bool) (*config.Config, error) {
 		cfg.RepoName = repoName
 	}
 	if token != "" {
-		cfg.Token = token
+		cfg.SetToken(token, config.TokenSourceFlag)
 	}
 	if logLevel != "" {
 		cfg.LogLevel = logLevel
 	}
+	if remote != "" {
+		cfg.Remote = remote
+	}
 
-	// Try to infer repo from git if not set
-	if cfg.RepoOwner == "" || cfg.RepoName == "" {
-		if inferErr := inferRepoFromGit(cfg); inferErr != nil {
+	// Try to infer repo from git if not set. An explicit --remote picks that
+	// remote's repository over the configured one unless the repo flags are given.
+	overrideRepo := remote != "" && repoOwner == "" && repoName == ""
+	if (cfg.RepoOwner == "" || cfg.RepoName == "" || overrideRepo) && !containerMode() {
+		if inferErr := inferRepoFromGit(cfg, overrideRepo); inferErr != nil {
 			log.Warnf("Could not infer repo from git: %v", inferErr)
 		}
 	}
 
-	if err := cfg.ValidateToken(); err != nil {
+	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
-	if requireRepo {
-		if cfg.RepoOwner == "" {
-			return nil, fmt.Errorf("repository owner is required. Set GH_REPO_OWNER env var, 'repo_owner' in config, or use --repo-owner flag")
-		}
-		if cfg.RepoName == "" {
-			return nil, fmt.Errorf("repository name is required. Set GH_REPO_NAME env var, 'repo_name' in config, or use --repo-name flag")
-		}
+	cfg.Version = currentVersion().Version
+
+	if containerMode() {
+		log.WithFields(logrus.Fields(cfg.EffectiveSettings())).Infof("Effective configuration (container mode, version %s)", currentVersion().Version)
 	}
 
 	if cfg.RepoOwner != "" && cfg.RepoName != "" {
 		log.Infof("Configured for repository: %s/%s", cfg.RepoOwner, cfg.RepoName)
 	} else {
-		log.Infof("Configured without a default repository")
+		log.Infof("Configured without a default repository; tools need owner and repo arguments")
 	}
 	return cfg, nil
 }
@@ -206,21 +298,29 @@ func configureLogLevel() error {
 	return nil
 }
 
-func inferRepoFromGit(cfg *config.Config) error {
-	detector := github.NewRepoDetector()
+func inferRepoFromGit(cfg *config.Config, override bool) error {
+	detector := github.NewRepoDetectorWithOptions(github.RepoDetectorOptions{
+		Remote:       cfg.Remote,
+		Preference:   cfg.RemotePreference,
+		SearchUpward: true,
+		HostPolicy:   github.HostPolicy{Hosts: []string{cfg.GitHubHost()}},
+	})
 	info, err := detector.Detect()
 	if err != nil {
 		return err
 	}
 
-	if cfg.RepoOwner == "" {
+	if cfg.RepoOwner == "" || override {
 		cfg.RepoOwner = info.Owner
 	}
-	if cfg.RepoName == "" {
+	if cfg.RepoName == "" || override {
 		cfg.RepoName = info.Repo
 	}
 
 	log.Infof("Inferred repository from %s: %s/%s", info.Source, info.Owner, info.Repo)
+	if info.Fork != "" {
+		log.Infof("%s is a fork of %s; use --remote to choose which one to operate on", info.Fork, info.Upstream)
+	}
 	return nil
 }
 
@@ -255,11 +355,25 @@ Examples:
   # Use regex filter
   gh-actions-mcp logs 21662021288 --regex "OTA.*started"
 
+  # Inspect a failing job, then re-run it
+  gh-actions-mcp logs 21662021288 --job-id 62449039965 --tail 50 --rerun
+
+  # Inspect a stuck run, then cancel it
+  gh-actions-mcp logs 21662021288 --tail 20 --cancel
+
+  # Save the original logs archive for other tools ("-" writes it to stdout)
+  gh-actions-mcp logs 21662021288 --raw-zip logs.zip
+
 TIPS:
   - If you get a 404 error, the run ID might not exist. List runs using the MCP tool:
     list_workflow_runs or list_repository_workflow_runs
   - When using a URL, owner/repo are extracted from the URL automatically
   - Use --job-id to get logs for a specific job within a run
+  - --rerun re-runs the job (or the failed jobs of the run when no job is given);
+    --cancel cancels the run. Either acts after the logs are printed, even if
+    fetching them failed
+  - --raw-zip saves the run's logs archive exactly as GitHub serves it; it
+    cannot be combined with filters or --job-id (job logs are plain text)
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: runLogs,
@@ -277,6 +391,10 @@ func init() {
 	logsCmd.Flags().Int64VarP(&logsJobID, "job-id", "j", 0, "Specific job ID (when using run ID)")
 	logsCmd.Flags().StringVar(&logsOwner, "owner", "", "Override repo owner")
 	logsCmd.Flags().StringVar(&logsRepo, "repo", "", "Override repo name")
+	logsCmd.Flags().BoolVar(&logsCancel, "cancel", false, "Cancel the run after printing its logs")
+	logsCmd.Flags().BoolVar(&logsRerun, "rerun", false, "Re-run the job (or the run's failed jobs) after printing its logs")
+	logsCmd.Flags().StringVar(&logsRawZip, "raw-zip", "", "Save the run's logs ZIP archive unprocessed to this file (\"-\" for stdout)")
+	logsCmd.MarkFlagsMutuallyExclusive("cancel", "rerun")
 
 	toolCmd.Flags().StringVar(&toolArgsJSON, "args", "{}", "Tool arguments as a JSON object")
 }
@@ -303,7 +421,7 @@ func runTool(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	cfg, err := loadConfigAllowMissingRepo()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -412,13 +530,33 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("repository owner and name must be specified via URL, config, or --owner/--repo flags")
 	}
 
+	if logsRawZip != "" {
+		if jobID > 0 {
+			return fmt.Errorf("--raw-zip saves a run's logs archive; job logs are plain text, so omit --job-id")
+		}
+		for _, name := range []string{"search", "regex", "section", "context", "tail", "head", "offset", "no-headers"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--raw-zip saves the logs archive unprocessed and cannot be combined with --%s", name)
+			}
+		}
+	}
+
+	tokens := github.NewTokenSource(cfg.Token, cfg.ReloadToken)
+	tokens.AddTokens(cfg.Tokens...)
+
 	// Create GitHub client
 	client, err := github.NewClientWithOptions(github.ClientOptions{
-		Token:      cfg.Token,
-		Owner:      owner,
-		Repo:       repo,
-		APIBaseURL: cfg.APIBaseURL,
-		UploadURL:  cfg.UploadURL,
+		Token:       cfg.Token,
+		Owner:       owner,
+		Repo:        repo,
+		Host:        cfg.Host,
+		APIBaseURL:  cfg.APIBaseURL,
+		UploadURL:   cfg.UploadURL,
+		AllowedRefs: cfg.AllowedTriggerRefs,
+		DefaultRefs: cfg.DefaultRefs(owner, repo),
+		LogCacheDir: cfg.LogCacheDir(),
+		TokenSource: tokens,
+		UserAgent:   github.UserAgent(cfg.Version, cfg.UserAgentSuffix),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create GitHub client: %w", err)
@@ -437,9 +575,12 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	// Fetch logs
 	var logs string
 
-	if logsSection != "" {
+	if logsRawZip != "" {
+		// Save the archive as-is
+		err = writeRawLogArchive(ctx, cmd, client, runID)
+	} else if logsSection != "" {
 		// Extract specific section
-		logs, err = client.GetLogSection(ctx, runID, jobID, logsSection, filterOpts)
+		logs, err = client.GetLogSection(ctx, runID, jobID, logsSection, logsHead, logsTail, logsOffset, filterOpts)
 	} else if jobID > 0 {
 		// Get logs for specific job
 		logs, err = client.GetWorkflowJobLogs(ctx, jobID, logsHead, logsTail, logsOffset, logsNoHeaders, filterOpts)
@@ -449,52 +590,82 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	}
 
 	if err != nil {
+		if actionErr := runLogsAction(ctx, cmd, client, runID, jobID); actionErr != nil {
+			log.Error(actionErr)
+		}
+
 		// Provide helpful error messages for common HTTP errors
-		if github.IsHTTPError(err, 404) {
+		err = github.Classify(err)
+		if errors.Is(err, github.ErrLogsExpired) {
+			return fmt.Errorf("logs for run %d in %s/%s have expired and are no longer available", runID, owner, repo)
+		}
+		if errors.Is(err, github.ErrNotFound) {
 			return fmt.Errorf("run or job not found (404). The run ID %d might not exist in %s/%s. Use the MCP tool list_repository_workflow_runs to find valid run IDs", runID, owner, repo)
 		}
-		if github.IsHTTPError(err, 401) {
+		if errors.Is(err, github.ErrUnauthorized) {
 			return fmt.Errorf("authentication failed (401). Your token may not have access to %s/%s or the repository is private", owner, repo)
 		}
 		return fmt.Errorf("failed to get logs: %w", err)
 	}
 
 	// Output results
-	if logs == "" {
+	switch {
+	case logsRawZip != "":
+		// Already written by writeRawLogArchive
+	case logs == "":
 		fmt.Println("(no matching logs)")
-	} else {
+	default:
 		fmt.Print(logs)
 	}
 
-	return nil
+	return runLogsAction(ctx, cmd, client, runID, jobID)
 }
 
-func Execute() {
-	// Add git info to version
-	version = getVersion()
-
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+// writeRawLogArchive saves the run's logs archive to the --raw-zip file, or to stdout for "-".
+// Status goes to stderr so that stdout holds only the archive.
+func writeRawLogArchive(ctx context.Context, cmd *cobra.Command, client *github.Client, runID int64) error {
+	if logsRawZip == "-" {
+		_, err := client.WriteRunLogArchive(ctx, runID, os.Stdout)
+		return err
 	}
-}
-
-func getVersion() string {
-	// Try to get version from build
-	buildInfo, ok := os.LookupEnv("VERSION")
-	if ok {
-		return buildInfo
+	result, err := client.SaveRunLogArchive(ctx, runID, logsRawZip)
+	if err != nil {
+		return err
 	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Saved logs of run %d to %s (%d bytes, %d files)\n", runID, result.SavedPath, result.SizeBytes, result.FileCount)
+	return nil
+}
 
-	// Try to get from git
-	if dir, err := os.Getwd(); err == nil {
-		gitDir := filepath.Join(dir, ".git")
-		if _, statErr := os.Stat(gitDir); statErr == nil {
-			gitCmd := exec.Command("git", "describe", "--tags", "--always")
-			if output, err := gitCmd.Output(); err == nil {
-				return string(output[:len(output)-1])
-			}
+// runLogsAction applies --cancel or --rerun to the run or job whose logs were shown.
+// Status goes to stderr so that stdout holds only the logs.
+func runLogsAction(ctx context.Context, cmd *cobra.Command, client *github.Client, runID, jobID int64) error {
+	out := cmd.ErrOrStderr()
+	switch {
+	case logsCancel:
+		if err := client.CancelWorkflowRun(ctx, runID); err != nil {
+			return err
 		}
+		fmt.Fprintf(out, "Cancelled workflow run %d\n", runID)
+	case logsRerun && jobID > 0:
+		if err := client.RerunJob(ctx, jobID); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Triggered rerun of job %d\n", jobID)
+	case logsRerun:
+		result, err := client.ManageRun(ctx, runID, github.ManageRunActionRerunFailed)
+		if err != nil {
+			return err
+		}
+		if result.Status != "success" {
+			return fmt.Errorf("%s", result.Message)
+		}
+		fmt.Fprintln(out, result.Message)
 	}
+	return nil
+}
 
-	return version
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
 }
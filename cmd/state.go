@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/denysvitali/gh-actions-mcp/state"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	stateCmd.AddCommand(stateResetCmd)
+	stateCmd.AddCommand(statePathCmd)
+	rootCmd.AddCommand(stateCmd)
+}
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Manage persisted server state",
+	Long: `Manage state the server keeps between runs, such as get_new_failures cursors.
+
+State is stored in $XDG_DATA_HOME/gh-actions-mcp (or ~/.local/share/gh-actions-mcp)
+unless state_dir is set in the config file or GH_STATE_DIR is exported.`,
+}
+
+var stateResetCmd = &cobra.Command{
+	Use:   "reset [name...]",
+	Short: "Delete persisted state",
+	Long: `Delete the named state documents, or all of them when no name is given.
+
+Examples:
+  gh-actions-mcp state reset
+  gh-actions-mcp state reset failure_cursors`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openStateStore()
+		if err != nil {
+			return err
+		}
+		removed, err := store.Reset(args...)
+		if err != nil {
+			return err
+		}
+		if len(removed) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "No state to remove in %s\n", store.Dir())
+			return nil
+		}
+		for _, name := range removed {
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", name)
+		}
+		return nil
+	},
+}
+
+var statePathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the state directory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openStateStore()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), store.Dir())
+		return nil
+	},
+}
+
+// openStateStore opens the state store without requiring a token or repository.
+func openStateStore() (*state.Store, error) {
+	config.SetLogger(log)
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return state.Open(cfg.StateDir)
+}
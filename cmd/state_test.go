@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/denysvitali/gh-actions-mcp/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateResetRemovesDocuments(t *testing.T) {
+	restore := preserveCommandGlobals()
+	defer restore()
+
+	dir := filepath.Join(t.TempDir(), "state")
+	cfgFile = writeTestConfig(t, "state_dir: "+dir+"\n")
+
+	store, err := state.Open(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Save("failure_cursors", 1, map[string]int{"a": 1}))
+	require.NoError(t, store.Save("bookmarks", 1, []string{"x"}))
+
+	var out bytes.Buffer
+	stateResetCmd.SetOut(&out)
+	defer stateResetCmd.SetOut(nil)
+
+	require.NoError(t, stateResetCmd.RunE(stateResetCmd, []string{"failure_cursors"}))
+	assert.Equal(t, "Removed failure_cursors\n", out.String())
+
+	names, err := store.Names()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bookmarks"}, names)
+
+	out.Reset()
+	require.NoError(t, stateResetCmd.RunE(stateResetCmd, nil))
+	assert.Equal(t, "Removed bookmarks\n", out.String())
+}
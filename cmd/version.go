@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X github.com/denysvitali/gh-actions-mcp/cmd.version=v1.2.3 -X github.com/denysvitali/gh-actions-mcp/cmd.commit=abc1234"
+//
+// Otherwise they are filled in from the build information Go embeds in the binary.
+var (
+	version = ""
+	commit  = ""
+)
+
+// goGitHubModule is the GitHub API client module whose version is reported.
+const goGitHubModule = "github.com/google/go-github/v69"
+
+var versionJSON bool
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Print the build information as JSON")
+	rootCmd.AddCommand(versionCmd)
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version and build information",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info := currentVersion()
+		out := cmd.OutOrStdout()
+		if versionJSON {
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(info)
+		}
+
+		fmt.Fprintf(out, "gh-actions-mcp %s\n", info.Version)
+		if info.Commit != "" {
+			commit := info.Commit
+			if info.Modified {
+				commit += " (modified)"
+			}
+			fmt.Fprintf(out, "Commit:    %s\n", commit)
+		}
+		if info.Date != "" {
+			fmt.Fprintf(out, "Built:     %s\n", info.Date)
+		}
+		fmt.Fprintf(out, "Go:        %s\n", info.GoVersion)
+		if info.GoGitHub != "" {
+			fmt.Fprintf(out, "go-github: %s\n", info.GoGitHub)
+		}
+		return nil
+	},
+}
+
+// versionInfo describes the running binary.
+type versionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"go_version"`
+	GoGitHub  string `json:"go_github_version,omitempty"`
+}
+
+// currentVersion returns the version from the VERSION environment variable or
+// -ldflags, falling back to the build information embedded by the Go toolchain: the
+// module version for `go install`ed binaries and the VCS revision for builds from a
+// checkout.
+func currentVersion() versionInfo {
+	bi, _ := debug.ReadBuildInfo()
+	v := version
+	if env, ok := os.LookupEnv("VERSION"); ok && env != "" {
+		v = env
+	}
+	return buildVersion(bi, v, commit)
+}
+
+func buildVersion(bi *debug.BuildInfo, ldVersion, ldCommit string) versionInfo {
+	info := versionInfo{
+		Version:   ldVersion,
+		Commit:    ldCommit,
+		GoVersion: runtime.Version(),
+	}
+	if bi == nil {
+		if info.Version == "" {
+			info.Version = "dev"
+		}
+		return info
+	}
+
+	if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.time":
+			info.Date = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	if len(info.Commit) > 12 {
+		info.Commit = info.Commit[:12]
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if bi.GoVersion != "" {
+		info.GoVersion = bi.GoVersion
+	}
+
+	for _, dep := range bi.Deps {
+		if dep.Path != goGitHubModule {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		info.GoGitHub = strings.TrimSpace(dep.Version)
+	}
+	return info
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildVersionFromBuildInfo(t *testing.T) {
+	bi := &debug.BuildInfo{
+		GoVersion: "go1.24.1",
+		Main:      debug.Module{Path: "github.com/denysvitali/gh-actions-mcp", Version: "v1.4.0"},
+		Deps: []*debug.Module{
+			{Path: "github.com/spf13/cobra", Version: "v1.8.1"},
+			{Path: "github.com/google/go-github/v69", Version: "v69.2.0"},
+		},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	info := buildVersion(bi, "", "")
+	assert.Equal(t, "v1.4.0", info.Version)
+	assert.Equal(t, "0123456789ab", info.Commit)
+	assert.True(t, info.Modified)
+	assert.Equal(t, "go1.24.1", info.GoVersion)
+	assert.Equal(t, "v69.2.0", info.GoGitHub)
+
+	// ldflags take precedence over the embedded module version and revision.
+	info = buildVersion(bi, "v2.0.0", "feedbee")
+	assert.Equal(t, "v2.0.0", info.Version)
+	assert.Equal(t, "feedbee", info.Commit)
+}
+
+func TestBuildVersionDevelBuild(t *testing.T) {
+	info := buildVersion(&debug.BuildInfo{Main: debug.Module{Version: "(devel)"}}, "", "")
+	assert.Equal(t, "dev", info.Version)
+	assert.NotEmpty(t, info.GoVersion)
+
+	info = buildVersion(nil, "", "")
+	assert.Equal(t, "dev", info.Version)
+}
+
+func TestVersionCommandJSON(t *testing.T) {
+	oldJSON := versionJSON
+	t.Cleanup(func() { versionJSON = oldJSON })
+	versionJSON = true
+	t.Setenv("VERSION", "v9.9.9")
+
+	var out bytes.Buffer
+	versionCmd.SetOut(&out)
+	defer versionCmd.SetOut(nil)
+	require.NoError(t, versionCmd.RunE(versionCmd, nil))
+
+	var info versionInfo
+	require.NoError(t, json.Unmarshal(out.Bytes(), &info))
+	assert.Equal(t, "v9.9.9", info.Version)
+	assert.NotEmpty(t, info.GoVersion)
+}
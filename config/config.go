@@ -2,9 +2,12 @@ package config
 
 import (
 	"fmt"
-	"runtime"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"time"
 
+	"github.com/denysvitali/gh-actions-mcp/state"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -18,6 +21,16 @@ type Config struct {
 	DefaultLogLen int    `mapstructure:"default_log_len"`
 	PerPageLimit  int    `mapstructure:"per_page_limit"`
 	DefaultFormat string `mapstructure:"default_format"` // "minimal", "compact", "full"
+	// Remote is the git remote the repository is inferred from when
+	// repo_owner/repo_name are not set. Empty means the first existing
+	// remote in RemotePreference.
+	Remote string `mapstructure:"remote"`
+	// RemotePreference is the order in which git remotes are tried when
+	// Remote is empty. Defaults to upstream, then origin.
+	RemotePreference []string `mapstructure:"remote_preference"`
+	// Host is the GitHub host for repositories that do not name one, e.g.
+	// a GitHub Enterprise Server hostname. Defaults to github.com.
+	Host string `mapstructure:"host"`
 	// APIBaseURL overrides the GitHub API base URL. Useful for GitHub
 	// Enterprise or a reverse proxy (e.g. "http://gh-proxy:8080/api/").
 	// Must end with a trailing slash.
@@ -25,6 +38,130 @@ type Config struct {
 	// UploadURL overrides the GitHub upload URL. Defaults to APIBaseURL
 	// when empty.
 	UploadURL string `mapstructure:"upload_url"`
+	// DispatchDedupWindow is how many seconds an identical dispatch
+	// (same workflow, ref, and inputs) is treated as a duplicate of an
+	// earlier one. 0 disables the guard.
+	DispatchDedupWindow int `mapstructure:"dispatch_dedup_window"`
+	// DispatchDedupMode is "refuse" (default) to reject duplicate
+	// dispatches or "warn" to dispatch anyway and flag the duplicate.
+	DispatchDedupMode string `mapstructure:"dispatch_dedup_mode"`
+	// DisableSecretMasking turns off masking of credentials and
+	// high-entropy strings in logs returned by tools.
+	DisableSecretMasking bool `mapstructure:"disable_secret_masking"`
+	// AllowedTriggerRefs restricts workflow dispatches and reruns to
+	// branches or tags matching these glob patterns (e.g. "release/*").
+	// Empty allows every ref.
+	AllowedTriggerRefs []string `mapstructure:"allowed_trigger_refs"`
+	// WorkflowDefaultRefs pin the ref workflows are dispatched on when no
+	// ref is given, e.g. deploy.yml on a production branch, instead of
+	// the repository's default branch.
+	WorkflowDefaultRefs []WorkflowDefaultRef `mapstructure:"workflow_default_refs"`
+	// Repos lists "owner/repo" repositories that get_multi_repo_status
+	// reports on when no repos argument is given.
+	Repos []string `mapstructure:"repos"`
+	// Tokens are extra GitHub tokens. Requests move on to the next one
+	// when the token in use exhausts its rate limit.
+	Tokens []string `mapstructure:"tokens"`
+	// StateDir is where state that outlives a single run (failure cursors,
+	// bookmarks, watch state) is stored. Defaults to
+	// $XDG_DATA_HOME/gh-actions-mcp.
+	StateDir string `mapstructure:"state_dir"`
+	// TokenCommand is a shell command that prints a GitHub token. It is
+	// run when no token is configured, and again when GitHub rejects the
+	// token, so rotated tokens are picked up without a restart.
+	TokenCommand string `mapstructure:"token_command"`
+	// RequireRepo refuses to start without a default repository, instead
+	// of requiring owner and repo arguments on each tool call.
+	RequireRepo bool `mapstructure:"require_repo"`
+	// ValidateOnStart makes the server check the token and repository
+	// with one API call before serving, and exit if they are unusable.
+	ValidateOnStart bool `mapstructure:"validate_on_start"`
+	// NotifyWebhookURL receives a JSON POST ({"text": ...}, as accepted
+	// by Slack and compatible incoming webhooks) when a run being waited
+	// on is blocked on an environment approval. Empty disables it.
+	NotifyWebhookURL string `mapstructure:"notify_webhook_url"`
+	// Schedules lists tools the server calls on a cron schedule while it
+	// is running, for maintenance that would otherwise need external cron.
+	Schedules []Schedule `mapstructure:"schedules"`
+	// OutputTransforms post-process the text of tool results, for
+	// redaction or trimming policies beyond the built-in secret masking.
+	OutputTransforms OutputTransforms `mapstructure:"output_transforms"`
+	// ScratchThreshold is the size in bytes above which a tool result is
+	// written to a temporary scratch resource and replaced by a summary
+	// with the resource's URI. 0 disables it.
+	ScratchThreshold int `mapstructure:"scratch_threshold"`
+	// ScratchTTL is how many seconds a scratch resource stays readable.
+	ScratchTTL int `mapstructure:"scratch_ttl"`
+	// Timezone is the IANA time zone (e.g. "Europe/Zurich", or "Local")
+	// human-readable output such as markdown reports shows times in. JSON
+	// output always uses UTC RFC3339. Defaults to UTC.
+	Timezone string `mapstructure:"timezone"`
+	// UserAgentSuffix is appended to the User-Agent of API requests, e.g.
+	// a contact address, so GitHub support can reach the operator when
+	// investigating abuse or rate limit issues.
+	UserAgentSuffix string `mapstructure:"user_agent_suffix"`
+	// TokenSource records where Token came from (see the TokenSource*
+	// constants); set by Load and ValidateToken.
+	TokenSource string `mapstructure:"-"`
+	// Version is the version of the running binary, reported in the
+	// User-Agent; set by the command line.
+	Version string `mapstructure:"-"`
+	// EnvOnly is set by LoadFromEnv. Tokens are then never read from the
+	// macOS keychain or gh's hosts.yml.
+	EnvOnly bool `mapstructure:"-"`
+}
+
+// Schedule is a tool call the server makes on a cron schedule.
+type Schedule struct {
+	// Name identifies the schedule in logs. Defaults to the tool name.
+	Name string `mapstructure:"name"`
+	// Cron is a five-field cron expression (minute hour day-of-month
+	// month day-of-week) or one of @hourly, @daily, @midnight, @weekly,
+	// and @monthly, evaluated in local time.
+	Cron string `mapstructure:"cron"`
+	// Tool is the name of the MCP tool to call.
+	Tool string `mapstructure:"tool"`
+	// Args are the tool's arguments.
+	Args map[string]interface{} `mapstructure:"args"`
+}
+
+// WorkflowDefaultRef is the ref a workflow is dispatched on when no ref is given.
+type WorkflowDefaultRef struct {
+	// Workflow is the workflow's file name (deploy.yml), path
+	// (.github/workflows/deploy.yml), name, or numeric ID.
+	Workflow string `mapstructure:"workflow"`
+	// Ref is the branch or tag to dispatch on.
+	Ref string `mapstructure:"ref"`
+	// Repo limits the entry to one "owner/repo" repository. Empty applies
+	// it to every repository.
+	Repo string `mapstructure:"repo"`
+}
+
+// OutputTransforms are applied to tool results in this order: fields are dropped and
+// long strings shortened in JSON results, then replacements run over the text, and
+// finally the text is cut to MaxLength.
+type OutputTransforms struct {
+	// Tools limits the transforms to these tools. Empty applies them to
+	// every tool. Job log resources are transformed whatever it lists.
+	Tools []string `mapstructure:"tools"`
+	// DropFields removes these keys, at any depth, from JSON results.
+	DropFields []string `mapstructure:"drop_fields"`
+	// MaxStringLength shortens longer string values in JSON results.
+	// 0 disables it.
+	MaxStringLength int `mapstructure:"max_string_length"`
+	// Replace lists regular expression replacements, applied in order.
+	Replace []Replacement `mapstructure:"replace"`
+	// MaxLength cuts result text longer than this many characters.
+	// 0 disables it.
+	MaxLength int `mapstructure:"max_length"`
+}
+
+// Replacement replaces matches of a regular expression in tool results.
+type Replacement struct {
+	// Pattern is an RE2 regular expression.
+	Pattern string `mapstructure:"pattern"`
+	// Replacement may refer to capture groups as $1 or ${name}.
+	Replacement string `mapstructure:"replacement"`
 }
 
 var log = logrus.New()
@@ -35,28 +172,7 @@ func SetLogger(l *logrus.Logger) {
 }
 
 func Load(configPath string) (*Config, error) {
-	v := viper.New()
-
-	// Set defaults
-	v.SetDefault("log_level", "info")
-	v.SetDefault("token", "")
-	v.SetDefault("default_limit", 10)
-	v.SetDefault("default_log_len", 100)
-	v.SetDefault("per_page_limit", 50)
-	v.SetDefault("default_format", "compact")
-
-	// Environment variables - support both GITHUB_* and GH_* prefixes
-	// GITHUB_* prefix takes precedence over GH_* prefix for backward compatibility
-	_ = v.BindEnv("token", "GITHUB_TOKEN", "GH_TOKEN")
-	_ = v.BindEnv("repo_owner", "GITHUB_REPO_OWNER", "GH_REPO_OWNER")
-	_ = v.BindEnv("repo_name", "GITHUB_REPO_NAME", "GH_REPO_NAME")
-	_ = v.BindEnv("log_level", "GITHUB_LOG_LEVEL", "GH_LOG_LEVEL")
-	_ = v.BindEnv("default_limit", "GITHUB_DEFAULT_LIMIT", "GH_DEFAULT_LIMIT")
-	_ = v.BindEnv("default_log_len", "GITHUB_DEFAULT_LOG_LEN", "GH_DEFAULT_LOG_LEN")
-	_ = v.BindEnv("per_page_limit", "GITHUB_PER_PAGE_LIMIT", "GH_PER_PAGE_LIMIT")
-	_ = v.BindEnv("default_format", "GITHUB_DEFAULT_FORMAT", "GH_DEFAULT_FORMAT")
-	_ = v.BindEnv("api_base_url", "GITHUB_API_BASE_URL", "GH_API_BASE_URL")
-	_ = v.BindEnv("upload_url", "GITHUB_UPLOAD_URL", "GH_UPLOAD_URL")
+	v := newViper()
 
 	// Config file. We support two modes:
 	//   1) Explicit path via --config / configPath: load that single file.
@@ -97,6 +213,70 @@ func Load(configPath string) (*Config, error) {
 		}
 	}
 
+	return decode(v)
+}
+
+// LoadFromEnv loads the configuration from defaults and environment variables only. No
+// config file is read, and tokens are only taken from the environment or token_command.
+func LoadFromEnv() (*Config, error) {
+	cfg, err := decode(newViper())
+	if err != nil {
+		return nil, err
+	}
+	cfg.EnvOnly = true
+	return cfg, nil
+}
+
+// newViper returns a viper instance with the defaults and environment bindings.
+func newViper() *viper.Viper {
+	v := viper.New()
+
+	// Set defaults
+	v.SetDefault("log_level", "info")
+	v.SetDefault("token", "")
+	v.SetDefault("default_limit", 10)
+	v.SetDefault("default_log_len", 100)
+	v.SetDefault("per_page_limit", 50)
+	v.SetDefault("default_format", "compact")
+	v.SetDefault("dispatch_dedup_window", 60)
+	v.SetDefault("dispatch_dedup_mode", "refuse")
+	v.SetDefault("scratch_threshold", 200000)
+	v.SetDefault("scratch_ttl", 3600)
+
+	// Environment variables - support both GITHUB_* and GH_* prefixes
+	// GITHUB_* prefix takes precedence over GH_* prefix for backward compatibility
+	_ = v.BindEnv("token", "GITHUB_TOKEN", "GH_TOKEN")
+	_ = v.BindEnv("repo_owner", "GITHUB_REPO_OWNER", "GH_REPO_OWNER")
+	_ = v.BindEnv("repo_name", "GITHUB_REPO_NAME", "GH_REPO_NAME")
+	_ = v.BindEnv("log_level", "GITHUB_LOG_LEVEL", "GH_LOG_LEVEL")
+	_ = v.BindEnv("default_limit", "GITHUB_DEFAULT_LIMIT", "GH_DEFAULT_LIMIT")
+	_ = v.BindEnv("default_log_len", "GITHUB_DEFAULT_LOG_LEN", "GH_DEFAULT_LOG_LEN")
+	_ = v.BindEnv("per_page_limit", "GITHUB_PER_PAGE_LIMIT", "GH_PER_PAGE_LIMIT")
+	_ = v.BindEnv("default_format", "GITHUB_DEFAULT_FORMAT", "GH_DEFAULT_FORMAT")
+	_ = v.BindEnv("remote", "GITHUB_REMOTE", "GH_REMOTE")
+	_ = v.BindEnv("remote_preference", "GITHUB_REMOTE_PREFERENCE", "GH_REMOTE_PREFERENCE")
+	_ = v.BindEnv("host", "GITHUB_HOST", "GH_HOST")
+	_ = v.BindEnv("api_base_url", "GITHUB_API_BASE_URL", "GH_API_BASE_URL")
+	_ = v.BindEnv("upload_url", "GITHUB_UPLOAD_URL", "GH_UPLOAD_URL")
+	_ = v.BindEnv("dispatch_dedup_window", "GITHUB_DISPATCH_DEDUP_WINDOW", "GH_DISPATCH_DEDUP_WINDOW")
+	_ = v.BindEnv("dispatch_dedup_mode", "GITHUB_DISPATCH_DEDUP_MODE", "GH_DISPATCH_DEDUP_MODE")
+	_ = v.BindEnv("disable_secret_masking", "GITHUB_DISABLE_SECRET_MASKING", "GH_DISABLE_SECRET_MASKING")
+	_ = v.BindEnv("allowed_trigger_refs", "GITHUB_ALLOWED_TRIGGER_REFS", "GH_ALLOWED_TRIGGER_REFS")
+	_ = v.BindEnv("repos", "GITHUB_REPOS", "GH_REPOS")
+	_ = v.BindEnv("state_dir", "GITHUB_STATE_DIR", "GH_STATE_DIR")
+	_ = v.BindEnv("tokens", "GITHUB_TOKENS", "GH_TOKENS")
+	_ = v.BindEnv("token_command", "GITHUB_TOKEN_COMMAND", "GH_TOKEN_COMMAND")
+	_ = v.BindEnv("require_repo", "GITHUB_REQUIRE_REPO", "GH_REQUIRE_REPO")
+	_ = v.BindEnv("validate_on_start", "GITHUB_VALIDATE_ON_START", "GH_VALIDATE_ON_START")
+	_ = v.BindEnv("notify_webhook_url", "GITHUB_NOTIFY_WEBHOOK_URL", "GH_NOTIFY_WEBHOOK_URL")
+	_ = v.BindEnv("timezone", "GITHUB_TIMEZONE", "GH_TIMEZONE")
+	_ = v.BindEnv("user_agent_suffix", "GITHUB_USER_AGENT_SUFFIX", "GH_USER_AGENT_SUFFIX")
+	_ = v.BindEnv("scratch_threshold", "GITHUB_SCRATCH_THRESHOLD", "GH_SCRATCH_THRESHOLD")
+	_ = v.BindEnv("scratch_ttl", "GITHUB_SCRATCH_TTL", "GH_SCRATCH_TTL")
+	return v
+}
+
+func decode(v *viper.Viper) (*Config, error) {
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("config file validation error: %w\nEnsure all config values have correct types (strings, numbers, etc.)", err)
@@ -104,17 +284,137 @@ func Load(configPath string) (*Config, error) {
 
 	// Override with environment variable if set
 	if token := v.GetString("token"); token != "" {
-		cfg.Token = token
+		cfg.SetToken(token, TokenSourceConfig)
 	}
 
 	log.Debugf("Loaded config: owner=%s, repo=%s", cfg.RepoOwner, cfg.RepoName)
 	return &cfg, nil
 }
 
+// EffectiveSettings returns the configuration keyed by config file key, for logging. The
+// token is replaced by where it came from, tokens by their count, and token_command by
+// whether it is set.
+func (c *Config) EffectiveSettings() map[string]interface{} {
+	settings := make(map[string]interface{})
+	v := reflect.ValueOf(*c)
+	for i := 0; i < v.NumField(); i++ {
+		key := v.Type().Field(i).Tag.Get("mapstructure")
+		if key == "" || key == "-" {
+			continue
+		}
+		settings[key] = v.Field(i).Interface()
+	}
+
+	settings["token"] = "(none)"
+	if c.Token != "" {
+		settings["token"] = "(from " + c.TokenSource + ")"
+	}
+	settings["tokens"] = fmt.Sprintf("(%d tokens)", len(c.Tokens))
+	if c.TokenCommand != "" {
+		settings["token_command"] = "(set)"
+	}
+	return settings
+}
+
+// defaultHost is the host used when Host is empty.
+const defaultHost = "github.com"
+
+// GitHubHost returns the configured host, github.com by default.
+func (c *Config) GitHubHost() string {
+	return normalizeHost(c.Host)
+}
+
+// normalizeHost lowercases host and strips any scheme; "" and api.github.com mean github.com.
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	if host == "" || host == "api.github.com" {
+		return defaultHost
+	}
+	return host
+}
+
+// Location returns the time zone named by Timezone, UTC when it is empty.
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w. Use an IANA time zone name such as Europe/Zurich", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// LogCacheDir returns where logs of completed jobs are cached: log-cache in the state
+// directory, or "" when the state directory cannot be determined.
+func (c *Config) LogCacheDir() string {
+	dir := c.StateDir
+	if dir == "" {
+		var err error
+		if dir, err = state.DefaultDir(); err != nil {
+			return ""
+		}
+	}
+	return filepath.Join(dir, "log-cache")
+}
+
+// DefaultRefs returns the default refs of workflows of the owner/repo repository, keyed by
+// workflow as configured.
+func (c *Config) DefaultRefs(owner, repo string) map[string]string {
+	refs := make(map[string]string)
+	for _, entry := range c.WorkflowDefaultRefs {
+		if entry.Repo != "" && !strings.EqualFold(entry.Repo, owner+"/"+repo) {
+			continue
+		}
+		// An entry for the repository wins over one for every repository.
+		if _, ok := refs[entry.Workflow]; ok && entry.Repo == "" {
+			continue
+		}
+		refs[entry.Workflow] = entry.Ref
+	}
+	return refs
+}
+
+// validateWorkflowDefaultRefs checks that every workflow default ref names a workflow and a
+// ref, and that no workflow has two for the same repository.
+func (c *Config) validateWorkflowDefaultRefs() error {
+	seen := make(map[string]bool)
+	for i, entry := range c.WorkflowDefaultRefs {
+		if strings.TrimSpace(entry.Workflow) == "" || strings.TrimSpace(entry.Ref) == "" {
+			return fmt.Errorf("workflow_default_refs[%d] needs both workflow and ref", i)
+		}
+		if entry.Repo != "" {
+			if owner, repo, ok := strings.Cut(entry.Repo, "/"); !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+				return fmt.Errorf("workflow_default_refs[%d]: invalid repo %q, expected owner/repo", i, entry.Repo)
+			}
+		}
+		key := strings.ToLower(entry.Repo) + " " + entry.Workflow
+		if seen[key] {
+			return fmt.Errorf("workflow_default_refs[%d]: %s has more than one default ref", i, entry.Workflow)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Validate checks that a token is available and that the default repository is either
+// fully configured or, unless RequireRepo is set, absent. Without a default repository
+// every tool call has to name its repository.
 func (c *Config) Validate() error {
 	if err := c.ValidateToken(); err != nil {
 		return err
 	}
+	if _, err := c.Location(); err != nil {
+		return err
+	}
+	if err := c.validateWorkflowDefaultRefs(); err != nil {
+		return err
+	}
+	if c.RepoOwner == "" && c.RepoName == "" && !c.RequireRepo {
+		return nil
+	}
 	if c.RepoOwner == "" {
 		return fmt.Errorf("repository owner is required. Set GH_REPO_OWNER env var, 'repo_owner' in config, or use --repo-owner flag")
 	}
@@ -126,24 +426,28 @@ func (c *Config) Validate() error {
 
 func (c *Config) ValidateToken() error {
 	if c.Token == "" {
-		// Try to get token from macOS keychain (only on macOS)
-		if runtime.GOOS == "darwin" {
-			if token, err := keychainTokenProvider(); err == nil {
-				c.Token = token
-				log.Infof("Obtained GitHub token from macOS keychain")
-			} else {
-				log.Debugf("Could not get token from keychain: %v", err)
-			}
+		token, source, err := c.resolveToken()
+		if err != nil {
+			return err
+		}
+		if token != "" {
+			c.SetToken(token, source)
+			log.Infof("Obtained GitHub token from %s", source)
 		}
+	} else if c.TokenSource == "" {
+		c.TokenSource = TokenSourceConfig
 	}
 
 	if c.Token == "" {
-		return fmt.Errorf("GitHub token is required. Set GITHUB_TOKEN environment variable, set 'token' in config file, or run 'gh auth login' on macOS")
+		return fmt.Errorf("GitHub token is required. Set GITHUB_TOKEN environment variable, set 'token' or 'token_command' in config file, or run 'gh auth login'")
 	}
 	return nil
 }
 
 // IsAuthenticationError checks if an error is likely related to authentication
+//
+// Deprecated: match the typed errors from the github package instead, e.g.
+// errors.Is(github.Classify(err), github.ErrUnauthorized).
 func IsAuthenticationError(err error) bool {
 	if err == nil {
 		return false
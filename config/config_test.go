@@ -2,10 +2,12 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -80,6 +82,10 @@ func TestLoad_DefaultValues(t *testing.T) {
 
 	// Check defaults
 	assert.Equal(t, "info", cfg.LogLevel)
+	assert.Equal(t, 60, cfg.DispatchDedupWindow)
+	assert.Equal(t, "refuse", cfg.DispatchDedupMode)
+	assert.Equal(t, 200000, cfg.ScratchThreshold)
+	assert.Equal(t, 3600, cfg.ScratchTTL)
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -90,6 +96,7 @@ func TestConfig_Validate(t *testing.T) {
 	t.Cleanup(func() {
 		keychainTokenProvider = originalProvider
 	})
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
 
 	tests := []struct {
 		name      string
@@ -132,6 +139,21 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "no repository",
+			cfg: Config{
+				Token: "token",
+			},
+			wantError: false,
+		},
+		{
+			name: "no repository when required",
+			cfg: Config{
+				Token:       "token",
+				RequireRepo: true,
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -154,6 +176,7 @@ func TestConfig_ValidateToken(t *testing.T) {
 	t.Cleanup(func() {
 		keychainTokenProvider = originalProvider
 	})
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
 
 	cfg := Config{Token: "token"}
 	require.NoError(t, cfg.ValidateToken())
@@ -393,3 +416,220 @@ func TestIsAuthenticationError(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_AllowedTriggerRefs(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("allowed_trigger_refs: [main, develop, \"release/*\"]\n"), 0644)
+	require.NoError(t, err)
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main", "develop", "release/*"}, cfg.AllowedTriggerRefs)
+
+	t.Setenv("GH_ALLOWED_TRIGGER_REFS", "main,release/*")
+	cfg, err = Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main", "release/*"}, cfg.AllowedTriggerRefs)
+}
+
+func TestLoad_Repos(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("repos: [acme/api, acme/web]\n"), 0644)
+	require.NoError(t, err)
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/api", "acme/web"}, cfg.Repos)
+
+	t.Setenv("GITHUB_REPOS", "acme/cli")
+	cfg, err = Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/cli"}, cfg.Repos)
+}
+
+func TestLoad_Tokens(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	err := os.WriteFile(configPath, []byte("token: primary\ntokens: [spare1, spare2]\n"), 0644)
+	require.NoError(t, err)
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spare1", "spare2"}, cfg.Tokens)
+
+	t.Setenv("GH_TOKENS", "env1,env2")
+	cfg, err = Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env1", "env2"}, cfg.Tokens)
+}
+
+func TestLoad_Schedules(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte(`schedules:
+  - name: stale-branches
+    cron: "0 2 * * *"
+    tool: get_stale_branch_report
+    args:
+      stale_days: 30
+  - cron: "0 9 * * 1"
+    tool: get_multi_repo_status
+`), 0644)
+	require.NoError(t, err)
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Schedules, 2)
+	assert.Equal(t, Schedule{Name: "stale-branches", Cron: "0 2 * * *", Tool: "get_stale_branch_report", Args: map[string]interface{}{"stale_days": 30}}, cfg.Schedules[0])
+	assert.Equal(t, "0 9 * * 1", cfg.Schedules[1].Cron)
+}
+
+func TestLoad_WorkflowDefaultRefs(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte(`workflow_default_refs:
+  - workflow: deploy.yml
+    ref: production
+  - workflow: deploy.yml
+    ref: release
+    repo: acme/api
+  - workflow: Docs
+    ref: gh-pages
+    repo: acme/web
+`), 0644)
+	require.NoError(t, err)
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.WorkflowDefaultRefs, 3)
+	assert.Equal(t, WorkflowDefaultRef{Workflow: "deploy.yml", Ref: "release", Repo: "acme/api"}, cfg.WorkflowDefaultRefs[1])
+	require.NoError(t, cfg.validateWorkflowDefaultRefs())
+
+	assert.Equal(t, map[string]string{"deploy.yml": "release"}, cfg.DefaultRefs("acme", "api"))
+	assert.Equal(t, map[string]string{"deploy.yml": "production", "Docs": "gh-pages"}, cfg.DefaultRefs("Acme", "Web"))
+	assert.Equal(t, map[string]string{"deploy.yml": "production"}, cfg.DefaultRefs("acme", "cli"))
+
+	cfg.WorkflowDefaultRefs = append(cfg.WorkflowDefaultRefs, WorkflowDefaultRef{Workflow: "deploy.yml", Ref: "main"})
+	assert.ErrorContains(t, cfg.validateWorkflowDefaultRefs(), "deploy.yml has more than one default ref")
+	cfg.WorkflowDefaultRefs = []WorkflowDefaultRef{{Workflow: "deploy.yml"}}
+	assert.ErrorContains(t, cfg.validateWorkflowDefaultRefs(), "needs both workflow and ref")
+	cfg.WorkflowDefaultRefs = []WorkflowDefaultRef{{Workflow: "deploy.yml", Ref: "main", Repo: "acme"}}
+	assert.ErrorContains(t, cfg.validateWorkflowDefaultRefs(), "expected owner/repo")
+}
+
+func TestLoad_OutputTransforms(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte(`output_transforms:
+  tools: [get_run, diagnose_failure]
+  drop_fields: [runner_name]
+  max_string_length: 2000
+  replace:
+    - pattern: 'acme-[a-z]+\.internal'
+      replacement: "[internal]"
+  max_length: 50000
+`), 0644)
+	require.NoError(t, err)
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, OutputTransforms{
+		Tools:           []string{"get_run", "diagnose_failure"},
+		DropFields:      []string{"runner_name"},
+		MaxStringLength: 2000,
+		Replace:         []Replacement{{Pattern: `acme-[a-z]+\.internal`, Replacement: "[internal]"}},
+		MaxLength:       50000,
+	}, cfg.OutputTransforms)
+}
+
+func TestLoad_Remote(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("remote_preference: [fork, upstream]\n"), 0644)
+	require.NoError(t, err)
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Remote)
+	assert.Equal(t, []string{"fork", "upstream"}, cfg.RemotePreference)
+
+	t.Setenv("GH_REMOTE", "origin")
+	t.Setenv("GH_REMOTE_PREFERENCE", "origin,upstream")
+	cfg, err = Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "origin", cfg.Remote)
+	assert.Equal(t, []string{"origin", "upstream"}, cfg.RemotePreference)
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	originalProvider := ghHostsTokenProvider
+	ghHostsTokenProvider = func(string) (string, error) {
+		return "from-hosts-yml", nil
+	}
+	t.Cleanup(func() {
+		ghHostsTokenProvider = originalProvider
+	})
+	// A config file in the default location must be ignored.
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".config", "gh-actions-mcp"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".config", "gh-actions-mcp", "config.yaml"), []byte("repo_owner: from-file\n"), 0o644))
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_REPO_NAME", "api")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.EnvOnly)
+	assert.Empty(t, cfg.RepoOwner)
+	assert.Equal(t, "api", cfg.RepoName)
+	assert.Equal(t, 10, cfg.DefaultLimit)
+
+	assert.Error(t, cfg.ValidateToken(), "hosts.yml must not be read in env-only mode")
+	assert.Empty(t, cfg.TokenForHost("ghe.example.com"))
+
+	t.Setenv("GH_TOKEN", "env-token")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	require.NoError(t, cfg.ValidateToken())
+	assert.Equal(t, "env-token", cfg.Token)
+}
+
+func TestEffectiveSettings(t *testing.T) {
+	cfg := &Config{Token: "ghp_abc123", TokenSource: TokenSourceConfig, TokenCommand: "vault read token", RepoOwner: "acme", Repos: []string{"acme/api"}, Tokens: []string{"ghp_pool1", "ghp_pool2"}}
+
+	settings := cfg.EffectiveSettings()
+	assert.Equal(t, "(from config)", settings["token"])
+	assert.Equal(t, "(set)", settings["token_command"])
+	assert.Equal(t, "(2 tokens)", settings["tokens"])
+	assert.Equal(t, "acme", settings["repo_owner"])
+	assert.Equal(t, []string{"acme/api"}, settings["repos"])
+	assert.NotContains(t, settings, "-")
+	assert.NotContains(t, fmt.Sprint(settings), "ghp_abc123")
+	assert.NotContains(t, fmt.Sprint(settings), "vault")
+	assert.NotContains(t, fmt.Sprint(settings), "ghp_pool")
+}
+
+func TestConfig_Location(t *testing.T) {
+	loc, err := (&Config{}).Location()
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	loc, err = (&Config{Timezone: "Local"}).Location()
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, loc)
+
+	_, err = (&Config{Timezone: "Mars/Olympus_Mons"}).Location()
+	assert.ErrorContains(t, err, `invalid timezone "Mars/Olympus_Mons"`)
+
+	cfg := &Config{Token: "token", TokenSource: TokenSourceConfig, Timezone: "Mars/Olympus_Mons"}
+	assert.Error(t, cfg.Validate())
+}
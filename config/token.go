@@ -0,0 +1,180 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Token sources recorded in Config.TokenSource.
+const (
+	TokenSourceFlag     = "flag"
+	TokenSourceConfig   = "config" // token in the config file or GITHUB_TOKEN/GH_TOKEN
+	TokenSourceCommand  = "token_command"
+	TokenSourceKeychain = "keychain"
+	TokenSourceGHHosts  = "gh_hosts"
+)
+
+// tokenCommandTimeout bounds how long token_command may run.
+const tokenCommandTimeout = 30 * time.Second
+
+// ghHostsTokenProvider reads the token gh stores in hosts.yml for a host; overridden in tests.
+var ghHostsTokenProvider = getTokenFromGHHosts
+
+// enterpriseTokenEnv holds the token for hosts other than the configured one, as in gh.
+var enterpriseTokenEnv = []string{"GITHUB_ENTERPRISE_TOKEN", "GH_ENTERPRISE_TOKEN"}
+
+// SetToken sets the token and records where it came from.
+func (c *Config) SetToken(token, source string) {
+	c.Token = token
+	c.TokenSource = source
+}
+
+// resolveToken looks up a token from token_command, the macOS keychain, and gh's hosts.yml,
+// in that order, and returns the token and its source. With EnvOnly only token_command
+// is consulted.
+func (c *Config) resolveToken() (string, string, error) {
+	if c.TokenCommand != "" {
+		token, err := runTokenCommand(c.TokenCommand)
+		if err != nil {
+			return "", "", err
+		}
+		return token, TokenSourceCommand, nil
+	}
+
+	if c.EnvOnly {
+		return "", "", nil
+	}
+
+	if runtime.GOOS == "darwin" {
+		token, err := keychainTokenProvider()
+		if err == nil {
+			return token, TokenSourceKeychain, nil
+		}
+		log.Debugf("Could not get token from keychain: %v", err)
+	}
+
+	token, err := ghHostsTokenProvider(c.GitHubHost())
+	if err == nil {
+		return token, TokenSourceGHHosts, nil
+	}
+	log.Debugf("Could not get token from gh hosts.yml: %v", err)
+
+	return "", "", nil
+}
+
+// ReloadToken resolves the token again from the source it was originally read from, so a
+// token rotated in the keychain, hosts.yml, or by token_command is picked up without a
+// restart. Tokens passed by flag, environment, or config file cannot be reloaded.
+func (c *Config) ReloadToken() (string, error) {
+	switch c.TokenSource {
+	case TokenSourceCommand:
+		return runTokenCommand(c.TokenCommand)
+	case TokenSourceKeychain:
+		return keychainTokenProvider()
+	case TokenSourceGHHosts:
+		return ghHostsTokenProvider(c.GitHubHost())
+	case "":
+		return "", fmt.Errorf("token source is unknown")
+	default:
+		return "", fmt.Errorf("token from %s cannot be reloaded", c.TokenSource)
+	}
+}
+
+// runTokenCommand runs command through the shell and returns its trimmed output.
+func runTokenCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("token_command failed: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("token_command failed: %w", err)
+	}
+
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("token_command printed no token")
+	}
+	return token, nil
+}
+
+// TokenForHost returns the token to use for host: the configured token for the configured
+// host, otherwise GITHUB_ENTERPRISE_TOKEN / GH_ENTERPRISE_TOKEN for enterprise hosts, or
+// the host's entry in gh's hosts.yml. It returns "" when no token is known, so the
+// configured token is never sent to another host.
+func (c *Config) TokenForHost(host string) string {
+	host = normalizeHost(host)
+	if host == c.GitHubHost() {
+		return c.Token
+	}
+	if host != defaultHost {
+		for _, env := range enterpriseTokenEnv {
+			if token := os.Getenv(env); token != "" {
+				return token
+			}
+		}
+	}
+	if c.EnvOnly {
+		return ""
+	}
+	token, err := ghHostsTokenProvider(host)
+	if err != nil {
+		log.Debugf("No token for %s: %v", host, err)
+		return ""
+	}
+	return token
+}
+
+// getTokenFromGHHosts reads the token for host from gh's hosts.yml. Recent gh versions
+// keep the token in the system keyring instead, in which case no token is found here.
+func getTokenFromGHHosts(host string) (string, error) {
+	dir := os.Getenv("GH_CONFIG_DIR")
+	if dir == "" {
+		if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+			dir = filepath.Join(xdg, "gh")
+		} else {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			dir = filepath.Join(home, ".config", "gh")
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "hosts.yml"))
+	if err != nil {
+		return "", err
+	}
+
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", fmt.Errorf("failed to parse hosts.yml: %w", err)
+	}
+	token := hosts[host].OAuthToken
+	if token == "" {
+		return "", fmt.Errorf("no %s token in hosts.yml", host)
+	}
+	return token, nil
+}
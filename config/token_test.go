@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateToken_TokenCommandAndReload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell command")
+	}
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("ghp_first\n"), 0600))
+
+	cfg := Config{TokenCommand: "cat " + tokenFile}
+	require.NoError(t, cfg.ValidateToken())
+	assert.Equal(t, "ghp_first", cfg.Token)
+	assert.Equal(t, TokenSourceCommand, cfg.TokenSource)
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("ghp_rotated\n"), 0600))
+	token, err := cfg.ReloadToken()
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_rotated", token)
+
+	cfg = Config{TokenCommand: "exit 3"}
+	assert.Error(t, cfg.ValidateToken())
+}
+
+func TestValidateToken_GHHosts(t *testing.T) {
+	originalProvider := keychainTokenProvider
+	keychainTokenProvider = func() (string, error) {
+		return "", errors.New("no token in test keychain")
+	}
+	t.Cleanup(func() {
+		keychainTokenProvider = originalProvider
+	})
+
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	hosts := "github.com:\n    user: octocat\n    oauth_token: gho_fromhosts\n    git_protocol: https\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0600))
+
+	cfg := Config{}
+	require.NoError(t, cfg.ValidateToken())
+	assert.Equal(t, "gho_fromhosts", cfg.Token)
+	assert.Equal(t, TokenSourceGHHosts, cfg.TokenSource)
+}
+
+func TestReloadToken_StaticSources(t *testing.T) {
+	cfg := Config{Token: "ghp_static"}
+	require.NoError(t, cfg.ValidateToken())
+	assert.Equal(t, TokenSourceConfig, cfg.TokenSource)
+
+	_, err := cfg.ReloadToken()
+	assert.Error(t, err)
+
+	cfg.SetToken("ghp_flag", TokenSourceFlag)
+	_, err = cfg.ReloadToken()
+	assert.Error(t, err)
+}
+
+func TestTokenForHost(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	hosts := "github.com:\n    oauth_token: gho_dotcom\nghe.example.com:\n    oauth_token: gho_ghes\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0600))
+
+	cfg := Config{Token: "ghp_configured"}
+	assert.Equal(t, "ghp_configured", cfg.TokenForHost(""))
+	assert.Equal(t, "ghp_configured", cfg.TokenForHost("GitHub.com"))
+	assert.Equal(t, "gho_ghes", cfg.TokenForHost("ghe.example.com"))
+	assert.Empty(t, cfg.TokenForHost("other.example.com"))
+
+	t.Setenv("GH_ENTERPRISE_TOKEN", "ghp_enterprise")
+	assert.Equal(t, "ghp_enterprise", cfg.TokenForHost("ghe.example.com"))
+
+	cfg = Config{Token: "ghp_configured", Host: "ghe.example.com"}
+	assert.Equal(t, "ghp_configured", cfg.TokenForHost("ghe.example.com"))
+	assert.Equal(t, "gho_dotcom", cfg.TokenForHost("github.com"))
+}
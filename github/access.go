@@ -0,0 +1,21 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckAccess makes one cheap authenticated request to confirm that the token is
+// accepted and, when the client has a repository, that the token can read it.
+func (c *Client) CheckAccess(ctx context.Context) error {
+	if c.owner == "" || c.repo == "" {
+		if _, _, err := c.gh.RateLimit.Get(ctx); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", Classify(err))
+		}
+		return nil
+	}
+	if _, _, err := c.gh.Repositories.Get(ctx, c.owner, c.repo); err != nil {
+		return fmt.Errorf("failed to read repository %s: %w", c.FullName(), Classify(err))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAccess(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":1,"full_name":"o/r"}`))
+	})
+	mux.HandleFunc("/repos/o/private", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	})
+	mux.HandleFunc("/rate_limit", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	newClient := func(owner, repo string) *Client {
+		ghc := githubapi.NewClient(nil)
+		ghc.BaseURL, _ = url.Parse(ts.URL + "/")
+		return &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+	}
+
+	require.NoError(t, newClient("o", "r").CheckAccess(context.Background()))
+
+	err := newClient("o", "private").CheckAccess(context.Background())
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorContains(t, err, "o/private")
+
+	err = newClient("", "").CheckAccess(context.Background())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
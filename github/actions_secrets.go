@@ -0,0 +1,183 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v69/github"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// SecretInfo describes an Actions secret. GitHub never returns secret values.
+type SecretInfo struct {
+	Name        string `json:"name"`
+	Scope       string `json:"scope"`                 // organization, repository, or environment
+	Environment string `json:"environment,omitempty"` // Set for environment secrets
+	CreatedAt   string `json:"created_at,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+// SecretUpdate is the outcome of SetSecret.
+type SecretUpdate struct {
+	Name        string `json:"name"`
+	Scope       string `json:"scope"`
+	Environment string `json:"environment,omitempty"`
+	Created     bool   `json:"created"` // false when an existing secret was replaced
+}
+
+// ListSecrets lists the names of the Actions secrets available to the repository's
+// workflows: the organization secrets shared with it and its own, plus those of
+// environment when it is not empty.
+func (c *Client) ListSecrets(ctx context.Context, environment string) ([]*SecretInfo, error) {
+	var result []*SecretInfo
+	org, err := c.listSecrets(ctx, VariableScopeOrganization, "")
+	// Repositories owned by a user have no organization secrets.
+	if err != nil && !IsHTTPError(err, http.StatusNotFound) {
+		return nil, err
+	}
+	result = append(result, org...)
+
+	repo, err := c.listSecrets(ctx, VariableScopeRepository, "")
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, repo...)
+
+	if environment != "" {
+		env, err := c.listSecrets(ctx, VariableScopeEnvironment, environment)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, env...)
+	}
+	return result, nil
+}
+
+// listSecrets lists the secrets of one scope.
+func (c *Client) listSecrets(ctx context.Context, scope, environment string) ([]*SecretInfo, error) {
+	var repoID int
+	if scope == VariableScopeEnvironment {
+		id, err := c.repositoryID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		repoID = id
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	var result []*SecretInfo
+	for {
+		var secrets *github.Secrets
+		var resp *github.Response
+		var err error
+		switch scope {
+		case VariableScopeOrganization:
+			secrets, resp, err = c.gh.Actions.ListRepoOrgSecrets(ctx, c.owner, c.repo, opts)
+		case VariableScopeEnvironment:
+			secrets, resp, err = c.gh.Actions.ListEnvSecrets(ctx, repoID, url.PathEscape(environment), opts)
+		default:
+			secrets, resp, err = c.gh.Actions.ListRepoSecrets(ctx, c.owner, c.repo, opts)
+		}
+		if err != nil {
+			if scope == VariableScopeEnvironment {
+				return nil, fmt.Errorf("failed to list secrets of environment %s: %w", environment, Classify(err))
+			}
+			return nil, fmt.Errorf("failed to list %s secrets: %w", scope, Classify(err))
+		}
+		for _, s := range secrets.Secrets {
+			info := &SecretInfo{
+				Name:      s.Name,
+				Scope:     scope,
+				CreatedAt: formatTimeValue(s.CreatedAt),
+				UpdatedAt: formatTimeValue(s.UpdatedAt),
+			}
+			if scope == VariableScopeEnvironment {
+				info.Environment = environment
+			}
+			result = append(result, info)
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+// SetSecret creates or replaces a repository secret, or an environment secret when
+// environment is not empty. The value is encrypted locally with the repository's (or
+// environment's) public key as a libsodium sealed box; only the ciphertext is sent.
+func (c *Client) SetSecret(ctx context.Context, name, value, environment string) (*SecretUpdate, error) {
+	if err := validateConfigName("secret", name); err != nil {
+		return nil, err
+	}
+
+	update := &SecretUpdate{Name: name, Scope: VariableScopeRepository}
+	var repoID int
+	var key *github.PublicKey
+	var err error
+	if environment != "" {
+		update.Scope, update.Environment = VariableScopeEnvironment, environment
+		if repoID, err = c.repositoryID(ctx); err != nil {
+			return nil, err
+		}
+		key, _, err = c.gh.Actions.GetEnvPublicKey(ctx, repoID, url.PathEscape(environment))
+	} else {
+		key, _, err = c.gh.Actions.GetRepoPublicKey(ctx, c.owner, c.repo)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key for secret %s: %w", name, Classify(err))
+	}
+
+	encrypted, err := sealSecret(key.GetKey(), value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt secret %s: %w", name, err)
+	}
+	secret := &github.EncryptedSecret{Name: name, KeyID: key.GetKeyID(), EncryptedValue: encrypted}
+
+	var resp *github.Response
+	if environment != "" {
+		resp, err = c.gh.Actions.CreateOrUpdateEnvSecret(ctx, repoID, url.PathEscape(environment), secret)
+	} else {
+		resp, err = c.gh.Actions.CreateOrUpdateRepoSecret(ctx, c.owner, c.repo, secret)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set secret %s: %w", name, Classify(err))
+	}
+	update.Created = resp != nil && resp.StatusCode == http.StatusCreated
+	return update, nil
+}
+
+// sealSecret encrypts value for a base64-encoded Curve25519 public key as a libsodium
+// sealed box, the format GitHub expects for secret values.
+func sealSecret(publicKey, value string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(raw) != 32 {
+		return "", fmt.Errorf("invalid public key: %d bytes, want 32", len(raw))
+	}
+	var recipient [32]byte
+	copy(recipient[:], raw)
+
+	sealed, err := box.SealAnonymous(nil, []byte(value), &recipient, rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// repositoryID returns the numeric ID of the repository, which the environment secret
+// endpoints are addressed by.
+func (c *Client) repositoryID(ctx context.Context) (int, error) {
+	repository, _, err := c.gh.Repositories.Get(ctx, c.owner, c.repo)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get repository %s/%s: %w", c.owner, c.repo, Classify(err))
+	}
+	return int(repository.GetID()), nil
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/box"
+)
+
+func TestSecrets(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	var stored map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 42, "name": "test-repo"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/organization-secrets", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/secrets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "secrets": [{"name": "NPM_TOKEN", "created_at": "2026-01-01T00:00:00Z", "updated_at": "2026-02-01T00:00:00Z"}]}`))
+	})
+	mux.HandleFunc("/repositories/42/environments/production/secrets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "secrets": [{"name": "DEPLOY_KEY", "created_at": "2026-01-01T00:00:00Z", "updated_at": "2026-01-01T00:00:00Z"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/secrets/public-key", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"key_id": "key-1", "key": %q}`, base64.StdEncoding.EncodeToString(publicKey[:]))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/secrets/NPM_TOKEN", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&stored))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	t.Run("list", func(t *testing.T) {
+		secrets, err := client.ListSecrets(context.Background(), "production")
+		require.NoError(t, err)
+		require.Len(t, secrets, 2)
+		assert.Equal(t, &SecretInfo{Name: "NPM_TOKEN", Scope: VariableScopeRepository, CreatedAt: "2026-01-01T00:00:00Z", UpdatedAt: "2026-02-01T00:00:00Z"}, secrets[0])
+		assert.Equal(t, "DEPLOY_KEY", secrets[1].Name)
+		assert.Equal(t, "production", secrets[1].Environment)
+	})
+
+	t.Run("set encrypts the value", func(t *testing.T) {
+		update, err := client.SetSecret(context.Background(), "NPM_TOKEN", "s3cr3t", "")
+		require.NoError(t, err)
+		assert.Equal(t, &SecretUpdate{Name: "NPM_TOKEN", Scope: VariableScopeRepository}, update)
+
+		assert.Equal(t, "key-1", stored["key_id"])
+		sealed, err := base64.StdEncoding.DecodeString(stored["encrypted_value"])
+		require.NoError(t, err)
+		plain, ok := box.OpenAnonymous(nil, sealed, publicKey, privateKey)
+		require.True(t, ok)
+		assert.Equal(t, "s3cr3t", string(plain))
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		for _, name := range []string{"GITHUB_TOKEN", "1TOKEN", "MY-TOKEN"} {
+			_, err := client.SetSecret(context.Background(), name, "x", "")
+			assert.Error(t, err, name)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// RunStatusWaitingForApproval is the WaitRunResult status of a run that is blocked on a
+// deployment protection rule requiring a reviewer's approval.
+const RunStatusWaitingForApproval = "waiting_for_approval"
+
+// PendingApproval describes the environments a run is waiting on and who may approve them.
+type PendingApproval struct {
+	Environments []string `json:"environments"`
+	Reviewers    []string `json:"reviewers,omitempty"` // User logins and "org/team" slugs
+	CanApprove   bool     `json:"can_approve"`         // Whether the token's user may approve every environment
+	Message      string   `json:"message"`             // e.g. "waiting for approval by: octo-org/team-sre"
+}
+
+// GetPendingApproval returns the environment approvals a run is waiting on, or nil when
+// no deployment of the run is waiting for a reviewer.
+func (c *Client) GetPendingApproval(ctx context.Context, runID int64) (*PendingApproval, error) {
+	pending, _, err := c.gh.Actions.GetPendingDeployments(ctx, c.owner, c.repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending deployments for run %d: %w", runID, Classify(err))
+	}
+	return buildPendingApproval(pending), nil
+}
+
+// buildPendingApproval summarizes pending deployments, skipping those without required
+// reviewers (e.g. ones only held by a wait timer).
+func buildPendingApproval(pending []*github.PendingDeployment) *PendingApproval {
+	approval := &PendingApproval{CanApprove: true}
+	seen := make(map[string]bool)
+	for _, p := range pending {
+		if len(p.Reviewers) == 0 {
+			continue
+		}
+		approval.Environments = append(approval.Environments, p.GetEnvironment().GetName())
+		approval.CanApprove = approval.CanApprove && p.GetCurrentUserCanApprove()
+		for _, r := range p.Reviewers {
+			name := reviewerName(r)
+			if name != "" && !seen[name] {
+				seen[name] = true
+				approval.Reviewers = append(approval.Reviewers, name)
+			}
+		}
+	}
+	if len(approval.Environments) == 0 {
+		return nil
+	}
+
+	approval.Message = "waiting for approval"
+	if len(approval.Reviewers) > 0 {
+		approval.Message += " by: " + strings.Join(approval.Reviewers, ", ")
+	}
+	approval.Message += fmt.Sprintf(" (environment: %s)", strings.Join(approval.Environments, ", "))
+	return approval
+}
+
+// reviewerName returns a required reviewer's login, or "org/slug" for a team.
+func reviewerName(r *github.RequiredReviewer) string {
+	switch v := r.Reviewer.(type) {
+	case *github.User:
+		return v.GetLogin()
+	case *github.Team:
+		if org := v.GetOrganization().GetLogin(); org != "" {
+			return org + "/" + v.GetSlug()
+		}
+		return v.GetSlug()
+	}
+	return ""
+}
+
+// PendingDeployment is an environment a run is waiting on before a deployment job starts.
+type PendingDeployment struct {
+	EnvironmentID      int64    `json:"environment_id"`
+	Environment        string   `json:"environment"`
+	URL                string   `json:"url,omitempty"`
+	WaitTimerMinutes   int64    `json:"wait_timer_minutes,omitempty"`
+	WaitTimerStartedAt string   `json:"wait_timer_started_at,omitempty"`
+	Reviewers          []string `json:"reviewers,omitempty"` // User logins and "org/team" slugs
+	CanApprove         bool     `json:"can_approve"`         // Whether the token's user may review it
+}
+
+// DeploymentReview is the outcome of approving or rejecting a run's pending deployments.
+type DeploymentReview struct {
+	RunID        int64    `json:"run_id"`
+	State        string   `json:"state"` // approved or rejected
+	Environments []string `json:"environments"`
+	Deployments  []int64  `json:"deployments,omitempty"` // IDs of the deployments GitHub created
+	Comment      string   `json:"comment,omitempty"`
+}
+
+// ListPendingDeployments returns the environments a run is waiting on, including those
+// only held by a wait timer.
+func (c *Client) ListPendingDeployments(ctx context.Context, runID int64) ([]*PendingDeployment, error) {
+	pending, _, err := c.gh.Actions.GetPendingDeployments(ctx, c.owner, c.repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending deployments for run %d: %w", runID, Classify(err))
+	}
+	result := make([]*PendingDeployment, 0, len(pending))
+	for _, p := range pending {
+		d := &PendingDeployment{
+			EnvironmentID:      p.GetEnvironment().GetID(),
+			Environment:        p.GetEnvironment().GetName(),
+			URL:                p.GetEnvironment().GetHTMLURL(),
+			WaitTimerMinutes:   p.GetWaitTimer(),
+			WaitTimerStartedAt: formatTime(p.WaitTimerStartedAt),
+			CanApprove:         p.GetCurrentUserCanApprove(),
+		}
+		for _, r := range p.Reviewers {
+			if name := reviewerName(r); name != "" {
+				d.Reviewers = append(d.Reviewers, name)
+			}
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// ReviewPendingDeployments approves or rejects a run's pending deployments. state is
+// "approved" or "rejected". Without environmentIDs, every pending environment the token's
+// user may review is reviewed. Approvals are subject to the allowed refs; rejections,
+// like cancellations, are not.
+func (c *Client) ReviewPendingDeployments(ctx context.Context, runID int64, state string, environmentIDs []int64, comment string) (*DeploymentReview, error) {
+	if state != "approved" && state != "rejected" {
+		return nil, fmt.Errorf("invalid state %q: must be approved or rejected", state)
+	}
+	if state == "approved" {
+		if err := c.checkRunRefAllowed(ctx, runID); err != nil {
+			return nil, err
+		}
+	}
+
+	pending, err := c.ListPendingDeployments(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*PendingDeployment, len(pending))
+	var available []string
+	for _, p := range pending {
+		byID[p.EnvironmentID] = p
+		available = append(available, fmt.Sprintf("%s (%d)", p.Environment, p.EnvironmentID))
+	}
+
+	review := &DeploymentReview{RunID: runID, State: state, Environments: []string{}, Comment: comment}
+	if len(environmentIDs) == 0 {
+		for _, p := range pending {
+			if p.CanApprove {
+				environmentIDs = append(environmentIDs, p.EnvironmentID)
+			}
+		}
+		if len(environmentIDs) == 0 {
+			if len(pending) == 0 {
+				return nil, fmt.Errorf("run %d has no pending deployments: %w", runID, ErrNotFound)
+			}
+			return nil, fmt.Errorf("the token's user may not review any pending deployment of run %d (pending: %s)", runID, strings.Join(available, ", "))
+		}
+	}
+	for _, id := range environmentIDs {
+		p, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("environment %d is not pending for run %d (pending: %s): %w", id, runID, strings.Join(available, ", "), ErrNotFound)
+		}
+		review.Environments = append(review.Environments, p.Environment)
+	}
+
+	deployments, _, err := c.gh.Actions.PendingDeployments(ctx, c.owner, c.repo, runID, &github.PendingDeploymentsRequest{
+		EnvironmentIDs: environmentIDs,
+		State:          state,
+		Comment:        comment,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to review pending deployments for run %d: %w", runID, Classify(err))
+	}
+	for _, d := range deployments {
+		review.Deployments = append(review.Deployments, d.GetID())
+	}
+	return review, nil
+}
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPendingApproval(t *testing.T) {
+	var pending []*githubapi.PendingDeployment
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"environment": {"name": "staging"}, "wait_timer": 5, "current_user_can_approve": false, "reviewers": []},
+		{"environment": {"name": "production"}, "current_user_can_approve": false, "reviewers": [
+			{"type": "Team", "reviewer": {"slug": "team-sre"}},
+			{"type": "User", "reviewer": {"login": "octocat"}}
+		]},
+		{"environment": {"name": "production-eu"}, "current_user_can_approve": true, "reviewers": [
+			{"type": "Team", "reviewer": {"slug": "team-sre"}}
+		]}
+	]`), &pending))
+
+	approval := buildPendingApproval(pending)
+	require.NotNil(t, approval)
+	assert.Equal(t, []string{"production", "production-eu"}, approval.Environments)
+	assert.Equal(t, []string{"team-sre", "octocat"}, approval.Reviewers)
+	assert.False(t, approval.CanApprove)
+	assert.Equal(t, "waiting for approval by: team-sre, octocat (environment: production, production-eu)", approval.Message)
+
+	// A deployment only held by a wait timer needs no approval.
+	assert.Nil(t, buildPendingApproval(pending[:1]))
+	assert.Nil(t, buildPendingApproval(nil))
+}
+
+func TestReviewPendingDeployments(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	var request githubapi.PendingDeploymentsRequest
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/5/pending_deployments", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			_, _ = w.Write([]byte(`[{"id": 900, "environment": "production-eu"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"environment": {"id": 11, "name": "production"}, "current_user_can_approve": false, "reviewers": [
+				{"type": "Team", "reviewer": {"slug": "team-sre", "organization": {"login": "octo-org"}}}
+			]},
+			{"environment": {"id": 12, "name": "production-eu"}, "wait_timer": 10, "current_user_can_approve": true, "reviewers": [
+				{"type": "User", "reviewer": {"login": "octocat"}}
+			]}
+		]`))
+	})
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	pending, err := client.ListPendingDeployments(context.Background(), 5)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, []string{"octo-org/team-sre"}, pending[0].Reviewers)
+	assert.Equal(t, int64(10), pending[1].WaitTimerMinutes)
+	assert.True(t, pending[1].CanApprove)
+
+	// Without environment IDs, only the environments the user may review are reviewed.
+	review, err := client.ReviewPendingDeployments(context.Background(), 5, "approved", nil, "ship it")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{12}, request.EnvironmentIDs)
+	assert.Equal(t, "approved", request.State)
+	assert.Equal(t, "ship it", request.Comment)
+	assert.Equal(t, []string{"production-eu"}, review.Environments)
+	assert.Equal(t, []int64{900}, review.Deployments)
+
+	_, err = client.ReviewPendingDeployments(context.Background(), 5, "rejected", []int64{99}, "")
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, err.Error(), "production (11)")
+
+	_, err = client.ReviewPendingDeployments(context.Background(), 5, "maybe", nil, "")
+	assert.Error(t, err)
+}
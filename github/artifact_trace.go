@@ -0,0 +1,179 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ArtifactTrace is the workflow_run chain a run belongs to and the artifacts passed
+// between its runs.
+type ArtifactTrace struct {
+	RunID    int64               `json:"run_id"`
+	HeadSHA  string              `json:"head_sha"`
+	Runs     []*ArtifactTraceRun `json:"runs"` // Upstream runs before the runs they triggered
+	Links    []*ArtifactLink     `json:"links"`
+	Missing  []*ArtifactMatch    `json:"missing,omitempty"` // Downloads that match no artifact of the producing run
+	Warnings []string            `json:"warnings,omitempty"`
+}
+
+// ArtifactTraceRun is a run of an artifact trace and the artifacts it uploaded. A run
+// without artifacts has an empty list.
+type ArtifactTraceRun struct {
+	*TracedRun
+	Artifacts []*Artifact `json:"artifacts"`
+}
+
+// ArtifactDownload is a step of a workflow that downloads artifacts of another run.
+type ArtifactDownload struct {
+	Job     string `json:"job"`
+	Step    string `json:"step"`
+	Action  string `json:"action"`
+	Name    string `json:"name,omitempty"`    // name input; empty downloads every artifact
+	Pattern string `json:"pattern,omitempty"` // pattern input of actions/download-artifact
+	RunID   string `json:"run_id,omitempty"`  // run-id or run_id input, usually an expression
+}
+
+// ArtifactMatch is a download step of a consumer run and the run expected to produce it.
+type ArtifactMatch struct {
+	ProducerRunID int64             `json:"producer_run_id"`
+	ConsumerRunID int64             `json:"consumer_run_id"`
+	Download      *ArtifactDownload `json:"download"`
+}
+
+// ArtifactLink is an artifact of a producer run downloaded by a consumer run.
+type ArtifactLink struct {
+	Artifact   string `json:"artifact"`
+	ArtifactID int64  `json:"artifact_id"`
+	Expired    bool   `json:"expired,omitempty"`
+	ArtifactMatch
+}
+
+// TraceArtifacts follows the workflow_run triggers between the runs of a run's commit up
+// to the first run of the chain and down to every run it triggered, then matches the
+// artifact download steps of each workflow_run run's workflow file against the artifacts
+// of the run that triggered it. Downloads are found by reading the workflow files, so
+// steps that download artifacts with a script rather than an action are not traced.
+func (c *Client) TraceArtifacts(ctx context.Context, runID int64) (*ArtifactTrace, error) {
+	chain, err := c.workflowRunChain(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	trace := &ArtifactTrace{RunID: runID, HeadSHA: chain.HeadSHA, Runs: make([]*ArtifactTraceRun, 0, len(chain.Runs)), Links: []*ArtifactLink{}, Warnings: chain.Warnings}
+
+	artifactsByRun := make(map[int64][]*Artifact, len(chain.Runs))
+	for _, run := range chain.Runs {
+		artifacts, err := c.GetWorkflowRunArtifacts(ctx, run.RunID)
+		if err != nil {
+			trace.Warnings = append(trace.Warnings, err.Error())
+			artifacts = []*Artifact{}
+		}
+		artifactsByRun[run.RunID] = artifacts
+		trace.Runs = append(trace.Runs, &ArtifactTraceRun{TracedRun: run, Artifacts: artifacts})
+	}
+
+	for _, consumer := range chain.Runs {
+		info := chain.workflows[consumer.workflowID]
+		if info == nil || consumer.TriggeredBy == 0 {
+			continue
+		}
+		producer := chain.runs[consumer.TriggeredBy]
+		for _, download := range info.downloads {
+			match := ArtifactMatch{ProducerRunID: producer.RunID, ConsumerRunID: consumer.RunID, Download: download}
+			matched := false
+			for _, artifact := range artifactsByRun[producer.RunID] {
+				if download.matches(artifact.Name) {
+					matched = true
+					trace.Links = append(trace.Links, &ArtifactLink{
+						Artifact:      artifact.Name,
+						ArtifactID:    artifact.ID,
+						Expired:       artifact.Expired,
+						ArtifactMatch: match,
+					})
+				}
+			}
+			if !matched {
+				trace.Missing = append(trace.Missing, &match)
+			}
+		}
+	}
+	return trace, nil
+}
+
+// matches reports whether a download step fetches the artifact called name.
+func (d *ArtifactDownload) matches(name string) bool {
+	switch {
+	case d.Name != "":
+		return d.Name == name
+	case d.Pattern != "":
+		ok, _ := path.Match(d.Pattern, name)
+		return ok
+	}
+	return true
+}
+
+// parseArtifactTrace returns the workflows whose completion triggers a workflow file
+// (on.workflow_run.workflows) and its steps that download artifacts of another run.
+func parseArtifactTrace(data []byte) ([]string, []*ArtifactDownload, error) {
+	var doc struct {
+		On   yaml.Node `yaml:"on"`
+		Jobs yaml.Node `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+
+	var triggers []string
+	if _, workflowRun := yamlMappingValue(&doc.On, "workflow_run"); workflowRun != nil {
+		var trigger struct {
+			Workflows []string `yaml:"workflows"`
+		}
+		if err := workflowRun.Decode(&trigger); err != nil {
+			return nil, nil, fmt.Errorf("on.workflow_run: %w", err)
+		}
+		triggers = trigger.Workflows
+	}
+
+	var downloads []*ArtifactDownload
+	for i := 0; i+1 < len(doc.Jobs.Content); i += 2 {
+		job := doc.Jobs.Content[i].Value
+		var def struct {
+			Steps []struct {
+				Name string            `yaml:"name"`
+				ID   string            `yaml:"id"`
+				Uses string            `yaml:"uses"`
+				With map[string]string `yaml:"with"`
+			} `yaml:"steps"`
+		}
+		if err := doc.Jobs.Content[i+1].Decode(&def); err != nil {
+			return nil, nil, fmt.Errorf("jobs.%s: %w", job, err)
+		}
+		for n, step := range def.Steps {
+			action, _, _ := strings.Cut(step.Uses, "@")
+			download := &ArtifactDownload{Job: job, Step: step.Name, Action: step.Uses, Name: step.With["name"]}
+			switch strings.ToLower(action) {
+			case "actions/download-artifact":
+				// Without run-id, the action downloads artifacts of its own run.
+				if download.RunID = step.With["run-id"]; download.RunID == "" {
+					continue
+				}
+				download.Pattern = step.With["pattern"]
+			case "dawidd6/action-download-artifact":
+				download.RunID = step.With["run_id"]
+			default:
+				continue
+			}
+			if download.Step == "" {
+				download.Step = step.ID
+			}
+			if download.Step == "" {
+				download.Step = fmt.Sprintf("step %d", n+1)
+			}
+			downloads = append(downloads, download)
+		}
+	}
+	return triggers, downloads, nil
+}
@@ -0,0 +1,143 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deployWorkflow = `name: Deploy
+on:
+  workflow_run:
+    workflows: [Build]
+    types: [completed]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - name: Fetch binaries
+        uses: actions/download-artifact@v4
+        with:
+          pattern: bin-*
+          run-id: ${{ github.event.workflow_run.id }}
+          github-token: ${{ secrets.GITHUB_TOKEN }}
+      - uses: dawidd6/action-download-artifact@v6
+        with:
+          name: docs
+          run_id: ${{ github.event.workflow_run.id }}
+      - uses: actions/download-artifact@v4
+        with:
+          name: local
+`
+
+func TestParseArtifactTrace(t *testing.T) {
+	triggers, downloads, err := parseArtifactTrace([]byte(deployWorkflow))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Build"}, triggers)
+	require.Len(t, downloads, 2)
+	assert.Equal(t, &ArtifactDownload{
+		Job:     "deploy",
+		Step:    "Fetch binaries",
+		Action:  "actions/download-artifact@v4",
+		Pattern: "bin-*",
+		RunID:   "${{ github.event.workflow_run.id }}",
+	}, downloads[0])
+	assert.Equal(t, "step 2", downloads[1].Step)
+	assert.Equal(t, "docs", downloads[1].Name)
+}
+
+func TestTraceArtifacts(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	runs := map[int64]string{
+		1: `{"id": 1, "name": "Build", "event": "push", "status": "completed", "conclusion": "success", "workflow_id": 10, "head_sha": "abc",
+			"created_at": "2026-01-01T10:00:00Z", "updated_at": "2026-01-01T10:05:00Z"}`,
+		2: `{"id": 2, "name": "Deploy", "event": "workflow_run", "status": "completed", "conclusion": "failure", "workflow_id": 20, "head_sha": "abc",
+			"created_at": "2026-01-01T10:06:00Z", "updated_at": "2026-01-01T10:08:00Z"}`,
+		3: `{"id": 3, "name": "Lint", "event": "push", "status": "completed", "conclusion": "success", "workflow_id": 30, "head_sha": "abc",
+			"created_at": "2026-01-01T10:00:00Z", "updated_at": "2026-01-01T10:01:00Z"}`,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(runs[2]))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc", r.URL.Query().Get("head_sha"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 3, "workflow_runs": [` + runs[3] + `,` + runs[2] + `,` + runs[1] + `]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/20", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 20, "path": ".github/workflows/deploy.yml"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/contents/.github/workflows/deploy.yml", func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.Query().Get("ref"), "workflow_run workflows run from the default branch")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(deployWorkflow)),
+		})
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/1/artifacts", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 3, "artifacts": [
+			{"id": 101, "name": "bin-linux"},
+			{"id": 102, "name": "bin-darwin", "expired": true},
+			{"id": 103, "name": "coverage"}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/2/artifacts", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 0, "artifacts": []}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	trace, err := client.TraceArtifacts(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, trace.Warnings)
+
+	require.Len(t, trace.Runs, 2)
+	assert.Equal(t, int64(1), trace.Runs[0].RunID)
+	assert.Equal(t, int64(2), trace.Runs[1].RunID)
+	assert.Equal(t, int64(1), trace.Runs[1].TriggeredBy)
+	assert.Equal(t, ".github/workflows/deploy.yml", trace.Runs[1].WorkflowPath)
+	assert.Len(t, trace.Runs[0].Artifacts, 3)
+
+	require.Len(t, trace.Links, 2)
+	assert.Equal(t, "bin-linux", trace.Links[0].Artifact)
+	assert.Equal(t, int64(101), trace.Links[0].ArtifactID)
+	assert.Equal(t, int64(1), trace.Links[0].ProducerRunID)
+	assert.Equal(t, int64(2), trace.Links[0].ConsumerRunID)
+	assert.Equal(t, "Fetch binaries", trace.Links[0].Download.Step)
+	assert.True(t, trace.Links[1].Expired)
+
+	require.Len(t, trace.Missing, 1)
+	assert.Equal(t, "docs", trace.Missing[0].Download.Name)
+
+	// A run without artifacts shows an empty list rather than no field.
+	data, err := json.Marshal(trace.Runs[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"artifacts":[]`)
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Billing scopes accepted by GetActionsBilling.
+const (
+	BillingScopeOrganization = "org"
+	BillingScopeUser         = "user"
+)
+
+// ActionsBilling is the GitHub Actions minutes an account used in its current billing cycle.
+type ActionsBilling struct {
+	Account          string                `json:"account"`
+	Scope            string                `json:"scope"` // org or user
+	IncludedMinutes  float64               `json:"included_minutes"`
+	UsedMinutes      float64               `json:"used_minutes"`
+	PaidMinutes      float64               `json:"paid_minutes"`           // Minutes used beyond the included ones
+	RemainingMinutes float64               `json:"remaining_minutes"`      // Included minutes left, never below 0
+	PercentUsed      float64               `json:"percent_used,omitempty"` // Used share of the included minutes
+	Breakdown        []*BillingMinutesByOS `json:"breakdown"`              // Most minutes first
+	Note             string                `json:"note,omitempty"`
+}
+
+// BillingMinutesByOS is the minutes used on one runner OS or machine type.
+type BillingMinutesByOS struct {
+	OS      string  `json:"os"` // e.g. UBUNTU, WINDOWS, MACOS, or a larger runner type such as UBUNTU_16_CORE
+	Minutes int     `json:"minutes"`
+	Share   float64 `json:"share"` // Fraction of the used minutes
+}
+
+// GetActionsBilling reports the Actions minutes of an account (the client's owner when
+// account is empty) in its current billing cycle: included, used, and paid minutes, and the
+// minutes per runner OS. scope is org, user, or empty to try the organization endpoint
+// first and fall back to the user one. Reading billing needs an organization owner or
+// billing manager, or for a user the user's own token with the user scope.
+func (c *Client) GetActionsBilling(ctx context.Context, account, scope string) (*ActionsBilling, error) {
+	if account == "" {
+		account = c.owner
+	}
+	if account == "" {
+		return nil, fmt.Errorf("account is required when no repository owner is configured")
+	}
+
+	var billing *github.ActionBilling
+	var err error
+	switch scope {
+	case BillingScopeOrganization:
+		billing, _, err = c.gh.Billing.GetActionsBillingOrg(ctx, account)
+	case BillingScopeUser:
+		billing, _, err = c.gh.Billing.GetActionsBillingUser(ctx, account)
+	case "":
+		// Users have no organization billing, so the org endpoint answers 404 for them.
+		scope = BillingScopeOrganization
+		billing, _, err = c.gh.Billing.GetActionsBillingOrg(ctx, account)
+		if IsHTTPError(err, http.StatusNotFound) {
+			scope = BillingScopeUser
+			billing, _, err = c.gh.Billing.GetActionsBillingUser(ctx, account)
+		}
+	default:
+		return nil, fmt.Errorf("invalid scope %q: must be org or user", scope)
+	}
+	if err != nil {
+		if IsHTTPError(err, http.StatusGone) || IsHTTPError(err, http.StatusNotFound) {
+			return nil, fmt.Errorf("failed to get Actions billing of %s: %w (accounts on the enhanced billing platform report usage only in the billing settings, and reading billing needs an owner or billing manager)", account, Classify(err))
+		}
+		return nil, fmt.Errorf("failed to get Actions billing of %s: %w", account, Classify(err))
+	}
+
+	result := &ActionsBilling{
+		Account:         account,
+		Scope:           scope,
+		IncludedMinutes: billing.IncludedMinutes,
+		UsedMinutes:     billing.TotalMinutesUsed,
+		PaidMinutes:     billing.TotalPaidMinutesUsed,
+		Breakdown:       []*BillingMinutesByOS{},
+	}
+	result.RemainingMinutes = math.Max(0, billing.IncludedMinutes-billing.TotalMinutesUsed)
+	if billing.IncludedMinutes > 0 {
+		result.PercentUsed = math.Round(billing.TotalMinutesUsed/billing.IncludedMinutes*1000) / 10
+	}
+	total := 0
+	for _, minutes := range billing.MinutesUsedBreakdown {
+		total += minutes
+	}
+	for machine, minutes := range billing.MinutesUsedBreakdown {
+		if minutes == 0 {
+			continue
+		}
+		result.Breakdown = append(result.Breakdown, &BillingMinutesByOS{
+			OS:      machine,
+			Minutes: minutes,
+			Share:   math.Round(float64(minutes)/float64(total)*100) / 100,
+		})
+	}
+	sort.Slice(result.Breakdown, func(i, j int) bool {
+		if result.Breakdown[i].Minutes != result.Breakdown[j].Minutes {
+			return result.Breakdown[i].Minutes > result.Breakdown[j].Minutes
+		}
+		return result.Breakdown[i].OS < result.Breakdown[j].OS
+	})
+	if result.UsedMinutes > result.IncludedMinutes && result.IncludedMinutes > 0 {
+		result.Note = fmt.Sprintf("the included minutes are used up; %.0f minutes were billed", result.PaidMinutes)
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetActionsBilling(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/acme/settings/billing/actions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_minutes_used": 3300, "total_paid_minutes_used": 300, "included_minutes": 3000,
+			"minutes_used_breakdown": {"UBUNTU": 2400, "MACOS": 600, "WINDOWS": 300, "UBUNTU_16_CORE": 0}}`))
+	})
+	mux.HandleFunc("/orgs/octocat/settings/billing/actions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	})
+	mux.HandleFunc("/users/octocat/settings/billing/actions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_minutes_used": 500, "total_paid_minutes_used": 0, "included_minutes": 2000, "minutes_used_breakdown": {"UBUNTU": 500}}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: "acme", repo: "api", gh: ghc, perPageLimit: 50}
+
+	billing, err := client.GetActionsBilling(context.Background(), "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", billing.Account)
+	assert.Equal(t, BillingScopeOrganization, billing.Scope)
+	assert.Equal(t, 3300.0, billing.UsedMinutes)
+	assert.Equal(t, 0.0, billing.RemainingMinutes)
+	assert.Equal(t, 110.0, billing.PercentUsed)
+	assert.Equal(t, []*BillingMinutesByOS{
+		{OS: "UBUNTU", Minutes: 2400, Share: 0.73},
+		{OS: "MACOS", Minutes: 600, Share: 0.18},
+		{OS: "WINDOWS", Minutes: 300, Share: 0.09},
+	}, billing.Breakdown)
+	assert.Equal(t, "the included minutes are used up; 300 minutes were billed", billing.Note)
+
+	// A user account falls back to the user endpoint.
+	billing, err = client.GetActionsBilling(context.Background(), "octocat", "")
+	require.NoError(t, err)
+	assert.Equal(t, BillingScopeUser, billing.Scope)
+	assert.Equal(t, 1500.0, billing.RemainingMinutes)
+	assert.Equal(t, 25.0, billing.PercentUsed)
+	assert.Empty(t, billing.Note)
+
+	_, err = client.GetActionsBilling(context.Background(), "octocat", BillingScopeOrganization)
+	assert.ErrorContains(t, err, "enhanced billing platform")
+
+	_, err = client.GetActionsBilling(context.Background(), "", "team")
+	assert.ErrorContains(t, err, "invalid scope")
+}
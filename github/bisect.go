@@ -0,0 +1,192 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+const (
+	// maxBisectRuns bounds how far back a bisection walks a branch's runs.
+	maxBisectRuns = 500
+	// maxBisectFiles caps the changed files listed in a bisection's diff summary.
+	maxBisectFiles = 50
+)
+
+// DiffFile is a file changed in a commit range.
+type DiffFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"` // added, modified, removed, renamed, ...
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// FailureBisection locates the commit range in which a workflow started failing on a branch.
+type FailureBisection struct {
+	Workflow       string              `json:"workflow"`
+	Branch         string              `json:"branch"`
+	Latest         *WorkflowRun        `json:"latest"`               // The current failing run
+	FirstFailing   *WorkflowRun        `json:"first_failing"`        // Oldest run of the current failure streak
+	LastGreen      *WorkflowRun        `json:"last_green,omitempty"` // Newest successful run before the streak
+	FailingCommits int                 `json:"failing_commits"`      // Commits with runs in the streak
+	CommitsBetween int                 `json:"commits_between"`      // Commits after last_green up to first_failing
+	SuspectCommits []*ComparisonCommit `json:"suspect_commits"`      // Oldest first
+	ChangedFiles   int                 `json:"changed_files"`
+	Additions      int                 `json:"additions"`
+	Deletions      int                 `json:"deletions"`
+	Files          []*DiffFile         `json:"files,omitempty"` // Most changed first
+	Notes          []string            `json:"notes,omitempty"`
+	Summary        string              `json:"summary"`
+}
+
+// BisectFailure walks back through the completed runs of a workflow on branch (default: the
+// repository's default branch), newest first, to the first run of the current failure streak
+// and the last successful run before it. Each commit counts once, by its newest run, and
+// cancelled or skipped runs are ignored. The suspect range is the commits after the last
+// green commit up to the first failing one, returned with a summary of their diff.
+func (c *Client) BisectFailure(ctx context.Context, workflow, branch string) (*FailureBisection, error) {
+	workflowID, workflowName, err := c.ResolveWorkflowID(ctx, workflow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve workflow %q: %w", workflow, err)
+	}
+	if branch == "" {
+		repository, _, err := c.gh.Repositories.Get(ctx, c.owner, c.repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get default branch: %w", Classify(err))
+		}
+		branch = repository.GetDefaultBranch()
+	}
+
+	opts := &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		Status:      "completed",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	var runs []*github.WorkflowRun
+	for len(runs) < maxBisectRuns {
+		page, resp, err := c.gh.Actions.ListWorkflowRunsByID(ctx, c.owner, c.repo, workflowID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list runs of workflow %q: %w", workflowName, Classify(err))
+		}
+		runs = append(runs, page.WorkflowRuns...)
+		// Stop paging once the streak has ended.
+		if hasSuccessfulRun(page.WorkflowRuns) || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	bisection, err := bisectRuns(runs)
+	if err != nil {
+		return nil, fmt.Errorf("workflow %q on branch %s: %w", workflowName, branch, err)
+	}
+	bisection.Workflow, bisection.Branch = workflowName, branch
+
+	if bisection.LastGreen == nil {
+		bisection.Notes = append(bisection.Notes, fmt.Sprintf("no successful run found in the last %d runs; the failure may predate them", len(runs)))
+	} else {
+		cmp, _, err := c.gh.Repositories.CompareCommits(ctx, c.owner, c.repo, bisection.LastGreen.HeadSHA, bisection.FirstFailing.HeadSHA, &github.ListOptions{PerPage: maxComparisonCommits})
+		if err != nil {
+			bisection.Notes = append(bisection.Notes, fmt.Sprintf("could not compare %s...%s: %v", shortSHA(bisection.LastGreen.HeadSHA), shortSHA(bisection.FirstFailing.HeadSHA), Classify(err)))
+		} else {
+			addBisectDiff(bisection, cmp)
+		}
+	}
+
+	bisection.Summary = buildBisectionSummary(bisection)
+	return bisection, nil
+}
+
+// hasSuccessfulRun reports whether any of the runs succeeded.
+func hasSuccessfulRun(runs []*github.WorkflowRun) bool {
+	for _, r := range runs {
+		if r.GetConclusion() == "success" {
+			return true
+		}
+	}
+	return false
+}
+
+// bisectRuns finds the failure streak in runs listed newest first. It fails when the newest
+// conclusive commit is not failing.
+func bisectRuns(runs []*github.WorkflowRun) (*FailureBisection, error) {
+	b := &FailureBisection{SuspectCommits: []*ComparisonCommit{}}
+	seen := make(map[string]bool)
+	for _, r := range runs {
+		sha := r.GetHeadSHA()
+		if seen[sha] || r.GetConclusion() == "cancelled" || isInconclusiveConclusion(r.GetConclusion()) {
+			continue
+		}
+		seen[sha] = true
+
+		switch {
+		case isFailureConclusion(r.GetConclusion()):
+			if b.Latest == nil {
+				b.Latest = workflowRunFromGitHub(r)
+			}
+			b.FirstFailing = workflowRunFromGitHub(r)
+			b.FailingCommits++
+		case b.Latest == nil:
+			return nil, fmt.Errorf("latest run %d concluded %s; nothing to bisect", r.GetID(), r.GetConclusion())
+		default:
+			if r.GetConclusion() == "success" {
+				b.LastGreen = workflowRunFromGitHub(r)
+				return b, nil
+			}
+		}
+	}
+	if b.Latest == nil {
+		return nil, fmt.Errorf("no failing runs found")
+	}
+	return b, nil
+}
+
+// addBisectDiff fills in the suspect commits and diff summary of the last green to first
+// failing comparison.
+func addBisectDiff(b *FailureBisection, cmp *github.CommitsComparison) {
+	b.SuspectCommits, b.CommitsBetween = comparisonCommits(cmp)
+	if b.CommitsBetween > len(b.SuspectCommits) {
+		b.Notes = append(b.Notes, fmt.Sprintf("only the first %d of %d suspect commits are listed", len(b.SuspectCommits), b.CommitsBetween))
+	}
+	b.ChangedFiles = len(cmp.Files)
+	for _, f := range cmp.Files {
+		b.Additions += f.GetAdditions()
+		b.Deletions += f.GetDeletions()
+		b.Files = append(b.Files, &DiffFile{
+			Filename:  f.GetFilename(),
+			Status:    f.GetStatus(),
+			Additions: f.GetAdditions(),
+			Deletions: f.GetDeletions(),
+		})
+	}
+	sort.SliceStable(b.Files, func(i, j int) bool {
+		return b.Files[i].Additions+b.Files[i].Deletions > b.Files[j].Additions+b.Files[j].Deletions
+	})
+	if len(b.Files) > maxBisectFiles {
+		b.Files = b.Files[:maxBisectFiles]
+	}
+}
+
+// buildBisectionSummary describes the suspect range in one sentence or two.
+func buildBisectionSummary(b *FailureBisection) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Failing since run %d (%s)", b.FirstFailing.ID, shortSHA(b.FirstFailing.HeadSHA))
+	if b.FailingCommits > 1 {
+		fmt.Fprintf(&sb, ", %d commits with failing runs", b.FailingCommits)
+	}
+	if b.LastGreen == nil {
+		sb.WriteString("; no earlier green run found.")
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "; last green run %d (%s).", b.LastGreen.ID, shortSHA(b.LastGreen.HeadSHA))
+	switch {
+	case b.CommitsBetween == 1:
+		fmt.Fprintf(&sb, " Suspect commit %s changes %d file(s).", shortSHA(b.FirstFailing.HeadSHA), b.ChangedFiles)
+	case b.CommitsBetween > 1:
+		fmt.Fprintf(&sb, " %d suspect commits change %d file(s) (+%d/-%d).", b.CommitsBetween, b.ChangedFiles, b.Additions, b.Deletions)
+	}
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBisectFailure(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"default_branch": "main"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "workflows": [{"id": 50, "name": "CI", "path": ".github/workflows/ci.yml"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "main", r.URL.Query().Get("branch"))
+		assert.Equal(t, "completed", r.URL.Query().Get("status"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 6, "workflow_runs": [
+			{"id": 106, "name": "CI", "status": "completed", "conclusion": "failure", "head_sha": "sha6", "run_number": 6},
+			{"id": 105, "name": "CI", "status": "completed", "conclusion": "cancelled", "head_sha": "sha5", "run_number": 5},
+			{"id": 104, "name": "CI", "status": "completed", "conclusion": "failure", "head_sha": "sha4", "run_number": 4},
+			{"id": 103, "name": "CI", "status": "completed", "conclusion": "failure", "head_sha": "sha3", "run_number": 3},
+			{"id": 102, "name": "CI", "status": "completed", "conclusion": "success", "head_sha": "sha3", "run_number": 2},
+			{"id": 101, "name": "CI", "status": "completed", "conclusion": "success", "head_sha": "sha1", "run_number": 1}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/compare/sha1...sha3", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_commits": 2,
+			"commits": [
+				{"sha": "sha2", "commit": {"message": "Bump parser\n\nDetails", "author": {"name": "Dev"}}},
+				{"sha": "sha3", "commit": {"message": "Refactor config"}, "author": {"login": "octocat"}}
+			],
+			"files": [
+				{"filename": "go.mod", "status": "modified", "additions": 1, "deletions": 1},
+				{"filename": "config/config.go", "status": "modified", "additions": 40, "deletions": 12}
+			]}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	bisection, err := client.BisectFailure(context.Background(), "CI", "")
+	require.NoError(t, err)
+	assert.Equal(t, "main", bisection.Branch)
+	assert.Equal(t, int64(106), bisection.Latest.ID)
+	// sha3's newest run failed, so its earlier success does not count as green.
+	assert.Equal(t, int64(103), bisection.FirstFailing.ID)
+	assert.Equal(t, int64(101), bisection.LastGreen.ID)
+	assert.Equal(t, 3, bisection.FailingCommits)
+	assert.Equal(t, 2, bisection.CommitsBetween)
+	assert.Equal(t, []*ComparisonCommit{
+		{SHA: "sha2", Message: "Bump parser", Author: "Dev"},
+		{SHA: "sha3", Message: "Refactor config", Author: "octocat"},
+	}, bisection.SuspectCommits)
+	require.Len(t, bisection.Files, 2)
+	assert.Equal(t, "config/config.go", bisection.Files[0].Filename)
+	assert.Equal(t, 41, bisection.Additions)
+	assert.Equal(t, "Failing since run 103 (sha3), 3 commits with failing runs; last green run 101 (sha1). 2 suspect commits change 2 file(s) (+41/-13).", bisection.Summary)
+}
+
+func TestBisectRuns_LatestPassing(t *testing.T) {
+	_, err := bisectRuns([]*githubapi.WorkflowRun{
+		{ID: githubapi.Ptr(int64(2)), Conclusion: githubapi.Ptr("success"), HeadSHA: githubapi.Ptr("b")},
+		{ID: githubapi.Ptr(int64(1)), Conclusion: githubapi.Ptr("failure"), HeadSHA: githubapi.Ptr("a")},
+	})
+	assert.EqualError(t, err, "latest run 2 concluded success; nothing to bisect")
+}
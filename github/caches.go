@@ -0,0 +1,173 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// maxCacheUsageCaches bounds the caches read to break cache usage down by ref.
+const maxCacheUsageCaches = 1000
+
+// Cache is an entry in the repository's Actions cache.
+type Cache struct {
+	ID             int64  `json:"id"`
+	Key            string `json:"key"`
+	Ref            string `json:"ref"`
+	Version        string `json:"version,omitempty"`
+	SizeInBytes    int64  `json:"size_in_bytes"`
+	CreatedAt      string `json:"created_at,omitempty"`
+	LastAccessedAt string `json:"last_accessed_at,omitempty"`
+}
+
+// CacheListOptions filters and orders ListCaches.
+type CacheListOptions struct {
+	Ref       string // Branch name, refs/heads/<branch>, or refs/pull/<number>/merge
+	Key       string // Key prefix
+	Sort      string // created_at, last_accessed_at (default), or size_in_bytes
+	Direction string // asc or desc (default)
+	Limit     int    // Maximum number of caches to return; 0 for all
+}
+
+// CacheRefUsage is the cache storage used by one ref.
+type CacheRefUsage struct {
+	Ref         string `json:"ref"`
+	Caches      int    `json:"caches"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+}
+
+// CacheUsage is the repository's Actions cache storage, broken down by ref.
+type CacheUsage struct {
+	ActiveCaches      int              `json:"active_caches"`
+	ActiveSizeInBytes int64            `json:"active_size_in_bytes"`
+	ByRef             []*CacheRefUsage `json:"by_ref"`              // Largest first
+	Truncated         bool             `json:"truncated,omitempty"` // ByRef covers only the first caches
+}
+
+// ListCaches lists the repository's Actions caches, most recently used first unless
+// opts says otherwise, and returns them with the total number of matching caches.
+func (c *Client) ListCaches(ctx context.Context, opts *CacheListOptions) ([]*Cache, int, error) {
+	if opts == nil {
+		opts = &CacheListOptions{}
+	}
+	listOpts := &github.ActionsCacheListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	if opts.Ref != "" {
+		listOpts.Ref = github.Ptr(opts.Ref)
+	}
+	if opts.Key != "" {
+		listOpts.Key = github.Ptr(opts.Key)
+	}
+	if opts.Sort != "" {
+		listOpts.Sort = github.Ptr(opts.Sort)
+	}
+	if opts.Direction != "" {
+		listOpts.Direction = github.Ptr(opts.Direction)
+	}
+
+	caches := []*Cache{}
+	total := 0
+	for {
+		page, resp, err := c.gh.Actions.ListCaches(ctx, c.owner, c.repo, listOpts)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list caches: %w", Classify(err))
+		}
+		total = page.TotalCount
+		for _, cache := range page.ActionsCaches {
+			caches = append(caches, cacheFromGitHub(cache))
+			if opts.Limit > 0 && len(caches) >= opts.Limit {
+				return caches, total, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+	return caches, total, nil
+}
+
+// GetCacheUsage returns how many caches the repository holds and how much storage they
+// use, with the storage per ref so branches that crowd out others stand out.
+func (c *Client) GetCacheUsage(ctx context.Context) (*CacheUsage, error) {
+	usage, _, err := c.gh.Actions.GetCacheUsageForRepo(ctx, c.owner, c.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache usage: %w", Classify(err))
+	}
+	result := &CacheUsage{
+		ActiveCaches:      usage.ActiveCachesCount,
+		ActiveSizeInBytes: usage.ActiveCachesSizeInBytes,
+		ByRef:             []*CacheRefUsage{},
+	}
+
+	caches, total, err := c.ListCaches(ctx, &CacheListOptions{Sort: "size_in_bytes", Limit: maxCacheUsageCaches})
+	if err != nil {
+		return nil, err
+	}
+	result.Truncated = total > len(caches)
+	byRef := make(map[string]*CacheRefUsage)
+	for _, cache := range caches {
+		ref := byRef[cache.Ref]
+		if ref == nil {
+			ref = &CacheRefUsage{Ref: cache.Ref}
+			byRef[cache.Ref] = ref
+			result.ByRef = append(result.ByRef, ref)
+		}
+		ref.Caches++
+		ref.SizeInBytes += cache.SizeInBytes
+	}
+	sort.SliceStable(result.ByRef, func(i, j int) bool {
+		return result.ByRef[i].SizeInBytes > result.ByRef[j].SizeInBytes
+	})
+	return result, nil
+}
+
+// DeleteCache deletes one cache by ID.
+func (c *Client) DeleteCache(ctx context.Context, cacheID int64) error {
+	if _, err := c.gh.Actions.DeleteCachesByID(ctx, c.owner, c.repo, cacheID); err != nil {
+		return fmt.Errorf("failed to delete cache %d: %w", cacheID, Classify(err))
+	}
+	return nil
+}
+
+// DeleteCachesByKey deletes the caches with exactly key, optionally only those of ref, and
+// returns them.
+func (c *Client) DeleteCachesByKey(ctx context.Context, key, ref string) ([]*Cache, error) {
+	// The list filter matches key prefixes; the delete endpoint matches the key exactly.
+	listed, _, err := c.ListCaches(ctx, &CacheListOptions{Key: key, Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	deleted := []*Cache{}
+	for _, cache := range listed {
+		if cache.Key == key {
+			deleted = append(deleted, cache)
+		}
+	}
+	if len(deleted) == 0 {
+		return nil, fmt.Errorf("no cache with key %q: %w", key, ErrNotFound)
+	}
+
+	var refOpt *string
+	if ref != "" {
+		refOpt = github.Ptr(ref)
+	}
+	if _, err := c.gh.Actions.DeleteCachesByKey(ctx, c.owner, c.repo, key, refOpt); err != nil {
+		return nil, fmt.Errorf("failed to delete caches with key %q: %w", key, Classify(err))
+	}
+	return deleted, nil
+}
+
+// cacheFromGitHub converts an Actions cache entry.
+func cacheFromGitHub(cache *github.ActionsCache) *Cache {
+	return &Cache{
+		ID:             cache.GetID(),
+		Key:            cache.GetKey(),
+		Ref:            cache.GetRef(),
+		Version:        cache.GetVersion(),
+		SizeInBytes:    cache.GetSizeInBytes(),
+		CreatedAt:      formatTime(cache.CreatedAt),
+		LastAccessedAt: formatTime(cache.LastAccessedAt),
+	}
+}
@@ -0,0 +1,93 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaches(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	var deletedKey, deletedRef string
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/caches", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deletedKey, deletedRef = r.URL.Query().Get("key"), r.URL.Query().Get("ref")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"total_count": 1, "actions_caches": []}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("key") == "go-linux" {
+			_, _ = w.Write([]byte(`{"total_count": 2, "actions_caches": [
+				{"id": 1, "key": "go-linux", "ref": "refs/heads/main", "size_in_bytes": 500},
+				{"id": 4, "key": "go-linux-extra", "ref": "refs/heads/main", "size_in_bytes": 50}
+			]}`))
+			return
+		}
+		if r.URL.Query().Get("key") == "missing" {
+			_, _ = w.Write([]byte(`{"total_count": 0, "actions_caches": []}`))
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`{"total_count": 3, "actions_caches": [
+				{"id": 3, "key": "npm-abc", "ref": "refs/pull/7/merge", "size_in_bytes": 300}
+			]}`))
+			return
+		}
+		assert.Equal(t, "size_in_bytes", r.URL.Query().Get("sort"))
+		w.Header().Set("Link", `<`+ts.URL+`/repos/`+owner+`/`+repo+`/actions/caches?page=2>; rel="next"`)
+		_, _ = w.Write([]byte(`{"total_count": 3, "actions_caches": [
+			{"id": 1, "key": "go-linux", "ref": "refs/heads/main", "size_in_bytes": 500, "last_accessed_at": "2026-01-02T00:00:00Z"},
+			{"id": 2, "key": "go-macos", "ref": "refs/heads/main", "size_in_bytes": 400}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/cache/usage", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"full_name": "test-owner/test-repo", "active_caches_size_in_bytes": 1200, "active_caches_count": 3}`))
+	})
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	caches, total, err := client.ListCaches(context.Background(), &CacheListOptions{Sort: "size_in_bytes", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, caches, 2)
+	assert.Equal(t, &Cache{ID: 1, Key: "go-linux", Ref: "refs/heads/main", SizeInBytes: 500, LastAccessedAt: "2026-01-02T00:00:00Z"}, caches[0])
+
+	usage, err := client.GetCacheUsage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, usage.ActiveCaches)
+	assert.Equal(t, int64(1200), usage.ActiveSizeInBytes)
+	assert.False(t, usage.Truncated)
+	assert.Equal(t, []*CacheRefUsage{
+		{Ref: "refs/heads/main", Caches: 2, SizeInBytes: 900},
+		{Ref: "refs/pull/7/merge", Caches: 1, SizeInBytes: 300},
+	}, usage.ByRef)
+
+	deleted, err := client.DeleteCachesByKey(context.Background(), "go-linux", "refs/heads/main")
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, int64(1), deleted[0].ID)
+	assert.Equal(t, "go-linux", deletedKey)
+	assert.Equal(t, "refs/heads/main", deletedRef)
+
+	_, err = client.DeleteCachesByKey(context.Background(), "missing", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
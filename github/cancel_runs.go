@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// CancelRunsOptions selects the runs CancelWorkflowRuns cancels.
+type CancelRunsOptions struct {
+	WorkflowID *int64        // Optional: only runs of this workflow
+	Branch     string        // Optional: only runs on this branch
+	Statuses   []string      // Statuses to cancel; queued and in_progress when empty
+	OlderThan  time.Duration // Optional: only runs created at least this long ago
+	DryRun     bool          // List the matching runs without cancelling them
+}
+
+// CancelledRun is a run matched by CancelWorkflowRuns.
+type CancelledRun struct {
+	RunID     int64  `json:"run_id"`
+	Name      string `json:"name"`
+	Branch    string `json:"branch,omitempty"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	URL       string `json:"url,omitempty"`
+	Error     string `json:"error,omitempty"` // Why the cancellation failed
+}
+
+// CancelRunsResult is the outcome of CancelWorkflowRuns.
+type CancelRunsResult struct {
+	DryRun    bool            `json:"dry_run,omitempty"`
+	Matched   int             `json:"matched"`
+	Cancelled int             `json:"cancelled"`
+	Failed    int             `json:"failed"`
+	Runs      []*CancelledRun `json:"runs"`
+}
+
+// CancelWorkflowRuns cancels every queued or in-progress run matching opts. A failure to
+// cancel one run, for example because it finished in the meantime, is recorded on that
+// run and does not stop the others.
+func (c *Client) CancelWorkflowRuns(ctx context.Context, opts *CancelRunsOptions) (*CancelRunsResult, error) {
+	statuses := opts.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{"queued", "in_progress"}
+	}
+	var cutoff time.Time
+	if opts.OlderThan > 0 {
+		cutoff = time.Now().Add(-opts.OlderThan)
+	}
+
+	result := &CancelRunsResult{DryRun: opts.DryRun, Runs: []*CancelledRun{}}
+	seen := make(map[int64]bool)
+	for _, status := range statuses {
+		runs, err := c.listRunsWithStatus(ctx, opts.WorkflowID, opts.Branch, status)
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range runs {
+			// A run can move from queued to in_progress between the two listings.
+			if seen[run.GetID()] {
+				continue
+			}
+			seen[run.GetID()] = true
+			if !cutoff.IsZero() && run.GetCreatedAt().Time.After(cutoff) {
+				continue
+			}
+			result.Runs = append(result.Runs, &CancelledRun{
+				RunID:     run.GetID(),
+				Name:      run.GetName(),
+				Branch:    run.GetHeadBranch(),
+				Status:    run.GetStatus(),
+				CreatedAt: formatTime(run.CreatedAt),
+				URL:       run.GetHTMLURL(),
+			})
+		}
+	}
+	result.Matched = len(result.Runs)
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, run := range result.Runs {
+		if err := c.CancelWorkflowRun(ctx, run.RunID); err != nil {
+			run.Error = err.Error()
+			result.Failed++
+			continue
+		}
+		result.Cancelled++
+	}
+	return result, nil
+}
+
+// listRunsWithStatus lists every run with status, optionally of one workflow and branch.
+func (c *Client) listRunsWithStatus(ctx context.Context, workflowID *int64, branch, status string) ([]*github.WorkflowRun, error) {
+	opts := &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		Status:      status,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	var all []*github.WorkflowRun
+	for {
+		var runs *github.WorkflowRuns
+		var resp *github.Response
+		var err error
+		if workflowID != nil {
+			runs, resp, err = c.gh.Actions.ListWorkflowRunsByID(ctx, c.owner, c.repo, *workflowID, opts)
+		} else {
+			runs, resp, err = c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s workflow runs: %w", status, Classify(err))
+		}
+		all = append(all, runs.WorkflowRuns...)
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
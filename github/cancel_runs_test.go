@@ -0,0 +1,87 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelWorkflowRuns(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+	old := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	recent := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+
+	var cancelled []int64
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/7/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "main", r.URL.Query().Get("branch"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("status") {
+		case "queued":
+			if r.URL.Query().Get("page") == "2" {
+				fmt.Fprintf(w, `{"total_count": 2, "workflow_runs": [{"id": 2, "name": "CI", "status": "queued", "created_at": %q}]}`, recent)
+				return
+			}
+			w.Header().Set("Link", `<`+ts.URL+`/repos/`+owner+`/`+repo+`/actions/workflows/7/runs?status=queued&branch=main&page=2>; rel="next"`)
+			fmt.Fprintf(w, `{"total_count": 2, "workflow_runs": [{"id": 1, "name": "CI", "status": "queued", "created_at": %q}]}`, old)
+		case "in_progress":
+			// Run 1 started between the two listings.
+			fmt.Fprintf(w, `{"total_count": 2, "workflow_runs": [
+				{"id": 1, "name": "CI", "status": "in_progress", "created_at": %q},
+				{"id": 3, "name": "CI", "status": "in_progress", "created_at": %q}
+			]}`, old, old)
+		default:
+			t.Errorf("unexpected status filter %q", r.URL.Query().Get("status"))
+		}
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		var id int64
+		_, _ = fmt.Sscanf(r.URL.Path, "/repos/"+owner+"/"+repo+"/actions/runs/%d/cancel", &id)
+		if id == 3 {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message": "Cannot cancel a workflow run that is completed."}`))
+			return
+		}
+		cancelled = append(cancelled, id)
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	workflowID := int64(7)
+	opts := &CancelRunsOptions{WorkflowID: &workflowID, Branch: "main", DryRun: true}
+	result, err := client.CancelWorkflowRuns(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Matched)
+	assert.Zero(t, result.Cancelled)
+	assert.Empty(t, cancelled)
+
+	opts.DryRun = false
+	opts.OlderThan = time.Hour
+	result, err = client.CancelWorkflowRuns(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Matched, "run 2 is too recent")
+	assert.Equal(t, 1, result.Cancelled)
+	assert.Equal(t, 1, result.Failed)
+	assert.Empty(t, result.Runs[0].Error)
+	assert.Contains(t, result.Runs[1].Error, "completed")
+	assert.Equal(t, []int64{1}, cancelled)
+}
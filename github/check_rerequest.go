@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// CheckRerequest is the outcome of re-requesting one check run or check suite.
+type CheckRerequest struct {
+	CheckRunID   int64  `json:"check_run_id,omitempty"`
+	CheckSuiteID int64  `json:"check_suite_id"`
+	Name         string `json:"name,omitempty"`
+	App          string `json:"app,omitempty"`
+	HeadBranch   string `json:"head_branch,omitempty"`
+	Status       string `json:"status"` // requested, failed, or skipped
+	Message      string `json:"message,omitempty"`
+}
+
+// RerequestCheckRun asks the GitHub App that created a check run to run it again. Only
+// the check run's own app can act on the request; Actions jobs are rerun with ManageRun.
+func (c *Client) RerequestCheckRun(ctx context.Context, checkRunID int64) (*CheckRerequest, error) {
+	run, _, err := c.gh.Checks.GetCheckRun(ctx, c.owner, c.repo, checkRunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check run %d: %w", checkRunID, Classify(err))
+	}
+	result := checkRerequestFromRun(run)
+	// A check run only carries its suite's ID; the branch is on the suite.
+	suite, _, err := c.gh.Checks.GetCheckSuite(ctx, c.owner, c.repo, result.CheckSuiteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check suite %d: %w", result.CheckSuiteID, Classify(err))
+	}
+	result.HeadBranch = suite.GetHeadBranch()
+	if err := c.checkRefAllowed(result.HeadBranch); err != nil {
+		return nil, fmt.Errorf("cannot re-request check run %d: %w", checkRunID, err)
+	}
+	if _, err := c.gh.Checks.ReRequestCheckRun(ctx, c.owner, c.repo, checkRunID); err != nil {
+		result.Status, result.Message = "failed", Classify(err).Error()
+		return result, nil
+	}
+	result.Status = "requested"
+	return result, nil
+}
+
+// RerequestCheckSuite asks the GitHub App that owns a check suite to run all of its checks
+// again, which also restarts suites that are stuck queued.
+func (c *Client) RerequestCheckSuite(ctx context.Context, checkSuiteID int64) (*CheckRerequest, error) {
+	suite, _, err := c.gh.Checks.GetCheckSuite(ctx, c.owner, c.repo, checkSuiteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check suite %d: %w", checkSuiteID, Classify(err))
+	}
+	result := &CheckRerequest{
+		CheckSuiteID: checkSuiteID,
+		App:          suite.GetApp().GetName(),
+		HeadBranch:   suite.GetHeadBranch(),
+	}
+	if err := c.checkRefAllowed(result.HeadBranch); err != nil {
+		return nil, fmt.Errorf("cannot re-request check suite %d: %w", checkSuiteID, err)
+	}
+	if _, err := c.gh.Checks.ReRequestCheckSuite(ctx, c.owner, c.repo, checkSuiteID); err != nil {
+		result.Status, result.Message = "failed", Classify(err).Error()
+		return result, nil
+	}
+	result.Status = "requested"
+	return result, nil
+}
+
+// RerequestFailedChecks re-requests the latest check runs of ref that failed, optionally
+// only those named checkName. Check runs created by GitHub Actions are skipped with a
+// message, since their app does not handle re-requests; rerun their workflow runs instead.
+func (c *Client) RerequestFailedChecks(ctx context.Context, ref, checkName string) ([]*CheckRerequest, error) {
+	opts := &github.ListCheckRunsOptions{Filter: github.Ptr("latest"), ListOptions: github.ListOptions{PerPage: 100}}
+	if checkName != "" {
+		opts.CheckName = github.Ptr(checkName)
+	}
+	var runs []*github.CheckRun
+	for {
+		page, resp, err := c.gh.Checks.ListCheckRunsForRef(ctx, c.owner, c.repo, ref, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list check runs for %s: %w", ref, Classify(err))
+		}
+		runs = append(runs, page.CheckRuns...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	results := []*CheckRerequest{}
+	for _, run := range runs {
+		if run.GetStatus() != "completed" || !isFailureConclusion(run.GetConclusion()) {
+			continue
+		}
+		if run.GetApp().GetSlug() == "github-actions" {
+			result := checkRerequestFromRun(run)
+			result.Status = "skipped"
+			result.Message = "created by GitHub Actions; rerun its workflow run with manage_run instead"
+			results = append(results, result)
+			continue
+		}
+		result, err := c.RerequestCheckRun(ctx, run.GetID())
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// checkRerequestFromRun describes a check run about to be re-requested.
+func checkRerequestFromRun(run *github.CheckRun) *CheckRerequest {
+	return &CheckRerequest{
+		CheckRunID:   run.GetID(),
+		CheckSuiteID: run.GetCheckSuite().GetID(),
+		Name:         run.GetName(),
+		App:          run.GetApp().GetName(),
+	}
+}
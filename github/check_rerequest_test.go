@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRerequestFailedChecks(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	var rerequested []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/main/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "latest", r.URL.Query().Get("filter"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 4, "check_runs": [
+			{"id": 1, "name": "build", "status": "completed", "conclusion": "failure", "app": {"slug": "github-actions", "name": "GitHub Actions"}, "check_suite": {"id": 10}},
+			{"id": 2, "name": "coverage", "status": "completed", "conclusion": "failure", "app": {"slug": "codecov", "name": "Codecov"}, "check_suite": {"id": 20}},
+			{"id": 3, "name": "license", "status": "completed", "conclusion": "success", "app": {"slug": "fossa", "name": "FOSSA"}, "check_suite": {"id": 30}},
+			{"id": 4, "name": "preview", "status": "completed", "conclusion": "timed_out", "app": {"slug": "vercel", "name": "Vercel"}, "check_suite": {"id": 40}}
+		]}`))
+	})
+	for _, id := range []string{"2", "4"} {
+		id := id
+		mux.HandleFunc("/repos/"+owner+"/"+repo+"/check-runs/"+id, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id": ` + id + `, "name": "check-` + id + `", "app": {"name": "App"}, "check_suite": {"id": ` + id + `0}}`))
+		})
+		mux.HandleFunc("/repos/"+owner+"/"+repo+"/check-suites/"+id+"0", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id": ` + id + `0, "head_branch": "main"}`))
+		})
+		mux.HandleFunc("/repos/"+owner+"/"+repo+"/check-runs/"+id+"/rerequest", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			rerequested = append(rerequested, id)
+			if id == "4" {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		})
+	}
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	results, err := client.RerequestFailedChecks(context.Background(), "main", "")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "skipped", results[0].Status)
+	assert.Equal(t, "build", results[0].Name)
+	assert.Equal(t, &CheckRerequest{CheckRunID: 2, CheckSuiteID: 20, Name: "check-2", App: "App", HeadBranch: "main", Status: "requested"}, results[1])
+	assert.Equal(t, "failed", results[2].Status)
+	assert.Contains(t, results[2].Message, "Resource not accessible by integration")
+	assert.Equal(t, []string{"2", "4"}, rerequested)
+
+	client.allowedRefs = []string{"release/*"}
+	_, err = client.RerequestCheckRun(context.Background(), 2)
+	assert.ErrorIs(t, err, ErrRefNotAllowed)
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,7 +19,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/go-git/go-git/v5"
 	"github.com/google/go-github/v69/github"
 	"github.com/sirupsen/logrus"
 )
@@ -39,24 +39,31 @@ func (e *HTTPError) Error() string {
 	return e.Message
 }
 
-// IsHTTPError checks if an error is an HTTPError with a specific status code
+// Is lets errors.Is match an HTTPError against the sentinel for its status code.
+func (e *HTTPError) Is(target error) bool {
+	if e.StatusCode == http.StatusGone {
+		return target == ErrLogsExpired
+	}
+	kind := statusKind(e.StatusCode)
+	return kind != nil && kind == target
+}
+
+// IsHTTPError checks if an error carries a specific HTTP status code, either as an
+// HTTPError, a go-github ErrorResponse, or a classified error for that status.
 func IsHTTPError(err error, statusCode int) bool {
 	if err == nil {
 		return false
 	}
-	if httpErr, ok := err.(*HTTPError); ok {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
 		return httpErr.StatusCode == statusCode
 	}
-	// Check for go-github error format: "unexpected status code: 404 Not Found"
-	// This regex specifically matches the status code pattern at the end of the message
-	// preceded by "status code: " to avoid matching repo names like "401k"
-	re := regexp.MustCompile(`status code:\s*(\d+)`)
-	matches := re.FindStringSubmatch(err.Error())
-	if len(matches) > 1 {
-		code, _ := strconv.Atoi(matches[1])
-		return code == statusCode
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode == statusCode
 	}
-	return false
+	kind := statusKind(statusCode)
+	return kind != nil && errors.Is(err, kind)
 }
 
 // newHTTPErrorFromGitHub creates an HTTPError from a github.Response
@@ -87,13 +94,18 @@ var (
 )
 
 // presignedHTTPClient is used for fetching pre-signed storage URLs (no auth headers)
-var presignedHTTPClient = &http.Client{Timeout: 30 * time.Second}
+var presignedHTTPClient = &http.Client{Timeout: 30 * time.Second, Transport: apiTransport}
 
 type Client struct {
+	host         string // empty for github.com
 	owner        string
 	repo         string
 	gh           *github.Client
 	perPageLimit int
+	allowedRefs  []string
+	defaultRefs  map[string]string // Workflow dispatch refs by workflow file name, path, name, or ID
+	location     *time.Location    // Time zone of human-readable output; nil means UTC
+	logCacheDir  string            // Where logs of completed jobs are kept; empty disables it
 }
 
 func NewClient(token, owner, repo string) *Client {
@@ -118,12 +130,49 @@ type ClientOptions struct {
 	Owner        string
 	Repo         string
 	PerPageLimit int
+	// Host is the GitHub host the repository lives on, e.g. a GitHub
+	// Enterprise Server hostname. When APIBaseURL is empty, the API URLs
+	// are derived from it. Empty means github.com.
+	Host string
 	// APIBaseURL overrides the default https://api.github.com/ base URL.
 	// Must end with a trailing slash (go-github requirement). Example for
 	// gh-proxy: "http://gh-proxy:8080/api/".
 	APIBaseURL string
 	// UploadURL overrides the upload URL. Defaults to APIBaseURL when empty.
 	UploadURL string
+	// AllowedRefs restricts workflow dispatches and reruns to matching
+	// branches or tags (glob patterns such as "release/*"). Empty allows all.
+	AllowedRefs []string
+	// DefaultRefs are the refs workflows are dispatched on when no ref is
+	// given, keyed by workflow file name, path, name, or ID. Workflows
+	// without one run on the repository's default branch.
+	DefaultRefs map[string]string
+	// LogCacheDir is where logs of completed jobs are kept, so each is
+	// downloaded once. Empty disables the disk cache.
+	LogCacheDir string
+	// UserAgent identifies the client in API requests. Empty means
+	// UserAgent("", "").
+	UserAgent string
+	// Location is the time zone human-readable output, such as markdown
+	// reports, shows times in. JSON fields stay in UTC. Nil means UTC.
+	Location *time.Location
+	// TokenSource supplies the token and refreshes it when GitHub answers
+	// 401. Clients sharing a source see a refreshed token immediately.
+	// When nil, Token is used as is.
+	TokenSource *TokenSource
+}
+
+// UserAgent returns the User-Agent of API requests: the program name, its version
+// ("dev" when empty), and the project URL, followed by suffix when it is not empty.
+func UserAgent(version, suffix string) string {
+	if version == "" {
+		version = "dev"
+	}
+	ua := "gh-actions-mcp/" + version + " (+https://github.com/denysvitali/gh-actions-mcp)"
+	if suffix = strings.TrimSpace(suffix); suffix != "" {
+		ua += " " + suffix
+	}
+	return ua
 }
 
 // NewClientWithOptions creates a new GitHub client using the provided options.
@@ -131,8 +180,22 @@ func NewClientWithOptions(opts ClientOptions) (*Client, error) {
 	if opts.PerPageLimit <= 0 {
 		opts.PerPageLimit = 50
 	}
-	hc := &http.Client{Timeout: 30 * time.Second}
-	gh := github.NewClient(hc).WithAuthToken(opts.Token)
+	source := opts.TokenSource
+	if source == nil {
+		source = NewTokenSource(opts.Token, nil)
+	}
+	hc := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &tokenTransport{source: source, base: &etagTransport{base: &metricsTransport{base: apiTransport}, cache: responseCache}},
+	}
+	gh := github.NewClient(hc)
+	if opts.UserAgent == "" {
+		opts.UserAgent = UserAgent("", "")
+	}
+	gh.UserAgent = opts.UserAgent
+	if opts.APIBaseURL == "" {
+		opts.APIBaseURL, opts.UploadURL = APIURLsForHost(opts.Host)
+	}
 	if opts.APIBaseURL != "" {
 		// Set BaseURL directly rather than via WithEnterpriseURLs, which
 		// would auto-append "api/v3/" and break non-Enterprise proxies
@@ -158,14 +221,32 @@ func NewClientWithOptions(opts ClientOptions) (*Client, error) {
 		}
 		gh.UploadURL = upload
 	}
+	host := ""
+	if !IsDefaultHost(opts.Host) {
+		host = NormalizeHost(opts.Host)
+	}
 	return &Client{
+		host:         host,
 		owner:        opts.Owner,
 		repo:         opts.Repo,
 		gh:           gh,
 		perPageLimit: opts.PerPageLimit,
+		allowedRefs:  opts.AllowedRefs,
+		defaultRefs:  opts.DefaultRefs,
+		location:     opts.Location,
+		logCacheDir:  opts.LogCacheDir,
 	}, nil
 }
 
+// FullName returns the repository as owner/repo, prefixed with the host when it is not
+// github.com.
+func (c *Client) FullName() string {
+	if c.host != "" {
+		return c.host + "/" + c.owner + "/" + c.repo
+	}
+	return c.owner + "/" + c.repo
+}
+
 type WorkflowRun struct {
 	ID              int64   `json:"id"`
 	Name            string  `json:"name"`
@@ -173,22 +254,41 @@ type WorkflowRun struct {
 	Conclusion      string  `json:"conclusion"`
 	Branch          string  `json:"branch"`
 	HeadSHA         string  `json:"head_sha,omitempty"`
+	CommitMessage   string  `json:"commit_message,omitempty"`
+	CommitAuthor    string  `json:"commit_author,omitempty"`
+	Committer       string  `json:"committer,omitempty"`
+	PullRequests    []int   `json:"pull_requests,omitempty"` // Numbers of the PRs the head commit belongs to (same-repository PRs only)
 	Event           string  `json:"event"`
 	Actor           string  `json:"actor"`
 	CreatedAt       string  `json:"created_at"`
 	UpdatedAt       string  `json:"updated_at"`
 	StartedAt       string  `json:"started_at,omitempty"`
+	Age             string  `json:"age,omitempty"` // Time since created_at, e.g. "3h5m"
 	URL             string  `json:"url"`
 	RunNumber       int     `json:"run_number"`
 	WorkflowID      int64   `json:"workflow_id"`
-	DurationSeconds float64 `json:"duration,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+
+	// Set for runs that completed without passing or failing, e.g. because every job's
+	// if: condition was false, so they are not mistaken for successes.
+	Skipped bool `json:"skipped,omitempty"`
+	Neutral bool `json:"neutral,omitempty"`
+	Stale   bool `json:"stale,omitempty"`
+
+	// Set for runs of workflows defined outside the repository.
+	WorkflowSource *WorkflowSource `json:"workflow_source,omitempty"`
 }
 
 type Workflow struct {
-	ID    int64  `json:"id"`
-	Name  string `json:"name"`
-	Path  string `json:"path"`
-	State string `json:"state"`
+	ID     int64           `json:"id"`
+	Name   string          `json:"name"`
+	Path   string          `json:"path"`
+	State  string          `json:"state"`
+	Source *WorkflowSource `json:"source,omitempty"` // Set for workflows defined outside the repository
+
+	// Disabled is set for workflows that do not run, with the reason in DisabledReason.
+	Disabled       bool   `json:"disabled,omitempty"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
 }
 
 // WorkflowRunMinimal is a compact workflow run representation for reduced token usage
@@ -198,17 +298,22 @@ type WorkflowRunMinimal struct {
 	Status          string  `json:"status"`
 	Conclusion      string  `json:"conclusion,omitempty"`
 	CreatedAt       string  `json:"created_at"`
-	DurationSeconds float64 `json:"duration,omitempty"`
+	Age             string  `json:"age,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
 }
 
 // WorkflowRunCompact extends Minimal with additional fields
 type WorkflowRunCompact struct {
 	WorkflowRunMinimal
-	Branch string `json:"branch,omitempty"`
-	SHA    string `json:"sha,omitempty"`
-	Event  string `json:"event,omitempty"`
-	Actor  string `json:"actor,omitempty"`
-	URL    string `json:"url,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+	SHA          string `json:"sha,omitempty"`
+	Commit       string `json:"commit,omitempty"` // First line of the head commit message
+	CommitAuthor string `json:"commit_author,omitempty"`
+	Event        string `json:"event,omitempty"`
+	Actor        string `json:"actor,omitempty"`
+	URL          string `json:"url,omitempty"`
+
+	WorkflowSource *WorkflowSource `json:"workflow_source,omitempty"`
 }
 
 // WorkflowRunFull is the complete workflow run representation
@@ -226,9 +331,15 @@ type WorkflowRunFull struct {
 	RunNumber       int     `json:"run_number"`
 	WorkflowID      int64   `json:"workflow_id"`
 	HeadSHA         string  `json:"head_sha"`
+	CommitMessage   string  `json:"commit_message,omitempty"`
+	CommitAuthor    string  `json:"commit_author,omitempty"`
+	Committer       string  `json:"committer,omitempty"`
 	StartedAt       string  `json:"started_at,omitempty"`
 	CompletedAt     string  `json:"completed_at,omitempty"`
-	DurationSeconds float64 `json:"duration,omitempty"`
+	Age             string  `json:"age,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+
+	WorkflowSource *WorkflowSource `json:"workflow_source,omitempty"`
 }
 
 // Step represents a single step within a workflow job
@@ -239,7 +350,7 @@ type Step struct {
 	Conclusion      string  `json:"conclusion,omitempty"`
 	StartedAt       string  `json:"started_at,omitempty"`
 	CompletedAt     string  `json:"completed_at,omitempty"`
-	DurationSeconds float64 `json:"duration,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
 }
 
 // Job represents a workflow run job
@@ -329,6 +440,7 @@ type Artifact struct {
 	SizeInBytes int64  `json:"size_in_bytes"`
 	CreatedAt   string `json:"created_at"`
 	ExpiresAt   string `json:"expires_at,omitempty"`
+	Expired     bool   `json:"expired,omitempty"`
 	ArchiveURL  string `json:"archive_url,omitempty"`
 }
 
@@ -387,14 +499,25 @@ type CombinedCheckStatus struct {
 
 // WaitRunResult is the result of waiting for a workflow run
 type WaitRunResult struct {
-	Status          string  `json:"status"`               // "completed", "timed_out"
-	Conclusion      string  `json:"conclusion,omitempty"` // "success", "failure", etc.
-	DurationSeconds float64 `json:"duration"`
-	RunURL          string  `json:"run_url"`
-	StartedAt       string  `json:"started_at,omitempty"`
-	CompletedAt     string  `json:"completed_at,omitempty"`
-	TimeoutReached  bool    `json:"timeout_reached"`
-	PollCount       int     `json:"poll_count"`
+	Status          string           `json:"status"`               // "completed", "timed_out", "waiting_for_approval"
+	Conclusion      string           `json:"conclusion,omitempty"` // "success", "failure", etc.
+	DurationSeconds float64          `json:"duration_seconds"`
+	RunURL          string           `json:"run_url"`
+	StartedAt       string           `json:"started_at,omitempty"`
+	CompletedAt     string           `json:"completed_at,omitempty"`
+	TimeoutReached  bool             `json:"timeout_reached"`
+	PollCount       int              `json:"poll_count"`
+	Approval        *PendingApproval `json:"approval,omitempty"` // Set while the run waits for an environment approval
+}
+
+// WaitRunOptions controls WaitForRunWithOptions.
+type WaitRunOptions struct {
+	TimeoutMinutes int
+	// StopOnApproval returns as soon as the run is blocked on an environment approval
+	// instead of waiting for a reviewer.
+	StopOnApproval bool
+	// OnApproval, if set, is called each time the run starts waiting for an approval.
+	OnApproval func(*PendingApproval)
 }
 
 // WaitCommitChecksResult is the result of waiting for commit checks
@@ -433,6 +556,7 @@ type ListRunsOptions struct {
 	CreatedAfter string // Optional: ISO 8601 date string
 	Event        string // Optional: push, pull_request, etc.
 	Actor        string // Optional: GitHub username
+	Runner       string // Optional: runner name; keeps runs with at least one job on this runner
 }
 
 // GetCheckRunsOptions contains parameters for getting check runs
@@ -452,16 +576,49 @@ func workflowRunFromGitHub(run *github.WorkflowRun) *WorkflowRun {
 		Conclusion:      run.GetConclusion(),
 		Branch:          run.GetHeadBranch(),
 		HeadSHA:         run.GetHeadSHA(),
+		CommitMessage:   strings.TrimSpace(run.GetHeadCommit().GetMessage()),
+		CommitAuthor:    commitPerson(run.GetHeadCommit().GetAuthor()),
+		Committer:       commitPerson(run.GetHeadCommit().GetCommitter()),
+		PullRequests:    pullRequestNumbers(run.PullRequests),
 		Event:           run.GetEvent(),
 		Actor:           run.GetActor().GetLogin(),
-		CreatedAt:       run.GetCreatedAt().String(),
-		UpdatedAt:       updatedAt.String(),
+		CreatedAt:       formatTime(run.CreatedAt),
+		UpdatedAt:       formatTimeValue(updatedAt),
 		StartedAt:       formatTime(run.RunStartedAt),
+		Age:             formatAge(run.CreatedAt),
 		URL:             run.GetHTMLURL(),
 		RunNumber:       run.GetRunNumber(),
 		WorkflowID:      run.GetWorkflowID(),
 		DurationSeconds: durationSeconds(run.RunStartedAt, &updatedAt),
+		Skipped:         run.GetConclusion() == "skipped",
+		Neutral:         run.GetConclusion() == "neutral",
+		Stale:           run.GetConclusion() == "stale",
+		WorkflowSource:  workflowSource(run.GetRepository().GetFullName(), run.GetPath(), run.GetWorkflowURL()),
+	}
+}
+
+// commitPerson returns the name of a commit author or committer, or the email when the
+// name is missing.
+func commitPerson(a *github.CommitAuthor) string {
+	if name := a.GetName(); name != "" {
+		return name
+	}
+	return a.GetEmail()
+}
+
+// pullRequestNumbers returns the numbers of prs, or nil when there are none.
+func pullRequestNumbers(prs []*github.PullRequest) []int {
+	var numbers []int
+	for _, pr := range prs {
+		numbers = append(numbers, pr.GetNumber())
 	}
+	return numbers
+}
+
+// CommitTitle returns the first line of a commit message.
+func CommitTitle(message string) string {
+	title, _, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(title)
 }
 
 // GetCurrentBranch attempts to detect the current git branch from the working directory.
@@ -472,9 +629,9 @@ func GetCurrentBranch() (string, error) {
 		return "", fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	repo, err := git.PlainOpen(wd)
+	repo, err := openRepository(wd, true)
 	if err != nil {
-		return "", fmt.Errorf("not in a git repository: %w", err)
+		return "", err
 	}
 
 	head, err := repo.Head()
@@ -506,9 +663,9 @@ func GetLastCommit() (*CommitInfo, error) {
 		return nil, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	repo, err := git.PlainOpen(wd)
+	repo, err := openRepository(wd, true)
 	if err != nil {
-		return nil, fmt.Errorf("not in a git repository: %w", err)
+		return nil, err
 	}
 
 	head, err := repo.Head()
@@ -535,6 +692,9 @@ type ActionsStatus struct {
 	RecentRuns     []*WorkflowRun `json:"recent_runs"`
 	SuccessfulRuns int            `json:"successful_runs"`
 	FailedRuns     int            `json:"failed_runs"`
+	SkippedRuns    int            `json:"skipped_runs"`
+	NeutralRuns    int            `json:"neutral_runs"`
+	StaleRuns      int            `json:"stale_runs"`
 	InProgressRuns int            `json:"in_progress_runs"`
 	QueuedRuns     int            `json:"queued_runs"`
 	PendingRuns    int            `json:"pending_runs"`
@@ -563,6 +723,7 @@ func getCachedRegex(pattern string) (*regexp.Regexp, error) {
 	re, ok := regexCache[pattern]
 	regexCacheMutex.RUnlock()
 
+	apiMetrics.recordCacheLookup("regex", ok)
 	if ok {
 		return re, nil
 	}
@@ -576,11 +737,15 @@ func getCachedRegex(pattern string) (*regexp.Regexp, error) {
 		return re, nil
 	}
 
-	compiled, err := regexp.Compile(pattern)
+	compiled, err := compileFilterRegex(pattern)
 	if err != nil {
 		return nil, err
 	}
 
+	// Patterns come from callers, so keep the cache from growing without bound.
+	if len(regexCache) >= maxCachedRegexes {
+		regexCache = make(map[string]*regexp.Regexp)
+	}
 	regexCache[pattern] = compiled
 	return compiled, nil
 }
@@ -623,7 +788,7 @@ func filterLogLines(lines []logLine, opts *LogFilterOptions) ([]logLine, error)
 			return nil, fmt.Errorf("invalid regex pattern %q: %w", opts.FilterRegex, err)
 		}
 		matcher = func(s string) bool {
-			return re.MatchString(s)
+			return re.MatchString(matchWindow(s))
 		}
 		matcherErr = err
 	} else {
@@ -640,7 +805,11 @@ func filterLogLines(lines []logLine, opts *LogFilterOptions) ([]logLine, error)
 
 	// First pass: find all matching lines (excluding headers)
 	matchedIndices := make(map[int]bool)
+	start := time.Now()
 	for i, line := range lines {
+		if i%regexTimeCheckInterval == 0 && time.Since(start) > regexFilterTimeout {
+			return nil, fmt.Errorf("%w after %s (%d of %d lines scanned); use a simpler pattern or narrow the log with job_id or section", ErrRegexTimeout, regexFilterTimeout, i, len(lines))
+		}
 		if !line.isHeader && matcher(line.content) {
 			matchedIndices[i] = true
 		}
@@ -743,6 +912,12 @@ func (c *Client) GetActionsStatus(ctx context.Context, limit int) (*ActionsStatu
 			status.SuccessfulRuns++
 		case "failure", "cancelled", "timed_out", "action_required":
 			status.FailedRuns++
+		case "skipped":
+			status.SkippedRuns++
+		case "neutral":
+			status.NeutralRuns++
+		case "stale":
+			status.StaleRuns++
 		}
 
 		switch wr.Status {
@@ -801,10 +976,14 @@ func (c *Client) GetWorkflows(ctx context.Context) ([]*Workflow, error) {
 	result := make([]*Workflow, len(workflows.Workflows))
 	for i, w := range workflows.Workflows {
 		result[i] = &Workflow{
-			ID:    w.GetID(),
-			Name:  w.GetName(),
-			Path:  w.GetPath(),
-			State: w.GetState(),
+			ID:     w.GetID(),
+			Name:   w.GetName(),
+			Path:   w.GetPath(),
+			State:  w.GetState(),
+			Source: workflowSource(c.owner+"/"+c.repo, w.GetPath(), w.GetURL()),
+
+			Disabled:       isWorkflowDisabled(w.GetState()),
+			DisabledReason: workflowDisabledReason(w.GetState()),
 		}
 	}
 
@@ -815,27 +994,30 @@ func (c *Client) TriggerWorkflow(ctx context.Context, workflowID string, ref str
 	// Use the shared helper to resolve workflow ID
 	id, _, err := c.ResolveWorkflowID(ctx, workflowID)
 	if err != nil {
-		return fmt.Errorf("failed to trigger workflow %s: %w", workflowID, err)
+		return fmt.Errorf("failed to trigger workflow %s: %w", workflowID, Classify(err))
 	}
 
 	_, err = c.gh.Actions.CreateWorkflowDispatchEventByID(ctx, c.owner, c.repo, id, github.CreateWorkflowDispatchEventRequest{
 		Ref: ref,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to trigger workflow %s: %w", workflowID, err)
+		return fmt.Errorf("failed to trigger workflow %s: %w", workflowID, Classify(err))
 	}
 	return nil
 }
 
 func (c *Client) CancelWorkflowRun(ctx context.Context, runID int64) error {
 	_, err := c.gh.Actions.CancelWorkflowRunByID(ctx, c.owner, c.repo, runID)
-	if err != nil {
+	if err != nil && !isAccepted(err) {
 		return fmt.Errorf("failed to cancel workflow run %d: %w", runID, err)
 	}
 	return nil
 }
 
 func (c *Client) RerunWorkflowRun(ctx context.Context, runID int64) error {
+	if err := c.checkRunRefAllowed(ctx, runID); err != nil {
+		return err
+	}
 	_, err := c.gh.Actions.RerunWorkflowByID(ctx, c.owner, c.repo, runID)
 	if err != nil {
 		return fmt.Errorf("failed to rerun workflow run %d: %w", runID, err)
@@ -843,6 +1025,25 @@ func (c *Client) RerunWorkflowRun(ctx context.Context, runID int64) error {
 	return nil
 }
 
+// RerunJob re-runs a single job and the jobs that depend on it. The job's run must have
+// completed.
+func (c *Client) RerunJob(ctx context.Context, jobID int64) error {
+	if len(c.allowedRefs) > 0 {
+		job, _, err := c.gh.Actions.GetWorkflowJobByID(ctx, c.owner, c.repo, jobID)
+		if err != nil {
+			return fmt.Errorf("failed to get job %d: %w", jobID, Classify(err))
+		}
+		if err := c.checkRunRefAllowed(ctx, job.GetRunID()); err != nil {
+			return err
+		}
+	}
+	_, err := c.gh.Actions.RerunJobByID(ctx, c.owner, c.repo, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to rerun job %d: %w", jobID, Classify(err))
+	}
+	return nil
+}
+
 // logFile represents a log file's name and content
 type logFile struct {
 	name string
@@ -995,6 +1196,12 @@ func formatLogFiles(logFiles []logFile, head, tail, offset int, noHeaders bool,
 		logStr = linesToString(filteredLines)
 	}
 
+	return sliceLogLines(logStr, head, tail, offset), nil
+}
+
+// sliceLogLines skips the first offset lines of logStr, then keeps the last tail lines
+// or, without tail, the first head lines. The result ends with a newline unless empty.
+func sliceLogLines(logStr string, head, tail, offset int) string {
 	lines := strings.Split(logStr, "\n")
 	if offset > 0 {
 		if offset >= len(lines) {
@@ -1016,28 +1223,14 @@ func formatLogFiles(logFiles []logFile, head, tail, offset int, noHeaders bool,
 	if logStr != "" {
 		logStr += "\n"
 	}
-
-	return logStr, nil
+	return logStr
 }
 
 // GetWorkflowLogFiles returns a list of log files available in the workflow run archive
 func (c *Client) GetWorkflowLogFiles(ctx context.Context, runID int64) ([]*LogFileInfo, error) {
-	// Get the log archive URL
-	url, resp, err := c.gh.Actions.GetWorkflowRunLogs(ctx, c.owner, c.repo, runID, maxRedirects)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get workflow log URL for run %d: %w", runID, err)
-	}
-
-	if resp != nil && resp.StatusCode != 0 {
-		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusFound {
-			return nil, newHTTPErrorFromGitHub(resp, "failed to get workflow logs")
-		}
-	}
-
-	// Fetch ZIP archive (use unauthenticated client for pre-signed storage URLs)
-	logFiles, _, err := readZipArchive(url.String(), presignedHTTPClient)
+	logFiles, err := c.runLogFiles(ctx, runID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read log archive for run %d: %w", runID, err)
+		return nil, err
 	}
 
 	// Convert to LogFileInfo
@@ -1054,22 +1247,9 @@ func (c *Client) GetWorkflowLogFiles(ctx context.Context, runID int64) ([]*LogFi
 
 // GetWorkflowLogsWithPattern retrieves logs for a workflow run with optional file pattern filtering
 func (c *Client) GetWorkflowLogsWithPattern(ctx context.Context, runID int64, head, tail, offset int, noHeaders bool, filePattern string, filterOpts *LogFilterOptions) (string, error) {
-	// Get the log archive URL
-	url, resp, err := c.gh.Actions.GetWorkflowRunLogs(ctx, c.owner, c.repo, runID, maxRedirects)
+	logFiles, err := c.runLogFiles(ctx, runID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get workflow log URL for run %d: %w", runID, err)
-	}
-
-	if resp != nil && resp.StatusCode != 0 {
-		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusFound {
-			return "", newHTTPErrorFromGitHub(resp, "failed to get workflow logs")
-		}
-	}
-
-	// Read ZIP archive (use unauthenticated client for pre-signed storage URLs)
-	logFiles, _, err := readZipArchive(url.String(), presignedHTTPClient)
-	if err != nil {
-		return "", fmt.Errorf("failed to read log archive for run %d: %w", runID, err)
+		return "", err
 	}
 
 	// Apply file pattern filter if specified
@@ -1153,6 +1333,7 @@ func (c *Client) WaitForWorkflowRun(ctx context.Context, runID int64, pollInterv
 
 		// Check if completed
 		if run.Status == "completed" {
+			result.Elapsed = time.Since(startTime)
 			return result, nil
 		}
 
@@ -1163,6 +1344,7 @@ func (c *Client) WaitForWorkflowRun(ctx context.Context, runID int64, pollInterv
 		select {
 		case <-ctx.Done():
 			timer.Stop()
+			result.Elapsed = time.Since(startTime)
 			return result, ctx.Err()
 		case <-timer.C:
 		}
@@ -1174,55 +1356,15 @@ func (c *Client) GetRepoInfo() (string, string) {
 }
 
 // InferRepoFromOrigin attempts to extract owner/repo from a git remote URL.
-// It also reverses git url.<base>.insteadOf rewrites (for example gh-proxy)
-// before parsing.
+//
+// Deprecated: use ParseGitURL, or ParseRepoURL to accept other hosts.
 func InferRepoFromOrigin(remoteURL string) (owner, repo string, err error) {
-	// Undo git insteadOf rewrites such as gh-proxy so that a proxied remote
-	// URL is translated back to its original github.com form.
-	if reversed, revErr := ReverseInsteadOf(remoteURL); revErr == nil {
-		remoteURL = reversed
-	}
-
-	// Handle bare owner/repo format (e.g., "owner/repo")
-	if !strings.Contains(remoteURL, "://") && !strings.Contains(remoteURL, "@") {
-		path := strings.TrimSuffix(remoteURL, ".git")
-		repoParts := strings.Split(path, "/")
-		if len(repoParts) == 2 {
-			return repoParts[0], repoParts[1], nil
-		}
-	}
-
-	// Handle SSH format: git@github.com:owner/repo.git
-	// Also handles malformed URLs like git@github.com:/owner/repo.git (extra slash)
-	if strings.Contains(remoteURL, "git@") {
-		parts := strings.Split(remoteURL, ":")
-		if len(parts) > 1 {
-			path := strings.TrimSuffix(parts[1], ".git")
-			path = strings.TrimPrefix(path, "/") // Handle extra leading slash
-			repoParts := strings.Split(path, "/")
-			if len(repoParts) == 2 {
-				return repoParts[0], repoParts[1], nil
-			}
-		}
-	}
-
-	// Handle HTTPS format: https://github.com/owner/repo.git
-	if strings.HasPrefix(remoteURL, "https://") || strings.HasPrefix(remoteURL, "http://") {
-		path := strings.TrimPrefix(remoteURL, "https://")
-		path = strings.TrimPrefix(path, "http://")
-		path = strings.TrimPrefix(path, "github.com/")
-		path = strings.TrimSuffix(path, ".git")
-		repoParts := strings.Split(path, "/")
-		if len(repoParts) == 2 {
-			return repoParts[0], repoParts[1], nil
-		}
-	}
-
-	return "", "", fmt.Errorf("could not parse owner/repo from URL: %s", remoteURL)
+	return ParseGitURL(remoteURL)
 }
 
-// ResolveWorkflowID resolves a workflow identifier (ID or name) to a numeric ID and name.
-// Returns the workflow ID, name, and an error if the workflow is not found.
+// ResolveWorkflowID resolves a workflow identifier (ID, path, or name) to a numeric ID and name.
+// Returns the workflow ID, name, and an error if the workflow is not found. A name shared by
+// several workflows returns an *AmbiguousWorkflowError listing them instead of picking one.
 func (c *Client) ResolveWorkflowID(ctx context.Context, workflowID string) (int64, string, error) {
 	// Try to parse as ID first
 	if id, err := ParseWorkflowID(workflowID); err == nil {
@@ -1246,13 +1388,28 @@ func (c *Client) ResolveWorkflowID(ctx context.Context, workflowID string) (int6
 		return 0, "", fmt.Errorf("failed to list workflows: %w", err)
 	}
 
+	// A path is unique; a display name may be shared by several workflows.
+	var matches []*github.Workflow
 	for _, w := range workflows.Workflows {
-		if w.GetName() == workflowID || w.GetPath() == workflowID {
+		if w.GetPath() == workflowID {
 			return w.GetID(), w.GetName(), nil
 		}
+		if w.GetName() == workflowID {
+			matches = append(matches, w)
+		}
 	}
 
-	return 0, "", fmt.Errorf("workflow %s not found", workflowID)
+	switch len(matches) {
+	case 0:
+		return 0, "", fmt.Errorf("workflow %s not found", workflowID)
+	case 1:
+		return matches[0].GetID(), matches[0].GetName(), nil
+	}
+	ambiguous := &AmbiguousWorkflowError{Name: workflowID}
+	for _, w := range matches {
+		ambiguous.Candidates = append(ambiguous.Candidates, WorkflowCandidate{ID: w.GetID(), Name: w.GetName(), Path: w.GetPath(), State: w.GetState()})
+	}
+	return 0, "", ambiguous
 }
 
 // ParseWorkflowID parses a workflow ID string into an int64
@@ -1314,12 +1471,36 @@ func (c *Client) ListRepositoryWorkflowRunsWithOptions(ctx context.Context, opts
 		if opts.Conclusion != "" && run.GetConclusion() != opts.Conclusion {
 			continue
 		}
+		if opts.Runner != "" {
+			matched, err := c.runUsedRunner(ctx, run.GetID(), opts.Runner)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+		}
 		result = append(result, workflowRunFromGitHub(run))
 	}
 
 	return result, nil
 }
 
+// runUsedRunner reports whether any job of the run was assigned to the named runner.
+// Runner filtering has no server-side equivalent, so this costs one jobs request per run.
+func (c *Client) runUsedRunner(ctx context.Context, runID int64, runner string) (bool, error) {
+	jobs, err := c.GetWorkflowJobs(ctx, runID, "all", 0)
+	if err != nil {
+		return false, err
+	}
+	for _, job := range jobs {
+		if strings.EqualFold(job.RunnerName, runner) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // GetWorkflowJobs retrieves jobs for a workflow run
 func (c *Client) GetWorkflowJobs(ctx context.Context, runID int64, filter string, attemptNumber int) ([]*Job, error) {
 	opts := &github.ListWorkflowJobsOptions{
@@ -1330,53 +1511,66 @@ func (c *Client) GetWorkflowJobs(ctx context.Context, runID int64, filter string
 		opts.Filter = filter
 	}
 
-	jobs, _, err := c.gh.Actions.ListWorkflowJobs(ctx, c.owner, c.repo, runID, opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list jobs for run %d: %w", runID, err)
+	var allJobs []*github.WorkflowJob
+	for {
+		jobs, resp, err := c.gh.Actions.ListWorkflowJobs(ctx, c.owner, c.repo, runID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jobs for run %d: %w", runID, err)
+		}
+		allJobs = append(allJobs, jobs.Jobs...)
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
 
-	result := make([]*Job, 0, len(jobs.Jobs))
-	for _, job := range jobs.Jobs {
+	result := make([]*Job, 0, len(allJobs))
+	for _, job := range allJobs {
 		// Filter by attempt number if specified
 		if attemptNumber > 0 && job.GetRunAttempt() != int64(attemptNumber) {
 			continue
 		}
 
-		var labels []string
-		if job.Labels != nil {
-			labels = job.Labels
-		}
+		result = append(result, jobFromGitHub(job))
+	}
 
-		steps := make([]*Step, 0, len(job.Steps))
-		for _, s := range job.Steps {
-			steps = append(steps, &Step{
-				Name:            s.GetName(),
-				Number:          s.GetNumber(),
-				Status:          s.GetStatus(),
-				Conclusion:      s.GetConclusion(),
-				StartedAt:       formatTime(s.StartedAt),
-				CompletedAt:     formatTime(s.CompletedAt),
-				DurationSeconds: durationSeconds(s.StartedAt, s.CompletedAt),
-			})
-		}
+	return result, nil
+}
 
-		result = append(result, &Job{
-			ID:              job.GetID(),
-			Name:            job.GetName(),
-			Status:          job.GetStatus(),
-			Conclusion:      job.GetConclusion(),
-			StartedAt:       formatTime(job.StartedAt),
-			CompletedAt:     formatTime(job.CompletedAt),
-			DurationSeconds: durationSeconds(job.StartedAt, job.CompletedAt),
-			RunnerName:      job.GetRunnerName(),
-			RunnerGroup:     job.GetRunnerGroupName(),
-			Labels:          labels,
-			WorkflowRunID:   job.GetRunID(),
-			Steps:           steps,
+// jobFromGitHub converts a workflow job and its steps.
+func jobFromGitHub(job *github.WorkflowJob) *Job {
+	var labels []string
+	if job.Labels != nil {
+		labels = job.Labels
+	}
+
+	steps := make([]*Step, 0, len(job.Steps))
+	for _, s := range job.Steps {
+		steps = append(steps, &Step{
+			Name:            s.GetName(),
+			Number:          s.GetNumber(),
+			Status:          s.GetStatus(),
+			Conclusion:      s.GetConclusion(),
+			StartedAt:       formatTime(s.StartedAt),
+			CompletedAt:     formatTime(s.CompletedAt),
+			DurationSeconds: durationSeconds(s.StartedAt, s.CompletedAt),
 		})
 	}
 
-	return result, nil
+	return &Job{
+		ID:              job.GetID(),
+		Name:            job.GetName(),
+		Status:          job.GetStatus(),
+		Conclusion:      job.GetConclusion(),
+		StartedAt:       formatTime(job.StartedAt),
+		CompletedAt:     formatTime(job.CompletedAt),
+		DurationSeconds: durationSeconds(job.StartedAt, job.CompletedAt),
+		RunnerName:      job.GetRunnerName(),
+		RunnerGroup:     job.GetRunnerGroupName(),
+		Labels:          labels,
+		WorkflowRunID:   job.GetRunID(),
+		Steps:           steps,
+	}
 }
 
 // AnalyzeTiming compares workflow, job, or step durations across recent runs.
@@ -1872,16 +2066,27 @@ func limitTimingBreakdown(items []*TimingBreakdownItem, limit int) []*TimingBrea
 
 // GetWorkflowJobLogs retrieves logs for a specific job
 func (c *Client) GetWorkflowJobLogs(ctx context.Context, jobID int64, head, tail, offset int, noHeaders bool, filterOpts *LogFilterOptions) (string, error) {
-	// Get the log archive
+	logFiles, err := c.jobLogFiles(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	return formatLogFiles(logFiles, head, tail, offset, noHeaders, filterOpts)
+}
+
+// jobLogFiles downloads the log of a job.
+func (c *Client) jobLogFiles(ctx context.Context, jobID int64) ([]logFile, error) {
+	if files, ok := c.cachedJobLog(ctx, jobID); ok {
+		return files, nil
+	}
 	url, resp, err := c.gh.Actions.GetWorkflowJobLogs(ctx, c.owner, c.repo, jobID, maxRedirects)
 	if err != nil {
-		return "", fmt.Errorf("failed to get job log URL for job %d: %w", jobID, err)
+		return nil, fmt.Errorf("failed to get job log URL for job %d: %w", jobID, logsError(resp, err))
 	}
 
 	// Check response status
 	if resp != nil && resp.StatusCode != 0 {
 		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusFound {
-			return "", newHTTPErrorFromGitHub(resp, "failed to get job logs")
+			return nil, newHTTPErrorFromGitHub(resp, "failed to get job logs")
 		}
 	}
 
@@ -1889,22 +2094,22 @@ func (c *Client) GetWorkflowJobLogs(ctx context.Context, jobID int64, head, tail
 	// Some storage backends reject Authorization headers on pre-signed URLs.
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to build job log request for job %d: %w", jobID, err)
+		return nil, fmt.Errorf("failed to build job log request for job %d: %w", jobID, err)
 	}
 	zipResp, err := presignedHTTPClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch job logs for job %d: %w", jobID, err)
+		return nil, fmt.Errorf("failed to fetch job logs for job %d: %w", jobID, err)
 	}
 	defer zipResp.Body.Close()
 
 	if zipResp.StatusCode != http.StatusOK {
-		return "", &HTTPError{StatusCode: zipResp.StatusCode, Message: fmt.Sprintf("failed to fetch job logs: HTTP %d", zipResp.StatusCode)}
+		return nil, &HTTPError{StatusCode: zipResp.StatusCode, Message: fmt.Sprintf("failed to fetch job logs: HTTP %d", zipResp.StatusCode)}
 	}
 
 	// Read the payload data (may be ZIP or plain text), bounded to maxLogFileSize.
 	zipData, err := io.ReadAll(io.LimitReader(zipResp.Body, maxLogFileSize))
 	if err != nil {
-		return "", fmt.Errorf("failed to read job logs for job %d: %w", jobID, err)
+		return nil, fmt.Errorf("failed to read job logs for job %d: %w", jobID, err)
 	}
 
 	// Collect all log files from ZIP payload when available.
@@ -1941,7 +2146,8 @@ func (c *Client) GetWorkflowJobLogs(ctx context.Context, jobID int64, head, tail
 		})
 	}
 
-	return formatLogFiles(logFiles, head, tail, offset, noHeaders, filterOpts)
+	c.cacheJobLog(ctx, jobID, logFiles)
+	return logFiles, nil
 }
 
 // GetWorkflowJobLogsFromRunArchive retrieves logs for a job from the workflow
@@ -1964,20 +2170,9 @@ func (c *Client) GetWorkflowJobLogsFromRunArchive(ctx context.Context, runID, jo
 		return "", fmt.Errorf("job %d not found in run %d", jobID, runID)
 	}
 
-	url, resp, err := c.gh.Actions.GetWorkflowRunLogs(ctx, c.owner, c.repo, runID, maxRedirects)
+	logFiles, err := c.runLogArchive(ctx, runID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get workflow log URL for run %d: %w", runID, err)
-	}
-
-	if resp != nil && resp.StatusCode != 0 {
-		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusFound {
-			return "", newHTTPErrorFromGitHub(resp, "failed to get workflow logs")
-		}
-	}
-
-	logFiles, _, err := readZipArchive(url.String(), presignedHTTPClient)
-	if err != nil {
-		return "", fmt.Errorf("failed to read log archive for run %d: %w", runID, err)
+		return "", err
 	}
 
 	prefix := jobName + "/"
@@ -1994,45 +2189,61 @@ func (c *Client) GetWorkflowJobLogsFromRunArchive(ctx context.Context, runID, jo
 	return formatLogFiles(filtered, head, tail, offset, noHeaders, filterOpts)
 }
 
-// GetWorkflowRunArtifacts retrieves artifacts for a workflow run
+// GetWorkflowRunArtifacts retrieves all artifacts of a workflow run, across pages
 func (c *Client) GetWorkflowRunArtifacts(ctx context.Context, runID int64) ([]*Artifact, error) {
-	arts, _, err := c.gh.Actions.ListWorkflowRunArtifacts(ctx, c.owner, c.repo, runID, &github.ListOptions{
-		PerPage: c.perPageLimit,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list artifacts for run %d: %w", runID, err)
-	}
-
-	result := make([]*Artifact, 0, len(arts.Artifacts))
-	for _, art := range arts.Artifacts {
-		result = append(result, &Artifact{
-			ID:          art.GetID(),
-			Name:        art.GetName(),
-			SizeInBytes: art.GetSizeInBytes(),
-			CreatedAt:   formatTimeValue(art.GetCreatedAt()),
-			ExpiresAt:   formatTimeValue(art.GetExpiresAt()),
-			ArchiveURL:  art.GetArchiveDownloadURL(),
-		})
+	opts := &github.ListOptions{PerPage: c.perPageLimit}
+	result := []*Artifact{}
+	for {
+		arts, resp, err := c.gh.Actions.ListWorkflowRunArtifacts(ctx, c.owner, c.repo, runID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts for run %d: %w", runID, err)
+		}
+		for _, art := range arts.Artifacts {
+			result = append(result, artifactFromGitHub(art))
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
 
 	return result, nil
 }
 
-// GetArtifactByID retrieves a single artifact by its ID
-func (c *Client) GetArtifactByID(ctx context.Context, artifactID int64) (*Artifact, error) {
-	art, _, err := c.gh.Actions.GetArtifact(ctx, c.owner, c.repo, artifactID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get artifact %d: %w", artifactID, err)
-	}
-
+// artifactFromGitHub converts an artifact's metadata.
+func artifactFromGitHub(art *github.Artifact) *Artifact {
 	return &Artifact{
 		ID:          art.GetID(),
 		Name:        art.GetName(),
 		SizeInBytes: art.GetSizeInBytes(),
 		CreatedAt:   formatTimeValue(art.GetCreatedAt()),
 		ExpiresAt:   formatTimeValue(art.GetExpiresAt()),
+		Expired:     art.GetExpired(),
 		ArchiveURL:  art.GetArchiveDownloadURL(),
-	}, nil
+	}
+}
+
+// GetArtifactByID retrieves a single artifact by its ID
+func (c *Client) GetArtifactByID(ctx context.Context, artifactID int64) (*Artifact, error) {
+	art, _, err := c.gh.Actions.GetArtifact(ctx, c.owner, c.repo, artifactID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artifact %d: %w", artifactID, err)
+	}
+
+	return artifactFromGitHub(art), nil
+}
+
+// DeleteArtifact deletes an artifact and returns its metadata, so callers can report the
+// storage freed
+func (c *Client) DeleteArtifact(ctx context.Context, artifactID int64) (*Artifact, error) {
+	artifact, err := c.GetArtifactByID(ctx, artifactID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.gh.Actions.DeleteArtifact(ctx, c.owner, c.repo, artifactID); err != nil {
+		return nil, fmt.Errorf("failed to delete artifact %d: %w", artifactID, err)
+	}
+	return artifact, nil
 }
 
 // GetArtifactContent retrieves the contents of an artifact without downloading to disk
@@ -2045,11 +2256,14 @@ func (c *Client) GetArtifactContent(ctx context.Context, artifactID int64, fileP
 	if err != nil {
 		return nil, err
 	}
+	if artifact.Expired {
+		return nil, fmt.Errorf("artifact %d (%s) expired at %s and can no longer be downloaded", artifactID, artifact.Name, artifact.ExpiresAt)
+	}
 
 	// Download the artifact ZIP
 	zipURL, resp, err := c.gh.Actions.DownloadArtifact(ctx, c.owner, c.repo, artifactID, maxRedirects)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get artifact download URL: %w", err)
+		return nil, fmt.Errorf("failed to get artifact download URL: %w", responseError(resp, err))
 	}
 
 	if resp != nil && resp.StatusCode != 0 {
@@ -2171,6 +2385,9 @@ func (c *Client) DownloadArtifact(ctx context.Context, artifactID int64, outputP
 	if err != nil {
 		return nil, err
 	}
+	if artifact.Expired {
+		return nil, fmt.Errorf("artifact %d (%s) expired at %s and can no longer be downloaded", artifactID, artifact.Name, artifact.ExpiresAt)
+	}
 
 	// Generate default output path if not provided
 	if outputPath == "" {
@@ -2180,7 +2397,7 @@ func (c *Client) DownloadArtifact(ctx context.Context, artifactID int64, outputP
 	// Download the artifact ZIP
 	zipURL, resp, err := c.gh.Actions.DownloadArtifact(ctx, c.owner, c.repo, artifactID, maxRedirects)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get artifact download URL: %w", err)
+		return nil, fmt.Errorf("failed to get artifact download URL: %w", responseError(resp, err))
 	}
 
 	if resp != nil && resp.StatusCode != 0 {
@@ -2442,9 +2659,17 @@ func (c *Client) determineOverallState(checkRuns []*CheckRun) string {
 
 // WaitForRun waits for a workflow run to complete (silent polling)
 func (c *Client) WaitForRun(ctx context.Context, runID int64, timeoutMinutes int) (*WaitRunResult, error) {
+	return c.WaitForRunWithOptions(ctx, runID, WaitRunOptions{TimeoutMinutes: timeoutMinutes})
+}
+
+// WaitForRunWithOptions waits for a workflow run to complete. A run blocked on a required
+// reviewer is reported with status "waiting_for_approval" and the pending approval when
+// the wait times out, or right away with StopOnApproval.
+func (c *Client) WaitForRunWithOptions(ctx context.Context, runID int64, opts WaitRunOptions) (*WaitRunResult, error) {
 	const defaultTimeoutMinutes = 30
 	const pollIntervalSeconds = 15
 
+	timeoutMinutes := opts.TimeoutMinutes
 	if timeoutMinutes <= 0 {
 		timeoutMinutes = defaultTimeoutMinutes
 	}
@@ -2455,6 +2680,9 @@ func (c *Client) WaitForRun(ctx context.Context, runID int64, timeoutMinutes int
 
 	log.Infof("Starting to wait for workflow run %d (timeout: %dm)", runID, timeoutMinutes)
 
+	var approval *PendingApproval
+	waiting := false
+	polls := 0
 	for {
 		// Check context cancellation
 		select {
@@ -2473,13 +2701,19 @@ func (c *Client) WaitForRun(ctx context.Context, runID int64, timeoutMinutes int
 			// Get final run state for the result
 			run, err := c.GetWorkflowRun(ctx, runID)
 			if err == nil {
+				status := "timed_out"
+				if approval != nil {
+					status = RunStatusWaitingForApproval
+				}
 				return &WaitRunResult{
-					Status:          "timed_out",
+					Status:          status,
 					Conclusion:      run.Conclusion,
 					DurationSeconds: elapsed.Seconds(),
 					RunURL:          run.URL,
 					StartedAt:       run.CreatedAt,
 					TimeoutReached:  true,
+					PollCount:       polls,
+					Approval:        approval,
 				}, nil
 			}
 			return &WaitRunResult{
@@ -2494,6 +2728,35 @@ func (c *Client) WaitForRun(ctx context.Context, runID int64, timeoutMinutes int
 		if err != nil {
 			return nil, fmt.Errorf("failed to get workflow run %d: %w", runID, err)
 		}
+		polls++
+
+		// A "waiting" run is held by a deployment protection rule; look up the reviewers
+		// once each time it starts waiting.
+		if run.Status != "waiting" {
+			waiting, approval = false, nil
+		} else if !waiting {
+			waiting = true
+			approval, err = c.GetPendingApproval(ctx, runID)
+			if err != nil {
+				log.Debugf("Could not get pending approvals for run %d: %v", runID, err)
+			}
+			if approval != nil {
+				log.Infof("Workflow run %d is %s", runID, approval.Message)
+				if opts.OnApproval != nil {
+					opts.OnApproval(approval)
+				}
+				if opts.StopOnApproval {
+					return &WaitRunResult{
+						Status:          RunStatusWaitingForApproval,
+						DurationSeconds: time.Since(startTime).Seconds(),
+						RunURL:          run.URL,
+						StartedAt:       run.CreatedAt,
+						PollCount:       polls,
+						Approval:        approval,
+					}, nil
+				}
+			}
+		}
 
 		// Check if completed
 		if run.Status == "completed" {
@@ -2507,6 +2770,7 @@ func (c *Client) WaitForRun(ctx context.Context, runID int64, timeoutMinutes int
 				StartedAt:       run.CreatedAt,
 				CompletedAt:     run.UpdatedAt,
 				TimeoutReached:  false,
+				PollCount:       polls,
 			}, nil
 		}
 
@@ -2634,9 +2898,18 @@ func (c *Client) ManageRun(ctx context.Context, runID int64, action ManageRunAct
 	var err error
 	var message string
 
+	if action == ManageRunActionRerun || action == ManageRunActionRerunFailed {
+		if err := c.checkRunRefAllowed(ctx, runID); err != nil {
+			return nil, err
+		}
+	}
+
 	switch action {
 	case ManageRunActionCancel:
 		_, err = c.gh.Actions.CancelWorkflowRunByID(ctx, c.owner, c.repo, runID)
+		if isAccepted(err) {
+			err = nil
+		}
 		if err == nil {
 			message = fmt.Sprintf("Successfully cancelled workflow run %d", runID)
 		}
@@ -2671,12 +2944,12 @@ func (c *Client) ManageRun(ctx context.Context, runID int64, action ManageRunAct
 	}, nil
 }
 
-// formatTime formats a github.Timestamp pointer into an ISO string
+// formatTime formats a github.Timestamp pointer as RFC3339 in UTC
 func formatTime(t *github.Timestamp) string {
 	if t == nil {
 		return ""
 	}
-	return t.String()
+	return formatTimeValue(*t)
 }
 
 // durationSeconds returns the elapsed seconds between two timestamps.
@@ -2692,15 +2965,65 @@ func durationSeconds(start, end *github.Timestamp) float64 {
 	return d
 }
 
-// formatTimeValue formats a github.Timestamp value into an ISO string
+// formatTimeValue formats a github.Timestamp value as RFC3339 in UTC
 func formatTimeValue(t github.Timestamp) string {
-	return t.String()
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// displayTime formats an RFC3339 timestamp for people to read, in loc (UTC when nil) and
+// with the zone's abbreviation. Values that do not parse are returned unchanged.
+func displayTime(value string, loc *time.Location) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("2006-01-02 15:04:05 MST")
+}
+
+// timeNow is the clock used for relative times; overridden in tests.
+var timeNow = time.Now
+
+// formatAge returns how long ago t was as a compact duration such as "45s", "12m",
+// "3h5m", or "2d4h". It returns "" for a nil or zero timestamp.
+func formatAge(t *github.Timestamp) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	d := timeNow().Sub(t.Time)
+	if d < 0 {
+		d = 0
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	minutes := int(d/time.Minute) % 60
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	}
 }
 
 // GetLogSection extracts a specific section from logs by header pattern
 // Section headers typically look like "##[group]Section Name" or similar patterns
 // If jobID is 0, it fetches logs for the run; otherwise for the specific job
-func (c *Client) GetLogSection(ctx context.Context, runID, jobID int64, sectionPattern string, filterOpts *LogFilterOptions) (string, error) {
+// head, tail, and offset slice the extracted section like they slice whole logs
+func (c *Client) GetLogSection(ctx context.Context, runID, jobID int64, sectionPattern string, head, tail, offset int, filterOpts *LogFilterOptions) (string, error) {
 	var logs string
 	var err error
 
@@ -2734,6 +3057,9 @@ func (c *Client) GetLogSection(ctx context.Context, runID, jobID int64, sectionP
 		section = linesToString(filteredLines)
 	}
 
+	if head > 0 || tail > 0 || offset > 0 {
+		section = sliceLogLines(strings.TrimRight(section, "\n"), head, tail, offset)
+	}
 	return section, nil
 }
 
@@ -2875,7 +3201,21 @@ type FailureDiagnosis struct {
 	Conclusion string         `json:"conclusion"`
 	FailedJobs []*FailedJob   `json:"failed_jobs"`
 	Flakiness  *FlakinessInfo `json:"flakiness,omitempty"`
-	Summary    string         `json:"summary"`
+	// Skipped explains the skipped steps of successful jobs and the skipped jobs, such as
+	// a deploy step whose if: condition was false.
+	Skipped []*SkippedStep `json:"skipped,omitempty"`
+	// QueuedJobs are the queued jobs of a run that has not completed, with any problems
+	// with the runner labels they wait for.
+	QueuedJobs []*QueuedJob `json:"queued_jobs,omitempty"`
+	Summary    string       `json:"summary"`
+}
+
+// QueuedJob is a job waiting for a runner.
+type QueuedJob struct {
+	JobID    int64    `json:"job_id"`
+	JobName  string   `json:"job_name"`
+	Labels   []string `json:"labels,omitempty"`
+	Problems []string `json:"problems,omitempty"` // Why no runner may pick the job up, from CheckRunnerLabels
 }
 
 // FailedJob represents a job that failed within a workflow run
@@ -2883,8 +3223,17 @@ type FailedJob struct {
 	JobID       int64         `json:"job_id"`
 	JobName     string        `json:"job_name"`
 	Conclusion  string        `json:"conclusion"`
+	RunnerName  string        `json:"runner_name,omitempty"`
+	RunnerGroup string        `json:"runner_group,omitempty"`
+	Labels      []string      `json:"labels,omitempty"`
 	FailedSteps []*FailedStep `json:"failed_steps"`
 	ErrorLines  []string      `json:"error_lines"`
+	// ContainerErrors are log lines about pulling or starting the job's container or
+	// service containers, a common failure that error_lines may bury.
+	ContainerErrors []string `json:"container_errors,omitempty"`
+	// Tool is the tool that produced the error lines (go, gcc, pytest, or webpack), when
+	// recognised.
+	Tool string `json:"tool,omitempty"`
 }
 
 // FailedStep represents a step that failed within a job
@@ -2944,11 +3293,27 @@ func (c *Client) DiagnoseFailure(ctx context.Context, runID int64, checkFlakines
 
 	if run.Status != "completed" {
 		diagnosis.Summary = fmt.Sprintf("Run %d is still %s (not completed yet)", runID, run.Status)
+		diagnosis.QueuedJobs = c.diagnoseQueuedJobs(ctx, runID)
+		for _, job := range diagnosis.QueuedJobs {
+			if len(job.Problems) > 0 {
+				diagnosis.Summary += fmt.Sprintf("; job %q may never start: %s", job.JobName, job.Problems[0])
+				break
+			}
+		}
 		return diagnosis, nil
 	}
 
 	if run.Conclusion == "success" {
 		diagnosis.Summary = fmt.Sprintf("Run %d succeeded — nothing to diagnose", runID)
+		// A successful run may still have skipped the step that mattered.
+		if jobs, err := c.GetWorkflowJobs(ctx, runID, "", 0); err == nil {
+			diagnosis.Skipped = c.explainSkipped(ctx, run, jobs)
+		} else {
+			log.Debugf("Could not get jobs for run %d: %v", runID, err)
+		}
+		if len(diagnosis.Skipped) > 0 {
+			diagnosis.Summary = fmt.Sprintf("Run %d succeeded, but %s did not run; see skipped for why", runID, skippedNames(diagnosis.Skipped))
+		}
 		return diagnosis, nil
 	}
 
@@ -2957,6 +3322,7 @@ func (c *Client) DiagnoseFailure(ctx context.Context, runID int64, checkFlakines
 	if err != nil {
 		return nil, fmt.Errorf("failed to get jobs for run %d: %w", runID, err)
 	}
+	diagnosis.Skipped = c.explainSkipped(ctx, run, jobs)
 
 	for _, job := range jobs {
 		if job.Conclusion != "failure" && job.Conclusion != "cancelled" && job.Conclusion != "timed_out" {
@@ -2964,9 +3330,12 @@ func (c *Client) DiagnoseFailure(ctx context.Context, runID int64, checkFlakines
 		}
 
 		failedJob := &FailedJob{
-			JobID:      job.ID,
-			JobName:    job.Name,
-			Conclusion: job.Conclusion,
+			JobID:       job.ID,
+			JobName:     job.Name,
+			Conclusion:  job.Conclusion,
+			RunnerName:  job.RunnerName,
+			RunnerGroup: job.RunnerGroup,
+			Labels:      job.Labels,
 		}
 
 		// Identify failed steps
@@ -2980,9 +3349,9 @@ func (c *Client) DiagnoseFailure(ctx context.Context, runID int64, checkFlakines
 			}
 		}
 
-		// 3. Extract error lines from job logs
-		errorLines := c.extractErrorLines(ctx, runID, job.ID, maxLogLines)
-		failedJob.ErrorLines = errorLines
+		// 3. Extract error lines, and container failures, from job logs
+		failedJob.ErrorLines, failedJob.ContainerErrors = c.extractErrorLines(ctx, runID, job.ID, maxLogLines)
+		failedJob.Tool, _ = DetectLogTool(failedJob.ErrorLines)
 
 		diagnosis.FailedJobs = append(diagnosis.FailedJobs, failedJob)
 	}
@@ -2998,8 +3367,9 @@ func (c *Client) DiagnoseFailure(ctx context.Context, runID int64, checkFlakines
 	return diagnosis, nil
 }
 
-// extractErrorLines fetches logs for a job and extracts lines matching error patterns
-func (c *Client) extractErrorLines(ctx context.Context, runID, jobID int64, maxLines int) []string {
+// extractErrorLines fetches logs for a job and extracts lines matching error patterns, and
+// separately the lines reporting job or service container failures
+func (c *Client) extractErrorLines(ctx context.Context, runID, jobID int64, maxLines int) ([]string, []string) {
 	logs, err := c.GetWorkflowJobLogs(ctx, jobID, 0, 0, 0, true, nil)
 	if err != nil {
 		log.Debugf("Could not fetch logs for job %d: %v", jobID, err)
@@ -3008,10 +3378,10 @@ func (c *Client) extractErrorLines(ctx context.Context, runID, jobID int64, maxL
 			if archiveErr == nil {
 				logs = archiveLogs
 			} else {
-				return []string{fmt.Sprintf("[could not fetch logs: %v; archive fallback failed: %v]", err, archiveErr)}
+				return []string{fmt.Sprintf("[could not fetch logs: %v; archive fallback failed: %v]", err, archiveErr)}, nil
 			}
 		} else {
-			return []string{fmt.Sprintf("[could not fetch logs: %v]", err)}
+			return []string{fmt.Sprintf("[could not fetch logs: %v]", err)}, nil
 		}
 	}
 
@@ -3047,7 +3417,7 @@ func (c *Client) extractErrorLines(ctx context.Context, runID, jobID int64, maxL
 		}
 	}
 
-	return errorLines
+	return errorLines, ExtractContainerErrors(logs, maxLines)
 }
 
 // checkFlakiness compares the current failure against recent runs of the same workflow
@@ -3150,6 +3520,9 @@ func (c *Client) buildDiagnosisSummary(d *FailureDiagnosis) string {
 			sb.WriteString(".")
 		}
 	}
+	if len(d.Skipped) > 0 {
+		sb.WriteString(fmt.Sprintf(" Skipped: %s.", skippedNames(d.Skipped)))
+	}
 
 	return sb.String()
 }
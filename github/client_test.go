@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	githubapi "github.com/google/go-github/v69/github"
 	"github.com/sirupsen/logrus"
@@ -95,12 +96,14 @@ func TestInferRepoFromOrigin_SSH(t *testing.T) {
 			wantRepo:  "repo",
 			wantErr:   false,
 		},
+		// Enterprise hosts must be allowed by a HostPolicy (see ParseRepoURL),
+		// the same as for HTTPS URLs.
 		{
-			name:      "SSH enterprise URL",
+			name:      "SSH enterprise URL fails",
 			url:       "git@github.mycompany.com:owner/repo.git",
-			wantOwner: "owner",
-			wantRepo:  "repo",
-			wantErr:   false,
+			wantOwner: "",
+			wantRepo:  "",
+			wantErr:   true,
 		},
 		{
 			name:      "SSH URL with extra slash after colon",
@@ -234,8 +237,8 @@ func TestTokenIsSentInRequest(t *testing.T) {
 	var capturedReq *http.Request
 
 	// Use a custom transport to capture the request
-	originalTransport := http.DefaultTransport
-	http.DefaultTransport = roundTripperFunc(func(req *http.Request) *http.Response {
+	originalTransport := apiTransport
+	apiTransport = roundTripperFunc(func(req *http.Request) *http.Response {
 		capturedReq = req
 		// Return a mock response
 		return &http.Response{
@@ -244,7 +247,7 @@ func TestTokenIsSentInRequest(t *testing.T) {
 			Header:     http.Header{"Content-Type": []string{"application/json"}},
 		}
 	})
-	defer func() { http.DefaultTransport = originalTransport }()
+	defer func() { apiTransport = originalTransport }()
 
 	client := NewClient("my-secret-token", "owner", "repo")
 	_, _ = client.GetWorkflows(context.Background())
@@ -1983,3 +1986,242 @@ func newTimingAnalysisTestClient(t *testing.T) (*Client, func()) {
 
 	return client, ts.Close
 }
+
+func TestListRepositoryWorkflowRunsWithOptions_RunnerFilter(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_count": 2,
+			"workflow_runs": [
+				{"id": 1, "name": "CI", "status": "completed", "conclusion": "failure"},
+				{"id": 2, "name": "CI", "status": "completed", "conclusion": "success"}
+			]
+		}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "jobs": [{"id": 11, "name": "build", "runner_name": "gpu-runner-01", "runner_group_name": "gpu", "labels": ["self-hosted", "gpu"], "run_id": 1}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/2/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "jobs": [{"id": 21, "name": "build", "runner_name": "GitHub Actions 2", "run_id": 2}]}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	runs, err := client.ListRepositoryWorkflowRunsWithOptions(context.Background(), &ListRunsOptions{Runner: "GPU-Runner-01"})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, int64(1), runs[0].ID)
+
+	jobs, err := client.GetWorkflowJobs(context.Background(), 1, "", 0)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "gpu-runner-01", jobs[0].RunnerName)
+	assert.Equal(t, "gpu", jobs[0].RunnerGroup)
+	assert.Equal(t, []string{"self-hosted", "gpu"}, jobs[0].Labels)
+}
+
+func TestWorkflowRunFromGitHub_TimesAndAge(t *testing.T) {
+	created := time.Date(2026, 4, 20, 10, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	started := created.Add(30 * time.Second)
+	updated := created.Add(5*time.Minute + 30*time.Second)
+
+	originalNow := timeNow
+	timeNow = func() time.Time { return created.Add(26*time.Hour + 15*time.Minute) }
+	t.Cleanup(func() { timeNow = originalNow })
+
+	run := workflowRunFromGitHub(&githubapi.WorkflowRun{
+		ID:           githubapi.Ptr(int64(1)),
+		CreatedAt:    &githubapi.Timestamp{Time: created},
+		RunStartedAt: &githubapi.Timestamp{Time: started},
+		UpdatedAt:    &githubapi.Timestamp{Time: updated},
+	})
+
+	assert.Equal(t, "2026-04-20T08:00:00Z", run.CreatedAt)
+	assert.Equal(t, "2026-04-20T08:05:30Z", run.UpdatedAt)
+	assert.Equal(t, "2026-04-20T08:00:30Z", run.StartedAt)
+	assert.Equal(t, "1d2h", run.Age)
+	assert.Equal(t, float64(300), run.DurationSeconds)
+
+	data, err := json.Marshal(run)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"duration_seconds":300`)
+}
+
+func TestWorkflowRunFromGitHub_HeadCommit(t *testing.T) {
+	var ghRun githubapi.WorkflowRun
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 1,
+		"head_sha": "abc123",
+		"head_commit": {
+			"id": "abc123",
+			"message": "Fix flaky test\n\nRetry the network call once.\n",
+			"author": {"name": "Alice", "email": "alice@example.com"},
+			"committer": {"email": "noreply@github.com"}
+		}
+	}`), &ghRun))
+
+	run := workflowRunFromGitHub(&ghRun)
+	assert.Equal(t, "Fix flaky test\n\nRetry the network call once.", run.CommitMessage)
+	assert.Equal(t, "Alice", run.CommitAuthor)
+	assert.Equal(t, "noreply@github.com", run.Committer)
+	assert.Equal(t, "Fix flaky test", CommitTitle(run.CommitMessage))
+
+	run = workflowRunFromGitHub(&githubapi.WorkflowRun{ID: githubapi.Ptr(int64(2))})
+	assert.Empty(t, run.CommitMessage)
+	assert.Empty(t, run.CommitAuthor)
+}
+
+func TestFormatAge(t *testing.T) {
+	now := time.Date(2026, 4, 20, 12, 0, 0, 0, time.UTC)
+	originalNow := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = originalNow })
+
+	ago := func(d time.Duration) *githubapi.Timestamp { return &githubapi.Timestamp{Time: now.Add(-d)} }
+	assert.Equal(t, "", formatAge(nil))
+	assert.Equal(t, "45s", formatAge(ago(45*time.Second)))
+	assert.Equal(t, "12m", formatAge(ago(12*time.Minute+10*time.Second)))
+	assert.Equal(t, "3h", formatAge(ago(3*time.Hour)))
+	assert.Equal(t, "3h5m", formatAge(ago(3*time.Hour+5*time.Minute)))
+	assert.Equal(t, "2d", formatAge(ago(48*time.Hour)))
+	assert.Equal(t, "0s", formatAge(ago(-time.Minute)))
+}
+
+func TestGetActionsStatus_CountsInconclusiveRuns(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/actions/workflows", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "workflows": [{"id": 1, "name": "CI"}]}`))
+	})
+	mux.HandleFunc("/repos/o/r/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 6, "workflow_runs": [
+			{"id": 6, "status": "completed", "conclusion": "success"},
+			{"id": 5, "status": "completed", "conclusion": "failure"},
+			{"id": 4, "status": "completed", "conclusion": "skipped"},
+			{"id": 3, "status": "completed", "conclusion": "skipped"},
+			{"id": 2, "status": "completed", "conclusion": "neutral"},
+			{"id": 1, "status": "completed", "conclusion": "stale"}
+		]}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client())
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: "o", repo: "r", gh: ghc, perPageLimit: 50}
+
+	status, err := client.GetActionsStatus(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.SuccessfulRuns)
+	assert.Equal(t, 1, status.FailedRuns)
+	assert.Equal(t, 2, status.SkippedRuns)
+	assert.Equal(t, 1, status.NeutralRuns)
+	assert.Equal(t, 1, status.StaleRuns)
+
+	require.Len(t, status.RecentRuns, 6)
+	assert.True(t, status.RecentRuns[2].Skipped)
+	assert.True(t, status.RecentRuns[4].Neutral)
+	assert.True(t, status.RecentRuns[5].Stale)
+	assert.False(t, status.RecentRuns[0].Skipped || status.RecentRuns[0].Neutral || status.RecentRuns[0].Stale)
+}
+
+func TestResolveWorkflowID_NameCollision(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 3, "workflows": [
+			{"id": 1, "name": "Deploy", "path": ".github/workflows/deploy.yml", "state": "active"},
+			{"id": 2, "name": "Deploy", "path": ".github/workflows/deploy-old.yml", "state": "disabled_manually"},
+			{"id": 3, "name": "CI", "path": ".github/workflows/ci.yml", "state": "active"}
+		]}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	_, _, err = client.ResolveWorkflowID(context.Background(), "Deploy")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrAmbiguousWorkflow)
+	assert.Equal(t, CodeAmbiguousWorkflow, ErrorCode(err))
+	var ambiguous *AmbiguousWorkflowError
+	require.ErrorAs(t, err, &ambiguous)
+	require.Len(t, ambiguous.Candidates, 2)
+	assert.Equal(t, ".github/workflows/deploy-old.yml", ambiguous.Candidates[1].Path)
+	assert.Contains(t, err.Error(), ".github/workflows/deploy.yml (id 1, active)")
+
+	id, name, err := client.ResolveWorkflowID(context.Background(), ".github/workflows/deploy-old.yml")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), id)
+	assert.Equal(t, "Deploy", name)
+
+	id, _, err = client.ResolveWorkflowID(context.Background(), "CI")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), id)
+}
+
+func TestDeleteArtifact(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	deleted := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/artifacts/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(artifactJSON(123, "coverage", 4096))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	artifact, err := client.DeleteArtifact(context.Background(), 123)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Equal(t, "coverage", artifact.Name)
+	assert.Equal(t, int64(4096), artifact.SizeInBytes)
+
+	_, err = client.DeleteArtifact(context.Background(), 124)
+	assert.Error(t, err)
+}
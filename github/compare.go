@@ -0,0 +1,341 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+const (
+	// maxComparisonCommits caps the number of commits listed in a run comparison.
+	maxComparisonCommits = 20
+	// maxLastGreenRuns bounds how many successful runs FindLastSuccessfulRun looks through.
+	maxLastGreenRuns = 500
+)
+
+// JobComparison describes how a single job changed between two runs.
+type JobComparison struct {
+	Name                 string   `json:"name"`
+	Change               string   `json:"change"`
+	BaseConclusion       string   `json:"base_conclusion,omitempty"`
+	HeadConclusion       string   `json:"head_conclusion,omitempty"`
+	BaseDurationSeconds  float64  `json:"base_duration_seconds,omitempty"`
+	HeadDurationSeconds  float64  `json:"head_duration_seconds,omitempty"`
+	DurationDeltaSeconds float64  `json:"duration_delta_seconds,omitempty"`
+	BaseRunnerName       string   `json:"base_runner_name,omitempty"`
+	HeadRunnerName       string   `json:"head_runner_name,omitempty"`
+	FailedSteps          []string `json:"failed_steps,omitempty"`
+}
+
+// ComparisonCommit is a commit that landed between the base and head runs.
+type ComparisonCommit struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+	Author  string `json:"author,omitempty"`
+}
+
+// RunComparison is the result of comparing a head run against a base run.
+type RunComparison struct {
+	Base                 *WorkflowRun        `json:"base"`
+	Head                 *WorkflowRun        `json:"head"`
+	DurationDeltaSeconds float64             `json:"duration_delta_seconds"`
+	Jobs                 []*JobComparison    `json:"jobs"`
+	NewFailures          []string            `json:"new_failures,omitempty"`
+	Fixed                []string            `json:"fixed,omitempty"`
+	CommitsBetween       int                 `json:"commits_between,omitempty"`
+	Commits              []*ComparisonCommit `json:"commits,omitempty"`
+	Summary              string              `json:"summary"`
+}
+
+// CompareRuns compares the jobs, steps, durations, and commits of two workflow runs.
+// The base run is typically a known-good run and the head run the one under investigation.
+func (c *Client) CompareRuns(ctx context.Context, baseRunID, headRunID int64) (*RunComparison, error) {
+	base, err := c.GetWorkflowRun(ctx, baseRunID)
+	if err != nil {
+		return nil, err
+	}
+	head, err := c.GetWorkflowRun(ctx, headRunID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseJobs, err := c.GetWorkflowJobs(ctx, baseRunID, "latest", 0)
+	if err != nil {
+		return nil, err
+	}
+	headJobs, err := c.GetWorkflowJobs(ctx, headRunID, "latest", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &RunComparison{
+		Base:                 base,
+		Head:                 head,
+		DurationDeltaSeconds: head.DurationSeconds - base.DurationSeconds,
+		Jobs:                 compareJobs(baseJobs, headJobs),
+	}
+
+	for _, j := range comparison.Jobs {
+		switch j.Change {
+		case "regressed":
+			comparison.NewFailures = append(comparison.NewFailures, j.Name)
+		case "fixed":
+			comparison.Fixed = append(comparison.Fixed, j.Name)
+		}
+	}
+
+	if base.HeadSHA != "" && head.HeadSHA != "" && base.HeadSHA != head.HeadSHA {
+		commits, total, err := c.commitsBetween(ctx, base.HeadSHA, head.HeadSHA)
+		if err != nil {
+			log.Debugf("Could not compare commits %s...%s: %v", base.HeadSHA, head.HeadSHA, err)
+		} else {
+			comparison.Commits = commits
+			comparison.CommitsBetween = total
+		}
+	}
+
+	comparison.Summary = buildComparisonSummary(comparison)
+	return comparison, nil
+}
+
+// FindLastSuccessfulRun returns the most recent successful run of the same workflow
+// and branch that was created before the given run. It looks through at most
+// maxLastGreenRuns successful runs.
+func (c *Client) FindLastSuccessfulRun(ctx context.Context, run *WorkflowRun) (*WorkflowRun, error) {
+	opts := &github.ListWorkflowRunsOptions{
+		Branch:      run.Branch,
+		Status:      "success",
+		ListOptions: github.ListOptions{PerPage: c.perPageLimit},
+	}
+
+	scanned := 0
+	for scanned < maxLastGreenRuns {
+		runs, resp, err := c.gh.Actions.ListWorkflowRunsByID(ctx, c.owner, c.repo, run.WorkflowID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list successful runs for workflow %d: %w", run.WorkflowID, err)
+		}
+
+		for _, r := range runs.WorkflowRuns {
+			scanned++
+			if r.GetID() == run.ID || r.GetConclusion() != "success" {
+				continue
+			}
+			if run.RunNumber > 0 && r.GetRunNumber() >= run.RunNumber {
+				continue
+			}
+			return workflowRunFromGitHub(r), nil
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	limit := ""
+	if scanned >= maxLastGreenRuns {
+		limit = fmt.Sprintf(" among the last %d successful runs", maxLastGreenRuns)
+	}
+	if run.Branch != "" {
+		return nil, fmt.Errorf("no successful run of workflow %q found on branch %s before run %d%s", run.Name, run.Branch, run.ID, limit)
+	}
+	return nil, fmt.Errorf("no successful run of workflow %q found before run %d%s", run.Name, run.ID, limit)
+}
+
+// CompareWithLastGreen compares a run against the most recent successful run of the
+// same workflow on the same branch.
+func (c *Client) CompareWithLastGreen(ctx context.Context, runID int64) (*RunComparison, error) {
+	run, err := c.GetWorkflowRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	green, err := c.FindLastSuccessfulRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CompareRuns(ctx, green.ID, run.ID)
+}
+
+func (c *Client) commitsBetween(ctx context.Context, baseSHA, headSHA string) ([]*ComparisonCommit, int, error) {
+	cmp, _, err := c.gh.Repositories.CompareCommits(ctx, c.owner, c.repo, baseSHA, headSHA, &github.ListOptions{PerPage: maxComparisonCommits})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	commits, total := comparisonCommits(cmp)
+	return commits, total, nil
+}
+
+// comparisonCommits converts the commits of a comparison, up to maxComparisonCommits, and
+// returns them with the total number of commits compared.
+func comparisonCommits(cmp *github.CommitsComparison) ([]*ComparisonCommit, int) {
+	commits := make([]*ComparisonCommit, 0, len(cmp.Commits))
+	for _, rc := range cmp.Commits {
+		if len(commits) >= maxComparisonCommits {
+			break
+		}
+		message := rc.GetCommit().GetMessage()
+		if idx := strings.Index(message, "\n"); idx >= 0 {
+			message = message[:idx]
+		}
+		author := rc.GetAuthor().GetLogin()
+		if author == "" {
+			author = rc.GetCommit().GetAuthor().GetName()
+		}
+		commits = append(commits, &ComparisonCommit{
+			SHA:     rc.GetSHA(),
+			Message: message,
+			Author:  author,
+		})
+	}
+
+	total := cmp.GetTotalCommits()
+	if total == 0 {
+		total = len(cmp.Commits)
+	}
+	return commits, total
+}
+
+// compareJobs pairs jobs by name and classifies how each one changed.
+func compareJobs(baseJobs, headJobs []*Job) []*JobComparison {
+	baseByName := make(map[string]*Job, len(baseJobs))
+	for _, j := range baseJobs {
+		baseByName[j.Name] = j
+	}
+	headByName := make(map[string]*Job, len(headJobs))
+	for _, j := range headJobs {
+		headByName[j.Name] = j
+	}
+
+	result := make([]*JobComparison, 0, len(headJobs)+len(baseJobs))
+	for _, h := range headJobs {
+		jc := &JobComparison{
+			Name:                h.Name,
+			HeadConclusion:      h.Conclusion,
+			HeadDurationSeconds: h.DurationSeconds,
+			HeadRunnerName:      h.RunnerName,
+			FailedSteps:         failedStepNames(h.Steps),
+		}
+		if b, ok := baseByName[h.Name]; ok {
+			jc.BaseConclusion = b.Conclusion
+			jc.BaseDurationSeconds = b.DurationSeconds
+			jc.BaseRunnerName = b.RunnerName
+			jc.DurationDeltaSeconds = h.DurationSeconds - b.DurationSeconds
+			jc.Change = classifyJobChange(b.Conclusion, h.Conclusion)
+		} else {
+			jc.Change = "added"
+		}
+		result = append(result, jc)
+	}
+	for _, b := range baseJobs {
+		if _, ok := headByName[b.Name]; ok {
+			continue
+		}
+		result = append(result, &JobComparison{
+			Name:                b.Name,
+			Change:              "removed",
+			BaseConclusion:      b.Conclusion,
+			BaseDurationSeconds: b.DurationSeconds,
+			BaseRunnerName:      b.RunnerName,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return jobChangeRank(result[i].Change) < jobChangeRank(result[j].Change)
+	})
+	return result
+}
+
+func classifyJobChange(baseConclusion, headConclusion string) string {
+	baseFailed := isFailureConclusion(baseConclusion)
+	headFailed := isFailureConclusion(headConclusion)
+	switch {
+	case !baseFailed && headFailed:
+		return "regressed"
+	case baseFailed && !headFailed:
+		return "fixed"
+	case baseConclusion != headConclusion:
+		return "changed"
+	default:
+		return "unchanged"
+	}
+}
+
+func isFailureConclusion(conclusion string) bool {
+	switch conclusion {
+	case "failure", "timed_out", "startup_failure":
+		return true
+	}
+	return false
+}
+
+// isInconclusiveConclusion reports whether a run or job completed without passing or
+// failing, typically because its if: condition skipped it.
+func isInconclusiveConclusion(conclusion string) bool {
+	switch conclusion {
+	case "skipped", "neutral", "stale":
+		return true
+	}
+	return false
+}
+
+func jobChangeRank(change string) int {
+	switch change {
+	case "regressed":
+		return 0
+	case "added", "removed":
+		return 1
+	case "changed":
+		return 2
+	case "fixed":
+		return 3
+	default:
+		return 4
+	}
+}
+
+func failedStepNames(steps []*Step) []string {
+	var names []string
+	for _, s := range steps {
+		if isFailureConclusion(s.Conclusion) {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+func buildComparisonSummary(c *RunComparison) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Run %d (%s) vs run %d (%s)", c.Head.ID, c.Head.Conclusion, c.Base.ID, c.Base.Conclusion))
+
+	if len(c.NewFailures) > 0 {
+		sb.WriteString(fmt.Sprintf(": %d newly failing job(s): %s", len(c.NewFailures), strings.Join(c.NewFailures, ", ")))
+	} else {
+		sb.WriteString(": no newly failing jobs")
+	}
+	sb.WriteString(".")
+
+	if len(c.Fixed) > 0 {
+		sb.WriteString(fmt.Sprintf(" Fixed: %s.", strings.Join(c.Fixed, ", ")))
+	}
+	if c.CommitsBetween > 0 {
+		sb.WriteString(fmt.Sprintf(" %d commit(s) between %s and %s.", c.CommitsBetween, shortSHA(c.Base.HeadSHA), shortSHA(c.Head.HeadSHA)))
+	}
+	if c.DurationDeltaSeconds != 0 {
+		sb.WriteString(fmt.Sprintf(" Duration changed by %+.0fs.", c.DurationDeltaSeconds))
+	}
+
+	return sb.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,208 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareWithLastGreen(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/110", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": 110, "name": "CI", "status": "completed", "conclusion": "failure",
+			"head_branch": "main", "head_sha": "sha110", "event": "push",
+			"created_at": "2026-04-20T10:00:00Z", "updated_at": "2026-04-20T10:09:00Z",
+			"run_started_at": "2026-04-20T10:00:00Z", "run_number": 11, "workflow_id": 50
+		}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/108", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": 108, "name": "CI", "status": "completed", "conclusion": "success",
+			"head_branch": "main", "head_sha": "sha108", "event": "push",
+			"created_at": "2026-04-19T10:00:00Z", "updated_at": "2026-04-19T10:06:00Z",
+			"run_started_at": "2026-04-19T10:00:00Z", "run_number": 9, "workflow_id": 50
+		}`))
+	})
+
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "main", r.URL.Query().Get("branch"))
+		assert.Equal(t, "success", r.URL.Query().Get("status"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_count": 2,
+			"workflow_runs": [
+				{"id": 112, "name": "CI", "status": "completed", "conclusion": "success", "head_branch": "main", "run_number": 12, "workflow_id": 50},
+				{"id": 108, "name": "CI", "status": "completed", "conclusion": "success", "head_branch": "main", "head_sha": "sha108", "run_number": 9, "workflow_id": 50}
+			]
+		}`))
+	})
+
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/108/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_count": 2,
+			"jobs": [
+				{"id": 1, "name": "build", "status": "completed", "conclusion": "success", "run_id": 108,
+				 "started_at": "2026-04-19T10:00:00Z", "completed_at": "2026-04-19T10:04:00Z"},
+				{"id": 2, "name": "docs", "status": "completed", "conclusion": "success", "run_id": 108,
+				 "started_at": "2026-04-19T10:00:00Z", "completed_at": "2026-04-19T10:01:00Z"}
+			]
+		}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/110/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_count": 2,
+			"jobs": [
+				{"id": 3, "name": "build", "status": "completed", "conclusion": "failure", "run_id": 110,
+				 "started_at": "2026-04-20T10:00:00Z", "completed_at": "2026-04-20T10:07:00Z",
+				 "steps": [
+					{"name": "Checkout", "number": 1, "status": "completed", "conclusion": "success"},
+					{"name": "Test", "number": 2, "status": "completed", "conclusion": "failure"}
+				 ]},
+				{"id": 4, "name": "lint", "status": "completed", "conclusion": "success", "run_id": 110}
+			]
+		}`))
+	})
+
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/compare/sha108...sha110", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_commits": 2,
+			"commits": [
+				{"sha": "c1", "commit": {"message": "Bump dependency\n\nDetails"}, "author": {"login": "alice"}},
+				{"sha": "c2", "commit": {"message": "Refactor tests", "author": {"name": "Bob"}}}
+			]
+		}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	comparison, err := client.CompareWithLastGreen(context.Background(), 110)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(108), comparison.Base.ID)
+	assert.Equal(t, int64(110), comparison.Head.ID)
+	assert.InDelta(t, 180.0, comparison.DurationDeltaSeconds, 0.01)
+	assert.Equal(t, []string{"build"}, comparison.NewFailures)
+	assert.Empty(t, comparison.Fixed)
+
+	require.Len(t, comparison.Jobs, 3)
+	assert.Equal(t, "build", comparison.Jobs[0].Name)
+	assert.Equal(t, "regressed", comparison.Jobs[0].Change)
+	assert.Equal(t, []string{"Test"}, comparison.Jobs[0].FailedSteps)
+	assert.InDelta(t, 180.0, comparison.Jobs[0].DurationDeltaSeconds, 0.01)
+
+	changes := map[string]string{}
+	for _, j := range comparison.Jobs {
+		changes[j.Name] = j.Change
+	}
+	assert.Equal(t, "added", changes["lint"])
+	assert.Equal(t, "removed", changes["docs"])
+
+	assert.Equal(t, 2, comparison.CommitsBetween)
+	require.Len(t, comparison.Commits, 2)
+	assert.Equal(t, "Bump dependency", comparison.Commits[0].Message)
+	assert.Equal(t, "alice", comparison.Commits[0].Author)
+	assert.Equal(t, "Bob", comparison.Commits[1].Author)
+
+	assert.Contains(t, comparison.Summary, "1 newly failing job(s): build")
+	assert.Contains(t, comparison.Summary, "2 commit(s)")
+}
+
+func TestFindLastSuccessfulRun_Paginates(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	// Every run on the first page is newer than run 300; the last green run is on page 2.
+	var requests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/runs", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Content-Type", "application/json")
+		if page < 2 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, "http://"+r.Host, r.URL.Path))
+			_, _ = w.Write([]byte(`{"total_count": 3, "workflow_runs": [
+				{"id": 320, "name": "CI", "conclusion": "success", "run_number": 32, "workflow_id": 50},
+				{"id": 310, "name": "CI", "conclusion": "success", "run_number": 31, "workflow_id": 50}
+			]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"total_count": 3, "workflow_runs": [
+			{"id": 290, "name": "CI", "conclusion": "success", "run_number": 29, "workflow_id": 50}
+		]}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client())
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 2}
+
+	green, err := client.FindLastSuccessfulRun(context.Background(), &WorkflowRun{ID: 300, Name: "CI", WorkflowID: 50, RunNumber: 30})
+	require.NoError(t, err)
+	assert.Equal(t, int64(290), green.ID)
+	assert.Equal(t, 2, requests)
+
+	// The search stops at maxLastGreenRuns and says so.
+	requests = 0
+	_, err = client.FindLastSuccessfulRun(context.Background(), &WorkflowRun{ID: 300, Name: "CI", WorkflowID: 50, RunNumber: 1})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "among the last")
+
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/60/runs", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, "http://"+r.Host, r.URL.Path, page+1))
+		_, _ = w.Write([]byte(`{"total_count": 10000, "workflow_runs": [
+			{"id": 920, "name": "CI", "conclusion": "success", "run_number": 92, "workflow_id": 60},
+			{"id": 910, "name": "CI", "conclusion": "success", "run_number": 91, "workflow_id": 60}
+		]}`))
+	})
+	requests = 0
+	_, err = client.FindLastSuccessfulRun(context.Background(), &WorkflowRun{ID: 900, Name: "CI", WorkflowID: 60, RunNumber: 90})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("among the last %d successful runs", maxLastGreenRuns))
+	assert.Equal(t, maxLastGreenRuns/2, requests)
+}
+
+func TestClassifyJobChange(t *testing.T) {
+	assert.Equal(t, "regressed", classifyJobChange("success", "failure"))
+	assert.Equal(t, "regressed", classifyJobChange("success", "timed_out"))
+	assert.Equal(t, "fixed", classifyJobChange("failure", "success"))
+	assert.Equal(t, "changed", classifyJobChange("success", "skipped"))
+	assert.Equal(t, "unchanged", classifyJobChange("failure", "failure"))
+}
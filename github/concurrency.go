@@ -0,0 +1,331 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+	"gopkg.in/yaml.v3"
+)
+
+// expensiveTimeoutMinutes is the timeout-minutes from which a job counts as long-running.
+const expensiveTimeoutMinutes = 60
+
+// ConcurrencySetting is a workflow's or job's concurrency: block.
+type ConcurrencySetting struct {
+	Group            string `json:"group"`
+	CancelInProgress string `json:"cancel_in_progress,omitempty"` // "true", "false", or an expression
+}
+
+// cancels reports whether a newer run may cancel one in progress; an expression may.
+func (s *ConcurrencySetting) cancels() bool {
+	return s != nil && s.CancelInProgress != "" && s.CancelInProgress != "false"
+}
+
+// JobConcurrency is a job's concurrency control and why it is considered expensive.
+type JobConcurrency struct {
+	Job         string              `json:"job"`
+	Concurrency *ConcurrencySetting `json:"concurrency,omitempty"`
+	Environment string              `json:"environment,omitempty"`
+	Expensive   []string            `json:"expensive,omitempty"` // e.g. "macOS runner", "matrix"
+}
+
+// WorkflowConcurrency is the concurrency configuration of one workflow file.
+type WorkflowConcurrency struct {
+	Workflow    string              `json:"workflow"`
+	Path        string              `json:"path"`
+	Triggers    []string            `json:"triggers,omitempty"`
+	Concurrency *ConcurrencySetting `json:"concurrency,omitempty"`
+	Jobs        []*JobConcurrency   `json:"jobs,omitempty"`      // Jobs with their own concurrency or that are expensive
+	Unguarded   []string            `json:"unguarded,omitempty"` // Expensive jobs with no concurrency control at any level
+	Suggestions []string            `json:"suggestions,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// ConcurrencyReport summarizes concurrency control across a repository's workflows.
+type ConcurrencyReport struct {
+	Ref           string                 `json:"ref,omitempty"`
+	Workflows     []*WorkflowConcurrency `json:"workflows"`
+	WithoutGroups []string               `json:"without_groups"` // Workflows with no concurrency at any level
+	Unguarded     []string               `json:"unguarded"`      // "workflow / job" for expensive jobs without concurrency
+	SharedGroups  map[string][]string    `json:"shared_groups,omitempty"`
+}
+
+// GetConcurrencyReport reads each workflow file at ref (default branch when empty) and
+// reports its concurrency groups and cancel-in-progress settings, the expensive jobs that
+// run without any concurrency control, and groups that several workflows share.
+func (c *Client) GetConcurrencyReport(ctx context.Context, ref string) (*ConcurrencyReport, error) {
+	workflows, _, err := c.gh.Actions.ListWorkflows(ctx, c.owner, c.repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows: %w", Classify(err))
+	}
+
+	var analyses []*WorkflowConcurrency
+	for _, wf := range workflows.Workflows {
+		// Dynamic workflows (code scanning, Dependabot, ...) have no file to read.
+		if !strings.HasPrefix(wf.GetPath(), ".github/workflows/") {
+			continue
+		}
+		data, err := c.GetWorkflowFile(ctx, wf.GetPath(), ref)
+		var analysis *WorkflowConcurrency
+		if err == nil {
+			analysis, err = AnalyzeWorkflowConcurrency(data)
+		}
+		if err != nil {
+			analysis = &WorkflowConcurrency{Error: err.Error()}
+		}
+		analysis.Workflow, analysis.Path = wf.GetName(), wf.GetPath()
+		analyses = append(analyses, analysis)
+	}
+
+	report := buildConcurrencyReport(analyses)
+	report.Ref = ref
+	return report, nil
+}
+
+// buildConcurrencyReport aggregates per-workflow analyses.
+func buildConcurrencyReport(analyses []*WorkflowConcurrency) *ConcurrencyReport {
+	report := &ConcurrencyReport{Workflows: analyses, WithoutGroups: []string{}, Unguarded: []string{}}
+	groups := make(map[string][]string)
+	for _, wc := range analyses {
+		if wc.Error != "" {
+			continue
+		}
+		seen := make(map[string]bool)
+		addGroup := func(s *ConcurrencySetting) {
+			// A group naming the workflow cannot collide with another workflow's.
+			if s == nil || strings.Contains(s.Group, "github.workflow") || seen[s.Group] {
+				return
+			}
+			seen[s.Group] = true
+			groups[s.Group] = append(groups[s.Group], wc.Workflow)
+		}
+		addGroup(wc.Concurrency)
+		hasGroup := wc.Concurrency != nil
+		for _, job := range wc.Jobs {
+			addGroup(job.Concurrency)
+			hasGroup = hasGroup || job.Concurrency != nil
+		}
+		if !hasGroup {
+			report.WithoutGroups = append(report.WithoutGroups, wc.Workflow)
+		}
+		for _, job := range wc.Unguarded {
+			report.Unguarded = append(report.Unguarded, wc.Workflow+" / "+job)
+		}
+	}
+	for group, workflows := range groups {
+		if len(workflows) > 1 {
+			if report.SharedGroups == nil {
+				report.SharedGroups = make(map[string][]string)
+			}
+			report.SharedGroups[group] = workflows
+		}
+	}
+	return report
+}
+
+// AnalyzeWorkflowConcurrency parses the workflow- and job-level concurrency of a workflow
+// file, flags expensive jobs (macOS, Windows, or larger runners, matrices, long timeouts,
+// deployments) that run without concurrency control, and suggests changes. The result's
+// Workflow and Path fields are left empty.
+func AnalyzeWorkflowConcurrency(data []byte) (*WorkflowConcurrency, error) {
+	var doc struct {
+		On          yaml.Node `yaml:"on"`
+		Concurrency yaml.Node `yaml:"concurrency"`
+		Jobs        yaml.Node `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+
+	wc := &WorkflowConcurrency{Triggers: workflowTriggers(&doc.On)}
+	var err error
+	if wc.Concurrency, err = parseConcurrency(&doc.Concurrency); err != nil {
+		return nil, fmt.Errorf("concurrency: %w", err)
+	}
+
+	for i := 0; i+1 < len(doc.Jobs.Content); i += 2 {
+		name := doc.Jobs.Content[i].Value
+		var def struct {
+			RunsOn         yaml.Node `yaml:"runs-on"`
+			Concurrency    yaml.Node `yaml:"concurrency"`
+			TimeoutMinutes yaml.Node `yaml:"timeout-minutes"`
+			Environment    yaml.Node `yaml:"environment"`
+			Strategy       struct {
+				Matrix yaml.Node `yaml:"matrix"`
+			} `yaml:"strategy"`
+		}
+		if err := doc.Jobs.Content[i+1].Decode(&def); err != nil {
+			return nil, fmt.Errorf("jobs.%s: %w", name, err)
+		}
+
+		job := &JobConcurrency{Job: name, Environment: environmentName(&def.Environment)}
+		if job.Concurrency, err = parseConcurrency(&def.Concurrency); err != nil {
+			return nil, fmt.Errorf("jobs.%s.concurrency: %w", name, err)
+		}
+		job.Expensive = runnerCosts(yamlStrings(&def.RunsOn))
+		if def.Strategy.Matrix.Kind != 0 {
+			job.Expensive = append(job.Expensive, "matrix")
+		}
+		if minutes, err := strconv.Atoi(def.TimeoutMinutes.Value); err == nil && minutes >= expensiveTimeoutMinutes {
+			job.Expensive = append(job.Expensive, fmt.Sprintf("timeout-minutes: %d", minutes))
+		}
+		if job.Environment != "" {
+			job.Expensive = append(job.Expensive, "deploys to "+job.Environment)
+		}
+
+		if job.Concurrency == nil && len(job.Expensive) == 0 {
+			continue
+		}
+		wc.Jobs = append(wc.Jobs, job)
+		if job.Concurrency == nil && wc.Concurrency == nil && len(job.Expensive) > 0 {
+			wc.Unguarded = append(wc.Unguarded, name)
+		}
+	}
+
+	wc.Suggestions = concurrencySuggestions(wc)
+	return wc, nil
+}
+
+// concurrencySuggestions proposes concurrency changes for a workflow.
+func concurrencySuggestions(wc *WorkflowConcurrency) []string {
+	var suggestions []string
+	onPushOrPR := false
+	for _, t := range wc.Triggers {
+		if t == "push" || t == "pull_request" || t == "pull_request_target" {
+			onPushOrPR = true
+		}
+	}
+
+	if len(wc.Unguarded) > 0 && onPushOrPR {
+		suggestions = append(suggestions, fmt.Sprintf("add a workflow-level concurrency block so superseded runs stop using runners for %s, e.g. group: ${{ github.workflow }}-${{ github.ref }} with cancel-in-progress: ${{ github.event_name == 'pull_request' }}", strings.Join(wc.Unguarded, ", ")))
+	}
+
+	check := func(s *ConcurrencySetting, where string) {
+		if s.cancels() && onPushOrPR && !strings.Contains(s.Group, "${{") {
+			suggestions = append(suggestions, fmt.Sprintf("%s group %q is the same for every branch, so with cancel-in-progress runs on different branches cancel each other; include ${{ github.ref }} in the group", where, s.Group))
+		}
+	}
+	if wc.Concurrency != nil {
+		check(wc.Concurrency, "the workflow's")
+	}
+	for _, job := range wc.Jobs {
+		if job.Concurrency != nil {
+			check(job.Concurrency, "job "+job.Job+"'s")
+		}
+		effective := job.Concurrency
+		if effective == nil {
+			effective = wc.Concurrency
+		}
+		if job.Environment != "" && effective.cancels() {
+			suggestions = append(suggestions, fmt.Sprintf("job %s deploys to %s but cancel-in-progress is enabled, so a newer run can cancel a deployment midway; give the job its own group with cancel-in-progress: false", job.Job, job.Environment))
+		}
+	}
+	return suggestions
+}
+
+// parseConcurrency parses a concurrency: value, either a group name or a mapping with
+// group and cancel-in-progress. It returns nil when the node is absent.
+func parseConcurrency(node *yaml.Node) (*ConcurrencySetting, error) {
+	switch node.Kind {
+	case 0:
+		return nil, nil
+	case yaml.ScalarNode:
+		return &ConcurrencySetting{Group: node.Value}, nil
+	case yaml.MappingNode:
+		s := &ConcurrencySetting{}
+		if _, v := yamlMappingValue(node, "group"); v != nil {
+			s.Group = v.Value
+		}
+		if _, v := yamlMappingValue(node, "cancel-in-progress"); v != nil {
+			s.CancelInProgress = v.Value
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("expected a group name or a mapping")
+}
+
+// workflowTriggers lists the events in a workflow's on: node.
+func workflowTriggers(on *yaml.Node) []string {
+	switch on.Kind {
+	case yaml.ScalarNode:
+		return []string{on.Value}
+	case yaml.SequenceNode:
+		return yamlStrings(on)
+	case yaml.MappingNode:
+		var triggers []string
+		for i := 0; i+1 < len(on.Content); i += 2 {
+			triggers = append(triggers, on.Content[i].Value)
+		}
+		sort.Strings(triggers)
+		return triggers
+	}
+	return nil
+}
+
+// environmentName returns a job's environment, given as a name or a mapping with a name.
+func environmentName(node *yaml.Node) string {
+	if node.Kind == yaml.MappingNode {
+		if _, v := yamlMappingValue(node, "name"); v != nil {
+			return v.Value
+		}
+		return ""
+	}
+	return node.Value
+}
+
+// yamlStrings returns the scalar values of a scalar or sequence node, or of the labels
+// of a runs-on mapping ({group, labels}).
+func yamlStrings(node *yaml.Node) []string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}
+	case yaml.SequenceNode:
+		var values []string
+		for _, n := range node.Content {
+			if n.Kind == yaml.ScalarNode {
+				values = append(values, n.Value)
+			}
+		}
+		return values
+	case yaml.MappingNode:
+		var values []string
+		if _, v := yamlMappingValue(node, "group"); v != nil {
+			values = append(values, v.Value)
+		}
+		if _, v := yamlMappingValue(node, "labels"); v != nil {
+			values = append(values, yamlStrings(v)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// runnerCosts describes runner labels that are billed above a standard Linux runner.
+func runnerCosts(labels []string) []string {
+	var costs []string
+	seen := make(map[string]bool)
+	add := func(cost string) {
+		if !seen[cost] {
+			seen[cost] = true
+			costs = append(costs, cost)
+		}
+	}
+	for _, label := range labels {
+		l := strings.ToLower(label)
+		switch {
+		case strings.Contains(l, "${{"):
+			// Resolved at run time, typically from a matrix, which is flagged separately.
+		case strings.Contains(l, "macos"):
+			add("macOS runner")
+		case strings.Contains(l, "windows"):
+			add("Windows runner")
+		case strings.Contains(l, "xlarge"), strings.Contains(l, "large"), strings.Contains(l, "-cores"), strings.Contains(l, "gpu"):
+			add("larger runner")
+		}
+	}
+	return costs
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeWorkflowConcurrency(t *testing.T) {
+	ci := []byte(`
+name: CI
+on:
+  push:
+    branches: [main]
+  pull_request:
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+  test:
+    runs-on: ${{ matrix.os }}
+    strategy:
+      matrix:
+        os: [ubuntu-latest, macos-latest]
+  ios:
+    runs-on: macos-14
+    timeout-minutes: 90
+`)
+	wc, err := AnalyzeWorkflowConcurrency(ci)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pull_request", "push"}, wc.Triggers)
+	assert.Nil(t, wc.Concurrency)
+	require.Len(t, wc.Jobs, 2)
+	assert.Equal(t, "test", wc.Jobs[0].Job)
+	assert.Equal(t, []string{"matrix"}, wc.Jobs[0].Expensive)
+	assert.Equal(t, []string{"macOS runner", "timeout-minutes: 90"}, wc.Jobs[1].Expensive)
+	assert.Equal(t, []string{"test", "ios"}, wc.Unguarded)
+	require.Len(t, wc.Suggestions, 1)
+	assert.Contains(t, wc.Suggestions[0], "workflow-level concurrency block")
+
+	deploy := []byte(`
+on: push
+concurrency:
+  group: deploy
+  cancel-in-progress: true
+jobs:
+  build:
+    runs-on: [self-hosted, linux, large]
+  release:
+    runs-on: ubuntu-latest
+    environment:
+      name: production
+`)
+	wc, err = AnalyzeWorkflowConcurrency(deploy)
+	require.NoError(t, err)
+	assert.Equal(t, &ConcurrencySetting{Group: "deploy", CancelInProgress: "true"}, wc.Concurrency)
+	assert.Empty(t, wc.Unguarded)
+	require.Len(t, wc.Jobs, 2)
+	assert.Equal(t, []string{"larger runner"}, wc.Jobs[0].Expensive)
+	assert.Equal(t, "production", wc.Jobs[1].Environment)
+	require.Len(t, wc.Suggestions, 2)
+	assert.Contains(t, wc.Suggestions[0], `group "deploy" is the same for every branch`)
+	assert.Contains(t, wc.Suggestions[1], "job release deploys to production")
+
+	_, err = AnalyzeWorkflowConcurrency([]byte("on: push\nconcurrency: [a, b]\n"))
+	assert.Error(t, err)
+}
+
+func TestBuildConcurrencyReport(t *testing.T) {
+	report := buildConcurrencyReport([]*WorkflowConcurrency{
+		{Workflow: "CI", Unguarded: []string{"test"}},
+		{Workflow: "Deploy", Concurrency: &ConcurrencySetting{Group: "production"}},
+		{Workflow: "Hotfix", Jobs: []*JobConcurrency{{Job: "ship", Concurrency: &ConcurrencySetting{Group: "production"}}}},
+		{Workflow: "Docs", Concurrency: &ConcurrencySetting{Group: "${{ github.workflow }}-${{ github.ref }}"}},
+		{Workflow: "Broken", Error: "failed to parse workflow YAML"},
+	})
+	assert.Equal(t, []string{"CI"}, report.WithoutGroups)
+	assert.Equal(t, []string{"CI / test"}, report.Unguarded)
+	assert.Equal(t, map[string][]string{"production": {"Deploy", "Hotfix"}}, report.SharedGroups)
+}
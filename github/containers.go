@@ -0,0 +1,184 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ContainerSpec is a job's container: or one of its services: entries.
+type ContainerSpec struct {
+	Service     string   `json:"service,omitempty"` // Service ID; empty for the job container
+	Image       string   `json:"image"`
+	Ports       []string `json:"ports,omitempty"`
+	Options     string   `json:"options,omitempty"`
+	Credentials bool     `json:"credentials,omitempty"` // Registry credentials are configured
+}
+
+// JobContainers lists the job container and service containers of one job.
+type JobContainers struct {
+	Job       string           `json:"job"`
+	Container *ContainerSpec   `json:"container,omitempty"`
+	Services  []*ContainerSpec `json:"services,omitempty"`
+}
+
+// containerErrorPatterns match log lines about pulling or starting job and service
+// containers: missing or private images, registry rate limits, and failed health checks.
+var containerErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`Error response from daemon`),
+	regexp.MustCompile(`(?i)pull access denied`),
+	regexp.MustCompile(`(?i)manifest (unknown|for .* not found)`),
+	regexp.MustCompile(`(?i)toomanyrequests`),
+	regexp.MustCompile(`(?i)failed to pull|docker pull failed`),
+	regexp.MustCompile(`(?i)failed to initialize (container|service)`),
+	regexp.MustCompile(`(?i)service container .* failed`),
+	regexp.MustCompile(`(?i)container .* is unhealthy`),
+	regexp.MustCompile(`(?i)docker: Error`),
+	regexp.MustCompile(`(?i)unauthorized: authentication required`),
+}
+
+// ParseJobContainers returns the container: and services: definitions of each job that
+// declares any, in file order.
+func ParseJobContainers(data []byte) ([]*JobContainers, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("workflow must be a YAML mapping")
+	}
+	_, jobs := yamlMappingValue(doc.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	var result []*JobContainers
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		job := jobs.Content[i+1]
+		if job.Kind != yaml.MappingNode {
+			continue
+		}
+		jc := &JobContainers{Job: jobs.Content[i].Value}
+		if _, container := yamlMappingValue(job, "container"); container != nil {
+			jc.Container = parseContainerSpec(container)
+		}
+		if _, services := yamlMappingValue(job, "services"); services != nil && services.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(services.Content); j += 2 {
+				spec := parseContainerSpec(services.Content[j+1])
+				spec.Service = services.Content[j].Value
+				jc.Services = append(jc.Services, spec)
+			}
+		}
+		if jc.Container != nil || len(jc.Services) > 0 {
+			result = append(result, jc)
+		}
+	}
+	return result, nil
+}
+
+// parseContainerSpec parses a container definition, either an image name or a mapping
+// with image, ports, options, and credentials.
+func parseContainerSpec(node *yaml.Node) *ContainerSpec {
+	spec := &ContainerSpec{}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		spec.Image = node.Value
+	case yaml.MappingNode:
+		if _, v := yamlMappingValue(node, "image"); v != nil {
+			spec.Image = v.Value
+		}
+		if _, v := yamlMappingValue(node, "ports"); v != nil {
+			spec.Ports = yamlStrings(v)
+		}
+		if _, v := yamlMappingValue(node, "options"); v != nil {
+			spec.Options = v.Value
+		}
+		_, creds := yamlMappingValue(node, "credentials")
+		spec.Credentials = creds != nil
+	}
+	return spec
+}
+
+// checkJobContainers reports container: and services: problems in a job: a missing image
+// is an error, and an untagged or :latest image, or containers on a runner that is not
+// Linux, a warning.
+func checkJobContainers(id string, job *yaml.Node) []*WorkflowIssue {
+	var issues []*WorkflowIssue
+	add := func(node *yaml.Node, severity, format string, args ...interface{}) {
+		issues = append(issues, &WorkflowIssue{
+			Line: node.Line, Column: node.Column, Severity: severity, Kind: "container",
+			Message: fmt.Sprintf(format, args...), Source: "yaml",
+		})
+	}
+	check := func(what string, node *yaml.Node) {
+		spec := parseContainerSpec(node)
+		image := spec.Image
+		switch {
+		case image == "":
+			add(node, "error", "%s of job %q has no image", what, id)
+		case strings.Contains(image, "${{"):
+			// Set by an expression; nothing to check statically.
+		case !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") && !strings.Contains(image, "@"):
+			add(node, "warning", "%s of job %q uses untagged image %q; pin a tag so the image cannot change under the job", what, id, image)
+		case strings.HasSuffix(image, ":latest"):
+			add(node, "warning", "%s of job %q uses %q; pin a tag so the image cannot change under the job", what, id, image)
+		}
+	}
+
+	containerKey, container := yamlMappingValue(job, "container")
+	if container != nil {
+		check("container", container)
+	}
+	servicesKey, services := yamlMappingValue(job, "services")
+	if services != nil {
+		if services.Kind != yaml.MappingNode {
+			add(servicesKey, "error", "services of job %q must be a mapping", id)
+		} else {
+			for i := 0; i+1 < len(services.Content); i += 2 {
+				check(fmt.Sprintf("service %q", services.Content[i].Value), services.Content[i+1])
+			}
+		}
+	}
+
+	if container == nil && services == nil {
+		return issues
+	}
+	key := containerKey
+	if key == nil {
+		key = servicesKey
+	}
+	if _, runsOn := yamlMappingValue(job, "runs-on"); runsOn != nil {
+		for _, label := range yamlStrings(runsOn) {
+			l := strings.ToLower(label)
+			if strings.HasPrefix(l, "macos") || strings.HasPrefix(l, "windows") {
+				add(key, "warning", "job %q runs on %q, but job and service containers need a Linux runner", id, label)
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// ExtractContainerErrors returns up to maxLines log lines reporting failures to pull or
+// start the job's container or service containers.
+func ExtractContainerErrors(logs string, maxLines int) []string {
+	var lines []string
+	for _, line := range strings.Split(logs, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		for _, pattern := range containerErrorPatterns {
+			if pattern.MatchString(trimmed) {
+				lines = append(lines, trimmed)
+				break
+			}
+		}
+		if len(lines) >= maxLines {
+			break
+		}
+	}
+	return lines
+}
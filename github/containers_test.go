@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const containerWorkflow = `
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    container: node:20
+    services:
+      postgres:
+        image: postgres
+        ports: ["5432:5432"]
+        options: --health-cmd pg_isready
+      redis:
+        image: ghcr.io/acme/redis:latest
+        credentials:
+          username: ${{ github.actor }}
+          password: ${{ secrets.GITHUB_TOKEN }}
+    steps:
+      - run: npm test
+  mac:
+    runs-on: macos-latest
+    services:
+      cache:
+        ports: [6379]
+    steps:
+      - run: echo hi
+  plain:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hi
+`
+
+func TestParseJobContainers(t *testing.T) {
+	containers, err := ParseJobContainers([]byte(containerWorkflow))
+	require.NoError(t, err)
+	require.Len(t, containers, 2)
+
+	assert.Equal(t, "test", containers[0].Job)
+	assert.Equal(t, &ContainerSpec{Image: "node:20"}, containers[0].Container)
+	assert.Equal(t, []*ContainerSpec{
+		{Service: "postgres", Image: "postgres", Ports: []string{"5432:5432"}, Options: "--health-cmd pg_isready"},
+		{Service: "redis", Image: "ghcr.io/acme/redis:latest", Credentials: true},
+	}, containers[0].Services)
+	assert.Equal(t, "mac", containers[1].Job)
+	assert.Nil(t, containers[1].Container)
+}
+
+func TestValidateWorkflowYAML_Containers(t *testing.T) {
+	result := ValidateWorkflowYAML(context.Background(), []byte(containerWorkflow), "ci.yml", false)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Containers, 2)
+
+	var messages []string
+	for _, issue := range result.Issues {
+		assert.Equal(t, "container", issue.Kind)
+		messages = append(messages, issue.Severity+": "+issue.Message)
+	}
+	assert.Equal(t, []string{
+		`warning: service "postgres" of job "test" uses untagged image "postgres"; pin a tag so the image cannot change under the job`,
+		`warning: service "redis" of job "test" uses "ghcr.io/acme/redis:latest"; pin a tag so the image cannot change under the job`,
+		`warning: job "mac" runs on "macos-latest", but job and service containers need a Linux runner`,
+		`error: service "cache" of job "mac" has no image`,
+	}, messages)
+}
+
+func TestExtractContainerErrors(t *testing.T) {
+	logs := `2024-01-01T00:00:00.0000000Z ##[group]Starting postgres service container
+2024-01-01T00:00:01.0000000Z Error response from daemon: pull access denied for postgress, repository does not exist
+2024-01-01T00:00:02.0000000Z ##[error]Docker pull failed with exit code 1
+2024-01-01T00:00:03.0000000Z npm ERR! test failed
+2024-01-01T00:00:04.0000000Z ##[error]Failed to initialize container postgres
+`
+	assert.Equal(t, []string{
+		"2024-01-01T00:00:01.0000000Z Error response from daemon: pull access denied for postgress, repository does not exist",
+		"2024-01-01T00:00:02.0000000Z ##[error]Docker pull failed with exit code 1",
+		"2024-01-01T00:00:04.0000000Z ##[error]Failed to initialize container postgres",
+	}, ExtractContainerErrors(logs, 10))
+	assert.Len(t, ExtractContainerErrors(logs, 1), 1)
+}
@@ -0,0 +1,189 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// DefaultDependencyBots are the accounts whose pull requests count as dependency updates
+// when no bots are given.
+var DefaultDependencyBots = []string{"dependabot[bot]", "renovate[bot]"}
+
+// dependencyBranchPrefixes identify dependency-update branches opened by bots running
+// under a regular account, such as self-hosted Renovate.
+var dependencyBranchPrefixes = []string{"dependabot/", "renovate/"}
+
+// DependencyPR is an open dependency-update pull request with failing checks.
+type DependencyPR struct {
+	Number       int      `json:"number"`
+	Title        string   `json:"title"`
+	Author       string   `json:"author"`
+	HeadBranch   string   `json:"head_branch"`
+	HeadSHA      string   `json:"head_sha"`
+	URL          string   `json:"url"`
+	FailedChecks []string `json:"failed_checks"`
+	RunIDs       []int64  `json:"run_ids,omitempty"` // Workflow runs with failed jobs, for rerun_failed
+}
+
+// DependencyPRGroup is a set of dependency PRs failing the same checks.
+type DependencyPRGroup struct {
+	Signature string          `json:"signature"` // The failing checks, sorted and comma-separated
+	Count     int             `json:"count"`
+	PRs       []*DependencyPR `json:"prs"`
+}
+
+// DependencyPRTriage summarizes the CI state of open dependency-update pull requests.
+type DependencyPRTriage struct {
+	Bots    []string             `json:"bots"`
+	Open    int                  `json:"open"`
+	Passing int                  `json:"passing"`
+	Pending int                  `json:"pending"`
+	Failing int                  `json:"failing"`
+	Groups  []*DependencyPRGroup `json:"groups"` // Largest first
+	Notes   []string             `json:"notes,omitempty"`
+}
+
+// RunIDs returns the distinct workflow runs with failed jobs across the group's PRs.
+func (g *DependencyPRGroup) RunIDs() []int64 {
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, pr := range g.PRs {
+		for _, id := range pr.RunIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// GetDependencyPRTriage lists the open pull requests opened by bots (default:
+// DefaultDependencyBots) or on dependabot/ and renovate/ branches, checks the latest check
+// runs of each head commit, and groups the failing PRs by the set of checks that fail, so a
+// check failing across many PRs (often a flake or a broken base branch) stands out.
+func (c *Client) GetDependencyPRTriage(ctx context.Context, bots []string) (*DependencyPRTriage, error) {
+	if len(bots) == 0 {
+		bots = DefaultDependencyBots
+	}
+	botSet := make(map[string]bool, len(bots))
+	for _, b := range bots {
+		botSet[strings.ToLower(b)] = true
+	}
+
+	triage := &DependencyPRTriage{Bots: bots, Groups: []*DependencyPRGroup{}}
+	opts := &github.PullRequestListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	var prs []*github.PullRequest
+	for {
+		page, resp, err := c.gh.PullRequests.List(ctx, c.owner, c.repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", Classify(err))
+		}
+		for _, pr := range page {
+			if isDependencyPR(pr, botSet) {
+				prs = append(prs, pr)
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	triage.Open = len(prs)
+
+	groups := make(map[string]*DependencyPRGroup)
+	for _, pr := range prs {
+		checkRuns, _, err := c.gh.Checks.ListCheckRunsForRef(ctx, c.owner, c.repo, pr.GetHead().GetSHA(), &github.ListCheckRunsOptions{
+			Filter:      github.Ptr("latest"),
+			ListOptions: github.ListOptions{PerPage: 100},
+		})
+		if err != nil {
+			triage.Notes = append(triage.Notes, fmt.Sprintf("could not list checks of #%d: %v", pr.GetNumber(), Classify(err)))
+			continue
+		}
+
+		dep, pending := dependencyPRFromChecks(pr, checkRuns.CheckRuns)
+		switch {
+		case dep != nil:
+			triage.Failing++
+			g, ok := groups[dep.signature()]
+			if !ok {
+				g = &DependencyPRGroup{Signature: dep.signature()}
+				groups[g.Signature] = g
+				triage.Groups = append(triage.Groups, g)
+			}
+			g.PRs = append(g.PRs, dep)
+			g.Count++
+		case pending:
+			triage.Pending++
+		default:
+			triage.Passing++
+		}
+	}
+
+	sort.SliceStable(triage.Groups, func(i, j int) bool {
+		return triage.Groups[i].Count > triage.Groups[j].Count
+	})
+	return triage, nil
+}
+
+// isDependencyPR reports whether a pull request was opened by one of the bots or from a
+// dependency-update branch.
+func isDependencyPR(pr *github.PullRequest, bots map[string]bool) bool {
+	if bots[strings.ToLower(pr.GetUser().GetLogin())] {
+		return true
+	}
+	for _, prefix := range dependencyBranchPrefixes {
+		if strings.HasPrefix(pr.GetHead().GetRef(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dependencyPRFromChecks returns the PR with its failing checks, or nil and whether any
+// check is still running when none has failed.
+func dependencyPRFromChecks(pr *github.PullRequest, checkRuns []*github.CheckRun) (*DependencyPR, bool) {
+	dep := &DependencyPR{
+		Number:     pr.GetNumber(),
+		Title:      pr.GetTitle(),
+		Author:     pr.GetUser().GetLogin(),
+		HeadBranch: pr.GetHead().GetRef(),
+		HeadSHA:    pr.GetHead().GetSHA(),
+		URL:        pr.GetHTMLURL(),
+	}
+	pending := false
+	seenRuns := make(map[int64]bool)
+	for _, cr := range checkRuns {
+		if cr.GetStatus() != "completed" {
+			pending = true
+			continue
+		}
+		if !isFailureConclusion(cr.GetConclusion()) {
+			continue
+		}
+		dep.FailedChecks = append(dep.FailedChecks, cr.GetName())
+		if m := actionsJobURLPattern.FindStringSubmatch(cr.GetDetailsURL()); m != nil {
+			runID, _ := strconv.ParseInt(m[1], 10, 64)
+			if runID != 0 && !seenRuns[runID] {
+				seenRuns[runID] = true
+				dep.RunIDs = append(dep.RunIDs, runID)
+			}
+		}
+	}
+	if len(dep.FailedChecks) == 0 {
+		return nil, pending
+	}
+	sort.Strings(dep.FailedChecks)
+	return dep, pending
+}
+
+// signature identifies the PR's failure by the checks that fail.
+func (d *DependencyPR) signature() string {
+	return strings.Join(d.FailedChecks, ", ")
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDependencyPRTriage_GroupsByFailingChecks(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/pulls", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"number": 1, "title": "Bump lodash", "user": {"login": "dependabot[bot]"}, "head": {"ref": "dependabot/npm/lodash", "sha": "sha1"}},
+			{"number": 2, "title": "Bump axios", "user": {"login": "dependabot[bot]"}, "head": {"ref": "dependabot/npm/axios", "sha": "sha2"}},
+			{"number": 3, "title": "Update go modules", "user": {"login": "ci-robot"}, "head": {"ref": "renovate/go", "sha": "sha3"}},
+			{"number": 4, "title": "Add feature", "user": {"login": "alice"}, "head": {"ref": "feature", "sha": "sha4"}},
+			{"number": 5, "title": "Bump react", "user": {"login": "renovate[bot]"}, "head": {"ref": "deps/react", "sha": "sha5"}},
+			{"number": 6, "title": "Bump vite", "user": {"login": "dependabot[bot]"}, "head": {"ref": "dependabot/npm/vite", "sha": "sha6"}}
+		]`))
+	})
+	checks := map[string]string{
+		"sha1": `[{"name": "test", "status": "completed", "conclusion": "failure", "details_url": "https://github.com/test-owner/test-repo/actions/runs/101/job/1"},
+			{"name": "lint", "status": "completed", "conclusion": "success"}]`,
+		"sha2": `[{"name": "test", "status": "completed", "conclusion": "timed_out", "details_url": "https://github.com/test-owner/test-repo/actions/runs/102/job/2"}]`,
+		"sha3": `[{"name": "test", "status": "completed", "conclusion": "failure", "details_url": "https://github.com/test-owner/test-repo/actions/runs/103/job/3"},
+			{"name": "lint", "status": "completed", "conclusion": "failure", "details_url": "https://github.com/test-owner/test-repo/actions/runs/103/job/4"}]`,
+		"sha5": `[{"name": "test", "status": "in_progress"}]`,
+		"sha6": `[{"name": "test", "status": "completed", "conclusion": "success"}]`,
+	}
+	for sha, runs := range checks {
+		runs := runs
+		mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/"+sha+"/check-runs", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"total_count": 1, "check_runs": ` + runs + `}`))
+		})
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	triage, err := client.GetDependencyPRTriage(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5, triage.Open)
+	assert.Equal(t, 3, triage.Failing)
+	assert.Equal(t, 1, triage.Pending)
+	assert.Equal(t, 1, triage.Passing)
+
+	require.Len(t, triage.Groups, 2)
+	assert.Equal(t, "test", triage.Groups[0].Signature)
+	assert.Equal(t, 2, triage.Groups[0].Count)
+	assert.Equal(t, []int64{101, 102}, triage.Groups[0].RunIDs())
+	assert.Equal(t, "lint, test", triage.Groups[1].Signature)
+	assert.Equal(t, 3, triage.Groups[1].PRs[0].Number)
+	assert.Equal(t, []int64{103}, triage.Groups[1].RunIDs())
+}
@@ -0,0 +1,341 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// dispatchPollInterval is how often DispatchWorkflow polls for the run it created.
+var dispatchPollInterval = 3 * time.Second
+
+const (
+	// defaultDispatchDiscoveryTimeout bounds how long DispatchWorkflow looks for the new run.
+	defaultDispatchDiscoveryTimeout = 60 * time.Second
+	// dispatchClockSkew tolerates drift between the local clock and GitHub's timestamps.
+	dispatchClockSkew = 10 * time.Second
+)
+
+// Sources of the ref of a dispatch that was given none.
+const (
+	RefSourceWorkflowDefault = "workflow_default_refs"
+	RefSourceDefaultBranch   = "default_branch"
+)
+
+// DispatchOptions configures a workflow_dispatch trigger.
+type DispatchOptions struct {
+	Workflow         string                 // Workflow name, path, or numeric ID
+	Ref              string                 // Branch or tag to run on; defaults to the workflow's configured default ref, then the repository's default branch
+	Inputs           map[string]interface{} // workflow_dispatch inputs
+	CorrelationInput string                 // Optional: input to fill with a unique marker when the workflow declares it
+	DiscoveryTimeout time.Duration          // How long to look for the created run (default: 60s)
+	EnableIfDisabled bool                   // Enable the workflow first if it is disabled
+	LocalDir         string                 // Optional: root of a local checkout of the repository, to warn about unpushed edits of the workflow
+	Target           *DispatchTarget        // Optional: the result of ResolveDispatchTarget for these options, so it is not resolved again
+}
+
+// DispatchTarget is the workflow and ref a dispatch runs, as resolved by ResolveDispatchTarget.
+type DispatchTarget struct {
+	WorkflowID    int64
+	WorkflowName  string
+	WorkflowPath  string
+	WorkflowState string
+	Ref           string
+	RefSource     string // Where the ref came from when none was given: workflow_default_refs or default_branch
+}
+
+// DispatchResult describes a dispatched workflow and the run it created.
+type DispatchResult struct {
+	WorkflowID    int64                  `json:"workflow_id"`
+	WorkflowName  string                 `json:"workflow_name"`
+	WorkflowPath  string                 `json:"workflow_path,omitempty"`
+	Ref           string                 `json:"ref"`
+	RefSource     string                 `json:"ref_source,omitempty"` // Where the ref came from when none was given: workflow_default_refs or default_branch
+	Inputs        map[string]interface{} `json:"inputs,omitempty"`
+	ReusedInputs  []string               `json:"reused_inputs,omitempty"` // inputs filled in from the workflow's last dispatch
+	Enabled       bool                   `json:"enabled,omitempty"`       // the workflow was disabled and enabled for this dispatch
+	DispatchedAt  string                 `json:"dispatched_at"`
+	Actor         string                 `json:"actor,omitempty"`
+	CorrelationID string                 `json:"correlation_id,omitempty"`
+	RunID         int64                  `json:"run_id,omitempty"`
+	Run           *WorkflowRun           `json:"run,omitempty"`
+	MatchedBy     string                 `json:"matched_by,omitempty"` // correlation_id, only_candidate, earliest_candidate
+	Candidates    []int64                `json:"candidates,omitempty"`
+	Warnings      []string               `json:"warnings,omitempty"`
+}
+
+// DispatchWorkflow triggers a workflow_dispatch event and identifies the run it created.
+//
+// Runs that already existed before the dispatch are ignored. Remaining candidates must be
+// workflow_dispatch runs on the same ref, created after the dispatch, by the same actor.
+// When CorrelationInput names an input the workflow declares, a unique marker is injected
+// and used to pick the run by its display title (which requires the workflow's run-name to
+// reference that input). If the run is not found in time, the result has no RunID and a warning.
+//
+// A disabled workflow cannot be dispatched: the call fails with ErrWorkflowDisabled unless
+// EnableIfDisabled is set, in which case the workflow is enabled first.
+//
+// A dispatch runs the workflow file at the ref, not a local copy. With LocalDir, a local
+// copy that differs from it is reported in a warning, since the local edits will not run.
+func (c *Client) DispatchWorkflow(ctx context.Context, opts DispatchOptions) (*DispatchResult, error) {
+	target := opts.Target
+	if target == nil {
+		var err error
+		if target, err = c.ResolveDispatchTarget(ctx, opts); err != nil {
+			return nil, err
+		}
+	}
+	workflowID, state := target.WorkflowID, target.WorkflowState
+
+	result := &DispatchResult{
+		WorkflowID:   workflowID,
+		WorkflowName: target.WorkflowName,
+		WorkflowPath: target.WorkflowPath,
+		Ref:          target.Ref,
+		RefSource:    target.RefSource,
+	}
+
+	if opts.LocalDir != "" && result.WorkflowPath != "" {
+		if warning := c.localWorkflowDrift(ctx, opts.LocalDir, result.WorkflowPath, result.Ref); warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+
+	if isWorkflowDisabled(state) {
+		if _, err := c.gh.Actions.EnableWorkflowByID(ctx, c.owner, c.repo, workflowID); err != nil {
+			return nil, fmt.Errorf("failed to enable workflow %s: %w", opts.Workflow, Classify(err))
+		}
+		log.Infof("Enabled workflow %s (was %s)", result.WorkflowPath, state)
+		result.Enabled = true
+	}
+
+	inputs := make(map[string]interface{}, len(opts.Inputs)+1)
+	for k, v := range opts.Inputs {
+		inputs[k] = v
+	}
+
+	titleCarriesMarker := false
+	if opts.CorrelationInput != "" && result.WorkflowPath != "" {
+		info, err := c.GetWorkflowDispatchInfo(ctx, result.WorkflowPath, result.Ref)
+		switch {
+		case err != nil:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("could not read %s to check for input %q: %v", result.WorkflowPath, opts.CorrelationInput, err))
+		case !info.Dispatchable:
+			return nil, fmt.Errorf("failed to trigger workflow %s: %w: %s at %s", opts.Workflow, ErrNoDispatchTrigger, result.WorkflowPath, result.Ref)
+		case info.Input(opts.CorrelationInput) == nil:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("workflow does not declare input %q; identifying the run by ref, actor, and time", opts.CorrelationInput))
+		default:
+			if existing, ok := inputs[opts.CorrelationInput]; ok {
+				result.CorrelationID = fmt.Sprint(existing)
+			} else {
+				result.CorrelationID = newCorrelationID()
+				inputs[opts.CorrelationInput] = result.CorrelationID
+			}
+			titleCarriesMarker = strings.Contains(info.RunName, opts.CorrelationInput)
+		}
+	}
+	if len(inputs) > 0 {
+		result.Inputs = inputs
+	}
+
+	if user, _, err := c.gh.Users.Get(ctx, ""); err == nil {
+		result.Actor = user.GetLogin()
+	} else {
+		log.Debugf("Could not determine authenticated user: %v", err)
+	}
+
+	branch := strings.TrimPrefix(strings.TrimPrefix(result.Ref, "refs/heads/"), "refs/tags/")
+	existing := make(map[int64]bool)
+	if runs, err := c.listDispatchRuns(ctx, workflowID, branch, ""); err == nil {
+		for _, r := range runs {
+			existing[r.GetID()] = true
+		}
+	} else {
+		log.Debugf("Could not snapshot existing runs: %v", err)
+	}
+
+	dispatchedAt := time.Now().UTC()
+	result.DispatchedAt = dispatchedAt.Format(time.RFC3339)
+
+	_, err := c.gh.Actions.CreateWorkflowDispatchEventByID(ctx, c.owner, c.repo, workflowID, github.CreateWorkflowDispatchEventRequest{
+		Ref:    result.Ref,
+		Inputs: result.Inputs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to trigger workflow %s: %w", opts.Workflow, Classify(err))
+	}
+
+	timeout := opts.DiscoveryTimeout
+	if timeout <= 0 {
+		timeout = defaultDispatchDiscoveryTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	createdFilter := ">=" + dispatchedAt.Add(-dispatchClockSkew).Format(time.RFC3339)
+
+	for {
+		runs, err := c.listDispatchRuns(ctx, workflowID, branch, createdFilter)
+		if err != nil {
+			log.Debugf("Could not list runs while looking for dispatched run: %v", err)
+		} else if run, matchedBy, candidates := selectDispatchedRun(runs, existing, dispatchedAt, result.Actor, result.CorrelationID, titleCarriesMarker); run != nil {
+			result.RunID = run.GetID()
+			result.Run = workflowRunFromGitHub(run)
+			result.MatchedBy = matchedBy
+			if len(candidates) > 1 {
+				result.Candidates = candidates
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%d runs matched the dispatch; picked the earliest", len(candidates)))
+			}
+			return result, nil
+		}
+
+		if time.Now().After(deadline) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("dispatch accepted but the new run did not appear within %s", timeout))
+			return result, nil
+		}
+
+		timer := time.NewTimer(dispatchPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// ResolveDispatchTarget resolves the workflow of opts and the ref it is dispatched on:
+// opts.Ref when set, else the workflow's configured default ref, else the repository's
+// default branch. It fails when the ref is not allowed, and with ErrWorkflowDisabled when
+// the workflow is disabled and opts.EnableIfDisabled is not set.
+func (c *Client) ResolveDispatchTarget(ctx context.Context, opts DispatchOptions) (*DispatchTarget, error) {
+	if opts.Ref != "" {
+		if err := c.checkRefAllowed(opts.Ref); err != nil {
+			return nil, fmt.Errorf("failed to trigger workflow %s: %w", opts.Workflow, err)
+		}
+	}
+
+	workflowID, workflowName, err := c.ResolveWorkflowID(ctx, opts.Workflow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to trigger workflow %s: %w", opts.Workflow, err)
+	}
+
+	target := &DispatchTarget{
+		WorkflowID:   workflowID,
+		WorkflowName: workflowName,
+		Ref:          opts.Ref,
+	}
+
+	if wf, _, err := c.gh.Actions.GetWorkflowByID(ctx, c.owner, c.repo, workflowID); err == nil {
+		target.WorkflowPath = wf.GetPath()
+		target.WorkflowState = wf.GetState()
+		if isWorkflowDisabled(target.WorkflowState) && !opts.EnableIfDisabled {
+			return nil, fmt.Errorf("failed to trigger workflow %s: %w: %s", opts.Workflow, ErrWorkflowDisabled, workflowDisabledReason(target.WorkflowState))
+		}
+	} else {
+		log.Debugf("Could not get workflow %d: %v", workflowID, err)
+	}
+
+	if target.Ref == "" {
+		if ref := c.defaultRefFor(opts.Workflow, target.WorkflowPath, path.Base(target.WorkflowPath), workflowName); ref != "" {
+			target.Ref, target.RefSource = ref, RefSourceWorkflowDefault
+			if err := c.checkRefAllowed(target.Ref); err != nil {
+				return nil, fmt.Errorf("failed to trigger workflow %s: %w", opts.Workflow, err)
+			}
+		}
+	}
+	if target.Ref == "" {
+		target.RefSource = RefSourceDefaultBranch
+		repository, _, err := c.gh.Repositories.Get(ctx, c.owner, c.repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine default branch: %w", err)
+		}
+		target.Ref = repository.GetDefaultBranch()
+		if err := c.checkRefAllowed(target.Ref); err != nil {
+			return nil, fmt.Errorf("failed to trigger workflow %s: %w", opts.Workflow, err)
+		}
+	}
+	return target, nil
+}
+
+func (c *Client) listDispatchRuns(ctx context.Context, workflowID int64, branch, created string) ([]*github.WorkflowRun, error) {
+	runs, _, err := c.gh.Actions.ListWorkflowRunsByID(ctx, c.owner, c.repo, workflowID, &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		Event:       "workflow_dispatch",
+		Created:     created,
+		ListOptions: github.ListOptions{PerPage: 20},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return runs.WorkflowRuns, nil
+}
+
+// selectDispatchedRun picks the run created by a dispatch out of the listed runs.
+// It returns the chosen run, how it was matched, and the IDs of all candidates.
+func selectDispatchedRun(runs []*github.WorkflowRun, existing map[int64]bool, dispatchedAt time.Time, actor, correlationID string, titleCarriesMarker bool) (*github.WorkflowRun, string, []int64) {
+	var candidates []*github.WorkflowRun
+	for _, r := range runs {
+		if existing[r.GetID()] {
+			continue
+		}
+		if r.GetEvent() != "" && r.GetEvent() != "workflow_dispatch" {
+			continue
+		}
+		if r.CreatedAt != nil && r.GetCreatedAt().Before(dispatchedAt.Add(-dispatchClockSkew)) {
+			continue
+		}
+		if actor != "" && !strings.EqualFold(dispatchRunActor(r), actor) {
+			continue
+		}
+		candidates = append(candidates, r)
+	}
+	if len(candidates) == 0 {
+		return nil, "", nil
+	}
+
+	if correlationID != "" {
+		for _, r := range candidates {
+			if strings.Contains(r.GetDisplayTitle(), correlationID) || strings.Contains(r.GetName(), correlationID) {
+				return r, "correlation_id", nil
+			}
+		}
+		if titleCarriesMarker {
+			// The title will carry the marker once GitHub renders it; keep waiting for it.
+			return nil, "", nil
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].GetCreatedAt().Before(candidates[j].GetCreatedAt().Time)
+	})
+	if len(candidates) == 1 {
+		return candidates[0], "only_candidate", nil
+	}
+
+	ids := make([]int64, 0, len(candidates))
+	for _, r := range candidates {
+		ids = append(ids, r.GetID())
+	}
+	return candidates[0], "earliest_candidate", ids
+}
+
+func dispatchRunActor(r *github.WorkflowRun) string {
+	if login := r.GetTriggeringActor().GetLogin(); login != "" {
+		return login
+	}
+	return r.GetActor().GetLogin()
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,405 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkflowDispatchInfo(t *testing.T) {
+	t.Run("map trigger with inputs", func(t *testing.T) {
+		info, err := ParseWorkflowDispatchInfo([]byte(`
+name: Deploy
+run-name: Deploy ${{ inputs.environment }} (${{ inputs.correlation_id }})
+on:
+  push:
+    branches: [main]
+  workflow_dispatch:
+    inputs:
+      environment:
+        description: Target environment
+        required: true
+        type: choice
+        options: [staging, production]
+      correlation_id:
+        type: string
+      dry_run:
+        type: boolean
+        default: false
+`))
+		require.NoError(t, err)
+		assert.True(t, info.Dispatchable)
+		require.Len(t, info.Inputs, 3)
+		assert.Equal(t, "correlation_id", info.Inputs[0].Name)
+		assert.Equal(t, "false", info.Input("dry_run").Default)
+		env := info.Input("environment")
+		require.NotNil(t, env)
+		assert.True(t, env.Required)
+		assert.Equal(t, "choice", env.Type)
+		assert.Equal(t, []string{"staging", "production"}, env.Options)
+		assert.Contains(t, info.RunName, "inputs.correlation_id")
+	})
+
+	t.Run("string and list triggers", func(t *testing.T) {
+		info, err := ParseWorkflowDispatchInfo([]byte("on: workflow_dispatch\n"))
+		require.NoError(t, err)
+		assert.True(t, info.Dispatchable)
+
+		info, err = ParseWorkflowDispatchInfo([]byte("on: [push, pull_request]\n"))
+		require.NoError(t, err)
+		assert.False(t, info.Dispatchable)
+	})
+}
+
+func TestWorkflowDispatchInfo_InputSchema(t *testing.T) {
+	info, err := ParseWorkflowDispatchInfo([]byte(`
+on:
+  workflow_dispatch:
+    inputs:
+      target:
+        description: Target environment
+        required: true
+        type: choice
+        options: [staging, production]
+      dry_run:
+        type: boolean
+        default: true
+      shards:
+        type: number
+        default: 4
+      deploy_to:
+        type: environment
+      note:
+        default: nightly
+`))
+	require.NoError(t, err)
+
+	schema := info.InputSchema("Deploy")
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Deploy",
+		"type": "object",
+		"properties": {
+			"target": {"type": "string", "description": "Target environment", "enum": ["staging", "production"]},
+			"dry_run": {"type": "boolean", "default": true},
+			"shards": {"type": "number", "default": 4},
+			"deploy_to": {"type": "string", "x-github-input-type": "environment"},
+			"note": {"type": "string", "default": "nightly"}
+		},
+		"required": ["target"],
+		"additionalProperties": false
+	}`, string(data))
+}
+
+func TestSelectDispatchedRun(t *testing.T) {
+	dispatchedAt := time.Date(2026, 4, 20, 10, 0, 0, 0, time.UTC)
+	run := func(id int64, offset time.Duration, actor, title string) *githubapi.WorkflowRun {
+		return &githubapi.WorkflowRun{
+			ID:           githubapi.Ptr(id),
+			Event:        githubapi.Ptr("workflow_dispatch"),
+			CreatedAt:    &githubapi.Timestamp{Time: dispatchedAt.Add(offset)},
+			Actor:        &githubapi.User{Login: githubapi.Ptr(actor)},
+			DisplayTitle: githubapi.Ptr(title),
+		}
+	}
+
+	runs := []*githubapi.WorkflowRun{
+		run(5, 4*time.Second, "bob", "Deploy"),
+		run(4, 2*time.Second, "alice", "Deploy (abc123)"),
+		run(3, time.Second, "alice", "Deploy"),
+		run(2, -time.Minute, "alice", "Deploy"),
+		run(1, time.Second, "alice", "Deploy"),
+	}
+	existing := map[int64]bool{1: true}
+
+	got, matchedBy, candidates := selectDispatchedRun(runs, existing, dispatchedAt, "alice", "abc123", true)
+	require.NotNil(t, got)
+	assert.Equal(t, int64(4), got.GetID())
+	assert.Equal(t, "correlation_id", matchedBy)
+	assert.Nil(t, candidates)
+
+	got, matchedBy, candidates = selectDispatchedRun(runs, existing, dispatchedAt, "alice", "", false)
+	require.NotNil(t, got)
+	assert.Equal(t, int64(3), got.GetID())
+	assert.Equal(t, "earliest_candidate", matchedBy)
+	assert.Equal(t, []int64{3, 4}, candidates)
+
+	got, matchedBy, _ = selectDispatchedRun(runs, existing, dispatchedAt, "bob", "", false)
+	require.NotNil(t, got)
+	assert.Equal(t, int64(5), got.GetID())
+	assert.Equal(t, "only_candidate", matchedBy)
+
+	got, _, _ = selectDispatchedRun(runs, existing, dispatchedAt, "alice", "missing", true)
+	assert.Nil(t, got, "should keep waiting for the run whose title carries the marker")
+}
+
+func TestDispatchWorkflow_IdentifiesNewRun(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	oldInterval := dispatchPollInterval
+	dispatchPollInterval = 10 * time.Millisecond
+	defer func() { dispatchPollInterval = oldInterval }()
+
+	workflowYAML := "run-name: Deploy ${{ inputs.correlation_id }}\non:\n  workflow_dispatch:\n    inputs:\n      correlation_id:\n        type: string\n      environment:\n        type: string\n"
+
+	var (
+		mu         sync.Mutex
+		dispatched map[string]interface{}
+		listCalls  int
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "workflows": [{"id": 50, "name": "Deploy", "path": ".github/workflows/deploy.yml", "state": "active"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 50, "name": "Deploy", "path": ".github/workflows/deploy.yml", "state": "active"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/contents/.github/workflows/deploy.yml", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "main", r.URL.Query().Get("ref"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(workflowYAML)),
+		})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"login": "alice"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/dispatches", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Ref    string                 `json:"ref"`
+			Inputs map[string]interface{} `json:"inputs"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "main", body.Ref)
+		mu.Lock()
+		dispatched = body.Inputs
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "workflow_dispatch", r.URL.Query().Get("event"))
+		assert.Equal(t, "main", r.URL.Query().Get("branch"))
+
+		mu.Lock()
+		defer mu.Unlock()
+		listCalls++
+
+		now := time.Now().UTC().Format(time.RFC3339)
+		runs := []string{
+			`{"id": 1, "name": "Deploy", "display_title": "Deploy old", "event": "workflow_dispatch", "status": "completed", "created_at": "2026-01-01T00:00:00Z", "actor": {"login": "alice"}}`,
+		}
+		// The new run only shows up on the second poll after the dispatch.
+		if dispatched != nil && listCalls >= 3 {
+			runs = append([]string{
+				fmt.Sprintf(`{"id": 3, "name": "Deploy", "display_title": "Deploy someone-else", "event": "workflow_dispatch", "status": "queued", "created_at": %q, "actor": {"login": "alice"}}`, now),
+				fmt.Sprintf(`{"id": 2, "name": "Deploy", "display_title": "Deploy %s", "event": "workflow_dispatch", "status": "queued", "created_at": %q, "actor": {"login": "alice"}}`, dispatched["correlation_id"], now),
+			}, runs...)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"total_count": %d, "workflow_runs": [%s]}`, len(runs), strings.Join(runs, ","))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	result, err := client.DispatchWorkflow(context.Background(), DispatchOptions{
+		Workflow:         "Deploy",
+		Ref:              "main",
+		Inputs:           map[string]interface{}{"environment": "staging"},
+		CorrelationInput: "correlation_id",
+		DiscoveryTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(50), result.WorkflowID)
+	assert.Equal(t, ".github/workflows/deploy.yml", result.WorkflowPath)
+	assert.Equal(t, "alice", result.Actor)
+	require.NotEmpty(t, result.CorrelationID)
+	assert.Equal(t, result.CorrelationID, dispatched["correlation_id"])
+	assert.Equal(t, "staging", dispatched["environment"])
+	assert.Equal(t, int64(2), result.RunID)
+	assert.Equal(t, "correlation_id", result.MatchedBy)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestDispatchWorkflow_DisabledWorkflow(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	oldInterval := dispatchPollInterval
+	dispatchPollInterval = time.Millisecond
+	defer func() { dispatchPollInterval = oldInterval }()
+
+	var enabled, dispatched bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "workflows": [{"id": 50, "name": "Nightly", "path": ".github/workflows/nightly.yml", "state": "disabled_inactivity"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 50, "name": "Nightly", "path": ".github/workflows/nightly.yml", "state": "disabled_inactivity"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/enable", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		enabled = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/dispatches", func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, enabled, "the workflow is enabled before the dispatch")
+		dispatched = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 0, "workflow_runs": []}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	workflows, err := client.GetWorkflows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, workflows, 1)
+	assert.True(t, workflows[0].Disabled)
+	assert.Contains(t, workflows[0].DisabledReason, "60 days")
+
+	_, err = client.DispatchWorkflow(context.Background(), DispatchOptions{Workflow: "Nightly", Ref: "main"})
+	require.ErrorIs(t, err, ErrWorkflowDisabled)
+	assert.Equal(t, CodeWorkflowDisabled, ErrorCode(err))
+	assert.False(t, enabled)
+	assert.False(t, dispatched)
+
+	result, err := client.DispatchWorkflow(context.Background(), DispatchOptions{
+		Workflow:         "Nightly",
+		Ref:              "main",
+		EnableIfDisabled: true,
+		DiscoveryTimeout: time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Enabled)
+	assert.True(t, dispatched)
+}
+
+func TestDispatchWorkflow_WorkflowDefaultRef(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	oldInterval := dispatchPollInterval
+	dispatchPollInterval = time.Millisecond
+	defer func() { dispatchPollInterval = oldInterval }()
+
+	var dispatchedRefs []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "workflows": [
+			{"id": 50, "name": "Deploy", "path": ".github/workflows/deploy.yml", "state": "active"},
+			{"id": 60, "name": "CI", "path": ".github/workflows/ci.yml", "state": "active"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 50, "name": "Deploy", "path": ".github/workflows/deploy.yml", "state": "active"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/60", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 60, "name": "CI", "path": ".github/workflows/ci.yml", "state": "active"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"default_branch": "main"}`))
+	})
+	dispatch := func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Ref string `json:"ref"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		dispatchedRefs = append(dispatchedRefs, body.Ref)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/dispatches", dispatch)
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/60/dispatches", dispatch)
+	runs := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 0, "workflow_runs": []}`))
+	}
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/50/runs", runs)
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/60/runs", runs)
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50, defaultRefs: map[string]string{"deploy.yml": "production"}}
+
+	// The workflow is selected by name; its default ref is configured by file name.
+	result, err := client.DispatchWorkflow(context.Background(), DispatchOptions{Workflow: "Deploy", DiscoveryTimeout: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, "production", result.Ref)
+	assert.Equal(t, RefSourceWorkflowDefault, result.RefSource)
+
+	// An explicit ref wins over the default.
+	result, err = client.DispatchWorkflow(context.Background(), DispatchOptions{Workflow: "Deploy", Ref: "staging", DiscoveryTimeout: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, "staging", result.Ref)
+	assert.Empty(t, result.RefSource)
+
+	// Workflows without a default ref run on the default branch.
+	result, err = client.DispatchWorkflow(context.Background(), DispatchOptions{Workflow: "CI", DiscoveryTimeout: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, "main", result.Ref)
+	assert.Equal(t, RefSourceDefaultBranch, result.RefSource)
+	assert.Equal(t, []string{"production", "staging", "main"}, dispatchedRefs)
+
+	// The default ref is still subject to the allowed refs.
+	client.allowedRefs = []string{"main"}
+	_, err = client.DispatchWorkflow(context.Background(), DispatchOptions{Workflow: "Deploy", DiscoveryTimeout: time.Millisecond})
+	require.ErrorIs(t, err, ErrRefNotAllowed)
+	assert.Len(t, dispatchedRefs, 3)
+}
@@ -0,0 +1,235 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// actionsRunURLPattern extracts the workflow run ID from a GitHub Actions URL.
+var actionsRunURLPattern = regexp.MustCompile(`/actions/runs/(\d+)`)
+
+// DeploymentInfo describes a deployment to an environment and its latest status.
+type DeploymentInfo struct {
+	ID          int64  `json:"id"`
+	SHA         string `json:"sha"`
+	Ref         string `json:"ref"`
+	State       string `json:"state"` // latest deployment status: success, in_progress, queued, waiting, failure, ...
+	Creator     string `json:"creator,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	RunID       int64  `json:"run_id,omitempty"`
+}
+
+// EnvironmentStatus reports what is currently deployed to an environment and what is on its way.
+type EnvironmentStatus struct {
+	Name       string            `json:"name"`
+	URL        string            `json:"url,omitempty"`
+	Current    *DeploymentInfo   `json:"current,omitempty"`
+	Pending    []*DeploymentInfo `json:"pending,omitempty"`
+	LastFailed *DeploymentInfo   `json:"last_failed,omitempty"`
+}
+
+// pendingDeploymentStates are deployment status states for deployments that have not finished.
+var pendingDeploymentStates = map[string]bool{
+	"pending":     true,
+	"queued":      true,
+	"in_progress": true,
+	"waiting":     true,
+}
+
+// GetEnvironmentStatus reports, for each environment (or only the named one), the most recent
+// successful deployment, the commit it deployed, and deployments still pending. limit bounds
+// how many recent deployments are inspected per environment.
+func (c *Client) GetEnvironmentStatus(ctx context.Context, environment string, limit int) ([]*EnvironmentStatus, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var names []string
+	if environment != "" {
+		names = []string{environment}
+	} else {
+		envs, _, err := c.gh.Repositories.ListEnvironments(ctx, c.owner, c.repo, &github.EnvironmentListOptions{
+			ListOptions: github.ListOptions{PerPage: 100},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list environments: %w", err)
+		}
+		for _, env := range envs.Environments {
+			names = append(names, env.GetName())
+		}
+	}
+
+	result := make([]*EnvironmentStatus, 0, len(names))
+	for _, name := range names {
+		status, err := c.environmentStatus(ctx, name, limit)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+func (c *Client) environmentStatus(ctx context.Context, name string, limit int) (*EnvironmentStatus, error) {
+	deployments, _, err := c.gh.Repositories.ListDeployments(ctx, c.owner, c.repo, &github.DeploymentsListOptions{
+		Environment: name,
+		ListOptions: github.ListOptions{PerPage: limit},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments for environment %s: %w", name, err)
+	}
+
+	status := &EnvironmentStatus{Name: name}
+
+	// Deployments are returned newest first. Walk until the current (successful) deployment is
+	// found; anything newer that has not finished is pending.
+	for _, d := range deployments {
+		statuses, _, err := c.gh.Repositories.ListDeploymentStatuses(ctx, c.owner, c.repo, d.GetID(), &github.ListOptions{PerPage: 1})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get status of deployment %d: %w", d.GetID(), err)
+		}
+		var latest *github.DeploymentStatus
+		if len(statuses) > 0 {
+			latest = statuses[0]
+		}
+		info := deploymentInfo(d, latest)
+
+		switch {
+		case info.State == "success":
+			status.Current = info
+			if latest != nil && latest.GetEnvironmentURL() != "" {
+				status.URL = latest.GetEnvironmentURL()
+			}
+		case pendingDeploymentStates[info.State]:
+			status.Pending = append(status.Pending, info)
+		case (info.State == "failure" || info.State == "error") && status.LastFailed == nil:
+			status.LastFailed = info
+		}
+		if status.Current != nil {
+			break
+		}
+	}
+
+	return status, nil
+}
+
+func deploymentInfo(d *github.Deployment, latest *github.DeploymentStatus) *DeploymentInfo {
+	info := &DeploymentInfo{
+		ID:          d.GetID(),
+		SHA:         d.GetSHA(),
+		Ref:         d.GetRef(),
+		State:       "pending",
+		Creator:     d.GetCreator().GetLogin(),
+		CreatedAt:   formatTime(d.CreatedAt),
+		Description: d.GetDescription(),
+	}
+	if latest != nil {
+		info.State = latest.GetState()
+		info.UpdatedAt = formatTime(latest.UpdatedAt)
+		info.URL = latest.GetLogURL()
+		if info.URL == "" {
+			info.URL = latest.GetTargetURL()
+		}
+	}
+	if m := actionsRunURLPattern.FindStringSubmatch(info.URL); m != nil {
+		info.RunID, _ = strconv.ParseInt(m[1], 10, 64)
+	}
+	return info
+}
+
+// EnvironmentProtection describes the rules a deployment to an environment must pass.
+type EnvironmentProtection struct {
+	Name               string   `json:"name"`
+	URL                string   `json:"url,omitempty"`
+	RequiredReviewers  []string `json:"required_reviewers,omitempty"` // User logins and "org/team" slugs
+	PreventSelfReview  bool     `json:"prevent_self_review,omitempty"`
+	WaitTimerMinutes   int      `json:"wait_timer_minutes,omitempty"`
+	DeploymentBranches string   `json:"deployment_branches"`       // all, protected, or selected
+	BranchPatterns     []string `json:"branch_patterns,omitempty"` // Name patterns when selected; tags as "tag:<pattern>"
+	CustomRules        []string `json:"custom_rules,omitempty"`    // Apps gating deployments through custom protection rules
+	CanAdminsBypass    bool     `json:"can_admins_bypass"`
+}
+
+// ListEnvironments lists the repository's environments with their protection rules: required
+// reviewers, wait timer, the branches allowed to deploy, and custom protection rules.
+func (c *Client) ListEnvironments(ctx context.Context) ([]*EnvironmentProtection, error) {
+	opts := &github.EnvironmentListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	var result []*EnvironmentProtection
+	for {
+		envs, resp, err := c.gh.Repositories.ListEnvironments(ctx, c.owner, c.repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list environments: %w", Classify(err))
+		}
+		for _, env := range envs.Environments {
+			result = append(result, c.environmentProtection(ctx, env))
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+func (c *Client) environmentProtection(ctx context.Context, env *github.Environment) *EnvironmentProtection {
+	p := &EnvironmentProtection{
+		Name:               env.GetName(),
+		URL:                env.GetHTMLURL(),
+		DeploymentBranches: "all",
+		CanAdminsBypass:    env.GetCanAdminsBypass(),
+	}
+	for _, rule := range env.ProtectionRules {
+		switch rule.GetType() {
+		case "required_reviewers":
+			p.PreventSelfReview = rule.GetPreventSelfReview()
+			for _, r := range rule.Reviewers {
+				if name := reviewerName(r); name != "" {
+					p.RequiredReviewers = append(p.RequiredReviewers, name)
+				}
+			}
+		case "wait_timer":
+			p.WaitTimerMinutes = rule.GetWaitTimer()
+		}
+	}
+
+	if policy := env.DeploymentBranchPolicy; policy != nil {
+		switch {
+		case policy.GetProtectedBranches():
+			p.DeploymentBranches = "protected"
+		case policy.GetCustomBranchPolicies():
+			p.DeploymentBranches = "selected"
+			policies, _, err := c.gh.Repositories.ListDeploymentBranchPolicies(ctx, c.owner, c.repo, url.PathEscape(p.Name))
+			if err != nil {
+				log.Debugf("Could not list deployment branch policies of environment %s: %v", p.Name, err)
+				break
+			}
+			for _, bp := range policies.BranchPolicies {
+				pattern := bp.GetName()
+				if bp.GetType() == "tag" {
+					pattern = "tag:" + pattern
+				}
+				p.BranchPatterns = append(p.BranchPatterns, pattern)
+			}
+		}
+	}
+
+	rules, _, err := c.gh.Repositories.GetAllDeploymentProtectionRules(ctx, c.owner, c.repo, url.PathEscape(p.Name))
+	if err != nil {
+		log.Debugf("Could not list custom protection rules of environment %s: %v", p.Name, err)
+		return p
+	}
+	for _, rule := range rules.ProtectionRules {
+		if rule.GetEnabled() {
+			p.CustomRules = append(p.CustomRules, rule.GetApp().GetSlug())
+		}
+	}
+	return p
+}
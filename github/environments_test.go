@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEnvironmentStatus(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/environments", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "environments": [{"id": 1, "name": "staging"}, {"id": 2, "name": "production"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/deployments", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("environment") {
+		case "production":
+			_, _ = w.Write([]byte(`[
+				{"id": 30, "sha": "ccc", "ref": "main", "environment": "production", "created_at": "2026-04-20T12:00:00Z", "creator": {"login": "alice"}},
+				{"id": 20, "sha": "bbb", "ref": "main", "environment": "production", "created_at": "2026-04-19T12:00:00Z"},
+				{"id": 10, "sha": "aaa", "ref": "main", "environment": "production", "created_at": "2026-04-18T12:00:00Z"},
+				{"id": 5, "sha": "zzz", "ref": "main", "environment": "production", "created_at": "2026-04-17T12:00:00Z"}
+			]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	})
+	statuses := map[string]string{
+		"30": `[{"state": "waiting", "log_url": "https://github.com/test-owner/test-repo/actions/runs/303/job/1"}]`,
+		"20": `[{"state": "failure", "log_url": "https://github.com/test-owner/test-repo/actions/runs/202/job/1"}]`,
+		"10": `[{"state": "success", "environment_url": "https://prod.example.com", "log_url": "https://github.com/test-owner/test-repo/actions/runs/101/job/1"}]`,
+	}
+	for id, body := range statuses {
+		body := body
+		mux.HandleFunc("/repos/"+owner+"/"+repo+"/deployments/"+id+"/statuses", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		})
+	}
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/deployments/5/statuses", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("deployments older than the current one should not be inspected")
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	envs, err := client.GetEnvironmentStatus(context.Background(), "", 10)
+	require.NoError(t, err)
+	require.Len(t, envs, 2)
+
+	assert.Equal(t, "staging", envs[0].Name)
+	assert.Nil(t, envs[0].Current)
+
+	prod := envs[1]
+	require.NotNil(t, prod.Current)
+	assert.Equal(t, "aaa", prod.Current.SHA)
+	assert.Equal(t, int64(101), prod.Current.RunID)
+	assert.Equal(t, "https://prod.example.com", prod.URL)
+	require.Len(t, prod.Pending, 1)
+	assert.Equal(t, "waiting", prod.Pending[0].State)
+	assert.Equal(t, "alice", prod.Pending[0].Creator)
+	require.NotNil(t, prod.LastFailed)
+	assert.Equal(t, int64(202), prod.LastFailed.RunID)
+}
+
+func TestListEnvironments(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/environments", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "environments": [
+			{"id": 1, "name": "staging", "can_admins_bypass": true, "protection_rules": []},
+			{"id": 2, "name": "production", "html_url": "https://github.com/test-owner/test-repo/deployments/activity_log?environments_filter=production", "can_admins_bypass": false,
+			 "deployment_branch_policy": {"protected_branches": false, "custom_branch_policies": true},
+			 "protection_rules": [
+				{"id": 10, "type": "required_reviewers", "prevent_self_review": true, "reviewers": [
+					{"type": "User", "reviewer": {"login": "alice"}},
+					{"type": "Team", "reviewer": {"slug": "sre", "organization": {"login": "octo-org"}}}
+				]},
+				{"id": 11, "type": "wait_timer", "wait_timer": 15},
+				{"id": 12, "type": "branch_policy"}
+			]}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/environments/production/deployment-branch-policies", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "branch_policies": [{"id": 1, "name": "main", "type": "branch"}, {"id": 2, "name": "v*", "type": "tag"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/environments/production/deployment_protection_rules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "custom_deployment_protection_rules": [
+			{"id": 3, "enabled": true, "app": {"id": 1, "slug": "datadog-gate"}},
+			{"id": 4, "enabled": false, "app": {"id": 2, "slug": "disabled-gate"}}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/environments/staging/deployment_protection_rules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 0, "custom_deployment_protection_rules": []}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	envs, err := client.ListEnvironments(context.Background())
+	require.NoError(t, err)
+	require.Len(t, envs, 2)
+
+	staging := envs[0]
+	assert.Equal(t, "staging", staging.Name)
+	assert.Equal(t, "all", staging.DeploymentBranches)
+	assert.Empty(t, staging.RequiredReviewers)
+	assert.True(t, staging.CanAdminsBypass)
+
+	prod := envs[1]
+	assert.Equal(t, []string{"alice", "octo-org/sre"}, prod.RequiredReviewers)
+	assert.True(t, prod.PreventSelfReview)
+	assert.Equal(t, 15, prod.WaitTimerMinutes)
+	assert.Equal(t, "selected", prod.DeploymentBranches)
+	assert.Equal(t, []string{"main", "tag:v*"}, prod.BranchPatterns)
+	assert.Equal(t, []string{"datadog-gate"}, prod.CustomRules)
+	assert.False(t, prod.CanAdminsBypass)
+}
@@ -0,0 +1,189 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Sentinel errors for the failures callers act on. Use errors.Is on errors returned by the
+// client, after passing API errors through Classify.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrForbidden         = errors.New("forbidden")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrLogsExpired       = errors.New("logs expired")
+	ErrNoDispatchTrigger = errors.New("workflow has no workflow_dispatch trigger")
+	ErrWorkflowDisabled  = errors.New("workflow is disabled")
+	ErrRefNotAllowed     = errors.New("ref not allowed")
+	ErrAmbiguousWorkflow = errors.New("ambiguous workflow name")
+)
+
+// Machine-readable error codes returned by ErrorCode.
+const (
+	CodeNotFound          = "not_found"
+	CodeUnauthorized      = "unauthorized"
+	CodeForbidden         = "forbidden"
+	CodeRateLimited       = "rate_limited"
+	CodeLogsExpired       = "logs_expired"
+	CodeNoDispatchTrigger = "no_dispatch_trigger"
+	CodeWorkflowDisabled  = "workflow_disabled"
+	CodeRefNotAllowed     = "ref_not_allowed"
+	CodeAmbiguousWorkflow = "ambiguous_workflow"
+	CodeAPIError          = "api_error"
+)
+
+// errorCodes maps sentinels to codes, most specific first.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrLogsExpired, CodeLogsExpired},
+	{ErrNoDispatchTrigger, CodeNoDispatchTrigger},
+	{ErrWorkflowDisabled, CodeWorkflowDisabled},
+	{ErrRefNotAllowed, CodeRefNotAllowed},
+	{ErrAmbiguousWorkflow, CodeAmbiguousWorkflow},
+	{ErrRateLimited, CodeRateLimited},
+	{ErrUnauthorized, CodeUnauthorized},
+	{ErrForbidden, CodeForbidden},
+	{ErrNotFound, CodeNotFound},
+}
+
+// WorkflowCandidate is one of several workflows matching an ambiguous name.
+type WorkflowCandidate struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	State string `json:"state"`
+}
+
+// AmbiguousWorkflowError is returned when a workflow name matches several workflows,
+// typically after a rename left an old file with the same display name. It matches
+// ErrAmbiguousWorkflow.
+type AmbiguousWorkflowError struct {
+	Name       string
+	Candidates []WorkflowCandidate
+}
+
+func (e *AmbiguousWorkflowError) Error() string {
+	parts := make([]string, 0, len(e.Candidates))
+	for _, c := range e.Candidates {
+		parts = append(parts, fmt.Sprintf("%s (id %d, %s)", c.Path, c.ID, c.State))
+	}
+	return fmt.Sprintf("workflow name %q matches %d workflows; select one by path or ID: %s", e.Name, len(e.Candidates), strings.Join(parts, ", "))
+}
+
+func (e *AmbiguousWorkflowError) Is(target error) bool { return target == ErrAmbiguousWorkflow }
+
+// classifiedError attaches a sentinel to an error without changing its message.
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+func (e *classifiedError) Error() string   { return e.err.Error() }
+func (e *classifiedError) Unwrap() []error { return []error{e.err, e.kind} }
+
+// withKind wraps err so that errors.Is(err, kind) holds.
+func withKind(kind, err error) error {
+	if err == nil || kind == nil || errors.Is(err, kind) {
+		return err
+	}
+	return &classifiedError{kind: kind, err: err}
+}
+
+// Classify returns err wrapped with the sentinel matching the GitHub API failure it
+// carries, so errors.Is(err, ErrNotFound) and friends work on raw go-github errors. Errors
+// that match no sentinel are returned unchanged.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return err
+		}
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return withKind(ErrRateLimited, err)
+	}
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		// "Cannot trigger a 'workflow_dispatch' on a disabled workflow"
+		if errResp.Response.StatusCode == http.StatusUnprocessableEntity && strings.Contains(errResp.Message, "disabled workflow") {
+			return withKind(ErrWorkflowDisabled, err)
+		}
+		if errResp.Response.StatusCode == http.StatusUnprocessableEntity && strings.Contains(errResp.Message, "workflow_dispatch") {
+			return withKind(ErrNoDispatchTrigger, err)
+		}
+		return withKind(statusKind(errResp.Response.StatusCode), err)
+	}
+	return err
+}
+
+// ErrorCode returns the machine-readable code for err: one of the Code* constants, or
+// CodeAPIError when the failure matches no sentinel.
+func ErrorCode(err error) string {
+	err = Classify(err)
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return CodeAPIError
+}
+
+// IsTokenScopeError reports whether GitHub rejected a fine-grained or classic PAT because
+// it lacks access to the endpoint, as opposed to the repository denying the user.
+func IsTokenScopeError(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && strings.Contains(strings.ToLower(errResp.Message), "resource not accessible by personal access token")
+}
+
+// statusKind maps an HTTP status code to a sentinel, or nil.
+func statusKind(status int) error {
+	switch status {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// responseError wraps err from calls that follow redirects (log and archive downloads),
+// for which go-github reports failures as untyped "unexpected status code" errors.
+func responseError(resp *github.Response, err error) error {
+	if resp == nil || resp.Response == nil {
+		return Classify(err)
+	}
+	return withKind(statusKind(resp.StatusCode), Classify(err))
+}
+
+// logsError wraps a failure to download logs. GitHub answers 410 Gone once a run's logs
+// have passed the retention period.
+func logsError(resp *github.Response, err error) error {
+	if resp != nil && resp.Response != nil && resp.StatusCode == http.StatusGone {
+		return withKind(ErrLogsExpired, fmt.Errorf("%w (logs are deleted after the repository's retention period)", err))
+	}
+	return responseError(resp, err)
+}
+
+// isAccepted reports whether err is go-github's report of a 202 Accepted response, which
+// endpoints such as run cancellation return on success.
+func isAccepted(err error) bool {
+	var accepted *github.AcceptedError
+	return errors.As(err, &accepted)
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	errorResponse := func(status int, msg string) error {
+		return &githubapi.ErrorResponse{Response: &http.Response{StatusCode: status}, Message: msg}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"404 response", fmt.Errorf("failed to get run: %w", errorResponse(http.StatusNotFound, "Not Found")), CodeNotFound},
+		{"401 response", errorResponse(http.StatusUnauthorized, "Bad credentials"), CodeUnauthorized},
+		{"403 response", errorResponse(http.StatusForbidden, "Must have admin rights"), CodeForbidden},
+		{"rate limit", &githubapi.RateLimitError{Message: "API rate limit exceeded"}, CodeRateLimited},
+		{"secondary rate limit", &githubapi.AbuseRateLimitError{Message: "secondary rate limit"}, CodeRateLimited},
+		{"no dispatch trigger", errorResponse(http.StatusUnprocessableEntity, "Workflow does not have 'workflow_dispatch' trigger"), CodeNoDispatchTrigger},
+		{"disabled workflow", errorResponse(http.StatusUnprocessableEntity, "Cannot trigger a 'workflow_dispatch' on a disabled workflow"), CodeWorkflowDisabled},
+		{"log HTTPError 410", &HTTPError{StatusCode: http.StatusGone, Message: "failed to get workflow logs: HTTP 410"}, CodeLogsExpired},
+		{"log HTTPError 404", &HTTPError{StatusCode: http.StatusNotFound, Message: "failed to get workflow logs: HTTP 404"}, CodeNotFound},
+		{"ref policy", fmt.Errorf("%w: ref %q is not allowed", ErrRefNotAllowed, "dev"), CodeRefNotAllowed},
+		{"untyped", errors.New("HTTP 404 in a message is not enough"), CodeAPIError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorCode(tt.err))
+		})
+	}
+}
+
+func TestClassify_KeepsMessage(t *testing.T) {
+	err := fmt.Errorf("failed to get run 5: %w", &githubapi.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Message: "Not Found"})
+	classified := Classify(err)
+	assert.True(t, errors.Is(classified, ErrNotFound))
+	assert.Equal(t, err.Error(), classified.Error())
+	assert.True(t, IsHTTPError(classified, http.StatusNotFound))
+	assert.Nil(t, Classify(nil))
+}
+
+func TestLogsError(t *testing.T) {
+	gone := &githubapi.Response{Response: &http.Response{StatusCode: http.StatusGone}}
+	err := logsError(gone, errors.New("unexpected status code: 410 Gone"))
+	assert.True(t, errors.Is(err, ErrLogsExpired))
+	assert.Contains(t, err.Error(), "retention period")
+
+	missing := &githubapi.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	err = logsError(missing, errors.New("unexpected status code: 404 Not Found"))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, IsHTTPError(err, http.StatusNotFound))
+}
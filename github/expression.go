@@ -0,0 +1,970 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Job statuses the status check functions of an expression are evaluated against.
+const (
+	ExpressionStatusSuccess   = "success"
+	ExpressionStatusFailure   = "failure"
+	ExpressionStatusCancelled = "cancelled"
+)
+
+// expressionContexts are the named values an expression may reference.
+var expressionContexts = map[string]bool{
+	"github": true, "env": true, "vars": true, "job": true, "jobs": true, "steps": true, "runner": true,
+	"secrets": true, "strategy": true, "matrix": true, "needs": true, "inputs": true,
+}
+
+// statusFunctions are the functions whose presence disables the implicit success() check
+// of an if: condition.
+var statusFunctions = map[string]bool{"success": true, "failure": true, "cancelled": true, "always": true}
+
+// ExpressionOptions controls how EvaluateExpression evaluates an expression.
+type ExpressionOptions struct {
+	// Status is the job status seen by success(), failure(), and cancelled(); success
+	// when empty.
+	Status string
+	// Condition evaluates the expression as an if: condition, which is implicitly
+	// "success() && (...)" unless it calls a status check function.
+	Condition bool
+}
+
+// ExpressionStep is the value of one sub-expression.
+type ExpressionStep struct {
+	Expression string      `json:"expression"`
+	Value      interface{} `json:"value"`
+}
+
+// ExpressionResult is the outcome of evaluating an expression.
+type ExpressionResult struct {
+	Expression      string           `json:"expression"`
+	Result          interface{}      `json:"result"`
+	Truthy          bool             `json:"truthy"`
+	ImplicitSuccess bool             `json:"implicit_success,omitempty"` // success() && was prepended to the condition
+	Steps           []ExpressionStep `json:"steps,omitempty"`            // Sub-expressions in evaluation order
+	Warnings        []string         `json:"warnings,omitempty"`
+}
+
+// EvaluateExpression evaluates a GitHub Actions expression against contexts, a map of
+// context names (github, env, vars, matrix, needs, steps, inputs, ...) to their values as
+// decoded from JSON. The expression may be given with or without ${{ }}; text around
+// ${{ }} is interpolated into a string, as in a step's with: or run: values.
+func EvaluateExpression(expression string, contexts map[string]interface{}, opts ExpressionOptions) (*ExpressionResult, error) {
+	ev := &exprEvaluator{contexts: make(map[string]interface{}, len(contexts)), status: opts.Status}
+	for name, value := range contexts {
+		ev.contexts[strings.ToLower(name)] = value
+	}
+	if ev.status == "" {
+		ev.status = ExpressionStatusSuccess
+	}
+	switch ev.status {
+	case ExpressionStatusSuccess, ExpressionStatusFailure, ExpressionStatusCancelled:
+	default:
+		return nil, fmt.Errorf("invalid status %q: use success, failure, or cancelled", opts.Status)
+	}
+
+	trimmed := strings.TrimSpace(expression)
+	if inner, ok := wholeExpression(trimmed); ok {
+		trimmed = inner
+	} else if strings.Contains(trimmed, "${{") {
+		return ev.interpolate(trimmed, opts.Condition)
+	}
+
+	node, err := parseExpression(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	result := &ExpressionResult{Expression: trimmed}
+	if opts.Condition && !callsStatusFunction(node) {
+		result.ImplicitSuccess = true
+		result.Expression = "success() && (" + trimmed + ")"
+		node = &exprNode{kind: nodeAnd, text: result.Expression, args: []*exprNode{
+			{kind: nodeCall, name: "success", text: "success()"},
+			node,
+		}}
+	}
+
+	value, err := ev.eval(node, true)
+	if err != nil {
+		return nil, err
+	}
+	result.Result = exprPlain(value)
+	result.Truthy = exprTruthy(value)
+	result.Steps = ev.steps
+	result.Warnings = ev.warnings
+	return result, nil
+}
+
+// wholeExpression returns the inside of s when s is a single ${{ }} expression.
+func wholeExpression(s string) (string, bool) {
+	if !strings.HasPrefix(s, "${{") || !strings.HasSuffix(s, "}}") || strings.Count(s, "${{") != 1 {
+		return "", false
+	}
+	return strings.TrimSpace(s[3 : len(s)-2]), true
+}
+
+// interpolate evaluates every ${{ }} in a template and joins the results into a string.
+func (ev *exprEvaluator) interpolate(template string, condition bool) (*ExpressionResult, error) {
+	var out strings.Builder
+	rest := template
+	for {
+		start := strings.Index(rest, "${{")
+		if start < 0 {
+			out.WriteString(rest)
+			break
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated ${{ in %q", template)
+		}
+		out.WriteString(rest[:start])
+		node, err := parseExpression(strings.TrimSpace(rest[start+3 : start+end]))
+		if err != nil {
+			return nil, err
+		}
+		value, err := ev.eval(node, true)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString(exprString(value))
+		rest = rest[start+end+2:]
+	}
+
+	result := &ExpressionResult{Expression: template, Result: out.String(), Truthy: out.Len() > 0, Steps: ev.steps, Warnings: ev.warnings}
+	if condition {
+		result.Warnings = append(result.Warnings, "the condition mixes text with ${{ }}, so it evaluates to a non-empty string, which is always true; put the whole condition inside ${{ }}")
+	}
+	return result, nil
+}
+
+// Expression syntax tree.
+
+type exprNodeKind int
+
+const (
+	nodeLiteral exprNodeKind = iota
+	nodeContext
+	nodeMember // args[0].name
+	nodeIndex  // args[0][args[1]]
+	nodeFilter // args[0].*
+	nodeNot
+	nodeAnd
+	nodeOr
+	nodeCompare // args[0] op args[1]
+	nodeCall
+)
+
+type exprNode struct {
+	kind  exprNodeKind
+	value interface{} // nodeLiteral
+	name  string      // context, member, or function name
+	op    string      // nodeCompare
+	args  []*exprNode
+	text  string // Source text
+}
+
+func callsStatusFunction(n *exprNode) bool {
+	if n.kind == nodeCall && statusFunctions[strings.ToLower(n.name)] {
+		return true
+	}
+	for _, arg := range n.args {
+		if callsStatusFunction(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lexer.
+
+type exprToken struct {
+	kind  string // num, str, ident, or the punctuation itself
+	text  string
+	value interface{}
+	pos   int
+}
+
+func lexExpression(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'':
+			var sb strings.Builder
+			j := i + 1
+			for {
+				if j >= len(src) {
+					return nil, fmt.Errorf("unterminated string at position %d", i)
+				}
+				if src[j] == '\'' {
+					if j+1 < len(src) && src[j+1] == '\'' {
+						sb.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				sb.WriteByte(src[j])
+				j++
+			}
+			tokens = append(tokens, exprToken{kind: "str", text: src[i : j+1], value: sb.String(), pos: i})
+			i = j + 1
+		case c >= '0' && c <= '9' || (c == '-' || c == '+' || c == '.') && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9' && !afterOperand(tokens):
+			j := i + 1
+			for j < len(src) && (isIdentByte(src[j]) || src[j] == '.' || (src[j] == '-' || src[j] == '+') && (src[j-1] == 'e' || src[j-1] == 'E')) {
+				j++
+			}
+			n, ok := parseExprNumber(src[i:j])
+			if !ok {
+				return nil, fmt.Errorf("invalid number %q at position %d", src[i:j], i)
+			}
+			tokens = append(tokens, exprToken{kind: "num", text: src[i:j], value: n, pos: i})
+			i = j
+		case isIdentByte(c) && c != '-':
+			j := i + 1
+			for j < len(src) && isIdentByte(src[j]) {
+				j++
+			}
+			tokens = append(tokens, exprToken{kind: "ident", text: src[i:j], pos: i})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"<=", ">=", "==", "!=", "&&", "||", "(", ")", "[", "]", ".", ",", "!", "<", ">", "*"} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, exprToken{kind: op, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// afterOperand reports whether the last token ends an operand, in which case a following
+// sign or dot is an operator rather than part of a number.
+func afterOperand(tokens []exprToken) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	switch tokens[len(tokens)-1].kind {
+	case "num", "str", "ident", ")", "]", "*":
+		return true
+	}
+	return false
+}
+
+func isIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func parseExprNumber(s string) (float64, bool) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		n, err := strconv.ParseInt(s[2:], 16, 64)
+		return float64(n), err == nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	return n, err == nil
+}
+
+// Parser, from the lowest to the highest precedence: ||, &&, == !=, < <= > >=, !, then
+// member access, indexing, and calls.
+
+type exprParser struct {
+	src    string
+	tokens []exprToken
+	pos    int
+}
+
+func parseExpression(src string) (*exprNode, error) {
+	if src == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	tokens, err := lexExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{src: src, tokens: tokens}
+	node, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+	return node, nil
+}
+
+var exprPrecedence = [][]string{{"||"}, {"&&"}, {"==", "!="}, {"<", "<=", ">", ">="}}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].kind
+	}
+	return ""
+}
+
+func (p *exprParser) start() int {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].pos
+	}
+	return len(p.src)
+}
+
+func (p *exprParser) end() int {
+	t := p.tokens[p.pos-1]
+	return t.pos + len(t.text)
+}
+
+func (p *exprParser) expect(kind string) error {
+	if p.peek() != kind {
+		if p.pos >= len(p.tokens) {
+			return fmt.Errorf("expected %q at end of expression", kind)
+		}
+		return fmt.Errorf("expected %q at position %d, found %q", kind, p.tokens[p.pos].pos, p.tokens[p.pos].text)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *exprParser) parseBinary(level int) (*exprNode, error) {
+	if level == len(exprPrecedence) {
+		return p.parseUnary()
+	}
+	start := p.start()
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		matched := false
+		for _, candidate := range exprPrecedence[level] {
+			if op == candidate {
+				matched = true
+			}
+		}
+		if !matched {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		node := &exprNode{args: []*exprNode{left, right}, text: p.src[start:p.end()]}
+		switch op {
+		case "&&":
+			node.kind = nodeAnd
+		case "||":
+			node.kind = nodeOr
+		default:
+			node.kind, node.op = nodeCompare, op
+		}
+		left = node
+	}
+}
+
+func (p *exprParser) parseUnary() (*exprNode, error) {
+	if p.peek() == "!" {
+		start := p.start()
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNode{kind: nodeNot, args: []*exprNode{operand}, text: p.src[start:p.end()]}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (*exprNode, error) {
+	start := p.start()
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek() {
+		case ".":
+			p.pos++
+			switch p.peek() {
+			case "*":
+				p.pos++
+				node = &exprNode{kind: nodeFilter, args: []*exprNode{node}}
+			case "ident":
+				name := p.tokens[p.pos].text
+				p.pos++
+				node = &exprNode{kind: nodeMember, name: name, args: []*exprNode{node}}
+			default:
+				return nil, fmt.Errorf("expected a property name after '.' at position %d", p.start())
+			}
+		case "[":
+			p.pos++
+			var index *exprNode
+			if p.peek() == "*" {
+				p.pos++
+				node = &exprNode{kind: nodeFilter, args: []*exprNode{node}}
+			} else {
+				if index, err = p.parseBinary(0); err != nil {
+					return nil, err
+				}
+				node = &exprNode{kind: nodeIndex, args: []*exprNode{node, index}}
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return node, nil
+		}
+		node.text = p.src[start:p.end()]
+	}
+}
+
+func (p *exprParser) parsePrimary() (*exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case "num", "str":
+		return &exprNode{kind: nodeLiteral, value: t.value, text: t.text}, nil
+	case "(":
+		node, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case "ident":
+		switch t.text {
+		case "true":
+			return &exprNode{kind: nodeLiteral, value: true, text: t.text}, nil
+		case "false":
+			return &exprNode{kind: nodeLiteral, value: false, text: t.text}, nil
+		case "null":
+			return &exprNode{kind: nodeLiteral, value: nil, text: t.text}, nil
+		}
+		if p.peek() != "(" {
+			return &exprNode{kind: nodeContext, name: t.text, text: t.text}, nil
+		}
+		p.pos++
+		call := &exprNode{kind: nodeCall, name: t.text}
+		for p.peek() != ")" {
+			if len(call.args) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			arg, err := p.parseBinary(0)
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+		}
+		p.pos++
+		call.text = p.src[t.pos:p.end()]
+		return call, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+// Evaluator.
+
+// exprFiltered is the result of a .* filter; member access applies to each element.
+type exprFiltered []interface{}
+
+type exprEvaluator struct {
+	contexts map[string]interface{}
+	status   string
+	steps    []ExpressionStep
+	warnings []string
+	missing  map[string]bool
+}
+
+// eval evaluates n. record adds the result to the steps unless n is a literal; parts of a
+// property chain are not recorded separately.
+func (ev *exprEvaluator) eval(n *exprNode, record bool) (interface{}, error) {
+	value, err := ev.evalNode(n)
+	if err != nil {
+		return nil, err
+	}
+	if record && n.kind != nodeLiteral {
+		ev.steps = append(ev.steps, ExpressionStep{Expression: n.text, Value: exprPlain(value)})
+	}
+	return value, nil
+}
+
+func (ev *exprEvaluator) evalNode(n *exprNode) (interface{}, error) {
+	switch n.kind {
+	case nodeLiteral:
+		return n.value, nil
+	case nodeContext:
+		name := strings.ToLower(n.name)
+		if value, ok := ev.contexts[name]; ok {
+			return value, nil
+		}
+		if !expressionContexts[name] {
+			return nil, fmt.Errorf("unrecognized named-value %q; contexts are %s", n.name, strings.Join(sortedKeys(expressionContexts), ", "))
+		}
+		if !ev.missing[name] {
+			if ev.missing == nil {
+				ev.missing = make(map[string]bool)
+			}
+			ev.missing[name] = true
+			ev.warnings = append(ev.warnings, fmt.Sprintf("the %s context was not provided, so its properties evaluate to null", name))
+		}
+		return nil, nil
+	case nodeMember:
+		object, err := ev.eval(n.args[0], false)
+		if err != nil {
+			return nil, err
+		}
+		return exprProperty(object, n.name), nil
+	case nodeIndex:
+		object, err := ev.eval(n.args[0], false)
+		if err != nil {
+			return nil, err
+		}
+		index, err := ev.eval(n.args[1], true)
+		if err != nil {
+			return nil, err
+		}
+		if list, ok := object.([]interface{}); ok {
+			if _, isString := index.(string); !isString {
+				i := exprNumber(index)
+				if i >= 0 && i < float64(len(list)) && i == math.Trunc(i) {
+					return list[int(i)], nil
+				}
+				return nil, nil
+			}
+		}
+		return exprProperty(object, exprString(index)), nil
+	case nodeFilter:
+		object, err := ev.eval(n.args[0], false)
+		if err != nil {
+			return nil, err
+		}
+		switch v := object.(type) {
+		case []interface{}:
+			return exprFiltered(append([]interface{}{}, v...)), nil
+		case exprFiltered:
+			var flat exprFiltered
+			for _, item := range v {
+				if list, ok := item.([]interface{}); ok {
+					flat = append(flat, list...)
+				}
+			}
+			return flat, nil
+		case map[string]interface{}:
+			filtered := exprFiltered{}
+			for _, key := range sortedKeys(v) {
+				filtered = append(filtered, v[key])
+			}
+			return filtered, nil
+		}
+		return exprFiltered{}, nil
+	case nodeNot:
+		operand, err := ev.eval(n.args[0], true)
+		if err != nil {
+			return nil, err
+		}
+		return !exprTruthy(operand), nil
+	case nodeAnd, nodeOr:
+		left, err := ev.eval(n.args[0], true)
+		if err != nil {
+			return nil, err
+		}
+		if exprTruthy(left) == (n.kind == nodeOr) {
+			return left, nil
+		}
+		return ev.eval(n.args[1], true)
+	case nodeCompare:
+		left, err := ev.eval(n.args[0], true)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ev.eval(n.args[1], true)
+		if err != nil {
+			return nil, err
+		}
+		return exprCompare(n.op, left, right), nil
+	case nodeCall:
+		args := make([]interface{}, len(n.args))
+		for i, arg := range n.args {
+			value, err := ev.eval(arg, true)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = value
+		}
+		return ev.call(n.name, args)
+	}
+	return nil, fmt.Errorf("unknown expression node")
+}
+
+func (ev *exprEvaluator) call(name string, args []interface{}) (interface{}, error) {
+	arity := func(min, max int) error {
+		if len(args) < min || (max >= 0 && len(args) > max) {
+			return fmt.Errorf("%s() called with %d argument(s)", name, len(args))
+		}
+		return nil
+	}
+
+	switch strings.ToLower(name) {
+	case "success", "failure", "cancelled", "always":
+		if err := arity(0, 0); err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(name) {
+		case "success":
+			return ev.status == ExpressionStatusSuccess, nil
+		case "failure":
+			return ev.status == ExpressionStatusFailure, nil
+		case "cancelled":
+			return ev.status == ExpressionStatusCancelled, nil
+		}
+		return true, nil
+	case "contains":
+		if err := arity(2, 2); err != nil {
+			return nil, err
+		}
+		if list, ok := exprList(args[0]); ok {
+			for _, item := range list {
+				if exprEqual(item, args[1]) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return strings.Contains(strings.ToLower(exprString(args[0])), strings.ToLower(exprString(args[1]))), nil
+	case "startswith":
+		if err := arity(2, 2); err != nil {
+			return nil, err
+		}
+		return strings.HasPrefix(strings.ToLower(exprString(args[0])), strings.ToLower(exprString(args[1]))), nil
+	case "endswith":
+		if err := arity(2, 2); err != nil {
+			return nil, err
+		}
+		return strings.HasSuffix(strings.ToLower(exprString(args[0])), strings.ToLower(exprString(args[1]))), nil
+	case "format":
+		if err := arity(1, -1); err != nil {
+			return nil, err
+		}
+		return exprFormat(exprString(args[0]), args[1:])
+	case "join":
+		if err := arity(1, 2); err != nil {
+			return nil, err
+		}
+		sep := ","
+		if len(args) == 2 {
+			sep = exprString(args[1])
+		}
+		list, ok := exprList(args[0])
+		if !ok {
+			return exprString(args[0]), nil
+		}
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = exprString(item)
+		}
+		return strings.Join(parts, sep), nil
+	case "tojson":
+		if err := arity(1, 1); err != nil {
+			return nil, err
+		}
+		data, err := json.MarshalIndent(exprPlain(args[0]), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("toJSON: %w", err)
+		}
+		return string(data), nil
+	case "fromjson":
+		if err := arity(1, 1); err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(exprString(args[0])), &value); err != nil {
+			return nil, fmt.Errorf("fromJSON: %w", err)
+		}
+		return value, nil
+	case "hashfiles":
+		return nil, fmt.Errorf("hashFiles() reads files on the runner and cannot be evaluated here")
+	}
+	return nil, fmt.Errorf("unknown function %s()", name)
+}
+
+// exprFormat implements format(): {N} is replaced by argument N, {{ and }} by braces.
+func exprFormat(format string, args []interface{}) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case c == '{' && i+1 < len(format) && format[i+1] == '{':
+			out.WriteByte('{')
+			i++
+		case c == '}' && i+1 < len(format) && format[i+1] == '}':
+			out.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("format: unclosed '{' in %q", format)
+			}
+			n, err := strconv.Atoi(format[i+1 : i+end])
+			if err != nil || n < 0 || n >= len(args) {
+				return "", fmt.Errorf("format: invalid placeholder %q in %q", format[i:i+end+1], format)
+			}
+			out.WriteString(exprString(args[n]))
+			i += end
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String(), nil
+}
+
+// exprProperty looks up a property case-insensitively. Looking up a property of a
+// filtered array looks it up on every element.
+func exprProperty(object interface{}, name string) interface{} {
+	switch v := object.(type) {
+	case map[string]interface{}:
+		if value, ok := v[name]; ok {
+			return value
+		}
+		for key, value := range v {
+			if strings.EqualFold(key, name) {
+				return value
+			}
+		}
+	case exprFiltered:
+		result := exprFiltered{}
+		for _, item := range v {
+			if value := exprProperty(item, name); value != nil {
+				result = append(result, value)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func exprList(v interface{}) ([]interface{}, bool) {
+	switch list := v.(type) {
+	case []interface{}:
+		return list, true
+	case exprFiltered:
+		return list, true
+	}
+	return nil, false
+}
+
+// exprPlain converts evaluator values to plain JSON values.
+func exprPlain(v interface{}) interface{} {
+	if filtered, ok := v.(exprFiltered); ok {
+		return []interface{}(filtered)
+	}
+	if n, ok := v.(float64); ok && (math.IsNaN(n) || math.IsInf(n, 0)) {
+		return exprString(n)
+	}
+	return v
+}
+
+// exprTruthy implements expression truthiness: false, 0, -0, NaN, "", and null are falsy.
+func exprTruthy(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case float64:
+		return x != 0 && !math.IsNaN(x)
+	case string:
+		return x != ""
+	}
+	return true
+}
+
+// exprString converts a value to a string the way expressions do.
+func exprString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		switch {
+		case math.IsNaN(x):
+			return "NaN"
+		case math.IsInf(x, 1):
+			return "Infinity"
+		case math.IsInf(x, -1):
+			return "-Infinity"
+		}
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case string:
+		return x
+	case []interface{}, exprFiltered:
+		return "Array"
+	}
+	return "Object"
+}
+
+// exprNumber converts a value to a number the way expressions do.
+func exprNumber(v interface{}) float64 {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if x {
+			return 1
+		}
+		return 0
+	case float64:
+		return x
+	case string:
+		s := strings.TrimSpace(x)
+		if s == "" {
+			return 0
+		}
+		if n, ok := parseExprNumber(s); ok {
+			return n
+		}
+	}
+	return math.NaN()
+}
+
+// exprEqual implements ==: strings compare case-insensitively, arrays and objects by
+// identity, and values of different types are compared as numbers.
+func exprEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case nil:
+		if b == nil {
+			return true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return x == y
+		}
+	case float64:
+		if y, ok := b.(float64); ok {
+			return x == y
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.EqualFold(x, y)
+		}
+	case []interface{}, map[string]interface{}, exprFiltered:
+		if reflect.TypeOf(a) == reflect.TypeOf(b) {
+			return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+		}
+		return false
+	}
+	if _, ok := b.([]interface{}); ok {
+		return false
+	}
+	if _, ok := b.(map[string]interface{}); ok {
+		return false
+	}
+	return exprNumber(a) == exprNumber(b)
+}
+
+func exprCompare(op string, a, b interface{}) bool {
+	switch op {
+	case "==":
+		return exprEqual(a, b)
+	case "!=":
+		return !exprEqual(a, b)
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			cmp := strings.Compare(strings.ToLower(x), strings.ToLower(y))
+			switch op {
+			case "<":
+				return cmp < 0
+			case "<=":
+				return cmp <= 0
+			case ">":
+				return cmp > 0
+			}
+			return cmp >= 0
+		}
+	}
+	x, y := exprNumber(a), exprNumber(b)
+	switch op {
+	case "<":
+		return x < y
+	case "<=":
+		return x <= y
+	case ">":
+		return x > y
+	}
+	return x >= y
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RunGitHubContext returns the github context properties of a run that the Actions API
+// exposes, for evaluating expressions as the run saw them. The event payload is not
+// included, and ref assumes the run's head is a branch. For pull_request runs, ref is the
+// PR's merge ref when the run belongs to exactly one PR.
+func (c *Client) RunGitHubContext(ctx context.Context, runID int64) (map[string]interface{}, error) {
+	run, err := c.GetWorkflowRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run %d: %w", runID, err)
+	}
+	return runGitHubContext(run, c.owner, c.repo), nil
+}
+
+// runGitHubContext returns the github context properties of a run.
+func runGitHubContext(run *WorkflowRun, owner, repo string) map[string]interface{} {
+	github := map[string]interface{}{
+		"event_name":       run.Event,
+		"sha":              run.HeadSHA,
+		"actor":            run.Actor,
+		"repository":       owner + "/" + repo,
+		"repository_owner": owner,
+		"run_id":           strconv.FormatInt(run.ID, 10),
+		"run_number":       strconv.Itoa(run.RunNumber),
+		"workflow":         run.Name,
+		"ref":              "refs/heads/" + run.Branch,
+		"ref_name":         run.Branch,
+		"head_ref":         "",
+		"base_ref":         "",
+	}
+	if strings.HasPrefix(run.Event, "pull_request") {
+		github["head_ref"] = run.Branch
+		if len(run.PullRequests) == 1 {
+			number := strconv.Itoa(run.PullRequests[0])
+			github["ref"] = "refs/pull/" + number + "/merge"
+			github["ref_name"] = number + "/merge"
+		}
+	}
+	return github
+}
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	contexts := map[string]interface{}{
+		"github": map[string]interface{}{
+			"event_name": "push",
+			"ref":        "refs/heads/main",
+			"event": map[string]interface{}{
+				"pull_request": map[string]interface{}{
+					"labels": []interface{}{
+						map[string]interface{}{"name": "bug"},
+						map[string]interface{}{"name": "deploy"},
+					},
+				},
+			},
+		},
+		"matrix": map[string]interface{}{"os": "ubuntu-latest", "shard": float64(2)},
+		"needs":  map[string]interface{}{"build-job": map[string]interface{}{"result": "success", "outputs": map[string]interface{}{"version": "1.2.0"}}},
+		"inputs": map[string]interface{}{"dry_run": "false"},
+	}
+
+	tests := []struct {
+		expression string
+		want       interface{}
+		truthy     bool
+	}{
+		{"${{ github.event_name == 'push' && github.ref == 'refs/heads/main' }}", true, true},
+		{"github.event_name == 'PUSH'", true, true},
+		{"github.EVENT_NAME", "push", true},
+		{"github.event_name == 'pull_request' || 'fallback'", "fallback", true},
+		{"needs.build-job.result == 'success' && needs['build-job'].outputs.version", "1.2.0", true},
+		{"contains(github.event.pull_request.labels.*.name, 'Deploy')", true, true},
+		{"contains(github.ref, 'heads')", true, true},
+		{"startsWith(github.ref, 'refs/tags/')", false, false},
+		{"endsWith(matrix.os, '-latest')", true, true},
+		{"matrix.shard >= 2 && matrix.shard < 3", true, true},
+		{"matrix.shard == '2'", true, true},
+		{"inputs.dry_run", "false", true},
+		{"!fromJSON(inputs.dry_run)", true, true},
+		{"format('{0}-{1} {{x}}', matrix.os, matrix.shard)", "ubuntu-latest-2 {x}", true},
+		{"join(github.event.pull_request.labels.*.name, ', ')", "bug, deploy", true},
+		{"toJSON(matrix.shard)", "2", true},
+		{"null == 0 && '' == false", true, true},
+		{"0x10 == 16", true, true},
+		{"'it''s'", "it's", true},
+		{"github.event.missing.deep", nil, false},
+		{"Deploy ${{ matrix.os }} #${{ matrix.shard }}", "Deploy ubuntu-latest #2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			result, err := EvaluateExpression(tt.expression, contexts, ExpressionOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Result)
+			assert.Equal(t, tt.truthy, result.Truthy)
+		})
+	}
+}
+
+func TestEvaluateExpression_Condition(t *testing.T) {
+	contexts := map[string]interface{}{"github": map[string]interface{}{"event_name": "push"}}
+
+	result, err := EvaluateExpression("github.event_name == 'push'", contexts, ExpressionOptions{Condition: true, Status: ExpressionStatusFailure})
+	require.NoError(t, err)
+	assert.True(t, result.ImplicitSuccess)
+	assert.Equal(t, "success() && (github.event_name == 'push')", result.Expression)
+	assert.False(t, result.Truthy)
+	require.NotEmpty(t, result.Steps)
+	assert.Equal(t, ExpressionStep{Expression: "success()", Value: false}, result.Steps[0])
+
+	result, err = EvaluateExpression("${{ always() && github.event_name == 'push' }}", contexts, ExpressionOptions{Condition: true, Status: ExpressionStatusFailure})
+	require.NoError(t, err)
+	assert.False(t, result.ImplicitSuccess)
+	assert.True(t, result.Truthy)
+	assert.Equal(t, []ExpressionStep{
+		{Expression: "always()", Value: true},
+		{Expression: "github.event_name", Value: "push"},
+		{Expression: "github.event_name == 'push'", Value: true},
+		{Expression: "always() && github.event_name == 'push'", Value: true},
+	}, result.Steps)
+
+	result, err = EvaluateExpression("failure() && steps.test.outcome == 'failure'", nil, ExpressionOptions{Condition: true, Status: ExpressionStatusFailure})
+	require.NoError(t, err)
+	assert.False(t, result.Truthy)
+	assert.Equal(t, []string{"the steps context was not provided, so its properties evaluate to null"}, result.Warnings)
+
+	result, err = EvaluateExpression("build ${{ github.event_name == 'push' }}", contexts, ExpressionOptions{Condition: true})
+	require.NoError(t, err)
+	assert.True(t, result.Truthy)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "always true")
+}
+
+func TestEvaluateExpression_Errors(t *testing.T) {
+	for expression, want := range map[string]string{
+		"github.ref ==":          "unexpected end",
+		"(github.ref":            `expected ")"`,
+		"foo.bar":                "unrecognized named-value",
+		"nope()":                 "unknown function",
+		"hashFiles('**/go.sum')": "cannot be evaluated",
+		"contains('a')":          "argument",
+		"format('{1}', 'a')":     "invalid placeholder",
+		"'unterminated":          "unterminated string",
+		"github.ref = 'x'":       "unexpected character",
+		"fromJSON('{not json')":  "fromJSON",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := EvaluateExpression(expression, nil, ExpressionOptions{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), want)
+		})
+	}
+
+	_, err := EvaluateExpression("always()", nil, ExpressionOptions{Status: "skipped"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+const (
+	// DefaultHeatmapDays is the window of the failure heatmap when none is given.
+	DefaultHeatmapDays = 30
+	// DefaultHeatmapBucketHours is the width of a heatmap column when none is given.
+	DefaultHeatmapBucketHours = 3
+	// maxHeatmapRuns bounds the runs read for a heatmap; the API returns at most 1000.
+	maxHeatmapRuns = 1000
+)
+
+// heatmapDays orders the heatmap rows Monday first.
+var heatmapDays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// HeatmapRow counts one weekday's runs and failures per time-of-day bucket.
+type HeatmapRow struct {
+	Day      string `json:"day"`
+	Runs     []int  `json:"runs"`
+	Failures []int  `json:"failures"`
+}
+
+// HeatmapHotspot is a time slot whose runs fail much more often than the rest.
+type HeatmapHotspot struct {
+	Day         string   `json:"day"`
+	Hours       string   `json:"hours"` // e.g. 02:00-03:00
+	Runs        int      `json:"runs"`
+	Failures    int      `json:"failures"`
+	FailureRate float64  `json:"failure_rate"`
+	Workflows   []string `json:"workflows"` // Failing workflows, most failures first
+	Events      []string `json:"events"`    // Triggers of the failing runs, most failures first
+}
+
+// FailureHeatmap buckets completed runs by weekday and time of day (by creation time, in
+// the client's time zone, UTC by default).
+type FailureHeatmap struct {
+	Workflow    string            `json:"workflow,omitempty"`
+	Days        int               `json:"days"`
+	BucketHours int               `json:"bucket_hours"`
+	Timezone    string            `json:"timezone"`
+	Runs        int               `json:"runs"`
+	Failures    int               `json:"failures"`
+	Columns     []string          `json:"columns"` // Bucket start times, e.g. 00:00, 03:00
+	Rows        []*HeatmapRow     `json:"rows"`    // Monday first
+	Hotspots    []*HeatmapHotspot `json:"hotspots"`
+	Markdown    string            `json:"markdown"`
+	Truncated   bool              `json:"truncated,omitempty"` // More runs than could be read
+}
+
+// GetFailureHeatmap reads the completed runs of the last days (default DefaultHeatmapDays),
+// optionally of one workflow, and counts runs and failures per weekday and bucketHours-wide
+// time-of-day slot (default DefaultHeatmapBucketHours; must divide 24), so failures tied to a
+// time, such as nightly maintenance breaking a 02:00 scheduled run, stand out.
+func (c *Client) GetFailureHeatmap(ctx context.Context, workflow string, days, bucketHours int) (*FailureHeatmap, error) {
+	if days <= 0 {
+		days = DefaultHeatmapDays
+	}
+	if bucketHours <= 0 {
+		bucketHours = DefaultHeatmapBucketHours
+	}
+	if bucketHours > 24 || 24%bucketHours != 0 {
+		return nil, fmt.Errorf("bucket_hours must divide 24, got %d", bucketHours)
+	}
+
+	var workflowID int64
+	var workflowName string
+	if workflow != "" {
+		var err error
+		workflowID, workflowName, err = c.ResolveWorkflowID(ctx, workflow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve workflow %q: %w", workflow, err)
+		}
+	}
+
+	since := timeNow().UTC().AddDate(0, 0, -days)
+	opts := &github.ListWorkflowRunsOptions{
+		Status:      "completed",
+		Created:     ">=" + since.Format("2006-01-02"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	var runs []*github.WorkflowRun
+	truncated := false
+	for {
+		var page *github.WorkflowRuns
+		var resp *github.Response
+		var err error
+		if workflowID != 0 {
+			page, resp, err = c.gh.Actions.ListWorkflowRunsByID(ctx, c.owner, c.repo, workflowID, opts)
+		} else {
+			page, resp, err = c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs: %w", Classify(err))
+		}
+		for _, run := range page.WorkflowRuns {
+			// The created filter works on dates; drop the runs earlier that day.
+			if !run.GetCreatedAt().Before(since) {
+				runs = append(runs, run)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		if len(runs) >= maxHeatmapRuns {
+			truncated = true
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	heatmap := buildFailureHeatmap(runs, bucketHours, c.location)
+	heatmap.Workflow = workflowName
+	heatmap.Days = days
+	heatmap.Truncated = truncated
+	return heatmap, nil
+}
+
+// buildFailureHeatmap counts runs and failures per weekday and time slot, and picks the
+// slots that fail at least twice, at least half the time, and at least 1.5 times as often
+// as runs overall. Runs are placed by their creation time in loc (UTC when nil).
+func buildFailureHeatmap(runs []*github.WorkflowRun, bucketHours int, loc *time.Location) *FailureHeatmap {
+	if loc == nil {
+		loc = time.UTC
+	}
+	buckets := 24 / bucketHours
+	heatmap := &FailureHeatmap{BucketHours: bucketHours, Timezone: loc.String(), Hotspots: []*HeatmapHotspot{}}
+	for b := 0; b < buckets; b++ {
+		heatmap.Columns = append(heatmap.Columns, fmt.Sprintf("%02d:00", b*bucketHours))
+	}
+	rowOf := make(map[time.Weekday]*HeatmapRow)
+	for _, day := range heatmapDays {
+		row := &HeatmapRow{Day: day.String()[:3], Runs: make([]int, buckets), Failures: make([]int, buckets)}
+		rowOf[day] = row
+		heatmap.Rows = append(heatmap.Rows, row)
+	}
+
+	type slot struct {
+		day    time.Weekday
+		bucket int
+	}
+	workflows := make(map[slot]map[string]int)
+	events := make(map[slot]map[string]int)
+	for _, run := range runs {
+		if run.GetStatus() != "completed" || run.CreatedAt == nil {
+			continue
+		}
+		created := run.GetCreatedAt().In(loc)
+		s := slot{created.Weekday(), created.Hour() / bucketHours}
+		row := rowOf[s.day]
+		row.Runs[s.bucket]++
+		heatmap.Runs++
+		if !isFailureConclusion(run.GetConclusion()) {
+			continue
+		}
+		row.Failures[s.bucket]++
+		heatmap.Failures++
+		if workflows[s] == nil {
+			workflows[s], events[s] = make(map[string]int), make(map[string]int)
+		}
+		workflows[s][run.GetName()]++
+		events[s][run.GetEvent()]++
+	}
+
+	overall := 0.0
+	if heatmap.Runs > 0 {
+		overall = float64(heatmap.Failures) / float64(heatmap.Runs)
+	}
+	for _, day := range heatmapDays {
+		row := rowOf[day]
+		for b := range row.Runs {
+			failures, total := row.Failures[b], row.Runs[b]
+			if failures < 2 {
+				continue
+			}
+			rate := float64(failures) / float64(total)
+			if rate < 0.5 || rate < 1.5*overall {
+				continue
+			}
+			s := slot{day, b}
+			heatmap.Hotspots = append(heatmap.Hotspots, &HeatmapHotspot{
+				Day:         row.Day,
+				Hours:       fmt.Sprintf("%02d:00-%02d:00", b*bucketHours, (b+1)*bucketHours),
+				Runs:        total,
+				Failures:    failures,
+				FailureRate: math.Round(rate*100) / 100,
+				Workflows:   keysByCount(workflows[s]),
+				Events:      keysByCount(events[s]),
+			})
+		}
+	}
+	sort.SliceStable(heatmap.Hotspots, func(i, j int) bool {
+		return heatmap.Hotspots[i].Failures > heatmap.Hotspots[j].Failures
+	})
+
+	heatmap.Markdown = renderHeatmapMarkdown(heatmap)
+	return heatmap
+}
+
+// renderHeatmapMarkdown renders the heatmap as a table of failures/runs per slot, with
+// "·" for slots without runs.
+func renderHeatmapMarkdown(heatmap *FailureHeatmap) string {
+	var b strings.Builder
+	b.WriteString("| " + heatmap.Timezone + " |")
+	for _, col := range heatmap.Columns {
+		b.WriteString(" " + col + " |")
+	}
+	b.WriteString("\n|---|")
+	b.WriteString(strings.Repeat("---|", len(heatmap.Columns)))
+	b.WriteString("\n")
+	for _, row := range heatmap.Rows {
+		b.WriteString("| " + row.Day + " |")
+		for i, runs := range row.Runs {
+			if runs == 0 {
+				b.WriteString(" · |")
+			} else {
+				fmt.Fprintf(&b, " %d/%d |", row.Failures[i], runs)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// keysByCount returns the keys of counts, highest count first, then by name.
+func keysByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFailureHeatmap(t *testing.T) {
+	run := func(name, event, conclusion string, created time.Time) *githubapi.WorkflowRun {
+		return &githubapi.WorkflowRun{
+			Name:       githubapi.Ptr(name),
+			Event:      githubapi.Ptr(event),
+			Status:     githubapi.Ptr("completed"),
+			Conclusion: githubapi.Ptr(conclusion),
+			CreatedAt:  &githubapi.Timestamp{Time: created},
+		}
+	}
+	// 2024-01-15 is a Monday.
+	day := func(d, h, m int) time.Time { return time.Date(2024, 1, 15+d, h, m, 0, 0, time.UTC) }
+	runs := []*githubapi.WorkflowRun{
+		run("Nightly", "schedule", "failure", day(0, 2, 5)),
+		run("Nightly", "schedule", "failure", day(7, 2, 10)),
+		run("Nightly", "schedule", "timed_out", day(14, 2, 0)),
+		run("CI", "push", "failure", day(7, 2, 30)),
+		run("CI", "push", "success", day(0, 10, 0)),
+		run("CI", "push", "success", day(1, 11, 0)),
+		run("CI", "push", "failure", day(1, 12, 0)),
+		run("CI", "push", "success", day(6, 23, 59)),
+		run("CI", "push", "cancelled", day(2, 9, 0)),
+		{Status: githubapi.Ptr("in_progress"), CreatedAt: &githubapi.Timestamp{Time: day(0, 2, 0)}},
+	}
+
+	heatmap := buildFailureHeatmap(runs, 6, nil)
+	assert.Equal(t, []string{"00:00", "06:00", "12:00", "18:00"}, heatmap.Columns)
+	assert.Equal(t, 9, heatmap.Runs)
+	assert.Equal(t, 5, heatmap.Failures)
+	require.Len(t, heatmap.Rows, 7)
+	assert.Equal(t, &HeatmapRow{Day: "Mon", Runs: []int{4, 1, 0, 0}, Failures: []int{4, 0, 0, 0}}, heatmap.Rows[0])
+	assert.Equal(t, []int{0, 0, 0, 1}, heatmap.Rows[6].Runs)
+
+	require.Len(t, heatmap.Hotspots, 1)
+	assert.Equal(t, &HeatmapHotspot{
+		Day: "Mon", Hours: "00:00-06:00", Runs: 4, Failures: 4, FailureRate: 1,
+		Workflows: []string{"Nightly", "CI"}, Events: []string{"schedule", "push"},
+	}, heatmap.Hotspots[0])
+
+	assert.Equal(t, "| UTC | 00:00 | 06:00 | 12:00 | 18:00 |\n"+
+		"|---|---|---|---|---|\n"+
+		"| Mon | 4/4 | 0/1 | · | · |\n"+
+		"| Tue | · | 0/1 | 1/1 | · |\n"+
+		"| Wed | · | 0/1 | · | · |\n"+
+		"| Thu | · | · | · | · |\n"+
+		"| Fri | · | · | · | · |\n"+
+		"| Sat | · | · | · | · |\n"+
+		"| Sun | · | · | · | 0/1 |\n", heatmap.Markdown)
+}
+
+func TestBuildFailureHeatmap_Location(t *testing.T) {
+	// 23:30 UTC on Sunday is 01:30 on Monday two hours east.
+	runs := []*githubapi.WorkflowRun{{
+		Status:     githubapi.Ptr("completed"),
+		Conclusion: githubapi.Ptr("failure"),
+		CreatedAt:  &githubapi.Timestamp{Time: time.Date(2024, 1, 21, 23, 30, 0, 0, time.UTC)},
+	}}
+
+	heatmap := buildFailureHeatmap(runs, 6, time.FixedZone("EET", 2*3600))
+	assert.Equal(t, "EET", heatmap.Timezone)
+	assert.Equal(t, []int{1, 0, 0, 0}, heatmap.Rows[0].Runs)
+	assert.Equal(t, []int{0, 0, 0, 0}, heatmap.Rows[6].Runs)
+	assert.True(t, strings.HasPrefix(heatmap.Markdown, "| EET |"))
+}
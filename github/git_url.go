@@ -0,0 +1,111 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// HostPolicy decides which hosts a git remote URL may point to. github.com (including
+// subdomains such as ssh.github.com) is always accepted.
+type HostPolicy struct {
+	// Hosts lists further accepted hosts, e.g. a GitHub Enterprise Server host.
+	Hosts []string
+	// AnyHost accepts every host.
+	AnyHost bool
+}
+
+// DefaultHostPolicy accepts github.com only.
+var DefaultHostPolicy = HostPolicy{}
+
+// Allows reports whether host is accepted by the policy.
+func (p HostPolicy) Allows(host string) bool {
+	host = canonicalRemoteHost(host)
+	if host == DefaultHost || p.AnyHost {
+		return true
+	}
+	for _, h := range p.Hosts {
+		if NormalizeHost(h) == host {
+			return true
+		}
+	}
+	return false
+}
+
+// RepoURL is the repository a git remote URL points to. Host is empty for a bare
+// "owner/repo" reference.
+type RepoURL struct {
+	Host  string `json:"host,omitempty"`
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+}
+
+// scpLikeURLPattern matches scp-style SSH remotes such as git@github.com:owner/repo.git.
+var scpLikeURLPattern = regexp.MustCompile(`^(?:[^@/:]+@)?([^@/:]+):(.+)$`)
+
+// ParseRepoURL is the single parser for git remote URLs. It accepts HTTPS, SSH (both
+// ssh:// and scp-style user@host:path), git://, and bare owner/repo forms. Git
+// url.<base>.insteadOf rewrites (e.g. gh-proxy) are reversed first, URLs that still
+// carry a token are refused, and the host must be allowed by policy.
+func ParseRepoURL(remoteURL string, policy HostPolicy) (*RepoURL, error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	// Undo git insteadOf rewrites such as gh-proxy so that a proxied remote
+	// URL is translated back to its original github.com form.
+	if reversed, revErr := ReverseInsteadOf(remoteURL); revErr == nil {
+		remoteURL = reversed
+	}
+
+	if containsToken(remoteURL) {
+		return nil, fmt.Errorf("URL appears to contain a token (refusing for security)")
+	}
+
+	var host, path string
+	// URLs with a scheme may carry extra path segments (e.g. a pasted browser URL);
+	// the other forms must be exactly owner/repo.
+	exact := true
+	switch {
+	case strings.Contains(remoteURL, "://"):
+		u, err := url.Parse(remoteURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse URL: %w", err)
+		}
+		switch u.Scheme {
+		case "https", "http", "ssh", "git":
+		default:
+			return nil, fmt.Errorf("unsupported URL scheme %q: %s", u.Scheme, remoteURL)
+		}
+		host, path = u.Hostname(), u.Path
+		exact = u.Scheme == "ssh"
+	case scpLikeURLPattern.MatchString(remoteURL):
+		m := scpLikeURLPattern.FindStringSubmatch(remoteURL)
+		host, path = m[1], m[2]
+	default:
+		path = remoteURL
+	}
+
+	if host != "" {
+		if !policy.Allows(host) {
+			return nil, fmt.Errorf("not a GitHub URL: %s", remoteURL)
+		}
+		host = canonicalRemoteHost(host)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || (exact && len(parts) != 2) || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("could not parse owner/repo from URL: %s", remoteURL)
+	}
+	return &RepoURL{Host: host, Owner: parts[0], Repo: strings.TrimSuffix(parts[1], ".git")}, nil
+}
+
+// canonicalRemoteHost normalizes a remote's host, mapping github.com subdomains used
+// for git transport (ssh.github.com, www.github.com) to github.com.
+func canonicalRemoteHost(host string) string {
+	host = NormalizeHost(host)
+	if strings.HasSuffix(host, ".github.com") {
+		return DefaultHost
+	}
+	return host
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepoURL(t *testing.T) {
+	enterprise := HostPolicy{Hosts: []string{"github.mycompany.com"}}
+
+	tests := []struct {
+		name    string
+		url     string
+		policy  HostPolicy
+		want    *RepoURL
+		wantErr string
+	}{
+		{name: "https", url: "https://github.com/owner/repo.git", want: &RepoURL{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{name: "https browser URL", url: "https://github.com/owner/repo/actions/runs/1", want: &RepoURL{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{name: "scp-style ssh", url: "git@github.com:owner/repo.git", want: &RepoURL{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{name: "ssh scheme", url: "ssh://git@github.com/owner/repo.git", want: &RepoURL{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{name: "ssh over https port", url: "ssh://git@ssh.github.com:443/owner/repo.git", want: &RepoURL{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{name: "managed user ssh", url: "org-123@github.com:owner/repo.git", want: &RepoURL{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{name: "git protocol", url: "git://github.com/owner/repo.git", want: &RepoURL{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{name: "bare", url: "owner/repo", want: &RepoURL{Owner: "owner", Repo: "repo"}},
+		{name: "enterprise ssh rejected by default", url: "git@github.mycompany.com:owner/repo.git", wantErr: "not a GitHub URL"},
+		{name: "enterprise https rejected by default", url: "https://github.mycompany.com/owner/repo.git", wantErr: "not a GitHub URL"},
+		{name: "enterprise ssh allowed", url: "git@github.mycompany.com:owner/repo.git", policy: enterprise, want: &RepoURL{Host: "github.mycompany.com", Owner: "owner", Repo: "repo"}},
+		{name: "enterprise https allowed", url: "https://GitHub.MyCompany.com/owner/repo", policy: enterprise, want: &RepoURL{Host: "github.mycompany.com", Owner: "owner", Repo: "repo"}},
+		{name: "any host", url: "https://gitlab.com/owner/repo.git", policy: HostPolicy{AnyHost: true}, want: &RepoURL{Host: "gitlab.com", Owner: "owner", Repo: "repo"}},
+		{name: "token in https", url: "https://ghp_abc@github.com/owner/repo.git", wantErr: "token"},
+		{name: "token in ssh", url: "ghp_abc@github.com:owner/repo.git", policy: enterprise, wantErr: "token"},
+		{name: "unsupported scheme", url: "ftp://github.com/owner/repo", wantErr: "unsupported URL scheme"},
+		{name: "scp-style missing repo", url: "git@github.com:owner", wantErr: "could not parse"},
+		{name: "ssh extra path", url: "ssh://git@github.com/owner/repo/extra", wantErr: "could not parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRepoURL(tt.url, tt.policy)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHostPolicyAllows(t *testing.T) {
+	assert.True(t, DefaultHostPolicy.Allows("github.com"))
+	assert.True(t, DefaultHostPolicy.Allows("ssh.github.com"))
+	assert.False(t, DefaultHostPolicy.Allows("github.mycompany.com"))
+	assert.True(t, HostPolicy{Hosts: []string{"https://GHE.example.com/"}}.Allows("ghe.example.com"))
+}
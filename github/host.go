@@ -0,0 +1,62 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultHost is the host used when none is configured.
+const DefaultHost = "github.com"
+
+// NormalizeHost lowercases host and strips any scheme or trailing slash. The empty host
+// and api.github.com both mean github.com.
+func NormalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	if host == "" || host == "api.github.com" {
+		return DefaultHost
+	}
+	return host
+}
+
+// IsDefaultHost reports whether host is github.com.
+func IsDefaultHost(host string) bool {
+	return NormalizeHost(host) == DefaultHost
+}
+
+// APIURLsForHost returns the REST API and upload base URLs for a GitHub host, following
+// the gh CLI: github.com needs no override, GHE.com tenants use api.<host>, and GitHub
+// Enterprise Server serves the API under /api/v3/.
+func APIURLsForHost(host string) (string, string) {
+	host = NormalizeHost(host)
+	switch {
+	case host == DefaultHost:
+		return "", ""
+	case strings.HasSuffix(host, ".ghe.com"):
+		return "https://api." + host + "/", "https://uploads." + host + "/"
+	default:
+		return "https://" + host + "/api/v3/", "https://" + host + "/api/uploads/"
+	}
+}
+
+// ParseRepoRef splits an "owner/repo" or "host/owner/repo" reference. The host is empty
+// when the reference does not name one.
+func ParseRepoRef(ref string) (host, owner, repo string, err error) {
+	parts := strings.Split(strings.TrimSpace(ref), "/")
+	switch len(parts) {
+	case 2:
+		owner, repo = parts[0], parts[1]
+	case 3:
+		host, owner, repo = parts[0], parts[1], parts[2]
+		if host == "" {
+			return "", "", "", fmt.Errorf("invalid repository %q: expected [host/]owner/repo", ref)
+		}
+	default:
+		return "", "", "", fmt.Errorf("invalid repository %q: expected [host/]owner/repo", ref)
+	}
+	if owner == "" || repo == "" {
+		return "", "", "", fmt.Errorf("invalid repository %q: expected [host/]owner/repo", ref)
+	}
+	return host, owner, repo, nil
+}
@@ -0,0 +1,57 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIURLsForHost(t *testing.T) {
+	api, upload := APIURLsForHost("")
+	assert.Empty(t, api)
+	assert.Empty(t, upload)
+
+	api, _ = APIURLsForHost("api.github.com")
+	assert.Empty(t, api)
+
+	api, upload = APIURLsForHost("https://GHE.example.com/")
+	assert.Equal(t, "https://ghe.example.com/api/v3/", api)
+	assert.Equal(t, "https://ghe.example.com/api/uploads/", upload)
+
+	api, upload = APIURLsForHost("acme.ghe.com")
+	assert.Equal(t, "https://api.acme.ghe.com/", api)
+	assert.Equal(t, "https://uploads.acme.ghe.com/", upload)
+}
+
+func TestParseRepoRef(t *testing.T) {
+	host, owner, repo, err := ParseRepoRef("octo/hello")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "octo", "hello"}, []string{host, owner, repo})
+
+	host, owner, repo, err = ParseRepoRef(" ghe.example.com/octo/hello ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ghe.example.com", "octo", "hello"}, []string{host, owner, repo})
+
+	for _, bad := range []string{"octo", "octo/", "/octo/hello", "a/b/c/d"} {
+		_, _, _, err = ParseRepoRef(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestNewClientWithOptions_Host(t *testing.T) {
+	c, err := NewClientWithOptions(ClientOptions{Owner: "octo", Repo: "hello", Host: "ghe.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://ghe.example.com/api/v3/", c.gh.BaseURL.String())
+	assert.Equal(t, "ghe.example.com/octo/hello", c.FullName())
+
+	// An explicit API URL wins over the host.
+	c, err = NewClientWithOptions(ClientOptions{Owner: "octo", Repo: "hello", Host: "ghe.example.com", APIBaseURL: "http://proxy:8080/api/"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy:8080/api/", c.gh.BaseURL.String())
+
+	c, err = NewClientWithOptions(ClientOptions{Owner: "octo", Repo: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.github.com/", c.gh.BaseURL.String())
+	assert.Equal(t, "octo/hello", c.FullName())
+}
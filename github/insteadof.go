@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync/atomic"
 )
 
 // insteadOfRule represents a single git url.<base>.insteadOf rule.
@@ -14,6 +15,16 @@ type insteadOfRule struct {
 	value string
 }
 
+// gitCommandsDisabled stops ReverseInsteadOf from running git; see DisableGitCommands.
+var gitCommandsDisabled atomic.Bool
+
+// DisableGitCommands stops the package from running the git executable, for
+// environments such as minimal container images that do not have it. Remote URLs are
+// then parsed without undoing insteadOf rewrites.
+func DisableGitCommands() {
+	gitCommandsDisabled.Store(true)
+}
+
 // ReverseInsteadOf queries git config for url.<base>.insteadOf rules and
 // returns the original URL by undoing the rewrite. If no rule matches, the
 // input URL is returned unchanged.
@@ -28,6 +39,9 @@ func ReverseInsteadOf(remoteURL string) (string, error) {
 // loadInsteadOfRules shells out to git config to collect all
 // url.<base>.insteadOf entries from the usual config files.
 func loadInsteadOfRules() ([]insteadOfRule, error) {
+	if gitCommandsDisabled.Load() {
+		return nil, nil
+	}
 	cmd := exec.Command("git", "config", "--includes", "--get-regexp", `^url\..*\.insteadof$`)
 	output, err := cmd.Output()
 	if err != nil {
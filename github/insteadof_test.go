@@ -180,3 +180,17 @@ func TestParseGitURL_GhProxyURL(t *testing.T) {
 	assert.Equal(t, "denysvitali", owner)
 	assert.Equal(t, "jaeger_flutter", repo)
 }
+
+func TestReverseInsteadOf_GitCommandsDisabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "gitconfig")
+	require.NoError(t, os.WriteFile(configPath, []byte("[url \"http://proxy/git/\"]\n\tinsteadOf = https://github.com/\n"), 0o600))
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+
+	DisableGitCommands()
+	t.Cleanup(func() { gitCommandsDisabled.Store(false) })
+
+	result, err := ReverseInsteadOf("http://proxy/git/owner/repo.git")
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy/git/owner/repo.git", result)
+}
@@ -0,0 +1,44 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+// JobDetails is a job with its run context and the steps that did not succeed picked out.
+type JobDetails struct {
+	*Job
+	RunAttempt   int64   `json:"run_attempt,omitempty"`
+	WorkflowName string  `json:"workflow_name,omitempty"`
+	HeadBranch   string  `json:"head_branch,omitempty"`
+	HeadSHA      string  `json:"head_sha,omitempty"`
+	HTMLURL      string  `json:"html_url,omitempty"`
+	FailedSteps  []*Step `json:"failed_steps,omitempty"` // Steps that failed, timed out, or were cancelled
+	CurrentStep  *Step   `json:"current_step,omitempty"` // The step in progress, while the job runs
+}
+
+// GetJobDetails returns a job's metadata and all of its steps with their number, status,
+// conclusion, and duration, so the failing step can be found before any log is read.
+func (c *Client) GetJobDetails(ctx context.Context, jobID int64) (*JobDetails, error) {
+	job, _, err := c.gh.Actions.GetWorkflowJobByID(ctx, c.owner, c.repo, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %d: %w", jobID, Classify(err))
+	}
+	details := &JobDetails{
+		Job:          jobFromGitHub(job),
+		RunAttempt:   job.GetRunAttempt(),
+		WorkflowName: job.GetWorkflowName(),
+		HeadBranch:   job.GetHeadBranch(),
+		HeadSHA:      job.GetHeadSHA(),
+		HTMLURL:      job.GetHTMLURL(),
+	}
+	for _, step := range details.Steps {
+		switch {
+		case step.Status == "in_progress":
+			details.CurrentStep = step
+		case isFailureConclusion(step.Conclusion) || step.Conclusion == "cancelled":
+			details.FailedSteps = append(details.FailedSteps, step)
+		}
+	}
+	return details, nil
+}
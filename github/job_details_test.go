@@ -0,0 +1,63 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJobDetails(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/jobs/42", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": 42, "run_id": 7, "run_attempt": 2, "name": "test", "workflow_name": "CI",
+			"head_branch": "main", "head_sha": "abc123", "html_url": "https://github.com/o/r/actions/runs/7/job/42",
+			"status": "completed", "conclusion": "failure",
+			"started_at": "2026-01-01T10:00:00Z", "completed_at": "2026-01-01T10:05:00Z",
+			"labels": ["ubuntu-latest"],
+			"steps": [
+				{"name": "Set up job", "number": 1, "status": "completed", "conclusion": "success", "started_at": "2026-01-01T10:00:00Z", "completed_at": "2026-01-01T10:00:10Z"},
+				{"name": "Run tests", "number": 2, "status": "completed", "conclusion": "failure", "started_at": "2026-01-01T10:00:10Z", "completed_at": "2026-01-01T10:04:50Z"},
+				{"name": "Upload coverage", "number": 3, "status": "completed", "conclusion": "skipped"}
+			]
+		}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	details, err := client.GetJobDetails(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), details.ID)
+	assert.Equal(t, int64(7), details.WorkflowRunID)
+	assert.Equal(t, int64(2), details.RunAttempt)
+	assert.Equal(t, "CI", details.WorkflowName)
+	assert.Equal(t, float64(300), details.DurationSeconds)
+	require.Len(t, details.Steps, 3)
+	assert.Equal(t, float64(280), details.Steps[1].DurationSeconds)
+	require.Len(t, details.FailedSteps, 1)
+	assert.Equal(t, "Run tests", details.FailedSteps[0].Name)
+	assert.Nil(t, details.CurrentStep)
+
+	_, err = client.GetJobDetails(context.Background(), 43)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
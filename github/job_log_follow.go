@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// JobLogChunk is the part of a job's log written since a given byte offset.
+type JobLogChunk struct {
+	Content   string `json:"content"`
+	Offset    int64  `json:"offset"` // offset to pass to the next call
+	Status    string `json:"status"`
+	Completed bool   `json:"completed"`
+}
+
+// ReadJobLogFrom returns the log of a job from byte offset onwards, along with the job's
+// status, so callers can follow a running job by passing back the returned offset. Only
+// the new bytes are downloaded when the log storage honours range requests. A log that is
+// not available yet is reported as an empty chunk rather than an error.
+func (c *Client) ReadJobLogFrom(ctx context.Context, jobID, offset int64) (*JobLogChunk, error) {
+	job, _, err := c.gh.Actions.GetWorkflowJobByID(ctx, c.owner, c.repo, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %d: %w", jobID, Classify(err))
+	}
+	chunk := &JobLogChunk{
+		Offset:    offset,
+		Status:    job.GetStatus(),
+		Completed: job.GetStatus() == "completed",
+	}
+
+	url, resp, err := c.gh.Actions.GetWorkflowJobLogs(ctx, c.owner, c.repo, jobID, maxRedirects)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound && !chunk.Completed {
+			return chunk, nil
+		}
+		return nil, fmt.Errorf("failed to get job log URL for job %d: %w", jobID, logsError(resp, err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build job log request for job %d: %w", jobID, err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+	logResp, err := presignedHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch job logs for job %d: %w", jobID, err)
+	}
+	defer logResp.Body.Close()
+
+	var skip int64
+	switch logResp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// Range was ignored; discard what the caller has already seen.
+		skip = offset
+	case http.StatusRequestedRangeNotSatisfiable:
+		return chunk, nil
+	default:
+		return nil, &HTTPError{StatusCode: logResp.StatusCode, Message: fmt.Sprintf("failed to fetch job logs: HTTP %d", logResp.StatusCode)}
+	}
+
+	if skip > 0 {
+		if _, err := io.CopyN(io.Discard, logResp.Body, skip); err != nil {
+			// The log is no longer than what was already read.
+			return chunk, nil
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(logResp.Body, maxLogFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job logs for job %d: %w", jobID, err)
+	}
+
+	chunk.Content = string(data)
+	chunk.Offset = offset + int64(len(data))
+	return chunk, nil
+}
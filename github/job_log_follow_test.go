@@ -0,0 +1,84 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJobLogTestClient(t *testing.T, status string, log *[]byte, ranges *[]string) *Client {
+	t.Helper()
+	const owner, repo = "example-owner", "example-repo"
+
+	mux := http.NewServeMux()
+	redirectBase := ""
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/jobs/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 7, "status": "` + status + `"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/jobs/7/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", redirectBase+"/blob/job.log")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/blob/job.log", func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "job.log", time.Time{}, bytes.NewReader(*log))
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	redirectBase = ts.URL
+
+	ghc := githubapi.NewClient(ts.Client())
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	return &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+}
+
+func TestReadJobLogFrom_Incremental(t *testing.T) {
+	log := []byte("line-1\n")
+	var ranges []string
+	client := newJobLogTestClient(t, "in_progress", &log, &ranges)
+	ctx := context.Background()
+
+	chunk, err := client.ReadJobLogFrom(ctx, 7, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "line-1\n", chunk.Content)
+	assert.Equal(t, int64(7), chunk.Offset)
+	assert.False(t, chunk.Completed)
+
+	log = append(log, "line-2\n"...)
+	chunk, err = client.ReadJobLogFrom(ctx, 7, chunk.Offset)
+	require.NoError(t, err)
+	assert.Equal(t, "line-2\n", chunk.Content)
+	assert.Equal(t, int64(14), chunk.Offset)
+
+	// Nothing new: the storage answers 416.
+	chunk, err = client.ReadJobLogFrom(ctx, 7, chunk.Offset)
+	require.NoError(t, err)
+	assert.Empty(t, chunk.Content)
+	assert.Equal(t, int64(14), chunk.Offset)
+
+	assert.Equal(t, []string{"", "bytes=7-", "bytes=14-"}, ranges)
+}
+
+func TestReadJobLogFrom_Completed(t *testing.T) {
+	log := []byte("done\n")
+	var ranges []string
+	client := newJobLogTestClient(t, "completed", &log, &ranges)
+
+	chunk, err := client.ReadJobLogFrom(context.Background(), 7, 0)
+	require.NoError(t, err)
+	assert.True(t, chunk.Completed)
+	assert.Equal(t, "completed", chunk.Status)
+	assert.Equal(t, "done\n", chunk.Content)
+}
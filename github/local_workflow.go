@@ -0,0 +1,38 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// localWorkflowDrift compares the workflow file at path in the local checkout dir with the
+// copy at ref in the repository, which is the one a dispatch on ref runs. It returns a
+// warning when they differ, or "" when they match or either cannot be read; a workflow
+// missing from the checkout is not treated as an edit. Line endings are ignored.
+func (c *Client) localWorkflowDrift(ctx context.Context, dir, path, ref string) string {
+	local, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Debugf("Could not read local %s: %v", path, err)
+		}
+		return ""
+	}
+	remote, err := c.GetWorkflowFile(ctx, path, ref)
+	if err != nil {
+		log.Debugf("Could not read %s at %s to compare with the local copy: %v", path, ref, err)
+		return ""
+	}
+	if bytes.Equal(normalizeNewlines(local), normalizeNewlines(remote)) {
+		return ""
+	}
+	return fmt.Sprintf("the local %s differs from the copy at %s, which is the one this run uses; commit and push local workflow edits first, or dispatch on a ref that has them", path, ref)
+}
+
+func normalizeNewlines(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+}
@@ -0,0 +1,60 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalWorkflowDrift(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+	remote := "name: Deploy\non: workflow_dispatch\njobs:\n  deploy:\n    runs-on: ubuntu-latest\n"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/contents/.github/workflows/deploy.yml", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "production", r.URL.Query().Get("ref"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(remote)),
+		})
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	dir := t.TempDir()
+	path := ".github/workflows/deploy.yml"
+	ctx := context.Background()
+
+	// Missing from the checkout: nothing to warn about.
+	assert.Empty(t, client.localWorkflowDrift(ctx, dir, path, "production"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github", "workflows"), 0o755))
+	local := filepath.Join(dir, filepath.FromSlash(path))
+	require.NoError(t, os.WriteFile(local, []byte("name: Deploy\r\non: workflow_dispatch\r\njobs:\r\n  deploy:\r\n    runs-on: ubuntu-latest\r\n"), 0o644))
+	assert.Empty(t, client.localWorkflowDrift(ctx, dir, path, "production"), "line endings are ignored")
+
+	require.NoError(t, os.WriteFile(local, []byte(remote+"    timeout-minutes: 10\n"), 0o644))
+	warning := client.localWorkflowDrift(ctx, dir, path, "production")
+	assert.Contains(t, warning, "the local .github/workflows/deploy.yml differs from the copy at production")
+}
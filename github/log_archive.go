@@ -0,0 +1,82 @@
+package github
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// LogArchiveResult describes a run's log archive saved to disk as GitHub served it.
+type LogArchiveResult struct {
+	RunID     int64  `json:"run_id"`
+	SavedPath string `json:"saved_path"`
+	SizeBytes int64  `json:"size_bytes"`
+	FileCount int    `json:"file_count"`
+}
+
+// WriteRunLogArchive copies the logs ZIP archive of a run to w without any processing:
+// no filtering, truncation, or secret masking.
+func (c *Client) WriteRunLogArchive(ctx context.Context, runID int64, w io.Writer) (int64, error) {
+	url, resp, err := c.gh.Actions.GetWorkflowRunLogs(ctx, c.owner, c.repo, runID, maxRedirects)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get workflow log URL for run %d: %w", runID, logsError(resp, err))
+	}
+
+	// Fetch the archive without auth headers; storage backends reject them on pre-signed URLs.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build log archive request: %w", err)
+	}
+	zipResp, err := presignedHTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch log archive for run %d: %w", runID, err)
+	}
+	defer zipResp.Body.Close()
+
+	if zipResp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to fetch log archive for run %d: HTTP %d", runID, zipResp.StatusCode)
+	}
+
+	n, err := io.Copy(w, zipResp.Body)
+	if err != nil {
+		return n, fmt.Errorf("failed to write log archive for run %d: %w", runID, err)
+	}
+	return n, nil
+}
+
+// SaveRunLogArchive saves the logs ZIP archive of a run to outputPath (default:
+// run-{id}-logs.zip) unchanged. A partially written file is removed on failure.
+func (c *Client) SaveRunLogArchive(ctx context.Context, runID int64, outputPath string) (*LogArchiveResult, error) {
+	if outputPath == "" {
+		outputPath = fmt.Sprintf("run-%d-logs.zip", runID)
+	}
+
+	outFile, err := os.Create(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file %q: %w", outputPath, err)
+	}
+	size, err := c.WriteRunLogArchive(ctx, runID, outFile)
+	if closeErr := outFile.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write log archive for run %d: %w", runID, closeErr)
+	}
+	if err != nil {
+		os.Remove(outputPath)
+		return nil, err
+	}
+
+	result := &LogArchiveResult{RunID: runID, SavedPath: outputPath, SizeBytes: size}
+	if zr, err := zip.OpenReader(outputPath); err == nil {
+		for _, f := range zr.File {
+			if !f.FileInfo().IsDir() {
+				result.FileCount++
+			}
+		}
+		zr.Close()
+	}
+
+	log.Infof("Saved log archive of run %d to %s (%d bytes, %d files)", runID, outputPath, size, result.FileCount)
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// logCacheMaxAge is how long a job log stays in the disk cache. Older files are removed
+// when a log of the same repository is cached.
+const logCacheMaxAge = 14 * 24 * time.Hour
+
+// cachedLogFile is a file of a job log as stored in the disk cache.
+type cachedLogFile struct {
+	Name string `json:"name"`
+	Data string `json:"data"`
+}
+
+// jobLogCachePath returns the disk cache file of a job's log, or "" when the cache is off.
+func (c *Client) jobLogCachePath(jobID int64) string {
+	if c.logCacheDir == "" {
+		return ""
+	}
+	host := strings.ReplaceAll(c.gh.BaseURL.Host, ":", "_")
+	return filepath.Join(c.logCacheDir, host, c.owner, c.repo, fmt.Sprintf("job-%d.json", jobID))
+}
+
+// cachedJobLog reads a job's log from the disk cache. Only logs of completed jobs are
+// cached, and those do not change. Contexts from WithCacheBypass skip the cache.
+func (c *Client) cachedJobLog(ctx context.Context, jobID int64) ([]logFile, bool) {
+	path := c.jobLogCachePath(jobID)
+	if path == "" || cacheBypassed(ctx) {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	info, statErr := os.Stat(path)
+	var cached []cachedLogFile
+	if err != nil || statErr != nil || json.Unmarshal(data, &cached) != nil {
+		apiMetrics.recordCacheLookup("job_log", false)
+		return nil, false
+	}
+	apiMetrics.recordCacheLookup("job_log", true)
+	recordCacheSource(ctx, CacheSourceDisk, info.ModTime())
+
+	files := make([]logFile, 0, len(cached))
+	for _, f := range cached {
+		files = append(files, logFile{name: f.Name, data: f.Data})
+	}
+	return files, true
+}
+
+// cacheJobLog stores a job's log in the disk cache when the job has completed. Failures
+// are logged and otherwise ignored.
+func (c *Client) cacheJobLog(ctx context.Context, jobID int64, files []logFile) {
+	path := c.jobLogCachePath(jobID)
+	if path == "" || len(files) == 0 {
+		return
+	}
+	job, _, err := c.gh.Actions.GetWorkflowJobByID(ctx, c.owner, c.repo, jobID)
+	if err != nil || job.GetStatus() != "completed" {
+		return
+	}
+
+	cached := make([]cachedLogFile, 0, len(files))
+	for _, f := range files {
+		cached = append(cached, cachedLogFile{Name: f.name, Data: f.data})
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		log.Debugf("Could not create log cache directory: %v", err)
+		return
+	}
+	pruneLogCache(dir)
+	// Write to a temporary file and rename it, so a concurrent reader never sees half a log.
+	tmp, err := os.CreateTemp(dir, ".job-*.tmp")
+	if err != nil {
+		log.Debugf("Could not cache log of job %d: %v", jobID, err)
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		log.Debugf("Could not cache log of job %d: %v", jobID, err)
+	}
+}
+
+// pruneLogCache removes cached logs in dir older than logCacheMaxAge.
+func pruneLogCache(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-logCacheMaxAge)
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && !entry.IsDir() && info.ModTime().Before(cutoff) {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobLogDiskCache(t *testing.T) {
+	status := "in_progress"
+	downloads := 0
+	mux := http.NewServeMux()
+	redirectBase := ""
+	mux.HandleFunc("/repos/octo/hello/actions/jobs/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 7, "status": "` + status + `"}`))
+	})
+	mux.HandleFunc("/repos/octo/hello/actions/jobs/7/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", redirectBase+"/blob/job.log")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/blob/job.log", func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		_, _ = w.Write([]byte("step 1\nstep 2\n"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	redirectBase = ts.URL
+
+	ghc := githubapi.NewClient(ts.Client())
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: "octo", repo: "hello", gh: ghc, perPageLimit: 50, logCacheDir: t.TempDir()}
+
+	// The log of a running job may still grow, so it is not cached.
+	logs, err := client.GetWorkflowJobLogs(context.Background(), 7, 0, 0, 0, true, nil)
+	require.NoError(t, err)
+	assert.Contains(t, logs, "step 2")
+	_, cached := client.cachedJobLog(context.Background(), 7)
+	assert.False(t, cached)
+
+	status = "completed"
+	_, err = client.GetWorkflowJobLogs(context.Background(), 7, 0, 0, 0, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, downloads)
+
+	ctx, trace := WithCacheTrace(context.Background())
+	logs, err = client.GetWorkflowJobLogs(ctx, 7, 0, 0, 0, true, nil)
+	require.NoError(t, err)
+	assert.Contains(t, logs, "step 1")
+	assert.Equal(t, 2, downloads)
+	p := trace.Provenance()
+	require.NotNil(t, p)
+	assert.True(t, p.Cached)
+	assert.Equal(t, CacheSourceDisk, p.Source)
+	assert.NotEmpty(t, p.AsOf)
+
+	_, err = client.GetWorkflowJobLogs(WithCacheBypass(context.Background()), 7, 0, 0, 0, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, downloads)
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// LogEntry is a single log line with the job and step it came from, for callers that
+// process logs programmatically rather than reading them.
+type LogEntry struct {
+	Job  string `json:"job"`
+	Step string `json:"step,omitempty"`
+	TS   string `json:"ts,omitempty"`
+	Line string `json:"line"`
+}
+
+// logTimestamp matches the timestamp GitHub Actions prepends to each log line.
+var logTimestamp = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?Z) ?`)
+
+// stepFilePrefix matches the "<n>_" ordering prefix of files in a log archive.
+var stepFilePrefix = regexp.MustCompile(`^\d+_`)
+
+// ParseLogEntries splits formatted log output into entries. The job and step come from
+// the "=== file ===" headers: archives hold "<job>/<n>_<step>.txt" per step and
+// "<n>_<job>.txt" per job. Where the file does not name the step, the latest ##[group]
+// title is used instead. A non-empty job overrides the job taken from headers.
+func ParseLogEntries(logs, job string) []LogEntry {
+	var entries []LogEntry
+	fileJob, fileStep, groupStep := "", "", ""
+
+	for _, raw := range strings.Split(logs, "\n") {
+		if headerPattern.MatchString(raw) {
+			fileJob, fileStep = logFileJobStep(strings.TrimSuffix(strings.TrimPrefix(raw, "=== "), " ==="))
+			groupStep = ""
+			continue
+		}
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		entry := LogEntry{Job: fileJob, Line: raw}
+		if job != "" {
+			entry.Job = job
+		}
+		if m := logTimestamp.FindStringSubmatch(raw); m != nil {
+			entry.TS = m[1]
+			entry.Line = raw[len(m[0]):]
+		}
+		if title, ok := strings.CutPrefix(entry.Line, "##[group]"); ok && fileStep == "" {
+			groupStep = title
+		}
+		entry.Step = fileStep
+		if entry.Step == "" {
+			entry.Step = groupStep
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// logFileJobStep derives the job and step from a log archive file name.
+func logFileJobStep(name string) (string, string) {
+	dir, file := path.Split(name)
+	file = strings.TrimSuffix(strings.TrimSuffix(file, ".txt"), ".log")
+	file = stepFilePrefix.ReplaceAllString(file, "")
+	if dir == "" {
+		return file, ""
+	}
+	return strings.TrimSuffix(dir, "/"), file
+}
+
+// FormatLogEntriesJSONL encodes entries as JSON lines.
+func FormatLogEntriesJSONL(entries []LogEntry) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, e := range entries {
+		_ = enc.Encode(e)
+	}
+	return buf.String()
+}
@@ -0,0 +1,49 @@
+package github
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogEntries(t *testing.T) {
+	logs := strings.Join([]string{
+		"=== build/2_Run tests.txt ===",
+		"2024-01-15T10:30:00.1234567Z go test ./...",
+		"2024-01-15T10:30:01.1234567Z FAIL",
+		"=== 1_lint.txt ===",
+		"2024-01-15T10:29:00.0000000Z ##[group]Run golangci-lint",
+		"2024-01-15T10:29:01.0000000Z ok",
+		"",
+	}, "\n")
+
+	entries := ParseLogEntries(logs, "")
+	require.Len(t, entries, 4)
+	assert.Equal(t, LogEntry{Job: "build", Step: "Run tests", TS: "2024-01-15T10:30:00.1234567Z", Line: "go test ./..."}, entries[0])
+	assert.Equal(t, "FAIL", entries[1].Line)
+	assert.Equal(t, LogEntry{Job: "lint", Step: "Run golangci-lint", TS: "2024-01-15T10:29:00.0000000Z", Line: "##[group]Run golangci-lint"}, entries[2])
+	assert.Equal(t, "Run golangci-lint", entries[3].Step)
+}
+
+func TestParseLogEntries_JobOverride(t *testing.T) {
+	entries := ParseLogEntries("=== job-42.log ===\nno timestamp here", "test (ubuntu)")
+	require.Len(t, entries, 1)
+	assert.Equal(t, LogEntry{Job: "test (ubuntu)", Line: "no timestamp here"}, entries[0])
+}
+
+func TestFormatLogEntriesJSONL(t *testing.T) {
+	out := FormatLogEntriesJSONL([]LogEntry{
+		{Job: "build", Step: "test", TS: "2024-01-15T10:30:00Z", Line: "a < b"},
+		{Job: "build", Line: "done"},
+	})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"job":"build","step":"test","ts":"2024-01-15T10:30:00Z","line":"a < b"}`, lines[0])
+
+	var e LogEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &e))
+	assert.Equal(t, "done", e.Line)
+}
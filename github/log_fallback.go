@@ -0,0 +1,109 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// runArchiveRetryDelays are the waits between attempts to download the log archive of a
+// run that completed moments ago. GitHub assembles the archive after the run completes
+// and answers 404 until it is ready, typically for up to a minute.
+var runArchiveRetryDelays = []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second}
+
+// runArchiveRetryWindow is how long after a run completes a 404 for its log archive is
+// taken to mean the archive is still being assembled rather than missing.
+const runArchiveRetryWindow = 5 * time.Minute
+
+// fetchRunLogArchive downloads and unpacks the log archive of a run.
+func (c *Client) fetchRunLogArchive(ctx context.Context, runID int64) ([]logFile, error) {
+	url, resp, err := c.gh.Actions.GetWorkflowRunLogs(ctx, c.owner, c.repo, runID, maxRedirects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow log URL for run %d: %w", runID, logsError(resp, err))
+	}
+
+	if resp != nil && resp.StatusCode != 0 {
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusFound {
+			return nil, newHTTPErrorFromGitHub(resp, "failed to get workflow logs")
+		}
+	}
+
+	// Read ZIP archive (use unauthenticated client for pre-signed storage URLs)
+	logFiles, _, err := readZipArchive(url.String(), presignedHTTPClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log archive for run %d: %w", runID, err)
+	}
+	return logFiles, nil
+}
+
+// runLogArchive downloads the log archive of a run, retrying with backoff while the
+// archive of a run that has just completed is not ready yet.
+func (c *Client) runLogArchive(ctx context.Context, runID int64) ([]logFile, error) {
+	logFiles, err := c.fetchRunLogArchive(ctx, runID)
+	if err == nil || !IsHTTPError(err, http.StatusNotFound) {
+		return logFiles, err
+	}
+
+	run, _, runErr := c.gh.Actions.GetWorkflowRunByID(ctx, c.owner, c.repo, runID)
+	if runErr != nil || run.GetStatus() != "completed" || time.Since(run.GetUpdatedAt().Time) > runArchiveRetryWindow {
+		return nil, err
+	}
+
+	for _, delay := range runArchiveRetryDelays {
+		log.Debugf("Log archive for run %d is not ready yet; retrying in %s", runID, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		logFiles, err = c.fetchRunLogArchive(ctx, runID)
+		if err == nil || !IsHTTPError(err, http.StatusNotFound) {
+			return logFiles, err
+		}
+	}
+	return nil, err
+}
+
+// runLogFiles returns the log files of a run. When GitHub has no archive for the run, as
+// happens until a run completes and for a short while after, the logs of its completed
+// jobs are read from the per-job endpoint instead, each as "<job name>/job.txt".
+func (c *Client) runLogFiles(ctx context.Context, runID int64) ([]logFile, error) {
+	logFiles, err := c.runLogArchive(ctx, runID)
+	if err == nil || !IsHTTPError(err, http.StatusNotFound) {
+		return logFiles, err
+	}
+
+	jobs, jobsErr := c.GetWorkflowJobs(ctx, runID, "latest", 0)
+	if jobsErr != nil {
+		return nil, err
+	}
+	var assembled []logFile
+	read := 0
+	for _, job := range jobs {
+		if job.Status != "completed" {
+			continue
+		}
+		files, jobErr := c.jobLogFiles(ctx, job.ID)
+		if jobErr != nil {
+			log.Debugf("Could not get logs of job %d: %v", job.ID, jobErr)
+			continue
+		}
+		read++
+		for _, f := range files {
+			name := job.Name + "/job.txt"
+			if len(files) > 1 {
+				name = job.Name + "/" + f.name
+			}
+			assembled = append(assembled, logFile{name: name, data: f.data})
+		}
+	}
+	if len(assembled) == 0 {
+		return nil, err
+	}
+	log.Infof("Log archive for run %d is not available; using the logs of %d job(s)", runID, read)
+	return assembled, nil
+}
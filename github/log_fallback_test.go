@@ -0,0 +1,100 @@
+package github
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunLogFiles_ArchiveFallbacks(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+	oldDelays := runArchiveRetryDelays
+	runArchiveRetryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+	defer func() { runArchiveRetryDelays = oldDelays }()
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	f, err := zw.Create("Build/system.txt")
+	require.NoError(t, err)
+	_, err = io.WriteString(f, "archive-line\n")
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	var archiveReadyAfter, archiveRequests int
+	runStatus := "completed"
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/100/logs", func(w http.ResponseWriter, r *http.Request) {
+		archiveRequests++
+		if archiveReadyAfter < 0 || archiveRequests <= archiveReadyAfter {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Location", ts.URL+"/blob/run.zip")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/blob/run.zip", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(zipBuf.Bytes())
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/100", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": 100, "status": %q, "updated_at": %q}`, runStatus, time.Now().UTC().Format(time.RFC3339))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/100/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "jobs": [
+			{"id": 1, "name": "Build", "status": "completed", "conclusion": "failure", "run_id": 100},
+			{"id": 2, "name": "Deploy", "status": "in_progress", "run_id": 100}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/jobs/1/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", ts.URL+"/blob/job1.txt")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/blob/job1.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("job-line\n"))
+	})
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	t.Run("archive of a just completed run is retried", func(t *testing.T) {
+		archiveRequests, archiveReadyAfter = 0, 2
+		logs, err := client.GetWorkflowLogs(context.Background(), 100, 0, 0, 0, false, nil)
+		require.NoError(t, err)
+		assert.Contains(t, logs, "archive-line")
+		assert.Equal(t, 3, archiveRequests)
+	})
+
+	t.Run("job logs are used while the run is in progress", func(t *testing.T) {
+		archiveRequests, archiveReadyAfter, runStatus = 0, -1, "in_progress"
+		logs, err := client.GetWorkflowLogs(context.Background(), 100, 0, 0, 0, false, nil)
+		require.NoError(t, err)
+		assert.Contains(t, logs, "=== Build/job.txt ===")
+		assert.Contains(t, logs, "job-line")
+		assert.Equal(t, 1, archiveRequests, "only recently completed runs are retried")
+
+		files, err := client.GetWorkflowLogFiles(context.Background(), 100)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, "Build/job.txt", files[0].Path)
+	})
+}
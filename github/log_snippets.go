@@ -0,0 +1,148 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// logToolSignature recognises the output of one tool in a failure snippet.
+type logToolSignature struct {
+	tool     string
+	language string // Code fence language hint
+	patterns []*regexp.Regexp
+}
+
+// logToolSignatures lists the tools DetectLogTool recognises, in tie-break order.
+var logToolSignatures = []logToolSignature{
+	{tool: "go", language: "go", patterns: []*regexp.Regexp{
+		regexp.MustCompile(`^\s*--- FAIL: `),
+		regexp.MustCompile(`^FAIL\s+\S+`),
+		regexp.MustCompile(`^panic: `),
+		regexp.MustCompile(`^go: `),
+		regexp.MustCompile(`\.go:\d+(:\d+)?: `),
+	}},
+	{tool: "gcc", language: "c", patterns: []*regexp.Regexp{
+		regexp.MustCompile(`\.(c|cc|cpp|cxx|h|hh|hpp):\d+:\d+: (fatal )?(error|warning|note):`),
+		regexp.MustCompile(`^(gcc|g\+\+|cc|c\+\+|clang|clang\+\+|cc1\w*|collect2|ld): `),
+		regexp.MustCompile(`undefined reference to `),
+	}},
+	{tool: "pytest", language: "python", patterns: []*regexp.Regexp{
+		regexp.MustCompile(`^=+ (FAILURES|ERRORS|short test summary info) =+$`),
+		regexp.MustCompile(`^(FAILED|ERROR) \S+\.py(::\S+)?`),
+		regexp.MustCompile(`^E\s{2,}\S`),
+		regexp.MustCompile(`\.py:\d+: `),
+		regexp.MustCompile(`^Traceback \(most recent call last\):`),
+	}},
+	{tool: "webpack", language: "javascript", patterns: []*regexp.Regexp{
+		regexp.MustCompile(`^ERROR in `),
+		regexp.MustCompile(`Module not found: Error: `),
+		regexp.MustCompile(`^webpack \d+\.\d+`),
+		regexp.MustCompile(`compiled with \d+ errors?`),
+		regexp.MustCompile(`^\s*@ \./`),
+	}},
+}
+
+// cppSource matches C++ source and header names, which switch the gcc hint to cpp.
+var cppSource = regexp.MustCompile(`\.(cc|cpp|cxx|hh|hpp):\d+`)
+
+// DetectLogTool returns the tool whose output the lines look most like (go, gcc, pytest,
+// or webpack) and the matching code fence language hint, or empty strings when no tool's
+// output is recognised. GitHub Actions timestamps and ##[error] prefixes are ignored.
+func DetectLogTool(lines []string) (tool, language string) {
+	best, bestScore := -1, 0
+	scores := make([]int, len(logToolSignatures))
+	cpp := false
+	for _, raw := range lines {
+		line := strings.TrimPrefix(logTimestamp.ReplaceAllString(raw, ""), "##[error]")
+		for i, sig := range logToolSignatures {
+			for _, p := range sig.patterns {
+				if p.MatchString(line) {
+					scores[i]++
+					break
+				}
+			}
+		}
+		cpp = cpp || cppSource.MatchString(line)
+	}
+	for i, score := range scores {
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return "", ""
+	}
+	sig := logToolSignatures[best]
+	if sig.tool == "gcc" && cpp {
+		return sig.tool, "cpp"
+	}
+	return sig.tool, sig.language
+}
+
+// RenderLogSnippet renders log lines as a fenced markdown code block, with the language
+// hint of the tool that produced them when it is recognised.
+func RenderLogSnippet(lines []string) string {
+	content := strings.Join(lines, "\n")
+	_, language := DetectLogTool(lines)
+	fence := markdownFence(content)
+	return fmt.Sprintf("%s%s\n%s\n%s\n", fence, language, content, fence)
+}
+
+// RenderDiagnosisMarkdown renders a failure diagnosis as markdown, with each job's error
+// lines in a code block fenced for the tool that produced them.
+func RenderDiagnosisMarkdown(d *FailureDiagnosis) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s (run %d): %s\n\n", d.RunName, d.RunID, d.Conclusion)
+	fmt.Fprintf(&sb, "- **Branch:** %s\n", d.Branch)
+	fmt.Fprintf(&sb, "- **Commit:** %s\n", d.HeadSHA)
+	fmt.Fprintf(&sb, "- **URL:** %s\n", d.RunURL)
+	if d.Summary != "" {
+		fmt.Fprintf(&sb, "\n%s\n", d.Summary)
+	}
+
+	for _, job := range d.FailedJobs {
+		fmt.Fprintf(&sb, "\n## %s (%s)\n\n", job.JobName, job.Conclusion)
+		if len(job.FailedSteps) > 0 {
+			steps := make([]string, 0, len(job.FailedSteps))
+			for _, step := range job.FailedSteps {
+				steps = append(steps, fmt.Sprintf("%d. %s (%s)", step.Number, step.Name, step.Conclusion))
+			}
+			fmt.Fprintf(&sb, "Failed steps: %s\n\n", strings.Join(steps, ", "))
+		}
+		if len(job.ContainerErrors) > 0 {
+			sb.WriteString("Container errors:\n\n")
+			sb.WriteString(RenderLogSnippet(job.ContainerErrors))
+			sb.WriteString("\n")
+		}
+		if len(job.ErrorLines) > 0 {
+			if job.Tool != "" {
+				fmt.Fprintf(&sb, "Error lines (%s):\n\n", job.Tool)
+			} else {
+				sb.WriteString("Error lines:\n\n")
+			}
+			sb.WriteString(RenderLogSnippet(job.ErrorLines))
+		}
+	}
+
+	if len(d.Skipped) > 0 {
+		sb.WriteString("\n## Skipped\n\n")
+		for _, s := range d.Skipped {
+			name := "Job " + s.Job
+			if s.Step != "" {
+				name = fmt.Sprintf("Step %q of job %s", s.Step, s.Job)
+			}
+			if s.Condition != "" {
+				fmt.Fprintf(&sb, "- %s (`if: %s`): %s\n", name, s.Condition, s.Reason)
+			} else {
+				fmt.Fprintf(&sb, "- %s: %s\n", name, s.Reason)
+			}
+		}
+	}
+
+	if f := d.Flakiness; f != nil {
+		fmt.Fprintf(&sb, "\n## Flakiness: %s\n\n", f.Verdict)
+		fmt.Fprintf(&sb, "%d of the last %d runs failed, %d succeeded.\n", f.RecentFailures, f.RecentRuns, f.RecentSuccesses)
+	}
+	return sb.String()
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLogTool(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		tool     string
+		language string
+	}{
+		{
+			name: "go test",
+			lines: []string{
+				"2026-01-01T10:00:00.1234567Z --- FAIL: TestParse (0.00s)",
+				"    parse_test.go:42: got 1, want 2",
+				"FAIL\tgithub.com/acme/app/parse\t0.012s",
+			},
+			tool: "go", language: "go",
+		},
+		{
+			name: "gcc",
+			lines: []string{
+				"src/main.c:10:5: error: 'x' undeclared (first use in this function)",
+				"make: *** [Makefile:4: main.o] Error 1",
+			},
+			tool: "gcc", language: "c",
+		},
+		{
+			name: "g++",
+			lines: []string{
+				"##[error]src/app.cpp:7:1: error: expected ';' before '}' token",
+				"collect2: error: ld returned 1 exit status",
+			},
+			tool: "gcc", language: "cpp",
+		},
+		{
+			name: "pytest",
+			lines: []string{
+				"E       AssertionError: assert 1 == 2",
+				"tests/test_app.py:12: AssertionError",
+				"FAILED tests/test_app.py::test_add - AssertionError: assert 1 == 2",
+			},
+			tool: "pytest", language: "python",
+		},
+		{
+			name: "webpack",
+			lines: []string{
+				"ERROR in ./src/index.js 3:0-28",
+				"Module not found: Error: Can't resolve './missing' in '/app/src'",
+				"webpack 5.90.0 compiled with 1 error in 812 ms",
+			},
+			tool: "webpack", language: "javascript",
+		},
+		{
+			name:  "unrecognised",
+			lines: []string{"Error: Process completed with exit code 1."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, language := DetectLogTool(tt.lines)
+			assert.Equal(t, tt.tool, tool)
+			assert.Equal(t, tt.language, language)
+		})
+	}
+}
+
+func TestRenderDiagnosisMarkdown(t *testing.T) {
+	md := RenderDiagnosisMarkdown(&FailureDiagnosis{
+		RunID:      7,
+		RunName:    "CI",
+		Conclusion: "failure",
+		FailedJobs: []*FailedJob{{
+			JobName:     "test",
+			Conclusion:  "failure",
+			FailedSteps: []*FailedStep{{Name: "Run tests", Number: 4, Conclusion: "failure"}},
+			ErrorLines:  []string{"--- FAIL: TestParse (0.00s)", "FAIL\tgithub.com/acme/app\t0.01s"},
+			Tool:        "go",
+		}, {
+			JobName:    "lint",
+			Conclusion: "failure",
+			ErrorLines: []string{"Error: Process completed with exit code 1."},
+		}},
+	})
+	assert.Contains(t, md, "## test (failure)\n\nFailed steps: 4. Run tests (failure)\n\nError lines (go):\n\n```go\n--- FAIL: TestParse (0.00s)\n")
+	assert.Contains(t, md, "Error lines:\n\n```\nError: Process completed with exit code 1.\n```\n")
+}
@@ -0,0 +1,166 @@
+package github
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// apiMetrics counts the API requests made by every client, and lookups in the in-process
+// caches, for the server's metrics snapshot.
+var apiMetrics = &metricsRecorder{
+	byStatus:   make(map[string]int64),
+	rateLimits: make(map[string]*RateLimitStatus),
+	caches:     make(map[string]*CacheStats),
+}
+
+// APIMetrics is a snapshot of the API requests made since the process started.
+type APIMetrics struct {
+	Calls            int64              `json:"calls"`
+	Errors           int64              `json:"errors"`    // Transport failures and 4xx/5xx responses
+	ByStatus         map[string]int64   `json:"by_status"` // Responses per status class, e.g. 2xx
+	AverageLatencyMs float64            `json:"average_latency_ms"`
+	MaxLatencyMs     float64            `json:"max_latency_ms"`
+	RateLimits       []*RateLimitStatus `json:"rate_limits"` // Latest per resource, as reported by GitHub
+	Caches           []*CacheStats      `json:"caches"`
+}
+
+// RateLimitStatus is the rate limit GitHub reported for a resource in its latest response.
+type RateLimitStatus struct {
+	Resource  string `json:"resource"` // core, search, graphql, ...
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Used      int    `json:"used"`
+	ResetAt   string `json:"reset_at,omitempty"`
+}
+
+// CacheStats counts the hits and misses of an in-process cache.
+type CacheStats struct {
+	Name    string  `json:"name"`
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+type metricsRecorder struct {
+	mu         sync.Mutex
+	calls      int64
+	errors     int64
+	byStatus   map[string]int64
+	latency    time.Duration
+	maxLatency time.Duration
+	rateLimits map[string]*RateLimitStatus
+	caches     map[string]*CacheStats
+}
+
+// metricsTransport records every request sent through it in apiMetrics.
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	apiMetrics.recordRequest(resp, err, time.Since(start))
+	return resp, err
+}
+
+// recordRequest counts one API response, or transport failure, and keeps the rate limit
+// headers it carries.
+func (m *metricsRecorder) recordRequest(resp *http.Response, err error, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	m.latency += elapsed
+	if elapsed > m.maxLatency {
+		m.maxLatency = elapsed
+	}
+	if err != nil || resp == nil {
+		m.errors++
+		m.byStatus["error"]++
+		return
+	}
+	if resp.StatusCode >= 400 {
+		m.errors++
+	}
+	m.byStatus[strconv.Itoa(resp.StatusCode/100)+"xx"]++
+
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	status := &RateLimitStatus{Resource: resp.Header.Get("X-RateLimit-Resource"), Limit: limit}
+	if status.Resource == "" {
+		status.Resource = "core"
+	}
+	status.Remaining, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	status.Used, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Used"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		status.ResetAt = time.Unix(reset, 0).UTC().Format(time.RFC3339)
+	}
+	m.rateLimits[status.Resource] = status
+}
+
+// recordCacheLookup counts a hit or miss of the named in-process cache.
+func (m *metricsRecorder) recordCacheLookup(name string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := m.caches[name]
+	if stats == nil {
+		stats = &CacheStats{Name: name}
+		m.caches[name] = stats
+	}
+	if hit {
+		stats.Hits++
+	} else {
+		stats.Misses++
+	}
+}
+
+// APIMetricsSnapshot returns the API and cache counters accumulated so far.
+func APIMetricsSnapshot() *APIMetrics {
+	m := apiMetrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := &APIMetrics{
+		Calls:        m.calls,
+		Errors:       m.errors,
+		ByStatus:     make(map[string]int64, len(m.byStatus)),
+		MaxLatencyMs: durationMs(m.maxLatency),
+		RateLimits:   []*RateLimitStatus{},
+		Caches:       []*CacheStats{},
+	}
+	if m.calls > 0 {
+		snapshot.AverageLatencyMs = durationMs(m.latency / time.Duration(m.calls))
+	}
+	for class, n := range m.byStatus {
+		snapshot.ByStatus[class] = n
+	}
+	for _, status := range m.rateLimits {
+		copied := *status
+		snapshot.RateLimits = append(snapshot.RateLimits, &copied)
+	}
+	sort.Slice(snapshot.RateLimits, func(i, j int) bool {
+		return snapshot.RateLimits[i].Resource < snapshot.RateLimits[j].Resource
+	})
+	for _, stats := range m.caches {
+		copied := *stats
+		if total := copied.Hits + copied.Misses; total > 0 {
+			copied.HitRate = math.Round(float64(copied.Hits)/float64(total)*1000) / 1000
+		}
+		snapshot.Caches = append(snapshot.Caches, &copied)
+	}
+	sort.Slice(snapshot.Caches, func(i, j int) bool {
+		return snapshot.Caches[i].Name < snapshot.Caches[j].Name
+	})
+	return snapshot
+}
+
+// durationMs converts a duration to milliseconds, rounded to a tenth.
+func durationMs(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*10) / 10
+}
@@ -0,0 +1,104 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// FailureCursor remembers where the previous failure check stopped.
+type FailureCursor struct {
+	LastRunID int64     `json:"last_run_id"`
+	Pending   []int64   `json:"pending,omitempty"` // Runs at or below LastRunID that had not completed yet
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// NewFailuresReport lists failed runs that were not reported by the previous check.
+type NewFailuresReport struct {
+	FirstCheck   bool           `json:"first_check"`
+	SinceRunID   int64          `json:"since_run_id"`
+	LastChecked  string         `json:"last_checked,omitempty"`
+	CheckedRuns  int            `json:"checked_runs"`
+	StillRunning int            `json:"still_running"`
+	NewFailures  []*WorkflowRun `json:"new_failures"`
+	Truncated    bool           `json:"truncated,omitempty"`
+	Cursor       *FailureCursor `json:"cursor"`
+}
+
+// GetNewFailures returns runs that failed since the given cursor and the cursor for the next check.
+//
+// A run counts as new when its ID is above the cursor's LastRunID, or when it was still running
+// at the previous check (so a run that started before the check but failed after it is reported
+// once). A nil cursor reports every failure in the listed window.
+func (c *Client) GetNewFailures(ctx context.Context, cursor *FailureCursor, opts *ListRunsOptions) (*NewFailuresReport, error) {
+	if opts == nil {
+		opts = &ListRunsOptions{}
+	}
+	listOpts := *opts
+	listOpts.Status = ""
+	listOpts.Conclusion = ""
+	if listOpts.Per_page <= 0 {
+		listOpts.Per_page = 100
+	}
+
+	runs, err := c.ListRepositoryWorkflowRunsWithOptions(ctx, &listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildNewFailuresReport(cursor, runs, listOpts.Per_page, time.Now().UTC()), nil
+}
+
+func buildNewFailuresReport(cursor *FailureCursor, runs []*WorkflowRun, pageSize int, now time.Time) *NewFailuresReport {
+	report := &NewFailuresReport{
+		FirstCheck:  cursor == nil,
+		CheckedRuns: len(runs),
+		NewFailures: []*WorkflowRun{},
+	}
+
+	pending := make(map[int64]bool)
+	next := &FailureCursor{CheckedAt: now}
+	if cursor != nil {
+		report.SinceRunID = cursor.LastRunID
+		if !cursor.CheckedAt.IsZero() {
+			report.LastChecked = cursor.CheckedAt.Format(time.RFC3339)
+		}
+		next.LastRunID = cursor.LastRunID
+		for _, id := range cursor.Pending {
+			pending[id] = true
+		}
+	}
+
+	oldestListed := int64(0)
+	for _, run := range runs {
+		if oldestListed == 0 || run.ID < oldestListed {
+			oldestListed = run.ID
+		}
+		if run.ID > next.LastRunID {
+			next.LastRunID = run.ID
+		}
+
+		unseen := cursor == nil || run.ID > cursor.LastRunID || pending[run.ID]
+		if !unseen {
+			continue
+		}
+		if run.Status != "completed" {
+			report.StillRunning++
+			next.Pending = append(next.Pending, run.ID)
+			continue
+		}
+		if isFailureConclusion(run.Conclusion) {
+			report.NewFailures = append(report.NewFailures, run)
+		}
+	}
+
+	// A full page whose oldest run is still unseen means older new runs may have been missed.
+	if cursor != nil && len(runs) >= pageSize && oldestListed > cursor.LastRunID {
+		report.Truncated = true
+	}
+
+	sort.Slice(report.NewFailures, func(i, j int) bool { return report.NewFailures[i].ID > report.NewFailures[j].ID })
+	sort.Slice(next.Pending, func(i, j int) bool { return next.Pending[i] < next.Pending[j] })
+	report.Cursor = next
+	return report
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildNewFailuresReport(t *testing.T) {
+	now := time.Date(2026, 4, 20, 10, 0, 0, 0, time.UTC)
+
+	firstRuns := []*WorkflowRun{
+		{ID: 103, Status: "in_progress"},
+		{ID: 102, Status: "completed", Conclusion: "failure"},
+		{ID: 101, Status: "completed", Conclusion: "success"},
+		{ID: 100, Status: "completed", Conclusion: "timed_out"},
+	}
+
+	first := buildNewFailuresReport(nil, firstRuns, 100, now)
+	assert.True(t, first.FirstCheck)
+	require.Len(t, first.NewFailures, 2)
+	assert.Equal(t, int64(102), first.NewFailures[0].ID)
+	assert.Equal(t, int64(100), first.NewFailures[1].ID)
+	assert.Equal(t, 1, first.StillRunning)
+	assert.Equal(t, int64(103), first.Cursor.LastRunID)
+	assert.Equal(t, []int64{103}, first.Cursor.Pending)
+
+	secondRuns := append([]*WorkflowRun{
+		{ID: 105, Status: "completed", Conclusion: "success"},
+		{ID: 104, Status: "completed", Conclusion: "startup_failure"},
+		{ID: 103, Status: "completed", Conclusion: "failure"},
+	}, firstRuns[1:]...)
+
+	second := buildNewFailuresReport(first.Cursor, secondRuns, 100, now.Add(time.Minute))
+	assert.False(t, second.FirstCheck)
+	assert.Equal(t, int64(103), second.SinceRunID)
+	assert.Equal(t, now.Format(time.RFC3339), second.LastChecked)
+	require.Len(t, second.NewFailures, 2, "the pending run that failed and the new failure are reported; old failures are not")
+	assert.Equal(t, int64(104), second.NewFailures[0].ID)
+	assert.Equal(t, int64(103), second.NewFailures[1].ID)
+	assert.Empty(t, second.Cursor.Pending)
+	assert.Equal(t, int64(105), second.Cursor.LastRunID)
+
+	third := buildNewFailuresReport(second.Cursor, secondRuns, 100, now.Add(2*time.Minute))
+	assert.Empty(t, third.NewFailures)
+	assert.False(t, third.Truncated)
+
+	truncated := buildNewFailuresReport(&FailureCursor{LastRunID: 50}, secondRuns, len(secondRuns), now)
+	assert.True(t, truncated.Truncated)
+}
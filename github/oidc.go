@@ -0,0 +1,196 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// defaultOIDCClaimKeys are the claims GitHub uses for the `sub` claim when no
+// customization template is configured.
+var defaultOIDCClaimKeys = []string{"repo", "context"}
+
+// OIDCSubjectTemplate is a subject claim customization template at a given scope.
+type OIDCSubjectTemplate struct {
+	Scope            string   `json:"scope"`
+	UseDefault       bool     `json:"use_default"`
+	IncludeClaimKeys []string `json:"include_claim_keys,omitempty"`
+}
+
+// OIDCSubjectExample is the `sub` claim a recent run would have presented.
+type OIDCSubjectExample struct {
+	RunID    int64  `json:"run_id"`
+	Workflow string `json:"workflow"`
+	Event    string `json:"event"`
+	Branch   string `json:"branch,omitempty"`
+	Subject  string `json:"subject"`
+}
+
+// OIDCConfiguration reports the OIDC subject claim setup for a repository.
+type OIDCConfiguration struct {
+	Repository      *OIDCSubjectTemplate  `json:"repository"`
+	Organization    *OIDCSubjectTemplate  `json:"organization,omitempty"`
+	Effective       *OIDCSubjectTemplate  `json:"effective"`
+	ExampleSubjects []*OIDCSubjectExample `json:"example_subjects,omitempty"`
+	Notes           []string              `json:"notes,omitempty"`
+}
+
+// GetOIDCConfiguration reads the repository and organization OIDC subject claim
+// templates and renders example `sub` claims for the most recent runs.
+func (c *Client) GetOIDCConfiguration(ctx context.Context, limit int) (*OIDCConfiguration, error) {
+	repoTemplate, _, err := c.gh.Actions.GetRepoOIDCSubjectClaimCustomTemplate(ctx, c.owner, c.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OIDC subject claim template: %w", err)
+	}
+
+	cfg := &OIDCConfiguration{
+		Repository: &OIDCSubjectTemplate{
+			Scope:            "repository",
+			UseDefault:       repoTemplate.GetUseDefault(),
+			IncludeClaimKeys: repoTemplate.IncludeClaimKeys,
+		},
+	}
+
+	orgTemplate, _, err := c.gh.Actions.GetOrgOIDCSubjectClaimCustomTemplate(ctx, c.owner)
+	if err != nil {
+		log.Debugf("Could not read organization OIDC template for %s: %v", c.owner, err)
+		cfg.Notes = append(cfg.Notes, fmt.Sprintf("Organization template for %s is unavailable (not an organization, or the token lacks org admin read access).", c.owner))
+	} else {
+		cfg.Organization = &OIDCSubjectTemplate{
+			Scope:            "organization",
+			UseDefault:       len(orgTemplate.IncludeClaimKeys) == 0,
+			IncludeClaimKeys: orgTemplate.IncludeClaimKeys,
+		}
+	}
+
+	cfg.Effective = effectiveOIDCTemplate(cfg.Repository, cfg.Organization)
+	cfg.Notes = append(cfg.Notes, "Jobs that reference an environment use environment:<name> instead of the ref in the context claim.")
+
+	if limit <= 0 {
+		limit = 5
+	}
+	runs, _, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, &github.ListWorkflowRunsOptions{
+		ListOptions: github.ListOptions{PerPage: limit},
+	})
+	if err != nil {
+		log.Debugf("Could not list runs for OIDC examples: %v", err)
+		cfg.Notes = append(cfg.Notes, "Example subjects unavailable: failed to list recent runs.")
+		return cfg, nil
+	}
+
+	tags := c.tagCommits(ctx)
+	for _, run := range runs.WorkflowRuns {
+		cfg.ExampleSubjects = append(cfg.ExampleSubjects, &OIDCSubjectExample{
+			RunID:    run.GetID(),
+			Workflow: run.GetName(),
+			Event:    run.GetEvent(),
+			Branch:   run.GetHeadBranch(),
+			Subject:  oidcSubjectForRun(c.owner, c.repo, run, oidcRunOnTag(run, tags), cfg.Effective.IncludeClaimKeys),
+		})
+	}
+
+	return cfg, nil
+}
+
+// effectiveOIDCTemplate resolves which template GitHub applies: the repository
+// template unless it opts into the default, then the organization template,
+// then GitHub's built-in default.
+func effectiveOIDCTemplate(repoTemplate, orgTemplate *OIDCSubjectTemplate) *OIDCSubjectTemplate {
+	if repoTemplate != nil && !repoTemplate.UseDefault && len(repoTemplate.IncludeClaimKeys) > 0 {
+		return repoTemplate
+	}
+	if orgTemplate != nil && len(orgTemplate.IncludeClaimKeys) > 0 {
+		return orgTemplate
+	}
+	return &OIDCSubjectTemplate{
+		Scope:            "default",
+		UseDefault:       true,
+		IncludeClaimKeys: defaultOIDCClaimKeys,
+	}
+}
+
+// tagCommits returns the commit SHA of each of the repository's most recent tags, or nil
+// when they cannot be listed.
+func (c *Client) tagCommits(ctx context.Context) map[string]string {
+	tags, _, err := c.gh.Repositories.ListTags(ctx, c.owner, c.repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		log.Debugf("Could not list tags for OIDC examples: %v", err)
+		return nil
+	}
+	commits := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		commits[tag.GetName()] = tag.GetCommit().GetSHA()
+	}
+	return commits
+}
+
+// oidcRunOnTag reports whether a run ran on a tag rather than a branch. The run only
+// names the ref, so a run counts as a tag run when it is a release run, or when a tag of
+// that name points at the run's commit.
+func oidcRunOnTag(run *github.WorkflowRun, tagCommits map[string]string) bool {
+	if run.GetEvent() == "release" {
+		return true
+	}
+	sha, ok := tagCommits[run.GetHeadBranch()]
+	return ok && sha != "" && sha == run.GetHeadSHA()
+}
+
+// oidcSubjectForRun renders the `sub` claim a run would present for the given claim keys.
+// onTag says the run's head branch is a tag.
+func oidcSubjectForRun(owner, repo string, run *github.WorkflowRun, onTag bool, claimKeys []string) string {
+	ref := "refs/heads/" + run.GetHeadBranch()
+	if onTag {
+		ref = "refs/tags/" + run.GetHeadBranch()
+	}
+	if run.GetEvent() == "pull_request" && len(run.PullRequests) > 0 {
+		ref = fmt.Sprintf("refs/pull/%d/merge", run.PullRequests[0].GetNumber())
+	}
+
+	parts := make([]string, 0, len(claimKeys))
+	for _, key := range claimKeys {
+		var value string
+		switch key {
+		case "repo", "repository":
+			value = owner + "/" + repo
+		case "context":
+			if run.GetEvent() == "pull_request" {
+				parts = append(parts, "pull_request")
+			} else {
+				parts = append(parts, "ref:"+ref)
+			}
+			continue
+		case "ref":
+			value = ref
+		case "repository_owner":
+			value = owner
+		case "repository_id":
+			value = strconv.FormatInt(run.GetRepository().GetID(), 10)
+		case "repository_owner_id":
+			value = strconv.FormatInt(run.GetRepository().GetOwner().GetID(), 10)
+		case "event_name":
+			value = run.GetEvent()
+		case "actor":
+			value = run.GetActor().GetLogin()
+		case "actor_id":
+			value = strconv.FormatInt(run.GetActor().GetID(), 10)
+		case "workflow":
+			value = run.GetName()
+		case "head_ref":
+			if strings.HasPrefix(run.GetEvent(), "pull_request") {
+				value = run.GetHeadBranch()
+			}
+		case "job_workflow_ref", "workflow_ref":
+			value = fmt.Sprintf("%s/%s/%s@%s", owner, repo, run.GetPath(), ref)
+		case "repository_visibility":
+			value = run.GetRepository().GetVisibility()
+		default:
+			value = "<" + key + ">"
+		}
+		parts = append(parts, key+":"+value)
+	}
+
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOIDCConfiguration(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/oidc/customization/sub", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"use_default": true}`))
+	})
+	mux.HandleFunc("/orgs/"+owner+"/actions/oidc/customization/sub", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"include_claim_keys": ["repo", "context", "job_workflow_ref"]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_count": 2,
+			"workflow_runs": [
+				{"id": 1, "name": "Deploy", "event": "push", "head_branch": "main", "path": ".github/workflows/deploy.yml"},
+				{"id": 2, "name": "CI", "event": "pull_request", "head_branch": "feature", "path": ".github/workflows/ci.yml",
+				 "pull_requests": [{"number": 7}]},
+				{"id": 3, "name": "Deploy", "event": "push", "head_branch": "v1.2.3", "head_sha": "abc123", "path": ".github/workflows/deploy.yml"},
+				{"id": 4, "name": "Publish", "event": "release", "head_branch": "v1.2.0", "path": ".github/workflows/publish.yml"}
+			]
+		}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name": "v1.2.3", "commit": {"sha": "abc123"}}, {"name": "main", "commit": {"sha": "other"}}]`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	cfg, err := client.GetOIDCConfiguration(context.Background(), 5)
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Repository.UseDefault)
+	require.NotNil(t, cfg.Organization)
+	assert.Equal(t, "organization", cfg.Effective.Scope)
+
+	require.Len(t, cfg.ExampleSubjects, 4)
+	// A tag named like the branch does not make a run at another commit a tag run.
+	assert.Equal(t, "repo:test-owner/test-repo:ref:refs/heads/main:job_workflow_ref:test-owner/test-repo/.github/workflows/deploy.yml@refs/heads/main", cfg.ExampleSubjects[0].Subject)
+	assert.Equal(t, "repo:test-owner/test-repo:pull_request:job_workflow_ref:test-owner/test-repo/.github/workflows/ci.yml@refs/pull/7/merge", cfg.ExampleSubjects[1].Subject)
+	assert.Equal(t, "repo:test-owner/test-repo:ref:refs/tags/v1.2.3:job_workflow_ref:test-owner/test-repo/.github/workflows/deploy.yml@refs/tags/v1.2.3", cfg.ExampleSubjects[2].Subject)
+	assert.Equal(t, "repo:test-owner/test-repo:ref:refs/tags/v1.2.0:job_workflow_ref:test-owner/test-repo/.github/workflows/publish.yml@refs/tags/v1.2.0", cfg.ExampleSubjects[3].Subject)
+}
+
+func TestEffectiveOIDCTemplate(t *testing.T) {
+	repoCustom := &OIDCSubjectTemplate{Scope: "repository", IncludeClaimKeys: []string{"repo", "ref"}}
+	repoDefault := &OIDCSubjectTemplate{Scope: "repository", UseDefault: true}
+	orgCustom := &OIDCSubjectTemplate{Scope: "organization", IncludeClaimKeys: []string{"repository_owner"}}
+
+	assert.Equal(t, "repository", effectiveOIDCTemplate(repoCustom, orgCustom).Scope)
+	assert.Equal(t, "organization", effectiveOIDCTemplate(repoDefault, orgCustom).Scope)
+
+	def := effectiveOIDCTemplate(repoDefault, nil)
+	assert.Equal(t, "default", def.Scope)
+	assert.Equal(t, []string{"repo", "context"}, def.IncludeClaimKeys)
+}
@@ -0,0 +1,140 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// FileChange is a file to write or delete on a patch branch.
+type FileChange struct {
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+	Delete  bool   `json:"delete,omitempty"`
+}
+
+// PatchBranchResult describes a branch created from a base ref with file changes committed.
+type PatchBranchResult struct {
+	Branch    string   `json:"branch"`
+	Base      string   `json:"base"`
+	BaseSHA   string   `json:"base_sha"`
+	HeadSHA   string   `json:"head_sha"`
+	Commits   []string `json:"commits"` // One per file change, oldest first
+	CommitURL string   `json:"commit_url,omitempty"`
+}
+
+// CreatePatchBranch creates branch at base (default: the repository's default branch) and
+// commits each change to it through the contents API, one commit per file. The branch must
+// not exist yet and must be allowed by allowed_trigger_refs. If a change fails, the branch
+// is left in place with the commits made so far, and the error says so.
+func (c *Client) CreatePatchBranch(ctx context.Context, branch, base, message string, changes []*FileChange) (*PatchBranchResult, error) {
+	if branch == "" {
+		return nil, fmt.Errorf("branch is required")
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("at least one file change is required")
+	}
+	for _, change := range changes {
+		if change.Path == "" {
+			return nil, fmt.Errorf("every file change needs a path")
+		}
+	}
+	if err := c.checkRefAllowed(branch); err != nil {
+		return nil, fmt.Errorf("cannot create branch %s: %w", branch, err)
+	}
+	if message == "" {
+		message = "Apply patch for CI run"
+	}
+
+	base, baseSHA, err := c.createBranch(ctx, branch, base)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PatchBranchResult{Branch: branch, Base: base, BaseSHA: baseSHA, HeadSHA: baseSHA, Commits: []string{}}
+	for _, change := range changes {
+		commit, err := c.commitFileChange(ctx, branch, message, change)
+		if err != nil {
+			return nil, fmt.Errorf("branch %s was created but committing %s failed after %d commit(s): %w", branch, change.Path, len(result.Commits), err)
+		}
+		result.HeadSHA = commit.GetSHA()
+		result.CommitURL = commit.GetHTMLURL()
+		result.Commits = append(result.Commits, result.HeadSHA)
+	}
+	return result, nil
+}
+
+// createBranch creates branch at base, the repository's default branch when empty, and
+// returns the base and the commit SHA the branch points to.
+func (c *Client) createBranch(ctx context.Context, branch, base string) (string, string, error) {
+	if base == "" {
+		repository, _, err := c.gh.Repositories.Get(ctx, c.owner, c.repo)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to determine default branch: %w", Classify(err))
+		}
+		base = repository.GetDefaultBranch()
+	}
+	baseSHA, _, err := c.gh.Repositories.GetCommitSHA1(ctx, c.owner, c.repo, base, "")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve %s: %w", base, Classify(err))
+	}
+	_, _, err = c.gh.Git.CreateRef(ctx, c.owner, c.repo, &github.Reference{
+		Ref:    github.Ptr("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: github.Ptr(baseSHA)},
+	})
+	if err != nil {
+		if IsHTTPError(err, http.StatusUnprocessableEntity) {
+			return "", "", fmt.Errorf("failed to create branch %s: it already exists; pick a new branch name: %w", branch, Classify(err))
+		}
+		return "", "", fmt.Errorf("failed to create branch %s: %w", branch, Classify(err))
+	}
+	return base, baseSHA, nil
+}
+
+// commitFileChange writes or deletes one file on branch and returns the commit it made.
+func (c *Client) commitFileChange(ctx context.Context, branch, message string, change *FileChange) (*github.Commit, error) {
+	var existingSHA *string
+	file, _, _, err := c.gh.Repositories.GetContents(ctx, c.owner, c.repo, change.Path, &github.RepositoryContentGetOptions{Ref: branch})
+	switch {
+	case err == nil && file == nil:
+		return nil, fmt.Errorf("%s is a directory", change.Path)
+	case err == nil:
+		existingSHA = file.SHA
+	case !errors.Is(Classify(err), ErrNotFound):
+		return nil, fmt.Errorf("failed to read %s: %w", change.Path, Classify(err))
+	}
+
+	opts := &github.RepositoryContentFileOptions{
+		Message: github.Ptr(message),
+		Branch:  github.Ptr(branch),
+		SHA:     existingSHA,
+	}
+	var resp *github.RepositoryContentResponse
+	switch {
+	case change.Delete && existingSHA == nil:
+		return nil, fmt.Errorf("cannot delete %s: it does not exist", change.Path)
+	case change.Delete:
+		resp, _, err = c.gh.Repositories.DeleteFile(ctx, c.owner, c.repo, change.Path, opts)
+	case existingSHA == nil:
+		opts.Content = []byte(change.Content)
+		resp, _, err = c.gh.Repositories.CreateFile(ctx, c.owner, c.repo, change.Path, opts)
+	default:
+		opts.Content = []byte(change.Content)
+		resp, _, err = c.gh.Repositories.UpdateFile(ctx, c.owner, c.repo, change.Path, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit %s: %w", change.Path, Classify(err))
+	}
+	return &resp.Commit, nil
+}
+
+// DeleteBranch deletes a branch, such as a patch branch once its run has finished.
+func (c *Client) DeleteBranch(ctx context.Context, branch string) error {
+	if _, err := c.gh.Git.DeleteRef(ctx, c.owner, c.repo, "refs/heads/"+branch); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", branch, Classify(err))
+	}
+	return nil
+}
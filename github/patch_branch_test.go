@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePatchBranch(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	var calls []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"default_branch": "main"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("basesha"))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]string{"ref": "refs/heads/fix/flaky", "sha": "basesha"}, body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ref": "refs/heads/fix/flaky"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/contents/", func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[len("/repos/"+owner+"/"+repo+"/contents/"):]
+		calls = append(calls, r.Method+" "+path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "fix/flaky", r.URL.Query().Get("ref"))
+			if path == "new.txt" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"type": "file", "path": "` + path + `", "sha": "old-` + path + `"}`))
+		case http.MethodPut, http.MethodDelete:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "fix/flaky", body["branch"])
+			assert.Equal(t, "Try a fix", body["message"])
+			if path == "new.txt" {
+				assert.Nil(t, body["sha"])
+			} else {
+				assert.Equal(t, "old-"+path, body["sha"])
+			}
+			_, _ = w.Write([]byte(`{"commit": {"sha": "commit-` + path + `", "html_url": "https://github.com/c/` + path + `"}}`))
+		}
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	result, err := client.CreatePatchBranch(context.Background(), "fix/flaky", "", "Try a fix", []*FileChange{
+		{Path: "src/app.go", Content: "package app\n"},
+		{Path: "new.txt", Content: "hello"},
+		{Path: "old.txt", Delete: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "main", result.Base)
+	assert.Equal(t, "basesha", result.BaseSHA)
+	assert.Equal(t, []string{"commit-src/app.go", "commit-new.txt", "commit-old.txt"}, result.Commits)
+	assert.Equal(t, "commit-old.txt", result.HeadSHA)
+	assert.Equal(t, []string{
+		"GET src/app.go", "PUT src/app.go",
+		"GET new.txt", "PUT new.txt",
+		"GET old.txt", "DELETE old.txt",
+	}, calls)
+
+	client.allowedRefs = []string{"main"}
+	_, err = client.CreatePatchBranch(context.Background(), "fix/other", "", "", []*FileChange{{Path: "a", Content: "b"}})
+	assert.ErrorIs(t, err, ErrRefNotAllowed)
+}
@@ -0,0 +1,316 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+	"gopkg.in/yaml.v3"
+)
+
+// maxFilterDiffFiles is the number of changed files GitHub considers when evaluating
+// path filters; the compare API returns at most as many.
+const maxFilterDiffFiles = 300
+
+// maxMatchedFilesShown caps the matching files listed per workflow.
+const maxMatchedFilesShown = 10
+
+// Outcomes of evaluating a workflow's filters against a change.
+const (
+	FilterReasonNoTrigger       = "no_trigger"        // The workflow does not trigger on the event
+	FilterReasonBranchFiltered  = "branch_filtered"   // branches/branches-ignore (or tags) exclude the branch
+	FilterReasonNoPathFilter    = "no_path_filter"    // The trigger has no path filter, so any change runs it
+	FilterReasonPathsMatched    = "paths_matched"     // At least one changed file matches paths
+	FilterReasonPathsUnmatched  = "paths_not_matched" // No changed file matches paths
+	FilterReasonPathsNotIgnored = "paths_not_ignored" // At least one changed file is outside paths-ignore
+	FilterReasonAllIgnored      = "all_paths_ignored" // Every changed file matches paths-ignore
+)
+
+// PathFilterOptions selects the change and workflows to evaluate.
+type PathFilterOptions struct {
+	Paths    []string // Changed files; alternatively give Base and Head
+	Base     string   // Base commit or branch of a commit range
+	Head     string   // Head commit or branch of a commit range
+	Event    string   // push (default) or pull_request
+	Branch   string   // Branch pushed to, or the base branch of a pull request; empty skips branch filters
+	Workflow string   // Optional: only evaluate this workflow (name, path, or ID)
+	Ref      string   // Ref to read workflow files from; defaults to Head, then the default branch
+}
+
+// WorkflowFilterResult reports whether a workflow runs for a change, and why.
+type WorkflowFilterResult struct {
+	Workflow     string   `json:"workflow"`
+	Path         string   `json:"path"`
+	WillRun      bool     `json:"will_run"`
+	Reason       string   `json:"reason"`
+	Paths        []string `json:"paths,omitempty"`
+	PathsIgnore  []string `json:"paths_ignore,omitempty"`
+	MatchedFiles []string `json:"matched_files,omitempty"` // Files that make the workflow run
+	Error        string   `json:"error,omitempty"`
+}
+
+// PathFilterReport is the outcome of evaluating each workflow's path filters against a change.
+type PathFilterReport struct {
+	Event        string                  `json:"event"`
+	Branch       string                  `json:"branch,omitempty"`
+	ChangedFiles int                     `json:"changed_files"`
+	WillRun      []string                `json:"will_run"`
+	WontRun      []string                `json:"wont_run"`
+	Workflows    []*WorkflowFilterResult `json:"workflows"`
+	Warnings     []string                `json:"warnings,omitempty"`
+}
+
+// GetPathFilterReport evaluates the on.<event> branch and path filters of the repository's
+// workflows against a set of changed files, given directly or as a commit range.
+func (c *Client) GetPathFilterReport(ctx context.Context, opts PathFilterOptions) (*PathFilterReport, error) {
+	if opts.Event == "" {
+		opts.Event = "push"
+	}
+	if opts.Event != "push" && opts.Event != "pull_request" && opts.Event != "pull_request_target" {
+		return nil, fmt.Errorf("unsupported event %q: path filters apply to push, pull_request, and pull_request_target", opts.Event)
+	}
+
+	report := &PathFilterReport{Event: opts.Event, Branch: opts.Branch, WillRun: []string{}, WontRun: []string{}}
+
+	changed := opts.Paths
+	if len(changed) == 0 {
+		if opts.Base == "" || opts.Head == "" {
+			return nil, fmt.Errorf("either paths or both base and head are required")
+		}
+		cmp, _, err := c.gh.Repositories.CompareCommits(ctx, c.owner, c.repo, opts.Base, opts.Head, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare %s...%s: %w", opts.Base, opts.Head, Classify(err))
+		}
+		for _, f := range cmp.Files {
+			changed = append(changed, f.GetFilename())
+			// A rename changes both paths as far as filters are concerned.
+			if prev := f.GetPreviousFilename(); prev != "" {
+				changed = append(changed, prev)
+			}
+		}
+	}
+	if len(changed) >= maxFilterDiffFiles {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("the change touches %d or more files; GitHub evaluates path filters against the first %d files of the diff only, so results may differ", maxFilterDiffFiles, maxFilterDiffFiles))
+	}
+	report.ChangedFiles = len(changed)
+
+	ref := opts.Ref
+	if ref == "" {
+		ref = opts.Head
+	}
+
+	workflows, _, err := c.gh.Actions.ListWorkflows(ctx, c.owner, c.repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows: %w", Classify(err))
+	}
+	for _, wf := range workflows.Workflows {
+		if opts.Workflow != "" && wf.GetName() != opts.Workflow && wf.GetPath() != opts.Workflow && fmt.Sprint(wf.GetID()) != opts.Workflow {
+			continue
+		}
+		// Dynamic workflows (code scanning, Dependabot, ...) have no file to evaluate.
+		if !strings.HasPrefix(wf.GetPath(), ".github/workflows/") {
+			continue
+		}
+
+		var result *WorkflowFilterResult
+		data, err := c.GetWorkflowFile(ctx, wf.GetPath(), ref)
+		if err == nil {
+			result, err = EvaluatePathFilters(data, opts.Event, opts.Branch, changed)
+		}
+		if err != nil {
+			report.Workflows = append(report.Workflows, &WorkflowFilterResult{Workflow: wf.GetName(), Path: wf.GetPath(), Error: err.Error()})
+			continue
+		}
+
+		result.Workflow, result.Path = wf.GetName(), wf.GetPath()
+		report.Workflows = append(report.Workflows, result)
+		if result.WillRun {
+			report.WillRun = append(report.WillRun, result.Workflow)
+		} else {
+			report.WontRun = append(report.WontRun, result.Workflow)
+		}
+	}
+	if opts.Workflow != "" && len(report.Workflows) == 0 {
+		return nil, fmt.Errorf("workflow %s not found", opts.Workflow)
+	}
+
+	return report, nil
+}
+
+// eventFilters is the filter configuration of one trigger event.
+type eventFilters struct {
+	Branches       []string `yaml:"branches"`
+	BranchesIgnore []string `yaml:"branches-ignore"`
+	Tags           []string `yaml:"tags"`
+	TagsIgnore     []string `yaml:"tags-ignore"`
+	Paths          []string `yaml:"paths"`
+	PathsIgnore    []string `yaml:"paths-ignore"`
+}
+
+// EvaluatePathFilters reports whether a workflow triggers on event for a change to the
+// given files, following GitHub's rules: with paths, at least one file must match (a later
+// "!" pattern excludes files an earlier pattern matched); with paths-ignore, at least one
+// file must not be ignored. An empty branch skips the branch filters. The result's
+// Workflow and Path fields are left empty.
+func EvaluatePathFilters(workflowYAML []byte, event, branch string, changed []string) (*WorkflowFilterResult, error) {
+	var doc struct {
+		On yaml.Node `yaml:"on"`
+	}
+	if err := yaml.Unmarshal(workflowYAML, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+
+	result := &WorkflowFilterResult{}
+	filters, triggered, err := triggerFilters(&doc.On, event)
+	if err != nil {
+		return nil, err
+	}
+	if !triggered {
+		result.Reason = FilterReasonNoTrigger
+		return result, nil
+	}
+	result.Paths, result.PathsIgnore = filters.Paths, filters.PathsIgnore
+
+	if branch != "" && !branchFiltersAllow(filters, event, branch) {
+		result.Reason = FilterReasonBranchFiltered
+		return result, nil
+	}
+
+	switch {
+	case len(filters.Paths) > 0:
+		for _, file := range changed {
+			if filterListMatches(filters.Paths, file) {
+				result.MatchedFiles = append(result.MatchedFiles, file)
+			}
+		}
+		result.WillRun = len(result.MatchedFiles) > 0
+		result.Reason = FilterReasonPathsUnmatched
+		if result.WillRun {
+			result.Reason = FilterReasonPathsMatched
+		}
+	case len(filters.PathsIgnore) > 0:
+		for _, file := range changed {
+			if !filterListMatches(filters.PathsIgnore, file) {
+				result.MatchedFiles = append(result.MatchedFiles, file)
+			}
+		}
+		result.WillRun = len(result.MatchedFiles) > 0
+		result.Reason = FilterReasonAllIgnored
+		if result.WillRun {
+			result.Reason = FilterReasonPathsNotIgnored
+		}
+	default:
+		result.WillRun = true
+		result.Reason = FilterReasonNoPathFilter
+	}
+	if len(result.MatchedFiles) > maxMatchedFilesShown {
+		result.MatchedFiles = result.MatchedFiles[:maxMatchedFilesShown]
+	}
+	return result, nil
+}
+
+// triggerFilters returns the filters of event in a workflow's on: node and whether the
+// workflow triggers on event at all.
+func triggerFilters(on *yaml.Node, event string) (*eventFilters, bool, error) {
+	filters := &eventFilters{}
+	switch on.Kind {
+	case yaml.ScalarNode:
+		return filters, on.Value == event, nil
+	case yaml.SequenceNode:
+		for _, n := range on.Content {
+			if n.Value == event {
+				return filters, true, nil
+			}
+		}
+		return filters, false, nil
+	case yaml.MappingNode:
+		_, value := yamlMappingValue(on, event)
+		if value == nil {
+			return filters, false, nil
+		}
+		if value.Kind == yaml.MappingNode {
+			if err := value.Decode(filters); err != nil {
+				return nil, false, fmt.Errorf("failed to parse on.%s filters: %w", event, err)
+			}
+		}
+		return filters, true, nil
+	}
+	return filters, false, nil
+}
+
+// branchFiltersAllow applies branches/branches-ignore to the pushed branch (or a pull
+// request's base branch). A push trigger that only filters tags never runs for branches.
+func branchFiltersAllow(f *eventFilters, event, branch string) bool {
+	switch {
+	case len(f.Branches) > 0:
+		return filterListMatches(f.Branches, branch)
+	case len(f.BranchesIgnore) > 0:
+		return !filterListMatches(f.BranchesIgnore, branch)
+	case event == "push" && (len(f.Tags) > 0 || len(f.TagsIgnore) > 0):
+		return false
+	}
+	return true
+}
+
+// filterListMatches reports whether name is selected by a GitHub filter pattern list:
+// the last pattern matching name decides, and "!" patterns deselect. Invalid patterns are
+// skipped.
+func filterListMatches(patterns []string, name string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		if negated {
+			pattern = pattern[1:]
+		}
+		re, err := filterPatternRegexp(pattern)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(name) {
+			matched = !negated
+		}
+	}
+	return matched
+}
+
+// filterPatternRegexp compiles a GitHub Actions filter pattern: "*" matches within a path
+// segment, "**" across segments, "?" and "+" quantify the preceding character, and [...]
+// is a character class.
+func filterPatternRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; ch {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					// "**/" also matches no directory at all.
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?', '+':
+			sb.WriteByte(ch)
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				sb.WriteString(regexp.QuoteMeta(pattern[i:]))
+				i = len(pattern)
+				continue
+			}
+			sb.WriteString(pattern[i : i+end+1])
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterListMatches(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		name     string
+		want     bool
+	}{
+		{[]string{"docs/**"}, "docs/guide/intro.md", true},
+		{[]string{"docs/*"}, "docs/guide/intro.md", false},
+		{[]string{"**.md"}, "README.md", true},
+		{[]string{"**/*.go"}, "main.go", true},
+		{[]string{"**/*.go"}, "cmd/root.go", true},
+		{[]string{"*.go"}, "cmd/root.go", false},
+		{[]string{"services/[ab]pi/**"}, "services/api/main.go", true},
+		{[]string{"services/[ab]pi/**"}, "services/cpi/main.go", false},
+		{[]string{"sub/**", "!sub/**/*.md"}, "sub/docs/readme.md", false},
+		{[]string{"sub/**", "!sub/**/*.md", "sub/keep.md"}, "sub/keep.md", true},
+		{[]string{"release/*"}, "release/1.0", true},
+		{[]string{"release/*"}, "release/1.0/hotfix", false},
+		{[]string{"v2+"}, "v222", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, filterListMatches(tt.patterns, tt.name), "%v vs %s", tt.patterns, tt.name)
+	}
+}
+
+func TestEvaluatePathFilters(t *testing.T) {
+	workflow := []byte(`
+name: API
+on:
+  push:
+    branches: [main, 'release/**']
+    paths:
+      - 'services/api/**'
+      - '!services/api/**/*.md'
+  pull_request:
+    paths-ignore: ['docs/**', '**.md']
+  workflow_dispatch:
+`)
+
+	result, err := EvaluatePathFilters(workflow, "push", "main", []string{"services/api/README.md", "services/api/server.go", "web/app.ts"})
+	require.NoError(t, err)
+	assert.True(t, result.WillRun)
+	assert.Equal(t, FilterReasonPathsMatched, result.Reason)
+	assert.Equal(t, []string{"services/api/server.go"}, result.MatchedFiles)
+
+	result, err = EvaluatePathFilters(workflow, "push", "main", []string{"services/api/README.md"})
+	require.NoError(t, err)
+	assert.False(t, result.WillRun)
+	assert.Equal(t, FilterReasonPathsUnmatched, result.Reason)
+
+	result, err = EvaluatePathFilters(workflow, "push", "feature/x", []string{"services/api/server.go"})
+	require.NoError(t, err)
+	assert.False(t, result.WillRun)
+	assert.Equal(t, FilterReasonBranchFiltered, result.Reason)
+
+	result, err = EvaluatePathFilters(workflow, "pull_request", "", []string{"docs/index.md", "CHANGELOG.md"})
+	require.NoError(t, err)
+	assert.False(t, result.WillRun)
+	assert.Equal(t, FilterReasonAllIgnored, result.Reason)
+
+	result, err = EvaluatePathFilters(workflow, "pull_request", "", []string{"docs/index.md", "web/app.ts"})
+	require.NoError(t, err)
+	assert.True(t, result.WillRun)
+	assert.Equal(t, FilterReasonPathsNotIgnored, result.Reason)
+	assert.Equal(t, []string{"web/app.ts"}, result.MatchedFiles)
+
+	result, err = EvaluatePathFilters([]byte("on: [push, pull_request]\n"), "push", "main", []string{"any"})
+	require.NoError(t, err)
+	assert.True(t, result.WillRun)
+	assert.Equal(t, FilterReasonNoPathFilter, result.Reason)
+
+	result, err = EvaluatePathFilters([]byte("on:\n  push:\n    tags: ['v*']\n"), "push", "main", []string{"any"})
+	require.NoError(t, err)
+	assert.Equal(t, FilterReasonBranchFiltered, result.Reason, "a tags-only push trigger does not run for branches")
+
+	result, err = EvaluatePathFilters([]byte("on: schedule\n"), "push", "", []string{"any"})
+	require.NoError(t, err)
+	assert.False(t, result.WillRun)
+	assert.Equal(t, FilterReasonNoTrigger, result.Reason)
+}
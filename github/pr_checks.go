@@ -0,0 +1,312 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// actionsJobURLPattern extracts the run and job IDs from a GitHub Actions check run details URL.
+var actionsJobURLPattern = regexp.MustCompile(`/actions/runs/(\d+)/job(?:s)?/(\d+)`)
+
+// PRCheck is one check (or legacy commit status) reported on a pull request's head commit.
+type PRCheck struct {
+	Name            string  `json:"name"`
+	Status          string  `json:"status"`
+	Conclusion      string  `json:"conclusion,omitempty"`
+	Required        bool    `json:"required"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+	DetailsURL      string  `json:"details_url,omitempty"`
+	App             string  `json:"app,omitempty"`
+	Source          string  `json:"source"` // check_run or status
+	CheckRunID      int64   `json:"check_run_id,omitempty"`
+	RunID           int64   `json:"run_id,omitempty"`
+	JobID           int64   `json:"job_id,omitempty"`
+}
+
+// PRWorkflowRun is a workflow run on a pull request's head commit.
+type PRWorkflowRun struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Path       string `json:"path,omitempty"`
+	Event      string `json:"event"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	Attempt    int    `json:"attempt,omitempty"`
+	URL        string `json:"url"`
+}
+
+// PRChecksReport summarizes all checks on a pull request's head commit.
+type PRChecksReport struct {
+	Number          int              `json:"number"`
+	Title           string           `json:"title"`
+	HeadSHA         string           `json:"head_sha"`
+	HeadBranch      string           `json:"head_branch"`
+	BaseBranch      string           `json:"base_branch"`
+	State           string           `json:"state"` // pending, success, failure
+	Counts          map[string]int   `json:"counts"`
+	Checks          []*PRCheck       `json:"checks"`
+	WorkflowRuns    []*PRWorkflowRun `json:"workflow_runs"` // Latest run of each workflow on the head commit
+	MissingRequired []string         `json:"missing_required,omitempty"`
+	FailingRequired []string         `json:"failing_required,omitempty"`
+	Notes           []string         `json:"notes,omitempty"`
+}
+
+// GetPRChecks lists the check runs and commit statuses on a pull request's head commit,
+// marks those required by branch protection or rulesets on the base branch, and includes
+// the workflow run and job IDs needed to rerun GitHub Actions checks. It also lists the
+// latest workflow run of each workflow on the head commit, which covers runs that have no
+// check runs: those awaiting approval to run for a fork, and those that failed to start.
+func (c *Client) GetPRChecks(ctx context.Context, number int) (*PRChecksReport, error) {
+	pr, _, err := c.gh.PullRequests.Get(ctx, c.owner, c.repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request #%d: %w", number, err)
+	}
+
+	report := &PRChecksReport{
+		Number:       number,
+		Title:        pr.GetTitle(),
+		HeadSHA:      pr.GetHead().GetSHA(),
+		HeadBranch:   pr.GetHead().GetRef(),
+		BaseBranch:   pr.GetBase().GetRef(),
+		Counts:       make(map[string]int),
+		Checks:       []*PRCheck{},
+		WorkflowRuns: []*PRWorkflowRun{},
+	}
+
+	required, notes := c.requiredCheckContexts(ctx, report.BaseBranch)
+	report.Notes = append(report.Notes, notes...)
+
+	checkOpts := &github.ListCheckRunsOptions{
+		Filter:      github.Ptr("latest"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		checkRuns, resp, err := c.gh.Checks.ListCheckRunsForRef(ctx, c.owner, c.repo, report.HeadSHA, checkOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list check runs for %s: %w", report.HeadSHA, err)
+		}
+		for _, cr := range checkRuns.CheckRuns {
+			report.Checks = append(report.Checks, prCheckFromCheckRun(cr, required))
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		checkOpts.Page = resp.NextPage
+	}
+
+	runs, err := c.listPRWorkflowRuns(ctx, report.HeadSHA)
+	if err != nil {
+		log.Debugf("Could not list workflow runs for %s: %v", report.HeadSHA, err)
+		report.Notes = append(report.Notes, "Workflow runs could not be listed; runs without check runs may be missing.")
+	}
+	report.WorkflowRuns = append(report.WorkflowRuns, runs...)
+	if n := countRuns(runs, func(r *PRWorkflowRun) bool { return r.Conclusion == "action_required" }); n > 0 {
+		report.Notes = append(report.Notes, fmt.Sprintf("%d workflow run(s) await approval: a maintainer must approve running workflows for this pull request (\"Approve and run\" on the pull request page).", n))
+	}
+	if n := countRuns(runs, func(r *PRWorkflowRun) bool { return r.Conclusion == "startup_failure" }); n > 0 {
+		report.Notes = append(report.Notes, fmt.Sprintf("%d workflow run(s) failed to start, usually because of an invalid workflow file; such runs have no jobs or check runs.", n))
+	}
+
+	statuses, _, err := c.gh.Repositories.GetCombinedStatus(ctx, c.owner, c.repo, report.HeadSHA, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		log.Debugf("Could not get commit statuses for %s: %v", report.HeadSHA, err)
+	} else {
+		for _, st := range statuses.Statuses {
+			report.Checks = append(report.Checks, prCheckFromStatus(st, required))
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, check := range report.Checks {
+		seen[check.Name] = true
+		report.Counts[prCheckOutcome(check)]++
+		if check.Required && isFailureConclusion(check.Conclusion) {
+			report.FailingRequired = append(report.FailingRequired, check.Name)
+		}
+	}
+	for name := range required {
+		if !seen[name] {
+			report.MissingRequired = append(report.MissingRequired, name)
+		}
+	}
+	sort.Strings(report.MissingRequired)
+
+	sort.SliceStable(report.Checks, func(i, j int) bool {
+		if report.Checks[i].Required != report.Checks[j].Required {
+			return report.Checks[i].Required
+		}
+		return report.Checks[i].Name < report.Checks[j].Name
+	})
+
+	report.State = prChecksState(report)
+	return report, nil
+}
+
+// requiredCheckContexts collects the status check contexts required on a branch by classic
+// branch protection and by rulesets. Lookup failures are reported as notes, not errors.
+func (c *Client) requiredCheckContexts(ctx context.Context, branch string) (map[string]bool, []string) {
+	required := make(map[string]bool)
+	var notes []string
+
+	checks, resp, err := c.gh.Repositories.GetRequiredStatusChecks(ctx, c.owner, c.repo, branch)
+	switch {
+	case err == nil:
+		for _, check := range checks.GetChecks() {
+			required[check.Context] = true
+		}
+		for _, name := range checks.GetContexts() {
+			required[name] = true
+		}
+	case resp != nil && resp.StatusCode == http.StatusNotFound:
+		// No classic branch protection, or no required checks configured.
+	default:
+		log.Debugf("Could not get required status checks for %s: %v", branch, err)
+		notes = append(notes, "Branch protection could not be read (needs Administration: Read); required flags may be incomplete.")
+	}
+
+	rules, _, err := c.gh.Repositories.GetRulesForBranch(ctx, c.owner, c.repo, branch)
+	if err != nil {
+		log.Debugf("Could not get rules for %s: %v", branch, err)
+		notes = append(notes, "Branch rulesets could not be read; required flags may be incomplete.")
+	} else if rules != nil {
+		for _, rule := range rules.RequiredStatusChecks {
+			for _, check := range rule.Parameters.RequiredStatusChecks {
+				required[check.Context] = true
+			}
+		}
+	}
+
+	return required, notes
+}
+
+// listPRWorkflowRuns returns the latest run of each workflow on sha, ordered by name.
+func (c *Client) listPRWorkflowRuns(ctx context.Context, sha string) ([]*PRWorkflowRun, error) {
+	runs, _, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, &github.ListWorkflowRunsOptions{
+		HeadSHA:     sha,
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, Classify(err)
+	}
+	var result []*PRWorkflowRun
+	seen := make(map[int64]bool)
+	// The API lists newest first, so the first run of a workflow is its latest.
+	for _, r := range runs.WorkflowRuns {
+		if seen[r.GetWorkflowID()] {
+			continue
+		}
+		seen[r.GetWorkflowID()] = true
+		result = append(result, &PRWorkflowRun{
+			ID:         r.GetID(),
+			Name:       r.GetName(),
+			Path:       r.GetPath(),
+			Event:      r.GetEvent(),
+			Status:     r.GetStatus(),
+			Conclusion: r.GetConclusion(),
+			Attempt:    r.GetRunAttempt(),
+			URL:        r.GetHTMLURL(),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func countRuns(runs []*PRWorkflowRun, match func(*PRWorkflowRun) bool) int {
+	n := 0
+	for _, r := range runs {
+		if match(r) {
+			n++
+		}
+	}
+	return n
+}
+
+func prCheckFromCheckRun(cr *github.CheckRun, required map[string]bool) *PRCheck {
+	check := &PRCheck{
+		Name:       cr.GetName(),
+		Status:     cr.GetStatus(),
+		Conclusion: cr.GetConclusion(),
+		Required:   required[cr.GetName()],
+		DetailsURL: cr.GetDetailsURL(),
+		App:        cr.GetApp().GetSlug(),
+		Source:     "check_run",
+		CheckRunID: cr.GetID(),
+	}
+	if cr.StartedAt != nil && cr.CompletedAt != nil {
+		check.DurationSeconds = cr.GetCompletedAt().Sub(cr.GetStartedAt().Time).Seconds()
+	}
+	if m := actionsJobURLPattern.FindStringSubmatch(check.DetailsURL); m != nil {
+		check.RunID, _ = strconv.ParseInt(m[1], 10, 64)
+		check.JobID, _ = strconv.ParseInt(m[2], 10, 64)
+	}
+	return check
+}
+
+func prCheckFromStatus(st *github.RepoStatus, required map[string]bool) *PRCheck {
+	check := &PRCheck{
+		Name:       st.GetContext(),
+		Status:     "completed",
+		Required:   required[st.GetContext()],
+		DetailsURL: st.GetTargetURL(),
+		App:        st.GetCreator().GetLogin(),
+		Source:     "status",
+	}
+	switch st.GetState() {
+	case "pending":
+		check.Status = "in_progress"
+	case "error":
+		check.Conclusion = "failure"
+	default:
+		check.Conclusion = st.GetState()
+	}
+	return check
+}
+
+// prCheckOutcome buckets a check for the report counts.
+func prCheckOutcome(check *PRCheck) string {
+	if check.Status != "completed" {
+		return "pending"
+	}
+	if check.Conclusion == "" {
+		return "unknown"
+	}
+	return strings.ToLower(check.Conclusion)
+}
+
+func prChecksState(report *PRChecksReport) string {
+	if len(report.FailingRequired) > 0 {
+		return "failure"
+	}
+	pending := len(report.MissingRequired) > 0
+	failed := false
+	for _, check := range report.Checks {
+		if check.Status != "completed" {
+			pending = true
+		} else if isFailureConclusion(check.Conclusion) {
+			failed = true
+		}
+	}
+	// Runs awaiting approval and runs that failed to start have no check runs to go by.
+	for _, run := range report.WorkflowRuns {
+		switch {
+		case run.Conclusion == "action_required":
+			pending = true
+		case run.Conclusion == "startup_failure":
+			failed = true
+		}
+	}
+	switch {
+	case failed:
+		return "failure"
+	case pending:
+		return "pending"
+	}
+	return "success"
+}
@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPRChecks(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"number": 7, "title": "Add feature", "head": {"ref": "feature", "sha": "abc123"}, "base": {"ref": "main"}}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/branches/main/protection/required_status_checks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"strict": true, "contexts": ["build"], "checks": [{"context": "build"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/rules/branches/main", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"type": "required_status_checks", "ruleset_source_type": "Organization", "ruleset_source": "test-owner", "ruleset_id": 5,
+			"parameters": {"required_status_checks": [{"context": "security"}, {"context": "license"}], "strict_required_status_checks_policy": false}}]`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "latest", r.URL.Query().Get("filter"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 3, "check_runs": [
+			{"id": 301, "name": "lint", "status": "completed", "conclusion": "success", "started_at": "2026-04-20T10:00:00Z", "completed_at": "2026-04-20T10:00:30Z",
+			 "details_url": "https://github.com/test-owner/test-repo/actions/runs/900/job/301", "app": {"slug": "github-actions"}},
+			{"id": 302, "name": "build", "status": "completed", "conclusion": "failure", "started_at": "2026-04-20T10:00:00Z", "completed_at": "2026-04-20T10:02:00Z",
+			 "details_url": "https://github.com/test-owner/test-repo/actions/runs/900/job/302", "app": {"slug": "github-actions"}},
+			{"id": 303, "name": "security", "status": "in_progress", "details_url": "https://scanner.example.com/303", "app": {"slug": "scanner"}}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc123", r.URL.Query().Get("head_sha"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 3, "workflow_runs": [
+			{"id": 901, "name": "CI", "path": ".github/workflows/ci.yml", "workflow_id": 1, "event": "pull_request", "status": "completed", "conclusion": "failure", "run_attempt": 2},
+			{"id": 910, "name": "Docs", "path": ".github/workflows/docs.yml", "workflow_id": 2, "event": "pull_request", "status": "completed", "conclusion": "action_required", "run_attempt": 1},
+			{"id": 900, "name": "CI", "path": ".github/workflows/ci.yml", "workflow_id": 1, "event": "pull_request", "status": "completed", "conclusion": "failure", "run_attempt": 1}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/abc123/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"state": "success", "statuses": [{"context": "ci/legacy", "state": "success", "target_url": "https://ci.example.com/1"}]}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	report, err := client.GetPRChecks(context.Background(), 7)
+	require.NoError(t, err)
+
+	assert.Equal(t, "abc123", report.HeadSHA)
+	assert.Equal(t, "main", report.BaseBranch)
+	assert.Equal(t, "failure", report.State)
+	assert.Equal(t, []string{"build"}, report.FailingRequired)
+	assert.Equal(t, []string{"license"}, report.MissingRequired)
+	require.Len(t, report.Notes, 1)
+	assert.Contains(t, report.Notes[0], "1 workflow run(s) await approval")
+
+	require.Len(t, report.WorkflowRuns, 2)
+	assert.Equal(t, int64(901), report.WorkflowRuns[0].ID, "only the latest run of each workflow is listed")
+	assert.Equal(t, 2, report.WorkflowRuns[0].Attempt)
+	assert.Equal(t, "Docs", report.WorkflowRuns[1].Name)
+	assert.Equal(t, "action_required", report.WorkflowRuns[1].Conclusion)
+
+	require.Len(t, report.Checks, 4)
+	build := report.Checks[0]
+	assert.Equal(t, "build", build.Name)
+	assert.True(t, build.Required)
+	assert.Equal(t, int64(900), build.RunID)
+	assert.Equal(t, int64(302), build.JobID)
+	assert.Equal(t, 120.0, build.DurationSeconds)
+
+	security := report.Checks[1]
+	assert.Equal(t, "security", security.Name)
+	assert.True(t, security.Required)
+	assert.Zero(t, security.RunID)
+
+	assert.Equal(t, "ci/legacy", report.Checks[2].Name)
+	assert.Equal(t, "status", report.Checks[2].Source)
+	assert.False(t, report.Checks[3].Required)
+
+	assert.Equal(t, 2, report.Counts["success"])
+	assert.Equal(t, 1, report.Counts["failure"])
+	assert.Equal(t, 1, report.Counts["pending"])
+}
+
+func TestPRChecksState_WorkflowRuns(t *testing.T) {
+	report := &PRChecksReport{
+		Checks:       []*PRCheck{{Name: "lint", Status: "completed", Conclusion: "success"}},
+		WorkflowRuns: []*PRWorkflowRun{{Name: "CI", Status: "completed", Conclusion: "action_required"}},
+	}
+	assert.Equal(t, "pending", prChecksState(report))
+
+	report.WorkflowRuns = append(report.WorkflowRuns, &PRWorkflowRun{Name: "Release", Status: "completed", Conclusion: "startup_failure"})
+	assert.Equal(t, "failure", prChecksState(report))
+
+	report.WorkflowRuns = nil
+	assert.Equal(t, "success", prChecksState(report))
+}
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// RefAllowed reports whether ref matches one of the glob patterns (path.Match syntax, so
+// "release/*" matches "release/1.0" but not "release/1.0/hotfix"). Leading "refs/heads/"
+// and "refs/tags/" are ignored on both sides. An empty pattern list allows every ref.
+func RefAllowed(ref string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	ref = shortRefName(ref)
+	for _, pattern := range patterns {
+		pattern = shortRefName(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if ok, err := path.Match(pattern, ref); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func shortRefName(ref string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/tags/")
+}
+
+// checkRefAllowed returns an error when ref is outside the client's allowed refs.
+func (c *Client) checkRefAllowed(ref string) error {
+	if RefAllowed(ref, c.allowedRefs) {
+		return nil
+	}
+	return fmt.Errorf("%w: ref %q is not in allowed_trigger_refs (%s)", ErrRefNotAllowed, ref, strings.Join(c.allowedRefs, ", "))
+}
+
+// defaultRefFor returns the configured default ref of a workflow, looked up by each of keys
+// (the workflow as given, its path, file name, and name) in turn, or "".
+func (c *Client) defaultRefFor(keys ...string) string {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if ref, ok := c.defaultRefs[key]; ok {
+			return ref
+		}
+	}
+	return ""
+}
+
+// checkRunRefAllowed returns an error when the run's branch is outside the client's allowed refs.
+func (c *Client) checkRunRefAllowed(ctx context.Context, runID int64) error {
+	if len(c.allowedRefs) == 0 {
+		return nil
+	}
+	run, err := c.GetWorkflowRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if err := c.checkRefAllowed(run.Branch); err != nil {
+		return fmt.Errorf("cannot rerun run %d: %w", runID, err)
+	}
+	return nil
+}
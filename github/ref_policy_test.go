@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefAllowed(t *testing.T) {
+	patterns := []string{"main", "develop", "release/*", "refs/tags/v*"}
+
+	assert.True(t, RefAllowed("main", patterns))
+	assert.True(t, RefAllowed("refs/heads/develop", patterns))
+	assert.True(t, RefAllowed("release/1.0", patterns))
+	assert.True(t, RefAllowed("v1.2.3", patterns))
+	assert.False(t, RefAllowed("release/1.0/hotfix", patterns))
+	assert.False(t, RefAllowed("feature/x", patterns))
+	assert.False(t, RefAllowed("mainline", patterns))
+	assert.True(t, RefAllowed("anything", nil))
+}
+
+func TestManageRun_RerunRespectsAllowedRefs(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	reruns := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1, "head_branch": "feature/x"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 2, "head_branch": "release/2.0"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/2/rerun", func(w http.ResponseWriter, r *http.Request) {
+		reruns++
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50, allowedRefs: []string{"main", "release/*"}}
+
+	_, err = client.ManageRun(context.Background(), 1, ManageRunActionRerun)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `ref "feature/x" is not in allowed_trigger_refs`)
+
+	result, err := client.ManageRun(context.Background(), 2, ManageRunActionRerun)
+	require.NoError(t, err)
+	assert.Equal(t, "success", result.Status)
+	assert.Equal(t, 1, reruns)
+
+	_, err = client.DispatchWorkflow(context.Background(), DispatchOptions{Workflow: "123", Ref: "feature/x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "allowed_trigger_refs")
+}
+
+func TestRerunJob_RespectsAllowedRefs(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	reruns := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/jobs/10", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 10, "run_id": 1}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/jobs/20", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 20, "run_id": 2}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1, "head_branch": "feature/x"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 2, "head_branch": "main"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/jobs/20/rerun", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		reruns++
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50, allowedRefs: []string{"main"}}
+
+	err = client.RerunJob(context.Background(), 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `ref "feature/x" is not in allowed_trigger_refs`)
+
+	require.NoError(t, client.RerunJob(context.Background(), 20))
+	assert.Equal(t, 1, reruns)
+}
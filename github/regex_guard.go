@@ -0,0 +1,54 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"time"
+)
+
+// Limits on user-supplied filter_regex patterns. Go's RE2 engine never backtracks, but a
+// huge pattern still costs time proportional to its program size for every byte scanned,
+// which adds up on logs of hundreds of megabytes.
+const (
+	maxRegexPatternLength  = 1024
+	maxRegexProgramSize    = 5000
+	regexMatchWindow       = 16 * 1024 // Only the first 16KB of a line is matched
+	maxCachedRegexes       = 128
+	regexTimeCheckInterval = 256 // Lines between deadline checks
+)
+
+// regexFilterTimeout bounds how long a filter may scan a log; overridden in tests.
+var regexFilterTimeout = 10 * time.Second
+
+// ErrRegexTimeout is returned when filtering a log takes longer than regexFilterTimeout.
+var ErrRegexTimeout = errors.New("log filter timed out")
+
+// compileFilterRegex compiles a user-supplied pattern after checking its length and the
+// size of the compiled program.
+func compileFilterRegex(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) > maxRegexPatternLength {
+		return nil, fmt.Errorf("pattern is %d characters long; the limit is %d", len(pattern), maxRegexPatternLength)
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	if len(prog.Inst) > maxRegexProgramSize {
+		return nil, fmt.Errorf("pattern is too complex (%d instructions; the limit is %d)", len(prog.Inst), maxRegexProgramSize)
+	}
+	return regexp.Compile(pattern)
+}
+
+// matchWindow returns the part of a log line a filter is applied to.
+func matchWindow(line string) string {
+	if len(line) > regexMatchWindow {
+		return line[:regexMatchWindow]
+	}
+	return line
+}
@@ -0,0 +1,61 @@
+package github
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileFilterRegex_Limits(t *testing.T) {
+	_, err := compileFilterRegex("error|fail(ed|ure)")
+	require.NoError(t, err)
+
+	_, err = compileFilterRegex(strings.Repeat("a", maxRegexPatternLength+1))
+	assert.ErrorContains(t, err, "limit is")
+
+	_, err = compileFilterRegex(`((a{100}){100}){100}`)
+	assert.Error(t, err)
+
+	_, err = compileFilterRegex(`(foo|bar|baz){1000}`)
+	assert.ErrorContains(t, err, "too complex")
+
+	_, err = compileFilterRegex(`(unclosed`)
+	assert.Error(t, err)
+}
+
+func TestFilterLogLines_MatchWindow(t *testing.T) {
+	long := strings.Repeat("x", regexMatchWindow) + "needle"
+	lines := parseLogLines("short needle\n" + long)
+
+	filtered, err := filterLogLines(lines, &LogFilterOptions{FilterRegex: "needle"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "short needle", filtered[0].content)
+}
+
+func TestFilterLogLines_Timeout(t *testing.T) {
+	original := regexFilterTimeout
+	regexFilterTimeout = 0
+	t.Cleanup(func() { regexFilterTimeout = original })
+
+	lines := parseLogLines(strings.Repeat("line\n", 2*regexTimeCheckInterval))
+	time.Sleep(time.Millisecond)
+
+	_, err := filterLogLines(lines, &LogFilterOptions{FilterRegex: "nomatch"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrRegexTimeout))
+}
+
+func TestGetCachedRegex_BoundedCache(t *testing.T) {
+	for i := 0; i < maxCachedRegexes+10; i++ {
+		_, err := getCachedRegex("pattern-" + strings.Repeat("x", i))
+		require.NoError(t, err)
+	}
+	regexCacheMutex.RLock()
+	defer regexCacheMutex.RUnlock()
+	assert.LessOrEqual(t, len(regexCache), maxCachedRegexes)
+}
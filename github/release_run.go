@@ -0,0 +1,268 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// releasePollInterval is how often RunRelease polls for the runs a tag triggered.
+var releasePollInterval = 5 * time.Second
+
+// defaultReleaseDiscoveryTimeout bounds how long RunRelease waits for the first run to start.
+const defaultReleaseDiscoveryTimeout = 2 * time.Minute
+
+// ReleaseOptions configures RunRelease.
+type ReleaseOptions struct {
+	Tag              string        // Tag to release, e.g. v1.2.3
+	Target           string        // Optional: commit SHA or branch to create Tag at; empty follows an existing tag
+	Workflow         string        // Optional: only follow runs of this workflow (name, path, or ID)
+	DiscoveryTimeout time.Duration // How long to wait for the first run to start (default: 2m)
+	TimeoutMinutes   int           // How long to wait for the runs to complete (default: 30)
+	// OnApproval, if set, is called when a run starts waiting for an environment approval.
+	OnApproval func(*PendingApproval)
+}
+
+// ReleaseRunOutcome is one run of a release and the artifacts it uploaded.
+type ReleaseRunOutcome struct {
+	Run       *WorkflowRun   `json:"run"`
+	Wait      *WaitRunResult `json:"wait,omitempty"`
+	Artifacts []*Artifact    `json:"artifacts,omitempty"`
+}
+
+// ReleaseAsset is a file attached to a GitHub release.
+type ReleaseAsset struct {
+	Name          string `json:"name"`
+	ContentType   string `json:"content_type,omitempty"`
+	Size          int    `json:"size"`
+	DownloadCount int    `json:"download_count"`
+	URL           string `json:"url"`
+}
+
+// ReleaseResult is the outcome of RunRelease.
+type ReleaseResult struct {
+	Tag        string               `json:"tag"`
+	SHA        string               `json:"sha"`
+	TagCreated bool                 `json:"tag_created"`
+	Verdict    string               `json:"verdict"` // One of the ReleaseVerdict* constants
+	Runs       []*ReleaseRunOutcome `json:"runs"`
+	Release    string               `json:"release,omitempty"`
+	ReleaseURL string               `json:"release_url,omitempty"`
+	Draft      bool                 `json:"draft,omitempty"`
+	Assets     []*ReleaseAsset      `json:"assets,omitempty"`
+	Warnings   []string             `json:"warnings,omitempty"`
+}
+
+// RunRelease runs a release end to end: it optionally creates the tag at Target, waits for
+// the runs the tag triggers (tag pushes, release events, and runs dispatched on the tag) to
+// start and complete, and returns their artifacts along with the assets of the tag's
+// release. Runs started by other release runs, such as a release event fired when a
+// workflow publishes the release, are followed too. Without Target, the latest run of each
+// workflow for the existing tag is followed.
+func (c *Client) RunRelease(ctx context.Context, opts ReleaseOptions) (*ReleaseResult, error) {
+	if opts.Tag == "" {
+		return nil, fmt.Errorf("tag is required")
+	}
+	timeoutMinutes := opts.TimeoutMinutes
+	if timeoutMinutes <= 0 {
+		timeoutMinutes = 30
+	}
+	discoveryTimeout := opts.DiscoveryTimeout
+	if discoveryTimeout <= 0 {
+		discoveryTimeout = defaultReleaseDiscoveryTimeout
+	}
+
+	var workflowID int64
+	if opts.Workflow != "" {
+		id, _, err := c.ResolveWorkflowID(ctx, opts.Workflow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve workflow %s: %w", opts.Workflow, err)
+		}
+		workflowID = id
+	}
+
+	result := &ReleaseResult{Tag: opts.Tag, Runs: []*ReleaseRunOutcome{}}
+	startedAt := time.Now()
+	var createdAfter time.Time
+	if opts.Target != "" {
+		sha, err := c.createTag(ctx, opts.Tag, opts.Target)
+		if err != nil {
+			return nil, err
+		}
+		result.SHA, result.TagCreated = sha, true
+		createdAfter = startedAt.Add(-dispatchClockSkew)
+	} else {
+		sha, _, err := c.gh.Repositories.GetCommitSHA1(ctx, c.owner, c.repo, "tags/"+opts.Tag, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tag %s: %w", opts.Tag, Classify(err))
+		}
+		result.SHA = sha
+	}
+
+	deadline := startedAt.Add(time.Duration(timeoutMinutes) * time.Minute)
+	discoveryDeadline := time.Now().Add(discoveryTimeout)
+	seen := make(map[int64]bool)
+	for {
+		runs, err := c.listReleaseRuns(ctx, opts.Tag, result.SHA, workflowID, createdAfter)
+		if err != nil {
+			return nil, err
+		}
+		var next []*WorkflowRun
+		for _, run := range runs {
+			if !seen[run.ID] {
+				seen[run.ID] = true
+				next = append(next, run)
+			}
+		}
+
+		if len(next) == 0 {
+			if len(result.Runs) > 0 {
+				break
+			}
+			if time.Now().After(discoveryDeadline) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("no run for tag %s started within %s", opts.Tag, discoveryTimeout))
+				break
+			}
+			timer := time.NewTimer(releasePollInterval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+
+		for _, run := range next {
+			outcome := &ReleaseRunOutcome{Run: run}
+			remaining := int(math.Ceil(time.Until(deadline).Minutes()))
+			if remaining < 1 {
+				remaining = 1
+			}
+			wait, err := c.WaitForRunWithOptions(ctx, run.ID, WaitRunOptions{TimeoutMinutes: remaining, OnApproval: opts.OnApproval})
+			if err != nil && wait == nil {
+				return nil, fmt.Errorf("failed to wait for run %d: %w", run.ID, err)
+			}
+			outcome.Wait = wait
+			if artifacts, err := c.GetWorkflowRunArtifacts(ctx, run.ID); err == nil {
+				outcome.Artifacts = artifacts
+			} else {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("could not list artifacts of run %d: %v", run.ID, err))
+			}
+			result.Runs = append(result.Runs, outcome)
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+	}
+	result.Verdict = releaseVerdict(result.Runs)
+
+	release, _, err := c.gh.Repositories.GetReleaseByTag(ctx, c.owner, c.repo, opts.Tag)
+	switch {
+	case err == nil:
+		result.Release = release.GetName()
+		result.ReleaseURL = release.GetHTMLURL()
+		result.Draft = release.GetDraft()
+		for _, a := range release.Assets {
+			result.Assets = append(result.Assets, &ReleaseAsset{
+				Name:          a.GetName(),
+				ContentType:   a.GetContentType(),
+				Size:          a.GetSize(),
+				DownloadCount: a.GetDownloadCount(),
+				URL:           a.GetBrowserDownloadURL(),
+			})
+		}
+	case errors.Is(Classify(err), ErrNotFound):
+		// Draft releases are not found by tag; a workflow creating one is not an error.
+		result.Warnings = append(result.Warnings, fmt.Sprintf("no published release for tag %s", opts.Tag))
+	default:
+		result.Warnings = append(result.Warnings, fmt.Sprintf("could not get the release for tag %s: %v", opts.Tag, err))
+	}
+
+	return result, nil
+}
+
+// createTag creates a lightweight tag at target (a commit SHA or branch) and returns the
+// tagged commit.
+func (c *Client) createTag(ctx context.Context, tag, target string) (string, error) {
+	if err := c.checkRefAllowed("refs/tags/" + tag); err != nil {
+		return "", fmt.Errorf("cannot create tag %s: %w", tag, err)
+	}
+	sha, _, err := c.gh.Repositories.GetCommitSHA1(ctx, c.owner, c.repo, target, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", target, Classify(err))
+	}
+	_, _, err = c.gh.Git.CreateRef(ctx, c.owner, c.repo, &github.Reference{
+		Ref:    github.Ptr("refs/tags/" + tag),
+		Object: &github.GitObject{SHA: github.Ptr(sha)},
+	})
+	if err != nil {
+		if IsHTTPError(err, http.StatusUnprocessableEntity) {
+			return "", fmt.Errorf("failed to create tag %s: it already exists; omit target to follow its runs: %w", tag, Classify(err))
+		}
+		return "", fmt.Errorf("failed to create tag %s: %w", tag, Classify(err))
+	}
+	return sha, nil
+}
+
+// listReleaseRuns returns the runs for tag at sha, oldest first. Runs created before
+// createdAfter are skipped; with a zero createdAfter only the latest run of each workflow
+// is kept, so superseded attempts on an existing tag are not waited on.
+func (c *Client) listReleaseRuns(ctx context.Context, tag, sha string, workflowID int64, createdAfter time.Time) ([]*WorkflowRun, error) {
+	listOpts := &github.ListWorkflowRunsOptions{HeadSHA: sha, ListOptions: github.ListOptions{PerPage: 100}}
+	var runs *github.WorkflowRuns
+	var err error
+	if workflowID != 0 {
+		runs, _, err = c.gh.Actions.ListWorkflowRunsByID(ctx, c.owner, c.repo, workflowID, listOpts)
+	} else {
+		runs, _, err = c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, listOpts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs for %s: %w", sha, Classify(err))
+	}
+
+	var result []*WorkflowRun
+	latest := make(map[int64]bool)
+	for _, r := range runs.WorkflowRuns {
+		if r.GetHeadBranch() != tag && r.GetEvent() != "release" {
+			continue
+		}
+		if !createdAfter.IsZero() {
+			if r.GetCreatedAt().Before(createdAfter) {
+				continue
+			}
+		} else if latest[r.GetWorkflowID()] {
+			continue
+		}
+		latest[r.GetWorkflowID()] = true
+		result = append(result, workflowRunFromGitHub(r))
+	}
+	// The API lists newest first; follow runs in the order they started.
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
+
+// releaseVerdict summarizes the waited-on runs of a release.
+func releaseVerdict(outcomes []*ReleaseRunOutcome) string {
+	if len(outcomes) == 0 {
+		return ReleaseVerdictNoRuns
+	}
+	verdict := ReleaseVerdictSuccess
+	for _, o := range outcomes {
+		if o.Wait == nil || o.Wait.Status != "completed" {
+			verdict = ReleaseVerdictInProgress
+			continue
+		}
+		if isFailureConclusion(o.Wait.Conclusion) || o.Wait.Conclusion == "cancelled" {
+			return ReleaseVerdictFailure
+		}
+	}
+	return verdict
+}
@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRelease_CreatesTagAndFollowsRuns(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	oldInterval := releasePollInterval
+	releasePollInterval = 10 * time.Millisecond
+	defer func() { releasePollInterval = oldInterval }()
+
+	var mu sync.Mutex
+	lists := 0
+	var createdRef string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("abc123"))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var body struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "abc123", body.SHA)
+		createdRef = body.Ref
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ref": "refs/tags/v1.2.3", "object": {"sha": "abc123"}}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc123", r.URL.Query().Get("head_sha"))
+		mu.Lock()
+		lists++
+		n := lists
+		mu.Unlock()
+
+		now := time.Now().UTC().Format(time.RFC3339)
+		build := fmt.Sprintf(`{"id": 10, "workflow_id": 1, "name": "Build", "event": "push", "head_branch": "v1.2.3", "status": "completed", "conclusion": "success", "created_at": %q}`, now)
+		publish := fmt.Sprintf(`{"id": 11, "workflow_id": 2, "name": "Publish", "event": "release", "head_branch": "v1.2.3", "status": "completed", "conclusion": "success", "created_at": %q}`, now)
+		old := `{"id": 9, "workflow_id": 1, "name": "Build", "event": "push", "head_branch": "main", "status": "completed", "conclusion": "success", "created_at": "2020-01-01T00:00:00Z"}`
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case n == 1:
+			_, _ = fmt.Fprintf(w, `{"total_count": 1, "workflow_runs": [%s]}`, old)
+		case n == 2:
+			_, _ = fmt.Fprintf(w, `{"total_count": 2, "workflow_runs": [%s, %s]}`, build, old)
+		default:
+			_, _ = fmt.Fprintf(w, `{"total_count": 3, "workflow_runs": [%s, %s, %s]}`, publish, build, old)
+		}
+	})
+	for _, id := range []string{"10", "11"} {
+		id := id
+		mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/"+id, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"id": %s, "status": "completed", "conclusion": "success"}`, id)
+		})
+	}
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/10/artifacts", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "artifacts": [{"id": 100, "name": "dist", "size_in_bytes": 2048}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/11/artifacts", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 0, "artifacts": []}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/releases/tags/v1.2.3", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "v1.2.3", "html_url": "https://github.com/test-owner/test-repo/releases/tag/v1.2.3", "assets": [{"name": "app.tar.gz", "size": 4096, "browser_download_url": "https://github.com/test-owner/test-repo/releases/download/v1.2.3/app.tar.gz"}]}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50, allowedRefs: []string{"main", "refs/tags/v*"}}
+
+	result, err := client.RunRelease(context.Background(), ReleaseOptions{Tag: "v1.2.3", Target: "main", DiscoveryTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, "refs/tags/v1.2.3", createdRef)
+	assert.True(t, result.TagCreated)
+	assert.Equal(t, "abc123", result.SHA)
+	assert.Equal(t, ReleaseVerdictSuccess, result.Verdict)
+	require.Len(t, result.Runs, 2)
+	assert.Equal(t, int64(10), result.Runs[0].Run.ID)
+	require.Len(t, result.Runs[0].Artifacts, 1)
+	assert.Equal(t, "dist", result.Runs[0].Artifacts[0].Name)
+	assert.Equal(t, int64(11), result.Runs[1].Run.ID)
+	require.Len(t, result.Assets, 1)
+	assert.Equal(t, "app.tar.gz", result.Assets[0].Name)
+
+	_, err = client.RunRelease(context.Background(), ReleaseOptions{Tag: "nightly", Target: "main"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrRefNotAllowed)
+}
@@ -0,0 +1,139 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Verdicts of a release's CI.
+const (
+	ReleaseVerdictSuccess    = "success"     // Every release workflow's latest run passed
+	ReleaseVerdictFailure    = "failure"     // At least one release workflow's latest run failed or was cancelled
+	ReleaseVerdictInProgress = "in_progress" // No failure yet, but some runs have not finished
+	ReleaseVerdictNoRuns     = "no_runs"     // Nothing ran for the tag or release
+)
+
+// ReleaseRuns reports the workflow runs of a tag or release and whether they passed.
+type ReleaseRuns struct {
+	Tag        string         `json:"tag"`
+	Release    string         `json:"release,omitempty"` // Release name, if the tag has a release
+	ReleaseURL string         `json:"release_url,omitempty"`
+	Draft      bool           `json:"draft,omitempty"`
+	Prerelease bool           `json:"prerelease,omitempty"`
+	SHA        string         `json:"sha"`
+	Verdict    string         `json:"verdict"`
+	Failed     []string       `json:"failed,omitempty"`  // Workflows whose latest run failed or was cancelled
+	Pending    []string       `json:"pending,omitempty"` // Workflows whose latest run has not finished
+	Runs       []*WorkflowRun `json:"runs"`              // Runs for the tag: tag pushes, release events, dispatches on the tag
+	OtherRuns  []*WorkflowRun `json:"other_runs,omitempty"`
+}
+
+// GetReleaseRuns resolves a tag or release name to its commit and returns the runs triggered
+// for it (pushes of the tag, release events, and runs dispatched on the tag), along with
+// the other runs of the same commit, such as the branch push it was tagged from.
+func (c *Client) GetReleaseRuns(ctx context.Context, name string) (*ReleaseRuns, error) {
+	tag := name
+	release, err := c.findRelease(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if release != nil {
+		tag = release.GetTagName()
+	}
+
+	sha, _, err := c.gh.Repositories.GetCommitSHA1(ctx, c.owner, c.repo, "tags/"+tag, "")
+	if err != nil {
+		// A missing ref is a 422 ("No commit found") rather than a 404.
+		if errors.Is(Classify(err), ErrNotFound) || IsHTTPError(err, http.StatusUnprocessableEntity) {
+			if release != nil {
+				return nil, fmt.Errorf("release %s has no tag %s yet (draft releases create their tag when published)", release.GetName(), tag)
+			}
+			return nil, fmt.Errorf("no release or tag named %s", name)
+		}
+		return nil, fmt.Errorf("failed to resolve tag %s: %w", tag, Classify(err))
+	}
+
+	runs, _, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, &github.ListWorkflowRunsOptions{
+		HeadSHA:     sha,
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs for %s: %w", sha, Classify(err))
+	}
+
+	report := buildReleaseRuns(tag, sha, runs.WorkflowRuns)
+	if release != nil {
+		report.Release = release.GetName()
+		report.ReleaseURL = release.GetHTMLURL()
+		report.Draft = release.GetDraft()
+		report.Prerelease = release.GetPrerelease()
+	}
+	return report, nil
+}
+
+// findRelease returns the release tagged name or, failing that, the release titled name.
+// It returns nil when there is neither, so name can still be resolved as a bare tag.
+func (c *Client) findRelease(ctx context.Context, name string) (*github.RepositoryRelease, error) {
+	release, _, err := c.gh.Repositories.GetReleaseByTag(ctx, c.owner, c.repo, name)
+	if err == nil {
+		return release, nil
+	}
+	if !errors.Is(Classify(err), ErrNotFound) {
+		return nil, fmt.Errorf("failed to get release %s: %w", name, Classify(err))
+	}
+
+	releases, _, err := c.gh.Repositories.ListReleases(ctx, c.owner, c.repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", Classify(err))
+	}
+	for _, r := range releases {
+		if r.GetName() == name {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// buildReleaseRuns splits the runs of a tagged commit (newest first) into those for the tag
+// and the rest, and derives the verdict from the latest tag run of each workflow.
+func buildReleaseRuns(tag, sha string, runs []*github.WorkflowRun) *ReleaseRuns {
+	report := &ReleaseRuns{Tag: tag, SHA: sha, Runs: []*WorkflowRun{}}
+	latest := make(map[int64]bool)
+	for _, r := range runs {
+		run := workflowRunFromGitHub(r)
+		// Tag pushes, release events, and dispatches on the tag all report the tag as
+		// their head branch.
+		if run.Branch != tag && run.Event != "release" {
+			report.OtherRuns = append(report.OtherRuns, run)
+			continue
+		}
+		report.Runs = append(report.Runs, run)
+
+		if latest[run.WorkflowID] {
+			continue
+		}
+		latest[run.WorkflowID] = true
+		switch {
+		case run.Status != "completed":
+			report.Pending = append(report.Pending, run.Name)
+		case isFailureConclusion(run.Conclusion) || run.Conclusion == "cancelled":
+			report.Failed = append(report.Failed, run.Name)
+		}
+	}
+
+	switch {
+	case len(report.Runs) == 0:
+		report.Verdict = ReleaseVerdictNoRuns
+	case len(report.Failed) > 0:
+		report.Verdict = ReleaseVerdictFailure
+	case len(report.Pending) > 0:
+		report.Verdict = ReleaseVerdictInProgress
+	default:
+		report.Verdict = ReleaseVerdictSuccess
+	}
+	return report
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReleaseRuns(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"tag_name": "v1.2.3", "name": "Big Release", "html_url": "https://github.com/test-owner/test-repo/releases/tag/v1.2.3"}]`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/tags/v1.2.3", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("abc123"))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/tags/v9.9.9", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message": "No commit found for SHA: tags/v9.9.9"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc123", r.URL.Query().Get("head_sha"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_count": 4,
+			"workflow_runs": [
+				{"id": 5, "workflow_id": 2, "name": "Release", "event": "release", "head_branch": "v1.2.3", "status": "completed", "conclusion": "success"},
+				{"id": 4, "workflow_id": 1, "name": "CI", "event": "push", "head_branch": "v1.2.3", "status": "completed", "conclusion": "success"},
+				{"id": 3, "workflow_id": 2, "name": "Release", "event": "release", "head_branch": "v1.2.3", "status": "completed", "conclusion": "failure"},
+				{"id": 2, "workflow_id": 1, "name": "CI", "event": "push", "head_branch": "main", "status": "completed", "conclusion": "success"}
+			]
+		}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	report, err := client.GetReleaseRuns(context.Background(), "Big Release")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", report.Tag)
+	assert.Equal(t, "Big Release", report.Release)
+	assert.Equal(t, "abc123", report.SHA)
+	// The rerun of Release superseded its failure.
+	assert.Equal(t, ReleaseVerdictSuccess, report.Verdict)
+	assert.Empty(t, report.Failed)
+	require.Len(t, report.Runs, 3)
+	require.Len(t, report.OtherRuns, 1)
+	assert.Equal(t, int64(2), report.OtherRuns[0].ID)
+
+	_, err = client.GetReleaseRuns(context.Background(), "v9.9.9")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no release or tag named v9.9.9")
+}
+
+func TestBuildReleaseRuns_Verdicts(t *testing.T) {
+	run := func(id, workflowID int64, status, conclusion string) *githubapi.WorkflowRun {
+		return &githubapi.WorkflowRun{
+			ID: githubapi.Ptr(id), WorkflowID: githubapi.Ptr(workflowID), Name: githubapi.Ptr("wf"),
+			Event: githubapi.Ptr("push"), HeadBranch: githubapi.Ptr("v1"),
+			Status: githubapi.Ptr(status), Conclusion: githubapi.Ptr(conclusion),
+		}
+	}
+
+	assert.Equal(t, ReleaseVerdictNoRuns, buildReleaseRuns("v1", "sha", nil).Verdict)
+	assert.Equal(t, ReleaseVerdictInProgress, buildReleaseRuns("v1", "sha", []*githubapi.WorkflowRun{run(2, 1, "in_progress", ""), run(1, 2, "completed", "success")}).Verdict)
+	report := buildReleaseRuns("v1", "sha", []*githubapi.WorkflowRun{run(2, 1, "in_progress", ""), run(1, 2, "completed", "cancelled")})
+	assert.Equal(t, ReleaseVerdictFailure, report.Verdict)
+	assert.Equal(t, []string{"wf"}, report.Failed)
+}
@@ -5,10 +5,12 @@ import (
 	"net/url"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/sirupsen/logrus"
 )
@@ -24,109 +26,127 @@ func SetDetectorLogger(l *logrus.Logger) {
 	detectorLog = l
 }
 
+// UpstreamRemoteName is the conventional name of the remote pointing at the parent
+// repository in a fork checkout.
+const UpstreamRemoteName = "upstream"
+
+// DefaultRemotePreference is the order in which remotes are tried when none is chosen
+// explicitly. In a fork checkout the parent repository's workflows are usually the ones
+// of interest, so "upstream" wins over "origin".
+var DefaultRemotePreference = []string{UpstreamRemoteName, DefaultRemoteName}
+
 // RepoInfo contains information about a repository
 type RepoInfo struct {
-	Owner   string `json:"owner"`
-	Repo    string `json:"repo"`
-	Source  string `json:"source"`  // How the repo was detected (e.g., "config", "git_remote")
-	Cached  bool   `json:"cached"`  // Whether this was from cache
-	RawURL  string `json:"raw_url"` // Original URL if from git remote
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Source string `json:"source"`           // How the repo was detected (e.g., "config", "git_remote")
+	Cached bool   `json:"cached"`           // Whether this was from cache
+	RawURL string `json:"raw_url"`          // Original URL if from git remote
+	Remote string `json:"remote,omitempty"` // Name of the remote the repo was detected from
+	Host   string `json:"host,omitempty"`   // GitHub host of the remote, e.g. github.com
+	Root   string `json:"root,omitempty"`   // Top directory of the checkout
+	// Branch is the checked out branch; DefaultBranch is the remote's default branch as
+	// recorded by the local clone. Either is empty when unknown.
+	Branch        string `json:"branch,omitempty"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+	// Fork and Upstream are set when the "origin" and "upstream" remotes point to
+	// different repositories, i.e. the checkout is of a fork (Fork) of Upstream.
+	Fork     string       `json:"fork,omitempty"`
+	Upstream string       `json:"upstream,omitempty"`
+	Remotes  []RemoteRepo `json:"remotes,omitempty"`
+}
+
+// RemoteRepo is the repository a git remote points to.
+type RemoteRepo struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Host  string `json:"host,omitempty"`
+	Owner string `json:"owner,omitempty"`
+	Repo  string `json:"repo,omitempty"`
+	Error string `json:"error,omitempty"` // Why owner/repo could not be parsed from URL
+}
+
+// FullName returns "owner/repo", or "" if the remote is not a GitHub repository.
+func (r RemoteRepo) FullName() string {
+	if r.Owner == "" || r.Repo == "" {
+		return ""
+	}
+	return r.Owner + "/" + r.Repo
+}
+
+// RepoDetectorOptions configures which remote a RepoDetector reads.
+type RepoDetectorOptions struct {
+	// Remote, if set, is the only remote considered.
+	Remote string
+	// Preference is the order in which remotes are tried. Defaults to
+	// DefaultRemotePreference.
+	Preference []string
+	// Dir is the directory to detect from. Defaults to the working directory.
+	Dir string
+	// SearchUpward also searches the parent directories of Dir for the
+	// repository, so detection works from a subdirectory of a checkout.
+	SearchUpward bool
+	// HostPolicy decides which hosts remotes may point to. Defaults to
+	// github.com only.
+	HostPolicy HostPolicy
 }
 
 // RepoDetector handles repository detection with caching
 type RepoDetector struct {
-	mu    sync.RWMutex
-	cache *RepoInfo
+	mu           sync.RWMutex
+	cache        *RepoInfo
+	remote       string
+	preference   []string
+	dir          string
+	searchUpward bool
+	hostPolicy   HostPolicy
 }
 
-// NewRepoDetector creates a new repository detector
+// NewRepoDetector creates a new repository detector for the working directory or any of
+// its parents.
 func NewRepoDetector() *RepoDetector {
-	return &RepoDetector{}
+	return NewRepoDetectorWithOptions(RepoDetectorOptions{SearchUpward: true})
 }
 
-// ParseGitURL parses a git URL and extracts owner/repo
-// Supports SSH, HTTPS, git://, and bare formats. It also reverses git
-// url.<base>.insteadOf rewrites (e.g. gh-proxy) before parsing.
-func ParseGitURL(remoteURL string) (string, string, error) {
-	// Undo git insteadOf rewrites such as gh-proxy so that a proxied remote
-	// URL is translated back to its original github.com form.
-	if reversed, revErr := ReverseInsteadOf(remoteURL); revErr == nil {
-		remoteURL = reversed
-	}
-
-	// Validate URL - reject tokens
-	if containsToken(remoteURL) {
-		return "", "", fmt.Errorf("URL appears to contain a token (refusing for security)")
+// NewRepoDetectorWithOptions creates a repository detector that picks the remote
+// according to opts.
+func NewRepoDetectorWithOptions(opts RepoDetectorOptions) *RepoDetector {
+	preference := opts.Preference
+	if len(preference) == 0 {
+		preference = DefaultRemotePreference
 	}
-
-	// Handle bare owner/repo format (e.g., "owner/repo")
-	if !strings.Contains(remoteURL, "://") && !strings.Contains(remoteURL, "@") {
-		path := strings.TrimSuffix(remoteURL, ".git")
-		repoParts := strings.Split(path, "/")
-		if len(repoParts) == 2 {
-			return repoParts[0], repoParts[1], nil
-		}
+	return &RepoDetector{
+		remote:       strings.TrimSpace(opts.Remote),
+		preference:   preference,
+		dir:          opts.Dir,
+		searchUpward: opts.SearchUpward,
+		hostPolicy:   opts.HostPolicy,
 	}
+}
 
-	// Handle SSH format: git@github.com:owner/repo.git
-	// Also handles malformed URLs like git@github.com:/owner/repo.git (extra slash)
-	if strings.Contains(remoteURL, "git@") {
-		parts := strings.Split(remoteURL, ":")
-		if len(parts) > 1 {
-			path := strings.TrimSuffix(parts[1], ".git")
-			path = strings.TrimPrefix(path, "/") // Handle extra leading slash
-			repoParts := strings.Split(path, "/")
-			if len(repoParts) == 2 {
-				return repoParts[0], repoParts[1], nil
-			}
-		}
-	}
-
-	// Handle HTTPS format: https://github.com/owner/repo.git
-	if strings.HasPrefix(remoteURL, "https://") || strings.HasPrefix(remoteURL, "http://") {
-		u, err := url.Parse(remoteURL)
-		if err != nil {
-			return "", "", fmt.Errorf("failed to parse URL: %w", err)
-		}
-
-		// Validate it's a GitHub URL
-		if !isGitHubURL(u) {
-			return "", "", fmt.Errorf("not a GitHub URL: %s", remoteURL)
-		}
-
-		path := strings.TrimPrefix(u.Path, "/")
-		path = strings.TrimSuffix(path, ".git")
-		repoParts := strings.Split(path, "/")
-		if len(repoParts) >= 2 {
-			return repoParts[0], repoParts[1], nil
-		}
-
-		return "", "", fmt.Errorf("could not extract owner/repo from URL: %s", remoteURL)
+// openRepository opens the git repository at dir, or with searchUpward the nearest one
+// containing it. A .git file is followed, so this works inside submodules and linked
+// worktrees, and configuration such as remotes is read from the common git directory
+// that a linked worktree shares with the main checkout.
+func openRepository(dir string, searchUpward bool) (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{
+		DetectDotGit:          searchUpward,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
 	}
+	return repo, nil
+}
 
-	// Handle git:// protocol: git://github.com/owner/repo.git
-	if strings.HasPrefix(remoteURL, "git://") {
-		u, err := url.Parse(remoteURL)
-		if err != nil {
-			return "", "", fmt.Errorf("failed to parse git:// URL: %w", err)
-		}
-
-		// Validate it's a GitHub URL
-		if !isGitHubURL(u) {
-			return "", "", fmt.Errorf("not a GitHub URL: %s", remoteURL)
-		}
-
-		path := strings.TrimPrefix(u.Path, "/")
-		path = strings.TrimSuffix(path, ".git")
-		repoParts := strings.Split(path, "/")
-		if len(repoParts) >= 2 {
-			return repoParts[0], repoParts[1], nil
-		}
-
-		return "", "", fmt.Errorf("could not extract owner/repo from URL: %s", remoteURL)
+// ParseGitURL parses a git URL and extracts owner/repo using DefaultHostPolicy.
+// See ParseRepoURL for the supported formats.
+func ParseGitURL(remoteURL string) (string, string, error) {
+	parsed, err := ParseRepoURL(remoteURL, DefaultHostPolicy)
+	if err != nil {
+		return "", "", err
 	}
-
-	return "", "", fmt.Errorf("could not parse owner/repo from URL: %s", remoteURL)
+	return parsed.Owner, parsed.Repo, nil
 }
 
 // containsToken checks if a URL appears to contain a token
@@ -156,114 +176,180 @@ func containsToken(remoteURL string) bool {
 	return false
 }
 
-// isGitHubURL validates that a URL is from GitHub
-func isGitHubURL(u *url.URL) bool {
-	// Check hostname
-	host := strings.ToLower(u.Hostname())
-	return host == "github.com" || strings.HasSuffix(host, ".github.com")
+// resolveRemoteName determines the remote to use for repo detection. An explicitly chosen
+// remote always wins; otherwise the first existing remote in the preference order is used,
+// then the current branch's tracking remote, then the only remote if there is just one.
+func (d *RepoDetector) resolveRemoteName(repo *git.Repository, remotes []RemoteRepo) (string, error) {
+	exists := make(map[string]bool, len(remotes))
+	for _, r := range remotes {
+		exists[r.Name] = true
+	}
+
+	if d.remote != "" {
+		if !exists[d.remote] {
+			return "", fmt.Errorf("remote %q not found", d.remote)
+		}
+		return d.remote, nil
+	}
+
+	for _, name := range d.preference {
+		if exists[name] {
+			detectorLog.Debugf("Using preferred remote %q", name)
+			return name, nil
+		}
+	}
+
+	if tracking := trackingRemoteName(repo); tracking != "" && exists[tracking] {
+		detectorLog.Debugf("Using tracking remote %q", tracking)
+		return tracking, nil
+	}
+
+	if len(remotes) == 1 {
+		return remotes[0].Name, nil
+	}
+	if len(remotes) == 0 {
+		return "", fmt.Errorf("repository has no remotes")
+	}
+	return "", fmt.Errorf("none of the preferred remotes (%s) exist; choose one of the %d remotes explicitly", strings.Join(d.preference, ", "), len(remotes))
 }
 
-// resolveRemoteName determines the best remote to use for repo detection.
-// It checks the current branch's tracking remote first, then falls back to "origin".
-func resolveRemoteName(repo *git.Repository) string {
+// trackingRemoteName returns the remote the current branch tracks, or "" if HEAD is
+// detached or the branch has no upstream.
+func trackingRemoteName(repo *git.Repository) string {
 	head, err := repo.Head()
-	if err != nil {
-		detectorLog.Debugf("Could not get HEAD: %v, falling back to origin", err)
-		return DefaultRemoteName
+	if err != nil || !head.Name().IsBranch() {
+		return ""
 	}
 
-	if !head.Name().IsBranch() {
-		detectorLog.Debugf("HEAD is not a branch (detached HEAD?), falling back to origin")
-		return DefaultRemoteName
+	cfg, err := repo.Config()
+	if err != nil {
+		return ""
 	}
 
-	branchName := head.Name().Short()
+	branchCfg, ok := cfg.Branches[head.Name().Short()]
+	if !ok {
+		return ""
+	}
+	return branchCfg.Remote
+}
 
-	cfg, err := repo.Config()
+// listRemoteRepos returns every remote of repo, sorted by name, with the GitHub
+// repository it points to if policy allows its host.
+func listRemoteRepos(repo *git.Repository, policy HostPolicy) ([]RemoteRepo, error) {
+	remotes, err := repo.Remotes()
 	if err != nil {
-		detectorLog.Debugf("Could not read repo config: %v, falling back to origin", err)
-		return DefaultRemoteName
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
 	}
 
-	branchCfg, ok := cfg.Branches[branchName]
-	if !ok || branchCfg.Remote == "" {
-		detectorLog.Debugf("Branch %q has no tracking remote, falling back to origin", branchName)
-		return DefaultRemoteName
+	var result []RemoteRepo
+	for _, remote := range remotes {
+		cfg := remote.Config()
+		r := RemoteRepo{Name: cfg.Name}
+		if len(cfg.URLs) == 0 {
+			r.Error = "remote has no URLs"
+			result = append(result, r)
+			continue
+		}
+		parsed, err := ParseRepoURL(cfg.URLs[0], policy)
+		if err != nil {
+			r.Error = err.Error()
+		} else {
+			r.Host, r.Owner, r.Repo = parsed.Host, parsed.Owner, parsed.Repo
+		}
+		if r.URL = redactURLCredentials(cfg.URLs[0]); containsToken(r.URL) {
+			r.URL = ""
+		}
+		result = append(result, r)
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
 
-	detectorLog.Debugf("Branch %q tracks remote %q", branchName, branchCfg.Remote)
-	return branchCfg.Remote
+// forkRelationship reports the fork and its parent when the "origin" and "upstream"
+// remotes point to different repositories.
+func forkRelationship(remotes []RemoteRepo) (string, string) {
+	var origin, upstream string
+	for _, r := range remotes {
+		switch r.Name {
+		case DefaultRemoteName:
+			origin = r.FullName()
+		case UpstreamRemoteName:
+			upstream = r.FullName()
+		}
+	}
+	if origin == "" || upstream == "" || strings.EqualFold(origin, upstream) {
+		return "", ""
+	}
+	return origin, upstream
 }
 
 // Detect attempts to detect the repository from git remote.
-// It uses the current branch's tracking remote if available, otherwise falls back to "origin".
+// It picks the remote as described by resolveRemoteName and reports the other remotes
+// and any fork relationship between them.
 // Returns cached result if available, otherwise performs detection.
 func (d *RepoDetector) Detect() (*RepoInfo, error) {
 	// Check cache first
 	d.mu.RLock()
 	if d.cache != nil {
-		cached := d.cache
+		cached := *d.cache
 		d.mu.RUnlock()
 		if detectorLog != nil {
 			detectorLog.Debugf("Using cached repo info: %s/%s", cached.Owner, cached.Repo)
 		}
-		return &RepoInfo{
-			Owner:  cached.Owner,
-			Repo:   cached.Repo,
-			Source: cached.Source,
-			Cached: true,
-			RawURL: cached.RawURL,
-		}, nil
+		cached.Cached = true
+		return &cached, nil
 	}
 	d.mu.RUnlock()
 
 	// Perform detection
-	wd, err := getWorkingDir()
+	wd := d.dir
+	if wd == "" {
+		var err error
+		if wd, err = getWorkingDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	repo, err := openRepository(wd, d.searchUpward)
 	if err != nil {
 		return nil, err
 	}
 
-	repo, err := git.PlainOpen(wd)
+	remotes, err := listRemoteRepos(repo, d.hostPolicy)
 	if err != nil {
-		return nil, fmt.Errorf("not in a git repository: %w", err)
+		return nil, err
 	}
 
-	remoteName := resolveRemoteName(repo)
-
-	remote, err := repo.Remote(remoteName)
+	remoteName, err := d.resolveRemoteName(repo, remotes)
 	if err != nil {
-		// If the resolved remote doesn't exist and it's not origin, try origin as fallback
-		if remoteName != DefaultRemoteName {
-			detectorLog.Warnf("Remote %q not found, falling back to origin", remoteName)
-			remote, err = repo.Remote(DefaultRemoteName)
-			if err != nil {
-				return nil, fmt.Errorf("neither remote %q nor origin found: %w", remoteName, err)
-			}
-			remoteName = DefaultRemoteName
-		} else {
-			return nil, fmt.Errorf("remote %q not found: %w", remoteName, err)
-		}
+		return nil, err
 	}
 
-	if len(remote.Config().URLs) == 0 {
-		return nil, fmt.Errorf("remote %q has no URLs", remoteName)
+	var selected RemoteRepo
+	for _, r := range remotes {
+		if r.Name == remoteName {
+			selected = r
+		}
 	}
-
-	remoteURL := remote.Config().URLs[0]
-
-	// Parse the URL
-	owner, repoName, err := ParseGitURL(remoteURL)
-	if err != nil {
-		return nil, fmt.Errorf("could not parse owner/repo from remote %q: %w", remoteName, err)
+	if selected.Error != "" {
+		return nil, fmt.Errorf("could not parse owner/repo from remote %q: %s", remoteName, selected.Error)
 	}
 
-	source := fmt.Sprintf("git_remote(%s)", remoteName)
+	fork, upstream := forkRelationship(remotes)
 	info := &RepoInfo{
-		Owner:  owner,
-		Repo:   repoName,
-		Source: source,
-		Cached: false,
-		RawURL: remoteURL,
+		Owner:         selected.Owner,
+		Repo:          selected.Repo,
+		Source:        fmt.Sprintf("git_remote(%s)", remoteName),
+		Cached:        false,
+		RawURL:        selected.URL,
+		Remote:        remoteName,
+		Host:          selected.Host,
+		Root:          worktreeRoot(repo),
+		Branch:        currentBranch(repo),
+		DefaultBranch: remoteDefaultBranch(repo, remoteName),
+		Fork:          fork,
+		Upstream:      upstream,
+		Remotes:       remotes,
 	}
 
 	// Cache the result
@@ -272,7 +358,10 @@ func (d *RepoDetector) Detect() (*RepoInfo, error) {
 	d.mu.Unlock()
 
 	if detectorLog != nil {
-		detectorLog.Infof("Detected repo from remote %q: %s/%s", remoteName, owner, repoName)
+		detectorLog.Infof("Detected repo from remote %q: %s/%s", remoteName, info.Owner, info.Repo)
+		if fork != "" {
+			detectorLog.Infof("Checkout is a fork: %s is a fork of %s", fork, upstream)
+		}
 	}
 
 	return info, nil
@@ -297,9 +386,9 @@ func FindRemoteByName(remoteName string) (string, error) {
 		return "", fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	repo, err := git.PlainOpen(wd)
+	repo, err := openRepository(wd, true)
 	if err != nil {
-		return "", fmt.Errorf("not in a git repository: %w", err)
+		return "", err
 	}
 
 	remote, err := repo.Remote(remoteName)
@@ -321,47 +410,80 @@ func GetDefaultBranch(remoteName string) (string, error) {
 		return "", fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	repo, err := git.PlainOpen(wd)
+	repo, err := openRepository(wd, true)
 	if err != nil {
-		return "", fmt.Errorf("not in a git repository: %w", err)
+		return "", err
 	}
 
-	remote, err := repo.Remote(remoteName)
-	if err != nil {
+	if _, err := repo.Remote(remoteName); err != nil {
 		return "", fmt.Errorf("remote '%s' not found: %w", remoteName, err)
 	}
 
-	// Get remote config
-	cfg := remote.Config()
-	if cfg == nil {
-		return "", fmt.Errorf("remote '%s' has no config", remoteName)
-	}
-
-	// Try to get the fetch refspec to infer default branch
-	for _, fetch := range cfg.Fetch {
-		refSpec := fetch.String()
-		// Common pattern: +refs/heads/main:refs/remotes/origin/main
-		// or +refs/heads/master:refs/remotes/origin/master
-		if strings.Contains(refSpec, "refs/heads/") {
-			parts := strings.Split(refSpec, ":")
-			if len(parts) > 0 {
-				src := parts[0]
-				// Extract branch name from refs/heads/branch
-				if strings.HasPrefix(src, "refs/heads/") {
-					branch := strings.TrimPrefix(src, "refs/heads/")
-					// Handle wildcard refspecs
-					if !strings.Contains(branch, "*") {
-						return branch, nil
-					}
-				}
-			}
-		}
+	if branch := remoteDefaultBranch(repo, remoteName); branch != "" {
+		return branch, nil
 	}
 
 	// Default to "main" if we can't determine
 	return "main", nil
 }
 
+// remoteDefaultBranch returns the default branch of a remote as recorded locally: the
+// target of refs/remotes/<remote>/HEAD, which clone sets, or else the branch of a
+// single-branch fetch refspec. It returns "" if neither is known.
+func remoteDefaultBranch(repo *git.Repository, remoteName string) string {
+	if ref, err := repo.Reference(plumbing.NewRemoteHEADReferenceName(remoteName), false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		prefix := "refs/remotes/" + remoteName + "/"
+		if target := ref.Target().String(); strings.HasPrefix(target, prefix) {
+			return strings.TrimPrefix(target, prefix)
+		}
+	}
+
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return ""
+	}
+
+	// Common pattern: +refs/heads/main:refs/remotes/origin/main
+	// Wildcard refspecs say nothing about the default branch.
+	for _, fetch := range remote.Config().Fetch {
+		src := strings.TrimPrefix(strings.SplitN(fetch.String(), ":", 2)[0], "+")
+		if branch, ok := strings.CutPrefix(src, "refs/heads/"); ok && !strings.Contains(branch, "*") {
+			return branch
+		}
+	}
+	return ""
+}
+
+// worktreeRoot returns the top directory of repo's checkout, or "" for a bare repository.
+func worktreeRoot(repo *git.Repository) string {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return ""
+	}
+	return wt.Filesystem.Root()
+}
+
+// currentBranch returns the branch checked out in repo, or "" if HEAD is detached or
+// unborn.
+func currentBranch(repo *git.Repository) string {
+	head, err := repo.Head()
+	if err != nil || !head.Name().IsBranch() {
+		return ""
+	}
+	return head.Name().Short()
+}
+
+// redactURLCredentials removes any user information from a remote URL, such as the
+// credentials of a git proxy, so the URL can be shown.
+func redactURLCredentials(remoteURL string) string {
+	u, err := url.Parse(remoteURL)
+	if err != nil || u.User == nil {
+		return remoteURL
+	}
+	u.User = nil
+	return u.String()
+}
+
 // DetectRepoInfo is a convenience function that creates a detector and returns info
 func DetectRepoInfo() (*RepoInfo, error) {
 	detector := NewRepoDetector()
@@ -375,7 +497,7 @@ func IsGitRepository() bool {
 		return false
 	}
 
-	_, err = git.PlainOpen(wd)
+	_, err = openRepository(wd, true)
 	return err == nil
 }
 
@@ -386,7 +508,7 @@ func HasOriginRemote() bool {
 		return false
 	}
 
-	repo, err := git.PlainOpen(wd)
+	repo, err := openRepository(wd, true)
 	if err != nil {
 		return false
 	}
@@ -424,9 +546,9 @@ func SetRemoteURL(remoteName, newURL string) error {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	repo, err := git.PlainOpen(wd)
+	repo, err := openRepository(wd, true)
 	if err != nil {
-		return fmt.Errorf("not in a git repository: %w", err)
+		return err
 	}
 
 	// Validate the new URL
@@ -469,15 +591,8 @@ func IsValidGitURL(url string) bool {
 
 // IsGitHubURL checks if a URL is from GitHub
 func IsGitHubURL(remoteURL string) bool {
-	u, err := url.Parse(remoteURL)
-	if err != nil {
-		// Try parsing as SSH URL
-		if strings.Contains(remoteURL, "git@github.com:") {
-			return true
-		}
-		return false
-	}
-	return isGitHubURL(u)
+	parsed, err := ParseRepoURL(remoteURL, DefaultHostPolicy)
+	return err == nil && parsed.Host != ""
 }
 
 // GetRemoteName returns the default remote name
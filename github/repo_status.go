@@ -0,0 +1,139 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// defaultRepoStatusConcurrency bounds how many repositories are queried at once.
+const defaultRepoStatusConcurrency = 8
+
+// RepoWorkflowState is the latest run of one workflow in a repository.
+type RepoWorkflowState struct {
+	Workflow   string `json:"workflow"`
+	RunID      int64  `json:"run_id"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	HeadSHA    string `json:"head_sha,omitempty"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// RepoStatusSummary is one row of a multi-repository status table.
+type RepoStatusSummary struct {
+	Repository string               `json:"repository"`
+	Branch     string               `json:"branch,omitempty"`
+	State      string               `json:"state"` // passing, failing, running, no_runs, error
+	Failing    []string             `json:"failing,omitempty"`
+	Running    []string             `json:"running,omitempty"`
+	NotRun     []string             `json:"not_run,omitempty"` // latest run skipped, neutral, or stale
+	Workflows  []*RepoWorkflowState `json:"workflows,omitempty"`
+	Error      string               `json:"error,omitempty"`
+}
+
+// GetRepoStatusSummary reports the latest run of each workflow on a branch (the default
+// branch when empty) and an overall state for the repository.
+func (c *Client) GetRepoStatusSummary(ctx context.Context, branch string) (*RepoStatusSummary, error) {
+	summary := &RepoStatusSummary{Repository: c.FullName(), Branch: branch}
+
+	if summary.Branch == "" {
+		repository, _, err := c.gh.Repositories.Get(ctx, c.owner, c.repo)
+		if err != nil {
+			return nil, err
+		}
+		summary.Branch = repository.GetDefaultBranch()
+	}
+
+	runs, _, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, &github.ListWorkflowRunsOptions{
+		Branch:      summary.Branch,
+		ListOptions: github.ListOptions{PerPage: c.perPageLimit},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Runs are returned newest first, so the first run seen per workflow is the latest.
+	seen := make(map[int64]bool)
+	for _, run := range runs.WorkflowRuns {
+		if seen[run.GetWorkflowID()] {
+			continue
+		}
+		seen[run.GetWorkflowID()] = true
+
+		state := &RepoWorkflowState{
+			Workflow:   run.GetName(),
+			RunID:      run.GetID(),
+			Status:     run.GetStatus(),
+			Conclusion: run.GetConclusion(),
+			HeadSHA:    run.GetHeadSHA(),
+			UpdatedAt:  formatTime(run.UpdatedAt),
+			URL:        run.GetHTMLURL(),
+		}
+		summary.Workflows = append(summary.Workflows, state)
+
+		switch {
+		case state.Status != "completed":
+			summary.Running = append(summary.Running, state.Workflow)
+		case isFailureConclusion(state.Conclusion):
+			summary.Failing = append(summary.Failing, state.Workflow)
+		case isInconclusiveConclusion(state.Conclusion):
+			summary.NotRun = append(summary.NotRun, state.Workflow)
+		}
+	}
+	sort.Slice(summary.Workflows, func(i, j int) bool { return summary.Workflows[i].Workflow < summary.Workflows[j].Workflow })
+	sort.Strings(summary.Failing)
+	sort.Strings(summary.Running)
+	sort.Strings(summary.NotRun)
+
+	switch {
+	case len(summary.Workflows) == 0:
+		summary.State = "no_runs"
+	case len(summary.Failing) > 0:
+		summary.State = "failing"
+	case len(summary.Running) > 0:
+		summary.State = "running"
+	default:
+		summary.State = "passing"
+	}
+	return summary, nil
+}
+
+// CollectRepoStatuses queries several repositories concurrently and returns one summary per
+// client, in the same order. A repository that cannot be queried gets state "error" instead
+// of failing the whole table. concurrency <= 0 uses a default of 8.
+func CollectRepoStatuses(ctx context.Context, clients []*Client, branch string, concurrency int) []*RepoStatusSummary {
+	if concurrency <= 0 {
+		concurrency = defaultRepoStatusConcurrency
+	}
+
+	results := make([]*RepoStatusSummary, len(clients))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *Client) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			summary, err := client.GetRepoStatusSummary(ctx, branch)
+			if err != nil {
+				log.Debugf("Could not get status for %s/%s: %v", client.owner, client.repo, err)
+				summary = &RepoStatusSummary{
+					Repository: client.FullName(),
+					Branch:     branch,
+					State:      "error",
+					Error:      err.Error(),
+				}
+			}
+			results[i] = summary
+		}(i, client)
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectRepoStatuses(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/api", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1, "name": "api", "default_branch": "main"}`))
+	})
+	mux.HandleFunc("/repos/acme/api/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "main", r.URL.Query().Get("branch"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 3, "workflow_runs": [
+			{"id": 30, "name": "CI", "workflow_id": 1, "status": "completed", "conclusion": "failure"},
+			{"id": 29, "name": "Lint", "workflow_id": 2, "status": "in_progress"},
+			{"id": 28, "name": "CI", "workflow_id": 1, "status": "completed", "conclusion": "success"}
+		]}`))
+	})
+	mux.HandleFunc("/repos/acme/web/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "develop", r.URL.Query().Get("branch"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "workflow_runs": [
+			{"id": 11, "name": "Deploy", "workflow_id": 6, "status": "completed", "conclusion": "skipped"},
+			{"id": 10, "name": "CI", "workflow_id": 5, "status": "completed", "conclusion": "success"}
+		]}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	newClient := func(owner, repo string) *Client {
+		ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+		baseURL, err := url.Parse(ts.URL + "/")
+		require.NoError(t, err)
+		ghc.BaseURL = baseURL
+		return &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+	}
+
+	results := CollectRepoStatuses(context.Background(), []*Client{newClient("acme", "api"), newClient("acme", "missing")}, "", 2)
+	require.Len(t, results, 2)
+
+	api := results[0]
+	assert.Equal(t, "acme/api", api.Repository)
+	assert.Equal(t, "main", api.Branch)
+	assert.Equal(t, "failing", api.State)
+	assert.Equal(t, []string{"CI"}, api.Failing)
+	assert.Equal(t, []string{"Lint"}, api.Running)
+	require.Len(t, api.Workflows, 2)
+	assert.Equal(t, int64(30), api.Workflows[0].RunID)
+
+	assert.Equal(t, "error", results[1].State)
+	assert.NotEmpty(t, results[1].Error)
+
+	results = CollectRepoStatuses(context.Background(), []*Client{newClient("acme", "web")}, "develop", 0)
+	assert.Equal(t, "passing", results[0].State)
+	assert.Equal(t, []string{"Deploy"}, results[0].NotRun)
+}
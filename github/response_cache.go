@@ -0,0 +1,257 @@
+package github
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Cache sources reported in a CacheProvenance.
+const (
+	// CacheSourceLive means the data was downloaded from GitHub.
+	CacheSourceLive = "live"
+	// CacheSourceETag means GitHub answered 304 Not Modified to a conditional request, so a
+	// copy kept in memory was used. The data is current; GitHub does not count such
+	// requests against the rate limit.
+	CacheSourceETag = "etag"
+	// CacheSourceDisk means a job log was read from the disk cache without asking GitHub.
+	CacheSourceDisk = "disk"
+)
+
+const (
+	// maxCachedResponses bounds the responses the ETag cache keeps.
+	maxCachedResponses = 512
+	// maxCachedResponseSize is the largest response body the ETag cache keeps.
+	maxCachedResponseSize = 1 << 20
+)
+
+// responseCache keeps the ETag and body of API responses, shared by every client like
+// apiTransport.
+var responseCache = newETagCache(maxCachedResponses)
+
+// CacheProvenance says where the data of a tool call came from.
+type CacheProvenance struct {
+	// Cached is true when no data had to be downloaded from GitHub.
+	Cached bool `json:"cached"`
+	// AsOf is when the oldest cached copy used was downloaded. For etag data GitHub
+	// confirmed it is unchanged since then.
+	AsOf string `json:"as_of,omitempty"`
+	// Source is disk when any data came from the disk cache, etag when all of it was
+	// revalidated copies, and live otherwise.
+	Source string `json:"source"`
+	// Requests counts the responses by source.
+	Requests map[string]int `json:"requests"`
+}
+
+// CacheTrace records the source of every response fetched with a context from
+// WithCacheTrace.
+type CacheTrace struct {
+	parent *CacheTrace
+
+	mu     sync.Mutex
+	counts map[string]int
+	oldest time.Time
+}
+
+// cacheTraceKey is the context key of a CacheTrace.
+type cacheTraceKey struct{}
+
+// cacheBypassKey is the context key that marks requests that must not be served from a cache.
+type cacheBypassKey struct{}
+
+// WithCacheBypass returns a context whose requests skip the ETag and job log caches and
+// fetch everything from GitHub. The fresh responses still replace the cached ones.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// cacheBypassed reports whether ctx comes from WithCacheBypass.
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// WithCacheTrace returns a context whose API responses are recorded in the returned trace.
+// A trace nested in another also records into the outer one.
+func WithCacheTrace(ctx context.Context) (context.Context, *CacheTrace) {
+	parent, _ := ctx.Value(cacheTraceKey{}).(*CacheTrace)
+	trace := &CacheTrace{parent: parent, counts: make(map[string]int)}
+	return context.WithValue(ctx, cacheTraceKey{}, trace), trace
+}
+
+// recordCacheSource records a response from source in the trace of ctx, if any. asOf is when
+// a cached copy was downloaded, and zero for live data.
+func recordCacheSource(ctx context.Context, source string, asOf time.Time) {
+	trace, _ := ctx.Value(cacheTraceKey{}).(*CacheTrace)
+	for ; trace != nil; trace = trace.parent {
+		trace.mu.Lock()
+		trace.counts[source]++
+		if !asOf.IsZero() && (trace.oldest.IsZero() || asOf.Before(trace.oldest)) {
+			trace.oldest = asOf
+		}
+		trace.mu.Unlock()
+	}
+}
+
+// Provenance summarizes the responses recorded so far, or returns nil when there were none.
+func (t *CacheTrace) Provenance() *CacheProvenance {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.counts) == 0 {
+		return nil
+	}
+	p := &CacheProvenance{Requests: make(map[string]int, len(t.counts)), Source: CacheSourceLive}
+	for source, n := range t.counts {
+		p.Requests[source] = n
+	}
+	if t.counts[CacheSourceLive] == 0 {
+		p.Cached = true
+		p.Source = CacheSourceETag
+		if t.counts[CacheSourceDisk] > 0 {
+			p.Source = CacheSourceDisk
+		}
+	}
+	if !t.oldest.IsZero() {
+		p.AsOf = t.oldest.UTC().Format(time.RFC3339)
+	}
+	return p
+}
+
+// etagEntry is a cached response.
+type etagEntry struct {
+	key      string
+	etag     string
+	header   http.Header
+	body     []byte
+	storedAt time.Time
+}
+
+// etagCache is a least-recently-used cache of responses, keyed by token, URL and Accept
+// header so tokens with different access never share responses.
+type etagCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newETagCache(max int) *etagCache {
+	return &etagCache{max: max, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *etagCache) get(key string) *etagEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	apiMetrics.recordCacheLookup("etag", ok)
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*etagEntry)
+}
+
+func (c *etagCache) put(entry *etagEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[entry.key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*etagEntry).key)
+	}
+}
+
+// etagCacheKey returns the cache key of req.
+func etagCacheKey(req *http.Request) string {
+	token := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return hex.EncodeToString(token[:8]) + " " + req.Header.Get("Accept") + " " + req.URL.String()
+}
+
+// etagTransport sends GET requests for responses it has cached as conditional requests, and
+// answers a 304 Not Modified with the cached response, unless the request context comes
+// from WithCacheBypass. Every response is recorded in the request context's CacheTrace.
+type etagTransport struct {
+	base  http.RoundTripper
+	cache *etagCache
+}
+
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("Range") != "" {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil {
+			recordCacheSource(ctx, CacheSourceLive, time.Time{})
+		}
+		return resp, err
+	}
+
+	key := etagCacheKey(req)
+	var cached *etagEntry
+	if !cacheBypassed(ctx) {
+		cached = t.cache.get(key)
+	}
+	if cached != nil {
+		req = req.Clone(ctx)
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		recordCacheSource(ctx, CacheSourceETag, cached.storedAt)
+		// The 304 carries the current rate limit headers; the rest come from the cached response.
+		header := cached.header.Clone()
+		for name, values := range resp.Header {
+			header[name] = values
+		}
+		header.Del("Content-Length")
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(cached.body)),
+			ContentLength: int64(len(cached.body)),
+			Request:       resp.Request,
+		}, nil
+	}
+
+	recordCacheSource(ctx, CacheSourceLive, time.Time{})
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" || resp.ContentLength > maxCachedResponseSize {
+		return resp, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedResponseSize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedResponseSize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.cache.put(&etagEntry{key: key, etag: etag, header: resp.Header.Clone(), body: body, storedAt: time.Now()})
+	return resp, nil
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETagTransport(t *testing.T) {
+	var conditional []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/octo/hello/actions/workflows", func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"total_count": 1, "workflows": [{"id": 1, "name": "CI", "path": ".github/workflows/ci.yml", "state": "active"}]}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	hc := &http.Client{Transport: &etagTransport{base: ts.Client().Transport, cache: newETagCache(8)}}
+	ghc := githubapi.NewClient(hc).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: "octo", repo: "hello", gh: ghc, perPageLimit: 50}
+
+	ctx, trace := WithCacheTrace(context.Background())
+	workflows, err := client.GetWorkflows(ctx)
+	require.NoError(t, err)
+	require.Len(t, workflows, 1)
+	first := trace.Provenance()
+	require.NotNil(t, first)
+	assert.False(t, first.Cached)
+	assert.Equal(t, CacheSourceLive, first.Source)
+
+	ctx, trace = WithCacheTrace(context.Background())
+	workflows, err = client.GetWorkflows(ctx)
+	require.NoError(t, err)
+	require.Len(t, workflows, 1)
+	assert.Equal(t, "CI", workflows[0].Name)
+	second := trace.Provenance()
+	require.NotNil(t, second)
+	assert.True(t, second.Cached)
+	assert.Equal(t, CacheSourceETag, second.Source)
+	assert.NotEmpty(t, second.AsOf)
+	assert.Equal(t, map[string]int{CacheSourceETag: 1}, second.Requests)
+
+	// A bypassing request is sent unconditionally.
+	ctx, trace = WithCacheTrace(WithCacheBypass(context.Background()))
+	_, err = client.GetWorkflows(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, CacheSourceLive, trace.Provenance().Source)
+
+	assert.Equal(t, []string{"", `"v1"`, ""}, conditional)
+}
+
+func TestCacheTrace_Nested(t *testing.T) {
+	ctx, outer := WithCacheTrace(context.Background())
+	inner, trace := WithCacheTrace(ctx)
+	recordCacheSource(inner, CacheSourceLive, time.Time{})
+	recordCacheSource(ctx, CacheSourceETag, time.Now())
+
+	assert.Equal(t, map[string]int{CacheSourceLive: 1}, trace.Provenance().Requests)
+	p := outer.Provenance()
+	assert.False(t, p.Cached)
+	assert.Equal(t, CacheSourceLive, p.Source)
+	assert.Equal(t, map[string]int{CacheSourceLive: 1, CacheSourceETag: 1}, p.Requests)
+
+	_, empty := WithCacheTrace(context.Background())
+	assert.Nil(t, empty.Provenance())
+}
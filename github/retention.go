@@ -0,0 +1,135 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// maxRetentionDays is the largest retention period GitHub accepts for private repositories.
+const maxRetentionDays = 400
+
+// RetentionPolicy is the repository's artifact and log retention setting.
+type RetentionPolicy struct {
+	Days               int `json:"days"`
+	MaximumAllowedDays int `json:"maximum_allowed_days,omitempty"`
+}
+
+// ArtifactExpiry reports when a single artifact expires.
+type ArtifactExpiry struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	SizeInBytes   int64   `json:"size_in_bytes"`
+	CreatedAt     string  `json:"created_at,omitempty"`
+	ExpiresAt     string  `json:"expires_at,omitempty"`
+	Expired       bool    `json:"expired"`
+	DaysRemaining float64 `json:"days_remaining"`
+}
+
+// RunArtifactExpiry summarizes artifact expiry for a workflow run.
+type RunArtifactExpiry struct {
+	RunID            int64             `json:"run_id"`
+	TotalSizeInBytes int64             `json:"total_size_in_bytes"`
+	ExpiredCount     int               `json:"expired_count"`
+	NextExpiry       string            `json:"next_expiry,omitempty"`
+	Artifacts        []*ArtifactExpiry `json:"artifacts"`
+}
+
+// GetRetentionPolicy returns the artifact and log retention period for the repository.
+func (c *Client) GetRetentionPolicy(ctx context.Context) (*RetentionPolicy, error) {
+	u := fmt.Sprintf("repos/%s/%s/actions/permissions/artifact-and-log-retention", c.owner, c.repo)
+	req, err := c.gh.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retention policy: %w", err)
+	}
+
+	policy := &RetentionPolicy{}
+	if _, err := c.gh.Do(ctx, req, policy); err != nil {
+		return nil, fmt.Errorf("failed to get retention policy: %w", err)
+	}
+	return policy, nil
+}
+
+// SetRetentionPolicy updates the artifact and log retention period for the repository.
+func (c *Client) SetRetentionPolicy(ctx context.Context, days int) (*RetentionPolicy, error) {
+	if days < 1 || days > maxRetentionDays {
+		return nil, fmt.Errorf("retention days must be between 1 and %d, got %d", maxRetentionDays, days)
+	}
+
+	u := fmt.Sprintf("repos/%s/%s/actions/permissions/artifact-and-log-retention", c.owner, c.repo)
+	req, err := c.gh.NewRequest(http.MethodPut, u, &RetentionPolicy{Days: days})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set retention policy: %w", err)
+	}
+
+	if _, err := c.gh.Do(ctx, req, nil); err != nil {
+		return nil, fmt.Errorf("failed to set retention policy to %d days: %w", days, err)
+	}
+
+	return c.GetRetentionPolicy(ctx)
+}
+
+// GetRunArtifactExpiry reports when each artifact of a workflow run expires.
+func (c *Client) GetRunArtifactExpiry(ctx context.Context, runID int64) (*RunArtifactExpiry, error) {
+	opts := &github.ListOptions{PerPage: c.perPageLimit}
+	var artifacts []*github.Artifact
+	for {
+		arts, resp, err := c.gh.Actions.ListWorkflowRunArtifacts(ctx, c.owner, c.repo, runID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts for run %d: %w", runID, err)
+		}
+		artifacts = append(artifacts, arts.Artifacts...)
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return buildRunArtifactExpiry(runID, artifacts, time.Now()), nil
+}
+
+func buildRunArtifactExpiry(runID int64, artifacts []*github.Artifact, now time.Time) *RunArtifactExpiry {
+	result := &RunArtifactExpiry{
+		RunID:     runID,
+		Artifacts: make([]*ArtifactExpiry, 0, len(artifacts)),
+	}
+
+	var next time.Time
+	for _, art := range artifacts {
+		expiry := &ArtifactExpiry{
+			ID:          art.GetID(),
+			Name:        art.GetName(),
+			SizeInBytes: art.GetSizeInBytes(),
+			CreatedAt:   formatTimeValue(art.GetCreatedAt()),
+			ExpiresAt:   formatTimeValue(art.GetExpiresAt()),
+			Expired:     art.GetExpired(),
+		}
+
+		if art.ExpiresAt != nil {
+			remaining := art.GetExpiresAt().Sub(now)
+			if remaining <= 0 {
+				expiry.Expired = true
+			} else {
+				expiry.DaysRemaining = math.Round(remaining.Hours()/24*10) / 10
+				if next.IsZero() || art.GetExpiresAt().Before(next) {
+					next = art.GetExpiresAt().Time
+				}
+			}
+		}
+
+		if expiry.Expired {
+			result.ExpiredCount++
+		}
+		result.TotalSizeInBytes += expiry.SizeInBytes
+		result.Artifacts = append(result.Artifacts, expiry)
+	}
+
+	if !next.IsZero() {
+		result.NextExpiry = formatTimeValue(github.Timestamp{Time: next})
+	}
+	return result
+}
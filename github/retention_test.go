@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPolicy_GetAndSet(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	days := 90
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/permissions/artifact-and-log-retention", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]int{"days": days, "maximum_allowed_days": 400})
+		case http.MethodPut:
+			var body RetentionPolicy
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			days = body.Days
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	policy, err := client.GetRetentionPolicy(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 90, policy.Days)
+	assert.Equal(t, 400, policy.MaximumAllowedDays)
+
+	policy, err = client.SetRetentionPolicy(context.Background(), 30)
+	require.NoError(t, err)
+	assert.Equal(t, 30, policy.Days)
+
+	_, err = client.SetRetentionPolicy(context.Background(), 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "between 1 and 400")
+}
+
+func TestGetRunArtifactExpiry_Paginates(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/7/artifacts", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") != "2" {
+			w.Header().Set("Link", `<http://`+r.Host+r.URL.Path+`?page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`{"total_count": 3, "artifacts": [
+				{"id": 1, "name": "a", "size_in_bytes": 10, "expired": true},
+				{"id": 2, "name": "b", "size_in_bytes": 20, "expired": false}
+			]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"total_count": 3, "artifacts": [
+			{"id": 3, "name": "c", "size_in_bytes": 30, "expired": true}
+		]}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client())
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 2}
+
+	expiry, err := client.GetRunArtifactExpiry(context.Background(), 7)
+	require.NoError(t, err)
+	require.Len(t, expiry.Artifacts, 3)
+	assert.Equal(t, "c", expiry.Artifacts[2].Name)
+	assert.Equal(t, 2, expiry.ExpiredCount)
+	assert.Equal(t, int64(60), expiry.TotalSizeInBytes)
+}
+
+func TestBuildRunArtifactExpiry(t *testing.T) {
+	now := time.Date(2026, 4, 20, 12, 0, 0, 0, time.UTC)
+	ts := func(t time.Time) *githubapi.Timestamp { return &githubapi.Timestamp{Time: t} }
+
+	artifacts := []*githubapi.Artifact{
+		{
+			ID:          githubapi.Ptr(int64(1)),
+			Name:        githubapi.Ptr("coverage"),
+			SizeInBytes: githubapi.Ptr(int64(100)),
+			ExpiresAt:   ts(now.Add(72 * time.Hour)),
+		},
+		{
+			ID:          githubapi.Ptr(int64(2)),
+			Name:        githubapi.Ptr("binaries"),
+			SizeInBytes: githubapi.Ptr(int64(300)),
+			ExpiresAt:   ts(now.Add(36 * time.Hour)),
+		},
+		{
+			ID:          githubapi.Ptr(int64(3)),
+			Name:        githubapi.Ptr("old-logs"),
+			SizeInBytes: githubapi.Ptr(int64(50)),
+			ExpiresAt:   ts(now.Add(-time.Hour)),
+		},
+	}
+
+	result := buildRunArtifactExpiry(42, artifacts, now)
+
+	assert.Equal(t, int64(42), result.RunID)
+	assert.Equal(t, int64(450), result.TotalSizeInBytes)
+	assert.Equal(t, 1, result.ExpiredCount)
+	assert.Equal(t, now.Add(36*time.Hour).UTC().Format(time.RFC3339), result.NextExpiry)
+
+	require.Len(t, result.Artifacts, 3)
+	assert.InDelta(t, 3.0, result.Artifacts[0].DaysRemaining, 0.01)
+	assert.InDelta(t, 1.5, result.Artifacts[1].DaysRemaining, 0.01)
+	assert.True(t, result.Artifacts[2].Expired)
+	assert.Zero(t, result.Artifacts[2].DaysRemaining)
+}
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultCallGraphDepth bounds how deep reusable workflow calls are followed.
+// GitHub itself allows at most four levels of nesting.
+const defaultCallGraphDepth = 4
+
+// ReusableWorkflowCall is a job that calls a reusable workflow via `uses:`.
+type ReusableWorkflowCall struct {
+	Job   string `json:"job"`
+	Uses  string `json:"uses"`
+	Owner string `json:"owner,omitempty"`
+	Repo  string `json:"repo,omitempty"`
+	Path  string `json:"path"`
+	Ref   string `json:"ref,omitempty"`
+	Local bool   `json:"local"`
+}
+
+// WorkflowCallNode is a workflow in a reusable-workflow call graph.
+type WorkflowCallNode struct {
+	Repository string              `json:"repository"`
+	Path       string              `json:"path"`
+	Ref        string              `json:"ref,omitempty"`
+	CalledBy   string              `json:"called_by,omitempty"` // Job ID in the caller
+	Calls      []*WorkflowCallNode `json:"calls,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	Cycle      bool                `json:"cycle,omitempty"`
+
+	content []byte
+}
+
+// Content returns the workflow YAML fetched for the node, if any.
+func (n *WorkflowCallNode) Content() []byte {
+	return n.content
+}
+
+// Walk calls fn for the node and every workflow it calls, depth first.
+func (n *WorkflowCallNode) Walk(fn func(*WorkflowCallNode)) {
+	fn(n)
+	for _, child := range n.Calls {
+		child.Walk(fn)
+	}
+}
+
+// ParseReusableWorkflowCalls lists the jobs in workflow YAML that call reusable workflows.
+func ParseReusableWorkflowCalls(data []byte) ([]*ReusableWorkflowCall, error) {
+	var doc struct {
+		Jobs map[string]struct {
+			Uses string `yaml:"uses"`
+		} `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+
+	var calls []*ReusableWorkflowCall
+	for job, def := range doc.Jobs {
+		if def.Uses == "" {
+			continue
+		}
+		if call := parseReusableWorkflowRef(def.Uses); call != nil {
+			call.Job = job
+			calls = append(calls, call)
+		}
+	}
+	sort.Slice(calls, func(i, j int) bool { return calls[i].Job < calls[j].Job })
+	return calls, nil
+}
+
+// parseReusableWorkflowRef parses `./.github/workflows/x.yml` or
+// `owner/repo/.github/workflows/x.yml@ref`. It returns nil for anything else.
+func parseReusableWorkflowRef(uses string) *ReusableWorkflowCall {
+	uses = strings.TrimSpace(uses)
+	if strings.HasPrefix(uses, "./") {
+		return &ReusableWorkflowCall{Uses: uses, Path: strings.TrimPrefix(uses, "./"), Local: true}
+	}
+
+	target, ref, _ := strings.Cut(uses, "@")
+	parts := strings.SplitN(target, "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || !strings.HasPrefix(parts[2], ".github/workflows/") {
+		return nil
+	}
+	return &ReusableWorkflowCall{Uses: uses, Owner: parts[0], Repo: parts[1], Path: parts[2], Ref: ref}
+}
+
+// ResolveWorkflowCallGraph fetches a workflow and, recursively, every reusable workflow it
+// calls, including workflows in other repositories. Local calls are read from the caller's
+// repository at the caller's ref. Fetch failures are recorded on the node instead of failing
+// the whole graph. maxDepth <= 0 uses GitHub's nesting limit.
+func (c *Client) ResolveWorkflowCallGraph(ctx context.Context, path, ref string, maxDepth int) (*WorkflowCallNode, error) {
+	if maxDepth <= 0 {
+		maxDepth = defaultCallGraphDepth
+	}
+
+	root := &WorkflowCallNode{Repository: c.owner + "/" + c.repo, Path: path, Ref: ref}
+	data, err := c.getRepoFile(ctx, c.owner, c.repo, path, ref)
+	if err != nil {
+		return nil, err
+	}
+	root.content = data
+
+	c.resolveWorkflowCalls(ctx, root, maxDepth, map[string]bool{root.key(): true})
+	return root, nil
+}
+
+func (c *Client) resolveWorkflowCalls(ctx context.Context, node *WorkflowCallNode, depth int, visiting map[string]bool) {
+	calls, err := ParseReusableWorkflowCalls(node.content)
+	if err != nil {
+		node.Error = err.Error()
+		return
+	}
+
+	owner, repo, _ := strings.Cut(node.Repository, "/")
+	for _, call := range calls {
+		child := &WorkflowCallNode{CalledBy: call.Job, Path: call.Path}
+		if call.Local {
+			child.Repository = node.Repository
+			child.Ref = node.Ref
+		} else {
+			child.Repository = call.Owner + "/" + call.Repo
+			child.Ref = call.Ref
+		}
+		node.Calls = append(node.Calls, child)
+
+		if visiting[child.key()] {
+			child.Cycle = true
+			continue
+		}
+		if depth <= 0 {
+			child.Error = "maximum call depth reached"
+			continue
+		}
+
+		childOwner, childRepo := owner, repo
+		if !call.Local {
+			childOwner, childRepo = call.Owner, call.Repo
+		}
+		data, err := c.getRepoFile(ctx, childOwner, childRepo, child.Path, child.Ref)
+		if err != nil {
+			child.Error = err.Error()
+			continue
+		}
+		child.content = data
+
+		visiting[child.key()] = true
+		c.resolveWorkflowCalls(ctx, child, depth-1, visiting)
+		delete(visiting, child.key())
+	}
+}
+
+func (n *WorkflowCallNode) key() string {
+	return strings.ToLower(n.Repository) + ":" + n.Path + "@" + n.Ref
+}
@@ -0,0 +1,127 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReusableWorkflowCalls(t *testing.T) {
+	calls, err := ParseReusableWorkflowCalls([]byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+  deploy:
+    uses: acme/shared/.github/workflows/deploy.yml@v2
+  lint:
+    uses: ./.github/workflows/lint.yml
+  action:
+    uses: actions/checkout@v4
+`))
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+
+	assert.Equal(t, "deploy", calls[0].Job)
+	assert.Equal(t, "acme", calls[0].Owner)
+	assert.Equal(t, "shared", calls[0].Repo)
+	assert.Equal(t, ".github/workflows/deploy.yml", calls[0].Path)
+	assert.Equal(t, "v2", calls[0].Ref)
+	assert.False(t, calls[0].Local)
+
+	assert.Equal(t, "lint", calls[1].Job)
+	assert.True(t, calls[1].Local)
+	assert.Equal(t, ".github/workflows/lint.yml", calls[1].Path)
+}
+
+func TestResolveWorkflowCallGraph(t *testing.T) {
+	files := map[string]string{
+		"/repos/test-owner/test-repo/contents/.github/workflows/ci.yml": `
+on: push
+jobs:
+  lint:
+    uses: ./.github/workflows/lint.yml
+  deploy:
+    uses: acme/shared/.github/workflows/deploy.yml@v2
+  missing:
+    uses: acme/private/.github/workflows/secret.yml@main
+`,
+		"/repos/test-owner/test-repo/contents/.github/workflows/lint.yml": `
+on: workflow_call
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make lint
+`,
+		"/repos/acme/shared/contents/.github/workflows/deploy.yml": `
+on: workflow_call
+jobs:
+  again:
+    uses: acme/shared/.github/workflows/deploy.yml@v2
+`,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Path == "/repos/test-owner/test-repo/contents/.github/workflows/lint.yml" {
+			assert.Equal(t, "feature", r.URL.Query().Get("ref"), "local calls use the caller's ref")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: "test-owner", repo: "test-repo", gh: ghc, perPageLimit: 50}
+
+	graph, err := client.ResolveWorkflowCallGraph(context.Background(), ".github/workflows/ci.yml", "feature", 0)
+	require.NoError(t, err)
+
+	require.Len(t, graph.Calls, 3)
+
+	deploy := graph.Calls[0]
+	assert.Equal(t, "deploy", deploy.CalledBy)
+	assert.Equal(t, "acme/shared", deploy.Repository)
+	assert.Equal(t, "v2", deploy.Ref)
+	require.Len(t, deploy.Calls, 1)
+	assert.True(t, deploy.Calls[0].Cycle)
+
+	lint := graph.Calls[1]
+	assert.Equal(t, "test-owner/test-repo", lint.Repository)
+	assert.Equal(t, "feature", lint.Ref)
+	assert.Empty(t, lint.Error)
+	assert.Contains(t, string(lint.Content()), "make lint")
+
+	missing := graph.Calls[2]
+	assert.Contains(t, missing.Error, "acme/private")
+
+	var visited int
+	graph.Walk(func(*WorkflowCallNode) { visited++ })
+	assert.Equal(t, 5, visited)
+}
@@ -0,0 +1,243 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// RulesetSummary describes a ruleset that applies to the repository.
+type RulesetSummary struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	SourceType  string `json:"source_type"`
+	Source      string `json:"source"`
+	Enforcement string `json:"enforcement"`
+	Target      string `json:"target,omitempty"`
+}
+
+// RequiredWorkflowStatus reports whether a workflow required by a ruleset ran successfully.
+type RequiredWorkflowStatus struct {
+	Path          string `json:"path"`
+	Ref           string `json:"ref,omitempty"`
+	RepositoryID  int64  `json:"repository_id,omitempty"`
+	RulesetID     int64  `json:"ruleset_id"`
+	RulesetSource string `json:"ruleset_source"`
+	SourceType    string `json:"source_type"`
+	Status        string `json:"status"`
+	RunID         int64  `json:"run_id,omitempty"`
+	Conclusion    string `json:"conclusion,omitempty"`
+}
+
+// RequiredCheckStatus reports whether a status check required by a ruleset passed.
+type RequiredCheckStatus struct {
+	Context       string `json:"context"`
+	RulesetID     int64  `json:"ruleset_id"`
+	RulesetSource string `json:"ruleset_source"`
+	SourceType    string `json:"source_type"`
+	Status        string `json:"status"`
+	Conclusion    string `json:"conclusion,omitempty"`
+}
+
+// BranchPolicyReport lists the rulesets and required workflows/checks that apply to a branch
+// and whether the latest runs satisfy them.
+type BranchPolicyReport struct {
+	Branch            string                    `json:"branch"`
+	HeadSHA           string                    `json:"head_sha,omitempty"`
+	Rulesets          []*RulesetSummary         `json:"rulesets"`
+	RequiredWorkflows []*RequiredWorkflowStatus `json:"required_workflows,omitempty"`
+	RequiredChecks    []*RequiredCheckStatus    `json:"required_checks,omitempty"`
+	Blocking          []string                  `json:"blocking,omitempty"`
+	Notes             []string                  `json:"notes,omitempty"`
+	Summary           string                    `json:"summary"`
+}
+
+// GetBranchPolicies reports repository, organization, and enterprise rulesets that apply
+// to a branch, with the required workflows and status checks they enforce and whether
+// the most recent runs on the branch satisfy them. An empty branch means the default branch.
+func (c *Client) GetBranchPolicies(ctx context.Context, branch string) (*BranchPolicyReport, error) {
+	if branch == "" {
+		repository, _, err := c.gh.Repositories.Get(ctx, c.owner, c.repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get default branch: %w", err)
+		}
+		branch = repository.GetDefaultBranch()
+	}
+
+	report := &BranchPolicyReport{Branch: branch}
+
+	rulesets, _, err := c.gh.Repositories.GetAllRulesets(ctx, c.owner, c.repo, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rulesets: %w", err)
+	}
+	report.Rulesets = make([]*RulesetSummary, 0, len(rulesets))
+	for _, rs := range rulesets {
+		summary := &RulesetSummary{
+			ID:          rs.GetID(),
+			Name:        rs.Name,
+			Source:      rs.Source,
+			Enforcement: string(rs.Enforcement),
+		}
+		if rs.SourceType != nil {
+			summary.SourceType = string(*rs.SourceType)
+		}
+		if rs.Target != nil {
+			summary.Target = string(*rs.Target)
+		}
+		report.Rulesets = append(report.Rulesets, summary)
+	}
+
+	rules, _, err := c.gh.Repositories.GetRulesForBranch(ctx, c.owner, c.repo, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules for branch %s: %w", branch, err)
+	}
+
+	runs, _, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		ListOptions: github.ListOptions{PerPage: c.perPageLimit},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs for branch %s: %w", branch, err)
+	}
+	if len(runs.WorkflowRuns) > 0 {
+		report.HeadSHA = runs.WorkflowRuns[0].GetHeadSHA()
+	}
+
+	if rules != nil {
+		for _, rule := range rules.Workflows {
+			for _, wf := range rule.Parameters.Workflows {
+				status := requiredWorkflowStatus(wf, rule.BranchRuleMetadata, runs.WorkflowRuns, report.HeadSHA)
+				report.RequiredWorkflows = append(report.RequiredWorkflows, status)
+			}
+		}
+
+		var checkRuns []*github.CheckRun
+		checksLoaded := false
+		for _, rule := range rules.RequiredStatusChecks {
+			if !checksLoaded && len(rule.Parameters.RequiredStatusChecks) > 0 {
+				checksLoaded = true
+				ref := report.HeadSHA
+				if ref == "" {
+					ref = branch
+				}
+				result, _, err := c.gh.Checks.ListCheckRunsForRef(ctx, c.owner, c.repo, ref, &github.ListCheckRunsOptions{
+					ListOptions: github.ListOptions{PerPage: 100},
+				})
+				if err != nil {
+					log.Debugf("Could not list check runs for %s: %v", ref, err)
+					report.Notes = append(report.Notes, "Required status checks could not be evaluated: the token cannot read check runs (needs Checks: Read).")
+				} else {
+					checkRuns = result.CheckRuns
+				}
+			}
+			for _, check := range rule.Parameters.RequiredStatusChecks {
+				report.RequiredChecks = append(report.RequiredChecks, requiredCheckStatus(check, rule.BranchRuleMetadata, checkRuns))
+			}
+		}
+	}
+
+	for _, wf := range report.RequiredWorkflows {
+		if wf.Status != "satisfied" {
+			report.Blocking = append(report.Blocking, fmt.Sprintf("required workflow %s (%s ruleset %q) is %s", wf.Path, strings.ToLower(wf.SourceType), wf.RulesetSource, wf.Status))
+		}
+	}
+	for _, check := range report.RequiredChecks {
+		if check.Status != "satisfied" && check.Status != "unknown" {
+			report.Blocking = append(report.Blocking, fmt.Sprintf("required check %q (%s ruleset %q) is %s", check.Context, strings.ToLower(check.SourceType), check.RulesetSource, check.Status))
+		}
+	}
+
+	report.Summary = buildBranchPolicySummary(report)
+	return report, nil
+}
+
+func requiredWorkflowStatus(wf *github.RuleWorkflow, meta github.BranchRuleMetadata, runs []*github.WorkflowRun, headSHA string) *RequiredWorkflowStatus {
+	status := &RequiredWorkflowStatus{
+		Path:          wf.Path,
+		Ref:           wf.GetRef(),
+		RepositoryID:  wf.GetRepositoryID(),
+		RulesetID:     meta.RulesetID,
+		RulesetSource: meta.RulesetSource,
+		SourceType:    string(meta.RulesetSourceType),
+		Status:        "missing",
+	}
+
+	for _, run := range runs {
+		runPath := run.GetPath()
+		if src := workflowSource(run.GetRepository().GetFullName(), runPath, run.GetWorkflowURL()); src != nil {
+			runPath = src.Path
+		}
+		if runPath != wf.Path {
+			continue
+		}
+		if headSHA != "" && run.GetHeadSHA() != headSHA {
+			continue
+		}
+		status.RunID = run.GetID()
+		status.Conclusion = run.GetConclusion()
+		switch {
+		case run.GetStatus() != "completed":
+			status.Status = "pending"
+		case run.GetConclusion() == "success":
+			status.Status = "satisfied"
+		default:
+			status.Status = "failing"
+		}
+		break
+	}
+
+	return status
+}
+
+func requiredCheckStatus(check *github.RuleStatusCheck, meta github.BranchRuleMetadata, checkRuns []*github.CheckRun) *RequiredCheckStatus {
+	status := &RequiredCheckStatus{
+		Context:       check.Context,
+		RulesetID:     meta.RulesetID,
+		RulesetSource: meta.RulesetSource,
+		SourceType:    string(meta.RulesetSourceType),
+		Status:        "unknown",
+	}
+	if checkRuns == nil {
+		return status
+	}
+
+	status.Status = "missing"
+	for _, cr := range checkRuns {
+		if cr.GetName() != check.Context {
+			continue
+		}
+		status.Conclusion = cr.GetConclusion()
+		switch {
+		case cr.GetStatus() != "completed":
+			status.Status = "pending"
+		case cr.GetConclusion() == "success" || cr.GetConclusion() == "neutral" || cr.GetConclusion() == "skipped":
+			status.Status = "satisfied"
+		default:
+			status.Status = "failing"
+		}
+		break
+	}
+
+	return status
+}
+
+func buildBranchPolicySummary(r *BranchPolicyReport) string {
+	orgLevel := 0
+	for _, rs := range r.Rulesets {
+		if rs.SourceType != string(github.RulesetSourceTypeRepository) {
+			orgLevel++
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d ruleset(s) apply to the repository (%d from organization/enterprise level). ", len(r.Rulesets), orgLevel))
+	sb.WriteString(fmt.Sprintf("Branch %s requires %d workflow(s) and %d status check(s).", r.Branch, len(r.RequiredWorkflows), len(r.RequiredChecks)))
+	if len(r.Blocking) > 0 {
+		sb.WriteString(fmt.Sprintf(" %d requirement(s) currently block merging.", len(r.Blocking)))
+	} else {
+		sb.WriteString(" No blocking requirements detected.")
+	}
+	return sb.String()
+}
@@ -0,0 +1,95 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBranchPolicies(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1, "name": "test-repo", "default_branch": "main"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/rulesets", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("includes_parents"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": 5, "name": "org security", "source_type": "Organization", "source": "test-owner", "enforcement": "active", "target": "branch"},
+			{"id": 6, "name": "repo protection", "source_type": "Repository", "source": "test-owner/test-repo", "enforcement": "evaluate", "target": "branch"}
+		]`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/rules/branches/main", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"type": "workflows", "ruleset_source_type": "Organization", "ruleset_source": "test-owner", "ruleset_id": 5,
+			 "parameters": {"workflows": [
+				{"path": ".github/workflows/security.yml", "repository_id": 99, "ref": "main"},
+				{"path": ".github/workflows/license.yml", "repository_id": 99}
+			 ]}},
+			{"type": "required_status_checks", "ruleset_source_type": "Repository", "ruleset_source": "test-owner/test-repo", "ruleset_id": 6,
+			 "parameters": {"required_status_checks": [{"context": "build"}, {"context": "lint"}], "strict_required_status_checks_policy": false}}
+		]`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "main", r.URL.Query().Get("branch"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_count": 2,
+			"workflow_runs": [
+				{"id": 10, "name": "Security", "status": "completed", "conclusion": "failure", "head_sha": "abc", "path": ".github/workflows/security.yml@main"},
+				{"id": 11, "name": "CI", "status": "completed", "conclusion": "success", "head_sha": "abc", "path": ".github/workflows/ci.yml"}
+			]
+		}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/abc/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_count": 1,
+			"check_runs": [{"id": 1, "name": "build", "status": "completed", "conclusion": "success"}]
+		}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	report, err := client.GetBranchPolicies(context.Background(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "main", report.Branch)
+	assert.Equal(t, "abc", report.HeadSHA)
+	require.Len(t, report.Rulesets, 2)
+	assert.Equal(t, "Organization", report.Rulesets[0].SourceType)
+
+	require.Len(t, report.RequiredWorkflows, 2)
+	assert.Equal(t, "failing", report.RequiredWorkflows[0].Status)
+	assert.Equal(t, int64(10), report.RequiredWorkflows[0].RunID)
+	assert.Equal(t, "missing", report.RequiredWorkflows[1].Status)
+
+	require.Len(t, report.RequiredChecks, 2)
+	assert.Equal(t, "satisfied", report.RequiredChecks[0].Status)
+	assert.Equal(t, "missing", report.RequiredChecks[1].Status)
+
+	assert.Len(t, report.Blocking, 3)
+	assert.Contains(t, report.Blocking[0], "organization ruleset")
+	assert.Contains(t, report.Summary, "1 from organization/enterprise level")
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Annotation levels, from the most to the least severe.
+var annotationLevels = []string{"failure", "warning", "notice"}
+
+// RunAnnotations are the check-run annotations of a workflow run's check suite.
+type RunAnnotations struct {
+	RunID        int64          `json:"run_id"`
+	CheckSuiteID int64          `json:"check_suite_id"`
+	Counts       map[string]int `json:"counts"` // Annotations per level, before the level filter
+	Annotations  []*Annotation  `json:"annotations"`
+	Warnings     []string       `json:"warnings,omitempty"`
+}
+
+// GetRunAnnotations lists the annotations of every check run in the check suite of a
+// workflow run: those of its jobs, such as compiler and test errors reported through
+// workflow commands, and those of any other check run in the suite. A non-empty level
+// (failure, warning, or notice) keeps only annotations of that level. Annotations are
+// ordered by severity, then by job, file, and line.
+func (c *Client) GetRunAnnotations(ctx context.Context, runID int64, level string) (*RunAnnotations, error) {
+	if level != "" && !slices.Contains(annotationLevels, level) {
+		return nil, fmt.Errorf("invalid level %q: must be failure, warning, or notice", level)
+	}
+	run, _, err := c.gh.Actions.GetWorkflowRunByID(ctx, c.owner, c.repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run %d: %w", runID, Classify(err))
+	}
+	result := &RunAnnotations{RunID: runID, CheckSuiteID: run.GetCheckSuiteID(), Counts: map[string]int{}, Annotations: []*Annotation{}}
+	if result.CheckSuiteID == 0 {
+		return nil, fmt.Errorf("run %d has no check suite", runID)
+	}
+
+	opts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := c.gh.Checks.ListCheckRunsCheckSuite(ctx, c.owner, c.repo, result.CheckSuiteID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list check runs of check suite %d: %w", result.CheckSuiteID, Classify(err))
+		}
+		for _, checkRun := range page.CheckRuns {
+			if checkRun.GetOutput().GetAnnotationsCount() == 0 {
+				continue
+			}
+			annotations, err := c.listJobAnnotations(ctx, &Job{ID: checkRun.GetID(), Name: checkRun.GetName()})
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("could not get annotations of %s: %v", checkRun.GetName(), err))
+			}
+			for _, a := range annotations {
+				result.Counts[a.Level]++
+				if level == "" || a.Level == level {
+					result.Annotations = append(result.Annotations, a)
+				}
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	severity := func(level string) int {
+		if i := slices.Index(annotationLevels, level); i >= 0 {
+			return i
+		}
+		return len(annotationLevels)
+	}
+	sort.SliceStable(result.Annotations, func(i, j int) bool {
+		a, b := result.Annotations[i], result.Annotations[j]
+		if severity(a.Level) != severity(b.Level) {
+			return severity(a.Level) < severity(b.Level)
+		}
+		if a.Job != b.Job {
+			return a.Job < b.Job
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.StartLine < b.StartLine
+	})
+	return result, nil
+}
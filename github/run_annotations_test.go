@@ -0,0 +1,76 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRunAnnotations(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/42", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 42, "check_suite_id": 900}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/check-suites/900/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 3, "check_runs": [
+			{"id": 101, "name": "test", "output": {"annotations_count": 2}},
+			{"id": 102, "name": "lint", "output": {"annotations_count": 1}},
+			{"id": 103, "name": "build", "output": {"annotations_count": 0}}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/check-runs/101/annotations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"path": ".github", "start_line": 1, "annotation_level": "failure", "message": "Process completed with exit code 1."},
+			{"path": "parse_test.go", "start_line": 12, "end_line": 12, "annotation_level": "failure", "title": "TestParse", "message": "expected 2, got 3"}
+		]`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/check-runs/102/annotations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"path": "main.go", "start_line": 3, "annotation_level": "warning", "message": "unused variable x"}]`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	result, err := client.GetRunAnnotations(context.Background(), 42, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(900), result.CheckSuiteID)
+	assert.Equal(t, map[string]int{"failure": 2, "warning": 1}, result.Counts)
+	require.Len(t, result.Annotations, 3)
+	assert.Equal(t, &Annotation{JobID: 101, Job: "test", Path: "parse_test.go", StartLine: 12, EndLine: 12, Level: "failure", Title: "TestParse", Message: "expected 2, got 3"}, result.Annotations[1])
+	assert.Equal(t, "warning", result.Annotations[2].Level)
+	assert.Equal(t, "lint", result.Annotations[2].Job)
+
+	result, err = client.GetRunAnnotations(context.Background(), 42, "warning")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"failure": 2, "warning": 1}, result.Counts)
+	require.Len(t, result.Annotations, 1)
+	assert.Equal(t, "unused variable x", result.Annotations[0].Message)
+
+	_, err = client.GetRunAnnotations(context.Background(), 42, "error")
+	assert.ErrorContains(t, err, "invalid level")
+}
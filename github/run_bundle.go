@@ -0,0 +1,348 @@
+package github
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Annotation is a check run annotation of a job, such as an ##[error] or a problem matcher hit.
+type Annotation struct {
+	JobID     int64  `json:"job_id"`
+	Job       string `json:"job"`
+	Path      string `json:"path,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Level     string `json:"level"` // notice, warning, or failure
+	Title     string `json:"title,omitempty"`
+	Message   string `json:"message"`
+}
+
+// FailedJobLog holds the log lines of a failed job's failed steps.
+type FailedJobLog struct {
+	JobID       int64    `json:"job_id"`
+	Job         string   `json:"job"`
+	FailedSteps []string `json:"failed_steps,omitempty"`
+	Log         string   `json:"log"`
+	Truncated   bool     `json:"truncated,omitempty"` // Earlier lines were dropped to stay within the line limit
+}
+
+// RunBundle is the CI context of a run, collected to attach to a bug report.
+type RunBundle struct {
+	Run          *WorkflowRun    `json:"run"`
+	Jobs         []*Job          `json:"jobs"`
+	Annotations  []*Annotation   `json:"annotations,omitempty"`
+	FailedLogs   []*FailedJobLog `json:"failed_logs,omitempty"`
+	WorkflowPath string          `json:"workflow_path,omitempty"`
+	WorkflowYAML string          `json:"workflow_yaml,omitempty"`
+	Warnings     []string        `json:"warnings,omitempty"`
+
+	location *time.Location // Time zone of the markdown report
+}
+
+// GetRunBundle collects a run's metadata, jobs, annotations, the logs of its failed steps
+// (the last maxLogLines lines per job, default 200), and the workflow file at the run's
+// commit. Parts that cannot be fetched are reported as warnings instead of failing the bundle.
+func (c *Client) GetRunBundle(ctx context.Context, runID int64, maxLogLines int) (*RunBundle, error) {
+	if maxLogLines <= 0 {
+		maxLogLines = 200
+	}
+
+	run, err := c.GetWorkflowRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run %d: %w", runID, err)
+	}
+	jobs, err := c.GetWorkflowJobs(ctx, runID, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs for run %d: %w", runID, err)
+	}
+	bundle := &RunBundle{Run: run, Jobs: jobs, location: c.location}
+
+	for _, job := range jobs {
+		annotations, err := c.listJobAnnotations(ctx, job)
+		if err != nil {
+			bundle.Warnings = append(bundle.Warnings, fmt.Sprintf("could not get annotations of job %s: %v", job.Name, err))
+		}
+		bundle.Annotations = append(bundle.Annotations, annotations...)
+
+		if job.Conclusion != "failure" && job.Conclusion != "cancelled" && job.Conclusion != "timed_out" {
+			continue
+		}
+		jobLog, err := c.failedJobLog(ctx, runID, job, maxLogLines)
+		if err != nil {
+			bundle.Warnings = append(bundle.Warnings, fmt.Sprintf("could not get logs of job %s: %v", job.Name, err))
+			continue
+		}
+		bundle.FailedLogs = append(bundle.FailedLogs, jobLog)
+	}
+
+	path, content, err := c.runWorkflowFile(ctx, run)
+	bundle.WorkflowPath = path
+	switch {
+	case err != nil && path == "":
+		bundle.Warnings = append(bundle.Warnings, fmt.Sprintf("could not get workflow %d: %v", run.WorkflowID, err))
+	case err != nil:
+		bundle.Warnings = append(bundle.Warnings, fmt.Sprintf("could not get workflow file %s: %v", path, err))
+	default:
+		bundle.WorkflowYAML = string(content)
+	}
+
+	return bundle, nil
+}
+
+// listJobAnnotations returns the annotations of a job. A job's ID is also the ID of its
+// check run.
+func (c *Client) listJobAnnotations(ctx context.Context, job *Job) ([]*Annotation, error) {
+	var result []*Annotation
+	opts := &github.ListOptions{PerPage: 50}
+	for {
+		annotations, resp, err := c.gh.Checks.ListCheckRunAnnotations(ctx, c.owner, c.repo, job.ID, opts)
+		if err != nil {
+			return result, Classify(err)
+		}
+		for _, a := range annotations {
+			result = append(result, &Annotation{
+				JobID:     job.ID,
+				Job:       job.Name,
+				Path:      a.GetPath(),
+				StartLine: a.GetStartLine(),
+				EndLine:   a.GetEndLine(),
+				Level:     a.GetAnnotationLevel(),
+				Title:     a.GetTitle(),
+				Message:   a.GetMessage(),
+			})
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return result, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// failedJobLog returns the log lines written while the job's failed steps ran. When the
+// steps have no timings or no line falls within them, the end of the job's log is used.
+func (c *Client) failedJobLog(ctx context.Context, runID int64, job *Job, maxLines int) (*FailedJobLog, error) {
+	logs, err := c.GetWorkflowJobLogs(ctx, job.ID, 0, 0, 0, true, nil)
+	if err != nil {
+		archiveLogs, archiveErr := c.GetWorkflowJobLogsFromRunArchive(ctx, runID, job.ID, 0, 0, 0, true, nil)
+		if archiveErr != nil {
+			return nil, fmt.Errorf("%w; archive fallback failed: %v", err, archiveErr)
+		}
+		logs = archiveLogs
+	}
+
+	result := &FailedJobLog{JobID: job.ID, Job: job.Name}
+	var spans [][2]time.Time
+	for _, step := range job.Steps {
+		if step.Conclusion != "failure" && step.Conclusion != "cancelled" && step.Conclusion != "timed_out" {
+			continue
+		}
+		result.FailedSteps = append(result.FailedSteps, step.Name)
+		start, startErr := time.Parse(time.RFC3339, step.StartedAt)
+		end, endErr := time.Parse(time.RFC3339, step.CompletedAt)
+		if startErr == nil && endErr == nil {
+			spans = append(spans, [2]time.Time{start, end})
+		}
+	}
+
+	lines := stepLogLines(logs, spans)
+	if len(lines) == 0 {
+		lines = strings.Split(strings.TrimRight(logs, "\n"), "\n")
+	}
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+		result.Truncated = true
+	}
+	result.Log = strings.Join(lines, "\n")
+	return result, nil
+}
+
+// stepLogLines returns the log lines whose timestamps fall within one of spans. Step
+// timings have second precision, so line timestamps are compared at that precision.
+func stepLogLines(logs string, spans [][2]time.Time) []string {
+	if len(spans) == 0 {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(logs, "\n") {
+		m := logTimestamp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339Nano, m[1])
+		if err != nil {
+			continue
+		}
+		ts = ts.Truncate(time.Second)
+		for _, span := range spans {
+			if !ts.Before(span[0]) && !ts.After(span[1]) {
+				lines = append(lines, line)
+				break
+			}
+		}
+	}
+	return lines
+}
+
+// RenderRunBundleMarkdown renders a bundle as a markdown report suitable for an issue.
+// Times are shown in the time zone of the client that collected the bundle.
+func RenderRunBundleMarkdown(b *RunBundle) string {
+	var sb strings.Builder
+	run := b.Run
+	fmt.Fprintf(&sb, "# %s #%d (run %d)\n\n", run.Name, run.RunNumber, run.ID)
+	fmt.Fprintf(&sb, "- **Status:** %s", run.Status)
+	if run.Conclusion != "" {
+		fmt.Fprintf(&sb, " / %s", run.Conclusion)
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "- **Event:** %s\n", run.Event)
+	fmt.Fprintf(&sb, "- **Branch:** %s\n", run.Branch)
+	fmt.Fprintf(&sb, "- **Commit:** %s", run.HeadSHA)
+	if msg, _, _ := strings.Cut(run.CommitMessage, "\n"); msg != "" {
+		fmt.Fprintf(&sb, " %s", msg)
+	}
+	sb.WriteString("\n")
+	if run.Actor != "" {
+		fmt.Fprintf(&sb, "- **Actor:** %s\n", run.Actor)
+	}
+	if run.CreatedAt != "" {
+		fmt.Fprintf(&sb, "- **Created:** %s\n", displayTime(run.CreatedAt, b.location))
+	}
+	fmt.Fprintf(&sb, "- **URL:** %s\n", run.URL)
+
+	sb.WriteString("\n## Jobs\n\n| Job | Status | Conclusion | Failed steps |\n|---|---|---|---|\n")
+	for _, job := range b.Jobs {
+		var failed []string
+		for _, step := range job.Steps {
+			if step.Conclusion == "failure" || step.Conclusion == "cancelled" || step.Conclusion == "timed_out" {
+				failed = append(failed, step.Name)
+			}
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", markdownCell(job.Name), job.Status, job.Conclusion, markdownCell(strings.Join(failed, ", ")))
+	}
+
+	if len(b.Annotations) > 0 {
+		sb.WriteString("\n## Annotations\n\n")
+		for _, a := range b.Annotations {
+			location := a.Job
+			if a.Path != "" {
+				location = fmt.Sprintf("%s, %s:%d", a.Job, a.Path, a.StartLine)
+			}
+			message := strings.ReplaceAll(strings.TrimSpace(a.Message), "\n", " ")
+			if a.Title != "" {
+				message = a.Title + ": " + message
+			}
+			fmt.Fprintf(&sb, "- **%s** (%s): %s\n", a.Level, location, message)
+		}
+	}
+
+	for _, l := range b.FailedLogs {
+		fmt.Fprintf(&sb, "\n## Log: %s", l.Job)
+		if len(l.FailedSteps) > 0 {
+			fmt.Fprintf(&sb, " (%s)", strings.Join(l.FailedSteps, ", "))
+		}
+		sb.WriteString("\n\n")
+		if l.Truncated {
+			sb.WriteString("Earlier lines omitted.\n\n")
+		}
+		sb.WriteString(RenderLogSnippet(strings.Split(l.Log, "\n")))
+	}
+
+	if b.WorkflowYAML != "" {
+		fence := markdownFence(b.WorkflowYAML)
+		fmt.Fprintf(&sb, "\n## Workflow: %s\n\n%syaml\n%s\n%s\n", b.WorkflowPath, fence, strings.TrimRight(b.WorkflowYAML, "\n"), fence)
+	}
+
+	if len(b.Warnings) > 0 {
+		sb.WriteString("\n## Bundle warnings\n\n")
+		for _, w := range b.Warnings {
+			fmt.Fprintf(&sb, "- %s\n", w)
+		}
+	}
+	return sb.String()
+}
+
+// markdownCell escapes a value for use in a markdown table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// backtickRun matches runs of backticks, which a code fence must be longer than.
+var backtickRun = regexp.MustCompile("`{3,}")
+
+// markdownFence returns a code fence longer than any backtick run in content.
+func markdownFence(content string) string {
+	n := 3
+	for _, run := range backtickRun.FindAllString(content, -1) {
+		if len(run) >= n {
+			n = len(run) + 1
+		}
+	}
+	return strings.Repeat("`", n)
+}
+
+// unsafeFileChars matches characters replaced in bundle file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// WriteRunBundleZip writes a bundle as a ZIP archive holding bundle.md, run.json,
+// jobs.json, annotations.json, logs/<job>.txt per failed job, and the workflow file.
+func WriteRunBundleZip(b *RunBundle, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	add := func(name string, data []byte) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to bundle: %w", name, err)
+		}
+		if _, err := f.Write(data); err != nil {
+			return fmt.Errorf("failed to add %s to bundle: %w", name, err)
+		}
+		return nil
+	}
+	addJSON := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		return add(name, data)
+	}
+
+	if err := add("bundle.md", []byte(RenderRunBundleMarkdown(b))); err != nil {
+		return err
+	}
+	if err := addJSON("run.json", b.Run); err != nil {
+		return err
+	}
+	if err := addJSON("jobs.json", b.Jobs); err != nil {
+		return err
+	}
+	annotations := b.Annotations
+	if annotations == nil {
+		annotations = []*Annotation{}
+	}
+	if err := addJSON("annotations.json", annotations); err != nil {
+		return err
+	}
+	for _, l := range b.FailedLogs {
+		name := fmt.Sprintf("logs/%d-%s.txt", l.JobID, strings.Trim(unsafeFileChars.ReplaceAllString(l.Job, "_"), "_"))
+		if err := add(name, []byte(l.Log+"\n")); err != nil {
+			return err
+		}
+	}
+	if b.WorkflowYAML != "" {
+		if err := add("workflow/"+path.Base(b.WorkflowPath), []byte(b.WorkflowYAML)); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
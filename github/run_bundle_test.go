@@ -0,0 +1,138 @@
+package github
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRunBundle_CollectsFailedStepContext(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	jobLog := "2024-01-15T10:00:00.1000000Z ##[group]Run actions/checkout@v4\n" +
+		"2024-01-15T10:00:01.2000000Z checked out\n" +
+		"2024-01-15T10:00:05.3000000Z ##[group]Run go test ./...\n" +
+		"2024-01-15T10:00:06.4000000Z --- FAIL: TestParse (0.00s)\n" +
+		"2024-01-15T10:00:07.9000000Z ##[error]Process completed with exit code 1.\n" +
+		"2024-01-15T10:00:09.0000000Z Post job cleanup.\n"
+	workflow := "name: CI\non: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n"
+
+	var redirectBase, workflowRef string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/42", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 42, "name": "CI", "run_number": 7, "workflow_id": 3, "status": "completed", "conclusion": "failure", "head_branch": "main", "head_sha": "abc123", "event": "push", "created_at": "2024-01-15T09:59:00Z", "html_url": "https://github.com/test-owner/test-repo/actions/runs/42"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/42/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "jobs": [
+			{"id": 100, "name": "lint", "status": "completed", "conclusion": "success"},
+			{"id": 101, "name": "test (ubuntu)", "status": "completed", "conclusion": "failure", "steps": [
+				{"name": "Checkout", "number": 1, "status": "completed", "conclusion": "success", "started_at": "2024-01-15T10:00:00Z", "completed_at": "2024-01-15T10:00:04Z"},
+				{"name": "Test", "number": 2, "status": "completed", "conclusion": "failure", "started_at": "2024-01-15T10:00:05Z", "completed_at": "2024-01-15T10:00:07Z"}
+			]}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/check-runs/100/annotations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/check-runs/101/annotations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"path": "parse_test.go", "start_line": 12, "end_line": 12, "annotation_level": "failure", "message": "expected 2, got 3"}]`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/jobs/101/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", redirectBase+"/blob/job.log")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/blob/job.log", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(jobLog))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/3", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 3, "name": "CI", "path": ".github/workflows/ci.yml"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/contents/.github/workflows/ci.yml", func(w http.ResponseWriter, r *http.Request) {
+		workflowRef = r.URL.Query().Get("ref")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": %q}`, base64.StdEncoding.EncodeToString([]byte(workflow)))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	redirectBase = ts.URL
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50, location: time.FixedZone("CET", 3600)}
+
+	bundle, err := client.GetRunBundle(context.Background(), 42, 0)
+	require.NoError(t, err)
+	assert.Empty(t, bundle.Warnings)
+	require.Len(t, bundle.Jobs, 2)
+	require.Len(t, bundle.Annotations, 1)
+	assert.Equal(t, "test (ubuntu)", bundle.Annotations[0].Job)
+	assert.Equal(t, "failure", bundle.Annotations[0].Level)
+
+	// Only the lines of the failed step are kept, not checkout or cleanup output.
+	require.Len(t, bundle.FailedLogs, 1)
+	assert.Equal(t, []string{"Test"}, bundle.FailedLogs[0].FailedSteps)
+	assert.Contains(t, bundle.FailedLogs[0].Log, "--- FAIL: TestParse")
+	assert.Contains(t, bundle.FailedLogs[0].Log, "exit code 1")
+	assert.NotContains(t, bundle.FailedLogs[0].Log, "checked out")
+	assert.NotContains(t, bundle.FailedLogs[0].Log, "Post job cleanup")
+
+	assert.Equal(t, ".github/workflows/ci.yml", bundle.WorkflowPath)
+	assert.Equal(t, workflow, bundle.WorkflowYAML)
+	assert.Equal(t, "abc123", workflowRef)
+
+	md := RenderRunBundleMarkdown(bundle)
+	assert.Contains(t, md, "# CI #7 (run 42)")
+	assert.Contains(t, md, "| test (ubuntu) | completed | failure | Test |")
+	assert.Contains(t, md, "- **failure** (test (ubuntu), parse_test.go:12): expected 2, got 3")
+	assert.Contains(t, md, "## Workflow: .github/workflows/ci.yml")
+	// The report shows the configured time zone, the JSON files keep UTC.
+	assert.Contains(t, md, "- **Created:** 2024-01-15 10:59:00 CET")
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteRunBundleZip(bundle, &buf))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[f.Name] = string(data)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"annotations.json", "bundle.md", "jobs.json", "logs/101-test_ubuntu.txt", "run.json", "workflow/ci.yml"}, names)
+	assert.Equal(t, md, files["bundle.md"])
+	assert.Equal(t, workflow, files["workflow/ci.yml"])
+	assert.Contains(t, files["run.json"], `"created_at": "2024-01-15T09:59:00Z"`)
+}
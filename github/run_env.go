@@ -0,0 +1,202 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Env entry scopes, from the lowest to the highest precedence.
+const (
+	EnvScopeWorkflow = "workflow"
+	EnvScopeJob      = "job"
+	EnvScopeStep     = "step"
+)
+
+// varsReference matches a vars context reference such as vars.DEPLOY_URL.
+var varsReference = regexp.MustCompile(`\bvars\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// EnvEntry is a variable set by an env: block of a workflow file.
+type EnvEntry struct {
+	Name  string `json:"name"` // "*" for an env block given as a single expression
+	Value string `json:"value"`
+	Scope string `json:"scope"` // workflow, job, or step
+	Job   string `json:"job,omitempty"`
+	Step  string `json:"step,omitempty"`
+}
+
+// RunEnvReport lists the environment variables a run's workflow defines and the
+// configuration variables (vars context) in scope for it.
+type RunEnvReport struct {
+	RunID         int64       `json:"run_id"`
+	WorkflowPath  string      `json:"workflow_path"`
+	SHA           string      `json:"sha,omitempty"`
+	Env           []*EnvEntry `json:"env"`
+	Environments  []string    `json:"environments,omitempty"` // Deployment environments used by the workflow's jobs
+	Variables     []*Variable `json:"variables"`
+	UndefinedVars []string    `json:"undefined_vars,omitempty"` // vars.<NAME> referenced by the workflow but defined in no scope
+	Warnings      []string    `json:"warnings,omitempty"`
+}
+
+// GetRunEnvReport reads the workflow file of a run as it was at the run's commit and lists
+// the variables its workflow-, job-, and step-level env: blocks set, together with the
+// organization, repository, and environment variables in scope. A vars reference that no
+// scope defines evaluates to an empty string and is listed in UndefinedVars. A non-empty
+// name limits the report to that variable (compared case-insensitively) and the undefined
+// vars its values reference.
+func (c *Client) GetRunEnvReport(ctx context.Context, runID int64, name string) (*RunEnvReport, error) {
+	run, err := c.GetWorkflowRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run %d: %w", runID, err)
+	}
+	path, data, err := c.runWorkflowFile(ctx, run)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the workflow of run %d: %w", runID, err)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("run %d has no workflow file: %w", runID, ErrNotFound)
+	}
+
+	report := &RunEnvReport{RunID: runID, WorkflowPath: path, SHA: run.HeadSHA, Variables: []*Variable{}}
+	env, environments, err := ParseWorkflowEnv(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	report.Env = env
+	report.Environments = environments
+
+	complete := true
+	collect := func(scope, environment string) {
+		vars, err := c.listVariables(ctx, scope, environment)
+		if err != nil && scope == VariableScopeOrganization && errors.Is(err, ErrNotFound) {
+			// Repositories owned by a user have no organization variables.
+			return
+		}
+		if err != nil {
+			complete = false
+			report.Warnings = append(report.Warnings, err.Error())
+			return
+		}
+		report.Variables = append(report.Variables, vars...)
+	}
+	collect(VariableScopeOrganization, "")
+	collect(VariableScopeRepository, "")
+	for _, environment := range environments {
+		if !strings.Contains(environment, "${{") {
+			collect(VariableScopeEnvironment, environment)
+		}
+	}
+
+	if complete {
+		defined := make(map[string]bool, len(report.Variables))
+		for _, v := range report.Variables {
+			defined[strings.ToUpper(v.Name)] = true
+		}
+		seen := make(map[string]bool)
+		for _, m := range varsReference.FindAllStringSubmatch(string(data), -1) {
+			ref := strings.ToUpper(m[1])
+			if !defined[ref] && !seen[ref] {
+				seen[ref] = true
+				report.UndefinedVars = append(report.UndefinedVars, ref)
+			}
+		}
+		sort.Strings(report.UndefinedVars)
+	}
+
+	if name != "" {
+		report.Env = slices.DeleteFunc(report.Env, func(e *EnvEntry) bool { return !strings.EqualFold(e.Name, name) && e.Name != "*" })
+		report.Variables = slices.DeleteFunc(report.Variables, func(v *Variable) bool { return !strings.EqualFold(v.Name, name) })
+		// Keep the undefined vars the remaining entries reference, which explain an empty value.
+		referenced := map[string]bool{strings.ToUpper(name): true}
+		for _, e := range report.Env {
+			for _, m := range varsReference.FindAllStringSubmatch(e.Value, -1) {
+				referenced[strings.ToUpper(m[1])] = true
+			}
+		}
+		report.UndefinedVars = slices.DeleteFunc(report.UndefinedVars, func(v string) bool { return !referenced[v] })
+	}
+	return report, nil
+}
+
+// ParseWorkflowEnv returns the entries of a workflow file's env: blocks in file order, and
+// the deployment environments its jobs use.
+func ParseWorkflowEnv(data []byte) ([]*EnvEntry, []string, error) {
+	var doc struct {
+		Env  yaml.Node `yaml:"env"`
+		Jobs yaml.Node `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+
+	entries := envEntries(&doc.Env, EnvScopeWorkflow, "", "")
+	var environments []string
+	for i := 0; i+1 < len(doc.Jobs.Content); i += 2 {
+		job := doc.Jobs.Content[i].Value
+		var def struct {
+			Environment yaml.Node `yaml:"environment"`
+			Env         yaml.Node `yaml:"env"`
+			Steps       []struct {
+				ID   string    `yaml:"id"`
+				Name string    `yaml:"name"`
+				Uses string    `yaml:"uses"`
+				Run  string    `yaml:"run"`
+				Env  yaml.Node `yaml:"env"`
+			} `yaml:"steps"`
+		}
+		if err := doc.Jobs.Content[i+1].Decode(&def); err != nil {
+			return nil, nil, fmt.Errorf("jobs.%s: %w", job, err)
+		}
+
+		if environment := environmentName(&def.Environment); environment != "" && !slices.Contains(environments, environment) {
+			environments = append(environments, environment)
+		}
+		entries = append(entries, envEntries(&def.Env, EnvScopeJob, job, "")...)
+		for n, step := range def.Steps {
+			label := step.Name
+			switch {
+			case label != "":
+			case step.ID != "":
+				label = step.ID
+			case step.Uses != "":
+				label = step.Uses
+			case step.Run != "":
+				label, _, _ = strings.Cut(strings.TrimSpace(step.Run), "\n")
+			default:
+				label = fmt.Sprintf("step %d", n+1)
+			}
+			entries = append(entries, envEntries(&step.Env, EnvScopeStep, job, label)...)
+		}
+	}
+	if entries == nil {
+		entries = []*EnvEntry{}
+	}
+	return entries, environments, nil
+}
+
+// envEntries returns the variables of an env: node.
+func envEntries(node *yaml.Node, scope, job, step string) []*EnvEntry {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []*EnvEntry{{Name: "*", Value: node.Value, Scope: scope, Job: job, Step: step}}
+	case yaml.MappingNode:
+		entries := make([]*EnvEntry, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			entries = append(entries, &EnvEntry{
+				Name:  node.Content[i].Value,
+				Value: node.Content[i+1].Value,
+				Scope: scope,
+				Job:   job,
+				Step:  step,
+			})
+		}
+		return entries
+	}
+	return nil
+}
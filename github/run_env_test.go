@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const runEnvWorkflow = `name: Deploy
+on: push
+env:
+  GO_VERSION: "1.24"
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    env:
+      API_URL: ${{ vars.API_URL }}
+    steps:
+      - uses: actions/checkout@v4
+      - name: Test
+        run: go test ./...
+        env:
+          CGO_ENABLED: 0
+  deploy:
+    runs-on: ubuntu-latest
+    environment: production
+    steps:
+      - run: ./deploy.sh
+        env:
+          TARGET: ${{ vars.DEPLOY_TARGET }}
+          REGION: ${{ vars.region }}
+`
+
+func TestParseWorkflowEnv(t *testing.T) {
+	env, environments, err := ParseWorkflowEnv([]byte(runEnvWorkflow))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"production"}, environments)
+	require.Len(t, env, 5)
+	assert.Equal(t, &EnvEntry{Name: "GO_VERSION", Value: "1.24", Scope: EnvScopeWorkflow}, env[0])
+	assert.Equal(t, &EnvEntry{Name: "API_URL", Value: "${{ vars.API_URL }}", Scope: EnvScopeJob, Job: "build"}, env[1])
+	assert.Equal(t, &EnvEntry{Name: "CGO_ENABLED", Value: "0", Scope: EnvScopeStep, Job: "build", Step: "Test"}, env[2])
+	assert.Equal(t, "./deploy.sh", env[3].Step)
+
+	env, _, err = ParseWorkflowEnv([]byte("on: push\nenv: ${{ fromJSON(vars.ENV) }}\njobs: {}\n"))
+	require.NoError(t, err)
+	require.Len(t, env, 1)
+	assert.Equal(t, "*", env[0].Name)
+}
+
+func TestGetRunEnvReport(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/100", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 100, "workflow_id": 7, "head_sha": "abc123", "path": ".github/workflows/deploy.yml"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 7, "path": ".github/workflows/deploy.yml"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/contents/.github/workflows/deploy.yml", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc123", r.URL.Query().Get("ref"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(runEnvWorkflow)),
+		})
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/organization-variables", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/variables", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "variables": [{"name": "API_URL", "value": "https://api.example.com", "updated_at": "2026-01-01T00:00:00Z"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/environments/production/variables", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "variables": [{"name": "REGION", "value": "eu-west-1"}]}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	report, err := client.GetRunEnvReport(context.Background(), 100, "")
+	require.NoError(t, err)
+	assert.Equal(t, ".github/workflows/deploy.yml", report.WorkflowPath)
+	assert.Equal(t, "abc123", report.SHA)
+	assert.Len(t, report.Env, 5)
+	require.Len(t, report.Variables, 2)
+	assert.Equal(t, VariableScopeRepository, report.Variables[0].Scope)
+	assert.Equal(t, &Variable{Name: "REGION", Value: "eu-west-1", Scope: VariableScopeEnvironment, Environment: "production"}, report.Variables[1])
+	assert.Equal(t, []string{"DEPLOY_TARGET"}, report.UndefinedVars)
+	assert.Empty(t, report.Warnings)
+
+	report, err = client.GetRunEnvReport(context.Background(), 100, "target")
+	require.NoError(t, err)
+	require.Len(t, report.Env, 1)
+	assert.Equal(t, "TARGET", report.Env[0].Name)
+	assert.Empty(t, report.Variables)
+	assert.Equal(t, []string{"DEPLOY_TARGET"}, report.UndefinedVars)
+}
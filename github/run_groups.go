@@ -0,0 +1,67 @@
+package github
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Ways to group a run listing.
+const (
+	GroupByCommit = "commit"
+	GroupByPR     = "pr"
+)
+
+// RunGroup is a set of runs triggered by the same change: one head commit, or one pull
+// request.
+type RunGroup struct {
+	Key         string         `json:"key"` // "pr/<number>" or "commit/<sha>"
+	PullRequest int            `json:"pull_request,omitempty"`
+	HeadSHA     string         `json:"head_sha,omitempty"` // Head of the group's first run; a PR may span several pushes
+	Branch      string         `json:"branch,omitempty"`
+	Commit      string         `json:"commit,omitempty"` // First line of that head commit's message
+	Outcomes    map[string]int `json:"outcomes"`         // Run counts by conclusion, or by status while in progress
+	Runs        []*WorkflowRun `json:"runs"`
+}
+
+// GroupRuns nests runs under their head commit (by "commit") or pull request (by "pr").
+// Runs not associated with a pull request, such as pushes or PRs from forks, are grouped
+// by commit when grouping by PR. Groups keep the order of their first run, so with runs
+// listed newest first the most recent change comes first.
+func GroupRuns(runs []*WorkflowRun, by string) ([]*RunGroup, error) {
+	if by != GroupByCommit && by != GroupByPR {
+		return nil, fmt.Errorf("invalid group_by %q: expected %s or %s", by, GroupByCommit, GroupByPR)
+	}
+
+	var groups []*RunGroup
+	index := make(map[string]*RunGroup)
+	for _, run := range runs {
+		key := "commit/" + run.HeadSHA
+		pr := 0
+		if by == GroupByPR && len(run.PullRequests) > 0 {
+			pr = run.PullRequests[0]
+			key = "pr/" + strconv.Itoa(pr)
+		}
+
+		group, ok := index[key]
+		if !ok {
+			group = &RunGroup{
+				Key:         key,
+				PullRequest: pr,
+				HeadSHA:     run.HeadSHA,
+				Branch:      run.Branch,
+				Commit:      CommitTitle(run.CommitMessage),
+				Outcomes:    make(map[string]int),
+			}
+			index[key] = group
+			groups = append(groups, group)
+		}
+
+		outcome := run.Conclusion
+		if outcome == "" {
+			outcome = run.Status
+		}
+		group.Outcomes[outcome]++
+		group.Runs = append(group.Runs, run)
+	}
+	return groups, nil
+}
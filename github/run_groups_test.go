@@ -0,0 +1,40 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupRuns(t *testing.T) {
+	runs := []*WorkflowRun{
+		{ID: 6, Name: "CI", Status: "in_progress", HeadSHA: "ccc", Branch: "feature", PullRequests: []int{12}, CommitMessage: "Address review\n\nDetails"},
+		{ID: 5, Name: "Lint", Status: "completed", Conclusion: "success", HeadSHA: "ccc", Branch: "feature", PullRequests: []int{12}},
+		{ID: 4, Name: "CI", Status: "completed", Conclusion: "failure", HeadSHA: "bbb", Branch: "feature", PullRequests: []int{12}},
+		{ID: 3, Name: "CI", Status: "completed", Conclusion: "success", HeadSHA: "aaa", Branch: "main", CommitMessage: "Release 1.0"},
+		{ID: 2, Name: "Lint", Status: "completed", Conclusion: "success", HeadSHA: "aaa", Branch: "main"},
+	}
+
+	byPR, err := GroupRuns(runs, GroupByPR)
+	require.NoError(t, err)
+	require.Len(t, byPR, 2)
+	assert.Equal(t, "pr/12", byPR[0].Key)
+	assert.Equal(t, 12, byPR[0].PullRequest)
+	assert.Equal(t, "ccc", byPR[0].HeadSHA)
+	assert.Equal(t, "Address review", byPR[0].Commit)
+	assert.Equal(t, map[string]int{"in_progress": 1, "success": 1, "failure": 1}, byPR[0].Outcomes)
+	assert.Len(t, byPR[0].Runs, 3)
+	assert.Equal(t, "commit/aaa", byPR[1].Key, "runs without a PR are grouped by commit")
+	assert.Zero(t, byPR[1].PullRequest)
+
+	byCommit, err := GroupRuns(runs, GroupByCommit)
+	require.NoError(t, err)
+	require.Len(t, byCommit, 3)
+	assert.Equal(t, []string{"commit/ccc", "commit/bbb", "commit/aaa"}, []string{byCommit[0].Key, byCommit[1].Key, byCommit[2].Key})
+	assert.Zero(t, byCommit[0].PullRequest)
+	assert.Equal(t, map[string]int{"success": 2}, byCommit[2].Outcomes)
+
+	_, err = GroupRuns(runs, "author")
+	assert.Error(t, err)
+}
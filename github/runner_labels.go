@@ -0,0 +1,264 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+	"gopkg.in/yaml.v3"
+)
+
+// githubHostedLabels are the runs-on labels of GitHub-hosted runners.
+var githubHostedLabels = []string{
+	"ubuntu-latest", "ubuntu-24.04", "ubuntu-22.04", "ubuntu-20.04",
+	"ubuntu-24.04-arm", "ubuntu-22.04-arm",
+	"windows-latest", "windows-2025", "windows-2022", "windows-2019", "windows-11-arm",
+	"macos-latest", "macos-15", "macos-14", "macos-13",
+	"macos-latest-large", "macos-15-large", "macos-14-large", "macos-13-large",
+	"macos-latest-xlarge", "macos-15-xlarge", "macos-14-xlarge", "macos-13-xlarge",
+}
+
+// selfHostedDefaultLabels are the labels every self-hosted runner gets for its OS and
+// architecture.
+var selfHostedDefaultLabels = []string{"self-hosted", "linux", "windows", "macos", "x64", "arm", "arm64"}
+
+// maxLabelTypoDistance is the largest edit distance at which an unknown label is taken for
+// a typo of a known one. Labels shorter than shortLabelLength allow one edit, as short
+// custom labels such as x86 are often a single edit away from a default one.
+const (
+	maxLabelTypoDistance = 2
+	shortLabelLength     = 8
+)
+
+// SelfHostedRunner is a self-hosted runner registered to a repository.
+type SelfHostedRunner struct {
+	Name   string   `json:"name"`
+	Status string   `json:"status"` // online or offline
+	Busy   bool     `json:"busy"`
+	Labels []string `json:"labels"`
+}
+
+// ListSelfHostedRunners lists the self-hosted runners registered to the repository.
+// Runners of the organization or enterprise are not included. Listing runners needs the
+// Administration read permission.
+func (c *Client) ListSelfHostedRunners(ctx context.Context) ([]*SelfHostedRunner, error) {
+	var runners []*SelfHostedRunner
+	opts := &github.ListRunnersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := c.gh.Actions.ListRunners(ctx, c.owner, c.repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list self-hosted runners: %w", Classify(err))
+		}
+		for _, r := range page.Runners {
+			runner := &SelfHostedRunner{Name: r.GetName(), Status: r.GetStatus(), Busy: r.GetBusy(), Labels: []string{}}
+			for _, label := range r.Labels {
+				runner.Labels = append(runner.Labels, label.GetName())
+			}
+			runners = append(runners, runner)
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return runners, nil
+}
+
+// CheckRunnerLabels checks the labels a job asks for against the GitHub-hosted runner
+// labels and the labels of runners, the repository's self-hosted runners. A label that
+// is unknown but close to a known one, such as ubuntu-latests, is reported as a likely
+// typo. A job on self-hosted runners is reported when runners is not empty and none of
+// them, or none that is online, has all its labels. Runners of the organization or
+// enterprise are not known here, and larger runners have custom labels, so any other
+// unknown label is accepted. Labels set by expressions are skipped.
+func CheckRunnerLabels(labels []string, runners []*SelfHostedRunner) []string {
+	known := make(map[string]bool)
+	for _, label := range githubHostedLabels {
+		known[label] = true
+	}
+	for _, label := range selfHostedDefaultLabels {
+		known[label] = true
+	}
+	for _, runner := range runners {
+		for _, label := range runner.Labels {
+			known[strings.ToLower(label)] = true
+		}
+	}
+
+	var problems []string
+	selfHosted := false
+	for _, label := range labels {
+		lower := strings.ToLower(strings.TrimSpace(label))
+		if lower == "" || strings.Contains(lower, "${{") {
+			continue
+		}
+		if lower == "self-hosted" {
+			selfHosted = true
+		}
+		if known[lower] {
+			continue
+		}
+		if suggestion := closestLabel(lower, known); suggestion != "" {
+			problems = append(problems, fmt.Sprintf("runner label %q is unknown; did you mean %q? A job whose labels no runner has stays queued until it is cancelled", label, suggestion))
+		}
+	}
+	if !selfHosted || len(runners) == 0 || len(problems) > 0 {
+		return problems
+	}
+
+	matching, online := 0, 0
+	for _, runner := range runners {
+		if runnerHasLabels(runner, labels) {
+			matching++
+			if runner.Status == "online" {
+				online++
+			}
+		}
+	}
+	switch {
+	case matching == 0:
+		problems = append(problems, fmt.Sprintf("no self-hosted runner of the repository has all of the labels %s; the job stays queued unless an organization or enterprise runner has them", strings.Join(labels, ", ")))
+	case online == 0:
+		problems = append(problems, fmt.Sprintf("the %d self-hosted runner(s) with the labels %s are offline", matching, strings.Join(labels, ", ")))
+	}
+	return problems
+}
+
+// diagnoseQueuedJobs lists the queued jobs of a run and checks the runner labels they
+// wait for. The repository's self-hosted runners are included when the token may list
+// them. Lookup failures leave the result empty.
+func (c *Client) diagnoseQueuedJobs(ctx context.Context, runID int64) []*QueuedJob {
+	jobs, err := c.GetWorkflowJobs(ctx, runID, "", 0)
+	if err != nil {
+		log.Debugf("Could not get jobs for run %d: %v", runID, err)
+		return nil
+	}
+	var queued []*QueuedJob
+	for _, job := range jobs {
+		if job.Status == "queued" || job.Status == "waiting" || job.Status == "pending" {
+			queued = append(queued, &QueuedJob{JobID: job.ID, JobName: job.Name, Labels: job.Labels})
+		}
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+	runners, err := c.ListSelfHostedRunners(ctx)
+	if err != nil {
+		log.Debugf("Could not list self-hosted runners: %v", err)
+	}
+	for _, job := range queued {
+		job.Problems = CheckRunnerLabels(job.Labels, runners)
+	}
+	return queued
+}
+
+// runnerHasLabels reports whether runner has every label, ignoring case.
+func runnerHasLabels(runner *SelfHostedRunner, labels []string) bool {
+	has := make(map[string]bool, len(runner.Labels))
+	for _, label := range runner.Labels {
+		has[strings.ToLower(label)] = true
+	}
+	for _, label := range labels {
+		if !has[strings.ToLower(strings.TrimSpace(label))] {
+			return false
+		}
+	}
+	return true
+}
+
+// closestLabel returns the known label nearest to label within maxLabelTypoDistance, or "".
+func closestLabel(label string, known map[string]bool) string {
+	maxDistance := maxLabelTypoDistance
+	if len(label) < shortLabelLength {
+		maxDistance = 1
+	}
+	candidates := make([]string, 0, len(known))
+	for k := range known {
+		candidates = append(candidates, k)
+	}
+	sort.Strings(candidates)
+	best, bestDistance := "", maxDistance+1
+	for _, candidate := range candidates {
+		if d := editDistance(label, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// checkWorkflowRunnerLabels reports the runs-on labels of the jobs in a workflow that
+// CheckRunnerLabels finds problems with.
+func checkWorkflowRunnerLabels(data []byte, runners []*SelfHostedRunner) []*WorkflowIssue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	_, jobs := yamlMappingValue(doc.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var issues []*WorkflowIssue
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		id, job := jobs.Content[i].Value, jobs.Content[i+1]
+		if job.Kind != yaml.MappingNode {
+			continue
+		}
+		_, runsOn := yamlMappingValue(job, "runs-on")
+		if runsOn == nil {
+			continue
+		}
+		// runs-on is a label, a list of labels, or a mapping with a runner group and labels.
+		node := runsOn
+		if runsOn.Kind == yaml.MappingNode {
+			if _, node = yamlMappingValue(runsOn, "labels"); node == nil {
+				continue
+			}
+		}
+		var labels []string
+		switch node.Kind {
+		case yaml.ScalarNode:
+			labels = []string{node.Value}
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				if item.Kind == yaml.ScalarNode {
+					labels = append(labels, item.Value)
+				}
+			}
+		}
+		for _, problem := range CheckRunnerLabels(labels, runners) {
+			issues = append(issues, &WorkflowIssue{
+				Line:     node.Line,
+				Column:   node.Column,
+				Severity: "warning",
+				Kind:     "runner-label",
+				Message:  fmt.Sprintf("job %q: %s", id, problem),
+				Source:   "yaml",
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRunnerLabels(t *testing.T) {
+	runners := []*SelfHostedRunner{
+		{Name: "gpu-1", Status: "offline", Labels: []string{"self-hosted", "Linux", "gpu"}},
+		{Name: "build-1", Status: "online", Labels: []string{"self-hosted", "linux", "x64", "build-large"}},
+	}
+
+	assert.Empty(t, CheckRunnerLabels([]string{"ubuntu-latest"}, nil))
+	assert.Empty(t, CheckRunnerLabels([]string{"${{ matrix.os }}"}, nil))
+	assert.Empty(t, CheckRunnerLabels([]string{"acme-larger-runner"}, nil), "custom labels are accepted")
+	assert.Empty(t, CheckRunnerLabels([]string{"x86"}, nil), "short labels allow one edit only")
+	assert.Empty(t, CheckRunnerLabels([]string{"self-hosted", "linux", "build-large"}, runners))
+
+	problems := CheckRunnerLabels([]string{"ubuntu-latests"}, nil)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], `did you mean "ubuntu-latest"?`)
+
+	problems = CheckRunnerLabels([]string{"self-hosted", "build-larg"}, runners)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], `did you mean "build-large"?`)
+
+	problems = CheckRunnerLabels([]string{"self-hosted", "GPU"}, runners)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "the 1 self-hosted runner(s) with the labels self-hosted, GPU are offline")
+
+	problems = CheckRunnerLabels([]string{"self-hosted", "gpu", "x64"}, runners)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "no self-hosted runner of the repository has all of the labels")
+
+	// Without the repository's runners, self-hosted label sets cannot be checked.
+	assert.Empty(t, CheckRunnerLabels([]string{"self-hosted", "gpu", "x64"}, nil))
+}
+
+func TestDiagnoseFailure_QueuedJobLabels(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/100", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 100, "name": "CI", "status": "queued", "head_branch": "main", "run_number": 10, "workflow_id": 50}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/100/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "jobs": [
+			{"id": 1, "name": "lint", "status": "queued", "labels": ["ubuntu-latest"]},
+			{"id": 2, "name": "test", "status": "queued", "labels": ["ubuntu-latests"]}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runners", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	diagnosis, err := client.DiagnoseFailure(context.Background(), 100, false, 50)
+	require.NoError(t, err)
+	require.Len(t, diagnosis.QueuedJobs, 2)
+	assert.Empty(t, diagnosis.QueuedJobs[0].Problems)
+	require.Len(t, diagnosis.QueuedJobs[1].Problems, 1)
+	assert.Contains(t, diagnosis.Summary, `Run 100 is still queued (not completed yet); job "test" may never start: runner label "ubuntu-latests" is unknown`)
+}
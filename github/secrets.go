@@ -0,0 +1,111 @@
+package github
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// secretMask replaces detected secrets, matching how GitHub masks registered secrets in logs.
+const secretMask = "***"
+
+// secretPatterns match known credential formats. When a pattern has a capture group, only
+// the group is masked so surrounding context (e.g. "Authorization: Bearer") stays readable.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}\b`),
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`),
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`),
+	regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`),
+	regexp.MustCompile(`\bnpm_[A-Za-z0-9]{36}\b`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\b`),
+	regexp.MustCompile(`(?i)\bauthorization:\s*(?:bearer|token|basic)\s+([^\s"']+)`),
+	regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@]+:([^\s@/]+)@`),
+	regexp.MustCompile(`(?i)(?:password|passwd|secret|client_secret|api[_-]?key|access[_-]?key|secret[_-]?key|private[_-]?key|token)["']?\s*[=:]\s*["']?([^\s"',;]{8,})`),
+}
+
+// highEntropyCandidate matches long tokens that may be random credentials.
+var highEntropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_-]{32,}={0,2}`)
+
+// MaskSecrets replaces known secret formats and high-entropy strings in text with "***".
+// It returns the masked text and the number of values masked. Hex strings such as commit
+// SHAs and digests are left alone since their entropy is capped at 4 bits per character.
+func MaskSecrets(text string) (string, int) {
+	count := 0
+	for _, re := range secretPatterns {
+		text = re.ReplaceAllStringFunc(text, func(match string) string {
+			if strings.Contains(match, secretMask) {
+				return match
+			}
+			sub := re.FindStringSubmatchIndex(match)
+			count++
+			if len(sub) >= 4 && sub[2] >= 0 {
+				return match[:sub[2]] + secretMask + match[sub[3]:]
+			}
+			return secretMask
+		})
+	}
+
+	text = highEntropyCandidate.ReplaceAllStringFunc(text, func(match string) string {
+		if !looksLikeSecret(match) {
+			return match
+		}
+		count++
+		return secretMask
+	})
+
+	return text, count
+}
+
+// looksLikeSecret reports whether a token mixes letters and digits and has the character
+// entropy of random base64/base62 data. Tokens made of short words joined by "_" or "-"
+// (artifact names, pod names) and paths are not considered secrets.
+func looksLikeSecret(token string) bool {
+	if strings.Count(token, "/") > 2 {
+		return false
+	}
+	longest := 0
+	for _, segment := range strings.FieldsFunc(token, func(r rune) bool { return r == '_' || r == '-' }) {
+		if len(segment) > longest {
+			longest = len(segment)
+		}
+	}
+	if longest < 24 {
+		return false
+	}
+
+	var hasDigit, hasUpper, hasLower bool
+	for _, r := range token {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r >= 'A' && r <= 'Z':
+			hasUpper = true
+		case r >= 'a' && r <= 'z':
+			hasLower = true
+		}
+	}
+	if !hasDigit || !hasUpper || !hasLower {
+		return false
+	}
+	return shannonEntropy(token) > 4.2
+}
+
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	freq := make(map[rune]int)
+	for _, r := range s {
+		freq[r]++
+	}
+	n := float64(len(s))
+	entropy := 0.0
+	for _, c := range freq {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
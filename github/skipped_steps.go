@@ -0,0 +1,287 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SkippedStep explains why a step of a successful job, or a whole job, did not run.
+type SkippedStep struct {
+	Job        string            `json:"job"`
+	Step       string            `json:"step,omitempty"` // Empty when the whole job was skipped
+	Number     int64             `json:"number,omitempty"`
+	Condition  string            `json:"condition,omitempty"` // if: condition from the workflow file
+	Evaluation *ExpressionResult `json:"evaluation,omitempty"`
+	Reason     string            `json:"reason"`
+}
+
+// workflowJobDef is the part of a job definition that decides whether it and its steps run.
+type workflowJobDef struct {
+	Name  string    `yaml:"name"`
+	If    string    `yaml:"if"`
+	Needs yaml.Node `yaml:"needs"`
+	Steps []struct {
+		ID   string `yaml:"id"`
+		Name string `yaml:"name"`
+		Uses string `yaml:"uses"`
+		Run  string `yaml:"run"`
+		If   string `yaml:"if"`
+	} `yaml:"steps"`
+}
+
+// explainSkipped explains the skipped steps of a run's successful jobs and its skipped
+// jobs by evaluating their if: conditions, from the workflow file at the run's commit,
+// against the contexts the run's metadata and job results provide.
+func (c *Client) explainSkipped(ctx context.Context, run *WorkflowRun, jobs []*Job) []*SkippedStep {
+	var skippedJobs, ranJobs []*Job
+	for _, job := range jobs {
+		switch job.Conclusion {
+		case "skipped":
+			skippedJobs = append(skippedJobs, job)
+		case "success":
+			for _, step := range job.Steps {
+				if step.Conclusion == "skipped" {
+					ranJobs = append(ranJobs, job)
+					break
+				}
+			}
+		}
+	}
+	if len(skippedJobs) == 0 && len(ranJobs) == 0 {
+		return nil
+	}
+
+	defs, order := map[string]*workflowJobDef{}, []string(nil)
+	_, data, err := c.runWorkflowFile(ctx, run)
+	if err != nil {
+		log.Debugf("Could not read the workflow of run %d: %v", run.ID, err)
+	} else if data != nil {
+		var doc struct {
+			Jobs yaml.Node `yaml:"jobs"`
+		}
+		if err := yaml.Unmarshal(data, &doc); err == nil {
+			for i := 0; i+1 < len(doc.Jobs.Content); i += 2 {
+				def := &workflowJobDef{}
+				if doc.Jobs.Content[i+1].Decode(def) == nil {
+					defs[doc.Jobs.Content[i].Value] = def
+					order = append(order, doc.Jobs.Content[i].Value)
+				}
+			}
+		}
+	}
+	findDef := func(jobName string) (string, *workflowJobDef) {
+		for _, key := range order {
+			def := defs[key]
+			for _, name := range []string{def.Name, key} {
+				if name != "" && (jobName == name || strings.HasPrefix(jobName, name+" (")) {
+					return key, def
+				}
+			}
+		}
+		return "", nil
+	}
+
+	githubContext := runGitHubContext(run, c.owner, c.repo)
+	var result []*SkippedStep
+	for _, job := range skippedJobs {
+		skipped := &SkippedStep{Job: job.Name}
+		key, def := findDef(job.Name)
+		if def == nil {
+			skipped.Reason = "the job was not found in the workflow file"
+			result = append(result, skipped)
+			continue
+		}
+
+		// Summarise the results of the needed jobs, a matrix job's combinations included.
+		needs := map[string]interface{}{}
+		status := ExpressionStatusSuccess
+		var unsuccessful []string
+		for _, need := range jobNeeds(&def.Needs) {
+			needResult := ""
+			for _, other := range jobs {
+				if otherKey, _ := findDef(other.Name); otherKey == need {
+					needResult = worseConclusion(needResult, other.Conclusion)
+				}
+			}
+			needs[need] = map[string]interface{}{"result": needResult, "outputs": map[string]interface{}{}}
+			switch needResult {
+			case "success", "":
+				continue
+			case "failure":
+				status = ExpressionStatusFailure
+			case "cancelled":
+				if status != ExpressionStatusFailure {
+					status = ExpressionStatusCancelled
+				}
+			}
+			unsuccessful = append(unsuccessful, fmt.Sprintf("%s (%s)", need, needResult))
+		}
+
+		skipped.Condition = strings.TrimSpace(def.If)
+		if skipped.Condition == "" {
+			if len(unsuccessful) > 0 {
+				skipped.Reason = fmt.Sprintf("needed job(s) did not succeed: %s; without an if: condition a job only runs when all its needs succeed", strings.Join(unsuccessful, ", "))
+			} else {
+				skipped.Reason = fmt.Sprintf("job %s has no if: condition, so it was skipped for a reason outside the workflow file", key)
+			}
+			result = append(result, skipped)
+			continue
+		}
+		explainCondition(skipped, map[string]interface{}{"github": githubContext, "needs": needs}, status, unsuccessful)
+		result = append(result, skipped)
+	}
+
+	for _, job := range ranJobs {
+		_, def := findDef(job.Name)
+		// Match the workflow's steps to the job's steps in order; GitHub names a step
+		// without a name after its action or the first line of its script.
+		matched := make([]*Step, len(job.Steps))
+		if def != nil {
+			next := 0
+			for i, s := range def.Steps {
+				name := s.Name
+				if name == "" && s.Uses != "" {
+					name = "Run " + s.Uses
+				} else if name == "" {
+					first, _, _ := strings.Cut(strings.TrimSpace(s.Run), "\n")
+					name = "Run " + first
+				}
+				for j := next; j < len(job.Steps); j++ {
+					if job.Steps[j].Name == name {
+						matched[i], next = job.Steps[j], j+1
+						break
+					}
+				}
+			}
+		}
+
+		for _, step := range job.Steps {
+			if step.Conclusion != "skipped" {
+				continue
+			}
+			skipped := &SkippedStep{Job: job.Name, Step: step.Name, Number: step.Number}
+			index := -1
+			if def != nil {
+				for i := range def.Steps {
+					if matched[i] == step {
+						index = i
+					}
+				}
+			}
+			if index < 0 {
+				skipped.Reason = "the step was not found in the workflow file"
+				result = append(result, skipped)
+				continue
+			}
+
+			steps := map[string]interface{}{}
+			for i := 0; i < index; i++ {
+				if id := def.Steps[i].ID; id != "" && matched[i] != nil {
+					steps[id] = map[string]interface{}{
+						"outcome":    matched[i].Conclusion,
+						"conclusion": matched[i].Conclusion,
+						"outputs":    map[string]interface{}{},
+					}
+				}
+			}
+			skipped.Condition = strings.TrimSpace(def.Steps[index].If)
+			if skipped.Condition == "" {
+				skipped.Reason = "the step has no if: condition, so it was skipped for a reason outside the workflow file"
+				result = append(result, skipped)
+				continue
+			}
+			explainCondition(skipped, map[string]interface{}{
+				"github": githubContext,
+				"steps":  steps,
+				"job":    map[string]interface{}{"status": ExpressionStatusSuccess},
+			}, ExpressionStatusSuccess, nil)
+			result = append(result, skipped)
+		}
+	}
+	return result
+}
+
+// explainCondition evaluates the if: condition of a skipped job or step and sets the
+// reason it did not run.
+func explainCondition(skipped *SkippedStep, contexts map[string]interface{}, status string, unsuccessful []string) {
+	evaluation, err := EvaluateExpression(skipped.Condition, contexts, ExpressionOptions{Status: status, Condition: true})
+	if err != nil {
+		skipped.Reason = fmt.Sprintf("the if: condition could not be evaluated: %v", err)
+		return
+	}
+	skipped.Evaluation = evaluation
+	if strings.Contains(strings.ToLower(skipped.Condition), "github.event.") {
+		evaluation.Warnings = append(evaluation.Warnings, "the event payload is not available, so github.event properties evaluate to null")
+	}
+
+	switch {
+	case evaluation.ImplicitSuccess && len(unsuccessful) > 0:
+		// A skipped need also makes success() false, which a single job status cannot express.
+		skipped.Reason = fmt.Sprintf("needed job(s) did not succeed: %s, and the if: condition calls no status function, so the implicit success() is false; add always() or !cancelled() to run anyway", strings.Join(unsuccessful, ", "))
+	case evaluation.Truthy:
+		skipped.Reason = "the if: condition evaluates to true with the contexts available here; it depends on values not known after the run, such as step outputs, inputs, or secrets"
+	default:
+		skipped.Reason = "the if: condition is false"
+		for _, step := range evaluation.Steps {
+			if !exprTruthy(step.Value) {
+				skipped.Reason = fmt.Sprintf("the if: condition is false because %s is %s", step.Expression, exprDescribe(step.Value))
+				break
+			}
+		}
+	}
+}
+
+// exprDescribe renders a value for a reason message.
+func exprDescribe(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "'" + x + "'"
+	}
+	return exprString(v)
+}
+
+// jobNeeds returns the job IDs of a needs: node, given as a string or a list.
+func jobNeeds(node *yaml.Node) []string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}
+	case yaml.SequenceNode:
+		needs := make([]string, 0, len(node.Content))
+		for _, n := range node.Content {
+			needs = append(needs, n.Value)
+		}
+		return needs
+	}
+	return nil
+}
+
+// worseConclusion combines the conclusions of a matrix job's combinations the way the
+// needs context reports them: failure over cancelled over skipped over success.
+func worseConclusion(a, b string) string {
+	rank := map[string]int{"": 0, "success": 1, "skipped": 2, "cancelled": 3, "failure": 4}
+	if b == "timed_out" {
+		b = "failure"
+	}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// skippedNames lists skipped jobs and steps for a summary, e.g. `step "Deploy" of job build`.
+func skippedNames(skipped []*SkippedStep) string {
+	names := make([]string, 0, len(skipped))
+	for _, s := range skipped {
+		if s.Step != "" {
+			names = append(names, fmt.Sprintf("step %q of job %s", s.Step, s.Job))
+		} else {
+			names = append(names, "job "+s.Job)
+		}
+	}
+	return strings.Join(names, ", ")
+}
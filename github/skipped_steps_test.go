@@ -0,0 +1,146 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const skippedWorkflow = `name: CI
+on: [push, pull_request]
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - id: test
+        run: make test
+      - name: Deploy
+        if: github.ref == 'refs/heads/main' && steps.test.outcome == 'success'
+        run: ./deploy.sh
+      - name: Report
+        if: failure()
+        run: ./report.sh
+  release:
+    needs: build
+    if: startsWith(github.ref, 'refs/tags/')
+    runs-on: ubuntu-latest
+    steps:
+      - run: ./release.sh
+`
+
+func TestDiagnoseFailure_ExplainsSkippedSteps(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/100", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 100, "name": "CI", "status": "completed", "conclusion": "success",
+			"event": "push", "head_branch": "develop", "head_sha": "abc123", "workflow_id": 7}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/100/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 2, "jobs": [
+			{"id": 1, "name": "build", "status": "completed", "conclusion": "success", "steps": [
+				{"name": "Set up job", "number": 1, "status": "completed", "conclusion": "success"},
+				{"name": "Run actions/checkout@v4", "number": 2, "status": "completed", "conclusion": "success"},
+				{"name": "Run make test", "number": 3, "status": "completed", "conclusion": "success"},
+				{"name": "Deploy", "number": 4, "status": "completed", "conclusion": "skipped"},
+				{"name": "Report", "number": 5, "status": "completed", "conclusion": "skipped"},
+				{"name": "Complete job", "number": 6, "status": "completed", "conclusion": "success"}
+			]},
+			{"id": 2, "name": "release", "status": "completed", "conclusion": "skipped", "steps": []}
+		]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 7, "path": ".github/workflows/ci.yml"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/contents/.github/workflows/ci.yml", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc123", r.URL.Query().Get("ref"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(skippedWorkflow)),
+		})
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	diagnosis, err := client.DiagnoseFailure(context.Background(), 100, false, 50)
+	require.NoError(t, err)
+	require.Len(t, diagnosis.Skipped, 3)
+	assert.Contains(t, diagnosis.Summary, `step "Deploy" of job build`)
+
+	release := diagnosis.Skipped[0]
+	assert.Equal(t, "release", release.Job)
+	assert.Empty(t, release.Step)
+	assert.Equal(t, "startsWith(github.ref, 'refs/tags/')", release.Condition)
+	assert.Equal(t, "the if: condition is false because startsWith(github.ref, 'refs/tags/') is false", release.Reason)
+
+	deploy := diagnosis.Skipped[1]
+	assert.Equal(t, "Deploy", deploy.Step)
+	assert.Equal(t, int64(4), deploy.Number)
+	require.NotNil(t, deploy.Evaluation)
+	assert.False(t, deploy.Evaluation.Truthy)
+	assert.Equal(t, "the if: condition is false because github.ref == 'refs/heads/main' is false", deploy.Reason)
+	assert.Contains(t, deploy.Evaluation.Steps, ExpressionStep{Expression: "github.ref", Value: "refs/heads/develop"})
+
+	report := diagnosis.Skipped[2]
+	assert.Equal(t, "failure()", report.Condition)
+	assert.Equal(t, "the if: condition is false because failure() is false", report.Reason)
+}
+
+func TestExplainSkipped_FailedNeed(t *testing.T) {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: "o", repo: "r", gh: ghc, perPageLimit: 50}
+
+	mux.HandleFunc("/repos/o/r/actions/workflows/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 7, "path": ".github/workflows/ci.yml"}`))
+	})
+	mux.HandleFunc("/repos/o/r/contents/.github/workflows/ci.yml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(skippedWorkflow)),
+		})
+	})
+	run := &WorkflowRun{ID: 1, Event: "push", Branch: "main", WorkflowID: 7}
+	jobs := []*Job{
+		{Name: "build", Conclusion: "failure"},
+		{Name: "release", Conclusion: "skipped"},
+	}
+
+	skipped := client.explainSkipped(context.Background(), run, jobs)
+	require.Len(t, skipped, 1)
+	assert.Contains(t, skipped[0].Reason, "needed job(s) did not succeed: build (failure)")
+	assert.Contains(t, skipped[0].Reason, "implicit success() is false")
+}
@@ -0,0 +1,346 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Defaults and caps for the stale-branch report.
+const (
+	DefaultStaleDays        = 14
+	DefaultStaleReportRuns  = 300
+	maxStaleReportRuns      = 1000
+	maxStaleMergeChecks     = 30
+	maxStaleReportRefsPages = 10
+)
+
+// Reasons a branch appears in the stale-branch report.
+const (
+	StaleReasonFailure = "stale_failure" // Latest run failed long ago and nothing ran since
+	StaleReasonMerged  = "merged"        // Branch is merged into the default branch but still runs CI
+	StaleReasonDeleted = "deleted"       // Branch no longer exists but still had runs recently
+)
+
+// StaleBranch is a branch whose CI runs can likely be cleaned up.
+type StaleBranch struct {
+	Branch           string   `json:"branch"`
+	Reason           string   `json:"reason"`
+	LastRunID        int64    `json:"last_run_id"`
+	LastWorkflow     string   `json:"last_workflow"`
+	LastConclusion   string   `json:"last_conclusion,omitempty"`
+	LastRunAt        string   `json:"last_run_at"`
+	DaysSinceLastRun int      `json:"days_since_last_run"`
+	RecentRuns       int      `json:"recent_runs"`    // Runs within the stale window
+	RecentMinutes    float64  `json:"recent_minutes"` // Run time of those runs
+	Workflows        []string `json:"workflows"`      // Workflows that ran on the branch in the scanned window
+}
+
+// StaleSchedule is a scheduled workflow that has been failing for longer than the stale window.
+type StaleSchedule struct {
+	WorkflowID          int64   `json:"workflow_id"`
+	Workflow            string  `json:"workflow"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	FailingSince        string  `json:"failing_since"`
+	DaysFailing         int     `json:"days_failing"`
+	MinutesSpent        float64 `json:"minutes_spent"` // Run time of the failing streak
+	LastRunID           int64   `json:"last_run_id"`
+}
+
+// StaleBranchReport lists branches and schedules whose CI runs are likely wasted.
+type StaleBranchReport struct {
+	DefaultBranch    string           `json:"default_branch"`
+	StaleDays        int              `json:"stale_days"`
+	RunsScanned      int              `json:"runs_scanned"`
+	OldestRunScanned string           `json:"oldest_run_scanned,omitempty"`
+	Branches         []*StaleBranch   `json:"branches"`
+	Schedules        []*StaleSchedule `json:"schedules"`
+	Suggestions      []string         `json:"suggestions"`
+	Warnings         []string         `json:"warnings,omitempty"`
+}
+
+// staleBranchInput is everything buildStaleBranchReport needs from the API.
+type staleBranchInput struct {
+	repo          string // owner/repo, to skip runs from forks
+	defaultBranch string
+	runs          []*github.WorkflowRun // newest first
+	refs          map[string]bool       // existing branches and tags
+	merged        map[string]bool       // branches whose commits are all in the default branch
+	staleDays     int
+	now           time.Time
+}
+
+// GetStaleBranchReport scans up to maxRuns recent runs for branches whose latest run
+// failed more than staleDays ago, branches that keep running CI after being merged or
+// deleted, and scheduled workflows that have been failing for longer than staleDays.
+func (c *Client) GetStaleBranchReport(ctx context.Context, staleDays, maxRuns int) (*StaleBranchReport, error) {
+	if staleDays <= 0 {
+		staleDays = DefaultStaleDays
+	}
+	if maxRuns <= 0 {
+		maxRuns = DefaultStaleReportRuns
+	}
+	if maxRuns > maxStaleReportRuns {
+		maxRuns = maxStaleReportRuns
+	}
+
+	repository, _, err := c.gh.Repositories.Get(ctx, c.owner, c.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default branch: %w", Classify(err))
+	}
+
+	in := &staleBranchInput{
+		repo:          c.owner + "/" + c.repo,
+		defaultBranch: repository.GetDefaultBranch(),
+		refs:          make(map[string]bool),
+		merged:        make(map[string]bool),
+		staleDays:     staleDays,
+		now:           timeNow().UTC(),
+	}
+
+	opts := &github.ListWorkflowRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for len(in.runs) < maxRuns {
+		page, resp, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs: %w", Classify(err))
+		}
+		in.runs = append(in.runs, page.WorkflowRuns...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	if len(in.runs) > maxRuns {
+		in.runs = in.runs[:maxRuns]
+	}
+
+	if err := c.collectRefs(ctx, in.refs); err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	checked := 0
+	for _, branch := range candidateMergedBranches(in) {
+		if checked == maxStaleMergeChecks {
+			warnings = append(warnings, fmt.Sprintf("merge status checked for the %d most recently active branches only", maxStaleMergeChecks))
+			break
+		}
+		checked++
+		cmp, _, err := c.gh.Repositories.CompareCommits(ctx, c.owner, c.repo, in.defaultBranch, branch, &github.ListOptions{PerPage: 1})
+		if err != nil {
+			log.Debugf("Could not compare %s...%s: %v", in.defaultBranch, branch, err)
+			continue
+		}
+		// "behind" means every commit on the branch is in the default branch, which has
+		// moved on since; an "identical" branch was probably just created.
+		if cmp.GetStatus() == "behind" {
+			in.merged[branch] = true
+		}
+	}
+
+	report := buildStaleBranchReport(in)
+	report.Warnings = append(report.Warnings, warnings...)
+	return report, nil
+}
+
+// collectRefs adds the names of the repository's branches and tags to refs. Tags are
+// included because runs triggered by a tag push report the tag as their branch.
+func (c *Client) collectRefs(ctx context.Context, refs map[string]bool) error {
+	branchOpts := &github.BranchListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for i := 0; i < maxStaleReportRefsPages; i++ {
+		branches, resp, err := c.gh.Repositories.ListBranches(ctx, c.owner, c.repo, branchOpts)
+		if err != nil {
+			return fmt.Errorf("failed to list branches: %w", Classify(err))
+		}
+		for _, b := range branches {
+			refs[b.GetName()] = true
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		branchOpts.Page = resp.NextPage
+	}
+
+	tagOpts := &github.ListOptions{PerPage: 100}
+	for i := 0; i < maxStaleReportRefsPages; i++ {
+		tags, resp, err := c.gh.Repositories.ListTags(ctx, c.owner, c.repo, tagOpts)
+		if err != nil {
+			return fmt.Errorf("failed to list tags: %w", Classify(err))
+		}
+		for _, t := range tags {
+			refs[t.GetName()] = true
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		tagOpts.Page = resp.NextPage
+	}
+	return nil
+}
+
+// candidateMergedBranches returns existing non-default branches with runs in the stale
+// window, most recently active first: the only ones whose merge status matters.
+func candidateMergedBranches(in *staleBranchInput) []string {
+	cutoff := in.now.AddDate(0, 0, -in.staleDays)
+	seen := make(map[string]bool)
+	var branches []string
+	for _, run := range in.runs {
+		branch := run.GetHeadBranch()
+		if seen[branch] || !ownRun(in, run) || branch == in.defaultBranch || !in.refs[branch] {
+			continue
+		}
+		seen[branch] = true
+		if run.GetCreatedAt().After(cutoff) {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// ownRun reports whether run is on a branch of the repository itself rather than a fork.
+func ownRun(in *staleBranchInput, run *github.WorkflowRun) bool {
+	if run.GetHeadBranch() == "" {
+		return false
+	}
+	head := run.GetHeadRepository().GetFullName()
+	return head == "" || strings.EqualFold(head, in.repo)
+}
+
+func buildStaleBranchReport(in *staleBranchInput) *StaleBranchReport {
+	report := &StaleBranchReport{
+		DefaultBranch: in.defaultBranch,
+		StaleDays:     in.staleDays,
+		RunsScanned:   len(in.runs),
+		Branches:      []*StaleBranch{},
+		Schedules:     []*StaleSchedule{},
+		Suggestions:   []string{},
+	}
+	if len(in.runs) > 0 {
+		report.OldestRunScanned = formatTime(in.runs[len(in.runs)-1].CreatedAt)
+	}
+
+	cutoff := in.now.AddDate(0, 0, -in.staleDays)
+	days := func(t time.Time) int { return int(in.now.Sub(t).Hours() / 24) }
+
+	byBranch := make(map[string]*StaleBranch)
+	var order []string
+	workflows := make(map[string]map[string]bool)
+	for _, run := range in.runs {
+		branch := run.GetHeadBranch()
+		if !ownRun(in, run) || branch == in.defaultBranch {
+			continue
+		}
+		entry, ok := byBranch[branch]
+		if !ok {
+			entry = &StaleBranch{
+				Branch:           branch,
+				LastRunID:        run.GetID(),
+				LastWorkflow:     run.GetName(),
+				LastConclusion:   run.GetConclusion(),
+				LastRunAt:        formatTime(run.CreatedAt),
+				DaysSinceLastRun: days(run.GetCreatedAt().Time),
+			}
+			byBranch[branch] = entry
+			workflows[branch] = make(map[string]bool)
+			order = append(order, branch)
+		}
+		workflows[branch][run.GetName()] = true
+		if run.GetCreatedAt().After(cutoff) {
+			updated := run.GetUpdatedAt()
+			entry.RecentRuns++
+			entry.RecentMinutes += durationSeconds(run.RunStartedAt, &updated) / 60
+		}
+	}
+
+	for _, branch := range order {
+		entry := byBranch[branch]
+		switch {
+		case !in.refs[branch] && entry.RecentRuns > 0:
+			entry.Reason = StaleReasonDeleted
+		case in.merged[branch] && entry.RecentRuns > 0:
+			entry.Reason = StaleReasonMerged
+		case in.refs[branch] && entry.LastConclusion == "failure" && entry.DaysSinceLastRun > in.staleDays:
+			entry.Reason = StaleReasonFailure
+		default:
+			continue
+		}
+		entry.RecentMinutes = math.Round(entry.RecentMinutes*10) / 10
+		for name := range workflows[branch] {
+			entry.Workflows = append(entry.Workflows, name)
+		}
+		sort.Strings(entry.Workflows)
+		report.Branches = append(report.Branches, entry)
+		report.Suggestions = append(report.Suggestions, staleBranchSuggestion(entry, in.defaultBranch))
+	}
+
+	report.Schedules = staleSchedules(in, cutoff, days)
+	for _, sched := range report.Schedules {
+		report.Suggestions = append(report.Suggestions, fmt.Sprintf(
+			"Scheduled workflow %q has failed %d times in a row for %d days (%.1f minutes); fix it, prune its schedule, or disable it.",
+			sched.Workflow, sched.ConsecutiveFailures, sched.DaysFailing, sched.MinutesSpent))
+	}
+	return report
+}
+
+func staleBranchSuggestion(b *StaleBranch, defaultBranch string) string {
+	switch b.Reason {
+	case StaleReasonDeleted:
+		return fmt.Sprintf("Branch %s was deleted but ran %d runs (%.1f minutes) in the stale window; check which triggers (%s) still target it.",
+			b.Branch, b.RecentRuns, b.RecentMinutes, strings.Join(b.Workflows, ", "))
+	case StaleReasonMerged:
+		return fmt.Sprintf("Branch %s is merged into %s but ran %d runs (%.1f minutes) in the stale window; delete the branch.",
+			b.Branch, defaultBranch, b.RecentRuns, b.RecentMinutes)
+	default:
+		return fmt.Sprintf("Branch %s last ran %d days ago and failed (%s); delete the branch or fix and re-run it.",
+			b.Branch, b.DaysSinceLastRun, b.LastWorkflow)
+	}
+}
+
+// staleSchedules finds scheduled workflows whose runs have all failed since before cutoff.
+func staleSchedules(in *staleBranchInput, cutoff time.Time, days func(time.Time) int) []*StaleSchedule {
+	streaks := make(map[int64]*StaleSchedule)
+	since := make(map[int64]time.Time)
+	ended := make(map[int64]bool)
+	var order []int64
+	for _, run := range in.runs {
+		if run.GetEvent() != "schedule" || run.GetStatus() != "completed" {
+			continue
+		}
+		id := run.GetWorkflowID()
+		if ended[id] {
+			continue
+		}
+		if run.GetConclusion() != "failure" {
+			ended[id] = true
+			continue
+		}
+		sched, ok := streaks[id]
+		if !ok {
+			sched = &StaleSchedule{WorkflowID: id, Workflow: run.GetName(), LastRunID: run.GetID()}
+			streaks[id] = sched
+			order = append(order, id)
+		}
+		updated := run.GetUpdatedAt()
+		sched.ConsecutiveFailures++
+		sched.MinutesSpent += durationSeconds(run.RunStartedAt, &updated) / 60
+		sched.FailingSince = formatTime(run.CreatedAt)
+		sched.DaysFailing = days(run.GetCreatedAt().Time)
+		since[id] = run.GetCreatedAt().Time
+	}
+
+	result := []*StaleSchedule{}
+	for _, id := range order {
+		if !since[id].Before(cutoff) {
+			continue
+		}
+		sched := streaks[id]
+		sched.MinutesSpent = math.Round(sched.MinutesSpent*10) / 10
+		result = append(result, sched)
+	}
+	return result
+}
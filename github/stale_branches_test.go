@@ -0,0 +1,84 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStaleBranchReport(t *testing.T) {
+	now := time.Date(2026, 4, 30, 12, 0, 0, 0, time.UTC)
+	run := func(id int64, name, branch, event, conclusion string, age time.Duration) *githubapi.WorkflowRun {
+		created := now.Add(-age)
+		return &githubapi.WorkflowRun{
+			ID:           githubapi.Ptr(id),
+			Name:         githubapi.Ptr(name),
+			WorkflowID:   githubapi.Ptr(id % 10),
+			HeadBranch:   githubapi.Ptr(branch),
+			Event:        githubapi.Ptr(event),
+			Status:       githubapi.Ptr("completed"),
+			Conclusion:   githubapi.Ptr(conclusion),
+			CreatedAt:    &githubapi.Timestamp{Time: created},
+			RunStartedAt: &githubapi.Timestamp{Time: created},
+			UpdatedAt:    &githubapi.Timestamp{Time: created.Add(6 * time.Minute)},
+		}
+	}
+	day := 24 * time.Hour
+
+	fork := run(91, "CI", "patch-1", "pull_request", "failure", 40*day)
+	fork.HeadRepository = &githubapi.Repository{FullName: githubapi.Ptr("someone/widget")}
+
+	in := &staleBranchInput{
+		repo:          "acme/widget",
+		defaultBranch: "main",
+		runs: []*githubapi.WorkflowRun{
+			run(81, "CI", "merged-feature", "push", "success", 1*day),
+			run(71, "Nightly", "main", "schedule", "failure", 2*day),
+			run(61, "Lint", "gone", "push", "success", 3*day),
+			run(51, "CI", "main", "push", "success", 4*day),
+			run(41, "Nightly", "main", "schedule", "failure", 20*day),
+			run(31, "CI", "abandoned", "push", "failure", 30*day),
+			run(21, "CI", "active", "push", "failure", 31*day),
+			fork,
+			run(11, "Nightly", "main", "schedule", "success", 35*day),
+		},
+		refs:      map[string]bool{"main": true, "merged-feature": true, "abandoned": true, "active": true},
+		merged:    map[string]bool{"merged-feature": true},
+		staleDays: 14,
+		now:       now,
+	}
+	in.runs = append([]*githubapi.WorkflowRun{run(101, "CI", "active", "push", "success", 2*time.Hour)}, in.runs...)
+
+	report := buildStaleBranchReport(in)
+	require.Len(t, report.Branches, 3)
+
+	assert.Equal(t, "merged-feature", report.Branches[0].Branch)
+	assert.Equal(t, StaleReasonMerged, report.Branches[0].Reason)
+	assert.Equal(t, 1, report.Branches[0].RecentRuns)
+	assert.Equal(t, 6.0, report.Branches[0].RecentMinutes)
+
+	assert.Equal(t, "gone", report.Branches[1].Branch)
+	assert.Equal(t, StaleReasonDeleted, report.Branches[1].Reason)
+	assert.Equal(t, []string{"Lint"}, report.Branches[1].Workflows)
+
+	assert.Equal(t, "abandoned", report.Branches[2].Branch)
+	assert.Equal(t, StaleReasonFailure, report.Branches[2].Reason)
+	assert.Equal(t, 30, report.Branches[2].DaysSinceLastRun)
+
+	require.Len(t, report.Schedules, 1)
+	assert.Equal(t, "Nightly", report.Schedules[0].Workflow)
+	assert.Equal(t, 2, report.Schedules[0].ConsecutiveFailures)
+	assert.Equal(t, 20, report.Schedules[0].DaysFailing)
+	assert.Equal(t, int64(71), report.Schedules[0].LastRunID)
+	assert.Equal(t, 12.0, report.Schedules[0].MinutesSpent)
+
+	assert.Len(t, report.Suggestions, 4)
+	assert.Contains(t, report.Suggestions[0], "delete the branch")
+	assert.Contains(t, report.Suggestions[3], "disable it")
+	assert.Equal(t, "2026-03-26T12:00:00Z", report.OldestRunScanned)
+
+	assert.Equal(t, []string{"active", "merged-feature"}, candidateMergedBranches(in))
+}
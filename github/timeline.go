@@ -0,0 +1,160 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Timeline event kinds, in the order they occur for a single run.
+const (
+	EventRunQueued     = "run_queued"
+	EventRunStarted    = "run_started"
+	EventJobQueued     = "job_queued"
+	EventJobStarted    = "job_started"
+	EventStepStarted   = "step_started"
+	EventStepCompleted = "step_completed"
+	EventJobCompleted  = "job_completed"
+	EventRunCompleted  = "run_completed"
+)
+
+// TimelineEvent is a point in a run's life. OffsetSeconds is measured from when the run
+// was queued; completion events carry the duration of what completed and, for job starts,
+// how long the job waited for a runner.
+type TimelineEvent struct {
+	Time            string  `json:"time"`
+	OffsetSeconds   float64 `json:"offset_seconds"`
+	Event           string  `json:"event"`
+	Job             string  `json:"job,omitempty"`
+	JobID           int64   `json:"job_id,omitempty"`
+	Step            string  `json:"step,omitempty"`
+	Conclusion      string  `json:"conclusion,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+	QueuedSeconds   float64 `json:"queued_seconds,omitempty"`
+}
+
+// RunTimeline is the ordered sequence of events for a workflow run.
+type RunTimeline struct {
+	RunID           int64            `json:"run_id"`
+	Name            string           `json:"name"`
+	Status          string           `json:"status"`
+	Conclusion      string           `json:"conclusion,omitempty"`
+	Attempt         int              `json:"attempt,omitempty"`
+	QueuedSeconds   float64          `json:"queued_seconds"`
+	DurationSeconds float64          `json:"duration_seconds,omitempty"`
+	SlowestJob      string           `json:"slowest_job,omitempty"`
+	SlowestStep     string           `json:"slowest_step,omitempty"`
+	Events          []*TimelineEvent `json:"events"`
+}
+
+// GetRunTimeline returns the events of the latest attempt of a run: when it was queued,
+// when each job and (optionally) each step started and ended, and when the run completed.
+func (c *Client) GetRunTimeline(ctx context.Context, runID int64, includeSteps bool) (*RunTimeline, error) {
+	run, _, err := c.gh.Actions.GetWorkflowRunByID(ctx, c.owner, c.repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run %d: %w", runID, Classify(err))
+	}
+
+	var jobs []*github.WorkflowJob
+	opts := &github.ListWorkflowJobsOptions{Filter: "latest", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := c.gh.Actions.ListWorkflowJobs(ctx, c.owner, c.repo, runID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jobs for run %d: %w", runID, Classify(err))
+		}
+		jobs = append(jobs, page.Jobs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return buildRunTimeline(run, jobs, includeSteps), nil
+}
+
+// buildRunTimeline orders the events of run and its jobs by time. At the same instant,
+// endings come before beginnings and the run's completion comes last.
+func buildRunTimeline(run *github.WorkflowRun, jobs []*github.WorkflowJob, includeSteps bool) *RunTimeline {
+	t := &RunTimeline{
+		RunID:      run.GetID(),
+		Name:       run.GetName(),
+		Status:     run.GetStatus(),
+		Conclusion: run.GetConclusion(),
+		Attempt:    run.GetRunAttempt(),
+	}
+
+	origin := run.CreatedAt
+	add := func(ts *github.Timestamp, e *TimelineEvent) {
+		if ts == nil || ts.IsZero() {
+			return
+		}
+		e.Time = formatTime(ts)
+		e.OffsetSeconds = durationSeconds(origin, ts)
+		t.Events = append(t.Events, e)
+	}
+
+	add(run.CreatedAt, &TimelineEvent{Event: EventRunQueued})
+	if run.RunStartedAt != nil && !run.RunStartedAt.Equal(run.GetCreatedAt()) {
+		add(run.RunStartedAt, &TimelineEvent{Event: EventRunStarted})
+	}
+	t.QueuedSeconds = durationSeconds(run.CreatedAt, run.RunStartedAt)
+
+	var slowestJob, slowestStep float64
+	for _, job := range jobs {
+		name := job.GetName()
+		add(job.CreatedAt, &TimelineEvent{Event: EventJobQueued, Job: name, JobID: job.GetID()})
+		add(job.StartedAt, &TimelineEvent{Event: EventJobStarted, Job: name, JobID: job.GetID(), QueuedSeconds: durationSeconds(job.CreatedAt, job.StartedAt)})
+
+		if includeSteps {
+			for _, step := range job.Steps {
+				add(step.StartedAt, &TimelineEvent{Event: EventStepStarted, Job: name, JobID: job.GetID(), Step: step.GetName()})
+				d := durationSeconds(step.StartedAt, step.CompletedAt)
+				add(step.CompletedAt, &TimelineEvent{Event: EventStepCompleted, Job: name, JobID: job.GetID(), Step: step.GetName(), Conclusion: step.GetConclusion(), DurationSeconds: d})
+				if d > slowestStep {
+					slowestStep = d
+					t.SlowestStep = name + " / " + step.GetName()
+				}
+			}
+		}
+
+		if job.GetStatus() == "completed" {
+			d := durationSeconds(job.StartedAt, job.CompletedAt)
+			add(job.CompletedAt, &TimelineEvent{Event: EventJobCompleted, Job: name, JobID: job.GetID(), Conclusion: job.GetConclusion(), DurationSeconds: d})
+			if d > slowestJob {
+				slowestJob = d
+				t.SlowestJob = name
+			}
+		}
+	}
+
+	if run.GetStatus() == "completed" {
+		start := run.RunStartedAt
+		if start == nil {
+			start = run.CreatedAt
+		}
+		t.DurationSeconds = durationSeconds(start, run.UpdatedAt)
+		add(run.UpdatedAt, &TimelineEvent{Event: EventRunCompleted, Conclusion: run.GetConclusion(), DurationSeconds: t.DurationSeconds})
+	}
+
+	sort.SliceStable(t.Events, func(i, j int) bool {
+		a, b := t.Events[i], t.Events[j]
+		if a.OffsetSeconds != b.OffsetSeconds {
+			return a.OffsetSeconds < b.OffsetSeconds
+		}
+		return eventRank(a.Event) < eventRank(b.Event)
+	})
+	return t
+}
+
+// eventRank orders events that happen at the same instant.
+func eventRank(event string) int {
+	switch event {
+	case EventStepCompleted, EventJobCompleted:
+		return 0
+	case EventRunCompleted:
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,104 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRunTimeline(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/110", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": 110, "name": "CI", "status": "completed", "conclusion": "failure", "run_attempt": 1,
+			"created_at": "2026-04-20T10:00:00Z", "run_started_at": "2026-04-20T10:00:30Z",
+			"updated_at": "2026-04-20T10:06:00Z"
+		}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/110/jobs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "latest", r.URL.Query().Get("filter"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total_count": 2,
+			"jobs": [
+				{"id": 2, "name": "test", "status": "completed", "conclusion": "failure",
+				 "created_at": "2026-04-20T10:02:00Z", "started_at": "2026-04-20T10:03:00Z", "completed_at": "2026-04-20T10:06:00Z",
+				 "steps": [
+					{"name": "Run tests", "number": 1, "status": "completed", "conclusion": "failure",
+					 "started_at": "2026-04-20T10:03:00Z", "completed_at": "2026-04-20T10:06:00Z"}
+				 ]},
+				{"id": 1, "name": "build", "status": "completed", "conclusion": "success",
+				 "created_at": "2026-04-20T10:00:30Z", "started_at": "2026-04-20T10:00:40Z", "completed_at": "2026-04-20T10:02:00Z",
+				 "steps": [
+					{"name": "Checkout", "number": 1, "status": "completed", "conclusion": "success",
+					 "started_at": "2026-04-20T10:00:40Z", "completed_at": "2026-04-20T10:01:00Z"},
+					{"name": "Build", "number": 2, "status": "completed", "conclusion": "success",
+					 "started_at": "2026-04-20T10:01:00Z", "completed_at": "2026-04-20T10:02:00Z"}
+				 ]}
+			]
+		}`))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	timeline, err := client.GetRunTimeline(context.Background(), 110, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, 30.0, timeline.QueuedSeconds)
+	assert.Equal(t, 330.0, timeline.DurationSeconds)
+	assert.Equal(t, "test", timeline.SlowestJob)
+	assert.Equal(t, "test / Run tests", timeline.SlowestStep)
+
+	var kinds []string
+	for _, e := range timeline.Events {
+		kinds = append(kinds, e.Event+":"+e.Job+":"+e.Step)
+	}
+	assert.Equal(t, []string{
+		"run_queued::",
+		"run_started::",
+		"job_queued:build:",
+		"job_started:build:",
+		"step_started:build:Checkout",
+		"step_completed:build:Checkout",
+		"step_started:build:Build",
+		"step_completed:build:Build",
+		"job_completed:build:",
+		"job_queued:test:",
+		"job_started:test:",
+		"step_started:test:Run tests",
+		"step_completed:test:Run tests",
+		"job_completed:test:",
+		"run_completed::",
+	}, kinds)
+
+	jobStarted := timeline.Events[3]
+	assert.Equal(t, 40.0, jobStarted.OffsetSeconds)
+	assert.Equal(t, 10.0, jobStarted.QueuedSeconds)
+	assert.Equal(t, "2026-04-20T10:00:40Z", jobStarted.Time)
+	assert.Equal(t, 180.0, timeline.Events[13].DurationSeconds)
+
+	timeline, err = client.GetRunTimeline(context.Background(), 110, false)
+	require.NoError(t, err)
+	for _, e := range timeline.Events {
+		assert.Empty(t, e.Step)
+	}
+}
@@ -0,0 +1,259 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultJobTimeoutMinutes is the limit GitHub applies to jobs without timeout-minutes.
+const defaultJobTimeoutMinutes = 360
+
+// JobTimeout compares a job's recent durations with its timeout-minutes.
+type JobTimeout struct {
+	Job                     string  `json:"job"`
+	TimeoutMinutes          int     `json:"timeout_minutes"`
+	TimeoutSource           string  `json:"timeout_source"` // workflow, default, or expression
+	Runs                    int     `json:"runs"`
+	TimedOut                int     `json:"timed_out"`
+	TimedOutRunIDs          []int64 `json:"timed_out_run_ids,omitempty"`
+	Successes               int     `json:"successes"`
+	MedianSuccessMinutes    float64 `json:"median_success_minutes,omitempty"`
+	MaxSuccessMinutes       float64 `json:"max_success_minutes,omitempty"`
+	MaxUsagePercent         float64 `json:"max_usage_percent,omitempty"` // Slowest success as a share of the limit
+	SuggestedTimeoutMinutes int     `json:"suggested_timeout_minutes,omitempty"`
+	Suggestion              string  `json:"suggestion,omitempty"`
+}
+
+// TimeoutReport is the timeout analysis of a workflow's recent runs.
+type TimeoutReport struct {
+	WorkflowID   int64         `json:"workflow_id"`
+	Workflow     string        `json:"workflow"`
+	Path         string        `json:"path,omitempty"`
+	Branch       string        `json:"branch,omitempty"`
+	RunsAnalyzed int           `json:"runs_analyzed"`
+	Jobs         []*JobTimeout `json:"jobs"` // Timed-out jobs first, then by usage
+	Notes        []string      `json:"notes,omitempty"`
+}
+
+// jobTimeoutLimit is a job's timeout-minutes as declared in the workflow file.
+type jobTimeoutLimit struct {
+	minutes int
+	source  string
+}
+
+// GetTimeoutReport inspects the last limit completed runs of a workflow (default 20),
+// finds jobs that hit their timeout-minutes (or GitHub's 6-hour default), reports how close
+// successful runs of each job get to its limit, and suggests adjusted timeouts.
+func (c *Client) GetTimeoutReport(ctx context.Context, workflow, branch string, limit int) (*TimeoutReport, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	workflowID, workflowName, err := c.ResolveWorkflowID(ctx, workflow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve workflow %q: %w", workflow, err)
+	}
+	report := &TimeoutReport{WorkflowID: workflowID, Workflow: workflowName, Branch: branch, Jobs: []*JobTimeout{}}
+
+	var limits map[string]*jobTimeoutLimit
+	wf, _, err := c.gh.Actions.GetWorkflowByID(ctx, c.owner, c.repo, workflowID)
+	if err != nil {
+		report.Notes = append(report.Notes, fmt.Sprintf("could not get workflow %d: %v; assuming the default timeout", workflowID, Classify(err)))
+	} else {
+		report.Path = wf.GetPath()
+		data, err := c.GetWorkflowFile(ctx, report.Path, branch)
+		if err == nil {
+			limits, err = parseJobTimeouts(data)
+		}
+		if err != nil {
+			report.Notes = append(report.Notes, fmt.Sprintf("could not read timeouts from %s: %v; assuming the default timeout", report.Path, err))
+		}
+	}
+
+	runs, err := c.listWorkflowRunsForTiming(ctx, workflowID, branch, limit)
+	if err != nil {
+		return nil, err
+	}
+	jobsByRun := make(map[int64][]*Job)
+	var runIDs []int64
+	for _, run := range runs {
+		if run.Status != "completed" {
+			continue
+		}
+		if len(runIDs) == limit {
+			break
+		}
+		jobs, err := c.GetWorkflowJobs(ctx, run.ID, "", 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jobs for run %d: %w", run.ID, err)
+		}
+		jobsByRun[run.ID] = jobs
+		runIDs = append(runIDs, run.ID)
+	}
+	report.RunsAnalyzed = len(runIDs)
+	report.Jobs = buildJobTimeouts(runIDs, jobsByRun, limits)
+	return report, nil
+}
+
+// buildJobTimeouts aggregates job durations across runs (newest first) against the limits
+// parsed from the workflow file.
+func buildJobTimeouts(runIDs []int64, jobsByRun map[int64][]*Job, limits map[string]*jobTimeoutLimit) []*JobTimeout {
+	byName := make(map[string]*JobTimeout)
+	successes := make(map[string][]float64)
+	var result []*JobTimeout
+	for _, runID := range runIDs {
+		for _, job := range jobsByRun[runID] {
+			if job.Status != "completed" || job.Conclusion == "skipped" {
+				continue
+			}
+			jt, ok := byName[job.Name]
+			if !ok {
+				jt = &JobTimeout{Job: job.Name, TimeoutMinutes: defaultJobTimeoutMinutes, TimeoutSource: "default"}
+				if l := lookupJobTimeout(limits, job.Name); l != nil {
+					jt.TimeoutMinutes, jt.TimeoutSource = l.minutes, l.source
+				}
+				byName[job.Name] = jt
+				result = append(result, jt)
+			}
+			jt.Runs++
+			switch {
+			case hitTimeout(job, jt.TimeoutMinutes):
+				jt.TimedOut++
+				jt.TimedOutRunIDs = append(jt.TimedOutRunIDs, runID)
+			case job.Conclusion == "success" && job.DurationSeconds > 0:
+				jt.Successes++
+				successes[job.Name] = append(successes[job.Name], job.DurationSeconds)
+			}
+		}
+	}
+
+	for _, jt := range result {
+		if durations := successes[jt.Job]; len(durations) > 0 {
+			stats := timingStatsFromDurations(durations)
+			jt.MedianSuccessMinutes = roundMinutes(stats.MedianSeconds)
+			jt.MaxSuccessMinutes = roundMinutes(stats.MaxSeconds)
+			jt.MaxUsagePercent = math.Round(stats.MaxSeconds / 60 / float64(jt.TimeoutMinutes) * 100)
+		}
+		jt.SuggestedTimeoutMinutes, jt.Suggestion = suggestJobTimeout(jt)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].TimedOut != result[j].TimedOut {
+			return result[i].TimedOut > result[j].TimedOut
+		}
+		return result[i].MaxUsagePercent > result[j].MaxUsagePercent
+	})
+	return result
+}
+
+// hitTimeout reports whether a job ended because it ran out of time: GitHub reports jobs
+// stopped at timeout-minutes as timed_out or cancelled, having run for the whole limit.
+func hitTimeout(job *Job, limitMinutes int) bool {
+	switch job.Conclusion {
+	case "timed_out":
+		return true
+	case "cancelled", "failure":
+		return job.DurationSeconds >= float64(limitMinutes*60-60)
+	}
+	return false
+}
+
+// suggestJobTimeout proposes a timeout-minutes for a job from its slowest successful run.
+func suggestJobTimeout(jt *JobTimeout) (int, string) {
+	if jt.Successes == 0 {
+		if jt.TimedOut > 0 {
+			return 0, fmt.Sprintf("every analyzed run that finished hit the %d-minute limit; check whether the job hangs before raising it", jt.TimeoutMinutes)
+		}
+		return 0, ""
+	}
+	usage := jt.MaxUsagePercent
+	generous := roundUpMinutes(jt.MaxSuccessMinutes * 1.5)
+	switch {
+	case jt.TimedOut > 0 && usage >= 80:
+		return generous, fmt.Sprintf("%d of %d runs hit the %d-minute limit and successful runs take up to %.0f%% of it; raise timeout-minutes to %d or speed the job up",
+			jt.TimedOut, jt.Runs, jt.TimeoutMinutes, usage, generous)
+	case jt.TimedOut > 0:
+		return 0, fmt.Sprintf("%d of %d runs hit the %d-minute limit although successful runs finish within %.0f minutes; the timeouts look like hangs rather than slow runs",
+			jt.TimedOut, jt.Runs, jt.TimeoutMinutes, jt.MaxSuccessMinutes)
+	case usage >= 80:
+		return generous, fmt.Sprintf("successful runs take up to %.0f%% of the %d-minute limit; raise timeout-minutes to %d before slow runs start failing",
+			usage, jt.TimeoutMinutes, generous)
+	case jt.TimeoutSource == "default":
+		suggested := roundUpMinutes(math.Max(jt.MaxSuccessMinutes*2, 10))
+		return suggested, fmt.Sprintf("no timeout-minutes is set, so a hung job runs for 6 hours; successful runs finish within %.0f minutes, so set timeout-minutes: %d",
+			jt.MaxSuccessMinutes, suggested)
+	case usage < 25 && jt.TimeoutMinutes > 30:
+		suggested := roundUpMinutes(math.Max(jt.MaxSuccessMinutes*2, 10))
+		return suggested, fmt.Sprintf("successful runs use at most %.0f%% of the %d-minute limit; lower timeout-minutes to %d so hung jobs fail sooner",
+			usage, jt.TimeoutMinutes, suggested)
+	}
+	return 0, ""
+}
+
+// roundMinutes converts seconds to minutes rounded to one decimal.
+func roundMinutes(seconds float64) float64 {
+	return math.Round(seconds/6) / 10
+}
+
+// roundUpMinutes rounds minutes up to a multiple of five.
+func roundUpMinutes(minutes float64) int {
+	return int(math.Ceil(minutes/5)) * 5
+}
+
+// lookupJobTimeout finds the limit of a job by its display name. Matrix legs ("test
+// (ubuntu, 20)") and reusable workflow jobs ("build / compile") use the caller's limit.
+func lookupJobTimeout(limits map[string]*jobTimeoutLimit, name string) *jobTimeoutLimit {
+	if l, ok := limits[name]; ok {
+		return l
+	}
+	if i := strings.Index(name, " / "); i > 0 {
+		if l, ok := limits[name[:i]]; ok {
+			return l
+		}
+	}
+	if i := strings.LastIndex(name, " ("); i > 0 && strings.HasSuffix(name, ")") {
+		return lookupJobTimeout(limits, name[:i])
+	}
+	return nil
+}
+
+// parseJobTimeouts reads each job's timeout-minutes, keyed by the job ID and, when set,
+// by its name.
+func parseJobTimeouts(data []byte) (map[string]*jobTimeoutLimit, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("workflow must be a YAML mapping")
+	}
+	limits := make(map[string]*jobTimeoutLimit)
+	_, jobs := yamlMappingValue(doc.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return limits, nil
+	}
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		id, job := jobs.Content[i].Value, jobs.Content[i+1]
+		if job.Kind != yaml.MappingNode {
+			continue
+		}
+		limit := &jobTimeoutLimit{minutes: defaultJobTimeoutMinutes, source: "default"}
+		if _, v := yamlMappingValue(job, "timeout-minutes"); v != nil {
+			if minutes, err := strconv.Atoi(v.Value); err == nil && minutes > 0 {
+				limit.minutes, limit.source = minutes, "workflow"
+			} else {
+				limit.source = "expression"
+			}
+		}
+		limits[id] = limit
+		if _, name := yamlMappingValue(job, "name"); name != nil && name.Value != "" {
+			limits[name.Value] = limit
+		}
+	}
+	return limits, nil
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildJobTimeouts(t *testing.T) {
+	limits, err := parseJobTimeouts([]byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    timeout-minutes: 20
+  test:
+    name: Test
+    runs-on: ubuntu-latest
+    timeout-minutes: 60
+  e2e:
+    runs-on: ubuntu-latest
+    timeout-minutes: 30
+  lint:
+    runs-on: ubuntu-latest
+  deploy:
+    runs-on: ubuntu-latest
+    timeout-minutes: ${{ inputs.timeout }}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &jobTimeoutLimit{minutes: 60, source: "workflow"}, limits["Test"])
+	assert.Equal(t, &jobTimeoutLimit{minutes: 360, source: "expression"}, limits["deploy"])
+
+	job := func(name, conclusion string, minutes float64) *Job {
+		return &Job{Name: name, Status: "completed", Conclusion: conclusion, DurationSeconds: minutes * 60}
+	}
+	jobsByRun := map[int64][]*Job{
+		3: {job("build", "success", 18), job("Test (ubuntu)", "success", 5), job("e2e", "cancelled", 30), job("lint", "success", 2)},
+		2: {job("build", "timed_out", 20), job("Test (ubuntu)", "success", 6), job("e2e", "success", 8), job("lint", "success", 3)},
+		1: {job("build", "success", 17), job("Test (ubuntu)", "cancelled", 1), job("e2e", "success", 9), job("deploy", "skipped", 0)},
+	}
+	jobs := buildJobTimeouts([]int64{3, 2, 1}, jobsByRun, limits)
+	require.Len(t, jobs, 4)
+
+	assert.Equal(t, "build", jobs[0].Job)
+	assert.Equal(t, 1, jobs[0].TimedOut)
+	assert.Equal(t, []int64{2}, jobs[0].TimedOutRunIDs)
+	assert.Equal(t, float64(90), jobs[0].MaxUsagePercent)
+	assert.Equal(t, 30, jobs[0].SuggestedTimeoutMinutes)
+	assert.Contains(t, jobs[0].Suggestion, "1 of 3 runs hit the 20-minute limit")
+
+	assert.Equal(t, "e2e", jobs[1].Job)
+	assert.Equal(t, []int64{3}, jobs[1].TimedOutRunIDs)
+	assert.Zero(t, jobs[1].SuggestedTimeoutMinutes)
+	assert.Contains(t, jobs[1].Suggestion, "look like hangs")
+
+	assert.Equal(t, "Test (ubuntu)", jobs[2].Job)
+	assert.Equal(t, 60, jobs[2].TimeoutMinutes)
+	assert.Equal(t, 0, jobs[2].TimedOut)
+	assert.Equal(t, 5.5, jobs[2].MedianSuccessMinutes)
+	assert.Equal(t, 15, jobs[2].SuggestedTimeoutMinutes)
+	assert.Contains(t, jobs[2].Suggestion, "lower timeout-minutes to 15")
+
+	assert.Equal(t, "lint", jobs[3].Job)
+	assert.Equal(t, "default", jobs[3].TimeoutSource)
+	assert.Equal(t, 10, jobs[3].SuggestedTimeoutMinutes)
+	assert.Contains(t, jobs[3].Suggestion, "no timeout-minutes is set")
+}
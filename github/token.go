@@ -0,0 +1,255 @@
+package github
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TokenSource holds the API token shared by all clients and re-resolves it when GitHub
+// rejects it, so a rotated token is picked up without restarting the server. Extra tokens
+// added with AddTokens form a pool: when the token in use runs out of its rate limit,
+// requests move on to one that has calls left.
+type TokenSource struct {
+	mu      sync.Mutex
+	tokens  []*pooledToken // tokens[0] is the primary token, the one refresh replaces
+	active  int
+	refresh func() (string, error)
+}
+
+// pooledToken is a token with the core rate limit GitHub last reported for it.
+type pooledToken struct {
+	token     string
+	observed  bool
+	limit     int
+	remaining int
+	reset     time.Time
+	rejected  bool // Refused with 401
+}
+
+// TokenStatus describes a pooled token without revealing it.
+type TokenStatus struct {
+	Token     string `json:"token"` // Last four characters
+	Active    bool   `json:"active"`
+	Limit     int    `json:"limit,omitempty"`
+	Remaining *int   `json:"remaining,omitempty"` // Unknown until a response reports it
+	ResetAt   string `json:"reset_at,omitempty"`
+	Rejected  bool   `json:"rejected,omitempty"`
+}
+
+// NewTokenSource returns a source for token. refresh re-reads the token from wherever it
+// came from; nil means the token cannot be refreshed.
+func NewTokenSource(token string, refresh func() (string, error)) *TokenSource {
+	return &TokenSource{tokens: []*pooledToken{{token: token}}, refresh: refresh}
+}
+
+// AddTokens adds tokens to the pool, skipping empty ones and ones already in it.
+func (t *TokenSource) AddTokens(tokens ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, token := range tokens {
+		if token != "" && t.find(token) == nil {
+			t.tokens = append(t.tokens, &pooledToken{token: token})
+		}
+	}
+}
+
+// Token returns the token to use for the next request: the one in use, unless it has
+// exhausted its rate limit and another token has calls left.
+func (t *TokenSource) Token() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tokens[t.pick(time.Now())].token
+}
+
+// pick returns the index of the token to use, switching the active token when it is
+// exhausted or rejected. With every token exhausted, the one whose limit resets first
+// is used.
+func (t *TokenSource) pick(now time.Time) int {
+	if t.tokens[t.active].usable(now) {
+		return t.active
+	}
+	for i := 1; i < len(t.tokens); i++ {
+		next := (t.active + i) % len(t.tokens)
+		if t.tokens[next].usable(now) {
+			log.Infof("GitHub token ...%s is rate limited or rejected; switching to token ...%s", tokenSuffix(t.tokens[t.active].token), tokenSuffix(t.tokens[next].token))
+			t.active = next
+			return next
+		}
+	}
+	soonest := t.active
+	for i, pt := range t.tokens {
+		if !pt.rejected && (t.tokens[soonest].rejected || pt.reset.Before(t.tokens[soonest].reset)) {
+			soonest = i
+		}
+	}
+	t.active = soonest
+	return soonest
+}
+
+// usable reports whether a token is accepted and has calls left, as far as is known.
+func (pt *pooledToken) usable(now time.Time) bool {
+	if pt.rejected {
+		return false
+	}
+	return !pt.observed || pt.remaining > 0 || !now.Before(pt.reset)
+}
+
+// find returns the pool entry of token, or nil.
+func (t *TokenSource) find(token string) *pooledToken {
+	for _, pt := range t.tokens {
+		if pt.token == token {
+			return pt
+		}
+	}
+	return nil
+}
+
+// observe records the core rate limit a response reports for token.
+func (t *TokenSource) observe(token string, resp *http.Response) {
+	if resource := resp.Header.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	pt := t.find(token)
+	if pt == nil {
+		return
+	}
+	pt.observed = true
+	pt.remaining = remaining
+	pt.limit, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		pt.reset = time.Unix(reset, 0)
+	}
+}
+
+// Refresh is called after rejected was refused with 401. It returns the token to retry
+// with and whether a retry is worthwhile: either another request already refreshed the
+// token, refresh produced a different one, or the pool has another token to use.
+func (t *TokenSource) Refresh(rejected string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pt := t.find(rejected)
+	if pt == nil {
+		return t.tokens[t.pick(time.Now())].token, true
+	}
+	if pt == t.tokens[0] && t.refresh != nil {
+		token, err := t.refresh()
+		switch {
+		case err != nil:
+			log.Debugf("Could not refresh GitHub token: %v", err)
+		case token != "" && token != rejected:
+			log.Infof("GitHub token was rejected; retrying with a refreshed token")
+			*pt = pooledToken{token: token}
+			return token, true
+		}
+	}
+
+	// Only the primary token is refreshed, on its next rejection.
+	pt.rejected = true
+	if len(t.tokens) > 1 {
+		log.Warnf("GitHub rejected token ...%s; using the other configured tokens", tokenSuffix(rejected))
+	}
+	token := t.tokens[t.pick(time.Now())].token
+	return token, token != rejected
+}
+
+// Status returns the tokens in the pool with their last reported core rate limit.
+func (t *TokenSource) Status() []TokenStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	statuses := make([]TokenStatus, 0, len(t.tokens))
+	for i, pt := range t.tokens {
+		status := TokenStatus{Token: tokenSuffix(pt.token), Active: i == t.active, Rejected: pt.rejected}
+		if pt.observed {
+			remaining := pt.remaining
+			status.Limit, status.Remaining = pt.limit, &remaining
+			status.ResetAt = pt.reset.UTC().Format(time.RFC3339)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// tokenSuffix returns the last four characters of a token, for logs and status.
+func tokenSuffix(token string) string {
+	if len(token) <= 4 {
+		return token
+	}
+	return token[len(token)-4:]
+}
+
+// tokenTransport authenticates requests with the current token and retries a request once
+// with another token when GitHub answers 401, or when the token's rate limit is exhausted
+// and the pool has a token with calls left.
+type tokenTransport struct {
+	source *TokenSource
+	base   http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := t.source.Token()
+	resp, err := t.base.RoundTrip(authorize(req, token))
+	if err != nil {
+		return resp, err
+	}
+	t.source.observe(token, resp)
+
+	// The body has already been consumed; only retry when it can be replayed.
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	var newToken string
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		var ok bool
+		if newToken, ok = t.source.Refresh(token); !ok {
+			return resp, nil
+		}
+	case isRateLimitResponse(resp):
+		if newToken = t.source.Token(); newToken == token {
+			return resp, nil
+		}
+	default:
+		return resp, nil
+	}
+
+	retry := authorize(req, newToken)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	resp.Body.Close()
+	resp, err = t.base.RoundTrip(retry)
+	if err == nil {
+		t.source.observe(newToken, resp)
+	}
+	return resp, err
+}
+
+// isRateLimitResponse reports whether resp refused a request because the token's primary
+// rate limit is exhausted.
+func isRateLimitResponse(resp *http.Response) bool {
+	return (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// authorize returns a copy of req carrying token, as RoundTrippers must not modify the
+// original request.
+func authorize(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenTransport_RetriesOnceWithRefreshedToken(t *testing.T) {
+	var auths []string
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auths = append(auths, r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	refreshes := 0
+	source := NewTokenSource("expired", func() (string, error) {
+		refreshes++
+		return "fresh", nil
+	})
+	client, err := NewClientWithOptions(ClientOptions{
+		Owner:       "octo",
+		Repo:        "hello",
+		APIBaseURL:  ts.URL + "/",
+		TokenSource: source,
+	})
+	require.NoError(t, err)
+
+	_, err = client.gh.Actions.CreateWorkflowDispatchEventByID(context.Background(), "octo", "hello", 1, githubapi.CreateWorkflowDispatchEventRequest{Ref: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer expired", "Bearer fresh"}, auths)
+	assert.Equal(t, bodies[0], bodies[1], "request body is replayed on retry")
+	assert.Equal(t, 1, refreshes)
+	assert.Equal(t, "fresh", source.Token())
+}
+
+func TestTokenTransport_NoRetryWithoutNewToken(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	for _, refresh := range []func() (string, error){
+		nil,
+		func() (string, error) { return "expired", nil },
+		func() (string, error) { return "", errors.New("keychain locked") },
+	} {
+		requests = 0
+		client, err := NewClientWithOptions(ClientOptions{
+			Owner:       "octo",
+			Repo:        "hello",
+			APIBaseURL:  ts.URL + "/",
+			TokenSource: NewTokenSource("expired", refresh),
+		})
+		require.NoError(t, err)
+
+		_, _, err = client.gh.Actions.ListWorkflows(context.Background(), "octo", "hello", nil)
+		require.Error(t, err)
+		assert.Equal(t, 1, requests)
+	}
+}
+
+func TestTokenTransport_RotatesWhenRateLimited(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	var auths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auths = append(auths, r.Header.Get("Authorization"))
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Reset", reset)
+		if r.Header.Get("Authorization") == "Bearer primary" {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		_, _ = w.Write([]byte(`{"total_count":0,"workflows":[]}`))
+	}))
+	defer ts.Close()
+
+	source := NewTokenSource("primary", nil)
+	source.AddTokens("spare-1234", "", "primary")
+	client, err := NewClientWithOptions(ClientOptions{
+		Owner:       "octo",
+		Repo:        "hello",
+		APIBaseURL:  ts.URL + "/",
+		TokenSource: source,
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.gh.Actions.ListWorkflows(context.Background(), "octo", "hello", nil)
+	require.NoError(t, err)
+	_, _, err = client.gh.Actions.ListWorkflows(context.Background(), "octo", "hello", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer primary", "Bearer spare-1234", "Bearer spare-1234"}, auths, "the exhausted token is skipped until it resets")
+
+	status := source.Status()
+	require.Len(t, status, 2)
+	assert.Equal(t, "mary", status[0].Token)
+	assert.False(t, status[0].Active)
+	require.NotNil(t, status[0].Remaining)
+	assert.Equal(t, 0, *status[0].Remaining)
+	assert.Equal(t, "1234", status[1].Token)
+	assert.True(t, status[1].Active)
+	assert.Equal(t, 5000, status[1].Limit)
+	require.NotNil(t, status[1].Remaining)
+	assert.Equal(t, 4999, *status[1].Remaining)
+}
+
+func TestTokenSource_UsesSoonestResetWhenAllExhausted(t *testing.T) {
+	source := NewTokenSource("a", nil)
+	source.AddTokens("b")
+	exhausted := func(resetIn time.Duration) *http.Response {
+		header := http.Header{}
+		header.Set("X-RateLimit-Remaining", "0")
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(resetIn).Unix(), 10))
+		return &http.Response{Header: header}
+	}
+	source.observe("a", exhausted(time.Hour))
+	source.observe("b", exhausted(time.Minute))
+	assert.Equal(t, "b", source.Token())
+
+	// Limits of other resources do not describe the core limit.
+	search := exhausted(time.Minute)
+	search.Header.Set("X-RateLimit-Resource", "search")
+	search.Header.Set("X-RateLimit-Remaining", "30")
+	source.observe("b", search)
+	assert.Equal(t, "b", source.Token())
+}
+
+func TestTokenSource_RejectedPoolTokenIsSkipped(t *testing.T) {
+	source := NewTokenSource("primary", nil)
+	source.AddTokens("spare")
+
+	token, ok := source.Refresh("primary")
+	assert.True(t, ok)
+	assert.Equal(t, "spare", token)
+
+	token, ok = source.Refresh("spare")
+	assert.False(t, ok, "no token left to retry with")
+	assert.Equal(t, "spare", token)
+}
@@ -0,0 +1,24 @@
+package github
+
+import (
+	"net/http"
+	"time"
+)
+
+// apiTransport is shared by every client so bursts of API calls (multi-repo status, log
+// assembly) reuse pooled connections instead of dialing a new one per request.
+var apiTransport http.RoundTripper = newAPITransport()
+
+// newAPITransport returns a transport tuned for many concurrent requests to a few hosts.
+func newAPITransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConns = 100
+	// The default of 2 idle connections per host forces most of a burst to reconnect.
+	t.MaxIdleConnsPerHost = 32
+	t.IdleConnTimeout = 90 * time.Second
+	// With Accept-Encoding left unset the transport asks for gzip and decompresses the
+	// response transparently; setting the header ourselves would turn that off.
+	t.DisableCompression = false
+	return t
+}
@@ -0,0 +1,61 @@
+package github
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITransport_Settings(t *testing.T) {
+	transport := newAPITransport()
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.False(t, transport.DisableCompression)
+	assert.GreaterOrEqual(t, transport.MaxIdleConnsPerHost, 16)
+}
+
+func TestClient_AcceptsGzipResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Contains(t, r.Header.Get("Accept-Encoding"), "gzip")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`{"total_count": 1, "workflows": [{"id": 7, "name": "CI", "path": ".github/workflows/ci.yml", "state": "active"}]}`))
+		_ = gz.Close()
+	}))
+	defer ts.Close()
+
+	client, err := NewClientWithOptions(ClientOptions{Token: "test-token", Owner: "octo", Repo: "hello", APIBaseURL: ts.URL + "/"})
+	require.NoError(t, err)
+
+	workflows, _, err := client.gh.Actions.ListWorkflows(context.Background(), "octo", "hello", nil)
+	require.NoError(t, err)
+	require.Len(t, workflows.Workflows, 1)
+	assert.True(t, strings.HasSuffix(workflows.Workflows[0].GetPath(), "ci.yml"))
+}
+
+func TestClient_SendsUserAgent(t *testing.T) {
+	var userAgents []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 0, "workflows": []}`))
+	}))
+	defer ts.Close()
+
+	for _, ua := range []string{"", UserAgent("v1.4.0", "ops@example.com")} {
+		client, err := NewClientWithOptions(ClientOptions{Token: "test-token", Owner: "octo", Repo: "hello", APIBaseURL: ts.URL + "/", UserAgent: ua})
+		require.NoError(t, err)
+		_, _, err = client.gh.Actions.ListWorkflows(context.Background(), "octo", "hello", nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{
+		"gh-actions-mcp/dev (+https://github.com/denysvitali/gh-actions-mcp)",
+		"gh-actions-mcp/v1.4.0 (+https://github.com/denysvitali/gh-actions-mcp) ops@example.com",
+	}, userAgents)
+}
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// configNamePattern matches the secret and variable names GitHub accepts.
+var configNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Configuration variable scopes, from the lowest to the highest precedence.
+const (
+	VariableScopeOrganization = "organization"
+	VariableScopeRepository   = "repository"
+	VariableScopeEnvironment  = "environment"
+)
+
+// Variable is a configuration variable available to workflows as vars.<NAME>.
+type Variable struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Scope       string `json:"scope"`                 // organization, repository, or environment
+	Environment string `json:"environment,omitempty"` // Set for environment variables
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+// listVariables lists the variables of one scope: the organization variables shared with
+// the repository, the repository's own, or those of an environment.
+func (c *Client) listVariables(ctx context.Context, scope, environment string) ([]*Variable, error) {
+	opts := &github.ListOptions{PerPage: 30}
+	var result []*Variable
+	for {
+		var vars *github.ActionsVariables
+		var resp *github.Response
+		var err error
+		switch scope {
+		case VariableScopeOrganization:
+			vars, resp, err = c.gh.Actions.ListRepoOrgVariables(ctx, c.owner, c.repo, opts)
+		case VariableScopeEnvironment:
+			vars, resp, err = c.gh.Actions.ListEnvVariables(ctx, c.owner, c.repo, url.PathEscape(environment), opts)
+		default:
+			vars, resp, err = c.gh.Actions.ListRepoVariables(ctx, c.owner, c.repo, opts)
+		}
+		if err != nil {
+			if scope == VariableScopeEnvironment {
+				return nil, fmt.Errorf("failed to list variables of environment %s: %w", environment, Classify(err))
+			}
+			return nil, fmt.Errorf("failed to list %s variables: %w", scope, Classify(err))
+		}
+		for _, v := range vars.Variables {
+			variable := &Variable{Name: v.Name, Value: v.Value, Scope: scope, UpdatedAt: formatTime(v.UpdatedAt)}
+			if scope == VariableScopeEnvironment {
+				variable.Environment = environment
+			}
+			result = append(result, variable)
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+// VariableUpdate is the outcome of SetVariable or DeleteVariable.
+type VariableUpdate struct {
+	Name        string `json:"name"`
+	Scope       string `json:"scope"`
+	Environment string `json:"environment,omitempty"`
+	Created     bool   `json:"created,omitempty"` // Set when SetVariable created a new variable
+	Deleted     bool   `json:"deleted,omitempty"`
+}
+
+// ListVariables lists the configuration variables available to the repository's workflows:
+// the organization variables shared with it and its own, plus those of environment when it
+// is not empty.
+func (c *Client) ListVariables(ctx context.Context, environment string) ([]*Variable, error) {
+	var result []*Variable
+	org, err := c.listVariables(ctx, VariableScopeOrganization, "")
+	// Repositories owned by a user have no organization variables.
+	if err != nil && !IsHTTPError(err, http.StatusNotFound) {
+		return nil, err
+	}
+	result = append(result, org...)
+
+	repo, err := c.listVariables(ctx, VariableScopeRepository, "")
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, repo...)
+
+	if environment != "" {
+		env, err := c.listVariables(ctx, VariableScopeEnvironment, environment)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, env...)
+	}
+	return result, nil
+}
+
+// SetVariable creates or updates a repository variable, or an environment variable when
+// environment is not empty.
+func (c *Client) SetVariable(ctx context.Context, name, value, environment string) (*VariableUpdate, error) {
+	if err := validateConfigName("variable", name); err != nil {
+		return nil, err
+	}
+
+	update := &VariableUpdate{Name: name, Scope: VariableScopeRepository}
+	variable := &github.ActionsVariable{Name: name, Value: value}
+	var err error
+	if environment != "" {
+		update.Scope, update.Environment = VariableScopeEnvironment, environment
+		env := url.PathEscape(environment)
+		if _, err = c.gh.Actions.UpdateEnvVariable(ctx, c.owner, c.repo, env, variable); IsHTTPError(err, http.StatusNotFound) {
+			_, err = c.gh.Actions.CreateEnvVariable(ctx, c.owner, c.repo, env, variable)
+			update.Created = err == nil
+		}
+	} else {
+		if _, err = c.gh.Actions.UpdateRepoVariable(ctx, c.owner, c.repo, variable); IsHTTPError(err, http.StatusNotFound) {
+			_, err = c.gh.Actions.CreateRepoVariable(ctx, c.owner, c.repo, variable)
+			update.Created = err == nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set variable %s: %w", name, Classify(err))
+	}
+	return update, nil
+}
+
+// DeleteVariable deletes a repository variable, or an environment variable when
+// environment is not empty.
+func (c *Client) DeleteVariable(ctx context.Context, name, environment string) (*VariableUpdate, error) {
+	update := &VariableUpdate{Name: name, Scope: VariableScopeRepository}
+	var err error
+	if environment != "" {
+		update.Scope, update.Environment = VariableScopeEnvironment, environment
+		_, err = c.gh.Actions.DeleteEnvVariable(ctx, c.owner, c.repo, url.PathEscape(environment), name)
+	} else {
+		_, err = c.gh.Actions.DeleteRepoVariable(ctx, c.owner, c.repo, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete variable %s: %w", name, Classify(err))
+	}
+	update.Deleted = true
+	return update, nil
+}
+
+// validateConfigName checks a secret or variable name against GitHub's naming rules.
+func validateConfigName(kind, name string) error {
+	if !configNamePattern.MatchString(name) || strings.HasPrefix(strings.ToUpper(name), "GITHUB_") {
+		return fmt.Errorf("invalid %s name %q: use letters, digits, and underscores, not starting with a digit or GITHUB_", kind, name)
+	}
+	return nil
+}
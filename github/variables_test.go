@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariables(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	vars := map[string]string{"API_URL": "https://api.example.com"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/organization-variables", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "variables": [{"name": "ORG_WIDE", "value": "1"}]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/variables", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"total_count": 1, "variables": [{"name": "API_URL", "value": "https://api.example.com"}]}`))
+		case http.MethodPost:
+			var v githubapi.ActionsVariable
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&v))
+			vars[v.Name] = v.Value
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/variables/", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[len("/repos/"+owner+"/"+repo+"/actions/variables/"):]
+		if _, ok := vars[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodPatch:
+			var v githubapi.ActionsVariable
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&v))
+			vars[name] = v.Value
+		case http.MethodDelete:
+			delete(vars, name)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+	ctx := context.Background()
+
+	list, err := client.ListVariables(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, VariableScopeOrganization, list[0].Scope)
+	assert.Equal(t, "API_URL", list[1].Name)
+
+	update, err := client.SetVariable(ctx, "API_URL", "https://new.example.com", "")
+	require.NoError(t, err)
+	assert.False(t, update.Created)
+	assert.Equal(t, "https://new.example.com", vars["API_URL"])
+
+	update, err = client.SetVariable(ctx, "REGION", "eu-west-1", "")
+	require.NoError(t, err)
+	assert.True(t, update.Created)
+	assert.Equal(t, "eu-west-1", vars["REGION"])
+
+	update, err = client.DeleteVariable(ctx, "REGION", "")
+	require.NoError(t, err)
+	assert.True(t, update.Deleted)
+	assert.NotContains(t, vars, "REGION")
+
+	_, err = client.DeleteVariable(ctx, "MISSING", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.SetVariable(ctx, "GITHUB_SHA", "x", "")
+	assert.Error(t, err)
+}
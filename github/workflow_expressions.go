@@ -0,0 +1,246 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// exprScope is what the expressions of a part of a workflow may reference.
+type exprScope struct {
+	job       string
+	needs     map[string]bool // Jobs listed in the job's needs
+	steps     map[string]bool // Step IDs of the job that ran before
+	later     map[string]bool // Step IDs of the job that run after, or nil outside steps
+	hasMatrix bool
+}
+
+// checkWorkflowExpressions parses the ${{ }} expressions of a workflow and the if:
+// conditions of its jobs and steps, and reports syntax errors, unknown contexts and
+// functions, needs and steps references that cannot resolve, and if: conditions that are
+// always true. It is the built-in counterpart of actionlint's expression checks.
+func checkWorkflowExpressions(data []byte) []*WorkflowIssue {
+	var doc yaml.Node
+	if yaml.Unmarshal(data, &doc) != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	root := doc.Content[0]
+
+	var issues []*WorkflowIssue
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "jobs" {
+			issues = append(issues, checkNodeExpressions(root.Content[i].Value, root.Content[i+1], nil)...)
+			continue
+		}
+		jobs := root.Content[i+1]
+		if jobs.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(jobs.Content); j += 2 {
+			issues = append(issues, checkJobExpressions(jobs.Content[j].Value, jobs.Content[j+1])...)
+		}
+	}
+	return issues
+}
+
+// checkJobExpressions checks the expressions of a job and its steps.
+func checkJobExpressions(id string, job *yaml.Node) []*WorkflowIssue {
+	if job.Kind != yaml.MappingNode {
+		return nil
+	}
+	scope := &exprScope{job: id, needs: map[string]bool{}, steps: map[string]bool{}}
+	if _, needs := yamlMappingValue(job, "needs"); needs != nil {
+		for _, need := range jobNeeds(needs) {
+			scope.needs[need] = true
+		}
+	}
+	if _, strategy := yamlMappingValue(job, "strategy"); strategy != nil {
+		_, matrix := yamlMappingValue(strategy, "matrix")
+		scope.hasMatrix = matrix != nil
+	}
+	_, steps := yamlMappingValue(job, "steps")
+	var stepIDs []string
+	if steps != nil {
+		for _, step := range steps.Content {
+			if _, stepID := yamlMappingValue(step, "id"); stepID != nil {
+				stepIDs = append(stepIDs, stepID.Value)
+			}
+		}
+	}
+	// Job outputs read the steps once they have all run.
+	for _, stepID := range stepIDs {
+		scope.steps[stepID] = true
+	}
+
+	var issues []*WorkflowIssue
+	for i := 0; i+1 < len(job.Content); i += 2 {
+		if key := job.Content[i].Value; key != "steps" {
+			issues = append(issues, checkNodeExpressions(key, job.Content[i+1], scope)...)
+		}
+	}
+	if steps == nil {
+		return issues
+	}
+
+	earlier := map[string]bool{}
+	for _, step := range steps.Content {
+		later := map[string]bool{}
+		for _, stepID := range stepIDs {
+			if !earlier[stepID] {
+				later[stepID] = true
+			}
+		}
+		stepScope := &exprScope{job: id, needs: scope.needs, steps: earlier, later: later, hasMatrix: scope.hasMatrix}
+		issues = append(issues, checkNodeExpressions("", step, stepScope)...)
+		if _, stepID := yamlMappingValue(step, "id"); stepID != nil {
+			earlier = copyBoolMap(earlier)
+			earlier[stepID.Value] = true
+		}
+	}
+	return issues
+}
+
+func copyBoolMap(m map[string]bool) map[string]bool {
+	c := make(map[string]bool, len(m)+1)
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// checkNodeExpressions checks the expressions in the scalar values under node, the value
+// of key in its parent mapping.
+func checkNodeExpressions(key string, node *yaml.Node, scope *exprScope) []*WorkflowIssue {
+	switch node.Kind {
+	case yaml.MappingNode:
+		var issues []*WorkflowIssue
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			issues = append(issues, checkNodeExpressions(node.Content[i].Value, node.Content[i+1], scope)...)
+		}
+		return issues
+	case yaml.SequenceNode:
+		var issues []*WorkflowIssue
+		for _, item := range node.Content {
+			issues = append(issues, checkNodeExpressions("", item, scope)...)
+		}
+		return issues
+	case yaml.ScalarNode:
+		return checkScalarExpressions(key, node, scope)
+	}
+	return nil
+}
+
+// checkScalarExpressions checks the expressions of one scalar value.
+func checkScalarExpressions(key string, node *yaml.Node, scope *exprScope) []*WorkflowIssue {
+	var issues []*WorkflowIssue
+	add := func(offset int, severity, format string, args ...interface{}) {
+		line := node.Line
+		if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			// Block scalars start on the line after their indicator.
+			line += 1 + strings.Count(node.Value[:offset], "\n")
+		}
+		issues = append(issues, &WorkflowIssue{
+			Line:     line,
+			Column:   node.Column,
+			Severity: severity,
+			Kind:     "expression",
+			Message:  fmt.Sprintf(format, args...),
+			Source:   "expression",
+		})
+	}
+
+	value := node.Value
+	if key == "if" && scope != nil {
+		trimmed := strings.TrimSpace(value)
+		if _, whole := wholeExpression(trimmed); !whole && strings.Contains(trimmed, "${{") {
+			add(0, "error", "if: condition %q mixes text with ${{ }}, so it is a non-empty string and always true; put the whole condition inside ${{ }}", trimmed)
+			// The embedded expressions are still checked below.
+		} else if !whole && trimmed != "" {
+			// if: conditions are expressions even without ${{ }}.
+			value = "${{ " + trimmed + " }}"
+		}
+	}
+
+	for offset := 0; ; {
+		start := strings.Index(value[offset:], "${{")
+		if start < 0 {
+			break
+		}
+		start += offset
+		end := strings.Index(value[start:], "}}")
+		if end < 0 {
+			add(start, "error", "unterminated ${{ in %q", strings.TrimSpace(value[start:]))
+			break
+		}
+		source := strings.TrimSpace(value[start+3 : start+end])
+		offset = start + end + 2
+		position := min(start, len(node.Value))
+
+		parsed, err := parseExpression(source)
+		if err != nil {
+			add(position, "error", "invalid expression %q: %v", source, err)
+			continue
+		}
+		for _, problem := range expressionProblems(parsed, scope) {
+			add(position, "error", "%s in %q", problem, source)
+		}
+	}
+	return issues
+}
+
+// expressionProblems returns the references and calls of an expression that cannot
+// resolve in scope.
+func expressionProblems(n *exprNode, scope *exprScope) []string {
+	var problems []string
+	var walk func(n *exprNode)
+	walk = func(n *exprNode) {
+		switch n.kind {
+		case nodeContext:
+			if !expressionContexts[strings.ToLower(n.name)] {
+				problems = append(problems, fmt.Sprintf("unknown context %q", n.name))
+			} else if strings.EqualFold(n.name, "matrix") && scope != nil && !scope.hasMatrix {
+				problems = append(problems, fmt.Sprintf("job %q uses the matrix context but defines no strategy.matrix", scope.job))
+			}
+		case nodeCall:
+			if _, known := expressionFunctions[strings.ToLower(n.name)]; !known {
+				problems = append(problems, fmt.Sprintf("unknown function %s()", n.name))
+			}
+		case nodeMember, nodeIndex:
+			if ctx := n.args[0]; ctx.kind == nodeContext && scope != nil {
+				property := n.name
+				if n.kind == nodeIndex {
+					if n.args[1].kind != nodeLiteral {
+						break
+					}
+					property = exprString(n.args[1].value)
+				}
+				switch strings.ToLower(ctx.name) {
+				case "needs":
+					if !scope.needs[property] {
+						problems = append(problems, fmt.Sprintf("job %q does not list %q in needs", scope.job, property))
+					}
+				case "steps":
+					switch {
+					case scope.steps[property]:
+					case scope.later[property]:
+						problems = append(problems, fmt.Sprintf("step %q runs after this step", property))
+					default:
+						problems = append(problems, fmt.Sprintf("job %q has no step with id %q", scope.job, property))
+					}
+				}
+			}
+		}
+		for _, arg := range n.args {
+			walk(arg)
+		}
+	}
+	walk(n)
+	return problems
+}
+
+// expressionFunctions are the functions expressions may call.
+var expressionFunctions = map[string]struct{}{
+	"contains": {}, "startswith": {}, "endswith": {}, "format": {}, "join": {}, "tojson": {}, "fromjson": {},
+	"hashfiles": {}, "success": {}, "failure": {}, "cancelled": {}, "always": {},
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWorkflowExpressions(t *testing.T) {
+	issues := checkWorkflowExpressions([]byte(`name: CI
+on: push
+run-name: Build ${{ github.ref_name }}
+jobs:
+  build:
+    runs-on: ${{ matrix.os }}
+    outputs:
+      version: ${{ steps.meta.outputs.version }}
+    steps:
+      - if: ${{ steps.meta.outputs.version != '' }}
+        run: echo early
+      - id: meta
+        run: echo "version=1" >> "$GITHUB_OUTPUT"
+      - if: github.event_name == 'push' && startswith(github.ref, 'refs/tags/')
+        run: |
+          echo ${{ steps.meta.outputs.version }}
+          echo ${{ steps.missing.outputs.x }}
+  deploy:
+    needs: build
+    if: always() && needs.build.result == 'success' && needs.test.result == 'success'
+    runs-on: ubuntu-latest
+    steps:
+      - if: deploy ${{ github.ref == 'refs/heads/main' }}
+        run: ./deploy.sh ${{ secret.TOKEN }}
+      - run: echo ${{ github.ref == }}
+      - run: echo ${{ toJson(github) }} ${{ nope(1) }}
+`))
+
+	type found struct {
+		Line    int
+		Message string
+	}
+	var got []found
+	for _, issue := range issues {
+		assert.Equal(t, "expression", issue.Source)
+		got = append(got, found{issue.Line, issue.Message})
+	}
+	require.Len(t, got, 8, "%v", got)
+	assert.Equal(t, 6, got[0].Line)
+	assert.Contains(t, got[0].Message, `job "build" uses the matrix context but defines no strategy.matrix`)
+	assert.Equal(t, 10, got[1].Line)
+	assert.Contains(t, got[1].Message, `step "meta" runs after this step`)
+	assert.Equal(t, 17, got[2].Line)
+	assert.Contains(t, got[2].Message, `job "build" has no step with id "missing"`)
+	assert.Equal(t, 20, got[3].Line)
+	assert.Contains(t, got[3].Message, `job "deploy" does not list "test" in needs`)
+	assert.Equal(t, 23, got[4].Line)
+	assert.Contains(t, got[4].Message, "always true")
+	assert.Contains(t, got[5].Message, `unknown context "secret"`)
+	assert.Contains(t, got[6].Message, "invalid expression")
+	assert.Equal(t, 26, got[7].Line)
+	assert.Contains(t, got[7].Message, "unknown function nope()")
+}
+
+func TestValidateWorkflowYAML_ExpressionsWithoutActionlint(t *testing.T) {
+	result := ValidateWorkflowYAML(context.Background(), []byte("on: push\njobs:\n  a:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ${{ github.sha\n"), "ci.yml", false)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Issues, 1)
+	assert.Equal(t, "expression", result.Issues[0].Source)
+	assert.Equal(t, 6, result.Issues[0].Line)
+}
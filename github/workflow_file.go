@@ -0,0 +1,236 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/google/go-github/v69/github"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowInput describes a workflow_dispatch input declared in a workflow file.
+type WorkflowInput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required"`
+	Default     string   `json:"default,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// WorkflowDispatchInfo summarizes the manual-dispatch configuration of a workflow file.
+type WorkflowDispatchInfo struct {
+	Dispatchable bool             `json:"dispatchable"`
+	RunName      string           `json:"run_name,omitempty"`
+	Inputs       []*WorkflowInput `json:"inputs,omitempty"`
+}
+
+// Input returns the declared input with the given name, or nil.
+func (d *WorkflowDispatchInfo) Input(name string) *WorkflowInput {
+	for _, in := range d.Inputs {
+		if in.Name == name {
+			return in
+		}
+	}
+	return nil
+}
+
+// GetWorkflowFile returns the raw contents of a file in the repository at the given ref.
+// An empty ref reads from the default branch.
+func (c *Client) GetWorkflowFile(ctx context.Context, path, ref string) ([]byte, error) {
+	return c.getRepoFile(ctx, c.owner, c.repo, path, ref)
+}
+
+// getRepoFile returns the raw contents of a file in any repository at the given ref.
+func (c *Client) getRepoFile(ctx context.Context, owner, repo, path, ref string) ([]byte, error) {
+	var opts *github.RepositoryContentGetOptions
+	if ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+
+	file, _, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s/%s/%s: %w", owner, repo, path, err)
+	}
+	if file == nil {
+		return nil, fmt.Errorf("failed to get %s/%s/%s: path is a directory", owner, repo, path)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s/%s/%s: %w", owner, repo, path, err)
+	}
+	return []byte(content), nil
+}
+
+// runWorkflowFile reads the workflow file of a run as it was at the run's commit. A
+// required workflow is read from the repository defining it, and its path is qualified
+// with that repository. path is empty when the run has no workflow or it could not be
+// looked up.
+func (c *Client) runWorkflowFile(ctx context.Context, run *WorkflowRun) (path string, data []byte, err error) {
+	if data, ok, err := c.readWorkflowSource(ctx, run.WorkflowSource); ok {
+		return run.WorkflowSource.Repository + "/" + run.WorkflowSource.Path, data, err
+	}
+	if run.WorkflowID == 0 {
+		return "", nil, nil
+	}
+	wf, _, err := c.gh.Actions.GetWorkflowByID(ctx, c.owner, c.repo, run.WorkflowID)
+	if err != nil {
+		return "", nil, Classify(err)
+	}
+	data, err = c.GetWorkflowFile(ctx, wf.GetPath(), run.HeadSHA)
+	return wf.GetPath(), data, err
+}
+
+// GetWorkflowDispatchInfo reads a workflow file and reports its workflow_dispatch inputs.
+func (c *Client) GetWorkflowDispatchInfo(ctx context.Context, path, ref string) (*WorkflowDispatchInfo, error) {
+	data, err := c.GetWorkflowFile(ctx, path, ref)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWorkflowDispatchInfo(data)
+}
+
+// ParseWorkflowDispatchInfo extracts the workflow_dispatch trigger and its inputs from workflow YAML.
+func ParseWorkflowDispatchInfo(data []byte) (*WorkflowDispatchInfo, error) {
+	var doc struct {
+		RunName string      `yaml:"run-name"`
+		On      interface{} `yaml:"on"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+
+	info := &WorkflowDispatchInfo{RunName: doc.RunName}
+
+	switch on := doc.On.(type) {
+	case string:
+		info.Dispatchable = on == "workflow_dispatch"
+	case []interface{}:
+		for _, event := range on {
+			if event == "workflow_dispatch" {
+				info.Dispatchable = true
+			}
+		}
+	case map[string]interface{}:
+		dispatch, ok := on["workflow_dispatch"]
+		if !ok {
+			break
+		}
+		info.Dispatchable = true
+		dispatchMap, _ := dispatch.(map[string]interface{})
+		inputs, _ := dispatchMap["inputs"].(map[string]interface{})
+		for name, raw := range inputs {
+			info.Inputs = append(info.Inputs, parseWorkflowInput(name, raw))
+		}
+		sort.Slice(info.Inputs, func(i, j int) bool { return info.Inputs[i].Name < info.Inputs[j].Name })
+	}
+
+	return info, nil
+}
+
+func parseWorkflowInput(name string, raw interface{}) *WorkflowInput {
+	input := &WorkflowInput{Name: name, Type: "string"}
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return input
+	}
+
+	if v, ok := fields["description"].(string); ok {
+		input.Description = v
+	}
+	if v, ok := fields["required"].(bool); ok {
+		input.Required = v
+	}
+	if v, ok := fields["type"].(string); ok {
+		input.Type = v
+	}
+	if v, ok := fields["default"]; ok && v != nil {
+		input.Default = fmt.Sprint(v)
+	}
+	if opts, ok := fields["options"].([]interface{}); ok {
+		for _, o := range opts {
+			input.Options = append(input.Options, fmt.Sprint(o))
+		}
+	}
+	return input
+}
+
+// InputSchema returns a JSON Schema (draft 2020-12) of the workflow_dispatch inputs, so
+// clients can build an input form. Boolean and number inputs are typed as such, choice
+// inputs list their options, and environment inputs take an environment name.
+func (d *WorkflowDispatchInfo) InputSchema(title string) map[string]interface{} {
+	properties := make(map[string]interface{}, len(d.Inputs))
+	required := []string{}
+	for _, in := range d.Inputs {
+		properties[in.Name] = in.schema()
+		if in.Required {
+			required = append(required, in.Name)
+		}
+	}
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                title,
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// schema returns the JSON Schema of one input.
+func (in *WorkflowInput) schema() map[string]interface{} {
+	prop := map[string]interface{}{"type": "string"}
+	if in.Description != "" {
+		prop["description"] = in.Description
+	}
+	var def interface{}
+	if in.Default != "" {
+		def = in.Default
+	}
+	switch in.Type {
+	case "boolean":
+		prop["type"] = "boolean"
+		if b, err := strconv.ParseBool(in.Default); err == nil {
+			def = b
+		}
+	case "number":
+		prop["type"] = "number"
+		if n, err := strconv.ParseFloat(in.Default, 64); err == nil {
+			def = n
+		}
+	case "choice":
+		prop["enum"] = in.Options
+	case "environment":
+		prop["x-github-input-type"] = "environment"
+	}
+	if def != nil {
+		prop["default"] = def
+	}
+	return prop
+}
+
+// GetWorkflowInputSchema returns the JSON Schema of the inputs of a workflow, selected by
+// ID, name, or path, as declared on the default branch.
+func (c *Client) GetWorkflowInputSchema(ctx context.Context, workflow string) (map[string]interface{}, error) {
+	workflowID, _, err := c.ResolveWorkflowID(ctx, workflow)
+	if err != nil {
+		return nil, err
+	}
+	wf, _, err := c.gh.Actions.GetWorkflowByID(ctx, c.owner, c.repo, workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow %d: %w", workflowID, Classify(err))
+	}
+	info, err := c.GetWorkflowDispatchInfo(ctx, wf.GetPath(), "")
+	if err != nil {
+		return nil, err
+	}
+	if !info.Dispatchable {
+		return nil, fmt.Errorf("%s: %w", wf.GetPath(), ErrNoDispatchTrigger)
+	}
+	schema := info.InputSchema(wf.GetName())
+	schema["description"] = fmt.Sprintf("Inputs of %s (workflow ID %d) for workflow_dispatch", wf.GetPath(), workflowID)
+	return schema, nil
+}
@@ -0,0 +1,269 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// actionlintLookPath locates the actionlint binary; overridden in tests.
+var actionlintLookPath = func() (string, error) { return exec.LookPath("actionlint") }
+
+// yamlErrorLinePattern extracts the line number from a yaml.v3 error message.
+var yamlErrorLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// WorkflowIssue is a single problem found in a workflow file.
+type WorkflowIssue struct {
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"` // error or warning
+	Kind     string `json:"kind"`
+	Message  string `json:"message"`
+	Source   string `json:"source"` // yaml, expression, or actionlint
+}
+
+// WorkflowValidation is the result of validating a workflow file.
+type WorkflowValidation struct {
+	Path       string           `json:"path,omitempty"`
+	Valid      bool             `json:"valid"`
+	Issues     []*WorkflowIssue `json:"issues"`
+	Actionlint string           `json:"actionlint"` // used, not_installed, disabled, or failed: <reason>
+	Summary    string           `json:"summary"`
+	Containers []*JobContainers `json:"containers,omitempty"` // Job and service containers, by job
+}
+
+// ValidateWorkflowYAML checks workflow YAML for syntax and structural problems. When
+// useActionlint is set and actionlint is installed, its findings (expression type errors,
+// invalid contexts, shellcheck results for run: blocks, deprecated syntax) are included;
+// otherwise expressions are checked by checkWorkflowExpressions. runs-on labels that look
+// like typos of GitHub-hosted runner labels are reported.
+func ValidateWorkflowYAML(ctx context.Context, data []byte, path string, useActionlint bool) *WorkflowValidation {
+	return ValidateWorkflowYAMLWithRunners(ctx, data, path, useActionlint, nil)
+}
+
+// ValidateWorkflowYAMLWithRunners is ValidateWorkflowYAML that also checks runs-on labels
+// against the labels of runners, the repository's self-hosted runners (see
+// CheckRunnerLabels).
+func ValidateWorkflowYAMLWithRunners(ctx context.Context, data []byte, path string, useActionlint bool, runners []*SelfHostedRunner) *WorkflowValidation {
+	result := &WorkflowValidation{Path: path, Issues: []*WorkflowIssue{}}
+	result.Issues = append(result.Issues, checkWorkflowStructure(data)...)
+	result.Issues = append(result.Issues, checkWorkflowRunnerLabels(data, runners)...)
+	result.Containers, _ = ParseJobContainers(data)
+
+	switch {
+	case !useActionlint:
+		result.Actionlint = "disabled"
+	default:
+		bin, err := actionlintLookPath()
+		if err != nil {
+			result.Actionlint = "not_installed"
+			break
+		}
+		issues, err := runActionlint(ctx, bin, data, path)
+		if err != nil {
+			result.Actionlint = "failed: " + err.Error()
+			break
+		}
+		result.Actionlint = "used"
+		result.Issues = append(result.Issues, issues...)
+	}
+	// actionlint checks expressions more thoroughly; without it, check them here.
+	if result.Actionlint != "used" {
+		result.Issues = append(result.Issues, checkWorkflowExpressions(data)...)
+	}
+
+	sort.SliceStable(result.Issues, func(i, j int) bool { return result.Issues[i].Line < result.Issues[j].Line })
+
+	errorCount, warningCount := 0, 0
+	for _, issue := range result.Issues {
+		if issue.Severity == "error" {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+	result.Valid = errorCount == 0
+	result.Summary = fmt.Sprintf("%d error(s), %d warning(s)", errorCount, warningCount)
+	if result.Actionlint == "not_installed" {
+		result.Summary += "; install actionlint for expression type and shellcheck checks"
+	}
+	return result
+}
+
+// checkWorkflowStructure reports YAML syntax errors and missing or malformed top-level keys,
+// jobs, and steps.
+func checkWorkflowStructure(data []byte) []*WorkflowIssue {
+	var issues []*WorkflowIssue
+	add := func(node *yaml.Node, kind, format string, args ...interface{}) {
+		issue := &WorkflowIssue{Severity: "error", Kind: kind, Message: fmt.Sprintf(format, args...), Source: "yaml"}
+		if node != nil {
+			issue.Line, issue.Column = node.Line, node.Column
+		}
+		issues = append(issues, issue)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		issue := &WorkflowIssue{Severity: "error", Kind: "syntax", Message: err.Error(), Source: "yaml"}
+		if m := yamlErrorLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+		}
+		return []*WorkflowIssue{issue}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		add(nil, "structure", "workflow must be a YAML mapping")
+		return issues
+	}
+	root := doc.Content[0]
+
+	if _, on := yamlMappingValue(root, "on"); on == nil {
+		add(root, "structure", "missing required key \"on\"")
+	}
+
+	jobsKey, jobs := yamlMappingValue(root, "jobs")
+	switch {
+	case jobs == nil:
+		add(root, "structure", "missing required key \"jobs\"")
+		return issues
+	case jobs.Kind != yaml.MappingNode || len(jobs.Content) == 0:
+		add(jobsKey, "structure", "\"jobs\" must be a mapping with at least one job")
+		return issues
+	}
+
+	jobIDs := make(map[string]bool)
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		jobIDs[jobs.Content[i].Value] = true
+	}
+
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		idNode, job := jobs.Content[i], jobs.Content[i+1]
+		id := idNode.Value
+		if job.Kind != yaml.MappingNode {
+			add(idNode, "job", "job %q must be a mapping", id)
+			continue
+		}
+
+		_, runsOn := yamlMappingValue(job, "runs-on")
+		_, uses := yamlMappingValue(job, "uses")
+		if runsOn == nil && uses == nil {
+			add(idNode, "job", "job %q must set \"runs-on\" or call a reusable workflow with \"uses\"", id)
+		}
+		issues = append(issues, checkJobContainers(id, job)...)
+
+		if _, needs := yamlMappingValue(job, "needs"); needs != nil {
+			deps := []*yaml.Node{needs}
+			if needs.Kind == yaml.SequenceNode {
+				deps = needs.Content
+			}
+			for _, dep := range deps {
+				if dep.Kind == yaml.ScalarNode && !jobIDs[dep.Value] {
+					add(dep, "job", "job %q needs unknown job %q", id, dep.Value)
+				}
+			}
+		}
+
+		stepsKey, steps := yamlMappingValue(job, "steps")
+		if steps == nil {
+			continue
+		}
+		if steps.Kind != yaml.SequenceNode {
+			add(stepsKey, "step", "steps of job %q must be a list", id)
+			continue
+		}
+		for n, step := range steps.Content {
+			if step.Kind != yaml.MappingNode {
+				add(step, "step", "step %d of job %q must be a mapping", n+1, id)
+				continue
+			}
+			_, run := yamlMappingValue(step, "run")
+			_, stepUses := yamlMappingValue(step, "uses")
+			switch {
+			case run == nil && stepUses == nil:
+				add(step, "step", "step %d of job %q must set \"run\" or \"uses\"", n+1, id)
+			case run != nil && stepUses != nil:
+				add(step, "step", "step %d of job %q sets both \"run\" and \"uses\"", n+1, id)
+			}
+		}
+	}
+
+	return issues
+}
+
+// yamlMappingValue returns the key and value nodes for key in a mapping node.
+func yamlMappingValue(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// actionlintError mirrors an entry of actionlint's JSON output.
+type actionlintError struct {
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Kind    string `json:"kind"`
+}
+
+// runActionlint lints data with the actionlint binary at bin.
+func runActionlint(ctx context.Context, bin string, data []byte, path string) ([]*WorkflowIssue, error) {
+	if path == "" {
+		path = "workflow.yml"
+	}
+	cmd := exec.CommandContext(ctx, bin, "-format", "{{json .}}", "-stdin-filename", path, "-")
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// actionlint exits with 1 when it found problems; anything else is a failure to run.
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		return nil, err
+	}
+
+	return parseActionlintOutput(stdout.Bytes())
+}
+
+func parseActionlintOutput(out []byte) ([]*WorkflowIssue, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 || bytes.Equal(out, []byte("null")) {
+		return nil, nil
+	}
+
+	var found []actionlintError
+	if err := json.Unmarshal(out, &found); err != nil {
+		return nil, fmt.Errorf("unexpected actionlint output: %w", err)
+	}
+
+	issues := make([]*WorkflowIssue, 0, len(found))
+	for _, e := range found {
+		severity := "error"
+		if e.Kind == "deprecated-commands" || e.Kind == "shellcheck" {
+			severity = "warning"
+		}
+		issues = append(issues, &WorkflowIssue{
+			Line:     e.Line,
+			Column:   e.Column,
+			Severity: severity,
+			Kind:     e.Kind,
+			Message:  e.Message,
+			Source:   "actionlint",
+		})
+	}
+	return issues, nil
+}
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWorkflowYAML_Structure(t *testing.T) {
+	result := ValidateWorkflowYAML(context.Background(), []byte(`
+name: CI
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - name: Empty
+      - run: make
+        uses: actions/setup-go@v5
+  test:
+    needs: [build, lint]
+    steps:
+      - run: go test ./...
+`), ".github/workflows/ci.yml", false)
+
+	assert.False(t, result.Valid)
+	assert.Equal(t, "disabled", result.Actionlint)
+	require.Len(t, result.Issues, 4)
+	assert.Contains(t, result.Issues[0].Message, `step 2 of job "build" must set "run" or "uses"`)
+	assert.Equal(t, 9, result.Issues[0].Line)
+	assert.Contains(t, result.Issues[1].Message, "sets both")
+	assert.Contains(t, result.Issues[2].Message, `job "test" must set "runs-on"`)
+	assert.Contains(t, result.Issues[3].Message, `needs unknown job "lint"`)
+
+	result = ValidateWorkflowYAML(context.Background(), []byte("on: push\njobs:\n  build:\n    runs-on: [unclosed\n"), "", false)
+	require.Len(t, result.Issues, 1)
+	assert.Equal(t, "syntax", result.Issues[0].Kind)
+	assert.NotZero(t, result.Issues[0].Line)
+
+	result = ValidateWorkflowYAML(context.Background(), []byte("name: CI\n"), "", false)
+	require.Len(t, result.Issues, 2)
+	assert.Contains(t, result.Issues[0].Message, `"on"`)
+	assert.Contains(t, result.Issues[1].Message, `"jobs"`)
+}
+
+func TestValidateWorkflowYAML_Actionlint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake actionlint")
+	}
+
+	oldLookPath := actionlintLookPath
+	defer func() { actionlintLookPath = oldLookPath }()
+
+	valid := []byte("on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ${{ github.foo }}\n")
+
+	actionlintLookPath = func() (string, error) { return "", errors.New("not found") }
+	result := ValidateWorkflowYAML(context.Background(), valid, "", true)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "not_installed", result.Actionlint)
+	assert.Contains(t, result.Summary, "install actionlint")
+
+	script := filepath.Join(t.TempDir(), "actionlint")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+cat > /dev/null
+echo '[{"message":"property \"foo\" is not defined in object type","line":6,"column":24,"kind":"expression"},{"message":"shellcheck reported issue in this script: SC2086","line":6,"column":14,"kind":"shellcheck"}]'
+exit 1
+`), 0o755))
+	actionlintLookPath = func() (string, error) { return script, nil }
+
+	result = ValidateWorkflowYAML(context.Background(), valid, ".github/workflows/ci.yml", true)
+	assert.Equal(t, "used", result.Actionlint)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Issues, 2)
+	assert.Equal(t, "expression", result.Issues[0].Kind)
+	assert.Equal(t, "error", result.Issues[0].Severity)
+	assert.Equal(t, "warning", result.Issues[1].Severity)
+	assert.Equal(t, "1 error(s), 1 warning(s)", result.Summary)
+}
@@ -0,0 +1,192 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// maxTracedRuns caps the runs of a commit considered when tracing a workflow_run chain.
+const maxTracedRuns = 100
+
+// TracedRun is a run of a workflow_run chain.
+type TracedRun struct {
+	RunID        int64  `json:"run_id"`
+	RunNumber    int    `json:"run_number"`
+	Workflow     string `json:"workflow"`
+	WorkflowPath string `json:"workflow_path,omitempty"`
+	Event        string `json:"event"`
+	Status       string `json:"status"`
+	Conclusion   string `json:"conclusion,omitempty"`
+	URL          string `json:"url"`
+	Depth        int    `json:"depth"`                  // 0 for the first run of the chain
+	TriggeredBy  int64  `json:"triggered_by,omitempty"` // Run whose completion started this workflow_run run
+
+	createdAt, updatedAt time.Time
+	workflowID           int64
+}
+
+// RunChain is the chain of runs connected by workflow_run triggers that a run belongs to.
+type RunChain struct {
+	RunID    int64        `json:"run_id"`
+	HeadSHA  string       `json:"head_sha"`
+	Runs     []*TracedRun `json:"runs"` // Upstream runs before the runs they triggered
+	Tree     string       `json:"tree"`
+	Warnings []string     `json:"warnings,omitempty"`
+}
+
+// chainWorkflow is what a workflow_run workflow's file says about its chain.
+type chainWorkflow struct {
+	path      string
+	triggers  []string // on.workflow_run.workflows
+	downloads []*ArtifactDownload
+}
+
+// runChain is a RunChain with the lookups TraceArtifacts needs.
+type runChain struct {
+	*RunChain
+	runs      map[int64]*TracedRun
+	workflows map[int64]*chainWorkflow // By workflow ID, for the workflows of workflow_run runs
+}
+
+// GetRunChain finds the runs that led to a run through workflow_run triggers and the runs
+// it triggered in turn, with their statuses.
+func (c *Client) GetRunChain(ctx context.Context, runID int64) (*RunChain, error) {
+	chain, err := c.workflowRunChain(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	return chain.RunChain, nil
+}
+
+// workflowRunChain follows the workflow_run triggers between the runs of a run's commit
+// up to the first run of the chain and down to every run it triggered. A workflow_run run
+// is taken to be triggered by the latest run of a workflow its trigger lists that
+// completed before the run was created.
+func (c *Client) workflowRunChain(ctx context.Context, runID int64) (*runChain, error) {
+	root, _, err := c.gh.Actions.GetWorkflowRunByID(ctx, c.owner, c.repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run %d: %w", runID, Classify(err))
+	}
+	chain := &runChain{
+		RunChain:  &RunChain{RunID: runID, HeadSHA: root.GetHeadSHA(), Runs: []*TracedRun{}},
+		workflows: map[int64]*chainWorkflow{},
+	}
+
+	// workflow_run runs report the head commit of the run that triggered them, so the
+	// whole chain shares a head SHA.
+	listed, _, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, c.owner, c.repo, &github.ListWorkflowRunsOptions{
+		HeadSHA:     root.GetHeadSHA(),
+		ListOptions: github.ListOptions{PerPage: maxTracedRuns},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs of commit %s: %w", root.GetHeadSHA(), Classify(err))
+	}
+	if listed.GetTotalCount() > maxTracedRuns {
+		chain.Warnings = append(chain.Warnings, fmt.Sprintf("commit %s has %d runs; only the latest %d were traced", root.GetHeadSHA(), listed.GetTotalCount(), maxTracedRuns))
+	}
+	runs := make(map[int64]*TracedRun, len(listed.WorkflowRuns)+1)
+	for _, r := range append(listed.WorkflowRuns, root) {
+		runs[r.GetID()] = &TracedRun{
+			RunID:      r.GetID(),
+			RunNumber:  r.GetRunNumber(),
+			Workflow:   r.GetName(),
+			Event:      r.GetEvent(),
+			Status:     r.GetStatus(),
+			Conclusion: r.GetConclusion(),
+			URL:        r.GetHTMLURL(),
+			createdAt:  r.GetCreatedAt().Time,
+			updatedAt:  r.GetUpdatedAt().Time,
+			workflowID: r.GetWorkflowID(),
+		}
+	}
+	chain.runs = runs
+
+	// Read the triggers and download steps of the workflows of workflow_run runs, which
+	// run from the default branch.
+	for _, run := range runs {
+		if run.Event != "workflow_run" || chain.workflows[run.workflowID] != nil {
+			continue
+		}
+		info := &chainWorkflow{}
+		chain.workflows[run.workflowID] = info
+		wf, _, err := c.gh.Actions.GetWorkflowByID(ctx, c.owner, c.repo, run.workflowID)
+		if err != nil {
+			chain.Warnings = append(chain.Warnings, fmt.Sprintf("failed to get workflow %d: %v", run.workflowID, Classify(err)))
+			continue
+		}
+		info.path = wf.GetPath()
+		data, err := c.GetWorkflowFile(ctx, info.path, "")
+		if err == nil {
+			info.triggers, info.downloads, err = parseArtifactTrace(data)
+		}
+		if err != nil {
+			chain.Warnings = append(chain.Warnings, fmt.Sprintf("%s: %v", info.path, err))
+		}
+	}
+
+	for _, run := range runs {
+		info := chain.workflows[run.workflowID]
+		if info == nil {
+			continue
+		}
+		run.WorkflowPath = info.path
+		var trigger *TracedRun
+		for _, candidate := range runs {
+			if candidate == run || candidate.Status != "completed" || candidate.updatedAt.After(run.createdAt) || !slices.Contains(info.triggers, candidate.Workflow) {
+				continue
+			}
+			if trigger == nil || candidate.updatedAt.After(trigger.updatedAt) {
+				trigger = candidate
+			}
+		}
+		if trigger != nil {
+			run.TriggeredBy = trigger.RunID
+		}
+	}
+
+	// Collect the chain: up from the run to its first run, then down depth-first so the
+	// runs a run triggered follow it. A trigger completes before the run it triggers is
+	// created, so there are no cycles.
+	first := runs[runID]
+	for first.TriggeredBy != 0 {
+		first = runs[first.TriggeredBy]
+	}
+	var tree strings.Builder
+	var visit func(run *TracedRun, depth int)
+	visit = func(run *TracedRun, depth int) {
+		run.Depth = depth
+		chain.Runs = append(chain.Runs, run)
+		state := run.Status
+		if run.Conclusion != "" {
+			state = run.Conclusion
+		}
+		indent := ""
+		if depth > 0 {
+			indent = strings.Repeat("   ", depth-1) + "└─ "
+		}
+		marker := ""
+		if run.RunID == runID {
+			marker = "  <- this run"
+		}
+		fmt.Fprintf(&tree, "%s%s #%d (%s, run %d): %s%s\n", indent, run.Workflow, run.RunNumber, run.Event, run.RunID, state, marker)
+
+		var children []*TracedRun
+		for _, other := range runs {
+			if other.TriggeredBy == run.RunID {
+				children = append(children, other)
+			}
+		}
+		slices.SortFunc(children, func(a, b *TracedRun) int { return a.createdAt.Compare(b.createdAt) })
+		for _, child := range children {
+			visit(child, depth+1)
+		}
+	}
+	visit(first, 0)
+	chain.Tree = tree.String()
+	return chain, nil
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRunChain(t *testing.T) {
+	const (
+		owner = "test-owner"
+		repo  = "test-repo"
+	)
+
+	runs := []string{
+		`{"id": 1, "run_number": 7, "name": "Build", "event": "push", "status": "completed", "conclusion": "success", "workflow_id": 10, "head_sha": "abc",
+			"created_at": "2026-01-01T10:00:00Z", "updated_at": "2026-01-01T10:05:00Z"}`,
+		`{"id": 2, "run_number": 3, "name": "Deploy", "event": "workflow_run", "status": "completed", "conclusion": "success", "workflow_id": 20, "head_sha": "abc",
+			"created_at": "2026-01-01T10:06:00Z", "updated_at": "2026-01-01T10:08:00Z"}`,
+		`{"id": 3, "run_number": 2, "name": "Notify", "event": "workflow_run", "status": "in_progress", "workflow_id": 30, "head_sha": "abc",
+			"created_at": "2026-01-01T10:09:00Z", "updated_at": "2026-01-01T10:09:00Z"}`,
+		`{"id": 4, "run_number": 9, "name": "Lint", "event": "push", "status": "completed", "conclusion": "failure", "workflow_id": 40, "head_sha": "abc",
+			"created_at": "2026-01-01T10:00:00Z", "updated_at": "2026-01-01T10:01:00Z"}`,
+	}
+	workflows := map[string]string{
+		"20": "name: Deploy\non:\n  workflow_run:\n    workflows: [Build]\n    types: [completed]\njobs: {}\n",
+		"30": "name: Notify\non:\n  workflow_run:\n    workflows: [Deploy, Release]\njobs: {}\n",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(runs[1]))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 4, "workflow_runs": [` + runs[3] + `,` + runs[2] + `,` + runs[1] + `,` + runs[0] + `]}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/workflows/", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[len("/repos/"+owner+"/"+repo+"/actions/workflows/"):]
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": ` + id + `, "path": ".github/workflows/` + id + `.yml"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/contents/.github/workflows/", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[len("/repos/"+owner+"/"+repo+"/contents/.github/workflows/") : len(r.URL.Path)-len(".yml")]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(workflows[id])),
+		})
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+
+	chain, err := client.GetRunChain(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, chain.Warnings)
+	require.Len(t, chain.Runs, 3)
+	for i, want := range []struct {
+		id, triggeredBy int64
+		depth           int
+	}{{1, 0, 0}, {2, 1, 1}, {3, 2, 2}} {
+		assert.Equal(t, want.id, chain.Runs[i].RunID)
+		assert.Equal(t, want.triggeredBy, chain.Runs[i].TriggeredBy)
+		assert.Equal(t, want.depth, chain.Runs[i].Depth)
+	}
+	assert.Equal(t, ".github/workflows/30.yml", chain.Runs[2].WorkflowPath)
+	assert.Equal(t, "Build #7 (push, run 1): success\n"+
+		"└─ Deploy #3 (workflow_run, run 2): success  <- this run\n"+
+		"   └─ Notify #2 (workflow_run, run 3): in_progress\n", chain.Tree)
+}
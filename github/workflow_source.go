@@ -0,0 +1,71 @@
+package github
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// Workflow source kinds.
+const (
+	// WorkflowSourceRequired is a workflow defined in another repository, such as the
+	// organization's .github repository, and run here because a ruleset requires it.
+	WorkflowSourceRequired = "required"
+	// WorkflowSourceDynamic is a workflow managed by GitHub rather than defined in a file,
+	// such as Dependabot updates, CodeQL default setup, or Pages builds.
+	WorkflowSourceDynamic = "dynamic"
+)
+
+// WorkflowSource tells where a workflow that is not defined in the repository's own
+// .github/workflows directory comes from.
+type WorkflowSource struct {
+	Kind       string `json:"kind"`                 // required or dynamic
+	Repository string `json:"repository,omitempty"` // owner/repo defining a required workflow, when known
+	Path       string `json:"path,omitempty"`       // Path of the workflow file in that repository
+	Ref        string `json:"ref,omitempty"`        // Ref the required workflow is read from
+}
+
+var (
+	// externalWorkflowPath matches workflow paths qualified with their repository, e.g.
+	// octo-org/.github/.github/workflows/ci.yml.
+	externalWorkflowPath = regexp.MustCompile(`^([^/]+/[^/]+)/(\.github/workflows/.+)$`)
+	// workflowAPIURL matches the API URL of a workflow.
+	workflowAPIURL = regexp.MustCompile(`/repos/([^/]+/[^/]+)/actions/workflows/`)
+)
+
+// workflowSource classifies a workflow of repository (owner/repo) by its path and API URL.
+// It returns nil for workflows defined in the repository itself.
+func workflowSource(repository, path, workflowURL string) *WorkflowSource {
+	if strings.HasPrefix(path, "dynamic/") {
+		return &WorkflowSource{Kind: WorkflowSourceDynamic, Path: path}
+	}
+
+	// Required workflows carry the ref they are read from: path@ref.
+	src := &WorkflowSource{Kind: WorkflowSourceRequired, Path: path}
+	if i := strings.LastIndex(path, "@"); i >= 0 {
+		src.Path, src.Ref = path[:i], path[i+1:]
+	}
+	if m := externalWorkflowPath.FindStringSubmatch(src.Path); m != nil && !strings.HasPrefix(src.Path, ".github/") {
+		src.Repository, src.Path = m[1], m[2]
+	}
+	if src.Repository == "" {
+		if m := workflowAPIURL.FindStringSubmatch(workflowURL); m != nil && !strings.EqualFold(m[1], repository) {
+			src.Repository = m[1]
+		}
+	}
+	if src.Ref == "" && src.Repository == "" {
+		return nil
+	}
+	return src
+}
+
+// readWorkflowSource reads the file of a required workflow from the repository that
+// defines it. ok is false when the source does not name that repository.
+func (c *Client) readWorkflowSource(ctx context.Context, src *WorkflowSource) (data []byte, ok bool, err error) {
+	if src == nil || src.Kind != WorkflowSourceRequired || src.Repository == "" {
+		return nil, false, nil
+	}
+	owner, repo, _ := strings.Cut(src.Repository, "/")
+	data, err = c.getRepoFile(ctx, owner, repo, src.Path, src.Ref)
+	return data, true, err
+}
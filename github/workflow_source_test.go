@@ -0,0 +1,50 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		workflowURL string
+		want        *WorkflowSource
+	}{
+		{
+			name:        "repository workflow",
+			path:        ".github/workflows/ci.yml",
+			workflowURL: "https://api.github.com/repos/octo/hello/actions/workflows/1",
+		},
+		{
+			name:        "required workflow qualified with its repository",
+			path:        "octo-org/.github/.github/workflows/required.yml@refs/heads/main",
+			workflowURL: "https://api.github.com/repos/octo-org/.github/actions/workflows/9",
+			want:        &WorkflowSource{Kind: WorkflowSourceRequired, Repository: "octo-org/.github", Path: ".github/workflows/required.yml", Ref: "refs/heads/main"},
+		},
+		{
+			name:        "required workflow identified by its API URL",
+			path:        ".github/workflows/required.yml@refs/heads/main",
+			workflowURL: "https://api.github.com/repos/octo-org/policies/actions/workflows/9",
+			want:        &WorkflowSource{Kind: WorkflowSourceRequired, Repository: "octo-org/policies", Path: ".github/workflows/required.yml", Ref: "refs/heads/main"},
+		},
+		{
+			name:        "workflow URL of the same repository in another case",
+			path:        ".github/workflows/ci.yml",
+			workflowURL: "https://api.github.com/repos/Octo/Hello/actions/workflows/1",
+		},
+		{
+			name: "dynamic workflow",
+			path: "dynamic/dependabot/dependabot-updates",
+			want: &WorkflowSource{Kind: WorkflowSourceDynamic, Path: "dynamic/dependabot/dependabot-updates"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, workflowSource("octo/hello", tt.path, tt.workflowURL))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package github
+
+import "strings"
+
+// Workflow states reported by GitHub.
+const (
+	WorkflowStateActive             = "active"
+	WorkflowStateDisabledInactivity = "disabled_inactivity"
+	WorkflowStateDisabledManually   = "disabled_manually"
+	WorkflowStateDisabledFork       = "disabled_fork"
+)
+
+// isWorkflowDisabled reports whether a workflow state keeps the workflow from running.
+func isWorkflowDisabled(state string) bool {
+	return strings.HasPrefix(state, "disabled")
+}
+
+// workflowDisabledReason explains a disabled workflow state, or returns "" for an
+// enabled workflow.
+func workflowDisabledReason(state string) string {
+	switch state {
+	case WorkflowStateDisabledInactivity:
+		return "disabled by GitHub after 60 days without repository activity; scheduled runs no longer start"
+	case WorkflowStateDisabledManually:
+		return "disabled manually; no event starts it"
+	case WorkflowStateDisabledFork:
+		return "disabled because the repository is a fork"
+	}
+	if isWorkflowDisabled(state) {
+		return state
+	}
+	return ""
+}
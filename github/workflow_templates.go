@@ -0,0 +1,294 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// WorkflowTemplateOptions parameterizes a generated starter workflow.
+type WorkflowTemplateOptions struct {
+	Stack     string   // go, node, docker, release
+	Name      string   // Workflow name (default depends on the stack)
+	Branches  []string // Branches that trigger the workflow (default: main)
+	Version   string   // Go or Node version (default: stable/lts)
+	Image     string   // docker: image name (default: ${{ github.repository }})
+	Registry  string   // docker: registry (default: ghcr.io)
+	Platforms string   // docker: comma-separated build platforms
+	Command   string   // Optional: override the build/test command
+}
+
+// GeneratedWorkflow is a starter workflow ready to be committed to the repository.
+type GeneratedWorkflow struct {
+	Stack string   `json:"stack"`
+	Path  string   `json:"path"`
+	YAML  string   `json:"yaml"`
+	Notes []string `json:"notes,omitempty"`
+}
+
+type workflowTemplate struct {
+	name     string
+	file     string
+	defaults func(*WorkflowTemplateOptions)
+	notes    []string
+	body     string
+}
+
+// The templates use [[ ]] delimiters so GitHub's ${{ }} expressions pass through untouched.
+var workflowTemplates = map[string]workflowTemplate{
+	"go": {
+		name: "Go",
+		file: "go.yml",
+		defaults: func(o *WorkflowTemplateOptions) {
+			if o.Version == "" {
+				o.Version = "stable"
+			}
+			if o.Command == "" {
+				o.Command = "go test -race ./..."
+			}
+		},
+		body: `name: [[ .Name ]]
+
+on:
+  push:
+    branches: [[ .BranchList ]]
+  pull_request:
+    branches: [[ .BranchList ]]
+
+permissions:
+  contents: read
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+
+      - uses: actions/setup-go@v5
+        with:
+          go-version: '[[ .Version ]]'
+          cache: true
+
+      - name: Build
+        run: go build ./...
+
+      - name: Vet
+        run: go vet ./...
+
+      - name: Test
+        run: [[ .Command ]]
+`,
+	},
+	"node": {
+		name: "Node.js",
+		file: "node.yml",
+		defaults: func(o *WorkflowTemplateOptions) {
+			if o.Version == "" {
+				o.Version = "lts/*"
+			}
+			if o.Command == "" {
+				o.Command = "npm test"
+			}
+		},
+		body: `name: [[ .Name ]]
+
+on:
+  push:
+    branches: [[ .BranchList ]]
+  pull_request:
+    branches: [[ .BranchList ]]
+
+permissions:
+  contents: read
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+
+      - uses: actions/setup-node@v4
+        with:
+          node-version: '[[ .Version ]]'
+          cache: npm
+
+      - name: Install dependencies
+        run: npm ci
+
+      - name: Build
+        run: npm run build --if-present
+
+      - name: Test
+        run: [[ .Command ]]
+`,
+	},
+	"docker": {
+		name: "Docker",
+		file: "docker.yml",
+		defaults: func(o *WorkflowTemplateOptions) {
+			if o.Registry == "" {
+				o.Registry = "ghcr.io"
+			}
+			if o.Image == "" {
+				o.Image = "${{ github.repository }}"
+			}
+			if o.Platforms == "" {
+				o.Platforms = "linux/amd64"
+			}
+		},
+		notes: []string{
+			"Images are pushed on branch and tag pushes; pull requests only build.",
+			"For registries other than ghcr.io, add REGISTRY_USERNAME and REGISTRY_PASSWORD secrets and update the login step.",
+		},
+		body: `name: [[ .Name ]]
+
+on:
+  push:
+    branches: [[ .BranchList ]]
+    tags: ['v*']
+  pull_request:
+    branches: [[ .BranchList ]]
+
+permissions:
+  contents: read
+  packages: write
+
+env:
+  REGISTRY: [[ .Registry ]]
+  IMAGE_NAME: [[ .Image ]]
+
+jobs:
+  build-and-push:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+
+      - uses: docker/setup-qemu-action@v3
+
+      - uses: docker/setup-buildx-action@v3
+
+      - name: Log in to the registry
+        if: github.event_name != 'pull_request'
+        uses: docker/login-action@v3
+        with:
+          registry: ${{ env.REGISTRY }}
+          username: ${{ github.actor }}
+          password: ${{ secrets.GITHUB_TOKEN }}
+
+      - name: Extract metadata
+        id: meta
+        uses: docker/metadata-action@v5
+        with:
+          images: ${{ env.REGISTRY }}/${{ env.IMAGE_NAME }}
+
+      - name: Build and push
+        uses: docker/build-push-action@v6
+        with:
+          context: .
+          platforms: [[ .Platforms ]]
+          push: ${{ github.event_name != 'pull_request' }}
+          tags: ${{ steps.meta.outputs.tags }}
+          labels: ${{ steps.meta.outputs.labels }}
+          cache-from: type=gha
+          cache-to: type=gha,mode=max
+`,
+	},
+	"release": {
+		name: "Release",
+		file: "release.yml",
+		defaults: func(o *WorkflowTemplateOptions) {
+			if o.Version == "" {
+				o.Version = "stable"
+			}
+			if o.Command == "" {
+				o.Command = "release --clean"
+			}
+		},
+		notes: []string{
+			"Runs GoReleaser on version tags; add a .goreleaser.yaml to the repository to configure the build.",
+		},
+		body: `name: [[ .Name ]]
+
+on:
+  push:
+    tags: ['v*']
+
+permissions:
+  contents: write
+
+jobs:
+  release:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+
+      - uses: actions/setup-go@v5
+        with:
+          go-version: '[[ .Version ]]'
+
+      - uses: goreleaser/goreleaser-action@v6
+        with:
+          version: '~> v2'
+          args: [[ .Command ]]
+        env:
+          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+`,
+	},
+}
+
+// WorkflowTemplateStacks returns the stacks GenerateWorkflow supports.
+func WorkflowTemplateStacks() []string {
+	stacks := make([]string, 0, len(workflowTemplates))
+	for stack := range workflowTemplates {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+	return stacks
+}
+
+// GenerateWorkflow renders starter workflow YAML for a common stack.
+func GenerateWorkflow(opts WorkflowTemplateOptions) (*GeneratedWorkflow, error) {
+	stack := strings.ToLower(strings.TrimSpace(opts.Stack))
+	tmpl, ok := workflowTemplates[stack]
+	if !ok {
+		return nil, fmt.Errorf("unknown stack %q (supported: %s)", opts.Stack, strings.Join(WorkflowTemplateStacks(), ", "))
+	}
+
+	if opts.Name == "" {
+		opts.Name = tmpl.name
+	}
+	if len(opts.Branches) == 0 {
+		opts.Branches = []string{"main"}
+	}
+	tmpl.defaults(&opts)
+
+	quoted := make([]string, 0, len(opts.Branches))
+	for _, b := range opts.Branches {
+		quoted = append(quoted, fmt.Sprintf("'%s'", strings.ReplaceAll(b, "'", "''")))
+	}
+
+	t, err := template.New(stack).Delims("[[", "]]").Parse(tmpl.body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s template: %w", stack, err)
+	}
+
+	var buf bytes.Buffer
+	data := struct {
+		WorkflowTemplateOptions
+		BranchList string
+	}{opts, "[" + strings.Join(quoted, ", ") + "]"}
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s template: %w", stack, err)
+	}
+
+	return &GeneratedWorkflow{
+		Stack: stack,
+		Path:  ".github/workflows/" + tmpl.file,
+		YAML:  buf.String(),
+		Notes: tmpl.notes,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateWorkflow(t *testing.T) {
+	for _, stack := range WorkflowTemplateStacks() {
+		t.Run(stack, func(t *testing.T) {
+			wf, err := GenerateWorkflow(WorkflowTemplateOptions{Stack: stack})
+			require.NoError(t, err)
+			assert.Contains(t, wf.Path, ".github/workflows/")
+
+			var doc map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(wf.YAML), &doc), wf.YAML)
+			assert.Contains(t, doc, "jobs")
+			assert.NotContains(t, wf.YAML, "[[")
+		})
+	}
+
+	wf, err := GenerateWorkflow(WorkflowTemplateOptions{
+		Stack:    "Go",
+		Name:     "Build",
+		Branches: []string{"main", "release/*"},
+		Version:  "1.24",
+		Command:  "make test",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "go", wf.Stack)
+
+	info, err := ParseWorkflowDispatchInfo([]byte(wf.YAML))
+	require.NoError(t, err)
+	assert.False(t, info.Dispatchable)
+	assert.Contains(t, wf.YAML, "name: Build\n")
+	assert.Contains(t, wf.YAML, "branches: ['main', 'release/*']")
+	assert.Contains(t, wf.YAML, "go-version: '1.24'")
+	assert.Contains(t, wf.YAML, "run: make test")
+
+	docker, err := GenerateWorkflow(WorkflowTemplateOptions{Stack: "docker", Platforms: "linux/amd64,linux/arm64"})
+	require.NoError(t, err)
+	assert.Contains(t, docker.YAML, "IMAGE_NAME: ${{ github.repository }}")
+	assert.Contains(t, docker.YAML, "platforms: linux/amd64,linux/arm64")
+	assert.NotEmpty(t, docker.Notes)
+
+	_, err = GenerateWorkflow(WorkflowTemplateOptions{Stack: "cobol"})
+	assert.ErrorContains(t, err, "supported: docker, go, node, release")
+}
@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// WorkflowFileUpdateOptions describes a change to a workflow file.
+type WorkflowFileUpdateOptions struct {
+	Path    string // Under .github/workflows/
+	Content string
+	Message string // Commit message; "Update <path>" or "Add <path>" when empty
+	// Branch is the branch to commit to, or the base of NewBranch; the repository's
+	// default branch when empty.
+	Branch    string
+	NewBranch string // Optional: branch to create from Branch and commit to
+	// Actionlint includes actionlint's findings in the validation when it is installed.
+	Actionlint bool
+	// Force commits content that fails validation.
+	Force bool
+}
+
+// WorkflowFileUpdate is the outcome of UpdateWorkflowFile.
+type WorkflowFileUpdate struct {
+	Path          string              `json:"path"`
+	Branch        string              `json:"branch"` // Branch committed to
+	Base          string              `json:"base,omitempty"`
+	BranchCreated bool                `json:"branch_created,omitempty"`
+	Created       bool                `json:"created,omitempty"`   // The file did not exist before
+	Unchanged     bool                `json:"unchanged,omitempty"` // The file already had this content; nothing was committed
+	CommitSHA     string              `json:"commit_sha,omitempty"`
+	CommitURL     string              `json:"commit_url,omitempty"`
+	Validation    *WorkflowValidation `json:"validation"`
+}
+
+// UpdateWorkflowFile validates workflow YAML and commits it to a workflow file through the
+// contents API, on an existing branch or on a new branch created for the change. Content
+// with validation issues is refused unless opts.Force is set. The branch written to must
+// be allowed by allowed_trigger_refs. Writing workflow files needs a token with the
+// workflow scope (classic) or the Workflows write permission (fine-grained).
+func (c *Client) UpdateWorkflowFile(ctx context.Context, opts WorkflowFileUpdateOptions) (*WorkflowFileUpdate, error) {
+	filePath := strings.TrimPrefix(strings.TrimSpace(opts.Path), "/")
+	if !strings.HasPrefix(filePath, ".github/workflows/") || (path.Ext(filePath) != ".yml" && path.Ext(filePath) != ".yaml") {
+		return nil, fmt.Errorf("path %q is not a workflow file: it must be a .yml or .yaml file under .github/workflows/", opts.Path)
+	}
+
+	result := &WorkflowFileUpdate{Path: filePath}
+	result.Validation = ValidateWorkflowYAML(ctx, []byte(opts.Content), filePath, opts.Actionlint)
+	if !result.Validation.Valid && !opts.Force {
+		issues := make([]string, 0, len(result.Validation.Issues))
+		for _, issue := range result.Validation.Issues {
+			if issue.Line > 0 {
+				issues = append(issues, fmt.Sprintf("line %d: %s", issue.Line, issue.Message))
+			} else {
+				issues = append(issues, issue.Message)
+			}
+		}
+		return nil, fmt.Errorf("%s was not committed because it has %d validation issue(s): %s; fix them or set force to commit anyway", filePath, len(issues), strings.Join(issues, "; "))
+	}
+
+	base := opts.Branch
+	if base == "" {
+		repository, _, err := c.gh.Repositories.Get(ctx, c.owner, c.repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine default branch: %w", Classify(err))
+		}
+		base = repository.GetDefaultBranch()
+	}
+	result.Branch = base
+	if opts.NewBranch != "" {
+		result.Branch, result.Base = opts.NewBranch, base
+	}
+	if err := c.checkRefAllowed(result.Branch); err != nil {
+		return nil, fmt.Errorf("cannot write to branch %s: %w", result.Branch, err)
+	}
+
+	existing, err := c.GetWorkflowFile(ctx, filePath, base)
+	switch {
+	case err == nil && string(existing) == opts.Content:
+		result.Unchanged = true
+		return result, nil
+	case err != nil && !errors.Is(Classify(err), ErrNotFound):
+		return nil, fmt.Errorf("failed to read %s on %s: %w", filePath, base, Classify(err))
+	}
+	result.Created = err != nil
+
+	message := opts.Message
+	if message == "" {
+		message = "Update " + filePath
+		if result.Created {
+			message = "Add " + filePath
+		}
+	}
+
+	if opts.NewBranch != "" {
+		if _, _, err := c.createBranch(ctx, opts.NewBranch, base); err != nil {
+			return nil, err
+		}
+		result.BranchCreated = true
+	}
+
+	commit, err := c.commitFileChange(ctx, result.Branch, message, &FileChange{Path: filePath, Content: opts.Content})
+	if err != nil {
+		if IsHTTPError(err, http.StatusForbidden) || IsHTTPError(err, http.StatusNotFound) {
+			err = fmt.Errorf("%w (writing workflow files needs a token with the workflow scope, or the Workflows write permission for fine-grained tokens)", err)
+		}
+		if result.BranchCreated {
+			return nil, fmt.Errorf("branch %s was created but the commit failed: %w", result.Branch, err)
+		}
+		return nil, err
+	}
+	result.CommitSHA = commit.GetSHA()
+	result.CommitURL = commit.GetHTMLURL()
+	return result, nil
+}
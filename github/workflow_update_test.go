@@ -0,0 +1,102 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	githubapi "github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateWorkflowFile(t *testing.T) {
+	const (
+		owner   = "test-owner"
+		repo    = "test-repo"
+		current = "name: CI\non: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make test\n"
+		fixed   = "name: CI\non: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    timeout-minutes: 20\n    steps:\n      - run: make test\n"
+	)
+
+	var calls []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"default_branch": "main"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("basesha"))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "create branch")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ref": "refs/heads/fix/ci-timeout"}`))
+	})
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/contents/.github/workflows/ci.yml", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Query().Get("ref"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"type":     "file",
+				"sha":      "oldsha",
+				"encoding": "base64",
+				"content":  base64.StdEncoding.EncodeToString([]byte(current)),
+			})
+		case http.MethodPut:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "fix/ci-timeout", body["branch"])
+			assert.Equal(t, "Update .github/workflows/ci.yml", body["message"])
+			assert.Equal(t, "oldsha", body["sha"])
+			assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(fixed)), body["content"])
+			_, _ = w.Write([]byte(`{"commit": {"sha": "newsha", "html_url": "https://github.com/c/newsha"}}`))
+		}
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ghc := githubapi.NewClient(ts.Client()).WithAuthToken("test-token")
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ghc.BaseURL = baseURL
+
+	client := &Client{owner: owner, repo: repo, gh: ghc, perPageLimit: 50}
+	ctx := context.Background()
+
+	result, err := client.UpdateWorkflowFile(ctx, WorkflowFileUpdateOptions{Path: "/.github/workflows/ci.yml", Content: fixed, NewBranch: "fix/ci-timeout"})
+	require.NoError(t, err)
+	assert.Equal(t, "fix/ci-timeout", result.Branch)
+	assert.Equal(t, "main", result.Base)
+	assert.True(t, result.BranchCreated)
+	assert.False(t, result.Created)
+	assert.Equal(t, "newsha", result.CommitSHA)
+	assert.True(t, result.Validation.Valid)
+	assert.Equal(t, []string{"GET main", "create branch", "GET fix/ci-timeout", "PUT "}, calls)
+
+	calls = nil
+	result, err = client.UpdateWorkflowFile(ctx, WorkflowFileUpdateOptions{Path: ".github/workflows/ci.yml", Content: current})
+	require.NoError(t, err)
+	assert.True(t, result.Unchanged)
+	assert.Empty(t, result.CommitSHA)
+	assert.Equal(t, []string{"GET main"}, calls)
+
+	calls = nil
+	_, err = client.UpdateWorkflowFile(ctx, WorkflowFileUpdateOptions{Path: ".github/workflows/ci.yml", Content: "on: push\njobs:\n  test:\n    steps:\n      - run: make\n"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "validation issue")
+	assert.Contains(t, err.Error(), "set force")
+	assert.Empty(t, calls)
+
+	_, err = client.UpdateWorkflowFile(ctx, WorkflowFileUpdateOptions{Path: "Makefile", Content: fixed})
+	assert.ErrorContains(t, err, "not a workflow file")
+
+	client.allowedRefs = []string{"release/*"}
+	_, err = client.UpdateWorkflowFile(ctx, WorkflowFileUpdateOptions{Path: ".github/workflows/ci.yml", Content: fixed})
+	assert.ErrorIs(t, err, ErrRefNotAllowed)
+}
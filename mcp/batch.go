@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxBatchCalls bounds the tool calls of one batch.
+	maxBatchCalls = 20
+	// defaultBatchConcurrency is how many calls of a batch run at once by default.
+	defaultBatchConcurrency = 4
+	// maxBatchConcurrency bounds max_concurrency, to stay clear of GitHub's secondary rate limits.
+	maxBatchConcurrency = 8
+)
+
+// batchTools are the tools a batch may call: those that only read, and return without
+// waiting on runs. Tools that change state (including get_new_failures, which advances its
+// cursor) or block (wait_for_run) are left out.
+var batchTools = map[string]bool{
+	"analyze_path_filters":     true,
+	"analyze_timing":           true,
+	"bisect_failure":           true,
+	"compare_with_last_green":  true,
+	"diagnose_failure":         true,
+	"evaluate_expression":      true,
+	"generate_workflow":        true,
+	"get_actions_billing":      true,
+	"get_artifact":             true,
+	"get_artifact_expiry":      true,
+	"get_branch_policies":      true,
+	"get_cache_usage":          true,
+	"get_check_status":         true,
+	"get_concurrency_report":   true,
+	"get_environment_status":   true,
+	"get_failure_heatmap":      true,
+	"get_job_details":          true,
+	"get_metrics_snapshot":     true,
+	"get_multi_repo_status":    true,
+	"get_oidc_config":          true,
+	"get_pr_checks":            true,
+	"get_release_runs":         true,
+	"get_retention_policy":     true,
+	"get_run":                  true,
+	"get_run_annotations":      true,
+	"get_run_chain":            true,
+	"get_run_env":              true,
+	"get_run_timeline":         true,
+	"get_stale_branch_report":  true,
+	"get_timeout_report":       true,
+	"get_workflow_call_graph":  true,
+	"lint_workflow":            true,
+	"list_artifacts":           true,
+	"list_caches":              true,
+	"list_environments":        true,
+	"list_git_remotes":         true,
+	"list_pending_deployments": true,
+	"list_runs":                true,
+	"list_secrets":             true,
+	"list_variables":           true,
+	"list_workflow_jobs":       true,
+	"list_workflows":           true,
+	"trace_artifacts":          true,
+	"validate_workflow_yaml":   true,
+}
+
+// batchCall is one tool invocation of a batch.
+type batchCall struct {
+	Key       string
+	Tool      string
+	Arguments map[string]interface{}
+}
+
+// batchCallResult is the outcome of one call of a batch.
+type batchCallResult struct {
+	Tool       string      `json:"tool"`
+	IsError    bool        `json:"is_error,omitempty"`
+	Result     interface{} `json:"result"` // The tool's JSON output, or its text when it is not JSON
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// batchResult holds the results of a batch keyed by call key, and the keys in call order.
+type batchResult struct {
+	Keys    []string                    `json:"keys"`
+	Results map[string]*batchCallResult `json:"results"`
+	Errors  int                         `json:"errors"` // Calls that returned an error result
+}
+
+// parseBatchCalls reads the calls argument. A call without a key is keyed by its tool name,
+// or by "<tool>#<n>" (n counting from 1 in call order) when several calls use that tool.
+func parseBatchCalls(args map[string]interface{}) ([]*batchCall, error) {
+	raw, ok := args["calls"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("calls is required: a list of {tool, arguments} objects")
+	}
+	if len(raw) > maxBatchCalls {
+		return nil, fmt.Errorf("a batch may hold at most %d calls, got %d", maxBatchCalls, len(raw))
+	}
+
+	calls := make([]*batchCall, 0, len(raw))
+	uses := make(map[string]int)
+	for i, item := range raw {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("calls[%d] must be an object with tool and arguments", i)
+		}
+		call := &batchCall{}
+		call.Key, _ = obj["key"].(string)
+		call.Tool, _ = obj["tool"].(string)
+		call.Tool = strings.TrimSpace(call.Tool)
+		if call.Tool == "" {
+			return nil, fmt.Errorf("calls[%d] has no tool", i)
+		}
+		if !batchTools[call.Tool] {
+			return nil, fmt.Errorf("calls[%d]: %s cannot be batched; only read-only tools can (call it on its own instead)", i, call.Tool)
+		}
+		if a, present := obj["arguments"]; present && a != nil {
+			if call.Arguments, ok = a.(map[string]interface{}); !ok {
+				return nil, fmt.Errorf("calls[%d] (%s): arguments must be an object", i, call.Tool)
+			}
+		}
+		uses[call.Tool]++
+		calls = append(calls, call)
+	}
+
+	seen := make(map[string]bool)
+	n := make(map[string]int)
+	for i, call := range calls {
+		if call.Key == "" {
+			call.Key = call.Tool
+			if uses[call.Tool] > 1 {
+				n[call.Tool]++
+				call.Key = fmt.Sprintf("%s#%d", call.Tool, n[call.Tool])
+			}
+		}
+		if seen[call.Key] {
+			return nil, fmt.Errorf("calls[%d]: duplicate key %q", i, call.Key)
+		}
+		seen[call.Key] = true
+	}
+	return calls, nil
+}
+
+// runBatch calls the tools of a batch, at most concurrency at a time, and collects their
+// results. A call that fails is reported in its result; it does not stop the others.
+func (s *MCPServer) runBatch(ctx context.Context, calls []*batchCall, concurrency int) *batchResult {
+	result := &batchResult{Keys: make([]string, len(calls)), Results: make(map[string]*batchCallResult, len(calls))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, call := range calls {
+		result.Keys[i] = call.Key
+		wg.Add(1)
+		go func(call *batchCall) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			out := &batchCallResult{Tool: call.Tool}
+			res, err := s.InvokeTool(ctx, call.Tool, call.Arguments)
+			switch {
+			case err != nil:
+				out.IsError, out.Result = true, err.Error()
+			case res == nil:
+				out.Result = nil
+			default:
+				out.IsError = res.IsError
+				text := resultText(res)
+				if json.Valid([]byte(text)) {
+					out.Result = json.RawMessage(text)
+				} else {
+					out.Result = text
+				}
+			}
+			out.DurationMs = time.Since(start).Milliseconds()
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Results[call.Key] = out
+			if out.IsError {
+				result.Errors++
+			}
+		}(call)
+	}
+	wg.Wait()
+	return result
+}
+
+func (s *MCPServer) batch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	calls, err := parseBatchCalls(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	concurrency := defaultBatchConcurrency
+	if c, ok := args["max_concurrency"].(float64); ok && c > 0 {
+		concurrency = min(int(c), maxBatchConcurrency)
+	}
+
+	tools := make([]string, 0, len(calls))
+	for _, call := range calls {
+		tools = append(tools, call.Tool)
+	}
+	sort.Strings(tools)
+	s.log.WithContext(ctx).Infof("Running a batch of %d calls (%s)", len(calls), strings.Join(tools, ", "))
+
+	return jsonResultPretty(s.runBatch(ctx, calls, concurrency))
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBatchCalls(t *testing.T) {
+	calls, err := parseBatchCalls(map[string]interface{}{"calls": []interface{}{
+		map[string]interface{}{"tool": "get_run", "arguments": map[string]interface{}{"run_id": float64(1)}},
+		map[string]interface{}{"tool": "list_runs"},
+		map[string]interface{}{"tool": "get_run", "arguments": map[string]interface{}{"run_id": float64(2)}},
+		map[string]interface{}{"tool": "get_run", "key": "latest"},
+	}})
+	require.NoError(t, err)
+	keys := make([]string, 0, len(calls))
+	for _, call := range calls {
+		keys = append(keys, call.Key)
+	}
+	assert.Equal(t, []string{"get_run#1", "list_runs", "get_run#2", "latest"}, keys)
+
+	_, err = parseBatchCalls(map[string]interface{}{"calls": []interface{}{
+		map[string]interface{}{"tool": "list_runs"},
+		map[string]interface{}{"tool": "cancel_run", "arguments": map[string]interface{}{"run_id": float64(1)}},
+	}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "calls[1]: cancel_run cannot be batched")
+
+	_, err = parseBatchCalls(map[string]interface{}{"calls": []interface{}{
+		map[string]interface{}{"tool": "list_runs", "key": "runs"},
+		map[string]interface{}{"tool": "list_workflows", "key": "runs"},
+	}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `duplicate key "runs"`)
+
+	_, err = parseBatchCalls(map[string]interface{}{"calls": []interface{}{}})
+	require.Error(t, err)
+}
+
+func TestBatch(t *testing.T) {
+	srv := NewMCPServer(&config.Config{
+		Token:        "token",
+		RepoOwner:    "octo",
+		RepoName:     "hello-world",
+		PerPageLimit: 50,
+		StateDir:     t.TempDir(),
+	}, logrus.New())
+
+	result, err := srv.batch(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "batch", Arguments: map[string]interface{}{"calls": []interface{}{
+			map[string]interface{}{"tool": "evaluate_expression", "key": "sum", "arguments": map[string]interface{}{"expression": "1 == 1"}},
+			map[string]interface{}{"tool": "evaluate_expression", "key": "missing", "arguments": map[string]interface{}{}},
+		}}},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var got struct {
+		Keys    []string `json:"keys"`
+		Errors  int      `json:"errors"`
+		Results map[string]struct {
+			Tool    string          `json:"tool"`
+			IsError bool            `json:"is_error"`
+			Result  json.RawMessage `json:"result"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &got))
+	assert.Equal(t, []string{"sum", "missing"}, got.Keys)
+	assert.Equal(t, 1, got.Errors)
+
+	var expression struct {
+		Result bool `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(got.Results["sum"].Result, &expression))
+	assert.True(t, expression.Result)
+	assert.False(t, got.Results["sum"].IsError)
+
+	assert.True(t, got.Results["missing"].IsError)
+	assert.Equal(t, `"expression is required"`, string(got.Results["missing"].Result))
+}
@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/denysvitali/gh-actions-mcp/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// cacheProvenanceField is the name of the cache provenance in result metadata.
+const cacheProvenanceField = "cache"
+
+// withRefresh returns ctx set to bypass the response and job log caches when the tool
+// call's refresh argument is true.
+func withRefresh(ctx context.Context, args map[string]interface{}) context.Context {
+	if refresh, _ := args["refresh"].(bool); refresh {
+		return github.WithCacheBypass(ctx)
+	}
+	return ctx
+}
+
+// cacheProvenanceMiddleware records where the API responses of a tool call came from, and
+// returns it in the result's _meta.cache as {cached, as_of, source, requests}, so a client
+// can tell data revalidated with GitHub (etag) or read from the job log cache (disk) from
+// data just downloaded (live). Calls that made no API requests get no cache metadata. Any
+// tool call passing refresh: true skips the caches.
+func cacheProvenanceMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, trace := github.WithCacheTrace(withRefresh(ctx, request.GetArguments()))
+		result, err := next(ctx, request)
+		if result == nil {
+			return result, err
+		}
+		if provenance := trace.Provenance(); provenance != nil {
+			setMetaField(result, cacheProvenanceField, provenance)
+		}
+		return result, err
+	}
+}
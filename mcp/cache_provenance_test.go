@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/denysvitali/gh-actions-mcp/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheProvenanceMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/octo/hello-world/actions/workflows", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"abc"`)
+		_, _ = w.Write([]byte(`{"total_count": 1, "workflows": [{"id": 1, "name": "CI", "path": ".github/workflows/ci.yml", "state": "active"}]}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	server := NewMCPServer(&config.Config{
+		Token:        "token",
+		RepoOwner:    "octo",
+		RepoName:     "hello-world",
+		APIBaseURL:   ts.URL + "/",
+		UploadURL:    ts.URL + "/",
+		PerPageLimit: 50,
+		StateDir:     t.TempDir(),
+	}, logrus.New())
+
+	handler := cacheProvenanceMiddleware(server.listWorkflows)
+	call := func(args map[string]interface{}) *github.CacheProvenance {
+		result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "list_workflows", Arguments: args}})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		require.NotNil(t, result.Meta)
+		provenance, ok := result.Meta.AdditionalFields["cache"].(*github.CacheProvenance)
+		require.True(t, ok)
+		return provenance
+	}
+
+	first := call(nil)
+	assert.False(t, first.Cached)
+	assert.Equal(t, github.CacheSourceLive, first.Source)
+
+	second := call(nil)
+	assert.True(t, second.Cached)
+	assert.Equal(t, github.CacheSourceETag, second.Source)
+	assert.NotEmpty(t, second.AsOf)
+
+	refreshed := call(map[string]interface{}{"refresh": true})
+	assert.False(t, refreshed.Cached)
+	assert.Equal(t, github.CacheSourceLive, refreshed.Source)
+
+	// A call that makes no API request gets no cache metadata.
+	result, err := cacheProvenanceMiddleware(server.evaluateExpression)(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "evaluate_expression", Arguments: map[string]interface{}{"expression": "1 == 1"}},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, result.Meta)
+}
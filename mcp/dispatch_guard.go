@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denysvitali/gh-actions-mcp/github"
+)
+
+// dispatchRecord remembers a workflow dispatch so identical retries can be detected.
+type dispatchRecord struct {
+	at    time.Time
+	runID int64
+	done  bool
+}
+
+// dispatchGuard detects identical workflow dispatches (same repository, workflow,
+// ref, and inputs) issued within a time window, so a retry loop does not spawn a
+// pile of identical runs.
+type dispatchGuard struct {
+	mu     sync.Mutex
+	window time.Duration
+	recent map[string]*dispatchRecord
+	now    func() time.Time
+}
+
+// newDispatchGuard returns a guard with a window of windowSeconds; 0 disables it.
+func newDispatchGuard(windowSeconds int) *dispatchGuard {
+	return &dispatchGuard{
+		window: time.Duration(windowSeconds) * time.Second,
+		recent: make(map[string]*dispatchRecord),
+		now:    time.Now,
+	}
+}
+
+// enabled reports whether the guard checks dispatches at all.
+func (g *dispatchGuard) enabled() bool {
+	return g != nil && g.window > 0
+}
+
+// dispatchKey identifies a dispatch by repository, resolved workflow ID and ref, and
+// inputs, so naming the workflow by name, path, or ID, or omitting the default ref, does
+// not change it. json.Marshal sorts map keys, so equal inputs always produce the same key.
+func dispatchKey(owner, repo string, target *github.DispatchTarget, inputs map[string]interface{}) string {
+	encoded, _ := json.Marshal(inputs)
+	return strings.ToLower(fmt.Sprintf("%s/%s", owner, repo)) + "\x00" + strconv.FormatInt(target.WorkflowID, 10) + "\x00" + target.Ref + "\x00" + string(encoded)
+}
+
+// reserve records a dispatch for key. If an identical dispatch happened within
+// the window, it returns a copy of that record and false instead.
+func (g *dispatchGuard) reserve(key string) (dispatchRecord, bool) {
+	if !g.enabled() {
+		return dispatchRecord{}, true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	for k, r := range g.recent {
+		if now.Sub(r.at) >= g.window {
+			delete(g.recent, k)
+		}
+	}
+
+	if prev, ok := g.recent[key]; ok {
+		return *prev, false
+	}
+	g.recent[key] = &dispatchRecord{at: now}
+	return dispatchRecord{}, true
+}
+
+// complete stores the run a reserved dispatch created.
+func (g *dispatchGuard) complete(key string, runID int64) {
+	if !g.enabled() {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if r, ok := g.recent[key]; ok {
+		r.runID = runID
+		r.done = true
+	}
+}
+
+// release forgets a reservation whose dispatch failed, so it can be retried.
+func (g *dispatchGuard) release(key string) {
+	if !g.enabled() {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.recent, key)
+}
+
+// describeDuplicate explains an earlier identical dispatch for error and warning messages.
+func (g *dispatchGuard) describeDuplicate(prev dispatchRecord, workflow, ref string) string {
+	ago := g.now().Sub(prev.at).Round(time.Second)
+	msg := fmt.Sprintf("identical dispatch of %s (ref: %s) was issued %s ago", workflow, ref, ago)
+	switch {
+	case prev.runID != 0:
+		msg += fmt.Sprintf(" and created run %d", prev.runID)
+	case !prev.done:
+		msg += " and is still in flight"
+	}
+	return msg + fmt.Sprintf("; duplicates are blocked for %s", g.window)
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denysvitali/gh-actions-mcp/github"
+	"github.com/denysvitali/gh-actions-mcp/state"
+	"github.com/sirupsen/logrus"
+)
+
+// State document holding the last inputs dispatched to each workflow, keyed by
+// repository and workflow ID.
+const (
+	dispatchInputsState  = "dispatch_inputs"
+	dispatchInputsSchema = 1
+)
+
+// lastDispatch records the inputs of the most recent dispatch of a workflow.
+type lastDispatch struct {
+	Ref          string                 `json:"ref"`
+	Inputs       map[string]interface{} `json:"inputs"`
+	DispatchedAt time.Time              `json:"dispatched_at"`
+}
+
+// dispatchInputs remembers the inputs used for each workflow so reuse_last_inputs can
+// repeat a manual dispatch without retyping its parameters.
+type dispatchInputs struct {
+	mu    sync.Mutex
+	store *state.Store
+	log   *logrus.Logger
+	last  map[string]*lastDispatch
+}
+
+func newDispatchInputs(store *state.Store, log *logrus.Logger) *dispatchInputs {
+	d := &dispatchInputs{store: store, log: log, last: make(map[string]*lastDispatch)}
+	if store != nil {
+		if _, err := store.Load(dispatchInputsState, dispatchInputsSchema, &d.last); err != nil {
+			log.Warnf("Ignoring saved dispatch inputs: %v", err)
+		}
+	}
+	return d
+}
+
+// dispatchInputsKey identifies a workflow by repository and ID, so that selecting it by
+// name, path, or ID finds the same entry.
+func dispatchInputsKey(owner, repo string, workflowID int64) string {
+	return fmt.Sprintf("%s/%d", strings.ToLower(owner+"/"+repo), workflowID)
+}
+
+// get returns the last dispatch of a workflow, or nil.
+func (d *dispatchInputs) get(key string) *lastDispatch {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.last[key]
+}
+
+// remember records inputs as the last used for a workflow. The correlation input is left
+// out because its marker is unique to each dispatch.
+func (d *dispatchInputs) remember(key string, opts github.DispatchOptions) {
+	inputs := make(map[string]interface{}, len(opts.Inputs))
+	for k, v := range opts.Inputs {
+		if k != opts.CorrelationInput {
+			inputs[k] = v
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.last[key] = &lastDispatch{Ref: opts.Ref, Inputs: inputs, DispatchedAt: time.Now().UTC()}
+	if d.store != nil {
+		if err := d.store.Save(dispatchInputsState, dispatchInputsSchema, d.last); err != nil {
+			d.log.Warnf("Failed to persist dispatch inputs: %v", err)
+		}
+	}
+}
+
+// mergeLastInputs fills inputs missing from explicit with the values from last, and
+// returns the merged inputs with the sorted names of the reused ones.
+func mergeLastInputs(explicit map[string]interface{}, last *lastDispatch) (map[string]interface{}, []string) {
+	merged := make(map[string]interface{}, len(explicit)+len(last.Inputs))
+	var reused []string
+	for k, v := range last.Inputs {
+		if _, ok := explicit[k]; !ok {
+			merged[k] = v
+			reused = append(reused, k)
+		}
+	}
+	for k, v := range explicit {
+		merged[k] = v
+	}
+	sort.Strings(reused)
+	return merged, reused
+}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// jobLogURITemplate addresses the log of a single job. Clients that support resource
+// subscriptions receive notifications/resources/updated as a running job writes output.
+const jobLogURITemplate = "gh-actions://{owner}/{repo}/jobs/{job_id}/logs"
+
+var jobLogURIPattern = regexp.MustCompile(`^gh-actions://([^/]+)/([^/]+)/jobs/(\d+)/logs$`)
+
+// jobLogPollInterval is how often subscribed job logs are checked for new output.
+const jobLogPollInterval = 5 * time.Second
+
+// jobLogSubscriptions tracks the job logs clients have subscribed to, each followed by its
+// own polling goroutine until the job completes or the client unsubscribes.
+type jobLogSubscriptions struct {
+	mu        sync.Mutex
+	followers map[string]context.CancelFunc
+	interval  time.Duration
+}
+
+func (s *MCPServer) registerResources() {
+	s.srv.AddResourceTemplate(mcp.NewResourceTemplate(jobLogURITemplate, "Job log",
+		mcp.WithTemplateDescription("Log of a workflow job. Subscribe to receive updates while the job is running."),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), s.readJobLog)
+	s.srv.AddResourceTemplate(mcp.NewResourceTemplate(workflowInputsURITemplate, "Workflow inputs",
+		mcp.WithTemplateDescription("JSON Schema of a workflow's workflow_dispatch inputs, addressed by workflow ID or file name (e.g. ci.yml), for generating trigger_workflow input forms."),
+		mcp.WithTemplateMIMEType("application/schema+json"),
+	), s.readWorkflowInputs)
+	if s.scratch != nil {
+		s.srv.AddResourceTemplate(mcp.NewResourceTemplate(scratchURITemplate, "Scratch output",
+			mcp.WithTemplateDescription("Full output of a tool call that was too large to return inline. Expires after scratch_ttl seconds."),
+		), s.readScratch)
+	}
+}
+
+// parseJobLogURI returns the repository and job ID addressed by a job log URI.
+func parseJobLogURI(uri string) (string, string, int64, error) {
+	m := jobLogURIPattern.FindStringSubmatch(uri)
+	if m == nil {
+		return "", "", 0, fmt.Errorf("unknown resource %q: expected %s", uri, jobLogURITemplate)
+	}
+	jobID, err := strconv.ParseInt(m[3], 10, 64)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid job ID in %q: %w", uri, err)
+	}
+	return m[1], m[2], jobID, nil
+}
+
+func (s *MCPServer) readJobLog(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+	owner, repo, jobID, err := parseJobLogURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.clientForRepo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infof("Reading log resource for job %d in %s/%s", jobID, owner, repo)
+	chunk, err := client.ReadJobLogFrom(ctx, jobID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s", s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get logs for job %d", jobID), owner, repo))
+	}
+
+	text, masked := s.maskSecrets(chunk.Content)
+	contents := mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: "text/plain",
+		Text:     s.outputs.applyResource(text),
+	}
+	if masked > 0 {
+		contents.Meta = map[string]any{secretsMaskedField: masked}
+	}
+	return []mcp.ResourceContents{contents}, nil
+}
+
+// subscribeJobLog starts following the job log at uri. Subscribing twice is a no-op.
+func (s *MCPServer) subscribeJobLog(uri string) error {
+	owner, repo, jobID, err := parseJobLogURI(uri)
+	if err != nil {
+		return err
+	}
+	client, err := s.clientForRepo(owner, repo)
+	if err != nil {
+		return err
+	}
+
+	s.logSubs.mu.Lock()
+	defer s.logSubs.mu.Unlock()
+	if _, ok := s.logSubs.followers[uri]; ok {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.logSubs.followers[uri] = cancel
+
+	s.log.Infof("Following log of job %d in %s/%s", jobID, owner, repo)
+	go s.followJobLog(ctx, uri, func(ctx context.Context, offset int64) (int64, bool, error) {
+		chunk, err := client.ReadJobLogFrom(ctx, jobID, offset)
+		if err != nil {
+			return offset, false, err
+		}
+		return chunk.Offset, chunk.Completed, nil
+	})
+	return nil
+}
+
+// unsubscribeJobLog stops following the job log at uri.
+func (s *MCPServer) unsubscribeJobLog(uri string) {
+	s.logSubs.mu.Lock()
+	defer s.logSubs.mu.Unlock()
+	if cancel, ok := s.logSubs.followers[uri]; ok {
+		cancel()
+		delete(s.logSubs.followers, uri)
+	}
+}
+
+// stopJobLogFollowers stops following all job logs.
+func (s *MCPServer) stopJobLogFollowers() {
+	s.logSubs.mu.Lock()
+	defer s.logSubs.mu.Unlock()
+	for uri, cancel := range s.logSubs.followers {
+		cancel()
+		delete(s.logSubs.followers, uri)
+	}
+}
+
+// followJobLog polls fetch, which returns the log offset reached and whether the job has
+// completed, and notifies subscribers whenever the log grows. Only the bytes past the last
+// offset are fetched on each poll. It sends a final notification when the job completes.
+func (s *MCPServer) followJobLog(ctx context.Context, uri string, fetch func(ctx context.Context, offset int64) (int64, bool, error)) {
+	// Start from the log as it is now; subscribers read it themselves.
+	offset, completed, err := fetch(ctx, 0)
+	if err != nil {
+		s.log.Debugf("Could not read %s: %v", uri, err)
+	}
+	if completed {
+		s.unsubscribeJobLog(uri)
+		return
+	}
+
+	ticker := time.NewTicker(s.logSubs.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		next, completed, err := fetch(ctx, offset)
+		if err != nil {
+			s.log.Debugf("Could not read %s: %v", uri, err)
+			continue
+		}
+		if next != offset || completed {
+			offset = next
+			s.srv.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		}
+		if completed {
+			s.unsubscribeJobLog(uri)
+			return
+		}
+	}
+}
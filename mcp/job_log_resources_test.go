@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJobLogURI(t *testing.T) {
+	owner, repo, jobID, err := parseJobLogURI("gh-actions://octo/hello/jobs/42/logs")
+	require.NoError(t, err)
+	assert.Equal(t, "octo", owner)
+	assert.Equal(t, "hello", repo)
+	assert.Equal(t, int64(42), jobID)
+
+	_, _, _, err = parseJobLogURI("gh-actions://octo/hello/runs/42")
+	assert.Error(t, err)
+}
+
+func TestParseWorkflowInputsURI(t *testing.T) {
+	owner, repo, workflow, err := parseWorkflowInputsURI("gh-actions://octo/hello/workflows/ci.yml/inputs")
+	require.NoError(t, err)
+	assert.Equal(t, "octo", owner)
+	assert.Equal(t, "hello", repo)
+	assert.Equal(t, ".github/workflows/ci.yml", workflow)
+
+	_, _, workflow, err = parseWorkflowInputsURI("gh-actions://octo/hello/workflows/161335/inputs")
+	require.NoError(t, err)
+	assert.Equal(t, "161335", workflow)
+
+	_, _, _, err = parseWorkflowInputsURI("gh-actions://octo/hello/workflows/161335")
+	assert.Error(t, err)
+}
+
+func TestHandleSubscriptionRequest(t *testing.T) {
+	s := NewMCPServer(&config.Config{Token: "t", RepoOwner: "o", RepoName: "r", StateDir: t.TempDir()}, logrus.New())
+	defer s.stopJobLogFollowers()
+
+	resp, ok := s.handleSubscriptionRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"gh-actions://o/r/runs/1"}}`))
+	require.True(t, ok)
+	_, isErr := resp.(mcp.JSONRPCError)
+	assert.True(t, isErr)
+
+	_, ok = s.handleSubscriptionRequest([]byte(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+	assert.False(t, ok)
+
+	const uri = "gh-actions://o/r/jobs/5/logs"
+	resp, ok = s.handleSubscriptionRequest([]byte(`{"jsonrpc":"2.0","id":3,"method":"resources/subscribe","params":{"uri":"` + uri + `"}}`))
+	require.True(t, ok)
+	_, isResult := resp.(mcp.JSONRPCResponse)
+	assert.True(t, isResult)
+	s.logSubs.mu.Lock()
+	assert.Contains(t, s.logSubs.followers, uri)
+	s.logSubs.mu.Unlock()
+
+	_, ok = s.handleSubscriptionRequest([]byte(`{"jsonrpc":"2.0","id":4,"method":"resources/unsubscribe","params":{"uri":"` + uri + `"}}`))
+	require.True(t, ok)
+	s.logSubs.mu.Lock()
+	assert.NotContains(t, s.logSubs.followers, uri)
+	s.logSubs.mu.Unlock()
+}
+
+func TestFollowJobLog_StopsWhenJobCompletes(t *testing.T) {
+	s := NewMCPServer(&config.Config{Token: "t", RepoOwner: "o", RepoName: "r", StateDir: t.TempDir()}, logrus.New())
+	s.logSubs.interval = time.Millisecond
+	const uri = "gh-actions://o/r/jobs/5/logs"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.logSubs.followers[uri] = cancel
+
+	var calls atomic.Int32
+	var offsets []int64
+	done := make(chan struct{})
+	go func() {
+		s.followJobLog(ctx, uri, func(ctx context.Context, offset int64) (int64, bool, error) {
+			offsets = append(offsets, offset)
+			n := calls.Add(1)
+			return int64(n) * 10, n == 3, nil
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("follower did not stop")
+	}
+	assert.Equal(t, []int64{0, 10, 20}, offsets)
+	assert.NotContains(t, s.logSubs.followers, uri)
+}
+
+func TestInterceptSubscriptions(t *testing.T) {
+	s := NewMCPServer(&config.Config{Token: "t", RepoOwner: "o", RepoName: "r", StateDir: t.TempDir()}, logrus.New())
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"bogus"}}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n")
+	var out bytes.Buffer
+
+	passed, err := io.ReadAll(s.interceptSubscriptions(in, &lockedWriter{w: &out}))
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`+"\n", string(passed))
+	assert.Contains(t, out.String(), `"id":1`)
+	assert.Contains(t, out.String(), `unknown resource`)
+}
+
+func TestReadJobLog_AppliesOutputTransforms(t *testing.T) {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/repos/o/r/actions/jobs/5", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 5, "status": "completed", "conclusion": "failure"}`))
+	})
+	mux.HandleFunc("/repos/o/r/actions/jobs/5/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", ts.URL+"/blob/job.log")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/blob/job.log", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("fetch from acme-build.internal failed\n"))
+	})
+
+	s := NewMCPServer(&config.Config{
+		Token:      "t",
+		RepoOwner:  "o",
+		RepoName:   "r",
+		APIBaseURL: ts.URL + "/",
+		UploadURL:  ts.URL + "/",
+		StateDir:   t.TempDir(),
+		OutputTransforms: config.OutputTransforms{
+			// Resources are transformed whatever tools lists.
+			Tools:   []string{"list_runs"},
+			Replace: []config.Replacement{{Pattern: `acme-[a-z]+\.internal`, Replacement: "[internal-host]"}},
+		},
+	}, logrus.New())
+
+	contents, err := s.readJobLog(context.Background(), mcp.ReadResourceRequest{
+		Params: mcp.ReadResourceParams{URI: "gh-actions://o/r/jobs/5/logs"},
+	})
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	assert.Equal(t, "fetch from [internal-host] failed\n", contents[0].(mcp.TextResourceContents).Text)
+}
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/denysvitali/gh-actions-mcp/github"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolMetrics counts the calls, errors, and latencies of each tool.
+type toolMetrics struct {
+	startedAt time.Time
+
+	mu    sync.Mutex
+	tools map[string]*toolStats
+}
+
+type toolStats struct {
+	calls      int64
+	errors     int64
+	latency    time.Duration
+	maxLatency time.Duration
+	last       time.Time
+}
+
+// ToolMetrics is a snapshot of one tool's calls.
+type ToolMetrics struct {
+	Tool             string  `json:"tool"`
+	Calls            int64   `json:"calls"`
+	Errors           int64   `json:"errors"` // Calls that returned an error result
+	AverageLatencyMs float64 `json:"average_latency_ms"`
+	MaxLatencyMs     float64 `json:"max_latency_ms"`
+	LastCalledAt     string  `json:"last_called_at"`
+}
+
+// MetricsSnapshot is the server's health at a point in time.
+type MetricsSnapshot struct {
+	StartedAt     string               `json:"started_at"`
+	UptimeSeconds float64              `json:"uptime_seconds"`
+	API           *github.APIMetrics   `json:"api"`
+	Tools         []*ToolMetrics       `json:"tools"`            // Most called first
+	Tokens        []github.TokenStatus `json:"tokens,omitempty"` // Rate limits per token, when several are configured
+}
+
+func newToolMetrics() *toolMetrics {
+	return &toolMetrics{startedAt: time.Now(), tools: make(map[string]*toolStats)}
+}
+
+// middleware records each tool call's outcome and latency.
+func (m *toolMetrics) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+		m.record(request.Params.Name, time.Since(start), err != nil || (result != nil && result.IsError))
+		return result, err
+	}
+}
+
+func (m *toolMetrics) record(tool string, elapsed time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := m.tools[tool]
+	if stats == nil {
+		stats = &toolStats{}
+		m.tools[tool] = stats
+	}
+	stats.calls++
+	if failed {
+		stats.errors++
+	}
+	stats.latency += elapsed
+	if elapsed > stats.maxLatency {
+		stats.maxLatency = elapsed
+	}
+	stats.last = time.Now()
+}
+
+// snapshot returns the counters of the tools called so far and of the API requests made.
+func (m *toolMetrics) snapshot() *MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := &MetricsSnapshot{
+		StartedAt:     m.startedAt.UTC().Format(time.RFC3339),
+		UptimeSeconds: math.Round(time.Since(m.startedAt).Seconds()),
+		API:           github.APIMetricsSnapshot(),
+		Tools:         []*ToolMetrics{},
+	}
+	for name, stats := range m.tools {
+		snapshot.Tools = append(snapshot.Tools, &ToolMetrics{
+			Tool:             name,
+			Calls:            stats.calls,
+			Errors:           stats.errors,
+			AverageLatencyMs: milliseconds(stats.latency / time.Duration(stats.calls)),
+			MaxLatencyMs:     milliseconds(stats.maxLatency),
+			LastCalledAt:     stats.last.UTC().Format(time.RFC3339),
+		})
+	}
+	sort.Slice(snapshot.Tools, func(i, j int) bool {
+		if snapshot.Tools[i].Calls != snapshot.Tools[j].Calls {
+			return snapshot.Tools[i].Calls > snapshot.Tools[j].Calls
+		}
+		return snapshot.Tools[i].Tool < snapshot.Tools[j].Tool
+	})
+	return snapshot
+}
+
+// milliseconds converts a duration to milliseconds, rounded to a tenth.
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*10) / 10
+}
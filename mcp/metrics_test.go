@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsSnapshot(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/octo/hello-world/actions/jobs/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Used", "679")
+		w.Header().Set("X-RateLimit-Reset", "1767225600")
+		w.Header().Set("X-RateLimit-Resource", "core")
+		_, _ = w.Write([]byte(`{"id": 1, "name": "build", "status": "completed", "conclusion": "success"}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	server := NewMCPServer(&config.Config{
+		Token:        "token",
+		RepoOwner:    "octo",
+		RepoName:     "hello-world",
+		APIBaseURL:   ts.URL + "/",
+		UploadURL:    ts.URL + "/",
+		PerPageLimit: 50,
+		StateDir:     t.TempDir(),
+	}, logrus.New())
+
+	handler := server.metrics.middleware(server.getJobDetails)
+	call := func(jobID float64) {
+		_, err := handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: "get_job_details", Arguments: map[string]interface{}{"job_id": jobID}},
+		})
+		require.NoError(t, err)
+	}
+	call(1)
+	call(2) // 404
+	call(0) // rejected before any API call
+
+	result, err := server.getMetricsSnapshot(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	var snapshot MetricsSnapshot
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &snapshot))
+
+	require.Len(t, snapshot.Tools, 1)
+	assert.Equal(t, "get_job_details", snapshot.Tools[0].Tool)
+	assert.Equal(t, int64(3), snapshot.Tools[0].Calls)
+	assert.Equal(t, int64(2), snapshot.Tools[0].Errors)
+	assert.NotEmpty(t, snapshot.StartedAt)
+
+	// API counters are shared by every client in the process.
+	assert.GreaterOrEqual(t, snapshot.API.Calls, int64(2))
+	assert.GreaterOrEqual(t, snapshot.API.ByStatus["4xx"], int64(1))
+	var core bool
+	for _, rl := range snapshot.API.RateLimits {
+		if rl.Resource == "core" && rl.Remaining == 4321 {
+			core = true
+			assert.Equal(t, "2026-01-01T00:00:00Z", rl.ResetAt)
+		}
+	}
+	assert.True(t, core, "core rate limit not reported: %+v", snapshot.API.RateLimits)
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// notifyTimeout bounds a single webhook delivery.
+const notifyTimeout = 10 * time.Second
+
+// webhookNotifier posts short messages to an incoming webhook (Slack or compatible) so a
+// person learns about events that need them, such as a run waiting for their approval.
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+	log    *logrus.Logger
+}
+
+// newWebhookNotifier returns a notifier for url, or nil when url is empty.
+func newWebhookNotifier(url string, log *logrus.Logger) *webhookNotifier {
+	if url == "" {
+		return nil
+	}
+	return &webhookNotifier{url: url, client: &http.Client{Timeout: notifyTimeout}, log: log}
+}
+
+// notify posts {"text": text} to the webhook. A nil notifier does nothing; delivery
+// failures are logged rather than returned, since notifications are best effort.
+func (n *webhookNotifier) notify(ctx context.Context, text string) {
+	if n == nil {
+		return
+	}
+	if err := n.post(ctx, text); err != nil {
+		n.log.Warnf("Failed to send notification: %v", err)
+	}
+}
+
+func (n *webhookNotifier) post(ctx context.Context, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
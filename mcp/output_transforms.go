@@ -0,0 +1,227 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// outputPipeline applies the configured output_transforms to tool results.
+type outputPipeline struct {
+	tools           map[string]bool
+	dropFields      map[string]bool
+	maxStringLength int
+	replace         []outputReplacement
+	maxLength       int
+}
+
+type outputReplacement struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// newOutputPipeline compiles the configured transforms. It returns nil when none are
+// configured.
+func newOutputPipeline(cfg config.OutputTransforms) (*outputPipeline, error) {
+	if len(cfg.DropFields) == 0 && cfg.MaxStringLength <= 0 && len(cfg.Replace) == 0 && cfg.MaxLength <= 0 {
+		return nil, nil
+	}
+	p := &outputPipeline{
+		tools:           make(map[string]bool),
+		dropFields:      make(map[string]bool),
+		maxStringLength: cfg.MaxStringLength,
+		maxLength:       cfg.MaxLength,
+	}
+	for _, tool := range cfg.Tools {
+		p.tools[tool] = true
+	}
+	for _, field := range cfg.DropFields {
+		p.dropFields[field] = true
+	}
+	for i, r := range cfg.Replace {
+		if r.Pattern == "" {
+			return nil, fmt.Errorf("output_transforms.replace[%d]: pattern is required", i)
+		}
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("output_transforms.replace[%d]: invalid pattern %q: %w", i, r.Pattern, err)
+		}
+		p.replace = append(p.replace, outputReplacement{pattern: re, replacement: r.Replacement})
+	}
+	return p, nil
+}
+
+// middleware transforms the text content of each result of the tools the pipeline
+// applies to. A nil pipeline passes results through.
+func (p *outputPipeline) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if p == nil || result == nil || (len(p.tools) > 0 && !p.tools[request.Params.Name]) {
+			return result, err
+		}
+		for i, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				text.Text = p.apply(text.Text)
+				result.Content[i] = text
+			}
+		}
+		return result, err
+	}
+}
+
+// applyResource transforms the text of a resource. Resources are not tools, so tools does
+// not limit it. A nil pipeline returns text unchanged.
+func (p *outputPipeline) applyResource(text string) string {
+	if p == nil {
+		return text
+	}
+	return p.apply(text)
+}
+
+// apply runs the transforms over one text.
+func (p *outputPipeline) apply(text string) string {
+	if len(p.dropFields) > 0 || p.maxStringLength > 0 {
+		text = p.transformJSON(text)
+	}
+	for _, r := range p.replace {
+		text = r.pattern.ReplaceAllString(text, r.replacement)
+	}
+	if p.maxLength > 0 && utf8.RuneCountInString(text) > p.maxLength {
+		total := utf8.RuneCountInString(text)
+		text = string([]rune(text)[:p.maxLength]) + fmt.Sprintf("\n[output truncated to %d of %d characters]", p.maxLength, total)
+	}
+	return text
+}
+
+// transformJSON drops fields and shortens strings in a JSON text, keeping key order and
+// indentation. Text that is not JSON is returned unchanged.
+func (p *outputPipeline) transformJSON(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") || !json.Valid([]byte(trimmed)) {
+		return text
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var out bytes.Buffer
+	if err := p.copyJSONValue(dec, &out); err != nil {
+		return text
+	}
+	if !strings.Contains(trimmed, "\n") {
+		return out.String()
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, out.Bytes(), "", "  "); err != nil {
+		return out.String()
+	}
+	return indented.String()
+}
+
+// copyJSONValue copies the next JSON value from dec to out, dropping fields and
+// shortening strings on the way.
+func (p *outputPipeline) copyJSONValue(dec *json.Decoder, out *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			out.WriteByte('{')
+			first := true
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := keyTok.(string)
+				if p.dropFields[key] {
+					if err := skipJSONValue(dec); err != nil {
+						return err
+					}
+					continue
+				}
+				if !first {
+					out.WriteByte(',')
+				}
+				first = false
+				writeJSONString(out, key)
+				out.WriteByte(':')
+				if err := p.copyJSONValue(dec, out); err != nil {
+					return err
+				}
+			}
+			out.WriteByte('}')
+		case '[':
+			out.WriteByte('[')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				if err := p.copyJSONValue(dec, out); err != nil {
+					return err
+				}
+			}
+			out.WriteByte(']')
+		}
+		// Consume the closing delimiter.
+		_, err := dec.Token()
+		return err
+	case string:
+		if p.maxStringLength > 0 && utf8.RuneCountInString(v) > p.maxStringLength {
+			v = string([]rune(v)[:p.maxStringLength]) + fmt.Sprintf("... [%d characters omitted]", utf8.RuneCountInString(v)-p.maxStringLength)
+		}
+		writeJSONString(out, v)
+	case json.Number:
+		out.WriteString(v.String())
+	case bool:
+		fmt.Fprintf(out, "%t", v)
+	case nil:
+		out.WriteString("null")
+	}
+	return nil
+}
+
+// skipJSONValue consumes the next JSON value from dec.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// writeJSONString writes s as a JSON string without escaping HTML characters.
+func writeJSONString(out *bytes.Buffer, s string) {
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	// Encode terminates the value with a newline.
+	out.Truncate(out.Len() - 1)
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputPipeline(t *testing.T) {
+	p, err := newOutputPipeline(config.OutputTransforms{
+		DropFields:      []string{"runner_name", "labels"},
+		MaxStringLength: 10,
+		Replace: []config.Replacement{
+			{Pattern: `acme-[a-z]+\.internal`, Replacement: "[internal-host]"},
+			{Pattern: `ticket (\d+)`, Replacement: "ticket #$1"},
+		},
+	})
+	require.NoError(t, err)
+
+	pretty, err := jsonResultPretty(map[string]interface{}{
+		"id":          int64(9007199254740993),
+		"runner_name": "runner-7",
+		"jobs": []map[string]interface{}{
+			{"name": "build", "labels": []string{"self-hosted"}, "log": "fetch from acme-build.internal failed"},
+		},
+		"note": "ticket 42",
+		"ok":   true,
+		"none": nil,
+	})
+	require.NoError(t, err)
+	text := p.apply(pretty.Content[0].(mcp.TextContent).Text)
+	assert.Equal(t, `{
+  "id": 9007199254740993,
+  "jobs": [
+    {
+      "log": "fetch from... [27 characters omitted]",
+      "name": "build"
+    }
+  ],
+  "none": null,
+  "note": "ticket #42",
+  "ok": true
+}`, text)
+
+	// Replacements also apply to plain text, and to strings kept in full.
+	assert.Equal(t, "cannot reach [internal-host]", p.apply("cannot reach acme-ci.internal"))
+
+	_, err = newOutputPipeline(config.OutputTransforms{Replace: []config.Replacement{{Pattern: "("}}})
+	assert.ErrorContains(t, err, "output_transforms.replace[0]")
+
+	none, err := newOutputPipeline(config.OutputTransforms{Tools: []string{"get_run"}})
+	require.NoError(t, err)
+	assert.Nil(t, none)
+}
+
+func TestOutputPipelineMiddleware(t *testing.T) {
+	p, err := newOutputPipeline(config.OutputTransforms{Tools: []string{"get_run"}, MaxLength: 5})
+	require.NoError(t, err)
+
+	handler := p.middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return textResult("abcdefgh"), nil
+	})
+	call := func(tool string) string {
+		result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: tool}})
+		require.NoError(t, err)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+	assert.Equal(t, "abcde\n[output truncated to 5 of 8 characters]", call("get_run"))
+	assert.Equal(t, "abcdefgh", call("list_workflows"))
+
+	var nilPipeline *outputPipeline
+	result, err := nilPipeline.middleware(handler)(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "list_workflows"}})
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefgh", result.Content[0].(mcp.TextContent).Text)
+}
@@ -0,0 +1,264 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/denysvitali/gh-actions-mcp/github"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxQuickActionTargets bounds how many runs one quick action may touch, so a vague
+// command cannot cancel or rerun a large part of a repository's history.
+const maxQuickActionTargets = 20
+
+// quickAction is a parsed quick_action command.
+type quickAction struct {
+	Action   github.ManageRunAction
+	RunID    int64    // Set when the command names a single run
+	Statuses []string // For cancel all: the statuses of the runs to cancel
+	Branch   string   // From "on <branch>"
+	Workflow string   // From "in <workflow>"
+}
+
+// quickActionTarget is a run a quick action applies to.
+type quickActionTarget struct {
+	RunID      int64  `json:"run_id"`
+	Name       string `json:"name"`
+	Branch     string `json:"branch,omitempty"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// quickActionResult is the preview or outcome of a quick action.
+type quickActionResult struct {
+	Command   string                    `json:"command"`
+	Action    github.ManageRunAction    `json:"action"`
+	Confirmed bool                      `json:"confirmed"`
+	Targets   []*quickActionTarget      `json:"targets"`
+	Results   []*github.ManageRunResult `json:"results,omitempty"`
+	Message   string                    `json:"message"`
+}
+
+// quickActionUsage lists the supported commands, for errors about unparseable ones.
+const quickActionUsage = `supported commands: "rerun <run_id>", "rerun failed [<run_id>] [on <branch>] [in <workflow>]", "cancel <run_id>", "cancel all [queued|in_progress] [on <branch>] [in <workflow>]"`
+
+// parseQuickAction parses commands such as "rerun failed on main", "rerun 123",
+// "cancel all queued", or "cancel all in CI". A leading slash is ignored, and
+// "retry"/"stop" are accepted as synonyms of "rerun"/"cancel".
+func parseQuickAction(command string) (*quickAction, error) {
+	tokens := strings.Fields(strings.TrimPrefix(strings.TrimSpace(command), "/"))
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("command is empty; %s", quickActionUsage)
+	}
+
+	qa := &quickAction{}
+	var words []string
+	for i := 1; i < len(tokens); i++ {
+		switch strings.ToLower(tokens[i]) {
+		case "on":
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("%q needs a branch name", tokens[i])
+			}
+			qa.Branch = tokens[i+1]
+			i++
+		case "in":
+			// Workflow names may contain spaces; take everything up to the next "on".
+			j := i + 1
+			for j < len(tokens) && !strings.EqualFold(tokens[j], "on") {
+				j++
+			}
+			if j == i+1 {
+				return nil, fmt.Errorf("%q needs a workflow name", tokens[i])
+			}
+			qa.Workflow = strings.Join(tokens[i+1:j], " ")
+			i = j - 1
+		default:
+			words = append(words, strings.ToLower(tokens[i]))
+		}
+	}
+
+	var failed, all bool
+	for _, w := range words {
+		if id, err := strconv.ParseInt(strings.TrimPrefix(w, "#"), 10, 64); err == nil && id > 0 {
+			if qa.RunID != 0 {
+				return nil, fmt.Errorf("only one run ID may be given")
+			}
+			qa.RunID = id
+			continue
+		}
+		switch w {
+		case "failed", "failures":
+			failed = true
+		case "all", "runs":
+			all = true
+		case "queued", "pending":
+			qa.Statuses = append(qa.Statuses, "queued")
+		case "running", "in_progress":
+			qa.Statuses = append(qa.Statuses, "in_progress")
+		default:
+			return nil, fmt.Errorf("unrecognized word %q; %s", w, quickActionUsage)
+		}
+	}
+	if qa.RunID != 0 && (qa.Branch != "" || qa.Workflow != "") {
+		return nil, fmt.Errorf(`"on" and "in" cannot be combined with a run ID`)
+	}
+
+	switch strings.ToLower(tokens[0]) {
+	case "rerun", "retry":
+		if all || len(qa.Statuses) > 0 {
+			return nil, fmt.Errorf("rerun takes a run ID or \"failed\"; %s", quickActionUsage)
+		}
+		if failed {
+			qa.Action = github.ManageRunActionRerunFailed
+		} else if qa.RunID != 0 {
+			qa.Action = github.ManageRunActionRerun
+		} else {
+			return nil, fmt.Errorf("rerun needs a run ID or \"failed\"; %s", quickActionUsage)
+		}
+	case "cancel", "stop":
+		if failed {
+			return nil, fmt.Errorf("failed runs have already finished; %s", quickActionUsage)
+		}
+		if qa.RunID == 0 && !all && len(qa.Statuses) == 0 {
+			return nil, fmt.Errorf("cancel needs a run ID or \"all\"; %s", quickActionUsage)
+		}
+		if qa.RunID == 0 && len(qa.Statuses) == 0 {
+			qa.Statuses = []string{"queued", "in_progress"}
+		}
+		qa.Action = github.ManageRunActionCancel
+	default:
+		return nil, fmt.Errorf("unknown command %q; %s", tokens[0], quickActionUsage)
+	}
+	return qa, nil
+}
+
+// resolveQuickActionTargets returns the runs a quick action applies to.
+func resolveQuickActionTargets(ctx context.Context, client *github.Client, qa *quickAction) ([]*github.WorkflowRun, error) {
+	if qa.RunID != 0 {
+		run, err := client.GetWorkflowRun(ctx, qa.RunID)
+		if err != nil {
+			return nil, err
+		}
+		return []*github.WorkflowRun{run}, nil
+	}
+
+	opts := github.ListRunsOptions{Branch: qa.Branch, Per_page: 100}
+	if qa.Workflow != "" {
+		id, _, err := client.ResolveWorkflowID(ctx, qa.Workflow)
+		if err != nil {
+			return nil, err
+		}
+		opts.WorkflowID = &id
+	}
+
+	var targets []*github.WorkflowRun
+	if qa.Action == github.ManageRunActionRerunFailed {
+		opts.Status = "completed"
+		runs, err := client.ListRepositoryWorkflowRunsWithOptions(ctx, &opts)
+		if err != nil {
+			return nil, err
+		}
+		// Only rerun workflows whose latest run on a branch failed; older failures
+		// have already been superseded.
+		latest := make(map[string]bool)
+		for _, run := range runs {
+			key := fmt.Sprintf("%d\x00%s", run.WorkflowID, run.Branch)
+			if latest[key] {
+				continue
+			}
+			latest[key] = true
+			switch run.Conclusion {
+			case "failure", "timed_out", "startup_failure", "cancelled":
+				targets = append(targets, run)
+			}
+		}
+	} else {
+		for _, status := range qa.Statuses {
+			opts.Status = status
+			runs, err := client.ListRepositoryWorkflowRunsWithOptions(ctx, &opts)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, runs...)
+		}
+	}
+
+	if len(targets) > maxQuickActionTargets {
+		return nil, fmt.Errorf("the command matches %d runs, more than the limit of %d; narrow it with \"on <branch>\" or \"in <workflow>\"", len(targets), maxQuickActionTargets)
+	}
+	return targets, nil
+}
+
+// quickActionVerb describes an action in preview and result messages.
+func quickActionVerb(action github.ManageRunAction) string {
+	switch action {
+	case github.ManageRunActionRerunFailed:
+		return "rerun the failed jobs of"
+	case github.ManageRunActionRerun:
+		return "rerun"
+	default:
+		return "cancel"
+	}
+}
+
+func (s *MCPServer) quickAction(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	command, _ := args["command"].(string)
+	qa, err := parseQuickAction(command)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	confirm, _ := args["confirm"].(bool)
+
+	runs, err := resolveQuickActionTargets(ctx, client, qa)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to resolve %q", command), owner, repo), nil
+	}
+
+	result := &quickActionResult{Command: command, Action: qa.Action, Confirmed: confirm, Targets: []*quickActionTarget{}}
+	for _, run := range runs {
+		result.Targets = append(result.Targets, &quickActionTarget{
+			RunID:      run.ID,
+			Name:       run.Name,
+			Branch:     run.Branch,
+			Status:     run.Status,
+			Conclusion: run.Conclusion,
+			URL:        run.URL,
+		})
+	}
+
+	verb := quickActionVerb(qa.Action)
+	switch {
+	case len(runs) == 0:
+		result.Message = "No runs matched; nothing to do."
+		return jsonResultPretty(result)
+	case !confirm:
+		result.Message = fmt.Sprintf("Would %s %d run(s). Call quick_action again with confirm: true to proceed; the runs are looked up again then.", verb, len(runs))
+		return jsonResultPretty(result)
+	}
+
+	s.log.WithContext(ctx).Infof("Quick action %q on %s/%s: %s %d run(s)", command, owner, repo, qa.Action, len(runs))
+
+	succeeded := 0
+	for _, run := range runs {
+		r, err := client.ManageRun(ctx, run.ID, qa.Action)
+		if err != nil {
+			r = &github.ManageRunResult{RunID: run.ID, Action: qa.Action, Status: "failed", Message: err.Error()}
+		}
+		if r.Status == "success" {
+			succeeded++
+		}
+		result.Results = append(result.Results, r)
+	}
+	result.Message = fmt.Sprintf("Asked GitHub to %s %d of %d run(s).", verb, succeeded, len(runs))
+	return jsonResultPretty(result)
+}
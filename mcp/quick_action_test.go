@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/denysvitali/gh-actions-mcp/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuickAction(t *testing.T) {
+	tests := []struct {
+		command string
+		want    quickAction
+	}{
+		{"rerun failed on main", quickAction{Action: github.ManageRunActionRerunFailed, Branch: "main"}},
+		{"/retry 123", quickAction{Action: github.ManageRunActionRerun, RunID: 123}},
+		{"rerun failed #456", quickAction{Action: github.ManageRunActionRerunFailed, RunID: 456}},
+		{"cancel all queued", quickAction{Action: github.ManageRunActionCancel, Statuses: []string{"queued"}}},
+		{"Cancel all in Release Build on feature/x", quickAction{Action: github.ManageRunActionCancel, Statuses: []string{"queued", "in_progress"}, Workflow: "Release Build", Branch: "feature/x"}},
+		{"stop 789", quickAction{Action: github.ManageRunActionCancel, RunID: 789}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got, err := parseQuickAction(tt.command)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+
+	for _, command := range []string{"", "rerun", "cancel", "cancel failed", "rerun all queued", "deploy main", "rerun 1 2", "rerun 12 on main", "rerun failed on"} {
+		_, err := parseQuickAction(command)
+		assert.Error(t, err, command)
+	}
+}
+
+func TestQuickAction_PreviewsThenCancels(t *testing.T) {
+	owner := "octo"
+	repo := "hello-world"
+
+	var mu sync.Mutex
+	var cancelled []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("status") == "queued" {
+			_, _ = w.Write([]byte(`{"total_count": 2, "workflow_runs": [
+				{"id": 11, "name": "CI", "status": "queued", "head_branch": "main"},
+				{"id": 12, "name": "Lint", "status": "queued", "head_branch": "main"}
+			]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"total_count": 0, "workflow_runs": []}`))
+	})
+	for _, id := range []string{"11", "12"} {
+		id := id
+		mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/runs/"+id+"/cancel", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			mu.Lock()
+			cancelled = append(cancelled, id)
+			mu.Unlock()
+			w.WriteHeader(http.StatusAccepted)
+		})
+	}
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	server := NewMCPServer(&config.Config{
+		Token:        "token",
+		RepoOwner:    owner,
+		RepoName:     repo,
+		APIBaseURL:   ts.URL + "/",
+		UploadURL:    ts.URL + "/",
+		PerPageLimit: 50,
+		StateDir:     t.TempDir(),
+	}, logrus.New())
+
+	call := func(args map[string]interface{}) *quickActionResult {
+		result, err := server.quickAction(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: "quick_action", Arguments: args},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+		var out quickActionResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+		return &out
+	}
+
+	preview := call(map[string]interface{}{"command": "cancel all queued"})
+	assert.False(t, preview.Confirmed)
+	require.Len(t, preview.Targets, 2)
+	assert.Equal(t, int64(11), preview.Targets[0].RunID)
+	assert.Contains(t, preview.Message, "confirm: true")
+	assert.Empty(t, cancelled)
+
+	done := call(map[string]interface{}{"command": "cancel all queued", "confirm": true})
+	assert.True(t, done.Confirmed)
+	require.Len(t, done.Results, 2)
+	assert.Equal(t, "success", done.Results[1].Status)
+	assert.ElementsMatch(t, []string{"11", "12"}, cancelled)
+	assert.Equal(t, "Asked GitHub to cancel 2 of 2 run(s).", done.Message)
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+)
+
+// requestIDKey is the context key of a tool call's request ID.
+type requestIDKey struct{}
+
+// requestIDField is the name of the request ID in log entries and result metadata.
+const requestIDField = "request_id"
+
+// newRequestID returns a random 16-character hex ID.
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// requestIDFromContext returns the request ID of the tool call ctx belongs to, or "".
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDMiddleware gives each tool call a request ID, so a result someone reports as
+// wrong can be matched with the server's logs. The ID is stored in the call's context,
+// which requestIDHook adds to log entries made with WithContext; it is logged with the
+// call's outcome and returned in the result's _meta.request_id.
+func requestIDMiddleware(log *logrus.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := newRequestID()
+			ctx = context.WithValue(ctx, requestIDKey{}, id)
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			entry := log.WithContext(ctx).WithFields(logrus.Fields{
+				"tool":        request.Params.Name,
+				"duration_ms": time.Since(start).Milliseconds(),
+			})
+			switch {
+			case err != nil:
+				entry.WithError(err).Warn("Tool call failed")
+			case result != nil && result.IsError:
+				entry.Info("Tool call returned an error result")
+			default:
+				entry.Info("Tool call completed")
+			}
+
+			if result != nil {
+				setMetaField(result, requestIDField, id)
+			}
+			return result, err
+		}
+	}
+}
+
+// requestIDHook adds the request ID of the entry's context to log entries.
+type requestIDHook struct{}
+
+func (requestIDHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (requestIDHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	if id := requestIDFromContext(entry.Context); id != "" {
+		entry.Data[requestIDField] = id
+	}
+	return nil
+}
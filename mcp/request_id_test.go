@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&buf)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(requestIDHook{})
+
+	var seen string
+	handler := requestIDMiddleware(log)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = requestIDFromContext(ctx)
+		log.WithContext(ctx).Infof("Getting run %d", 42)
+		log.Info("Unrelated entry")
+		return errorResult("run not found"), nil
+	})
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_run"}})
+	require.NoError(t, err)
+	require.Len(t, seen, 16)
+	require.NotNil(t, result.Meta)
+	assert.Equal(t, seen, result.Meta.AdditionalFields["request_id"])
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 3)
+	assert.Equal(t, "Getting run 42", entries[0]["msg"])
+	assert.Equal(t, seen, entries[0]["request_id"])
+	assert.NotContains(t, entries[1], "request_id")
+	assert.Equal(t, "Tool call returned an error result", entries[2]["msg"])
+	assert.Equal(t, seen, entries[2]["request_id"])
+	assert.Equal(t, "get_run", entries[2]["tool"])
+
+	// Each call gets its own ID.
+	_, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_run"}})
+	require.NoError(t, err)
+	assert.NotEqual(t, result.Meta.AdditionalFields["request_id"], seen)
+}
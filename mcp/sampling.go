@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// samplingChunkChars is the largest piece of a log sent in one sampling request.
+	samplingChunkChars = 24000
+	// maxSamplingChunks bounds the sampling requests per log; longer logs are summarized
+	// from their end, where failures usually are.
+	maxSamplingChunks = 12
+	// samplingMaxTokens is the length asked of each summary.
+	samplingMaxTokens = 800
+)
+
+// errSamplingUnsupported is returned when the client did not declare the sampling capability.
+var errSamplingUnsupported = errors.New("the client does not support MCP sampling")
+
+const logSummaryPrompt = "You summarize GitHub Actions logs for a developer investigating a CI run. " +
+	"Report what ran, what failed, and the exact error messages, file paths, and test names that explain a failure. " +
+	"Leave out routine output such as dependency downloads and passing tests. Answer in plain text, briefly."
+
+// supportsSampling reports whether the client of the request's session declared the
+// sampling capability.
+func supportsSampling(ctx context.Context) bool {
+	session := server.ClientSessionFromContext(ctx)
+	if _, ok := session.(server.SessionWithSampling); !ok {
+		return false
+	}
+	info, ok := session.(server.SessionWithClientInfo)
+	return ok && info.GetClientCapabilities().Sampling != nil
+}
+
+// summarizeLogs distills logs with the client's LLM through sampling requests, so only the
+// summary enters the conversation: each chunk of the log is summarized, then the chunk
+// summaries are merged. subject names the log in the prompts, e.g. "run 123".
+func (s *MCPServer) summarizeLogs(ctx context.Context, subject, logs string) (string, error) {
+	if !supportsSampling(ctx) {
+		return "", errSamplingUnsupported
+	}
+	chunks := splitLogChunks(logs, samplingChunkChars)
+	skipped := 0
+	if len(chunks) > maxSamplingChunks {
+		skipped = len(chunks) - maxSamplingChunks
+		chunks = chunks[skipped:]
+	}
+
+	summaries := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		prompt := fmt.Sprintf("Summarize the logs of %s.\n\n%s", subject, chunk)
+		if len(chunks) > 1 {
+			prompt = fmt.Sprintf("Summarize part %d of %d of the logs of %s.\n\n%s", i+1, len(chunks), subject, chunk)
+		}
+		summary, err := s.sample(ctx, prompt)
+		if err != nil {
+			return "", err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	summary := ""
+	switch len(summaries) {
+	case 0:
+		return "(no logs)", nil
+	case 1:
+		summary = summaries[0]
+	default:
+		prompt := fmt.Sprintf("These are summaries of consecutive parts of the logs of %s. Merge them into one summary.\n\n%s",
+			subject, strings.Join(summaries, "\n\n---\n\n"))
+		merged, err := s.sample(ctx, prompt)
+		if err != nil {
+			return "", err
+		}
+		summary = merged
+	}
+	if skipped > 0 {
+		summary += fmt.Sprintf("\n\n--- [summary covers the last %d of %d parts of the logs; use head/offset or search to read earlier output] ---", len(chunks), len(chunks)+skipped)
+	}
+	return summary, nil
+}
+
+// sample sends one sampling request and returns the text of the reply.
+func (s *MCPServer) sample(ctx context.Context, prompt string) (string, error) {
+	result, err := s.srv.RequestSampling(ctx, mcp.CreateMessageRequest{
+		CreateMessageParams: mcp.CreateMessageParams{
+			Messages: []mcp.SamplingMessage{{
+				Role:    mcp.RoleUser,
+				Content: mcp.NewTextContent(prompt),
+			}},
+			SystemPrompt: logSummaryPrompt,
+			MaxTokens:    samplingMaxTokens,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("sampling request failed: %w", err)
+	}
+	return strings.TrimSpace(mcp.GetTextFromContent(result.Content)), nil
+}
+
+// splitLogChunks splits logs into pieces of at most size bytes at line boundaries. A line
+// longer than size is cut.
+func splitLogChunks(logs string, size int) []string {
+	var chunks []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(logs, "\n") {
+		for len(line) > size {
+			if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+			chunks = append(chunks, line[:size])
+			line = line[size:]
+		}
+		if current.Len() > 0 && current.Len()+len(line) > size {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// summarizeLogResult returns the sampling summary of logs as a tool result, or an error
+// result explaining why the logs could not be summarized.
+func (s *MCPServer) summarizeLogResult(ctx context.Context, subject, logs string) *mcp.CallToolResult {
+	s.log.WithContext(ctx).Infof("Summarizing %d bytes of logs of %s through sampling", len(logs), subject)
+	summary, err := s.summarizeLogs(ctx, subject, logs)
+	if errors.Is(err, errSamplingUnsupported) {
+		return errorResult("summarize=true needs a client that supports MCP sampling; call again without summarize and narrow the logs with search, section, or tail")
+	}
+	if err != nil {
+		return errorResult(fmt.Sprintf("failed to summarize the logs of %s: %v", subject, err))
+	}
+	return textResult(summary)
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// samplingFunc answers sampling requests in tests.
+type samplingFunc func(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+
+func (f samplingFunc) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return f(ctx, request)
+}
+
+func TestSplitLogChunks(t *testing.T) {
+	assert.Equal(t, []string{"one\ntwo\n", "three\n"}, splitLogChunks("one\ntwo\nthree\n", 8))
+	assert.Equal(t, []string{"a\n", "bbbb", "bb\nc"}, splitLogChunks("a\nbbbbbb\nc", 4))
+	assert.Empty(t, splitLogChunks("", 8))
+}
+
+func TestGetRunLogs_Summarize(t *testing.T) {
+	owner := "octo"
+	repo := "hello-world"
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/repos/"+owner+"/"+repo+"/actions/jobs/7/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", ts.URL+"/blob/job.log")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/blob/job.log", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("downloading dependency\n", 2000) + "--- FAIL: TestParse\n"))
+	})
+
+	srv := NewMCPServer(&config.Config{
+		Token:        "token",
+		RepoOwner:    owner,
+		RepoName:     repo,
+		APIBaseURL:   ts.URL + "/",
+		UploadURL:    ts.URL + "/",
+		PerPageLimit: 50,
+		StateDir:     t.TempDir(),
+	}, logrus.New())
+
+	var prompts []string
+	session := server.NewInProcessSession("test", samplingFunc(func(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		prompt := request.Messages[0].Content.(mcp.TextContent).Text
+		prompts = append(prompts, prompt)
+		reply := "routine output"
+		switch {
+		case strings.HasPrefix(prompt, "These are summaries"):
+			reply = "TestParse failed"
+		case strings.Contains(prompt, "FAIL"):
+			reply = "TestParse failed after setup"
+		}
+		return &mcp.CreateMessageResult{SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent(reply)}}, nil
+	}))
+
+	call := func(ctx context.Context) *mcp.CallToolResult {
+		result, err := srv.getRun(ctx, mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: "get_run", Arguments: map[string]interface{}{
+				"run_id": float64(3), "element": "logs", "job_id": float64(7), "summarize": true,
+			}},
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// Without the sampling capability the call fails instead of dumping the logs.
+	result := call(srv.srv.WithContext(context.Background(), session))
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "supports MCP sampling")
+	assert.Empty(t, prompts)
+
+	session.SetClientCapabilities(mcp.ClientCapabilities{Sampling: &struct{}{}})
+	result = call(srv.srv.WithContext(context.Background(), session))
+	require.False(t, result.IsError)
+	assert.Equal(t, "TestParse failed", result.Content[0].(mcp.TextContent).Text)
+
+	// 2000 lines of 23 bytes make two chunks, then the chunk summaries are merged.
+	require.Len(t, prompts, 3)
+	assert.True(t, strings.HasPrefix(prompts[0], "Summarize part 1 of 2 of the logs of job 7 of workflow run 3."))
+	assert.Contains(t, prompts[2], "routine output\n\n---\n\nTestParse failed after setup")
+}
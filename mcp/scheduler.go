@@ -0,0 +1,258 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// cronSpec is a parsed five-field cron expression. Each field is a bit set of the values
+// it matches.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record a "*" day field: as in cron, when both day fields are
+	// restricted a time matches if either does.
+	domAny, dowAny bool
+}
+
+// cronMacros are the shorthand schedules accepted in place of five fields.
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// parseCron parses "minute hour day-of-month month day-of-week", where each field is "*",
+// a number, a range "a-b", a step "*/n" or "a-b/n", or a comma-separated list of these.
+// Day-of-week runs from 0 (Sunday) to 6; 7 is also Sunday.
+func parseCron(expr string) (*cronSpec, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields (minute hour day-of-month month day-of-week) or a macro such as @daily", expr)
+	}
+
+	spec := &cronSpec{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	bounds := []struct {
+		name     string
+		min, max int
+		bits     *uint64
+	}{
+		{"minute", 0, 59, &spec.minute},
+		{"hour", 0, 23, &spec.hour},
+		{"day-of-month", 1, 31, &spec.dom},
+		{"month", 1, 12, &spec.month},
+		{"day-of-week", 0, 7, &spec.dow},
+	}
+	for i, b := range bounds {
+		bits, err := parseCronField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %s: %w", expr, b.name, err)
+		}
+		*b.bits = bits
+	}
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1
+	}
+	return spec, nil
+}
+
+// parseCronField returns the bit set of values a cron field matches.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			loStr, hiStr, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(loStr); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// next returns the first whole minute after t that the spec matches, or the zero time
+// if none does within five years (e.g. "0 0 31 2 *").
+func (c *cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSpec) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// scheduledTask is a validated schedule from the config.
+type scheduledTask struct {
+	name string
+	spec *cronSpec
+	tool string
+	args map[string]interface{}
+}
+
+// loadSchedules validates the configured schedules against the cron syntax and the
+// registered tools.
+func (s *MCPServer) loadSchedules(schedules []config.Schedule) ([]*scheduledTask, error) {
+	var tasks []*scheduledTask
+	for i, sc := range schedules {
+		name := sc.Name
+		if name == "" {
+			name = sc.Tool
+		}
+		if sc.Tool == "" {
+			return nil, fmt.Errorf("schedule %d: tool is required", i+1)
+		}
+		if s.srv.GetTool(sc.Tool) == nil {
+			return nil, fmt.Errorf("schedule %q: unknown tool %q", name, sc.Tool)
+		}
+		spec, err := parseCron(sc.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", name, err)
+		}
+		args, err := normalizeScheduleArgs(sc.Args)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", name, err)
+		}
+		tasks = append(tasks, &scheduledTask{name: name, spec: spec, tool: sc.Tool, args: args})
+	}
+	return tasks, nil
+}
+
+// normalizeScheduleArgs round-trips args through JSON, so they have the types tool handlers
+// get from MCP clients: the config file decodes numbers as int, but handlers read float64.
+func normalizeScheduleArgs(args map[string]interface{}) (map[string]interface{}, error) {
+	if len(args) == 0 {
+		return args, nil
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid args: %w", err)
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, fmt.Errorf("invalid args: %w", err)
+	}
+	return normalized, nil
+}
+
+// startScheduler validates the configured schedules and runs each one in the background
+// until ctx is cancelled. A task that is still running when it comes due again skips
+// that occurrence.
+func (s *MCPServer) startScheduler(ctx context.Context) error {
+	tasks, err := s.loadSchedules(s.config.Schedules)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		s.log.Infof("Scheduled %s (tool %s), next run at %s", task.name, task.tool, task.spec.next(time.Now()).Format(time.RFC3339))
+		go s.runSchedule(ctx, task)
+	}
+	return nil
+}
+
+func (s *MCPServer) runSchedule(ctx context.Context, task *scheduledTask) {
+	for {
+		next := task.spec.next(time.Now())
+		if next.IsZero() {
+			s.log.Warnf("Schedule %s never runs again; stopping it", task.name)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.runScheduledTask(ctx, task)
+	}
+}
+
+// runScheduledTask calls the task's tool once and logs the outcome.
+func (s *MCPServer) runScheduledTask(ctx context.Context, task *scheduledTask) {
+	args := make(map[string]interface{}, len(task.args))
+	for k, v := range task.args {
+		args[k] = v
+	}
+
+	start := time.Now()
+	result, err := s.InvokeTool(ctx, task.tool, args)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	switch {
+	case err != nil:
+		s.log.Warnf("Schedule %s failed after %s: %v", task.name, elapsed, err)
+	case result.IsError:
+		s.log.Warnf("Schedule %s failed after %s: %s", task.name, elapsed, resultText(result))
+	default:
+		s.log.Infof("Schedule %s completed in %s", task.name, elapsed)
+		s.log.Debugf("Schedule %s result: %s", task.name, resultText(result))
+	}
+}
+
+// resultText returns the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronSpecNext(t *testing.T) {
+	// Wednesday.
+	from := time.Date(2024, 1, 17, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 1, 17, 10, 45, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 17, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, 1, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"30 6 1 * *", time.Date(2024, 2, 1, 6, 30, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matching is enough.
+		{"0 0 20 * 5", time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			spec, err := parseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, spec.next(from))
+		})
+	}
+
+	spec, err := parseCron("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, spec.next(from).IsZero())
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@yearly"} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestScheduler_RunsConfiguredTool(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 0, "workflows": []}`))
+	}))
+	defer ts.Close()
+
+	cfg := &config.Config{
+		Token:        "token",
+		RepoOwner:    "octo",
+		RepoName:     "hello-world",
+		APIBaseURL:   ts.URL + "/",
+		UploadURL:    ts.URL + "/",
+		PerPageLimit: 50,
+		StateDir:     t.TempDir(),
+		Schedules:    []config.Schedule{{Cron: "@daily", Tool: "list_workflows"}},
+	}
+	server := NewMCPServer(cfg, logrus.New())
+
+	tasks, err := server.loadSchedules(cfg.Schedules)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "list_workflows", tasks[0].name)
+
+	server.runScheduledTask(context.Background(), tasks[0])
+	assert.Equal(t, int32(1), calls.Load())
+
+	_, err = server.loadSchedules([]config.Schedule{{Name: "cleanup", Cron: "@daily", Tool: "prune_everything"}})
+	assert.ErrorContains(t, err, `schedule "cleanup": unknown tool "prune_everything"`)
+	_, err = server.loadSchedules([]config.Schedule{{Cron: "every day", Tool: "list_workflows"}})
+	assert.ErrorContains(t, err, "invalid cron expression")
+	_, err = server.loadSchedules([]config.Schedule{{Cron: "@nightly", Tool: "list_workflows"}})
+	assert.ErrorContains(t, err, "invalid cron expression")
+}
+
+func TestScheduler_NumericArgs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 3, "workflows": [
+			{"id": 1, "name": "CI", "path": ".github/workflows/ci.yml", "state": "active"},
+			{"id": 2, "name": "Lint", "path": ".github/workflows/lint.yml", "state": "active"},
+			{"id": 3, "name": "Release", "path": ".github/workflows/release.yml", "state": "active"}]}`))
+	}))
+	defer ts.Close()
+
+	server := NewMCPServer(&config.Config{
+		Token:        "token",
+		RepoOwner:    "octo",
+		RepoName:     "hello-world",
+		APIBaseURL:   ts.URL + "/",
+		UploadURL:    ts.URL + "/",
+		PerPageLimit: 50,
+		StateDir:     t.TempDir(),
+	}, logrus.New())
+
+	// The config file decodes numbers as int.
+	tasks, err := server.loadSchedules([]config.Schedule{{Cron: "@daily", Tool: "list_workflows", Args: map[string]interface{}{"limit": 1, "format": "compact"}}})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, float64(1), tasks[0].args["limit"])
+
+	result, err := server.InvokeTool(context.Background(), tasks[0].tool, tasks[0].args)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := resultText(result)
+	assert.Contains(t, text, "CI")
+	assert.NotContains(t, text, "Lint")
+}
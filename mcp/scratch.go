@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// scratchURITemplate addresses a tool result too large to return inline.
+const scratchURITemplate = "gh-actions://scratch/{id}"
+
+var scratchURIPattern = regexp.MustCompile(`^gh-actions://scratch/([0-9a-f]{16})$`)
+
+const (
+	// defaultScratchTTL is how long a scratch resource stays readable when scratch_ttl is unset.
+	defaultScratchTTL = time.Hour
+	// scratchPreviewLines is how many lines of the head and of the tail a summary shows.
+	scratchPreviewLines = 20
+	// scratchPreviewLineLength cuts longer preview lines.
+	scratchPreviewLineLength = 200
+)
+
+// scratchStore keeps tool results larger than the threshold in files of a private temporary
+// directory, readable as scratch resources until they expire.
+type scratchStore struct {
+	threshold int
+	ttl       time.Duration
+
+	mu      sync.Mutex
+	dir     string // Created on the first write
+	entries map[string]*scratchEntry
+}
+
+// scratchEntry is a stored tool result.
+type scratchEntry struct {
+	path      string
+	mimeType  string
+	expiresAt time.Time
+}
+
+// scratchSummary replaces a tool result that was moved to a scratch resource.
+type scratchSummary struct {
+	Resource  string   `json:"scratch_resource"`
+	Tool      string   `json:"tool"`
+	SizeBytes int      `json:"size_bytes"`
+	Lines     int      `json:"lines"`
+	MIMEType  string   `json:"mime_type"`
+	ExpiresAt string   `json:"expires_at"`
+	Head      []string `json:"head"`
+	Tail      []string `json:"tail,omitempty"`
+	Note      string   `json:"note"`
+}
+
+// newScratchStore returns a store for results larger than threshold bytes, kept for ttl. It
+// returns nil when threshold is not positive.
+func newScratchStore(threshold int, ttl time.Duration) *scratchStore {
+	if threshold <= 0 {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = defaultScratchTTL
+	}
+	return &scratchStore{threshold: threshold, ttl: ttl, entries: make(map[string]*scratchEntry)}
+}
+
+// middleware moves each text content of a successful result that is larger than the
+// threshold to a scratch resource, replacing it with a summary and a link to the resource.
+// A nil store passes results through.
+func (st *scratchStore) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if st == nil || result == nil || result.IsError {
+			return result, err
+		}
+		var links []mcp.Content
+		for i, content := range result.Content {
+			text, ok := content.(mcp.TextContent)
+			if !ok || len(text.Text) <= st.threshold {
+				continue
+			}
+			summary, storeErr := st.put(request.Params.Name, text.Text)
+			if storeErr != nil {
+				// Returning the full result beats failing the call.
+				continue
+			}
+			data, _ := json.MarshalIndent(summary, "", "  ")
+			text.Text = string(data)
+			result.Content[i] = text
+			links = append(links, mcp.NewResourceLink(summary.Resource, request.Params.Name+" output",
+				fmt.Sprintf("Full %s output (%d bytes), readable until %s", request.Params.Name, summary.SizeBytes, summary.ExpiresAt), summary.MIMEType))
+		}
+		result.Content = append(result.Content, links...)
+		return result, err
+	}
+}
+
+// put writes text to a new scratch file and returns its summary.
+func (st *scratchStore) put(tool, text string) (*scratchSummary, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.dir == "" {
+		dir, err := os.MkdirTemp("", "gh-actions-mcp-scratch-")
+		if err != nil {
+			return nil, err
+		}
+		st.dir = dir
+	}
+	f, err := os.CreateTemp(st.dir, "result-*")
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.WriteString(text)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+
+	id := newRequestID()
+	entry := &scratchEntry{path: f.Name(), mimeType: "text/plain", expiresAt: time.Now().Add(st.ttl)}
+	if json.Valid([]byte(text)) {
+		entry.mimeType = "application/json"
+	}
+	st.entries[id] = entry
+	time.AfterFunc(st.ttl, func() { st.remove(id) })
+
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	summary := &scratchSummary{
+		Resource:  strings.Replace(scratchURITemplate, "{id}", id, 1),
+		Tool:      tool,
+		SizeBytes: len(text),
+		Lines:     len(lines),
+		MIMEType:  entry.mimeType,
+		ExpiresAt: entry.expiresAt.UTC().Format(time.RFC3339),
+		Note:      fmt.Sprintf("The output is larger than the %d-byte budget. Read the scratch resource for the full content.", st.threshold),
+	}
+	if len(lines) <= 2*scratchPreviewLines {
+		summary.Head = previewLines(lines)
+	} else {
+		summary.Head = previewLines(lines[:scratchPreviewLines])
+		summary.Tail = previewLines(lines[len(lines)-scratchPreviewLines:])
+	}
+	return summary, nil
+}
+
+// previewLines returns lines cut to scratchPreviewLineLength.
+func previewLines(lines []string) []string {
+	preview := make([]string, len(lines))
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) > scratchPreviewLineLength {
+			line = string([]rune(line)[:scratchPreviewLineLength]) + "..."
+		}
+		preview[i] = line
+	}
+	return preview
+}
+
+// remove deletes a scratch resource.
+func (st *scratchStore) remove(id string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if entry, ok := st.entries[id]; ok {
+		_ = os.Remove(entry.path)
+		delete(st.entries, id)
+	}
+}
+
+// close deletes every scratch resource and the scratch directory.
+func (st *scratchStore) close() {
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.dir != "" {
+		_ = os.RemoveAll(st.dir)
+		st.dir = ""
+	}
+	st.entries = make(map[string]*scratchEntry)
+}
+
+// read returns the content and MIME type of the scratch resource at uri.
+func (st *scratchStore) read(uri string) (string, string, error) {
+	m := scratchURIPattern.FindStringSubmatch(uri)
+	if m == nil {
+		return "", "", fmt.Errorf("unknown resource %q: expected %s", uri, scratchURITemplate)
+	}
+	st.mu.Lock()
+	entry, ok := st.entries[m[1]]
+	st.mu.Unlock()
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", "", fmt.Errorf("scratch resource %s has expired or does not exist; call the tool again", uri)
+	}
+	data, err := os.ReadFile(entry.path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read scratch resource %s: %w", uri, err)
+	}
+	return string(data), entry.mimeType, nil
+}
+
+func (s *MCPServer) readScratch(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	text, mimeType, err := s.scratch.read(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: mimeType,
+			Text:     text,
+		},
+	}, nil
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denysvitali/gh-actions-mcp/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScratchMiddleware(t *testing.T) {
+	srv := NewMCPServer(&config.Config{
+		Token:            "token",
+		RepoOwner:        "octo",
+		RepoName:         "hello-world",
+		PerPageLimit:     50,
+		StateDir:         t.TempDir(),
+		ScratchThreshold: 1000,
+		ScratchTTL:       3600,
+	}, logrus.New())
+	defer srv.scratch.close()
+
+	var log strings.Builder
+	for i := 1; i <= 200; i++ {
+		fmt.Fprintf(&log, "line %d\n", i)
+	}
+	handler := srv.scratch.middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(log.String()), nil
+	})
+	result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_run"}})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 2)
+
+	var summary scratchSummary
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &summary))
+	assert.Equal(t, "get_run", summary.Tool)
+	assert.Equal(t, log.Len(), summary.SizeBytes)
+	assert.Equal(t, 200, summary.Lines)
+	assert.Equal(t, "text/plain", summary.MIMEType)
+	require.Len(t, summary.Head, 20)
+	assert.Equal(t, "line 1", summary.Head[0])
+	require.Len(t, summary.Tail, 20)
+	assert.Equal(t, "line 200", summary.Tail[19])
+
+	link, ok := result.Content[1].(mcp.ResourceLink)
+	require.True(t, ok)
+	assert.Equal(t, summary.Resource, link.URI)
+
+	contents, err := srv.readScratch(context.Background(), mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: summary.Resource}})
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	assert.Equal(t, log.String(), contents[0].(mcp.TextResourceContents).Text)
+
+	// Small results and errors are returned as they are.
+	small := srv.scratch.middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	result, err = small(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestScratchStore_Expiry(t *testing.T) {
+	st := newScratchStore(10, 20*time.Millisecond)
+	defer st.close()
+
+	summary, err := st.put("list_runs", `{"runs": [1, 2, 3, 4, 5]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", summary.MIMEType)
+	_, _, err = st.read(summary.Resource)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, _, err := st.read(summary.Resource)
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	_, _, err = st.read(summary.Resource)
+	assert.Contains(t, err.Error(), "has expired")
+
+	_, _, err = st.read("gh-actions://scratch/not-an-id")
+	assert.Error(t, err)
+
+	assert.Nil(t, newScratchStore(0, time.Hour))
+}
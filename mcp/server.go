@@ -1,18 +1,22 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/denysvitali/gh-actions-mcp/config"
 	"github.com/denysvitali/gh-actions-mcp/github"
-	ghapi "github.com/google/go-github/v69/github"
+	"github.com/denysvitali/gh-actions-mcp/state"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -20,12 +24,31 @@ import (
 )
 
 type MCPServer struct {
-	srv    *server.MCPServer
-	client *github.Client
-	config *config.Config
-	log    *logrus.Logger
+	srv        *server.MCPServer
+	client     *github.Client
+	config     *config.Config
+	log        *logrus.Logger
+	dispatches *dispatchGuard
+	lastInputs *dispatchInputs
+	tokens     *github.TokenSource
+	notifier   *webhookNotifier
+	metrics    *toolMetrics
+	outputs    *outputPipeline
+	scratch    *scratchStore
+
+	state          *state.Store
+	cursorMu       sync.Mutex
+	failureCursors map[string]*github.FailureCursor
+
+	logSubs jobLogSubscriptions
 }
 
+// State document holding get_new_failures cursors, keyed by repository, workflow, and branch.
+const (
+	failureCursorsState  = "failure_cursors"
+	failureCursorsSchema = 1
+)
+
 // Default limits for output control
 const (
 	DefaultListLimit = 5  // Default max items for lists (reduced from 10 for token efficiency)
@@ -97,6 +120,14 @@ func formatWorkflowStatusSummary(ref string, status *github.CombinedCheckStatus,
 }
 
 func (s *MCPServer) repoFromArgs(args map[string]interface{}) (string, string, error) {
+	_, owner, repo, err := s.repoRefFromArgs(args)
+	return owner, repo, err
+}
+
+// repoRefFromArgs returns the host, owner, and repository a tool call targets. repo may
+// be "owner/repo" or "host/owner/repo"; the host is empty unless one is given.
+func (s *MCPServer) repoRefFromArgs(args map[string]interface{}) (string, string, string, error) {
+	host := ""
 	owner := s.config.RepoOwner
 	repo := s.config.RepoName
 
@@ -113,42 +144,70 @@ func (s *MCPServer) repoFromArgs(args map[string]interface{}) (string, string, e
 		repo = strings.TrimSpace(v)
 	}
 
-	// Handle repo="owner/repo" format by splitting into owner and repo
+	// Handle repo="owner/repo" and repo="host/owner/repo" by splitting it up
 	if strings.Contains(repo, "/") {
-		parts := strings.SplitN(repo, "/", 2)
-		if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
-			owner = parts[0]
-			repo = parts[1]
+		h, o, r, err := github.ParseRepoRef(repo)
+		if err != nil {
+			return "", "", "", err
 		}
+		host, owner, repo = h, o, r
 	}
 
 	if owner == "" || repo == "" {
-		return "", "", fmt.Errorf("repository owner/repo not set. Provide owner and repo arguments")
+		return "", "", "", fmt.Errorf("repository owner/repo not set. Provide owner and repo arguments")
 	}
-	return owner, repo, nil
+	return host, owner, repo, nil
 }
 
 func (s *MCPServer) clientFromArgs(args map[string]interface{}) (*github.Client, string, string, error) {
-	owner, repo, err := s.repoFromArgs(args)
+	host, owner, repo, err := s.repoRefFromArgs(args)
+	if err != nil {
+		return nil, "", "", err
+	}
+	c, err := s.clientForHost(host, owner, repo)
 	if err != nil {
 		return nil, "", "", err
 	}
+	return c, owner, repo, nil
+}
+
+// clientForRepo creates a client for owner/repo on the configured host.
+func (s *MCPServer) clientForRepo(owner, repo string) (*github.Client, error) {
+	return s.clientForHost("", owner, repo)
+}
+
+// clientForHost creates a client for owner/repo on host using the server's configuration.
+// An empty host means the configured one. Other hosts get API URLs derived from the host
+// name and their own token, so the configured token and base URL never leak across hosts.
+func (s *MCPServer) clientForHost(host, owner, repo string) (*github.Client, error) {
 	perPageLimit := s.config.PerPageLimit
 	if perPageLimit <= 0 {
 		perPageLimit = 50
 	}
-	c, err := github.NewClientWithOptions(github.ClientOptions{
+	opts := github.ClientOptions{
 		Token:        s.config.Token,
 		Owner:        owner,
 		Repo:         repo,
 		PerPageLimit: perPageLimit,
+		Host:         s.config.Host,
 		APIBaseURL:   s.config.APIBaseURL,
 		UploadURL:    s.config.UploadURL,
-	})
-	if err != nil {
-		return nil, "", "", err
-	}
-	return c, owner, repo, nil
+		AllowedRefs:  s.config.AllowedTriggerRefs,
+		DefaultRefs:  s.config.DefaultRefs(owner, repo),
+		LogCacheDir:  s.config.LogCacheDir(),
+		TokenSource:  s.tokens,
+		UserAgent:    github.UserAgent(s.config.Version, s.config.UserAgentSuffix),
+	}
+	if loc, err := s.config.Location(); err == nil {
+		opts.Location = loc
+	}
+	if host != "" && github.NormalizeHost(host) != github.NormalizeHost(s.config.Host) {
+		opts.Host = host
+		opts.APIBaseURL, opts.UploadURL = "", ""
+		opts.Token = s.config.TokenForHost(host)
+		opts.TokenSource = nil
+	}
+	return github.NewClientWithOptions(opts)
 }
 
 // Helper functions to reduce repetition
@@ -170,35 +229,23 @@ func (s *MCPServer) getLogLines() int {
 }
 
 func (s *MCPServer) formatAuthErrorWithRepo(err error, msg, repo string) string {
-	errStr := ""
-	if err != nil {
-		errStr = strings.ToLower(err.Error())
-	}
-
-	if strings.Contains(errStr, "resource not accessible by personal access token") {
+	if github.IsTokenScopeError(err) {
 		return fmt.Sprintf("%s: %v\nGitHub rejected the token for this endpoint.\nFor fine-grained PATs, grant repository access plus:\n- Actions: Read (runs/jobs/logs/artifacts)\nFor classic PATs on private repos, include the 'repo' scope.", msg, err)
 	}
 
-	if strings.Contains(errStr, "401") || strings.Contains(errStr, "unauthorized") || strings.Contains(errStr, "bad credentials") || strings.Contains(errStr, "log access unauthorized") {
+	err = github.Classify(err)
+	switch {
+	case errors.Is(err, github.ErrUnauthorized):
 		return fmt.Sprintf("%s: %v\nGitHub rejected authentication for %s.\nSet a valid GITHUB_TOKEN and ensure it can read Actions data in this repository.", msg, err, repo)
-	}
-
-	var rateLimitErr *ghapi.RateLimitError
-	if errors.As(err, &rateLimitErr) {
+	case errors.Is(err, github.ErrRateLimited):
 		return fmt.Sprintf("%s: GitHub API rate limit exceeded for %s.\nTry again later or use a token with higher rate limits.", msg, repo)
-	}
-
-	if strings.Contains(errStr, "403") || strings.Contains(errStr, "insufficient") || strings.Contains(errStr, "forbidden") {
+	case errors.Is(err, github.ErrLogsExpired):
+		return fmt.Sprintf("%s: %v\nThe logs for this run are no longer available in %s.", msg, err, repo)
+	case errors.Is(err, github.ErrForbidden):
 		return fmt.Sprintf("%s: %v\nGitHub accepted authentication but denied authorization for %s.\nThe token likely lacks required repository permissions for this operation.", msg, err, repo)
-	}
-
-	if strings.Contains(errStr, "404") {
+	case errors.Is(err, github.ErrNotFound):
 		return fmt.Sprintf("%s: %v\nGitHub returned 404 for %s.\nThis usually means the run/ref/artifact is not in this repository, or the token cannot see a private repository.", msg, err, repo)
 	}
-
-	if config.IsAuthenticationError(err) {
-		return fmt.Sprintf("authentication failed: %v\nMake sure GITHUB_TOKEN is set (or run 'gh auth login' on macOS) and has access to %s", err, repo)
-	}
 	return fmt.Sprintf("%s: %v", msg, err)
 }
 
@@ -212,6 +259,27 @@ func (s *MCPServer) formatAuthErrorForRepo(err error, msg, owner, repo string) s
 	return s.formatAuthErrorWithRepo(err, msg, fmt.Sprintf("%s/%s", owner, repo))
 }
 
+// apiErrorResult returns an error result for a failed GitHub call. The text starts with a
+// machine-readable code in brackets (e.g. "[not_found]"), which is also returned as
+// structured content alongside the message.
+func (s *MCPServer) apiErrorResult(err error, msg, owner, repo string) *mcp.CallToolResult {
+	code := github.ErrorCode(err)
+	text := s.formatAuthErrorForRepo(err, msg, owner, repo)
+	details := map[string]interface{}{
+		"code":    code,
+		"message": text,
+	}
+	var ambiguous *github.AmbiguousWorkflowError
+	if errors.As(err, &ambiguous) {
+		details["candidates"] = ambiguous.Candidates
+	}
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{mcp.NewTextContent(fmt.Sprintf("[%s] %s", code, text))},
+		StructuredContent: map[string]interface{}{"error": details},
+		IsError:           true,
+	}
+}
+
 // jsonResult returns a successful JSON response
 func jsonResult(data interface{}) (*mcp.CallToolResult, error) {
 	d, err := json.Marshal(data)
@@ -264,6 +332,78 @@ func truncateLogResult(logs string, defaultLines int, callerLimited bool) *mcp.C
 	return mcp.NewToolResultText(strings.Join(truncated, "\n") + banner)
 }
 
+// Log output representations accepted by the "as" argument.
+const (
+	logOutputText  = "text"
+	logOutputJSONL = "jsonl"
+)
+
+// logOutputFromArgs returns the requested log representation, or an error result for an
+// unknown one.
+func logOutputFromArgs(args map[string]interface{}) (string, *mcp.CallToolResult) {
+	as, _ := args["as"].(string)
+	switch as {
+	case "", logOutputText:
+		return logOutputText, nil
+	case logOutputJSONL:
+		return logOutputJSONL, nil
+	}
+	return "", errorResult(fmt.Sprintf("invalid as %q: must be one of %s, %s", as, logOutputText, logOutputJSONL))
+}
+
+// jsonlLogResult returns log entries as JSON lines, keeping the last defaultLines entries
+// when the caller hasn't limited the output. The truncation notice goes in a separate
+// content block so the JSON lines stay parseable.
+func jsonlLogResult(entries []github.LogEntry, defaultLines int, callerLimited bool) *mcp.CallToolResult {
+	total := len(entries)
+	if callerLimited || total <= defaultLines {
+		return mcp.NewToolResultText(github.FormatLogEntriesJSONL(entries))
+	}
+
+	result := mcp.NewToolResultText(github.FormatLogEntriesJSONL(entries[total-defaultLines:]))
+	result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf(
+		"[showing last %d of %d lines] Use head/tail/offset/search/search_regex/section/file_pattern to refine.",
+		defaultLines, total,
+	)))
+	return result
+}
+
+// secretsMaskedField is the name of the masked secret count in result metadata.
+const secretsMaskedField = "secrets_masked"
+
+// maskSecrets masks credentials and high-entropy strings in log output unless masking is
+// disabled, and returns how many values were masked.
+func (s *MCPServer) maskSecrets(logs string) (string, int) {
+	if s.config.DisableSecretMasking {
+		return logs, 0
+	}
+	masked, count := github.MaskSecrets(logs)
+	if count > 0 {
+		s.log.Warnf("Masked %d potential secret(s) in log output", count)
+	}
+	return masked, count
+}
+
+// setMetaField sets a field of a result's _meta.
+func setMetaField(result *mcp.CallToolResult, name string, value any) {
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[name] = value
+}
+
+// withMaskedCount reports count masked secrets in the result's _meta.secrets_masked, rather
+// than in the content, where a note would read as a log line.
+func withMaskedCount(result *mcp.CallToolResult, count int) *mcp.CallToolResult {
+	if count > 0 {
+		setMetaField(result, secretsMaskedField, count)
+	}
+	return result
+}
+
 // errorResult returns an error response
 func errorResult(msg string) *mcp.CallToolResult {
 	return mcp.NewToolResultError(msg)
@@ -278,13 +418,41 @@ func extractRunID(arguments map[string]interface{}) (int64, bool) {
 	return int64(runIDFloat), true
 }
 
+// extractJobID returns the job_id argument, given as a number or a numeric string.
+func extractJobID(arguments map[string]interface{}) (int64, bool) {
+	switch v := arguments["job_id"].(type) {
+	case float64:
+		return int64(v), v > 0
+	case string:
+		id, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		return id, err == nil && id > 0
+	}
+	return 0, false
+}
+
 func NewMCPServer(cfg *config.Config, log *logrus.Logger) *MCPServer {
+	metrics := newToolMetrics()
+	outputs, err := newOutputPipeline(cfg.OutputTransforms)
+	if err != nil {
+		log.Fatalf("invalid output_transforms: %v", err)
+	}
+	scratch := newScratchStore(cfg.ScratchThreshold, time.Duration(cfg.ScratchTTL)*time.Second)
 	s := server.NewMCPServer(
 		"github-actions-mcp",
 		"Get GitHub Actions status and manage workflow runs",
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(true, false),
+		server.WithToolHandlerMiddleware(requestIDMiddleware(log)),
+		server.WithToolHandlerMiddleware(cacheProvenanceMiddleware),
+		server.WithToolHandlerMiddleware(metrics.middleware),
+		server.WithToolHandlerMiddleware(scratch.middleware),
+		server.WithToolHandlerMiddleware(outputs.middleware),
 	)
 
+	// Sampling lets get_run summarize huge logs with the client's model on request.
+	s.EnableSampling()
+
+	log.AddHook(requestIDHook{})
 	github.SetLogger(log)
 
 	// Use configured per-page limit or default to 50
@@ -293,34 +461,71 @@ func NewMCPServer(cfg *config.Config, log *logrus.Logger) *MCPServer {
 		perPageLimit = 50
 	}
 
+	tokens := github.NewTokenSource(cfg.Token, cfg.ReloadToken)
+	tokens.AddTokens(cfg.Tokens...)
 	ghClient, err := github.NewClientWithOptions(github.ClientOptions{
 		Token:        cfg.Token,
 		Owner:        cfg.RepoOwner,
 		Repo:         cfg.RepoName,
 		PerPageLimit: perPageLimit,
+		Host:         cfg.Host,
 		APIBaseURL:   cfg.APIBaseURL,
 		UploadURL:    cfg.UploadURL,
+		AllowedRefs:  cfg.AllowedTriggerRefs,
+		DefaultRefs:  cfg.DefaultRefs(cfg.RepoOwner, cfg.RepoName),
+		LogCacheDir:  cfg.LogCacheDir(),
+		TokenSource:  tokens,
+		UserAgent:    github.UserAgent(cfg.Version, cfg.UserAgentSuffix),
 	})
 	if err != nil {
 		log.Fatalf("failed to create GitHub client: %v", err)
 	}
 
 	mcpServer := &MCPServer{
-		srv:    s,
-		client: ghClient,
-		config: cfg,
-		log:    log,
+		srv:        s,
+		client:     ghClient,
+		config:     cfg,
+		log:        log,
+		dispatches: newDispatchGuard(cfg.DispatchDedupWindow),
+		tokens:     tokens,
+		notifier:   newWebhookNotifier(cfg.NotifyWebhookURL, log),
+		metrics:    metrics,
+		outputs:    outputs,
+		scratch:    scratch,
+
+		failureCursors: make(map[string]*github.FailureCursor),
+		logSubs:        jobLogSubscriptions{followers: make(map[string]context.CancelFunc), interval: jobLogPollInterval},
+	}
+
+	if store, err := state.Open(cfg.StateDir); err != nil {
+		log.Warnf("State will not be persisted: %v", err)
+	} else {
+		mcpServer.state = store
+		if _, err := store.Load(failureCursorsState, failureCursorsSchema, &mcpServer.failureCursors); err != nil {
+			log.Warnf("Ignoring saved failure cursors: %v", err)
+		}
 	}
+	mcpServer.lastInputs = newDispatchInputs(mcpServer.state, log)
 
 	mcpServer.registerTools()
+	mcpServer.registerResources()
 
 	return mcpServer
 }
 
+// ValidateAccess checks that GitHub accepts the configured token and, if a default
+// repository is configured, that the token can read it.
+func (s *MCPServer) ValidateAccess(ctx context.Context) error {
+	if err := s.client.CheckAccess(ctx); err != nil {
+		return errors.New(s.formatAuthError(err, "startup validation failed"))
+	}
+	return nil
+}
+
 func (s *MCPServer) registerTools() {
 	// Tool: list_workflows
 	s.srv.AddTool(mcp.NewTool("list_workflows",
-		mcp.WithDescription("List all workflows available in the repository"),
+		mcp.WithDescription("List all workflows available in the repository. Disabled workflows are listed first, flagged with disabled and disabled_reason."),
 		mcp.WithString("owner",
 			mcp.Description("Optional: override repository owner for this call"),
 		),
@@ -335,6 +540,9 @@ func (s *MCPServer) registerTools() {
 			mcp.Description("Output format: compact (default, single-line JSON), pretty (indented JSON), or full (detailed)"),
 			mcp.DefaultString("compact"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Optional: skip the response and job log caches and fetch everything from GitHub, when cached data looks stale"),
+		),
 	), s.listWorkflows)
 
 	// Tool: list_runs
@@ -371,10 +579,19 @@ func (s *MCPServer) registerTools() {
 		mcp.WithString("actor",
 			mcp.Description("Optional: GitHub username to filter by"),
 		),
+		mcp.WithString("runner",
+			mcp.Description("Optional: runner name (e.g., a self-hosted runner) to filter by. Keeps runs with at least one job executed on that runner; costs one extra API call per run."),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: minimal (basic fields), compact (default, most fields), or full (all fields)"),
 			mcp.DefaultString("compact"),
 		),
+		mcp.WithString("group_by",
+			mcp.Description("Optional: nest runs under the change that triggered them: commit (head SHA) or pr (pull request; runs without one are grouped by commit). Each group reports its run outcomes."),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Optional: skip the response and job log caches and fetch everything from GitHub, when cached data looks stale"),
+		),
 	), s.listRuns)
 
 	// Tool: get_run
@@ -405,7 +622,7 @@ func (s *MCPServer) registerTools() {
 			mcp.DefaultNumber(1024*1024),
 		),
 		mcp.WithNumber("job_id",
-			mcp.Description("For element=logs or element=log_sections: specific job ID to get logs/sections for"),
+			mcp.Description("For element=logs or element=log_sections: specific job ID to get logs/sections for. head, tail, offset, no_headers, search, and section apply to job logs as they do to run logs"),
 		),
 		mcp.WithBoolean("per_job",
 			mcp.Description("For element=logs: get logs per-job instead of all logs combined"),
@@ -420,13 +637,13 @@ func (s *MCPServer) registerTools() {
 			mcp.Description("For element=logs: return the last N lines of logs (default: auto-truncated to last ~100 lines if neither head nor tail is specified)"),
 		),
 		mcp.WithNumber("offset",
-			mcp.Description("For element=logs: skip first N lines before returning (0-based)"),
+			mcp.Description("For element=logs: skip first N lines before returning (0-based); applied before head or tail"),
 		),
 		mcp.WithString("search",
 			mcp.Description("For element=logs: search/filter logs to lines containing this substring (case-insensitive)"),
 		),
 		mcp.WithString("search_regex",
-			mcp.Description("For element=logs: filter logs to lines matching this regex pattern"),
+			mcp.Description("For element=logs: filter logs to lines matching this regex pattern (RE2 syntax; overly complex patterns are rejected and only the first 16KB of each line is matched)"),
 		),
 		mcp.WithNumber("context",
 			mcp.Description("For element=logs: number of lines to show before and after each search match (default: 0)"),
@@ -436,12 +653,21 @@ func (s *MCPServer) registerTools() {
 			mcp.Description("For element=logs: don't print file headers (=== filename ===)"),
 		),
 		mcp.WithString("section",
-			mcp.Description("For element=logs: extract a specific section by name/pattern (e.g., 'Build', 'Test'). GitHub Actions sections are marked with ##[group]Section Name"),
+			mcp.Description("For element=logs: extract a specific section by name/pattern (e.g., 'Build', 'Test'). GitHub Actions sections are marked with ##[group]Section Name. head, tail, and offset then slice the section"),
 		),
 		mcp.WithString("format",
 			mcp.Description("For element=info, jobs, artifacts, log_files: output format (compact/full, default: compact)"),
 			mcp.DefaultString("compact"),
 		),
+		mcp.WithString("as",
+			mcp.Description("For element=logs: text (default) or jsonl, which returns one {job, step, ts, line} object per line for programmatic sorting and merging"),
+		),
+		mcp.WithBoolean("summarize",
+			mcp.Description("For element=logs: instead of returning the logs, have the client's model summarize them chunk by chunk through MCP sampling and return only the summary, so huge logs do not fill the conversation. The other log arguments select what is summarized. Requires a client that supports sampling (default: false)"),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Optional: skip the response and job log caches and fetch everything from GitHub, when cached data looks stale"),
+		),
 	), s.getRun)
 
 	// Tool: analyze_timing
@@ -503,6 +729,9 @@ func (s *MCPServer) registerTools() {
 			mcp.Description("Output format: summary (default), compact, or full"),
 			mcp.DefaultString("summary"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Optional: skip the response and job log caches and fetch everything from GitHub, when cached data looks stale"),
+		),
 	), s.getCheckStatus)
 
 	// Tool: wait_for_run
@@ -522,8 +751,34 @@ func (s *MCPServer) registerTools() {
 			mcp.Description("Maximum time to wait in minutes (default: 30)"),
 			mcp.DefaultNumber(30),
 		),
+		mcp.WithBoolean("stop_on_approval",
+			mcp.Description("Optional: return with status waiting_for_approval as soon as the run is blocked on an environment's required reviewers, instead of waiting for the approval"),
+		),
 	), s.waitForRun)
 
+	// Tool: wait_for_workflow_run
+	s.srv.AddTool(mcp.NewTool("wait_for_workflow_run",
+		mcp.WithDescription("Poll a workflow run at a chosen interval until it completes or the timeout passes. Returns the final status and conclusion, how many times the run was polled, and the elapsed time; timed_out is true when the run was still going"),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID to wait for"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("poll_interval_seconds",
+			mcp.Description("Seconds between polls (default: 5, at most 300)"),
+			mcp.DefaultNumber(5),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("Maximum time to wait in seconds (default: 600, at most 7200)"),
+			mcp.DefaultNumber(600),
+		),
+	), s.waitForWorkflowRun)
+
 	// Tool: wait_for_commit_checks
 	s.srv.AddTool(mcp.NewTool("wait_for_commit_checks",
 		mcp.WithDescription("Wait for all CI check runs for a commit ref (SHA, branch, or tag) to complete."),
@@ -585,7 +840,7 @@ func (s *MCPServer) registerTools() {
 
 	// Tool: diagnose_failure
 	s.srv.AddTool(mcp.NewTool("diagnose_failure",
-		mcp.WithDescription("One-shot diagnosis of a failed workflow run: identifies failed jobs/steps, extracts error lines from logs, and optionally checks for flakiness. Returns a structured diagnosis with actionable error context."),
+		mcp.WithDescription("One-shot diagnosis of a failed workflow run: identifies failed jobs/steps, extracts error lines from logs, and optionally checks for flakiness. Also explains skipped jobs and the skipped steps of successful jobs by evaluating their if: conditions, for runs that succeeded without running a step. For a run still queued, checks the runner labels its queued jobs wait for. Returns a structured diagnosis with actionable error context."),
 		mcp.WithString("owner",
 			mcp.Description("Optional: override repository owner for this call"),
 		),
@@ -602,11 +857,31 @@ func (s *MCPServer) registerTools() {
 			mcp.Description("Maximum number of error lines to extract per job (default: 50)"),
 			mcp.DefaultNumber(50),
 		),
+		mcp.WithString("format",
+			mcp.Description("Output format: json (default) or markdown, which fences each job's error lines as a code block with a language hint for the tool that produced them (go, gcc, pytest, webpack)"),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Optional: skip the response and job log caches and fetch everything from GitHub, when cached data looks stale"),
+		),
 	), s.diagnoseFailure)
 
+	// Tool: compare_with_last_green
+	s.srv.AddTool(mcp.NewTool("compare_with_last_green",
+		mcp.WithDescription("Compare a workflow run against the most recent successful run of the same workflow and branch: newly failing jobs, fixed jobs, duration changes, and commits in between."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID to compare. If omitted, uses the latest failed run on the current branch."),
+		),
+	), s.compareWithLastGreen)
+
 	// Tool: download_artifact
 	s.srv.AddTool(mcp.NewTool("download_artifact",
-		mcp.WithDescription("Download a workflow run artifact to disk"),
+		mcp.WithDescription("Download a workflow run artifact to disk, or with inline=true return its files in the response (text as-is, binary as base64)"),
 		mcp.WithString("owner",
 			mcp.Description("Optional: override repository owner for this call"),
 		),
@@ -620,71 +895,1256 @@ func (s *MCPServer) registerTools() {
 		mcp.WithString("output_path",
 			mcp.Description("Optional: path where to save the artifact (default: {artifact-name}.zip)"),
 		),
+		mcp.WithBoolean("inline",
+			mcp.Description("Optional: return the artifact's files in the response instead of saving it to disk (default: false)"),
+		),
+		mcp.WithString("file_pattern",
+			mcp.Description("Optional: with inline, glob pattern to filter files within the artifact (e.g., '*.xml')"),
+		),
+		mcp.WithNumber("max_file_size",
+			mcp.Description("Optional: with inline, maximum size of individual files to return in bytes (default: 1MB). Larger files show size info only."),
+		),
 	), s.downloadArtifact)
-}
-
-func (s *MCPServer) listWorkflows(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	args := request.GetArguments()
-	limit := s.getLimit()
-
-	if l, ok := args["limit"]; ok {
-		if n, err := strconv.Atoi(fmt.Sprintf("%.0f", l)); err == nil {
-			limit = n
-		}
-	}
 
-	format := s.getFormat()
-	if f, ok := args["format"].(string); ok {
-		format = f
-	}
+	// Tool: get_retention_policy
+	s.srv.AddTool(mcp.NewTool("get_retention_policy",
+		mcp.WithDescription("Get the repository's artifact and log retention period in days"),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+	), s.getRetentionPolicy)
 
-	client, owner, repo, err := s.clientFromArgs(args)
-	if err != nil {
-		return errorResult(err.Error()), nil
-	}
+	// Tool: set_retention_policy
+	s.srv.AddTool(mcp.NewTool("set_retention_policy",
+		mcp.WithDescription("Set the repository's artifact and log retention period in days (requires admin access to the repository)"),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("days",
+			mcp.Description("Retention period in days (1-90 for public repositories, 1-400 for private repositories)"),
+			mcp.Required(),
+		),
+	), s.setRetentionPolicy)
 
-	s.log.Infof("Listing workflows for %s/%s (limit: %d, format: %s)", owner, repo, limit, format)
+	// Tool: get_artifact_expiry
+	s.srv.AddTool(mcp.NewTool("get_artifact_expiry",
+		mcp.WithDescription("Report when each artifact of a workflow run expires, including days remaining and total storage size"),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID"),
+			mcp.Required(),
+		),
+	), s.getArtifactExpiry)
 
-	workflows, err := client.GetWorkflows(ctx)
-	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, "failed to list workflows", owner, repo)), nil
-	}
+	// Tool: get_oidc_config
+	s.srv.AddTool(mcp.NewTool("get_oidc_config",
+		mcp.WithDescription("Inspect the repository and organization Actions OIDC subject claim templates and show example `sub` claims for recent runs, to debug cloud federation trust policies"),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Number of recent runs to render example subjects for (default: 5)"),
+			mcp.DefaultNumber(5),
+		),
+	), s.getOIDCConfig)
 
-	// Apply limit
-	result := workflows[:0]
-	for _, w := range workflows {
-		if len(result) >= limit {
-			break
-		}
-		result = append(result, w)
-	}
+	// Tool: get_branch_policies
+	s.srv.AddTool(mcp.NewTool("get_branch_policies",
+		mcp.WithDescription("List repository and organization rulesets that apply to a branch, the required workflows and status checks they enforce, and whether recent runs satisfy them. Use this to explain merge blocks caused by org-level policies."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Optional: branch to evaluate (default: the repository's default branch)"),
+		),
+	), s.getBranchPolicies)
 
-	switch format {
-	case "pretty", "full":
-		return jsonResultPretty(result)
-	default:
-		return jsonResult(result)
-	}
-}
+	// Tool: trigger_workflow
+	s.srv.AddTool(mcp.NewTool("trigger_workflow",
+		mcp.WithDescription("Trigger a workflow via workflow_dispatch and identify the run it created (matched by ref, event, actor, and creation time, or by a correlation input)."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("workflow",
+			mcp.Description("Workflow selector (name, path, or numeric ID)"),
+			mcp.Required(),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Optional: branch or tag to run on (default: the workflow's default ref from workflow_default_refs, else the repository's default branch)"),
+		),
+		mcp.WithObject("inputs",
+			mcp.Description("Optional: workflow_dispatch inputs as a JSON object"),
+		),
+		mcp.WithString("correlation_input",
+			mcp.Description("Optional: name of a workflow input to fill with a unique marker, used to identify the created run when the workflow's run-name includes it. Ignored if the workflow does not declare the input."),
+		),
+		mcp.WithBoolean("reuse_last_inputs",
+			mcp.Description("Optional: fill inputs not given in `inputs` from the last dispatch of this workflow (remembered across restarts)"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Optional: dispatch even if an identical dispatch (same workflow, ref, and inputs) was issued recently"),
+		),
+		mcp.WithBoolean("enable_if_disabled",
+			mcp.Description("Optional: if the workflow is disabled (manually or after 60 days of inactivity), enable it before dispatching"),
+		),
+	), s.triggerWorkflow)
 
-func (s *MCPServer) listRuns(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	args := request.GetArguments()
-	client, owner, repo, err := s.clientFromArgs(args)
-	if err != nil {
-		return errorResult(err.Error()), nil
-	}
+	// Tool: trigger_and_wait
+	s.srv.AddTool(mcp.NewTool("trigger_and_wait",
+		mcp.WithDescription("Trigger a workflow via workflow_dispatch, identify the run it created (same ref, workflow_dispatch event, created after the dispatch), and wait for it to complete. When the run fails, the result includes the failed jobs and steps with an excerpt of their error log lines."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("workflow",
+			mcp.Description("Workflow selector (name, path, or numeric ID)"),
+			mcp.Required(),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Optional: branch or tag to run on (default: the workflow's default ref from workflow_default_refs, else the repository's default branch)"),
+		),
+		mcp.WithObject("inputs",
+			mcp.Description("Optional: workflow_dispatch inputs as a JSON object"),
+		),
+		mcp.WithString("correlation_input",
+			mcp.Description("Optional: name of a workflow input to fill with a unique marker, used to identify the created run when the workflow's run-name includes it. Ignored if the workflow does not declare the input."),
+		),
+		mcp.WithBoolean("reuse_last_inputs",
+			mcp.Description("Optional: fill inputs not given in `inputs` from the last dispatch of this workflow (remembered across restarts)"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Optional: dispatch even if an identical dispatch (same workflow, ref, and inputs) was issued recently"),
+		),
+		mcp.WithBoolean("enable_if_disabled",
+			mcp.Description("Optional: if the workflow is disabled (manually or after 60 days of inactivity), enable it before dispatching"),
+		),
+		mcp.WithNumber("timeout_minutes",
+			mcp.Description("Maximum time to wait for completion in minutes (default: 30)"),
+			mcp.DefaultNumber(30),
+		),
+		mcp.WithNumber("max_error_lines",
+			mcp.Description("Maximum error log lines per failed job in the failure excerpt (default: 20)"),
+			mcp.DefaultNumber(20),
+		),
+	), s.triggerAndWait)
 
-	// Build options
-	opts := &github.ListRunsOptions{
-		Per_page: s.getLimit(),
-	}
+	// Tool: get_new_failures
+	s.srv.AddTool(mcp.NewTool("get_new_failures",
+		mcp.WithDescription("Return workflow runs that failed since the previous call. The server remembers the last seen run per repository/workflow/branch for this session, so periodic calls only report new breakage. The first call reports failures among recent runs."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("workflow",
+			mcp.Description("Optional: workflow selector (name, path, or numeric ID)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Optional: only consider runs on this branch"),
+		),
+		mcp.WithNumber("since_run_id",
+			mcp.Description("Optional: report failures of runs newer than this run ID instead of the remembered position"),
+		),
+		mcp.WithBoolean("reset",
+			mcp.Description("Optional: forget the remembered position and report failures among recent runs"),
+		),
+	), s.getNewFailures)
 
-	if perPage, ok := args["per_page"].(float64); ok && perPage > 0 {
-		opts.Per_page = int(perPage)
-		if opts.Per_page > 100 {
-			opts.Per_page = 100
-		}
-	}
+	// Tool: get_pr_checks
+	s.srv.AddTool(mcp.NewTool("get_pr_checks",
+		mcp.WithDescription("Summarize all checks on a pull request's head commit: name, status, whether it is required on the base branch, duration, and details URL. GitHub Actions checks include the run_id and job_id; rerun a failed one with manage_run (action: rerun_failed). Also lists the latest workflow run of each workflow on the head commit, including runs awaiting approval for a fork and runs that failed to start, which have no checks."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("pr_number",
+			mcp.Description("Pull request number"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("required_only",
+			mcp.Description("Optional: only list required checks"),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Optional: skip the response and job log caches and fetch everything from GitHub, when cached data looks stale"),
+		),
+	), s.getPRChecks)
+
+	// Tool: get_environment_status
+	s.srv.AddTool(mcp.NewTool("get_environment_status",
+		mcp.WithDescription("Report, per deployment environment, the most recent successful deployment (commit, ref, run ID), pending deployments, and the last failed one. Answers \"what's currently on prod?\" in one call."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("environment",
+			mcp.Description("Optional: only report this environment (default: all environments)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of recent deployments to inspect per environment (default: 20)"),
+			mcp.DefaultNumber(20),
+		),
+	), s.getEnvironmentStatus)
+
+	// Tool: list_environments
+	s.srv.AddTool(mcp.NewTool("list_environments",
+		mcp.WithDescription("List the repository's deployment environments with their protection rules: required reviewers, wait timer, the branches allowed to deploy, and custom protection rules. Explains why a deployment job is waiting."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+	), s.listEnvironments)
+
+	// Tool: generate_workflow
+	s.srv.AddTool(mcp.NewTool("generate_workflow",
+		mcp.WithDescription("Generate starter workflow YAML for a common stack (go, node, docker, release) and the path to commit it at. Nothing is written to the repository."),
+		mcp.WithString("stack",
+			mcp.Description("Stack to generate for: go, node, docker (build and push an image), or release (GoReleaser on version tags)"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Optional: workflow name"),
+		),
+		mcp.WithString("branches",
+			mcp.Description("Optional: comma-separated branches that trigger the workflow (default: main)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Optional: Go or Node.js version (default: stable for Go, lts/* for Node.js)"),
+		),
+		mcp.WithString("command",
+			mcp.Description("Optional: override the test command (go, node) or GoReleaser arguments (release)"),
+		),
+		mcp.WithString("image",
+			mcp.Description("Optional (docker): image name (default: the repository name)"),
+		),
+		mcp.WithString("registry",
+			mcp.Description("Optional (docker): registry host (default: ghcr.io)"),
+		),
+		mcp.WithString("platforms",
+			mcp.Description("Optional (docker): comma-separated build platforms (default: linux/amd64)"),
+		),
+	), s.generateWorkflow)
+
+	// Tool: validate_workflow_yaml
+	s.srv.AddTool(mcp.NewTool("validate_workflow_yaml",
+		mcp.WithDescription("Validate a workflow file: YAML syntax, required keys, job and step structure, unknown job dependencies, and runs-on labels no GitHub-hosted or self-hosted runner has. When actionlint is installed, also reports expression type errors, invalid contexts, shellcheck findings in run: blocks, and deprecated syntax. Pass the YAML inline or a path to read from the repository."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("yaml",
+			mcp.Description("Workflow YAML to validate (takes precedence over path)"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of a workflow file in the repository, e.g. .github/workflows/ci.yml"),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Optional: branch, tag, or SHA to read path from (default: the default branch)"),
+		),
+		mcp.WithBoolean("actionlint",
+			mcp.Description("Run actionlint when it is installed (default: true)"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean("include_called",
+			mcp.Description("Optional: with path, also validate the reusable workflows it calls (recursively, including other repositories)"),
+		),
+	), s.validateWorkflowYAML)
+
+	// Tool: get_workflow_call_graph
+	s.srv.AddTool(mcp.NewTool("get_workflow_call_graph",
+		mcp.WithDescription("Resolve the reusable workflows a workflow calls (jobs with uses: ./.github/workflows/x.yml or owner/repo/.github/workflows/x.yml@ref), recursively and across repositories, and return the call tree."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of the calling workflow, e.g. .github/workflows/ci.yml"),
+			mcp.Required(),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Optional: branch, tag, or SHA to read the workflow from (default: the default branch)"),
+		),
+		mcp.WithNumber("max_depth",
+			mcp.Description("Maximum nesting depth to follow (default: 4, GitHub's limit)"),
+		),
+	), s.getWorkflowCallGraph)
+
+	// Tool: get_multi_repo_status
+	s.srv.AddTool(mcp.NewTool("get_multi_repo_status",
+		mcp.WithDescription("Fetch the latest run of each workflow for several repositories concurrently and return a consolidated status table (passing, failing, running, no_runs, or error per repository). Defaults to the repos list from the configuration."),
+		mcp.WithString("repos",
+			mcp.Description("Optional: comma-separated list of owner/repo (default: configured repos, or the configured repository)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Optional: branch to report on (default: each repository's default branch)"),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description("Maximum number of repositories queried at once (default: 8)"),
+			mcp.DefaultNumber(8),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Optional: skip the response and job log caches and fetch everything from GitHub, when cached data looks stale"),
+		),
+	), s.getMultiRepoStatus)
+
+	// Tool: get_run_timeline
+	s.srv.AddTool(mcp.NewTool("get_run_timeline",
+		mcp.WithDescription("Get the ordered events of a workflow run (queued, each job and step start/end, completion) with offsets and durations, for a Gantt-style view of where time went."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("include_steps",
+			mcp.Description("Include step start/end events (default: true)"),
+			mcp.DefaultBool(true),
+		),
+	), s.getRunTimeline)
+
+	// Tool: list_git_remotes
+	s.srv.AddTool(mcp.NewTool("list_git_remotes",
+		mcp.WithDescription("List the git remotes of the server's working directory with the GitHub repository each points to, which remote the repository is inferred from, and whether the checkout is a fork (origin and upstream pointing to different repositories). Pass a repository from the output as repo=\"owner/repo\" to operate on it."),
+		mcp.WithString("remote",
+			mcp.Description("Optional: remote to resolve instead of the configured preference (default: --remote, then upstream, then origin)"),
+		),
+	), s.listGitRemotes)
+
+	// Tool: get_stale_branch_report
+	s.srv.AddTool(mcp.NewTool("get_stale_branch_report",
+		mcp.WithDescription("Find CI runs that can likely be cleaned up: branches whose latest run failed more than stale_days ago, branches that still run CI after being merged into the default branch or deleted, and scheduled workflows that have been failing for longer than stale_days. Suggests branches to delete and schedules to prune or disable."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("stale_days",
+			mcp.Description("Optional: age in days after which a failure is stale; also the window for counting recent runs (default: 14)"),
+		),
+		mcp.WithNumber("max_runs",
+			mcp.Description("Optional: number of recent runs to scan (default: 300, max: 1000)"),
+		),
+	), s.getStaleBranchReport)
+
+	// Tool: analyze_path_filters
+	s.srv.AddTool(mcp.NewTool("analyze_path_filters",
+		mcp.WithDescription("Report which workflows will or won't run for a change by evaluating each workflow's on.push / on.pull_request branch and paths / paths-ignore filters against the changed files. Give the files directly or as a commit range (base and head)."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("paths",
+			mcp.Description("Changed files, comma- or newline-separated (e.g. 'services/api/main.go,docs/readme.md'). Required unless base and head are given."),
+		),
+		mcp.WithString("base",
+			mcp.Description("Optional: base commit, branch, or tag of the change (used with head instead of paths)"),
+		),
+		mcp.WithString("head",
+			mcp.Description("Optional: head commit, branch, or tag of the change; workflow files are read from it"),
+		),
+		mcp.WithString("event",
+			mcp.Description("Optional: push (default), pull_request, or pull_request_target"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Optional: branch pushed to, or the base branch of the pull request, to evaluate branch filters (default: branch filters are not evaluated)"),
+		),
+		mcp.WithString("workflow",
+			mcp.Description("Optional: only evaluate this workflow (name, path, or numeric ID)"),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Optional: ref to read workflow files from (default: head, then the default branch)"),
+		),
+	), s.analyzePathFilters)
+
+	// Tool: get_release_runs
+	s.srv.AddTool(mcp.NewTool("get_release_runs",
+		mcp.WithDescription("Answer \"did the release pipeline for v1.2.3 pass?\": resolve a tag or release name to its commit and return the runs for it (tag pushes, release events, and runs dispatched on the tag) with an overall verdict (success, failure, in_progress, or no_runs). Other runs of the same commit, such as the branch push it was tagged from, are listed separately."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("Tag name (e.g. v1.2.3) or release name"),
+			mcp.Required(),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: minimal (basic fields), compact (default, most fields), or full (all fields)"),
+			mcp.DefaultString("compact"),
+		),
+	), s.getReleaseRuns)
+
+	// Tool: run_release
+	s.srv.AddTool(mcp.NewTool("run_release",
+		mcp.WithDescription("Run a release in one call: optionally create the tag at a commit or branch, wait for the runs the tag triggers (tag pushes, release events, runs dispatched on the tag) to start and complete, and return their outcome, uploaded artifacts, and the assets of the tag's release. Runs started along the way, such as a release event fired when a workflow publishes the release, are followed too."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("Tag to release, e.g. v1.2.3"),
+			mcp.Required(),
+		),
+		mcp.WithString("target",
+			mcp.Description("Optional: commit SHA or branch to create the tag at. Omit to follow the runs of an existing tag."),
+		),
+		mcp.WithString("workflow",
+			mcp.Description("Optional: only follow runs of this workflow (name, path, or numeric ID)"),
+		),
+		mcp.WithNumber("timeout_minutes",
+			mcp.Description("Maximum time to wait for the runs to complete in minutes (default: 30, max: 120)"),
+			mcp.DefaultNumber(30),
+		),
+	), s.runRelease)
+
+	// Tool: download_run_logs
+	s.srv.AddTool(mcp.NewTool("download_run_logs",
+		mcp.WithDescription("Save a workflow run's logs ZIP archive to disk exactly as GitHub serves it (no filtering, truncation, or secret masking), for processing with other tools"),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID"),
+			mcp.Required(),
+		),
+		mcp.WithString("output_path",
+			mcp.Description("Optional: path where to save the archive (default: run-{run_id}-logs.zip)"),
+		),
+	), s.downloadRunLogs)
+
+	// Tool: export_run_bundle
+	s.srv.AddTool(mcp.NewTool("export_run_bundle",
+		mcp.WithDescription("Export the CI context of a run for a bug report: run metadata, jobs, annotations, the logs of failed steps, and the workflow file at the run's commit, as a markdown report or a ZIP bundle. Logs are secret-masked."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID to export. If omitted, exports the latest failed run on the current branch."),
+		),
+		mcp.WithString("format",
+			mcp.Description("Bundle format: markdown (default, a single report) or zip (bundle.md plus run.json, jobs.json, annotations.json, logs/, and the workflow file)"),
+			mcp.DefaultString("markdown"),
+		),
+		mcp.WithString("output_path",
+			mcp.Description("Optional: path where to save the bundle. Required for zip (default: run-{run_id}-bundle.zip); without it, markdown is returned inline."),
+		),
+		mcp.WithNumber("max_log_lines",
+			mcp.Description("Maximum number of log lines to keep per failed job (default: 200)"),
+			mcp.DefaultNumber(200),
+		),
+	), s.exportRunBundle)
+
+	// Tool: quick_action
+	s.srv.AddTool(mcp.NewTool("quick_action",
+		mcp.WithDescription(`Run a compact command against workflow runs, such as "rerun failed on main", "rerun 12345", "cancel all queued", or "cancel all in CI on feature-x". Without confirm, returns a preview of the runs the command applies to; call again with confirm: true to carry it out. "rerun failed" reruns the failed jobs of each workflow whose latest run failed.`),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("command",
+			mcp.Description(`The command: "rerun <run_id>", "rerun failed [<run_id>] [on <branch>] [in <workflow>]", "cancel <run_id>", or "cancel all [queued|in_progress] [on <branch>] [in <workflow>]"`),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Carry out the command (default: false, which only previews the runs it applies to)"),
+		),
+	), s.quickAction)
+
+	// Tool: triage_dependency_prs
+	s.srv.AddTool(mcp.NewTool("triage_dependency_prs",
+		mcp.WithDescription("List open dependency-update pull requests (Dependabot, Renovate) with failing checks, grouped by the set of checks that fail, so a check failing across many bot PRs stands out. Optionally rerun the failed jobs of one group or all of them."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("bots",
+			mcp.Description("Optional: comma-separated PR authors that count as dependency bots (default: dependabot[bot],renovate[bot]). PRs from dependabot/ and renovate/ branches are always included."),
+		),
+		mcp.WithString("rerun",
+			mcp.Description("Optional: rerun the failed jobs of the PRs in the group with this signature (as returned in groups), or \"all\" for every failing PR"),
+		),
+	), s.triageDependencyPRs)
+
+	// Tool: get_concurrency_report
+	s.srv.AddTool(mcp.NewTool("get_concurrency_report",
+		mcp.WithDescription("Report the concurrency: groups and cancel-in-progress settings of every workflow, the expensive jobs (macOS/Windows/larger runners, matrices, long timeouts, deployments) that run without concurrency control, groups shared between workflows, and suggested changes, for tuning CI cost and contention."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Optional: branch, tag, or commit to read the workflow files from (default: the default branch)"),
+		),
+	), s.getConcurrencyReport)
+
+	// Tool: get_timeout_report
+	s.srv.AddTool(mcp.NewTool("get_timeout_report",
+		mcp.WithDescription("Analyze a workflow's recent runs for timeouts: jobs that hit their timeout-minutes (or the 6-hour default), how close successful runs of each job get to the limit, and a suggested timeout-minutes per job."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("workflow",
+			mcp.Required(),
+			mcp.Description("Workflow selector (name, path, or numeric ID)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Optional: only analyze runs on this branch, and read the timeouts from it"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Number of recent completed runs to analyze (default: 20)"),
+		),
+	), s.getTimeoutReport)
+
+	// Tool: get_failure_heatmap
+	s.srv.AddTool(mcp.NewTool("get_failure_heatmap",
+		mcp.WithDescription("Bucket completed runs and failures by day of week and time of day (in the configured timezone, UTC by default) over a window, as a matrix with a markdown rendering, and flag time slots that fail far more often than the rest (e.g. a 02:00 scheduled run broken by nightly maintenance)."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("workflow",
+			mcp.Description("Optional: only count runs of this workflow (name, path, or numeric ID)"),
+		),
+		mcp.WithNumber("days",
+			mcp.Description("Number of days to look back (default: 30)"),
+		),
+		mcp.WithNumber("bucket_hours",
+			mcp.Description("Width of each time-of-day column in hours; must divide 24 (default: 3)"),
+		),
+	), s.getFailureHeatmap)
+
+	// Tool: bisect_failure
+	s.srv.AddTool(mcp.NewTool("bisect_failure",
+		mcp.WithDescription("Find where a failing workflow broke on a branch: walks back through its runs to the first failing commit and the last green one, and returns the suspect commit range with a summary of its diff (files, additions, deletions)."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("workflow",
+			mcp.Required(),
+			mcp.Description("Workflow selector (name, path, or numeric ID)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Optional: branch to bisect (default: the current git branch, or the repository's default branch)"),
+		),
+	), s.bisectFailure)
+
+	// Tool: list_workflow_jobs
+	s.srv.AddTool(mcp.NewTool("list_workflow_jobs",
+		mcp.WithDescription("List the jobs of a workflow run with each job's status, conclusion, runner, started/completed timestamps, and step breakdown, and name the jobs that failed."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID"),
+			mcp.Required(),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Jobs of the latest attempt (default) or of all attempts. Allowed: latest, all."),
+			mcp.DefaultString("latest"),
+		),
+		mcp.WithNumber("attempt_number",
+			mcp.Description("Optional: only jobs of this run attempt"),
+		),
+		mcp.WithString("conclusion",
+			mcp.Description("Optional: only jobs with this conclusion (e.g. failure, success, skipped)"),
+		),
+		mcp.WithBoolean("include_steps",
+			mcp.Description("Include each job's steps (default: true)"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Optional: skip the response and job log caches and fetch everything from GitHub, when cached data looks stale"),
+		),
+	), s.listWorkflowJobs)
+
+	// Tool: trigger_patch_branch
+	s.srv.AddTool(mcp.NewTool("trigger_patch_branch",
+		mcp.WithDescription("Test a speculative fix in real CI without local git: create a branch from a base ref, commit the given file changes to it through the contents API, dispatch a workflow on the branch, and wait for the run to complete."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("workflow",
+			mcp.Description("Workflow selector (name, path, or numeric ID); it must have a workflow_dispatch trigger on the branch"),
+			mcp.Required(),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Name of the branch to create; it must not exist yet"),
+			mcp.Required(),
+		),
+		mcp.WithString("base",
+			mcp.Description("Optional: branch, tag, or commit SHA to create the branch from (default: the repository's default branch)"),
+		),
+		mcp.WithArray("files",
+			mcp.Description("File changes to commit, one commit per file: objects with path and content, or path and \"delete\": true"),
+			mcp.Required(),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path":    map[string]any{"type": "string"},
+					"content": map[string]any{"type": "string"},
+					"delete":  map[string]any{"type": "boolean"},
+				},
+				"required": []string{"path"},
+			}),
+		),
+		mcp.WithString("message",
+			mcp.Description("Optional: commit message (default: \"Apply patch for CI run\")"),
+		),
+		mcp.WithObject("inputs",
+			mcp.Description("Optional: workflow_dispatch inputs as a JSON object"),
+		),
+		mcp.WithString("correlation_input",
+			mcp.Description("Optional: name of a workflow input to fill with a unique marker, used to identify the created run when the workflow's run-name includes it"),
+		),
+		mcp.WithBoolean("wait",
+			mcp.Description("Wait for the run to complete (default: true)"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithNumber("timeout_minutes",
+			mcp.Description("Maximum time to wait for completion in minutes (default: 30)"),
+			mcp.DefaultNumber(30),
+		),
+		mcp.WithBoolean("delete_branch",
+			mcp.Description("Optional: delete the branch once the run has completed"),
+		),
+	), s.triggerPatchBranch)
+
+	// Tool: rerequest_check
+	s.srv.AddTool(mcp.NewTool("rerequest_check",
+		mcp.WithDescription("Re-request checks created by GitHub Apps (not Actions workflow runs) that are stuck or flaky: one check run, one check suite, or every failed check run on a ref. The app that created the check receives the request and runs it again."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("check_run_id",
+			mcp.Description("ID of a check run to re-request"),
+		),
+		mcp.WithNumber("check_suite_id",
+			mcp.Description("ID of a check suite to re-request, including all of its check runs"),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Branch, tag, or commit SHA whose failed check runs should be re-requested"),
+		),
+		mcp.WithString("check_name",
+			mcp.Description("Optional: with ref, only re-request check runs with this name"),
+		),
+	), s.rerequestCheck)
+
+	// Tool: get_job_details
+	s.srv.AddTool(mcp.NewTool("get_job_details",
+		mcp.WithDescription("Get a job's metadata and all of its steps with number, name, status, conclusion, and duration, with failed steps picked out, to pinpoint the failing step before pulling logs."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("job_id",
+			mcp.Description("The job ID"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Optional: skip the response and job log caches and fetch everything from GitHub, when cached data looks stale"),
+		),
+	), s.getJobDetails)
+
+	// Tool: list_artifacts
+	s.srv.AddTool(mcp.NewTool("list_artifacts",
+		mcp.WithDescription("List all artifacts uploaded by a workflow run, such as test reports, with name, size, and expiry. Read one with get_artifact or download_artifact."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Optional: only list artifacts whose name matches this glob pattern (e.g., 'test-results-*')"),
+		),
+	), s.listArtifacts)
+
+	// Tool: delete_artifact
+	s.srv.AddTool(mcp.NewTool("delete_artifact",
+		mcp.WithDescription("Delete a workflow run artifact to free storage quota. Use list_artifacts or get_artifact_expiry to find large or stale artifacts."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("artifact_id",
+			mcp.Description("The artifact ID"),
+			mcp.Required(),
+		),
+	), s.deleteArtifact)
+
+	// Tool: list_caches
+	s.srv.AddTool(mcp.NewTool("list_caches",
+		mcp.WithDescription("List the repository's Actions caches with key, ref, size, and creation and last access times, to find stale or oversized entries."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Optional: only caches of this ref (branch name, refs/heads/<branch>, or refs/pull/<number>/merge)"),
+		),
+		mcp.WithString("key",
+			mcp.Description("Optional: only caches whose key starts with this prefix"),
+		),
+		mcp.WithString("sort",
+			mcp.Description("Optional: created_at, last_accessed_at (default), or size_in_bytes"),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Optional: asc or desc (default)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of caches to return (default: 100)"),
+			mcp.DefaultNumber(100),
+		),
+	), s.listCaches)
+
+	// Tool: get_cache_usage
+	s.srv.AddTool(mcp.NewTool("get_cache_usage",
+		mcp.WithDescription("Get how many Actions caches the repository holds and how much storage they use, broken down by ref, largest first."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+	), s.getCacheUsage)
+
+	// Tool: delete_cache
+	s.srv.AddTool(mcp.NewTool("delete_cache",
+		mcp.WithDescription("Evict Actions caches when builds misbehave or storage runs out: one cache by ID, or every cache with a key, optionally only on one ref."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("cache_id",
+			mcp.Description("ID of the cache to delete"),
+		),
+		mcp.WithString("key",
+			mcp.Description("Delete the caches with exactly this key"),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Optional: with key, only delete the caches of this ref"),
+		),
+	), s.deleteCache)
+
+	// Tool: get_metrics_snapshot
+	s.srv.AddTool(mcp.NewTool("get_metrics_snapshot",
+		mcp.WithDescription("Get the server's health counters since it started: GitHub API calls, errors, and latency, the latest rate limit per resource, in-process cache hit rates, and per-tool call counts, error counts, and latencies."),
+	), s.getMetricsSnapshot)
+
+	// Tool: rerun_job
+	s.srv.AddTool(mcp.NewTool("rerun_job",
+		mcp.WithDescription("Rerun a single job of a completed workflow run, along with the jobs that depend on it. Use manage_run to rerun a whole run or all of its failed jobs."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("job_id",
+			mcp.Description("The job ID to rerun"),
+			mcp.Required(),
+		),
+	), s.rerunJob)
+
+	// Tool: cancel_workflow_runs
+	s.srv.AddTool(mcp.NewTool("cancel_workflow_runs",
+		mcp.WithDescription("Cancel every queued or in-progress workflow run matching the filters, e.g. to clear a queue flooded by a bad push. Use dry_run to list the matching runs first."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("workflow",
+			mcp.Description("Optional: only runs of this workflow (ID, name, or path)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Optional: only runs on this branch"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Optional: queued or in_progress (default: both)"),
+		),
+		mcp.WithNumber("older_than_minutes",
+			mcp.Description("Optional: only runs created at least this many minutes ago"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Optional: list the matching runs without cancelling them (default: false)"),
+		),
+	), s.cancelWorkflowRuns)
+
+	// Tool: list_pending_deployments
+	s.srv.AddTool(mcp.NewTool("list_pending_deployments",
+		mcp.WithDescription("List the environments a workflow run is waiting on before its deployment jobs start: environment ID and name, wait timer, required reviewers, and whether the token's user may approve. Review them with review_pending_deployments."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID"),
+			mcp.Required(),
+		),
+	), s.listPendingDeployments)
+
+	// Tool: review_pending_deployments
+	s.srv.AddTool(mcp.NewTool("review_pending_deployments",
+		mcp.WithDescription("Approve or reject a workflow run's pending environment deployments. Without environment_ids, every pending environment the token's user may review is reviewed."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID"),
+			mcp.Required(),
+		),
+		mcp.WithString("state",
+			mcp.Description("approved or rejected"),
+			mcp.Required(),
+		),
+		mcp.WithArray("environment_ids",
+			mcp.Description("Optional: IDs of the environments to review, as listed by list_pending_deployments"),
+			mcp.Items(map[string]any{"type": "number"}),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Optional: comment recorded with the review"),
+		),
+	), s.reviewPendingDeployments)
+
+	// Tool: get_run_env
+	s.srv.AddTool(mcp.NewTool("get_run_env",
+		mcp.WithDescription("List the env: variables a run's workflow sets at workflow, job, and step level (as the file was at the run's commit) and the organization, repository, and environment variables (vars context) in scope. Reports vars references that no scope defines. Helps diagnose why a variable is empty in CI."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("The workflow run ID"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Optional: only report this variable (case-insensitive)"),
+		),
+	), s.getRunEnv)
+
+	// Tool: list_secrets
+	s.srv.AddTool(mcp.NewTool("list_secrets",
+		mcp.WithDescription("List the Actions secrets available to the repository's workflows: organization secrets shared with it, its own, and optionally an environment's. Only names and creation/update times are returned; GitHub never exposes secret values."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("environment",
+			mcp.Description("Optional: also list the secrets of this deployment environment"),
+		),
+	), s.listSecrets)
+
+	// Tool: set_secret
+	s.srv.AddTool(mcp.NewTool("set_secret",
+		mcp.WithDescription("Create or replace a repository or environment Actions secret. The value is encrypted locally with the repository's public key (libsodium sealed box) and only the ciphertext is sent to GitHub."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Secret name: letters, digits, and underscores, not starting with a digit or GITHUB_"),
+			mcp.Required(),
+		),
+		mcp.WithString("value",
+			mcp.Description("Secret value"),
+			mcp.Required(),
+		),
+		mcp.WithString("environment",
+			mcp.Description("Optional: set the secret on this deployment environment instead of the repository"),
+		),
+	), s.setSecret)
+
+	// Tool: list_variables
+	s.srv.AddTool(mcp.NewTool("list_variables",
+		mcp.WithDescription("List the Actions configuration variables (vars context) available to the repository's workflows, with their values: organization variables shared with it, its own, and optionally an environment's."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("environment",
+			mcp.Description("Optional: also list the variables of this deployment environment"),
+		),
+	), s.listVariables)
+
+	// Tool: set_variable
+	s.srv.AddTool(mcp.NewTool("set_variable",
+		mcp.WithDescription("Create or update a repository or environment Actions variable. Variables are stored in plain text; use set_secret for sensitive values."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Variable name: letters, digits, and underscores, not starting with a digit or GITHUB_"),
+			mcp.Required(),
+		),
+		mcp.WithString("value",
+			mcp.Description("Variable value"),
+			mcp.Required(),
+		),
+		mcp.WithString("environment",
+			mcp.Description("Optional: set the variable on this deployment environment instead of the repository"),
+		),
+	), s.setVariable)
+
+	// Tool: delete_variable
+	s.srv.AddTool(mcp.NewTool("delete_variable",
+		mcp.WithDescription("Delete a repository or environment Actions variable"),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Variable name"),
+			mcp.Required(),
+		),
+		mcp.WithString("environment",
+			mcp.Description("Optional: delete the variable from this deployment environment instead of the repository"),
+		),
+	), s.deleteVariable)
+
+	// Tool: evaluate_expression
+	s.srv.AddTool(mcp.NewTool("evaluate_expression",
+		mcp.WithDescription("Evaluate a GitHub Actions expression, e.g. an if: condition, against contexts you provide, and show the value of every sub-expression. Helps debug why a step or job was skipped without pushing trial commits. Supports the operators, literals, and functions of the expression syntax except hashFiles(); status functions use the given job status."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("expression",
+			mcp.Description("The expression, with or without ${{ }}, e.g. \"github.event_name == 'push' && contains(github.ref, 'release')\""),
+			mcp.Required(),
+		),
+		mcp.WithObject("context",
+			mcp.Description("Optional: contexts by name, e.g. {\"github\": {\"event_name\": \"push\"}, \"needs\": {\"build\": {\"result\": \"success\"}}, \"matrix\": {\"os\": \"ubuntu-latest\"}}"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("Optional: fill the github context from this run (event_name, ref, sha, actor, ...); properties given in context take precedence"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Optional: job status seen by success(), failure(), and cancelled() (default: success)"),
+			mcp.Enum(github.ExpressionStatusSuccess, github.ExpressionStatusFailure, github.ExpressionStatusCancelled),
+		),
+		mcp.WithBoolean("condition",
+			mcp.Description("Evaluate as an if: condition, which is implicitly success() && (...) unless it calls a status function (default: true)"),
+			mcp.DefaultBool(true),
+		),
+	), s.evaluateExpression)
+
+	// Tool: trace_artifacts
+	s.srv.AddTool(mcp.NewTool("trace_artifacts",
+		mcp.WithDescription("Trace artifacts across workflow_run-chained workflows: finds the chain of runs a run belongs to (the run that triggered it and the runs it triggered) and, for each downstream run, which artifacts of the upstream run its download steps fetch. Reports download steps that match no artifact of the upstream run. Helps debug multi-workflow pipelines."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("Any run of the chain, upstream or downstream"),
+			mcp.Required(),
+		),
+	), s.traceArtifacts)
+
+	// Tool: update_workflow_file
+	s.srv.AddTool(mcp.NewTool("update_workflow_file",
+		mcp.WithDescription("Commit new content for a workflow file under .github/workflows/, to an existing branch or to a new branch created for the change, so a proposed workflow fix can be applied end-to-end. The YAML is validated first, as in validate_workflow_yaml, and refused when it has issues unless force is set. Needs a token allowed to write workflow files."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of the workflow file, e.g. .github/workflows/ci.yml; created when it does not exist"),
+			mcp.Required(),
+		),
+		mcp.WithString("content",
+			mcp.Description("The complete new workflow YAML"),
+			mcp.Required(),
+		),
+		mcp.WithString("message",
+			mcp.Description("Optional: commit message (default: \"Update <path>\", or \"Add <path>\" for a new file)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Optional: branch to commit to, or to create new_branch from (default: the repository's default branch)"),
+		),
+		mcp.WithString("new_branch",
+			mcp.Description("Optional: create this branch and commit to it instead of to branch; it must not exist yet"),
+		),
+		mcp.WithBoolean("actionlint",
+			mcp.Description("Run actionlint during validation when it is installed (default: true)"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Optional: commit even when validation reports issues"),
+		),
+	), s.updateWorkflowFile)
+
+	// Tool: lint_workflow
+	s.srv.AddTool(mcp.NewTool("lint_workflow",
+		mcp.WithDescription("Lint a workflow file and return diagnostics with line and column numbers: YAML syntax and structure, expression syntax, unknown contexts and functions, needs and steps references that cannot resolve, and if: conditions that are always true. Uses actionlint too when it is installed. Takes a local file, a path in the repository, or inline YAML, so an edited workflow can be checked before it is committed and triggered."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithString("file",
+			mcp.Description("Path of a workflow file on the local filesystem, relative to the working directory or absolute"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of a workflow file in the repository, e.g. .github/workflows/ci.yml"),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Optional: branch, tag, or SHA to read path from (default: the default branch)"),
+		),
+		mcp.WithString("yaml",
+			mcp.Description("Workflow YAML to lint"),
+		),
+		mcp.WithBoolean("actionlint",
+			mcp.Description("Run actionlint when it is installed (default: true)"),
+			mcp.DefaultBool(true),
+		),
+	), s.lintWorkflow)
+
+	// Tool: get_run_chain
+	s.srv.AddTool(mcp.NewTool("get_run_chain",
+		mcp.WithDescription("Show the workflow_run pipeline a run belongs to: the upstream runs whose completion triggered it and the downstream runs it triggered, with their statuses and conclusions, as a list and as an indented tree."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("Any run of the chain, upstream or downstream"),
+			mcp.Required(),
+		),
+	), s.getRunChain)
+
+	// Tool: get_run_annotations
+	s.srv.AddTool(mcp.NewTool("get_run_annotations",
+		mcp.WithDescription("List the check-run annotations (file, line, level, message) of a workflow run's check suite. Annotations often carry the exact compiler or test error with its location, which is quicker to act on than raw logs. Ordered failures first."),
+		mcp.WithString("owner",
+			mcp.Description("Optional: override repository owner for this call"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("Optional: override repository name for this call"),
+		),
+		mcp.WithNumber("run_id",
+			mcp.Description("Workflow run ID"),
+			mcp.Required(),
+		),
+		mcp.WithString("level",
+			mcp.Description("Optional: only return annotations of this level"),
+			mcp.Enum("failure", "warning", "notice"),
+		),
+	), s.getRunAnnotations)
+
+	// Tool: get_actions_billing
+	s.srv.AddTool(mcp.NewTool("get_actions_billing",
+		mcp.WithDescription("Report the GitHub Actions minutes an organization or user used in the current billing cycle: included, used, paid, and remaining minutes, and the minutes per runner OS. Needs an organization owner or billing manager token (or the user's own token for a user account)."),
+		mcp.WithString("account",
+			mcp.Description("Optional: organization or user login (default: the configured repository owner)"),
+		),
+		mcp.WithString("scope",
+			mcp.Description("Optional: org or user. When omitted, the organization endpoint is tried first, then the user one"),
+			mcp.Enum(github.BillingScopeOrganization, github.BillingScopeUser),
+		),
+	), s.getActionsBilling)
+
+	// Tool: batch
+	s.srv.AddTool(mcp.NewTool("batch",
+		mcp.WithDescription("Run several read-only tool calls concurrently in one round trip, e.g. get_check_status, list_runs, and list_workflow_jobs together, and return their results keyed by call. Tools that change state or wait on runs cannot be batched. A failing call is reported in its result without affecting the others."),
+		mcp.WithArray("calls",
+			mcp.Description(fmt.Sprintf("Tool calls to run (at most %d): objects with tool, arguments (the tool's usual arguments), and an optional key for the result (default: the tool name, or tool#n when a tool is called more than once)", maxBatchCalls)),
+			mcp.Required(),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"key":       map[string]any{"type": "string"},
+					"tool":      map[string]any{"type": "string"},
+					"arguments": map[string]any{"type": "object"},
+				},
+				"required": []string{"tool"},
+			}),
+		),
+		mcp.WithNumber("max_concurrency",
+			mcp.Description(fmt.Sprintf("Optional: how many calls run at once (default: %d, max: %d)", defaultBatchConcurrency, maxBatchConcurrency)),
+		),
+	), s.batch)
+}
+
+func (s *MCPServer) listWorkflows(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	limit := s.getLimit()
+
+	if l, ok := args["limit"]; ok {
+		if n, err := strconv.Atoi(fmt.Sprintf("%.0f", l)); err == nil {
+			limit = n
+		}
+	}
+
+	format := s.getFormat()
+	if f, ok := args["format"].(string); ok {
+		format = f
+	}
+
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Listing workflows for %s/%s (limit: %d, format: %s)", owner, repo, limit, format)
+
+	workflows, err := client.GetWorkflows(ctx)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to list workflows", owner, repo), nil
+	}
+
+	// Disabled workflows come first: a disabled scheduled workflow fails silently.
+	sort.SliceStable(workflows, func(i, j int) bool {
+		return workflows[i].Disabled && !workflows[j].Disabled
+	})
+
+	// Apply limit
+	result := workflows[:0]
+	for _, w := range workflows {
+		if len(result) >= limit {
+			break
+		}
+		result = append(result, w)
+	}
+
+	switch format {
+	case "pretty", "full":
+		return jsonResultPretty(result)
+	default:
+		return jsonResult(result)
+	}
+}
+
+func (s *MCPServer) listRuns(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	// Build options
+	opts := &github.ListRunsOptions{
+		Per_page: s.getLimit(),
+	}
+
+	if perPage, ok := args["per_page"].(float64); ok && perPage > 0 {
+		opts.Per_page = int(perPage)
+		if opts.Per_page > 100 {
+			opts.Per_page = 100
+		}
+	}
 
 	if workflowIDNum, ok := args["workflow_id"].(float64); ok && workflowIDNum > 0 {
 		workflowIDStr := fmt.Sprintf("%.0f", workflowIDNum)
@@ -693,104 +2153,2234 @@ func (s *MCPServer) listRuns(ctx context.Context, request mcp.CallToolRequest) (
 		}
 	}
 
-	if branch, ok := args["branch"].(string); ok && branch != "" {
-		opts.Branch = branch
+	if branch, ok := args["branch"].(string); ok && branch != "" {
+		opts.Branch = branch
+	}
+
+	if status, ok := args["status"].(string); ok && status != "" {
+		opts.Status = status
+	}
+
+	if conclusion, ok := args["conclusion"].(string); ok && conclusion != "" {
+		opts.Conclusion = conclusion
+	}
+
+	if createdAfter, ok := args["created_after"].(string); ok && createdAfter != "" {
+		opts.CreatedAfter = createdAfter
+	}
+
+	if event, ok := args["event"].(string); ok && event != "" {
+		opts.Event = event
+	}
+
+	if actor, ok := args["actor"].(string); ok && actor != "" {
+		opts.Actor = actor
+	}
+
+	if runner, ok := args["runner"].(string); ok && runner != "" {
+		opts.Runner = runner
+	}
+
+	format := s.getFormat()
+	if f, ok := args["format"].(string); ok {
+		format = f
+	}
+
+	groupBy, _ := args["group_by"].(string)
+	if groupBy != "" && groupBy != github.GroupByCommit && groupBy != github.GroupByPR {
+		return errorResult(fmt.Sprintf("invalid group_by %q: expected commit or pr", groupBy)), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Listing runs for %s/%s", owner, repo)
+
+	runs, err := client.ListRepositoryWorkflowRunsWithOptions(ctx, opts)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to list workflow runs", owner, repo), nil
+	}
+
+	if groupBy == "" {
+		return jsonResult(formatRunList(runs, format))
+	}
+
+	groups, err := github.GroupRuns(runs, groupBy)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	result := make([]*runGroupResult, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, &runGroupResult{RunGroup: g, Runs: formatRunList(g.Runs, format)})
+	}
+	return jsonResult(result)
+}
+
+// runGroupResult is a run group whose runs are rendered in the requested format.
+type runGroupResult struct {
+	*github.RunGroup
+	Runs interface{} `json:"runs"`
+}
+
+// formatRunList renders runs as minimal, compact (default), or full entries.
+func formatRunList(runs []*github.WorkflowRun, format string) interface{} {
+	switch format {
+	case "minimal":
+		result := make([]*github.WorkflowRunMinimal, 0, len(runs))
+		for _, r := range runs {
+			result = append(result, &github.WorkflowRunMinimal{
+				ID:              r.ID,
+				Name:            r.Name,
+				Status:          r.Status,
+				Conclusion:      r.Conclusion,
+				CreatedAt:       r.CreatedAt,
+				Age:             r.Age,
+				DurationSeconds: r.DurationSeconds,
+			})
+		}
+		return result
+	case "full":
+		result := make([]*github.WorkflowRunFull, 0, len(runs))
+		for _, r := range runs {
+			result = append(result, &github.WorkflowRunFull{
+				ID:              r.ID,
+				Name:            r.Name,
+				Status:          r.Status,
+				Conclusion:      r.Conclusion,
+				Branch:          r.Branch,
+				Event:           r.Event,
+				Actor:           r.Actor,
+				CreatedAt:       r.CreatedAt,
+				UpdatedAt:       r.UpdatedAt,
+				URL:             r.URL,
+				RunNumber:       r.RunNumber,
+				WorkflowID:      r.WorkflowID,
+				HeadSHA:         r.HeadSHA,
+				CommitMessage:   r.CommitMessage,
+				CommitAuthor:    r.CommitAuthor,
+				Committer:       r.Committer,
+				StartedAt:       r.StartedAt,
+				CompletedAt:     r.UpdatedAt,
+				Age:             r.Age,
+				DurationSeconds: r.DurationSeconds,
+				WorkflowSource:  r.WorkflowSource,
+			})
+		}
+		return result
+	default: // compact
+		result := make([]*github.WorkflowRunCompact, 0, len(runs))
+		for _, r := range runs {
+			result = append(result, &github.WorkflowRunCompact{
+				WorkflowRunMinimal: github.WorkflowRunMinimal{
+					ID:              r.ID,
+					Name:            r.Name,
+					Status:          r.Status,
+					Conclusion:      r.Conclusion,
+					CreatedAt:       r.CreatedAt,
+					Age:             r.Age,
+					DurationSeconds: r.DurationSeconds,
+				},
+				Branch:       r.Branch,
+				SHA:          r.HeadSHA,
+				Commit:       github.CommitTitle(r.CommitMessage),
+				CommitAuthor: r.CommitAuthor,
+				Event:        r.Event,
+				Actor:        r.Actor,
+				URL:          r.URL,
+
+				WorkflowSource: r.WorkflowSource,
+			})
+		}
+		return result
+	}
+}
+
+func (s *MCPServer) getRun(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
+	}
+
+	element := "info"
+	if e, ok := args["element"].(string); ok {
+		element = strings.TrimSpace(strings.ToLower(e))
+	}
+
+	if !isValidRunElement(element) {
+		return errorResult(fmt.Sprintf("invalid element %q. Allowed values: %s", element, strings.Join(validRunElements, ", "))), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Getting run %d (element: %s)", runID, element)
+
+	switch element {
+	case "jobs":
+		return s.getRunJobs(ctx, client, owner, repo, runID, args)
+	case "logs":
+		return s.getRunLogs(ctx, client, owner, repo, runID, args)
+	case "log_files":
+		return s.getLogFiles(ctx, client, owner, repo, runID, args)
+	case "log_sections":
+		return s.getLogSections(ctx, client, owner, repo, runID, args)
+	case "artifacts":
+		return s.getRunArtifacts(ctx, client, owner, repo, runID, args)
+	case "artifact_content":
+		return s.getArtifactContent(ctx, client, owner, repo, args)
+	default: // info
+		return s.getRunInfo(ctx, client, owner, repo, runID, args)
+	}
+}
+
+func (s *MCPServer) getRunInfo(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	run, err := client.GetWorkflowRun(ctx, runID)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("Run ID %d not found", runID), owner, repo), nil
+	}
+
+	format := s.getFormat()
+	if f, ok := args["format"].(string); ok {
+		format = f
+	}
+
+	switch format {
+	case "full":
+		result := &github.WorkflowRunFull{
+			ID:              run.ID,
+			Name:            run.Name,
+			Status:          run.Status,
+			Conclusion:      run.Conclusion,
+			Branch:          run.Branch,
+			Event:           run.Event,
+			Actor:           run.Actor,
+			CreatedAt:       run.CreatedAt,
+			UpdatedAt:       run.UpdatedAt,
+			URL:             run.URL,
+			RunNumber:       run.RunNumber,
+			WorkflowID:      run.WorkflowID,
+			HeadSHA:         run.HeadSHA,
+			CommitMessage:   run.CommitMessage,
+			CommitAuthor:    run.CommitAuthor,
+			Committer:       run.Committer,
+			StartedAt:       run.StartedAt,
+			CompletedAt:     run.UpdatedAt,
+			Age:             run.Age,
+			DurationSeconds: run.DurationSeconds,
+			WorkflowSource:  run.WorkflowSource,
+		}
+		return jsonResult(result)
+	default: // compact
+		result := &github.WorkflowRunCompact{
+			WorkflowRunMinimal: github.WorkflowRunMinimal{
+				ID:              run.ID,
+				Name:            run.Name,
+				Status:          run.Status,
+				Conclusion:      run.Conclusion,
+				CreatedAt:       run.CreatedAt,
+				Age:             run.Age,
+				DurationSeconds: run.DurationSeconds,
+			},
+			Branch:       run.Branch,
+			SHA:          run.HeadSHA,
+			Commit:       github.CommitTitle(run.CommitMessage),
+			CommitAuthor: run.CommitAuthor,
+			Event:        run.Event,
+			Actor:        run.Actor,
+			URL:          run.URL,
+
+			WorkflowSource: run.WorkflowSource,
+		}
+		return jsonResult(result)
+	}
+}
+
+func (s *MCPServer) getRunJobs(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	filter := ""
+	if f, ok := args["filter"].(string); ok {
+		filter = f
+	}
+
+	attemptNumber := 0
+	if an, ok := args["attempt_number"].(float64); ok && an > 0 {
+		attemptNumber = int(an)
+	}
+
+	jobs, err := client.GetWorkflowJobs(ctx, runID, filter, attemptNumber)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get jobs for run %d", runID), owner, repo), nil
+	}
+
+	format := s.getFormat()
+	if f, ok := args["format"].(string); ok {
+		format = f
+	}
+
+	if format == "full" {
+		return jsonResultPretty(jobs)
+	}
+	return jsonResult(jobs)
+}
+
+func (s *MCPServer) getRunLogs(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	// Check if getting logs for a specific job
+	if jobID, ok := extractJobID(args); ok {
+		return s.getRunJobLogs(ctx, client, owner, repo, runID, jobID, args)
+	}
+
+	// Get workflow run logs
+	head := 0
+	if h, ok := args["head"].(float64); ok && h > 0 {
+		head = int(h)
+	}
+
+	tail := 0
+	if t, ok := args["tail"].(float64); ok && t > 0 {
+		tail = int(t)
+	}
+
+	offset := 0
+	if o, ok := args["offset"].(float64); ok && o > 0 {
+		offset = int(o)
+	}
+
+	// Support both old 'filter' and new 'search' parameter
+	search := ""
+	if s, ok := args["search"].(string); ok {
+		search = s
+	} else if f, ok := args["filter"].(string); ok {
+		search = f // Support old parameter name for backwards compatibility
+	}
+
+	searchRegex := ""
+	if sr, ok := args["search_regex"].(string); ok {
+		searchRegex = sr
+	} else if fr, ok := args["filter_regex"].(string); ok {
+		searchRegex = fr // Support old parameter name
+	}
+
+	if search != "" && searchRegex != "" {
+		return errorResult("search and search_regex are mutually exclusive"), nil
+	}
+
+	contextLines := 0
+	if c, ok := args["context"].(float64); ok && c > 0 {
+		contextLines = int(c)
+	}
+
+	noHeaders := false
+	if nh, ok := args["no_headers"].(bool); ok {
+		noHeaders = nh
+	}
+
+	as, errResult := logOutputFromArgs(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if as == logOutputJSONL {
+		noHeaders = false // headers carry the job and step names
+	}
+
+	// Get file pattern for filtering log files
+	filePattern := ""
+	if fp, ok := args["file_pattern"].(string); ok {
+		filePattern = fp
+	}
+
+	filterOpts := &github.LogFilterOptions{
+		Filter:       search,
+		FilterRegex:  searchRegex,
+		ContextLines: contextLines,
+	}
+
+	// Check if section extraction is requested
+	section := ""
+	if sec, ok := args["section"].(string); ok {
+		section = sec
+	}
+
+	var logs string
+	var err error
+
+	if section != "" {
+		// Extract specific section
+		logs, err = client.GetLogSection(ctx, runID, 0, section, head, tail, offset, filterOpts)
+	} else {
+		// Get all logs with optional filtering
+		logs, err = client.GetWorkflowLogsWithPattern(ctx, runID, head, tail, offset, noHeaders, filePattern, filterOpts)
+	}
+
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get logs for run %d", runID), owner, repo), nil
+	}
+
+	logs, masked := s.maskSecrets(logs)
+	if summarize, _ := args["summarize"].(bool); summarize {
+		return withMaskedCount(s.summarizeLogResult(ctx, fmt.Sprintf("workflow run %d", runID), logs), masked), nil
+	}
+	callerLimited := head > 0 || tail > 0 || search != "" || searchRegex != "" || section != ""
+	if as == logOutputJSONL {
+		return withMaskedCount(jsonlLogResult(github.ParseLogEntries(logs, ""), s.getLogLines(), callerLimited), masked), nil
+	}
+	return withMaskedCount(truncateLogResult(logs, s.getLogLines(), callerLimited), masked), nil
+}
+
+func (s *MCPServer) getRunJobLogs(ctx context.Context, client *github.Client, owner, repo string, runID, jobID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	head := 0
+	if h, ok := args["head"].(float64); ok && h > 0 {
+		head = int(h)
+	}
+
+	tail := 0
+	if t, ok := args["tail"].(float64); ok && t > 0 {
+		tail = int(t)
+	}
+
+	offset := 0
+	if o, ok := args["offset"].(float64); ok && o > 0 {
+		offset = int(o)
+	}
+
+	search := ""
+	if s, ok := args["search"].(string); ok {
+		search = s
+	} else if f, ok := args["filter"].(string); ok {
+		search = f
+	}
+
+	searchRegex := ""
+	if sr, ok := args["search_regex"].(string); ok {
+		searchRegex = sr
+	} else if fr, ok := args["filter_regex"].(string); ok {
+		searchRegex = fr
+	}
+
+	if search != "" && searchRegex != "" {
+		return errorResult("search and search_regex are mutually exclusive"), nil
+	}
+
+	contextLines := 0
+	if c, ok := args["context"].(float64); ok && c > 0 {
+		contextLines = int(c)
+	}
+
+	noHeaders := false
+	if nh, ok := args["no_headers"].(bool); ok {
+		noHeaders = nh
+	}
+
+	as, errResult := logOutputFromArgs(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if as == logOutputJSONL {
+		noHeaders = false
+	}
+
+	filterOpts := &github.LogFilterOptions{
+		Filter:       search,
+		FilterRegex:  searchRegex,
+		ContextLines: contextLines,
+	}
+
+	section := ""
+	if sec, ok := args["section"].(string); ok {
+		section = sec
+	}
+
+	var logs string
+	var err error
+
+	if section != "" {
+		logs, err = client.GetLogSection(ctx, 0, jobID, section, head, tail, offset, filterOpts)
+	} else {
+		logs, err = client.GetWorkflowJobLogs(ctx, jobID, head, tail, offset, noHeaders, filterOpts)
+	}
+
+	if err != nil && runID > 0 {
+		if section == "" {
+			logs, err = client.GetWorkflowJobLogsFromRunArchive(ctx, runID, jobID, head, tail, offset, noHeaders, filterOpts)
+		}
+	}
+
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get logs for job %d", jobID), owner, repo), nil
+	}
+
+	logs, masked := s.maskSecrets(logs)
+	if summarize, _ := args["summarize"].(bool); summarize {
+		return withMaskedCount(s.summarizeLogResult(ctx, fmt.Sprintf("job %s of workflow run %d", s.jobName(ctx, client, runID, jobID), runID), logs), masked), nil
+	}
+	callerLimited := head > 0 || tail > 0 || search != "" || searchRegex != "" || section != ""
+	if as == logOutputJSONL {
+		return withMaskedCount(jsonlLogResult(github.ParseLogEntries(logs, s.jobName(ctx, client, runID, jobID)), s.getLogLines(), callerLimited), masked), nil
+	}
+	return withMaskedCount(truncateLogResult(logs, s.getLogLines(), callerLimited), masked), nil
+}
+
+// jobName looks up the name of a job in a run, falling back to its ID when the lookup fails.
+func (s *MCPServer) jobName(ctx context.Context, client *github.Client, runID, jobID int64) string {
+	if runID > 0 {
+		jobs, err := client.GetWorkflowJobs(ctx, runID, "", 0)
+		if err != nil {
+			s.log.WithContext(ctx).Debugf("Could not list jobs for run %d: %v", runID, err)
+		}
+		for _, job := range jobs {
+			if job.ID == jobID {
+				return job.Name
+			}
+		}
+	}
+	return strconv.FormatInt(jobID, 10)
+}
+
+func (s *MCPServer) getRunArtifacts(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	artifacts, err := client.GetWorkflowRunArtifacts(ctx, runID)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get artifacts for run %d", runID), owner, repo), nil
+	}
+
+	format := s.getFormat()
+	if f, ok := args["format"].(string); ok {
+		format = f
+	}
+
+	if format == "full" {
+		return jsonResultPretty(artifacts)
+	}
+	return jsonResult(artifacts)
+}
+
+func (s *MCPServer) getArtifactContent(ctx context.Context, client *github.Client, owner, repo string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	artifactIDFloat, ok := args["artifact_id"].(float64)
+	if !ok {
+		return errorResult("artifact_id is required for element=artifact_content"), nil
+	}
+	artifactID := int64(artifactIDFloat)
+
+	filePattern := ""
+	if fp, ok := args["file_pattern"].(string); ok {
+		filePattern = fp
+	}
+
+	maxFileSize := int64(1024 * 1024) // 1MB default
+	if mfs, ok := args["max_file_size"].(float64); ok && mfs > 0 {
+		maxFileSize = int64(mfs)
+	}
+
+	s.log.WithContext(ctx).Infof("Getting artifact content %d (pattern: %s, max_size: %d)", artifactID, filePattern, maxFileSize)
+
+	content, err := client.GetArtifactContent(ctx, artifactID, filePattern, maxFileSize)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get artifact content %d", artifactID), owner, repo), nil
+	}
+
+	return jsonResultPretty(content)
+}
+
+func (s *MCPServer) getLogFiles(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	logFiles, err := client.GetWorkflowLogFiles(ctx, runID)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get log files for run %d", runID), owner, repo), nil
+	}
+
+	// Apply file pattern filter if specified
+	if pattern, ok := args["file_pattern"].(string); ok && pattern != "" {
+		filtered := make([]*github.LogFileInfo, 0)
+		for _, lf := range logFiles {
+			matched, err := filepath.Match(pattern, lf.Path)
+			if err != nil {
+				return errorResult(fmt.Sprintf("invalid file pattern %q: %v", pattern, err)), nil
+			}
+			if matched {
+				filtered = append(filtered, lf)
+			}
+		}
+		logFiles = filtered
+	}
+
+	format := s.getFormat()
+	if f, ok := args["format"].(string); ok {
+		format = f
+	}
+
+	if format == "full" {
+		return jsonResultPretty(logFiles)
+	}
+	return jsonResult(logFiles)
+}
+
+func (s *MCPServer) getLogSections(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	// Check if getting sections for a specific job
+	jobID, _ := extractJobID(args)
+
+	s.log.WithContext(ctx).Infof("Getting log sections for run %d (job_id: %d)", runID, jobID)
+
+	sections, err := client.ListLogSections(ctx, runID, jobID)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get log sections for run %d", runID), owner, repo), nil
+	}
+
+	format := s.getFormat()
+	if f, ok := args["format"].(string); ok {
+		format = f
+	}
+
+	if format == "full" {
+		return jsonResultPretty(sections)
+	}
+	return jsonResult(sections)
+}
+
+func (s *MCPServer) analyzeTiming(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	workflow := ""
+	if value, ok := args["workflow"].(string); ok {
+		workflow = strings.TrimSpace(value)
+	}
+
+	runID := int64(0)
+	if value, ok := args["run_id"].(float64); ok && value > 0 {
+		runID = int64(value)
+	}
+
+	jobName := ""
+	if value, ok := args["job_name"].(string); ok {
+		jobName = strings.TrimSpace(value)
+	}
+
+	stepName := ""
+	if value, ok := args["step_name"].(string); ok {
+		stepName = strings.TrimSpace(value)
+	}
+
+	if stepName != "" && jobName == "" {
+		return errorResult("job_name is required when step_name is provided"), nil
+	}
+	if workflow == "" && runID == 0 {
+		return errorResult("workflow or run_id is required"), nil
+	}
+
+	branch := ""
+	if value, ok := args["branch"].(string); ok && value != "" {
+		branch = strings.TrimSpace(value)
+	}
+
+	conclusion := ""
+	if value, ok := args["conclusion"].(string); ok {
+		conclusion = strings.TrimSpace(value)
+	}
+
+	limit := 10
+	if value, ok := args["limit"].(float64); ok && value > 0 {
+		limit = int(value)
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	s.log.WithContext(ctx).Infof("Analyzing timing for %s/%s (workflow=%q, run_id=%d, job=%q, step=%q, limit=%d)", owner, repo, workflow, runID, jobName, stepName, limit)
+
+	analysis, err := client.AnalyzeTiming(ctx, &github.TimingAnalysisOptions{
+		Workflow:   workflow,
+		RunID:      runID,
+		Branch:     branch,
+		JobName:    jobName,
+		StepName:   stepName,
+		Limit:      limit,
+		Conclusion: conclusion,
+	})
+	if err != nil {
+		return s.apiErrorResult(err, "failed to analyze timing", owner, repo), nil
+	}
+
+	return jsonResultPretty(analysis)
+}
+
+func (s *MCPServer) getCheckStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	ref := ""
+	if r, ok := args["ref"].(string); ok && r != "" {
+		ref = strings.TrimSpace(r)
+	} else if owner == s.config.RepoOwner && repo == s.config.RepoName {
+		// Auto-detect ref only for the configured repo (not cross-repo overrides)
+		if commit, err := github.GetLastCommit(); err == nil {
+			ref = commit.SHA
+		} else {
+			return errorResult("could not determine ref - please specify explicitly"), nil
+		}
+	} else {
+		return errorResult("ref is required when querying a different repository"), nil
+	}
+
+	opts := &github.GetCheckRunsOptions{}
+
+	if checkName, ok := args["check_name"].(string); ok && checkName != "" {
+		opts.CheckName = checkName
+	}
+
+	if status, ok := args["status"].(string); ok && status != "" {
+		opts.Status = status
+	}
+
+	filterMode := "latest"
+	if filter, ok := args["filter"].(string); ok && filter != "" {
+		filterMode = strings.TrimSpace(strings.ToLower(filter))
+	}
+	if filterMode != "latest" && filterMode != "all" {
+		return errorResult(fmt.Sprintf("invalid filter %q. Allowed values: latest, all", filterMode)), nil
+	}
+	opts.Filter = filterMode
+
+	format := "summary"
+	if f, ok := args["format"].(string); ok && f != "" {
+		format = strings.TrimSpace(strings.ToLower(f))
+	}
+	if format != "summary" && format != "compact" && format != "full" {
+		return errorResult(fmt.Sprintf("invalid format %q. Allowed values: summary, compact, full", format)), nil
+	}
+
+	status, err := client.GetCheckRunsForRef(ctx, ref, opts)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to get check status", owner, repo), nil
+	}
+
+	switch format {
+	case "full":
+		return jsonResult(status)
+	case "compact":
+		return jsonResult(status)
+	default: // summary
+		return textResult(formatWorkflowStatusSummary(ref, status, filterMode)), nil
+	}
+}
+
+func (s *MCPServer) waitForRun(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
+	}
+
+	timeoutMinutes := 30
+	if tm, ok := args["timeout_minutes"].(float64); ok && tm > 0 {
+		timeoutMinutes = int(tm)
+		if timeoutMinutes > 120 {
+			timeoutMinutes = 120
+		}
+	}
+
+	stopOnApproval, _ := args["stop_on_approval"].(bool)
+
+	s.log.WithContext(ctx).Infof("Waiting for run %d (timeout: %dm)", runID, timeoutMinutes)
+
+	result, err := client.WaitForRunWithOptions(ctx, runID, s.waitRunOptions(ctx, owner, repo, runID, timeoutMinutes, stopOnApproval))
+	if err != nil {
+		if result == nil || !result.TimeoutReached {
+			return s.apiErrorResult(err, "failed to wait for run", owner, repo), nil
+		}
+	}
+
+	return jsonResult(result)
+}
+
+// waitForWorkflowRunResult is the outcome of wait_for_workflow_run.
+type waitForWorkflowRunResult struct {
+	RunID          int64               `json:"run_id"`
+	Status         string              `json:"status"`
+	Conclusion     string              `json:"conclusion,omitempty"`
+	TimedOut       bool                `json:"timed_out"`
+	PollCount      int                 `json:"poll_count"`
+	ElapsedSeconds float64             `json:"elapsed_seconds"`
+	URL            string              `json:"url,omitempty"`
+	Run            *github.WorkflowRun `json:"run,omitempty"`
+}
+
+func (s *MCPServer) waitForWorkflowRun(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
+	}
+
+	pollInterval := 5
+	if p, ok := args["poll_interval_seconds"].(float64); ok && p > 0 {
+		pollInterval = min(int(p), 300)
+	}
+	timeout := 600
+	if t, ok := args["timeout_seconds"].(float64); ok && t > 0 {
+		timeout = min(int(t), 7200)
+	}
+
+	s.log.WithContext(ctx).Infof("Waiting for workflow run %d (poll every %ds, timeout: %ds)", runID, pollInterval, timeout)
+
+	wait, err := client.WaitForWorkflowRun(ctx, runID, pollInterval, timeout)
+	if err != nil && (wait == nil || !wait.TimedOut) {
+		return s.apiErrorResult(err, "failed to wait for workflow run", owner, repo), nil
+	}
+
+	result := &waitForWorkflowRunResult{
+		RunID:          runID,
+		TimedOut:       wait.TimedOut,
+		PollCount:      wait.PollCount,
+		ElapsedSeconds: wait.Elapsed.Seconds(),
+		Run:            wait.Run,
+	}
+	if wait.Run != nil {
+		result.Status, result.Conclusion, result.URL = wait.Run.Status, wait.Run.Conclusion, wait.Run.URL
+	}
+	return jsonResult(result)
+}
+
+// waitRunOptions returns options for waiting on a run that send a notification through the
+// configured webhook when the run starts waiting for an environment approval.
+func (s *MCPServer) waitRunOptions(ctx context.Context, owner, repo string, runID int64, timeoutMinutes int, stopOnApproval bool) github.WaitRunOptions {
+	return github.WaitRunOptions{
+		TimeoutMinutes: timeoutMinutes,
+		StopOnApproval: stopOnApproval,
+		OnApproval: func(approval *github.PendingApproval) {
+			s.notifier.notify(ctx, fmt.Sprintf("%s/%s run %d is %s", owner, repo, runID, approval.Message))
+		},
+	}
+}
+
+func (s *MCPServer) waitForCommitChecks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	ref := ""
+	if r, ok := args["ref"].(string); ok && r != "" {
+		ref = strings.TrimSpace(r)
+	}
+
+	timeoutMinutes := 30
+	if tm, ok := args["timeout_minutes"].(float64); ok && tm > 0 {
+		timeoutMinutes = int(tm)
+		if timeoutMinutes > 120 {
+			timeoutMinutes = 120
+		}
+	}
+
+	s.log.WithContext(ctx).Infof("Waiting for checks on ref %s (timeout: %dm)", ref, timeoutMinutes)
+
+	result, err := client.WaitForCommitChecks(ctx, ref, timeoutMinutes)
+	if err != nil {
+		if result == nil || !result.TimeoutReached {
+			return s.apiErrorResult(err, "failed to wait for checks", owner, repo), nil
+		}
+	}
+
+	return jsonResult(result)
+}
+
+func (s *MCPServer) manageRun(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
+	}
+
+	actionStr, ok := args["action"].(string)
+	if !ok || actionStr == "" {
+		return errorResult("action is required (cancel, rerun, rerun_failed)"), nil
+	}
+
+	var action github.ManageRunAction
+	switch actionStr {
+	case "cancel":
+		action = github.ManageRunActionCancel
+	case "rerun":
+		action = github.ManageRunActionRerun
+	case "rerun_failed":
+		action = github.ManageRunActionRerunFailed
+	default:
+		return errorResult(fmt.Sprintf("unknown action: %s (must be cancel, rerun, or rerun_failed)", actionStr)), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Managing run %d on %s/%s: %s", runID, owner, repo, action)
+
+	result, err := client.ManageRun(ctx, runID, action)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to manage run", owner, repo), nil
+	}
+
+	if result.Status == "success" {
+		return textResult(result.Message), nil
+	}
+	return errorResult(result.Message), nil
+}
+
+func (s *MCPServer) getArtifact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	artifactIDFloat, ok := args["artifact_id"].(float64)
+	if !ok {
+		return errorResult("artifact_id is required"), nil
+	}
+	artifactID := int64(artifactIDFloat)
+
+	filePattern := ""
+	if fp, ok := args["file_pattern"].(string); ok {
+		filePattern = fp
+	}
+
+	maxFileSize := int64(1024 * 1024) // 1MB default
+	if mfs, ok := args["max_file_size"].(float64); ok && mfs > 0 {
+		maxFileSize = int64(mfs)
+	}
+
+	s.log.WithContext(ctx).Infof("Getting artifact %d (pattern: %s, max_size: %d)", artifactID, filePattern, maxFileSize)
+
+	content, err := client.GetArtifactContent(ctx, artifactID, filePattern, maxFileSize)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get artifact %d", artifactID), owner, repo), nil
+	}
+
+	return jsonResultPretty(content)
+}
+
+func (s *MCPServer) downloadArtifact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	artifactIDFloat, ok := args["artifact_id"].(float64)
+	if !ok {
+		return errorResult("artifact_id is required"), nil
+	}
+	artifactID := int64(artifactIDFloat)
+
+	if inline, _ := args["inline"].(bool); inline {
+		if _, ok := args["output_path"].(string); ok {
+			return errorResult("output_path cannot be combined with inline"), nil
+		}
+		return s.getArtifact(ctx, request)
+	}
+
+	outputPath := ""
+	if op, ok := args["output_path"].(string); ok {
+		// Reject absolute paths and path components that escape the current directory
+		if filepath.IsAbs(op) || strings.Contains(op, "..") {
+			return errorResult("output_path must be a relative path without '..' components"), nil
+		}
+		outputPath = op
+	}
+
+	s.log.WithContext(ctx).Infof("Downloading artifact %d to %s", artifactID, outputPath)
+
+	result, err := client.DownloadArtifact(ctx, artifactID, outputPath)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to download artifact %d", artifactID), owner, repo), nil
+	}
+
+	return jsonResultPretty(result)
+}
+
+// maskDiagnosis masks secrets in the log lines of a failure diagnosis, unless masking is
+// disabled.
+func (s *MCPServer) maskDiagnosis(diagnosis *github.FailureDiagnosis) {
+	if s.config.DisableSecretMasking || diagnosis == nil {
+		return
+	}
+	for _, job := range diagnosis.FailedJobs {
+		for i, line := range job.ErrorLines {
+			job.ErrorLines[i], _ = github.MaskSecrets(line)
+		}
+		for i, line := range job.ContainerErrors {
+			job.ContainerErrors[i], _ = github.MaskSecrets(line)
+		}
+	}
+}
+
+func (s *MCPServer) diagnoseFailure(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	// Check flakiness defaults to true
+	checkFlakiness := true
+	if cf, ok := args["check_flakiness"].(bool); ok {
+		checkFlakiness = cf
+	}
+
+	maxErrorLines := 50
+	if mel, ok := args["max_error_lines"].(float64); ok && mel > 0 {
+		maxErrorLines = int(mel)
+	}
+
+	format, _ := args["format"].(string)
+	if format != "" && format != "json" && format != "markdown" {
+		return errorResult("format must be json or markdown"), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		var errResult *mcp.CallToolResult
+		runID, errResult = s.latestFailedRunID(ctx, client, owner, repo)
+		if errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	s.log.WithContext(ctx).Infof("Diagnosing failure for run %d on %s/%s", runID, owner, repo)
+
+	diagnosis, err := client.DiagnoseFailure(ctx, runID, checkFlakiness, maxErrorLines)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to diagnose run %d", runID), owner, repo), nil
+	}
+	s.maskDiagnosis(diagnosis)
+
+	if format == "markdown" {
+		return textResult(github.RenderDiagnosisMarkdown(diagnosis)), nil
+	}
+	return jsonResultPretty(diagnosis)
+}
+
+// latestFailedRunID finds the latest failed run, scoped to the current branch when the
+// target repository is the configured one.
+func (s *MCPServer) latestFailedRunID(ctx context.Context, client *github.Client, owner, repo string) (int64, *mcp.CallToolResult) {
+	branch := ""
+	if owner == s.config.RepoOwner && repo == s.config.RepoName {
+		if detectedBranch, err := github.GetCurrentBranch(); err == nil {
+			branch = detectedBranch
+		}
+	}
+
+	opts := &github.ListRunsOptions{
+		Per_page:   5,
+		Status:     "completed",
+		Conclusion: "failure",
+		Branch:     branch,
+	}
+
+	runs, err := client.ListRepositoryWorkflowRunsWithOptions(ctx, opts)
+	if err != nil {
+		return 0, s.apiErrorResult(err, "failed to find failed runs", owner, repo)
+	}
+
+	if len(runs) == 0 {
+		msg := "No failed runs found"
+		if branch != "" {
+			msg += fmt.Sprintf(" on branch %s", branch)
+		}
+		return 0, errorResult(msg)
+	}
+
+	return runs[0].ID, nil
+}
+
+func (s *MCPServer) compareWithLastGreen(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		var errResult *mcp.CallToolResult
+		runID, errResult = s.latestFailedRunID(ctx, client, owner, repo)
+		if errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	s.log.WithContext(ctx).Infof("Comparing run %d with last successful run on %s/%s", runID, owner, repo)
+
+	comparison, err := client.CompareWithLastGreen(ctx, runID)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to compare run %d with last successful run", runID), owner, repo), nil
+	}
+
+	return jsonResultPretty(comparison)
+}
+
+func (s *MCPServer) getRetentionPolicy(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Getting retention policy for %s/%s", owner, repo)
+
+	policy, err := client.GetRetentionPolicy(ctx)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to get retention policy", owner, repo), nil
+	}
+
+	return jsonResult(policy)
+}
+
+func (s *MCPServer) setRetentionPolicy(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	days, ok := args["days"].(float64)
+	if !ok {
+		return errorResult("days is required"), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Setting retention policy for %s/%s to %d days", owner, repo, int(days))
+
+	policy, err := client.SetRetentionPolicy(ctx, int(days))
+	if err != nil {
+		return s.apiErrorResult(err, "failed to set retention policy", owner, repo), nil
+	}
+
+	return jsonResult(policy)
+}
+
+func (s *MCPServer) getArtifactExpiry(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Getting artifact expiry for run %d on %s/%s", runID, owner, repo)
+
+	expiry, err := client.GetRunArtifactExpiry(ctx, runID)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get artifact expiry for run %d", runID), owner, repo), nil
+	}
+
+	return jsonResultPretty(expiry)
+}
+
+func (s *MCPServer) getOIDCConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	limit := s.getLimit()
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	s.log.WithContext(ctx).Infof("Getting OIDC configuration for %s/%s", owner, repo)
+
+	cfg, err := client.GetOIDCConfiguration(ctx, limit)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to get OIDC configuration", owner, repo), nil
+	}
+
+	return jsonResultPretty(cfg)
+}
+
+func (s *MCPServer) getBranchPolicies(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	branch, _ := args["branch"].(string)
+
+	s.log.WithContext(ctx).Infof("Getting branch policies for %s/%s (branch: %s)", owner, repo, branch)
+
+	report, err := client.GetBranchPolicies(ctx, branch)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to get branch policies", owner, repo), nil
+	}
+
+	return jsonResultPretty(report)
+}
+
+// dispatchOptionsFromArgs builds workflow dispatch options from tool arguments.
+func dispatchOptionsFromArgs(args map[string]interface{}) (github.DispatchOptions, error) {
+	opts := github.DispatchOptions{}
+
+	workflow, ok := args["workflow"].(string)
+	if !ok || strings.TrimSpace(workflow) == "" {
+		return opts, fmt.Errorf("workflow is required")
+	}
+	opts.Workflow = strings.TrimSpace(workflow)
+
+	if ref, ok := args["ref"].(string); ok {
+		opts.Ref = strings.TrimSpace(ref)
+	}
+	if inputs, ok := args["inputs"].(map[string]interface{}); ok {
+		opts.Inputs = inputs
+	}
+	if name, ok := args["correlation_input"].(string); ok {
+		opts.CorrelationInput = strings.TrimSpace(name)
+	}
+	opts.EnableIfDisabled, _ = args["enable_if_disabled"].(bool)
+
+	return opts, nil
+}
+
+// dispatchWorkflow dispatches a workflow through the duplicate-dispatch guard.
+// Unless force is set, an identical dispatch within the configured window is
+// refused (or, in "warn" mode, dispatched with a warning attached). With
+// reuseLastInputs, inputs missing from opts are taken from the workflow's last
+// dispatch; the inputs of every successful dispatch are remembered.
+func (s *MCPServer) dispatchWorkflow(ctx context.Context, client *github.Client, owner, repo string, opts github.DispatchOptions, force, reuseLastInputs bool) (*github.DispatchResult, *mcp.CallToolResult) {
+	// The workflow and ref are resolved once, so the same dispatch gets the same dedup key
+	// whether it names the workflow by name, path, or ID, and whether it names the default ref.
+	target, err := client.ResolveDispatchTarget(ctx, opts)
+	if err != nil {
+		return nil, s.dispatchErrorResult(err, owner, repo)
+	}
+	opts.Target = target
+
+	var reused []string
+	var reuseWarning string
+	if reuseLastInputs {
+		if last := s.lastInputs.get(dispatchInputsKey(owner, repo, target.WorkflowID)); last != nil {
+			opts.Inputs, reused = mergeLastInputs(opts.Inputs, last)
+		} else {
+			reuseWarning = fmt.Sprintf("no earlier dispatch of %s is recorded; reuse_last_inputs had no effect", opts.Workflow)
+		}
+	}
+
+	opts.LocalDir = s.localCheckoutDir(owner, repo)
+
+	key := dispatchKey(owner, repo, target, opts.Inputs)
+	var duplicate string
+	if prev, ok := s.dispatches.reserve(key); !ok {
+		duplicate = s.dispatches.describeDuplicate(prev, opts.Workflow, target.Ref)
+		if !force && s.config.DispatchDedupMode != "warn" {
+			return nil, errorResult(duplicate + ". Pass force=true to dispatch anyway.")
+		}
+		s.log.WithContext(ctx).Warnf("Duplicate dispatch on %s/%s: %s", owner, repo, duplicate)
+	}
+
+	result, err := client.DispatchWorkflow(ctx, opts)
+	if err != nil {
+		if duplicate == "" {
+			s.dispatches.release(key)
+		}
+		return nil, s.dispatchErrorResult(err, owner, repo)
+	}
+
+	if duplicate == "" {
+		s.dispatches.complete(key, result.RunID)
+	} else if !force {
+		result.Warnings = append(result.Warnings, duplicate)
+	}
+	s.lastInputs.remember(dispatchInputsKey(owner, repo, result.WorkflowID), opts)
+	result.ReusedInputs = reused
+	if reuseWarning != "" {
+		result.Warnings = append(result.Warnings, reuseWarning)
+	}
+	return result, nil
+}
+
+// dispatchErrorResult returns the error result of a failed dispatch.
+func (s *MCPServer) dispatchErrorResult(err error, owner, repo string) *mcp.CallToolResult {
+	if errors.Is(err, github.ErrWorkflowDisabled) {
+		return s.apiErrorResult(err, "failed to trigger workflow (pass enable_if_disabled=true to enable it and dispatch)", owner, repo)
+	}
+	return s.apiErrorResult(err, "failed to trigger workflow", owner, repo)
+}
+
+func (s *MCPServer) triggerWorkflow(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	opts, err := dispatchOptionsFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	force, _ := args["force"].(bool)
+	reuse, _ := args["reuse_last_inputs"].(bool)
+
+	s.log.WithContext(ctx).Infof("Triggering workflow %s on %s/%s (ref: %s)", opts.Workflow, owner, repo, opts.Ref)
+
+	result, errResult := s.dispatchWorkflow(ctx, client, owner, repo, opts, force, reuse)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	return jsonResultPretty(result)
+}
+
+// triggerAndWaitResult combines the dispatch details with the outcome of waiting for the run,
+// and the diagnosis of the run when it failed.
+type triggerAndWaitResult struct {
+	Dispatch *github.DispatchResult   `json:"dispatch"`
+	Wait     *github.WaitRunResult    `json:"wait,omitempty"`
+	Failure  *github.FailureDiagnosis `json:"failure,omitempty"`
+	// FailureError says why the failure could not be diagnosed.
+	FailureError string `json:"failure_error,omitempty"`
+}
+
+// failedConclusions are the run conclusions trigger_and_wait diagnoses.
+var failedConclusions = map[string]bool{"failure": true, "timed_out": true, "startup_failure": true}
+
+func (s *MCPServer) triggerAndWait(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	opts, err := dispatchOptionsFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	timeoutMinutes := 30
+	if tm, ok := args["timeout_minutes"].(float64); ok && tm > 0 {
+		timeoutMinutes = int(tm)
+	}
+
+	force, _ := args["force"].(bool)
+	reuse, _ := args["reuse_last_inputs"].(bool)
+
+	s.log.WithContext(ctx).Infof("Triggering workflow %s on %s/%s (ref: %s) and waiting up to %dm", opts.Workflow, owner, repo, opts.Ref, timeoutMinutes)
+
+	dispatch, errResult := s.dispatchWorkflow(ctx, client, owner, repo, opts, force, reuse)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	result := &triggerAndWaitResult{Dispatch: dispatch}
+	if dispatch.RunID == 0 {
+		return jsonResultPretty(result)
+	}
+
+	wait, err := client.WaitForRunWithOptions(ctx, dispatch.RunID, s.waitRunOptions(ctx, owner, repo, dispatch.RunID, timeoutMinutes, false))
+	if err != nil && wait == nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to wait for run %d", dispatch.RunID), owner, repo), nil
+	}
+	result.Wait = wait
+
+	if wait.Status == "completed" && failedConclusions[wait.Conclusion] {
+		maxErrorLines := 20
+		if mel, ok := args["max_error_lines"].(float64); ok && mel > 0 {
+			maxErrorLines = int(mel)
+		}
+		if result.Failure, err = client.DiagnoseFailure(ctx, dispatch.RunID, false, maxErrorLines); err != nil {
+			s.log.WithContext(ctx).Warnf("Failed to diagnose run %d: %v", dispatch.RunID, err)
+			result.FailureError = err.Error()
+		}
+		s.maskDiagnosis(result.Failure)
+	}
+
+	return jsonResultPretty(result)
+}
+
+func (s *MCPServer) getNewFailures(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	opts := &github.ListRunsOptions{Per_page: 100}
+	workflow, _ := args["workflow"].(string)
+	if workflow != "" {
+		workflowID, _, err := client.ResolveWorkflowID(ctx, workflow)
+		if err != nil {
+			return s.apiErrorResult(err, fmt.Sprintf("failed to resolve workflow %s", workflow), owner, repo), nil
+		}
+		opts.WorkflowID = &workflowID
+	}
+	if branch, ok := args["branch"].(string); ok {
+		opts.Branch = branch
+	}
+
+	key := fmt.Sprintf("%s/%s|%s|%s", owner, repo, workflow, opts.Branch)
+
+	// The lock is not held while listing runs, so a slow listing does not block other calls.
+	s.cursorMu.Lock()
+	stored := s.failureCursors[key]
+	s.cursorMu.Unlock()
+
+	cursor := stored
+	if reset, _ := args["reset"].(bool); reset {
+		cursor = nil
+	}
+	if since, ok := args["since_run_id"].(float64); ok && since > 0 {
+		cursor = &github.FailureCursor{LastRunID: int64(since)}
+	}
+
+	s.log.WithContext(ctx).Infof("Checking for new failures on %s/%s (workflow: %s, branch: %s)", owner, repo, workflow, opts.Branch)
+
+	report, err := client.GetNewFailures(ctx, cursor, opts)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to check for new failures", owner, repo), nil
+	}
+	s.advanceFailureCursor(ctx, key, stored, report.Cursor)
+
+	return jsonResultPretty(report)
+}
+
+// advanceFailureCursor stores next as the failure cursor of key and persists the cursors. When
+// another call replaced the cursor since it was read as prev, next is stored only if it is
+// further along, so a slow check cannot move the cursor back.
+func (s *MCPServer) advanceFailureCursor(ctx context.Context, key string, prev, next *github.FailureCursor) {
+	s.cursorMu.Lock()
+	defer s.cursorMu.Unlock()
+
+	if current := s.failureCursors[key]; current != prev && current != nil && next.LastRunID <= current.LastRunID {
+		return
+	}
+	s.failureCursors[key] = next
+	if s.state != nil {
+		if err := s.state.Save(failureCursorsState, failureCursorsSchema, s.failureCursors); err != nil {
+			s.log.WithContext(ctx).Warnf("Failed to persist failure cursors: %v", err)
+		}
+	}
+}
+
+func (s *MCPServer) getPRChecks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	prNumber, ok := args["pr_number"].(float64)
+	if !ok || prNumber <= 0 {
+		return errorResult("pr_number is required"), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Getting checks for PR #%d on %s/%s", int(prNumber), owner, repo)
+
+	report, err := client.GetPRChecks(ctx, int(prNumber))
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get checks for PR #%d", int(prNumber)), owner, repo), nil
+	}
+
+	if requiredOnly, _ := args["required_only"].(bool); requiredOnly {
+		checks := make([]*github.PRCheck, 0, len(report.Checks))
+		for _, check := range report.Checks {
+			if check.Required {
+				checks = append(checks, check)
+			}
+		}
+		report.Checks = checks
+	}
+
+	return jsonResultPretty(report)
+}
+
+func (s *MCPServer) getEnvironmentStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	environment, _ := args["environment"].(string)
+	limit := 20
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	s.log.WithContext(ctx).Infof("Getting environment status for %s/%s (environment: %s)", owner, repo, environment)
+
+	envs, err := client.GetEnvironmentStatus(ctx, environment, limit)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to get environment status", owner, repo), nil
+	}
+
+	return jsonResultPretty(envs)
+}
+
+func (s *MCPServer) listEnvironments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Listing environments for %s/%s", owner, repo)
+
+	envs, err := client.ListEnvironments(ctx)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to list environments", owner, repo), nil
+	}
+	if envs == nil {
+		envs = []*github.EnvironmentProtection{}
+	}
+
+	return jsonResultPretty(envs)
+}
+
+func (s *MCPServer) generateWorkflow(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	stack, ok := args["stack"].(string)
+	if !ok || strings.TrimSpace(stack) == "" {
+		return errorResult("stack is required"), nil
+	}
+
+	opts := github.WorkflowTemplateOptions{Stack: stack}
+	opts.Name, _ = args["name"].(string)
+	opts.Version, _ = args["version"].(string)
+	opts.Command, _ = args["command"].(string)
+	opts.Image, _ = args["image"].(string)
+	opts.Registry, _ = args["registry"].(string)
+	opts.Platforms, _ = args["platforms"].(string)
+	if branches, ok := args["branches"].(string); ok {
+		for _, b := range strings.Split(branches, ",") {
+			if b = strings.TrimSpace(b); b != "" {
+				opts.Branches = append(opts.Branches, b)
+			}
+		}
+	}
+
+	s.log.WithContext(ctx).Infof("Generating %s workflow", stack)
+
+	wf, err := github.GenerateWorkflow(opts)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	return jsonResultPretty(wf)
+}
+
+func (s *MCPServer) validateWorkflowYAML(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	content, _ := args["yaml"].(string)
+	path, _ := args["path"].(string)
+	useActionlint := true
+	if v, ok := args["actionlint"].(bool); ok {
+		useActionlint = v
+	}
+
+	if includeCalled, _ := args["include_called"].(bool); includeCalled && content == "" && path != "" {
+		client, owner, repo, err := s.clientFromArgs(args)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		ref, _ := args["ref"].(string)
+
+		s.log.WithContext(ctx).Infof("Validating workflow %s and the workflows it calls on %s/%s", path, owner, repo)
+
+		graph, err := client.ResolveWorkflowCallGraph(ctx, path, ref, 0)
+		if err != nil {
+			return s.apiErrorResult(err, "failed to read workflow file", owner, repo), nil
+		}
+
+		// Called workflows run on the runners of the calling repository.
+		runners := s.selfHostedRunners(ctx, args)
+		result := &workflowValidationSet{Valid: true}
+		graph.Walk(func(node *github.WorkflowCallNode) {
+			if node.Content() == nil {
+				return
+			}
+			name := node.Path
+			if node != graph {
+				name = fmt.Sprintf("%s/%s", node.Repository, node.Path)
+				if node.Ref != "" {
+					name += "@" + node.Ref
+				}
+			}
+			validation := github.ValidateWorkflowYAMLWithRunners(ctx, node.Content(), name, useActionlint, runners)
+			result.Valid = result.Valid && validation.Valid
+			result.Workflows = append(result.Workflows, validation)
+		})
+		return jsonResultPretty(result)
+	}
+
+	if content == "" {
+		if path == "" {
+			return errorResult("either yaml or path is required"), nil
+		}
+		client, owner, repo, err := s.clientFromArgs(args)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		ref, _ := args["ref"].(string)
+		data, err := client.GetWorkflowFile(ctx, path, ref)
+		if err != nil {
+			return s.apiErrorResult(err, "failed to read workflow file", owner, repo), nil
+		}
+		content = string(data)
+	}
+
+	s.log.WithContext(ctx).Infof("Validating workflow YAML (path: %s, actionlint: %t)", path, useActionlint)
+
+	return jsonResultPretty(github.ValidateWorkflowYAMLWithRunners(ctx, []byte(content), path, useActionlint, s.selfHostedRunners(ctx, args)))
+}
+
+// selfHostedRunners returns the self-hosted runners of the repository args refer to, for
+// checking runs-on labels, or nil when there is no repository or they cannot be listed.
+func (s *MCPServer) selfHostedRunners(ctx context.Context, args map[string]interface{}) []*github.SelfHostedRunner {
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return nil
+	}
+	runners, err := client.ListSelfHostedRunners(ctx)
+	if err != nil {
+		s.log.WithContext(ctx).Debugf("Not checking runs-on labels against the self-hosted runners of %s/%s: %v", owner, repo, err)
+		return nil
+	}
+	return runners
+}
+
+func (s *MCPServer) getWorkflowCallGraph(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return errorResult("path is required"), nil
+	}
+	ref, _ := args["ref"].(string)
+	maxDepth := 0
+	if d, ok := args["max_depth"].(float64); ok && d > 0 {
+		maxDepth = int(d)
+	}
+
+	s.log.WithContext(ctx).Infof("Resolving workflow call graph for %s on %s/%s (ref: %s)", path, owner, repo, ref)
+
+	graph, err := client.ResolveWorkflowCallGraph(ctx, path, ref, maxDepth)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to resolve workflow call graph", owner, repo), nil
+	}
+
+	return jsonResultPretty(graph)
+}
+
+// workflowValidationSet is the result of validating a workflow and the reusable workflows it calls.
+type workflowValidationSet struct {
+	Valid     bool                         `json:"valid"`
+	Workflows []*github.WorkflowValidation `json:"workflows"`
+}
+
+// multiRepoStatus is the consolidated table returned by get_multi_repo_status.
+type multiRepoStatus struct {
+	Total        int                         `json:"total"`
+	Passing      int                         `json:"passing"`
+	Failing      int                         `json:"failing"`
+	Running      int                         `json:"running"`
+	NoRuns       int                         `json:"no_runs"`
+	Errors       int                         `json:"errors"`
+	Repositories []*github.RepoStatusSummary `json:"repositories"`
+}
+
+func (s *MCPServer) getMultiRepoStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	var names []string
+	if v, ok := args["repos"].(string); ok && strings.TrimSpace(v) != "" {
+		names = strings.Split(v, ",")
+	} else if len(s.config.Repos) > 0 {
+		names = s.config.Repos
+	} else if s.config.RepoOwner != "" && s.config.RepoName != "" {
+		names = []string{s.config.RepoOwner + "/" + s.config.RepoName}
+	}
+	if len(names) == 0 {
+		return errorResult("no repositories given: pass repos or set repos in the configuration"), nil
+	}
+
+	var clients []*github.Client
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		host, owner, repo, err := github.ParseRepoRef(name)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		client, err := s.clientForHost(host, owner, repo)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		clients = append(clients, client)
+	}
+
+	branch, _ := args["branch"].(string)
+	concurrency := 0
+	if c, ok := args["concurrency"].(float64); ok && c > 0 {
+		concurrency = int(c)
+	}
+
+	s.log.WithContext(ctx).Infof("Getting status for %d repositories (branch: %s)", len(clients), branch)
+
+	result := &multiRepoStatus{Repositories: github.CollectRepoStatuses(ctx, clients, branch, concurrency)}
+	for _, r := range result.Repositories {
+		result.Total++
+		switch r.State {
+		case "passing":
+			result.Passing++
+		case "failing":
+			result.Failing++
+		case "running":
+			result.Running++
+		case "no_runs":
+			result.NoRuns++
+		default:
+			result.Errors++
+		}
+	}
+
+	return jsonResultPretty(result)
+}
+
+func (s *MCPServer) getRunTimeline(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
+	}
+
+	includeSteps := true
+	if v, ok := args["include_steps"].(bool); ok {
+		includeSteps = v
+	}
+
+	s.log.WithContext(ctx).Infof("Getting timeline for run %d on %s/%s", runID, owner, repo)
+
+	timeline, err := client.GetRunTimeline(ctx, runID, includeSteps)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get timeline for run %d", runID), owner, repo), nil
+	}
+
+	return jsonResultPretty(timeline)
+}
+
+// gitRemotes is the result of list_git_remotes.
+type gitRemotes struct {
+	DefaultRepo string           `json:"default_repo,omitempty"`
+	Detected    *github.RepoInfo `json:"detected"`
+}
+
+func (s *MCPServer) listGitRemotes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	opts := github.RepoDetectorOptions{
+		Remote:       s.config.Remote,
+		Preference:   s.config.RemotePreference,
+		SearchUpward: true,
+		HostPolicy:   github.HostPolicy{Hosts: []string{s.config.GitHubHost()}},
+	}
+	if v, ok := args["remote"].(string); ok && strings.TrimSpace(v) != "" {
+		opts.Remote = strings.TrimSpace(v)
+	}
+
+	s.log.WithContext(ctx).Infof("Listing git remotes (remote: %s)", opts.Remote)
+
+	info, err := github.NewRepoDetectorWithOptions(opts).Detect()
+	if err != nil {
+		return errorResult(fmt.Sprintf("failed to detect repository from git remotes: %v", err)), nil
+	}
+
+	result := &gitRemotes{Detected: info}
+	if s.config.RepoOwner != "" && s.config.RepoName != "" {
+		result.DefaultRepo = s.config.RepoOwner + "/" + s.config.RepoName
+	}
+	return jsonResultPretty(result)
+}
+
+// localCheckoutDir returns the top directory of the git checkout the server runs in when
+// one of its remotes points to owner/repo, or "".
+func (s *MCPServer) localCheckoutDir(owner, repo string) string {
+	info, err := github.NewRepoDetectorWithOptions(github.RepoDetectorOptions{
+		SearchUpward: true,
+		HostPolicy:   github.HostPolicy{Hosts: []string{s.config.GitHubHost()}},
+	}).Detect()
+	if err != nil {
+		return ""
+	}
+	for _, remote := range info.Remotes {
+		if strings.EqualFold(remote.FullName(), owner+"/"+repo) {
+			return info.Root
+		}
+	}
+	return ""
+}
+
+func (s *MCPServer) getStaleBranchReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	staleDays := github.DefaultStaleDays
+	if d, ok := args["stale_days"].(float64); ok && d > 0 {
+		staleDays = int(d)
+	}
+	maxRuns := github.DefaultStaleReportRuns
+	if n, ok := args["max_runs"].(float64); ok && n > 0 {
+		maxRuns = int(n)
+	}
+
+	s.log.WithContext(ctx).Infof("Building stale-branch report for %s/%s (stale after %d days, scanning %d runs)", owner, repo, staleDays, maxRuns)
+
+	report, err := client.GetStaleBranchReport(ctx, staleDays, maxRuns)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to build stale-branch report", owner, repo), nil
+	}
+
+	return jsonResultPretty(report)
+}
+
+func (s *MCPServer) analyzePathFilters(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	opts := github.PathFilterOptions{}
+	switch v := args["paths"].(type) {
+	case string:
+		for _, p := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == '\n' }) {
+			if p = strings.TrimSpace(p); p != "" {
+				opts.Paths = append(opts.Paths, p)
+			}
+		}
+	case []interface{}:
+		for _, p := range v {
+			if p, ok := p.(string); ok && strings.TrimSpace(p) != "" {
+				opts.Paths = append(opts.Paths, strings.TrimSpace(p))
+			}
+		}
+	}
+	opts.Base, _ = args["base"].(string)
+	opts.Head, _ = args["head"].(string)
+	opts.Event, _ = args["event"].(string)
+	opts.Branch, _ = args["branch"].(string)
+	opts.Workflow, _ = args["workflow"].(string)
+	opts.Ref, _ = args["ref"].(string)
+	if len(opts.Paths) == 0 && (opts.Base == "" || opts.Head == "") {
+		return errorResult("either paths or both base and head are required"), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Analyzing path filters for %s/%s (event: %s, branch: %s, %d paths)", owner, repo, opts.Event, opts.Branch, len(opts.Paths))
+
+	report, err := client.GetPathFilterReport(ctx, opts)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to analyze path filters", owner, repo), nil
+	}
+
+	return jsonResultPretty(report)
+}
+
+// releaseRunsResult is a release report whose runs are rendered in the requested format.
+type releaseRunsResult struct {
+	*github.ReleaseRuns
+	Runs      interface{} `json:"runs"`
+	OtherRuns interface{} `json:"other_runs,omitempty"`
+}
+
+func (s *MCPServer) getReleaseRuns(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	tag, _ := args["tag"].(string)
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return errorResult("tag is required"), nil
+	}
+	format := s.getFormat()
+	if f, ok := args["format"].(string); ok && f != "" {
+		format = f
+	}
+
+	s.log.WithContext(ctx).Infof("Getting release runs for %s in %s/%s", tag, owner, repo)
+
+	report, err := client.GetReleaseRuns(ctx, tag)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get runs for %s", tag), owner, repo), nil
+	}
+
+	result := &releaseRunsResult{ReleaseRuns: report, Runs: formatRunList(report.Runs, format)}
+	if len(report.OtherRuns) > 0 {
+		result.OtherRuns = formatRunList(report.OtherRuns, format)
+	}
+	return jsonResultPretty(result)
+}
+
+func (s *MCPServer) runRelease(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	opts := github.ReleaseOptions{TimeoutMinutes: 30}
+	opts.Tag, _ = args["tag"].(string)
+	opts.Tag = strings.TrimSpace(opts.Tag)
+	if opts.Tag == "" {
+		return errorResult("tag is required"), nil
+	}
+	opts.Target, _ = args["target"].(string)
+	opts.Workflow, _ = args["workflow"].(string)
+	if tm, ok := args["timeout_minutes"].(float64); ok && tm > 0 {
+		opts.TimeoutMinutes = int(tm)
+		if opts.TimeoutMinutes > 120 {
+			opts.TimeoutMinutes = 120
+		}
+	}
+	opts.OnApproval = func(approval *github.PendingApproval) {
+		s.notifier.notify(ctx, fmt.Sprintf("%s/%s release %s is %s", owner, repo, opts.Tag, approval.Message))
+	}
+
+	s.log.WithContext(ctx).Infof("Running release %s for %s/%s (target: %q, timeout: %dm)", opts.Tag, owner, repo, opts.Target, opts.TimeoutMinutes)
+
+	result, err := client.RunRelease(ctx, opts)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to run release %s", opts.Tag), owner, repo), nil
+	}
+
+	return jsonResultPretty(result)
+}
+
+func (s *MCPServer) downloadRunLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
+	}
+
+	outputPath := ""
+	if op, ok := args["output_path"].(string); ok {
+		// Reject absolute paths and path components that escape the current directory
+		if filepath.IsAbs(op) || strings.Contains(op, "..") {
+			return errorResult("output_path must be a relative path without '..' components"), nil
+		}
+		outputPath = op
+	}
+
+	s.log.WithContext(ctx).Infof("Saving log archive of run %d to %s", runID, outputPath)
+
+	result, err := client.SaveRunLogArchive(ctx, runID, outputPath)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to download logs of run %d", runID), owner, repo), nil
+	}
+
+	return jsonResultPretty(result)
+}
+
+// runBundleFile describes a run bundle saved to disk.
+type runBundleFile struct {
+	RunID     int64    `json:"run_id"`
+	Format    string   `json:"format"`
+	SavedPath string   `json:"saved_path"`
+	SizeBytes int64    `json:"size_bytes"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+func (s *MCPServer) exportRunBundle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	format := "markdown"
+	if f, ok := args["format"].(string); ok && f != "" {
+		format = f
+	}
+	if format != "markdown" && format != "zip" {
+		return errorResult("format must be markdown or zip"), nil
+	}
+
+	outputPath := ""
+	if op, ok := args["output_path"].(string); ok {
+		// Reject absolute paths and path components that escape the current directory
+		if filepath.IsAbs(op) || strings.Contains(op, "..") {
+			return errorResult("output_path must be a relative path without '..' components"), nil
+		}
+		outputPath = op
+	}
+
+	maxLogLines := 200
+	if mll, ok := args["max_log_lines"].(float64); ok && mll > 0 {
+		maxLogLines = int(mll)
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		var errResult *mcp.CallToolResult
+		runID, errResult = s.latestFailedRunID(ctx, client, owner, repo)
+		if errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	s.log.WithContext(ctx).Infof("Exporting bundle of run %d on %s/%s as %s", runID, owner, repo, format)
+
+	bundle, err := client.GetRunBundle(ctx, runID, maxLogLines)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to export run %d", runID), owner, repo), nil
+	}
+	masked := 0
+	for _, l := range bundle.FailedLogs {
+		var n int
+		l.Log, n = s.maskSecrets(l.Log)
+		masked += n
+	}
+
+	if format == "markdown" && outputPath == "" {
+		return withMaskedCount(textResult(github.RenderRunBundleMarkdown(bundle)), masked), nil
+	}
+	if outputPath == "" {
+		outputPath = fmt.Sprintf("run-%d-bundle.zip", runID)
+	}
+
+	var buf bytes.Buffer
+	if format == "zip" {
+		if err := github.WriteRunBundleZip(bundle, &buf); err != nil {
+			return errorResult(err.Error()), nil
+		}
+	} else {
+		buf.WriteString(github.RenderRunBundleMarkdown(bundle))
+	}
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
+		return errorResult(fmt.Sprintf("failed to save bundle: %v", err)), nil
+	}
+
+	result, err := jsonResultPretty(&runBundleFile{
+		RunID:     runID,
+		Format:    format,
+		SavedPath: outputPath,
+		SizeBytes: int64(buf.Len()),
+		Warnings:  bundle.Warnings,
+	})
+	return withMaskedCount(result, masked), err
+}
+
+// dependencyPRTriageResult is a dependency PR triage and the reruns it triggered.
+type dependencyPRTriageResult struct {
+	*github.DependencyPRTriage
+	Reruns []*github.ManageRunResult `json:"reruns,omitempty"`
+}
+
+func (s *MCPServer) triageDependencyPRs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	var bots []string
+	if v, ok := args["bots"].(string); ok {
+		for _, b := range strings.Split(v, ",") {
+			if b = strings.TrimSpace(b); b != "" {
+				bots = append(bots, b)
+			}
+		}
+	}
+	rerun, _ := args["rerun"].(string)
+
+	s.log.WithContext(ctx).Infof("Triaging dependency PRs on %s/%s", owner, repo)
+
+	triage, err := client.GetDependencyPRTriage(ctx, bots)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to triage dependency PRs", owner, repo), nil
+	}
+	result := &dependencyPRTriageResult{DependencyPRTriage: triage}
+	if rerun == "" {
+		return jsonResultPretty(result)
 	}
 
-	if status, ok := args["status"].(string); ok && status != "" {
-		opts.Status = status
+	var groups []*github.DependencyPRGroup
+	for _, g := range triage.Groups {
+		if rerun == "all" || g.Signature == rerun {
+			groups = append(groups, g)
+		}
+	}
+	if len(groups) == 0 {
+		return errorResult(fmt.Sprintf("no failing group has the signature %q; pass a signature from groups or \"all\"", rerun)), nil
+	}
+	for _, g := range groups {
+		for _, runID := range g.RunIDs() {
+			r, err := client.ManageRun(ctx, runID, github.ManageRunActionRerunFailed)
+			if err != nil {
+				r = &github.ManageRunResult{RunID: runID, Action: github.ManageRunActionRerunFailed, Status: "failed", Message: err.Error()}
+			}
+			result.Reruns = append(result.Reruns, r)
+		}
 	}
+	s.log.WithContext(ctx).Infof("Requested reruns of %d run(s) for dependency PRs on %s/%s", len(result.Reruns), owner, repo)
+	return jsonResultPretty(result)
+}
 
-	if conclusion, ok := args["conclusion"].(string); ok && conclusion != "" {
-		opts.Conclusion = conclusion
+func (s *MCPServer) getConcurrencyReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
 
-	if createdAfter, ok := args["created_after"].(string); ok && createdAfter != "" {
-		opts.CreatedAfter = createdAfter
+	ref, _ := args["ref"].(string)
+
+	s.log.WithContext(ctx).Infof("Getting concurrency report for %s/%s", owner, repo)
+
+	report, err := client.GetConcurrencyReport(ctx, ref)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to get concurrency report", owner, repo), nil
 	}
 
-	if event, ok := args["event"].(string); ok && event != "" {
-		opts.Event = event
+	return jsonResultPretty(report)
+}
+
+func (s *MCPServer) getTimeoutReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
 
-	if actor, ok := args["actor"].(string); ok && actor != "" {
-		opts.Actor = actor
+	workflow, _ := args["workflow"].(string)
+	if workflow == "" {
+		return errorResult("workflow is required"), nil
+	}
+	branch, _ := args["branch"].(string)
+	limit := 20
+	if value, ok := args["limit"].(float64); ok && value > 0 {
+		limit = int(value)
 	}
 
-	format := s.getFormat()
-	if f, ok := args["format"].(string); ok {
-		format = f
+	s.log.WithContext(ctx).Infof("Getting timeout report for workflow %s in %s/%s", workflow, owner, repo)
+
+	report, err := client.GetTimeoutReport(ctx, workflow, branch, limit)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to get timeout report", owner, repo), nil
 	}
 
-	s.log.Infof("Listing runs for %s/%s", owner, repo)
+	return jsonResultPretty(report)
+}
 
-	runs, err := client.ListRepositoryWorkflowRunsWithOptions(ctx, opts)
+func (s *MCPServer) getFailureHeatmap(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, "failed to list workflow runs", owner, repo)), nil
+		return errorResult(err.Error()), nil
 	}
 
-	// Format output based on format parameter
-	switch format {
-	case "minimal":
-		result := make([]*github.WorkflowRunMinimal, 0, len(runs))
-		for _, r := range runs {
-			result = append(result, &github.WorkflowRunMinimal{
-				ID:              r.ID,
-				Name:            r.Name,
-				Status:          r.Status,
-				Conclusion:      r.Conclusion,
-				CreatedAt:       r.CreatedAt,
-				DurationSeconds: r.DurationSeconds,
-			})
-		}
-		return jsonResult(result)
-	case "full":
-		result := make([]*github.WorkflowRunFull, 0, len(runs))
-		for _, r := range runs {
-			result = append(result, &github.WorkflowRunFull{
-				ID:              r.ID,
-				Name:            r.Name,
-				Status:          r.Status,
-				Conclusion:      r.Conclusion,
-				Branch:          r.Branch,
-				Event:           r.Event,
-				Actor:           r.Actor,
-				CreatedAt:       r.CreatedAt,
-				UpdatedAt:       r.UpdatedAt,
-				URL:             r.URL,
-				RunNumber:       r.RunNumber,
-				WorkflowID:      r.WorkflowID,
-				HeadSHA:         r.HeadSHA,
-				StartedAt:       r.StartedAt,
-				CompletedAt:     r.UpdatedAt,
-				DurationSeconds: r.DurationSeconds,
-			})
-		}
-		return jsonResult(result)
-	default: // compact
-		result := make([]*github.WorkflowRunCompact, 0, len(runs))
-		for _, r := range runs {
-			result = append(result, &github.WorkflowRunCompact{
-				WorkflowRunMinimal: github.WorkflowRunMinimal{
-					ID:              r.ID,
-					Name:            r.Name,
-					Status:          r.Status,
-					Conclusion:      r.Conclusion,
-					CreatedAt:       r.CreatedAt,
-					DurationSeconds: r.DurationSeconds,
-				},
-				Branch: r.Branch,
-				SHA:    r.HeadSHA,
-				Event:  r.Event,
-				Actor:  r.Actor,
-				URL:    r.URL,
-			})
+	workflow, _ := args["workflow"].(string)
+	days := github.DefaultHeatmapDays
+	if value, ok := args["days"].(float64); ok && value > 0 {
+		days = int(value)
+	}
+	bucketHours := github.DefaultHeatmapBucketHours
+	if value, ok := args["bucket_hours"].(float64); ok && value > 0 {
+		bucketHours = int(value)
+	}
+	if bucketHours > 24 || 24%bucketHours != 0 {
+		return errorResult(fmt.Sprintf("bucket_hours must divide 24, got %d", bucketHours)), nil
+	}
+
+	s.log.WithContext(ctx).Infof("Getting failure heatmap for %s/%s over %d days", owner, repo, days)
+
+	heatmap, err := client.GetFailureHeatmap(ctx, workflow, days, bucketHours)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to get failure heatmap", owner, repo), nil
+	}
+
+	return jsonResultPretty(heatmap)
+}
+
+func (s *MCPServer) bisectFailure(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	workflow, _ := args["workflow"].(string)
+	if workflow == "" {
+		return errorResult("workflow is required"), nil
+	}
+	branch, _ := args["branch"].(string)
+	if branch == "" && owner == s.config.RepoOwner && repo == s.config.RepoName {
+		if detectedBranch, err := github.GetCurrentBranch(); err == nil {
+			branch = detectedBranch
 		}
-		return jsonResult(result)
 	}
+
+	s.log.WithContext(ctx).Infof("Bisecting failures of workflow %s in %s/%s", workflow, owner, repo)
+
+	bisection, err := client.BisectFailure(ctx, workflow, branch)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to bisect failure", owner, repo), nil
+	}
+
+	return jsonResultPretty(bisection)
 }
 
-func (s *MCPServer) getRun(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// workflowJobsResult is the result of list_workflow_jobs.
+type workflowJobsResult struct {
+	RunID      int64         `json:"run_id"`
+	TotalJobs  int           `json:"total_jobs"`
+	FailedJobs []string      `json:"failed_jobs,omitempty"`
+	Jobs       []*github.Job `json:"jobs"`
+}
+
+func (s *MCPServer) listWorkflowJobs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
@@ -801,581 +4391,748 @@ func (s *MCPServer) getRun(ctx context.Context, request mcp.CallToolRequest) (*m
 	if !ok {
 		return errorResult("run_id is required"), nil
 	}
+	filter, _ := args["filter"].(string)
+	if filter != "" && filter != "latest" && filter != "all" {
+		return errorResult(fmt.Sprintf("invalid filter %q: allowed values are latest, all", filter)), nil
+	}
+	attemptNumber := 0
+	if an, ok := args["attempt_number"].(float64); ok && an > 0 {
+		attemptNumber = int(an)
+	}
+	conclusion, _ := args["conclusion"].(string)
+	includeSteps := true
+	if v, ok := args["include_steps"].(bool); ok {
+		includeSteps = v
+	}
 
-	element := "info"
-	if e, ok := args["element"].(string); ok {
-		element = strings.TrimSpace(strings.ToLower(e))
+	s.log.WithContext(ctx).Infof("Listing jobs of run %d in %s/%s", runID, owner, repo)
+
+	jobs, err := client.GetWorkflowJobs(ctx, runID, filter, attemptNumber)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get jobs for run %d", runID), owner, repo), nil
 	}
 
-	if !isValidRunElement(element) {
-		return errorResult(fmt.Sprintf("invalid element %q. Allowed values: %s", element, strings.Join(validRunElements, ", "))), nil
+	result := &workflowJobsResult{RunID: runID, TotalJobs: len(jobs), Jobs: []*github.Job{}}
+	for _, job := range jobs {
+		if job.Conclusion == "failure" || job.Conclusion == "timed_out" {
+			result.FailedJobs = append(result.FailedJobs, job.Name)
+		}
+		if conclusion != "" && job.Conclusion != conclusion {
+			continue
+		}
+		if !includeSteps {
+			job.Steps = nil
+		}
+		result.Jobs = append(result.Jobs, job)
 	}
 
-	s.log.Infof("Getting run %d (element: %s)", runID, element)
+	return jsonResultPretty(result)
+}
 
-	switch element {
-	case "jobs":
-		return s.getRunJobs(ctx, client, owner, repo, runID, args)
-	case "logs":
-		return s.getRunLogs(ctx, client, owner, repo, runID, args)
-	case "log_files":
-		return s.getLogFiles(ctx, client, owner, repo, runID, args)
-	case "log_sections":
-		return s.getLogSections(ctx, client, owner, repo, runID, args)
-	case "artifacts":
-		return s.getRunArtifacts(ctx, client, owner, repo, runID, args)
-	case "artifact_content":
-		return s.getArtifactContent(ctx, client, owner, repo, args)
-	default: // info
-		return s.getRunInfo(ctx, client, owner, repo, runID, args)
+// patchBranchRunResult is the result of trigger_patch_branch.
+type patchBranchRunResult struct {
+	Branch        *github.PatchBranchResult `json:"branch"`
+	Dispatch      *github.DispatchResult    `json:"dispatch,omitempty"`
+	Wait          *github.WaitRunResult     `json:"wait,omitempty"`
+	BranchDeleted bool                      `json:"branch_deleted,omitempty"`
+	Warnings      []string                  `json:"warnings,omitempty"`
+}
+
+// fileChangesFromArgs parses the files argument of trigger_patch_branch.
+func fileChangesFromArgs(args map[string]interface{}) ([]*github.FileChange, error) {
+	raw, ok := args["files"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("files is required: a list of {path, content} or {path, delete: true} objects")
+	}
+	changes := make([]*github.FileChange, 0, len(raw))
+	for i, item := range raw {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("files[%d] must be an object with path and content", i)
+		}
+		change := &github.FileChange{}
+		change.Path, _ = obj["path"].(string)
+		change.Content, _ = obj["content"].(string)
+		change.Delete, _ = obj["delete"].(bool)
+		change.Path = strings.TrimPrefix(strings.TrimSpace(change.Path), "/")
+		if change.Path == "" {
+			return nil, fmt.Errorf("files[%d] has no path", i)
+		}
+		if _, hasContent := obj["content"]; !hasContent && !change.Delete {
+			return nil, fmt.Errorf("files[%d] (%s) needs content, or \"delete\": true", i, change.Path)
+		}
+		changes = append(changes, change)
 	}
+	return changes, nil
 }
 
-func (s *MCPServer) getRunInfo(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	run, err := client.GetWorkflowRun(ctx, runID)
+func (s *MCPServer) triggerPatchBranch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("Run ID %d not found", runID), owner, repo)), nil
+		return errorResult(err.Error()), nil
 	}
 
-	format := s.getFormat()
-	if f, ok := args["format"].(string); ok {
-		format = f
+	opts, err := dispatchOptionsFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	branch, _ := args["branch"].(string)
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return errorResult("branch is required"), nil
 	}
+	opts.Ref = branch
+	changes, err := fileChangesFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	base, _ := args["base"].(string)
+	message, _ := args["message"].(string)
+	wait := true
+	if v, ok := args["wait"].(bool); ok {
+		wait = v
+	}
+	timeoutMinutes := 30
+	if tm, ok := args["timeout_minutes"].(float64); ok && tm > 0 {
+		timeoutMinutes = int(tm)
+	}
+	deleteBranch, _ := args["delete_branch"].(bool)
 
-	switch format {
-	case "full":
-		result := &github.WorkflowRunFull{
-			ID:              run.ID,
-			Name:            run.Name,
-			Status:          run.Status,
-			Conclusion:      run.Conclusion,
-			Branch:          run.Branch,
-			Event:           run.Event,
-			Actor:           run.Actor,
-			CreatedAt:       run.CreatedAt,
-			UpdatedAt:       run.UpdatedAt,
-			URL:             run.URL,
-			RunNumber:       run.RunNumber,
-			WorkflowID:      run.WorkflowID,
-			HeadSHA:         run.HeadSHA,
-			StartedAt:       run.StartedAt,
-			CompletedAt:     run.UpdatedAt,
-			DurationSeconds: run.DurationSeconds,
+	s.log.WithContext(ctx).Infof("Creating branch %s on %s/%s with %d file change(s) and triggering workflow %s", branch, owner, repo, len(changes), opts.Workflow)
+
+	patch, err := client.CreatePatchBranch(ctx, branch, base, message, changes)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to create patch branch %s", branch), owner, repo), nil
+	}
+	result := &patchBranchRunResult{Branch: patch}
+
+	// The branch is new, so an identical earlier dispatch cannot exist.
+	dispatch, errResult := s.dispatchWorkflow(ctx, client, owner, repo, opts, true, false)
+	if errResult != nil {
+		return errResult, nil
+	}
+	result.Dispatch = dispatch
+	if dispatch.RunID == 0 || !wait {
+		return jsonResultPretty(result)
+	}
+
+	waitResult, err := client.WaitForRunWithOptions(ctx, dispatch.RunID, s.waitRunOptions(ctx, owner, repo, dispatch.RunID, timeoutMinutes, false))
+	if err != nil && waitResult == nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to wait for run %d", dispatch.RunID), owner, repo), nil
+	}
+	result.Wait = waitResult
+
+	if deleteBranch {
+		if waitResult.Status != "completed" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("kept branch %s because run %d has not completed", branch, dispatch.RunID))
+		} else if err := client.DeleteBranch(ctx, branch); err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		} else {
+			result.BranchDeleted = true
 		}
-		return jsonResult(result)
-	default: // compact
-		result := &github.WorkflowRunCompact{
-			WorkflowRunMinimal: github.WorkflowRunMinimal{
-				ID:              run.ID,
-				Name:            run.Name,
-				Status:          run.Status,
-				Conclusion:      run.Conclusion,
-				CreatedAt:       run.CreatedAt,
-				DurationSeconds: run.DurationSeconds,
-			},
-			Branch: run.Branch,
-			SHA:    run.HeadSHA,
-			Event:  run.Event,
-			Actor:  run.Actor,
-			URL:    run.URL,
+	}
+
+	return jsonResultPretty(result)
+}
+
+func (s *MCPServer) rerequestCheck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	checkRunID, _ := args["check_run_id"].(float64)
+	checkSuiteID, _ := args["check_suite_id"].(float64)
+	ref, _ := args["ref"].(string)
+	ref = strings.TrimSpace(ref)
+	given := 0
+	for _, set := range []bool{checkRunID > 0, checkSuiteID > 0, ref != ""} {
+		if set {
+			given++
 		}
-		return jsonResult(result)
 	}
+	if given != 1 {
+		return errorResult("exactly one of check_run_id, check_suite_id, or ref is required"), nil
+	}
+
+	switch {
+	case checkRunID > 0:
+		s.log.WithContext(ctx).Infof("Re-requesting check run %d in %s/%s", int64(checkRunID), owner, repo)
+		result, err := client.RerequestCheckRun(ctx, int64(checkRunID))
+		if err != nil {
+			return s.apiErrorResult(err, fmt.Sprintf("failed to re-request check run %d", int64(checkRunID)), owner, repo), nil
+		}
+		return jsonResultPretty(result)
+	case checkSuiteID > 0:
+		s.log.WithContext(ctx).Infof("Re-requesting check suite %d in %s/%s", int64(checkSuiteID), owner, repo)
+		result, err := client.RerequestCheckSuite(ctx, int64(checkSuiteID))
+		if err != nil {
+			return s.apiErrorResult(err, fmt.Sprintf("failed to re-request check suite %d", int64(checkSuiteID)), owner, repo), nil
+		}
+		return jsonResultPretty(result)
+	}
+
+	checkName, _ := args["check_name"].(string)
+	s.log.WithContext(ctx).Infof("Re-requesting failed checks on %s in %s/%s", ref, owner, repo)
+	results, err := client.RerequestFailedChecks(ctx, ref, checkName)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to re-request checks on %s", ref), owner, repo), nil
+	}
+	return jsonResultPretty(map[string]interface{}{"ref": ref, "results": results})
 }
 
-func (s *MCPServer) getRunJobs(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	filter := ""
-	if f, ok := args["filter"].(string); ok {
-		filter = f
+func (s *MCPServer) getJobDetails(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
 
-	attemptNumber := 0
-	if an, ok := args["attempt_number"].(float64); ok && an > 0 {
-		attemptNumber = int(an)
+	jobID, ok := args["job_id"].(float64)
+	if !ok || jobID <= 0 {
+		return errorResult("job_id is required"), nil
 	}
 
-	jobs, err := client.GetWorkflowJobs(ctx, runID, filter, attemptNumber)
+	s.log.WithContext(ctx).Infof("Getting details of job %d in %s/%s", int64(jobID), owner, repo)
+
+	details, err := client.GetJobDetails(ctx, int64(jobID))
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get jobs for run %d", runID), owner, repo)), nil
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get job %d", int64(jobID)), owner, repo), nil
 	}
 
-	format := s.getFormat()
-	if f, ok := args["format"].(string); ok {
-		format = f
-	}
+	return jsonResultPretty(details)
+}
 
-	if format == "full" {
-		return jsonResultPretty(jobs)
-	}
-	return jsonResult(jobs)
+// artifactListResult is the result of list_artifacts.
+type artifactListResult struct {
+	RunID          int64              `json:"run_id"`
+	TotalCount     int                `json:"total_count"`
+	TotalSizeBytes int64              `json:"total_size_bytes"`
+	Expired        int                `json:"expired,omitempty"`
+	Artifacts      []*github.Artifact `json:"artifacts"`
 }
 
-func (s *MCPServer) getRunLogs(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	// Check if getting logs for a specific job
-	if jobIDFloat, ok := args["job_id"].(float64); ok {
-		jobID := int64(jobIDFloat)
-		return s.getRunJobLogs(ctx, client, owner, repo, runID, jobID, args)
+func (s *MCPServer) listArtifacts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
 
-	// Get workflow run logs
-	head := 0
-	if h, ok := args["head"].(float64); ok && h > 0 {
-		head = int(h)
+	runIDFloat, ok := args["run_id"].(float64)
+	if !ok || runIDFloat <= 0 {
+		return errorResult("run_id is required"), nil
 	}
-
-	tail := 0
-	if t, ok := args["tail"].(float64); ok && t > 0 {
-		tail = int(t)
+	runID := int64(runIDFloat)
+	name, _ := args["name"].(string)
+	if name != "" {
+		if _, err := filepath.Match(name, ""); err != nil {
+			return errorResult(fmt.Sprintf("invalid name pattern %q: %v", name, err)), nil
+		}
 	}
 
-	offset := 0
-	if o, ok := args["offset"].(float64); ok && o > 0 {
-		offset = int(o)
-	}
+	s.log.WithContext(ctx).Infof("Listing artifacts of run %d in %s/%s", runID, owner, repo)
 
-	// Support both old 'filter' and new 'search' parameter
-	search := ""
-	if s, ok := args["search"].(string); ok {
-		search = s
-	} else if f, ok := args["filter"].(string); ok {
-		search = f // Support old parameter name for backwards compatibility
+	artifacts, err := client.GetWorkflowRunArtifacts(ctx, runID)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get artifacts for run %d", runID), owner, repo), nil
 	}
 
-	searchRegex := ""
-	if sr, ok := args["search_regex"].(string); ok {
-		searchRegex = sr
-	} else if fr, ok := args["filter_regex"].(string); ok {
-		searchRegex = fr // Support old parameter name
+	result := &artifactListResult{RunID: runID, Artifacts: []*github.Artifact{}}
+	for _, artifact := range artifacts {
+		if name != "" {
+			if matched, _ := filepath.Match(name, artifact.Name); !matched {
+				continue
+			}
+		}
+		result.Artifacts = append(result.Artifacts, artifact)
+		result.TotalSizeBytes += artifact.SizeInBytes
+		if artifact.Expired {
+			result.Expired++
+		}
 	}
+	result.TotalCount = len(result.Artifacts)
 
-	if search != "" && searchRegex != "" {
-		return errorResult("search and search_regex are mutually exclusive"), nil
-	}
+	return jsonResultPretty(result)
+}
 
-	contextLines := 0
-	if c, ok := args["context"].(float64); ok && c > 0 {
-		contextLines = int(c)
-	}
+// deletedArtifactResult is the result of delete_artifact.
+type deletedArtifactResult struct {
+	Deleted    bool             `json:"deleted"`
+	Artifact   *github.Artifact `json:"artifact"`
+	FreedBytes int64            `json:"freed_bytes"`
+}
 
-	noHeaders := false
-	if nh, ok := args["no_headers"].(bool); ok {
-		noHeaders = nh
+func (s *MCPServer) deleteArtifact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
 
-	// Get file pattern for filtering log files
-	filePattern := ""
-	if fp, ok := args["file_pattern"].(string); ok {
-		filePattern = fp
+	artifactIDFloat, ok := args["artifact_id"].(float64)
+	if !ok || artifactIDFloat <= 0 {
+		return errorResult("artifact_id is required"), nil
 	}
+	artifactID := int64(artifactIDFloat)
 
-	filterOpts := &github.LogFilterOptions{
-		Filter:       search,
-		FilterRegex:  searchRegex,
-		ContextLines: contextLines,
-	}
+	s.log.WithContext(ctx).Infof("Deleting artifact %d in %s/%s", artifactID, owner, repo)
 
-	// Check if section extraction is requested
-	section := ""
-	if sec, ok := args["section"].(string); ok {
-		section = sec
+	artifact, err := client.DeleteArtifact(ctx, artifactID)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to delete artifact %d", artifactID), owner, repo), nil
 	}
 
-	var logs string
-	var err error
+	return jsonResultPretty(&deletedArtifactResult{Deleted: true, Artifact: artifact, FreedBytes: artifact.SizeInBytes})
+}
 
-	if section != "" {
-		// Extract specific section
-		logs, err = client.GetLogSection(ctx, runID, 0, section, filterOpts)
-	} else {
-		// Get all logs with optional filtering
-		logs, err = client.GetWorkflowLogsWithPattern(ctx, runID, head, tail, offset, noHeaders, filePattern, filterOpts)
-	}
+// cacheListResult is the result of list_caches.
+type cacheListResult struct {
+	TotalCount     int             `json:"total_count"`
+	Returned       int             `json:"returned"`
+	TotalSizeBytes int64           `json:"total_size_bytes"` // Of the returned caches
+	Caches         []*github.Cache `json:"caches"`
+}
 
+func (s *MCPServer) listCaches(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get logs for run %d", runID), owner, repo)), nil
+		return errorResult(err.Error()), nil
 	}
 
-	callerLimited := head > 0 || tail > 0 || search != "" || searchRegex != "" || section != ""
-	return truncateLogResult(logs, s.getLogLines(), callerLimited), nil
-}
-
-func (s *MCPServer) getRunJobLogs(ctx context.Context, client *github.Client, owner, repo string, runID, jobID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	head := 0
-	if h, ok := args["head"].(float64); ok && h > 0 {
-		head = int(h)
+	opts := &github.CacheListOptions{Limit: 100}
+	opts.Ref, _ = args["ref"].(string)
+	opts.Key, _ = args["key"].(string)
+	opts.Sort, _ = args["sort"].(string)
+	opts.Direction, _ = args["direction"].(string)
+	switch opts.Sort {
+	case "", "created_at", "last_accessed_at", "size_in_bytes":
+	default:
+		return errorResult("sort must be created_at, last_accessed_at, or size_in_bytes"), nil
 	}
-
-	tail := 0
-	if t, ok := args["tail"].(float64); ok && t > 0 {
-		tail = int(t)
+	if opts.Direction != "" && opts.Direction != "asc" && opts.Direction != "desc" {
+		return errorResult("direction must be asc or desc"), nil
 	}
-
-	offset := 0
-	if o, ok := args["offset"].(float64); ok && o > 0 {
-		offset = int(o)
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		opts.Limit = int(l)
 	}
 
-	search := ""
-	if s, ok := args["search"].(string); ok {
-		search = s
-	} else if f, ok := args["filter"].(string); ok {
-		search = f
-	}
+	s.log.WithContext(ctx).Infof("Listing caches of %s/%s", owner, repo)
 
-	searchRegex := ""
-	if sr, ok := args["search_regex"].(string); ok {
-		searchRegex = sr
-	} else if fr, ok := args["filter_regex"].(string); ok {
-		searchRegex = fr
+	caches, total, err := client.ListCaches(ctx, opts)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to list caches", owner, repo), nil
 	}
 
-	if search != "" && searchRegex != "" {
-		return errorResult("search and search_regex are mutually exclusive"), nil
+	result := &cacheListResult{TotalCount: total, Returned: len(caches), Caches: caches}
+	for _, cache := range caches {
+		result.TotalSizeBytes += cache.SizeInBytes
 	}
+	return jsonResultPretty(result)
+}
 
-	contextLines := 0
-	if c, ok := args["context"].(float64); ok && c > 0 {
-		contextLines = int(c)
+func (s *MCPServer) getCacheUsage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
 
-	noHeaders := false
-	if nh, ok := args["no_headers"].(bool); ok {
-		noHeaders = nh
-	}
+	s.log.WithContext(ctx).Infof("Getting cache usage of %s/%s", owner, repo)
 
-	filterOpts := &github.LogFilterOptions{
-		Filter:       search,
-		FilterRegex:  searchRegex,
-		ContextLines: contextLines,
+	usage, err := client.GetCacheUsage(ctx)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to get cache usage", owner, repo), nil
 	}
+	return jsonResultPretty(usage)
+}
 
-	section := ""
-	if sec, ok := args["section"].(string); ok {
-		section = sec
-	}
+// cacheDeletionResult is the result of delete_cache.
+type cacheDeletionResult struct {
+	DeletedIDs []int64         `json:"deleted_ids"`
+	Caches     []*github.Cache `json:"caches,omitempty"`
+	FreedBytes int64           `json:"freed_bytes,omitempty"`
+}
 
-	var logs string
-	var err error
+func (s *MCPServer) deleteCache(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
 
-	if section != "" {
-		logs, err = client.GetLogSection(ctx, 0, jobID, section, filterOpts)
-	} else {
-		logs, err = client.GetWorkflowJobLogs(ctx, jobID, head, tail, offset, noHeaders, filterOpts)
+	cacheID, _ := args["cache_id"].(float64)
+	key, _ := args["key"].(string)
+	ref, _ := args["ref"].(string)
+	if (cacheID > 0) == (key != "") {
+		return errorResult("exactly one of cache_id or key is required"), nil
+	}
+	if cacheID > 0 && ref != "" {
+		return errorResult("ref can only be combined with key"), nil
 	}
 
-	if err != nil && runID > 0 {
-		if section == "" {
-			logs, err = client.GetWorkflowJobLogsFromRunArchive(ctx, runID, jobID, head, tail, offset, noHeaders, filterOpts)
+	if cacheID > 0 {
+		s.log.WithContext(ctx).Infof("Deleting cache %d in %s/%s", int64(cacheID), owner, repo)
+		if err := client.DeleteCache(ctx, int64(cacheID)); err != nil {
+			return s.apiErrorResult(err, fmt.Sprintf("failed to delete cache %d", int64(cacheID)), owner, repo), nil
 		}
+		return jsonResultPretty(&cacheDeletionResult{DeletedIDs: []int64{int64(cacheID)}})
 	}
 
+	s.log.WithContext(ctx).Infof("Deleting caches with key %s in %s/%s", key, owner, repo)
+	caches, err := client.DeleteCachesByKey(ctx, key, ref)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get logs for job %d", jobID), owner, repo)), nil
+		return s.apiErrorResult(err, fmt.Sprintf("failed to delete caches with key %q", key), owner, repo), nil
+	}
+	result := &cacheDeletionResult{DeletedIDs: []int64{}, Caches: caches}
+	for _, cache := range caches {
+		result.DeletedIDs = append(result.DeletedIDs, cache.ID)
+		result.FreedBytes += cache.SizeInBytes
 	}
+	return jsonResultPretty(result)
+}
 
-	callerLimited := head > 0 || tail > 0 || search != "" || searchRegex != "" || section != ""
-	return truncateLogResult(logs, s.getLogLines(), callerLimited), nil
+func (s *MCPServer) getMetricsSnapshot(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	snapshot := s.metrics.snapshot()
+	if pool := s.tokens.Status(); len(pool) > 1 {
+		snapshot.Tokens = pool
+	}
+	return jsonResultPretty(snapshot)
 }
 
-func (s *MCPServer) getRunArtifacts(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	artifacts, err := client.GetWorkflowRunArtifacts(ctx, runID)
+func (s *MCPServer) rerunJob(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get artifacts for run %d", runID), owner, repo)), nil
+		return errorResult(err.Error()), nil
 	}
 
-	format := s.getFormat()
-	if f, ok := args["format"].(string); ok {
-		format = f
+	jobIDFloat, ok := args["job_id"].(float64)
+	if !ok || jobIDFloat <= 0 {
+		return errorResult("job_id is required"), nil
 	}
+	jobID := int64(jobIDFloat)
 
-	if format == "full" {
-		return jsonResultPretty(artifacts)
+	s.log.WithContext(ctx).Infof("Rerunning job %d on %s/%s", jobID, owner, repo)
+
+	if err := client.RerunJob(ctx, jobID); err != nil {
+		return s.apiErrorResult(err, "failed to rerun job", owner, repo), nil
 	}
-	return jsonResult(artifacts)
+	return textResult(fmt.Sprintf("Triggered rerun of job %d and the jobs that depend on it", jobID)), nil
 }
 
-func (s *MCPServer) getArtifactContent(ctx context.Context, client *github.Client, owner, repo string, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	artifactIDFloat, ok := args["artifact_id"].(float64)
-	if !ok {
-		return errorResult("artifact_id is required for element=artifact_content"), nil
+func (s *MCPServer) cancelWorkflowRuns(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
-	artifactID := int64(artifactIDFloat)
 
-	filePattern := ""
-	if fp, ok := args["file_pattern"].(string); ok {
-		filePattern = fp
+	opts := &github.CancelRunsOptions{}
+	if workflow, _ := args["workflow"].(string); strings.TrimSpace(workflow) != "" {
+		workflowID, _, err := client.ResolveWorkflowID(ctx, strings.TrimSpace(workflow))
+		if err != nil {
+			return s.apiErrorResult(err, fmt.Sprintf("failed to resolve workflow %s", workflow), owner, repo), nil
+		}
+		opts.WorkflowID = &workflowID
 	}
-
-	maxFileSize := int64(1024 * 1024) // 1MB default
-	if mfs, ok := args["max_file_size"].(float64); ok && mfs > 0 {
-		maxFileSize = int64(mfs)
+	if branch, ok := args["branch"].(string); ok {
+		opts.Branch = strings.TrimSpace(branch)
+	}
+	switch status, _ := args["status"].(string); status {
+	case "":
+	case "queued", "in_progress":
+		opts.Statuses = []string{status}
+	default:
+		return errorResult(fmt.Sprintf("invalid status %q (must be queued or in_progress)", status)), nil
 	}
+	if minutes, ok := args["older_than_minutes"].(float64); ok {
+		if minutes < 0 {
+			return errorResult("older_than_minutes must not be negative"), nil
+		}
+		opts.OlderThan = time.Duration(minutes * float64(time.Minute))
+	}
+	opts.DryRun, _ = args["dry_run"].(bool)
 
-	s.log.Infof("Getting artifact content %d (pattern: %s, max_size: %d)", artifactID, filePattern, maxFileSize)
+	s.log.WithContext(ctx).Infof("Cancelling workflow runs on %s/%s (branch: %q, dry run: %t)", owner, repo, opts.Branch, opts.DryRun)
 
-	content, err := client.GetArtifactContent(ctx, artifactID, filePattern, maxFileSize)
+	result, err := client.CancelWorkflowRuns(ctx, opts)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get artifact content %d", artifactID), owner, repo)), nil
+		return s.apiErrorResult(err, "failed to cancel workflow runs", owner, repo), nil
 	}
-
-	return jsonResultPretty(content)
+	return jsonResultPretty(result)
 }
 
-func (s *MCPServer) getLogFiles(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	logFiles, err := client.GetWorkflowLogFiles(ctx, runID)
+func (s *MCPServer) listPendingDeployments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get log files for run %d", runID), owner, repo)), nil
+		return errorResult(err.Error()), nil
 	}
 
-	// Apply file pattern filter if specified
-	if pattern, ok := args["file_pattern"].(string); ok && pattern != "" {
-		filtered := make([]*github.LogFileInfo, 0)
-		for _, lf := range logFiles {
-			matched, err := filepath.Match(pattern, lf.Path)
-			if err != nil {
-				return errorResult(fmt.Sprintf("invalid file pattern %q: %v", pattern, err)), nil
-			}
-			if matched {
-				filtered = append(filtered, lf)
-			}
-		}
-		logFiles = filtered
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
 	}
 
-	format := s.getFormat()
-	if f, ok := args["format"].(string); ok {
-		format = f
-	}
+	s.log.WithContext(ctx).Infof("Listing pending deployments of run %d on %s/%s", runID, owner, repo)
 
-	if format == "full" {
-		return jsonResultPretty(logFiles)
+	pending, err := client.ListPendingDeployments(ctx, runID)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to list pending deployments", owner, repo), nil
 	}
-	return jsonResult(logFiles)
+	return jsonResultPretty(pending)
 }
 
-func (s *MCPServer) getLogSections(ctx context.Context, client *github.Client, owner, repo string, runID int64, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	// Check if getting sections for a specific job
-	var jobID int64
-	if jobIDFloat, ok := args["job_id"].(float64); ok {
-		jobID = int64(jobIDFloat)
+func (s *MCPServer) reviewPendingDeployments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
 	}
 
-	s.log.Infof("Getting log sections for run %d (job_id: %d)", runID, jobID)
+	state, _ := args["state"].(string)
+	switch strings.ToLower(strings.TrimSpace(state)) {
+	case "approved", "approve":
+		state = "approved"
+	case "rejected", "reject":
+		state = "rejected"
+	default:
+		return errorResult("state is required (approved or rejected)"), nil
+	}
 
-	sections, err := client.ListLogSections(ctx, runID, jobID)
-	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get log sections for run %d", runID), owner, repo)), nil
+	var environmentIDs []int64
+	if ids, ok := args["environment_ids"].([]interface{}); ok {
+		for _, v := range ids {
+			id, ok := v.(float64)
+			if !ok || id <= 0 {
+				return errorResult(fmt.Sprintf("invalid environment ID %v", v)), nil
+			}
+			environmentIDs = append(environmentIDs, int64(id))
+		}
 	}
+	comment, _ := args["comment"].(string)
 
-	format := s.getFormat()
-	if f, ok := args["format"].(string); ok {
-		format = f
-	}
+	s.log.WithContext(ctx).Infof("Reviewing pending deployments of run %d on %s/%s: %s", runID, owner, repo, state)
 
-	if format == "full" {
-		return jsonResultPretty(sections)
+	review, err := client.ReviewPendingDeployments(ctx, runID, state, environmentIDs, comment)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to review pending deployments", owner, repo), nil
 	}
-	return jsonResult(sections)
+	return jsonResultPretty(review)
 }
 
-func (s *MCPServer) analyzeTiming(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *MCPServer) getRunEnv(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
 		return errorResult(err.Error()), nil
 	}
 
-	workflow := ""
-	if value, ok := args["workflow"].(string); ok {
-		workflow = strings.TrimSpace(value)
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
 	}
+	name, _ := args["name"].(string)
 
-	runID := int64(0)
-	if value, ok := args["run_id"].(float64); ok && value > 0 {
-		runID = int64(value)
-	}
+	s.log.WithContext(ctx).Infof("Getting env report for run %d on %s/%s", runID, owner, repo)
 
-	jobName := ""
-	if value, ok := args["job_name"].(string); ok {
-		jobName = strings.TrimSpace(value)
+	report, err := client.GetRunEnvReport(ctx, runID, strings.TrimSpace(name))
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get env report for run %d", runID), owner, repo), nil
 	}
 
-	stepName := ""
-	if value, ok := args["step_name"].(string); ok {
-		stepName = strings.TrimSpace(value)
+	return jsonResultPretty(report)
+}
+
+func (s *MCPServer) listSecrets(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
+	environment, _ := args["environment"].(string)
+	environment = strings.TrimSpace(environment)
 
-	if stepName != "" && jobName == "" {
-		return errorResult("job_name is required when step_name is provided"), nil
+	s.log.WithContext(ctx).Infof("Listing secrets for %s/%s (environment: %s)", owner, repo, environment)
+
+	secrets, err := client.ListSecrets(ctx, environment)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to list secrets", owner, repo), nil
 	}
-	if workflow == "" && runID == 0 {
-		return errorResult("workflow or run_id is required"), nil
+	if secrets == nil {
+		secrets = []*github.SecretInfo{}
 	}
 
-	branch := ""
-	if value, ok := args["branch"].(string); ok && value != "" {
-		branch = strings.TrimSpace(value)
-	}
+	return jsonResultPretty(secrets)
+}
 
-	conclusion := ""
-	if value, ok := args["conclusion"].(string); ok {
-		conclusion = strings.TrimSpace(value)
+func (s *MCPServer) setSecret(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
 
-	limit := 10
-	if value, ok := args["limit"].(float64); ok && value > 0 {
-		limit = int(value)
+	name, _ := args["name"].(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errorResult("name is required"), nil
 	}
-	if limit > 50 {
-		limit = 50
+	value, ok := args["value"].(string)
+	if !ok {
+		return errorResult("value is required"), nil
 	}
+	environment, _ := args["environment"].(string)
+	environment = strings.TrimSpace(environment)
 
-	s.log.Infof("Analyzing timing for %s/%s (workflow=%q, run_id=%d, job=%q, step=%q, limit=%d)", owner, repo, workflow, runID, jobName, stepName, limit)
+	// Never log the value.
+	s.log.WithContext(ctx).Infof("Setting secret %s on %s/%s (environment: %s)", name, owner, repo, environment)
 
-	analysis, err := client.AnalyzeTiming(ctx, &github.TimingAnalysisOptions{
-		Workflow:   workflow,
-		RunID:      runID,
-		Branch:     branch,
-		JobName:    jobName,
-		StepName:   stepName,
-		Limit:      limit,
-		Conclusion: conclusion,
-	})
+	update, err := client.SetSecret(ctx, name, value, environment)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, "failed to analyze timing", owner, repo)), nil
+		return s.apiErrorResult(err, fmt.Sprintf("failed to set secret %s", name), owner, repo), nil
 	}
 
-	return jsonResultPretty(analysis)
+	return jsonResultPretty(update)
 }
 
-func (s *MCPServer) getCheckStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *MCPServer) listVariables(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
 		return errorResult(err.Error()), nil
 	}
+	environment, _ := args["environment"].(string)
+	environment = strings.TrimSpace(environment)
 
-	ref := ""
-	if r, ok := args["ref"].(string); ok && r != "" {
-		ref = strings.TrimSpace(r)
-	} else if owner == s.config.RepoOwner && repo == s.config.RepoName {
-		// Auto-detect ref only for the configured repo (not cross-repo overrides)
-		if commit, err := github.GetLastCommit(); err == nil {
-			ref = commit.SHA
-		} else {
-			return errorResult("could not determine ref - please specify explicitly"), nil
-		}
-	} else {
-		return errorResult("ref is required when querying a different repository"), nil
-	}
-
-	opts := &github.GetCheckRunsOptions{}
+	s.log.WithContext(ctx).Infof("Listing variables for %s/%s (environment: %s)", owner, repo, environment)
 
-	if checkName, ok := args["check_name"].(string); ok && checkName != "" {
-		opts.CheckName = checkName
+	vars, err := client.ListVariables(ctx, environment)
+	if err != nil {
+		return s.apiErrorResult(err, "failed to list variables", owner, repo), nil
 	}
-
-	if status, ok := args["status"].(string); ok && status != "" {
-		opts.Status = status
+	if vars == nil {
+		vars = []*github.Variable{}
 	}
 
-	filterMode := "latest"
-	if filter, ok := args["filter"].(string); ok && filter != "" {
-		filterMode = strings.TrimSpace(strings.ToLower(filter))
-	}
-	if filterMode != "latest" && filterMode != "all" {
-		return errorResult(fmt.Sprintf("invalid filter %q. Allowed values: latest, all", filterMode)), nil
+	return jsonResultPretty(vars)
+}
+
+func (s *MCPServer) setVariable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	client, owner, repo, err := s.clientFromArgs(args)
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
-	opts.Filter = filterMode
 
-	format := "summary"
-	if f, ok := args["format"].(string); ok && f != "" {
-		format = strings.TrimSpace(strings.ToLower(f))
+	name, _ := args["name"].(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errorResult("name is required"), nil
 	}
-	if format != "summary" && format != "compact" && format != "full" {
-		return errorResult(fmt.Sprintf("invalid format %q. Allowed values: summary, compact, full", format)), nil
+	value, ok := args["value"].(string)
+	if !ok {
+		return errorResult("value is required"), nil
 	}
+	environment, _ := args["environment"].(string)
+	environment = strings.TrimSpace(environment)
 
-	status, err := client.GetCheckRunsForRef(ctx, ref, opts)
+	s.log.WithContext(ctx).Infof("Setting variable %s on %s/%s (environment: %s)", name, owner, repo, environment)
+
+	update, err := client.SetVariable(ctx, name, value, environment)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, "failed to get check status", owner, repo)), nil
+		return s.apiErrorResult(err, fmt.Sprintf("failed to set variable %s", name), owner, repo), nil
 	}
 
-	switch format {
-	case "full":
-		return jsonResult(status)
-	case "compact":
-		return jsonResult(status)
-	default: // summary
-		return textResult(formatWorkflowStatusSummary(ref, status, filterMode)), nil
-	}
+	return jsonResultPretty(update)
 }
 
-func (s *MCPServer) waitForRun(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *MCPServer) deleteVariable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
 		return errorResult(err.Error()), nil
 	}
 
-	runID, ok := extractRunID(args)
-	if !ok {
-		return errorResult("run_id is required"), nil
-	}
-
-	timeoutMinutes := 30
-	if tm, ok := args["timeout_minutes"].(float64); ok && tm > 0 {
-		timeoutMinutes = int(tm)
-		if timeoutMinutes > 120 {
-			timeoutMinutes = 120
-		}
+	name, _ := args["name"].(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errorResult("name is required"), nil
 	}
+	environment, _ := args["environment"].(string)
+	environment = strings.TrimSpace(environment)
 
-	s.log.Infof("Waiting for run %d (timeout: %dm)", runID, timeoutMinutes)
+	s.log.WithContext(ctx).Infof("Deleting variable %s on %s/%s (environment: %s)", name, owner, repo, environment)
 
-	result, err := client.WaitForRun(ctx, runID, timeoutMinutes)
+	update, err := client.DeleteVariable(ctx, name, environment)
 	if err != nil {
-		if result == nil || !result.TimeoutReached {
-			return errorResult(s.formatAuthErrorForRepo(err, "failed to wait for run", owner, repo)), nil
-		}
+		return s.apiErrorResult(err, fmt.Sprintf("failed to delete variable %s", name), owner, repo), nil
 	}
 
-	return jsonResult(result)
+	return jsonResultPretty(update)
 }
 
-func (s *MCPServer) waitForCommitChecks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *MCPServer) evaluateExpression(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
-	client, owner, repo, err := s.clientFromArgs(args)
-	if err != nil {
-		return errorResult(err.Error()), nil
+	expression, _ := args["expression"].(string)
+	if strings.TrimSpace(expression) == "" {
+		return errorResult("expression is required"), nil
+	}
+	contexts := map[string]interface{}{}
+	if raw, ok := args["context"]; ok && raw != nil {
+		provided, ok := raw.(map[string]interface{})
+		if !ok {
+			return errorResult("context must be an object of contexts by name"), nil
+		}
+		for name, value := range provided {
+			contexts[strings.ToLower(name)] = value
+		}
 	}
-
-	ref := ""
-	if r, ok := args["ref"].(string); ok && r != "" {
-		ref = strings.TrimSpace(r)
+	status, _ := args["status"].(string)
+	condition := true
+	if v, ok := args["condition"].(bool); ok {
+		condition = v
 	}
 
-	timeoutMinutes := 30
-	if tm, ok := args["timeout_minutes"].(float64); ok && tm > 0 {
-		timeoutMinutes = int(tm)
-		if timeoutMinutes > 120 {
-			timeoutMinutes = 120
+	if runID, ok := extractRunID(args); ok {
+		client, owner, repo, err := s.clientFromArgs(args)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		s.log.WithContext(ctx).Infof("Loading the github context of run %d on %s/%s", runID, owner, repo)
+		runContext, err := client.RunGitHubContext(ctx, runID)
+		if err != nil {
+			return s.apiErrorResult(err, fmt.Sprintf("failed to get run %d", runID), owner, repo), nil
+		}
+		if provided, ok := contexts["github"].(map[string]interface{}); ok {
+			for name, value := range provided {
+				runContext[name] = value
+			}
 		}
+		contexts["github"] = runContext
 	}
 
-	s.log.Infof("Waiting for checks on ref %s (timeout: %dm)", ref, timeoutMinutes)
-
-	result, err := client.WaitForCommitChecks(ctx, ref, timeoutMinutes)
+	result, err := github.EvaluateExpression(expression, contexts, github.ExpressionOptions{
+		Status:    strings.TrimSpace(status),
+		Condition: condition,
+	})
 	if err != nil {
-		if result == nil || !result.TimeoutReached {
-			return errorResult(s.formatAuthErrorForRepo(err, "failed to wait for checks", owner, repo)), nil
-		}
+		return errorResult(fmt.Sprintf("failed to evaluate expression: %v", err)), nil
 	}
 
-	return jsonResult(result)
+	return jsonResultPretty(result)
 }
 
-func (s *MCPServer) manageRun(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *MCPServer) traceArtifacts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
@@ -1387,162 +5144,153 @@ func (s *MCPServer) manageRun(ctx context.Context, request mcp.CallToolRequest)
 		return errorResult("run_id is required"), nil
 	}
 
-	actionStr, ok := args["action"].(string)
-	if !ok || actionStr == "" {
-		return errorResult("action is required (cancel, rerun, rerun_failed)"), nil
-	}
-
-	var action github.ManageRunAction
-	switch actionStr {
-	case "cancel":
-		action = github.ManageRunActionCancel
-	case "rerun":
-		action = github.ManageRunActionRerun
-	case "rerun_failed":
-		action = github.ManageRunActionRerunFailed
-	default:
-		return errorResult(fmt.Sprintf("unknown action: %s (must be cancel, rerun, or rerun_failed)", actionStr)), nil
-	}
+	s.log.WithContext(ctx).Infof("Tracing artifacts of run %d on %s/%s", runID, owner, repo)
 
-	s.log.Infof("Managing run %d on %s/%s: %s", runID, owner, repo, action)
-
-	result, err := client.ManageRun(ctx, runID, action)
+	trace, err := client.TraceArtifacts(ctx, runID)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, "failed to manage run", owner, repo)), nil
+		return s.apiErrorResult(err, fmt.Sprintf("failed to trace artifacts of run %d", runID), owner, repo), nil
 	}
 
-	if result.Status == "success" {
-		return textResult(result.Message), nil
-	}
-	return errorResult(result.Message), nil
+	return jsonResultPretty(trace)
 }
 
-func (s *MCPServer) getArtifact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *MCPServer) updateWorkflowFile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
 		return errorResult(err.Error()), nil
 	}
 
-	artifactIDFloat, ok := args["artifact_id"].(float64)
-	if !ok {
-		return errorResult("artifact_id is required"), nil
+	opts := github.WorkflowFileUpdateOptions{Actionlint: true}
+	opts.Path, _ = args["path"].(string)
+	if strings.TrimSpace(opts.Path) == "" {
+		return errorResult("path is required"), nil
 	}
-	artifactID := int64(artifactIDFloat)
-
-	filePattern := ""
-	if fp, ok := args["file_pattern"].(string); ok {
-		filePattern = fp
+	content, ok := args["content"].(string)
+	if !ok || strings.TrimSpace(content) == "" {
+		return errorResult("content is required"), nil
+	}
+	opts.Content = content
+	opts.Message, _ = args["message"].(string)
+	opts.Branch, _ = args["branch"].(string)
+	opts.Branch = strings.TrimSpace(opts.Branch)
+	opts.NewBranch, _ = args["new_branch"].(string)
+	opts.NewBranch = strings.TrimSpace(opts.NewBranch)
+	if v, ok := args["actionlint"].(bool); ok {
+		opts.Actionlint = v
 	}
+	opts.Force, _ = args["force"].(bool)
 
-	maxFileSize := int64(1024 * 1024) // 1MB default
-	if mfs, ok := args["max_file_size"].(float64); ok && mfs > 0 {
-		maxFileSize = int64(mfs)
+	s.log.WithContext(ctx).Infof("Updating workflow file %s on %s/%s (branch: %s, new branch: %s)", opts.Path, owner, repo, opts.Branch, opts.NewBranch)
+
+	update, err := client.UpdateWorkflowFile(ctx, opts)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to update %s", opts.Path), owner, repo), nil
 	}
 
-	s.log.Infof("Getting artifact %d (pattern: %s, max_size: %d)", artifactID, filePattern, maxFileSize)
+	return jsonResultPretty(update)
+}
 
-	content, err := client.GetArtifactContent(ctx, artifactID, filePattern, maxFileSize)
+// lintWorkflow lints a local workflow file, or a repository path or inline YAML as
+// validate_workflow_yaml does.
+func (s *MCPServer) lintWorkflow(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	file, _ := args["file"].(string)
+	file = strings.TrimSpace(file)
+	if file == "" {
+		if content, _ := args["yaml"].(string); content == "" {
+			if path, _ := args["path"].(string); path == "" {
+				return errorResult("one of file, path, or yaml is required"), nil
+			}
+		}
+		return s.validateWorkflowYAML(ctx, request)
+	}
+
+	useActionlint := true
+	if v, ok := args["actionlint"].(bool); ok {
+		useActionlint = v
+	}
+	data, err := os.ReadFile(file)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get artifact %d", artifactID), owner, repo)), nil
+		return errorResult(fmt.Sprintf("failed to read %s: %v", file, err)), nil
 	}
 
-	return jsonResultPretty(content)
+	s.log.WithContext(ctx).Infof("Linting local workflow file %s (actionlint: %t)", file, useActionlint)
+
+	return jsonResultPretty(github.ValidateWorkflowYAMLWithRunners(ctx, data, file, useActionlint, s.selfHostedRunners(ctx, args)))
 }
 
-func (s *MCPServer) downloadArtifact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *MCPServer) getRunChain(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
 		return errorResult(err.Error()), nil
 	}
 
-	artifactIDFloat, ok := args["artifact_id"].(float64)
+	runID, ok := extractRunID(args)
 	if !ok {
-		return errorResult("artifact_id is required"), nil
-	}
-	artifactID := int64(artifactIDFloat)
-
-	outputPath := ""
-	if op, ok := args["output_path"].(string); ok {
-		// Reject absolute paths and path components that escape the current directory
-		if filepath.IsAbs(op) || strings.Contains(op, "..") {
-			return errorResult("output_path must be a relative path without '..' components"), nil
-		}
-		outputPath = op
+		return errorResult("run_id is required"), nil
 	}
 
-	s.log.Infof("Downloading artifact %d to %s", artifactID, outputPath)
+	s.log.WithContext(ctx).Infof("Getting the workflow_run chain of run %d on %s/%s", runID, owner, repo)
 
-	result, err := client.DownloadArtifact(ctx, artifactID, outputPath)
+	chain, err := client.GetRunChain(ctx, runID)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to download artifact %d", artifactID), owner, repo)), nil
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get the chain of run %d", runID), owner, repo), nil
 	}
 
-	return jsonResultPretty(result)
+	return jsonResultPretty(chain)
 }
 
-func (s *MCPServer) diagnoseFailure(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *MCPServer) getRunAnnotations(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 	client, owner, repo, err := s.clientFromArgs(args)
 	if err != nil {
 		return errorResult(err.Error()), nil
 	}
 
-	// Check flakiness defaults to true
-	checkFlakiness := true
-	if cf, ok := args["check_flakiness"].(bool); ok {
-		checkFlakiness = cf
-	}
-
-	maxErrorLines := 50
-	if mel, ok := args["max_error_lines"].(float64); ok && mel > 0 {
-		maxErrorLines = int(mel)
+	runID, ok := extractRunID(args)
+	if !ok {
+		return errorResult("run_id is required"), nil
 	}
+	level, _ := args["level"].(string)
 
-	var runID int64
-	if rid, ok := extractRunID(args); ok {
-		runID = rid
-	} else {
-		// Auto-detect: find the latest failed run on the current branch
-		branch := ""
-		if owner == s.config.RepoOwner && repo == s.config.RepoName {
-			if detectedBranch, err := github.GetCurrentBranch(); err == nil {
-				branch = detectedBranch
-			}
-		}
+	s.log.WithContext(ctx).Infof("Getting annotations of run %d on %s/%s", runID, owner, repo)
 
-		opts := &github.ListRunsOptions{
-			Per_page:   5,
-			Status:     "completed",
-			Conclusion: "failure",
-			Branch:     branch,
-		}
+	annotations, err := client.GetRunAnnotations(ctx, runID, level)
+	if err != nil {
+		return s.apiErrorResult(err, fmt.Sprintf("failed to get annotations of run %d", runID), owner, repo), nil
+	}
 
-		runs, err := client.ListRepositoryWorkflowRunsWithOptions(ctx, opts)
-		if err != nil {
-			return errorResult(s.formatAuthErrorForRepo(err, "failed to find failed runs", owner, repo)), nil
-		}
+	return jsonResultPretty(annotations)
+}
 
-		if len(runs) == 0 {
-			msg := "No failed runs found"
-			if branch != "" {
-				msg += fmt.Sprintf(" on branch %s", branch)
-			}
-			return errorResult(msg), nil
-		}
+func (s *MCPServer) getActionsBilling(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	account, _ := args["account"].(string)
+	account = strings.TrimSpace(account)
+	if account == "" {
+		account = s.config.RepoOwner
+	}
+	if account == "" {
+		return errorResult("account is required when no repository owner is configured"), nil
+	}
+	scope, _ := args["scope"].(string)
 
-		runID = runs[0].ID
+	client, err := s.clientForRepo(account, "")
+	if err != nil {
+		return errorResult(err.Error()), nil
 	}
 
-	s.log.Infof("Diagnosing failure for run %d on %s/%s", runID, owner, repo)
+	s.log.WithContext(ctx).Infof("Getting Actions billing of %s", account)
 
-	diagnosis, err := client.DiagnoseFailure(ctx, runID, checkFlakiness, maxErrorLines)
+	billing, err := client.GetActionsBilling(ctx, account, scope)
 	if err != nil {
-		return errorResult(s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to diagnose run %d", runID), owner, repo)), nil
+		// The error names the account; the repository hints of apiErrorResult do not apply.
+		return errorResult(err.Error()), nil
 	}
 
-	return jsonResultPretty(diagnosis)
+	return jsonResultPretty(billing)
 }
 
 // getFormat returns the format from config or default
@@ -1580,5 +5328,5 @@ func (s *MCPServer) InvokeTool(ctx context.Context, name string, args map[string
 		},
 	}
 
-	return tool.Handler(ctx, request)
+	return s.outputs.middleware(tool.Handler)(withRefresh(ctx, args), request)
 }
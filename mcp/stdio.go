@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Resource subscription methods. mcp-go advertises the subscribe capability but does not
+// route these requests, so ServeStdio answers them before they reach the library.
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+)
+
+// ServeStdio serves MCP over stdin/stdout until stdin is closed or the process receives
+// SIGINT or SIGTERM. Configured schedules run for as long as it serves.
+func (s *MCPServer) ServeStdio() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.stopJobLogFollowers()
+	defer s.scratch.close()
+
+	if err := s.startScheduler(ctx); err != nil {
+		return err
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	out := &lockedWriter{w: os.Stdout}
+	return server.NewStdioServer(s.srv).Listen(ctx, s.interceptSubscriptions(os.Stdin, out), out)
+}
+
+// interceptSubscriptions answers subscription requests read from in by writing to out,
+// and passes every other message through to the returned reader.
+func (s *MCPServer) interceptSubscriptions(in io.Reader, out io.Writer) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if resp, ok := s.handleSubscriptionRequest(line); ok {
+					if data, err := json.Marshal(resp); err == nil {
+						_, _ = out.Write(append(data, '\n'))
+					}
+				} else if _, werr := pw.Write(line); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pr
+}
+
+// handleSubscriptionRequest answers a resources/subscribe or resources/unsubscribe request.
+// It reports false for any other message.
+func (s *MCPServer) handleSubscriptionRequest(line []byte) (mcp.JSONRPCMessage, bool) {
+	var req struct {
+		ID     *mcp.RequestId `json:"id"`
+		Method string         `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(line, &req); err != nil || req.ID == nil {
+		return nil, false
+	}
+
+	switch req.Method {
+	case methodResourcesSubscribe:
+		if err := s.subscribeJobLog(req.Params.URI); err != nil {
+			return mcp.NewJSONRPCError(*req.ID, mcp.INVALID_PARAMS, err.Error(), nil), true
+		}
+	case methodResourcesUnsubscribe:
+		s.unsubscribeJobLog(req.Params.URI)
+	default:
+		return nil, false
+	}
+	return mcp.NewJSONRPCResultResponse(*req.ID, mcp.EmptyResult{}), true
+}
+
+// lockedWriter serialises writes so responses written here and by the stdio server never
+// interleave; each message is written with a single Write call.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
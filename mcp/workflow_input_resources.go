@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// workflowInputsURITemplate addresses the JSON Schema of a workflow's workflow_dispatch
+// inputs, from which clients can generate an input form for trigger_workflow.
+const workflowInputsURITemplate = "gh-actions://{owner}/{repo}/workflows/{workflow}/inputs"
+
+var workflowInputsURIPattern = regexp.MustCompile(`^gh-actions://([^/]+)/([^/]+)/workflows/([^/]+)/inputs$`)
+
+// parseWorkflowInputsURI returns the repository and workflow addressed by a workflow
+// inputs URI. A workflow file name such as ci.yml is expanded to its path.
+func parseWorkflowInputsURI(uri string) (string, string, string, error) {
+	m := workflowInputsURIPattern.FindStringSubmatch(uri)
+	if m == nil {
+		return "", "", "", fmt.Errorf("unknown resource %q: expected %s", uri, workflowInputsURITemplate)
+	}
+	workflow := m[3]
+	if strings.HasSuffix(workflow, ".yml") || strings.HasSuffix(workflow, ".yaml") {
+		workflow = ".github/workflows/" + workflow
+	}
+	return m[1], m[2], workflow, nil
+}
+
+func (s *MCPServer) readWorkflowInputs(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+	owner, repo, workflow, err := parseWorkflowInputsURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.clientForRepo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infof("Reading input schema of workflow %s in %s/%s", workflow, owner, repo)
+	schema, err := client.GetWorkflowInputSchema(ctx, workflow)
+	if err != nil {
+		return nil, fmt.Errorf("%s", s.formatAuthErrorForRepo(err, fmt.Sprintf("failed to get inputs of workflow %s", workflow), owner, repo))
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode input schema: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/schema+json",
+			Text:     string(data),
+		},
+	}, nil
+}
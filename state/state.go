@@ -0,0 +1,211 @@
+// Package state persists small JSON documents (failure cursors, bookmarks, watch state,
+// audit log) between runs of the server.
+//
+// Each document lives in its own file in the state directory and is wrapped in an envelope
+// that records the schema version it was written with, so features can change their format
+// without tripping over files written by older releases.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// appDirName is the directory created under the XDG data directory.
+const appDirName = "gh-actions-mcp"
+
+// ErrNewerSchema is returned by Load when a document was written by a newer release.
+var ErrNewerSchema = errors.New("state was written with a newer schema")
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// envelope is the on-disk format of every document.
+type envelope struct {
+	Schema    int             `json:"schema"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Store reads and writes documents in a state directory. The directory is created on the
+// first Save, so opening a store has no side effects.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// DefaultDir returns $XDG_DATA_HOME/gh-actions-mcp, falling back to
+// ~/.local/share/gh-actions-mcp.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, appDirName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine state directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", appDirName), nil
+}
+
+// Open returns a store rooted at dir, or at DefaultDir when dir is empty.
+func Open(dir string) (*Store, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the state directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Load decodes the named document into v. It returns false when the document does not
+// exist or was written with an older schema than requested; such documents are discarded
+// rather than misread. A document from a newer schema returns ErrNewerSchema.
+func (s *Store) Load(name string, schema int, v interface{}) (bool, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	data, err := os.ReadFile(path)
+	s.mu.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read state %s: %w", name, err)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return false, fmt.Errorf("failed to parse state %s: %w", name, err)
+	}
+	switch {
+	case env.Schema > schema:
+		return false, fmt.Errorf("%s: %w (schema %d, supported %d); run 'gh-actions-mcp state reset %s' to discard it", name, ErrNewerSchema, env.Schema, schema, name)
+	case env.Schema < schema:
+		return false, nil
+	}
+
+	if err := json.Unmarshal(env.Data, v); err != nil {
+		return false, fmt.Errorf("failed to decode state %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// Save writes v as the named document. The file is replaced atomically so a crash never
+// leaves a half-written document behind.
+func (s *Store) Save(name string, schema int, v interface{}) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode state %s: %w", name, err)
+	}
+	data, err := json.MarshalIndent(envelope{Schema: schema, UpdatedAt: time.Now().UTC(), Data: payload}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, "."+name+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write state %s: %w", name, err)
+	}
+	return nil
+}
+
+// Names lists the documents in the store.
+func (s *Store) Names() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if ok && !entry.IsDir() && namePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Reset deletes the named documents, or every document when no names are given. It
+// returns the names that were removed.
+func (s *Store) Reset(names ...string) ([]string, error) {
+	if len(names) == 0 {
+		var err error
+		if names, err = s.Names(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []string
+	for _, name := range names {
+		path, err := s.path(name)
+		if err != nil {
+			return removed, err
+		}
+		err = os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove state %s: %w", name, err)
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+func (s *Store) path(name string) (string, error) {
+	if !namePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid state name %q", name)
+	}
+	return filepath.Join(s.dir, name+".json"), nil
+}
@@ -0,0 +1,81 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cursor struct {
+	LastRunID int64 `json:"last_run_id"`
+}
+
+func TestStore_SaveLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	store, err := Open(dir)
+	require.NoError(t, err)
+
+	var got cursor
+	found, err := store.Load("cursors", 1, &got)
+	require.NoError(t, err)
+	assert.False(t, found)
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err), "opening and loading must not create the directory")
+
+	require.NoError(t, store.Save("cursors", 1, cursor{LastRunID: 42}))
+	found, err = store.Load("cursors", 1, &got)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(42), got.LastRunID)
+
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+}
+
+func TestStore_SchemaVersions(t *testing.T) {
+	store, err := Open(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Save("cursors", 2, cursor{LastRunID: 1}))
+
+	var got cursor
+	found, err := store.Load("cursors", 3, &got)
+	require.NoError(t, err)
+	assert.False(t, found, "older schema is discarded")
+
+	_, err = store.Load("cursors", 1, &got)
+	assert.True(t, errors.Is(err, ErrNewerSchema))
+}
+
+func TestStore_Reset(t *testing.T) {
+	store, err := Open(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Save("a", 1, 1))
+	require.NoError(t, store.Save("b", 1, 2))
+
+	names, err := store.Names()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	removed, err := store.Reset("b", "missing")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, removed)
+
+	removed, err = store.Reset()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, removed)
+
+	_, err = store.Reset("../etc")
+	assert.Error(t, err)
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/xdg")
+	dir, err := DefaultDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/xdg", "gh-actions-mcp"), dir)
+}
@@ -146,7 +146,11 @@ func TestTriggerWorkflowAndWait(t *testing.T) {
 
 	t.Logf("Triggering workflow %s on ref %s", workflowID, ref)
 
-	err := client.TriggerWorkflow(ctx, workflowID, ref)
+	dispatch, err := client.DispatchWorkflow(ctx, github.DispatchOptions{
+		Workflow:         workflowID,
+		Ref:              ref,
+		DiscoveryTimeout: 2 * time.Minute,
+	})
 	if err != nil {
 		// Skip if workflow doesn't exist or can't be triggered
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "404") {
@@ -161,41 +165,20 @@ func TestTriggerWorkflowAndWait(t *testing.T) {
 
 	t.Log("Workflow triggered successfully")
 
-	// Give it a moment to start
-	time.Sleep(5 * time.Second)
+	if dispatch.RunID == 0 {
+		t.Skipf("Could not identify the triggered run: %v", dispatch.Warnings)
+	}
+	t.Logf("Dispatched run: %d (matched by %s)", dispatch.RunID, dispatch.MatchedBy)
 
-	// Get the workflow runs to find the one we just triggered
-	// Note: This is a simplified approach - in a real scenario you'd want to
-	// poll and wait for the new run to appear
-	workflows, err := client.GetWorkflows(ctx)
+	t.Log("Waiting for workflow to complete...")
+	result, err := client.WaitForWorkflowRun(ctx, dispatch.RunID, 10, 300)
 	require.NoError(t, err)
 
-	for _, wf := range workflows {
-		if wf.Name == workflowID {
-			runs, err := client.GetWorkflowRuns(ctx, wf.ID, ref)
-			require.NoError(t, err)
-
-			if len(runs) > 0 {
-				latestRun := runs[0]
-				t.Logf("Latest run: #%d (ID: %d, Status: %s, Conclusion: %s)",
-					latestRun.RunNumber, latestRun.ID, latestRun.Status, latestRun.Conclusion)
-
-				// If the run is in progress or queued, wait for it to complete
-				if latestRun.Status == "in_progress" || latestRun.Status == "queued" {
-					t.Log("Waiting for workflow to complete...")
-					result, err := client.WaitForWorkflowRun(ctx, latestRun.ID, 10, 300)
-					require.NoError(t, err)
-
-					assert.False(t, result.TimedOut, "Workflow should complete within timeout")
-					assert.NotNil(t, result.Run, "Result should contain run info")
-
-					t.Logf("Workflow completed: %s (%s)", result.Run.Conclusion, result.Run.Status)
-					t.Logf("Polls: %d, Elapsed: %v", result.PollCount, result.Elapsed)
-				}
-			}
-			break
-		}
-	}
+	assert.False(t, result.TimedOut, "Workflow should complete within timeout")
+	assert.NotNil(t, result.Run, "Result should contain run info")
+
+	t.Logf("Workflow completed: %s (%s)", result.Run.Conclusion, result.Run.Status)
+	t.Logf("Polls: %d, Elapsed: %v", result.PollCount, result.Elapsed)
 }
 
 // TestGetWorkflowLogs tests retrieving logs from a workflow run
@@ -322,7 +305,11 @@ func TestWorkflowLifecycle(t *testing.T) {
 
 	// Step 1: Trigger the workflow
 	t.Log("Step 1: Triggering workflow...")
-	err := client.TriggerWorkflow(ctx, workflowID, ref)
+	dispatch, err := client.DispatchWorkflow(ctx, github.DispatchOptions{
+		Workflow:         workflowID,
+		Ref:              ref,
+		DiscoveryTimeout: 2 * time.Minute,
+	})
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "404") {
 			t.Skipf("Workflow %s not found", workflowID)
@@ -334,38 +321,12 @@ func TestWorkflowLifecycle(t *testing.T) {
 	}
 	t.Log("Workflow triggered successfully")
 
-	// Step 2: Wait a moment for the workflow to start
-	time.Sleep(5 * time.Second)
-
-	// Step 3: Find the triggered run
-	t.Log("Step 2: Finding the triggered workflow run...")
-	workflows, err := client.GetWorkflows(ctx)
-	require.NoError(t, err)
-
-	var triggeredRunID int64
-	var workflowIDInt int64
-
-	for _, wf := range workflows {
-		if wf.Name == workflowID {
-			workflowIDInt = wf.ID
-			runs, err := client.GetWorkflowRuns(ctx, wf.ID, ref)
-			require.NoError(t, err)
-
-			if len(runs) > 0 {
-				// Get the most recent run
-				triggeredRunID = runs[0].ID
-				t.Logf("Found run #%d (ID: %d, Status: %s)", runs[0].RunNumber, runs[0].ID, runs[0].Status)
-				break
-			}
-		}
-	}
-
+	// Step 2: The dispatch identifies the run it created
+	triggeredRunID := dispatch.RunID
 	if triggeredRunID == 0 {
-		t.Skip("Could not find the triggered workflow run")
+		t.Skipf("Could not find the triggered workflow run: %v", dispatch.Warnings)
 	}
-
-	// workflowIDInt is used when getting workflow runs
-	_ = workflowIDInt
+	t.Logf("Found run ID %d (matched by %s)", triggeredRunID, dispatch.MatchedBy)
 
 	// Step 4: Wait for completion
 	t.Log("Step 3: Waiting for workflow to complete...")